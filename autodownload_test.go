@@ -0,0 +1,132 @@
+package goscan_test
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+// writeModuleProxy writes the module into a directory usable as a file:// GOPROXY.
+func writeModuleProxy(t *testing.T, proxyDir, modPath, version string, files map[string]string) {
+	t.Helper()
+	dir := filepath.Join(proxyDir, filepath.FromSlash(modPath), "@v")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("list", version+"\n")
+	write(version+".info", `{"Version":"`+version+`"}`)
+	write(version+".mod", files["go.mod"])
+
+	f, err := os.Create(filepath.Join(dir, version+".zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(modPath + "@" + version + "/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithAutoDownload(t *testing.T) {
+	dir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"app/go.mod": `module example.com/app
+
+go 1.22
+
+require (
+	example.com/lib v1.0.0
+	example.com/missing v1.0.0
+)
+`,
+		"app/main.go": "package main\n\nimport _ \"example.com/lib\"\n",
+	})
+	defer cleanup()
+
+	proxyDir := filepath.Join(dir, "proxy")
+	writeModuleProxy(t, proxyDir, "example.com/lib", "v1.0.0", map[string]string{
+		"go.mod": "module example.com/lib\n\ngo 1.22\n",
+		"lib.go": "package lib\n\n// Value is a value.\ntype Value struct{ Name string }\n",
+	})
+
+	ctx := context.Background()
+	newScanner := func(t *testing.T, options ...goscan.ScannerOption) *goscan.Scanner {
+		t.Helper()
+		// A fresh module cache per case, downloaded from the local proxy only.
+		t.Setenv("GOMODCACHE", filepath.Join(t.TempDir(), "modcache"))
+		t.Setenv("GOPROXY", "file://"+filepath.ToSlash(proxyDir))
+		t.Setenv("GOSUMDB", "off")
+		t.Setenv("GOFLAGS", "-modcacherw") // so that the cache can be removed by the test cleanup
+		t.Setenv("GOTOOLCHAIN", "local")
+		options = append([]goscan.ScannerOption{goscan.WithWorkDir(filepath.Join(dir, "app")), goscan.WithGoModuleResolver()}, options...)
+		s, err := goscan.New(options...)
+		if err != nil {
+			t.Fatalf("goscan.New() failed: %v", err)
+		}
+		return s
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		s := newScanner(t)
+		if _, err := s.ScanPackageFromImportPath(ctx, "example.com/lib"); err == nil {
+			t.Fatal("expected an error for a module missing from the cache")
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		s := newScanner(t, goscan.WithAutoDownload(true))
+		pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/lib")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+		}
+		if pkg.Lookup("Value") == nil {
+			t.Errorf("expected the downloaded package to have the type Value")
+		}
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		s := newScanner(t, goscan.WithAutoDownload(true), goscan.WithAutoDownloadBudget(1))
+		// The failed download of the module unknown to the proxy uses up the budget.
+		_, err := s.ScanPackageFromImportPath(ctx, "example.com/missing")
+		if err == nil || !strings.Contains(err.Error(), "go mod download example.com/missing@v1.0.0") {
+			t.Fatalf("expected the error to hold the cause of the failed download, got %v", err)
+		}
+		_, err = s.ScanPackageFromImportPath(ctx, "example.com/lib")
+		if err == nil || !strings.Contains(err.Error(), "could not be resolved") || !strings.Contains(err.Error(), "download budget exhausted") {
+			t.Fatalf("expected the package not to be resolved after the budget is exhausted, got %v", err)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		s := newScanner(t, goscan.WithAutoDownload(true), goscan.WithAutoDownloadBudget(1))
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := s.ScanPackageFromImportPath(cancelled, "example.com/lib"); err == nil {
+			t.Fatal("expected an error for a download with a cancelled context")
+		}
+		// The cancelled download is neither recorded nor counted.
+		if _, err := s.ScanPackageFromImportPath(ctx, "example.com/lib"); err != nil {
+			t.Fatalf("ScanPackageFromImportPath() after the cancelled download failed: %v", err)
+		}
+	})
+}
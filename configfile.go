@@ -0,0 +1,191 @@
+package goscan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/podhmo/go-scan/scanner"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is the name of the file holding the settings shared by the tools built on
+// the scanner, at the root of a module or a workspace.
+const ConfigFileName = ".goscan.yaml"
+
+// FileConfig is the content of a config file (see ConfigFileName), e.g.
+//
+//	exclude-dirs: [testdata, vendor, node_modules]
+//	build-tags: [integration]
+//	scan-policy: ["example.com/app/...", "example.com/lib"]
+//	cache-dir: .cache/goscan
+//	log-level: info
+//
+// goscan.New reads it by default, and the settings given with options take precedence over it.
+type FileConfig struct {
+	// ExcludeDirs are the names of the directories skipped by the "..." patterns.
+	ExcludeDirs []string `yaml:"exclude-dirs"`
+	// BuildTags are the build tags used to select the files of the packages. Without them, all
+	// the files are scanned, whatever their build constraints.
+	BuildTags []string `yaml:"build-tags"`
+	// ScanPolicy are the import path patterns of the packages whose functions are evaluated by
+	// the symbolic execution of the tools, e.g. "example.com/app/...".
+	ScanPolicy []string `yaml:"scan-policy"`
+	// CacheDir is the directory of the symbol cache, relative to the config file.
+	CacheDir string `yaml:"cache-dir"`
+	// LogLevel is the log level of the tools (debug, info, warn or error).
+	LogLevel string `yaml:"log-level"`
+
+	// Path is the path of the config file.
+	Path string `yaml:"-"`
+
+	level *slog.Level
+}
+
+// LoadConfigFile reads a config file. Unknown settings are reported as errors.
+func LoadConfigFile(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	cfg := &FileConfig{Path: path}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) { // io.EOF for an empty file
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	if cfg.LogLevel != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+			return nil, fmt.Errorf("parsing config file %s: invalid log-level: %w", path, err)
+		}
+		cfg.level = &level
+	}
+	return cfg, nil
+}
+
+// FindConfigFile reads the config file of dir or of the nearest of its parents, up to the root
+// of the workspace (the directory with go.work), or returns nil if there is none.
+func FindConfigFile(dir string) (*FileConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		path := filepath.Join(dir, ConfigFileName)
+		if _, err := os.Stat(path); err == nil {
+			return LoadConfigFile(path)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.work")); err == nil {
+			return nil, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Level returns the log level, if set.
+func (c *FileConfig) Level() (slog.Level, bool) {
+	if c == nil || c.level == nil {
+		return 0, false
+	}
+	return *c.level, true
+}
+
+// InScanPolicy reports whether the package with the import path matches the ScanPolicy patterns.
+func (c *FileConfig) InScanPolicy(importPath string) bool {
+	if c == nil {
+		return false
+	}
+	for _, pattern := range c.ScanPolicy {
+		if scanner.MatchPattern(pattern, importPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// CachePath returns the path of the symbol cache file in CacheDir, or "" without CacheDir.
+func (c *FileConfig) CachePath() string {
+	if c == nil || c.CacheDir == "" {
+		return ""
+	}
+	dir := c.CacheDir
+	if !filepath.IsAbs(dir) && c.Path != "" {
+		dir = filepath.Join(filepath.Dir(c.Path), dir)
+	}
+	return filepath.Join(dir, "symbols.json")
+}
+
+// WithConfigFile sets the config file of the scanner, instead of the one found by FindConfigFile
+// from the working directory. With nil, no config file is used.
+func WithConfigFile(cfg *FileConfig) ScannerOption {
+	return func(s *Scanner) error {
+		s.configFile = cfg
+		s.configFileSet = true
+		return nil
+	}
+}
+
+// WithExcludeDirs sets the names of the directories skipped by the "..." patterns of Scan,
+// e.g. "testdata" and "vendor". Without names, no directory is skipped, whatever the config file.
+func WithExcludeDirs(dirs ...string) ScannerOption {
+	return func(s *Scanner) error {
+		s.excludeDirs = append([]string{}, dirs...)
+		return nil
+	}
+}
+
+// WithBuildTags sets the build tags selecting the files of the packages, with their build
+// constraints and their _GOOS/_GOARCH suffixes, for the current platform. Without tags, all
+// the files are scanned, whatever the config file.
+func WithBuildTags(tags ...string) ScannerOption {
+	return func(s *Scanner) error {
+		s.buildTags = append([]string{}, tags...)
+		return nil
+	}
+}
+
+// ConfigFile returns the config file used by the scanner, or nil.
+func (s *Scanner) ConfigFile() *FileConfig {
+	return s.configFile
+}
+
+// applyConfigFile finds the config file unless one is given with WithConfigFile, and uses its
+// settings for the ones not given with options.
+func (s *Scanner) applyConfigFile(loggerSet bool) error {
+	if !s.configFileSet {
+		cfg, err := FindConfigFile(s.workDir)
+		if err != nil {
+			return fmt.Errorf("loading %s: %w", ConfigFileName, err)
+		}
+		s.configFile = cfg
+	}
+	cfg := s.configFile
+	if cfg == nil {
+		return nil
+	}
+	if s.excludeDirs == nil {
+		s.excludeDirs = cfg.ExcludeDirs
+	}
+	if s.buildTags == nil {
+		s.buildTags = cfg.BuildTags
+	}
+	if s.CachePath == "" {
+		s.CachePath = cfg.CachePath()
+	}
+	if level, ok := cfg.Level(); ok && !loggerSet {
+		s.Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package goscan_test
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestConfigFile(t *testing.T) {
+	dir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		goscan.ConfigFileName: `exclude-dirs: [generated]
+build-tags: [integration]
+scan-policy: ["example.com/app/..."]
+cache-dir: .cache
+log-level: debug
+`,
+		"lib/lib.go":             "package lib\n\nfunc Lib() {}\n",
+		"lib/integration.go":     "//go:build integration\n\npackage lib\n\nfunc Integration() {}\n",
+		"lib/unit.go":            "//go:build !integration\n\npackage lib\n\nfunc Unit() {}\n",
+		"generated/generated.go": "package generated\n",
+		"cmd/tool/main.go":       "package main\n\nfunc main() {}\n",
+	})
+	defer cleanup()
+	ctx := context.Background()
+
+	scan := func(t *testing.T, options ...goscan.ScannerOption) (*goscan.Scanner, map[string][]string) {
+		t.Helper()
+		s, err := goscan.New(append([]goscan.ScannerOption{goscan.WithWorkDir(filepath.Join(dir, "cmd"))}, options...)...)
+		if err != nil {
+			t.Fatalf("New() failed: %v", err)
+		}
+		pkgs, err := s.Scan(ctx, filepath.Join(dir, "..."))
+		if err != nil {
+			t.Fatalf("Scan() failed: %v", err)
+		}
+		got := make(map[string][]string)
+		for _, pkg := range pkgs {
+			names := []string{}
+			for _, fn := range pkg.Functions {
+				names = append(names, fn.Name)
+			}
+			sort.Strings(names)
+			got[strings.TrimPrefix(pkg.ImportPath, "example.com/app/")] = names
+		}
+		return s, got
+	}
+
+	t.Run("found from a sub-directory of the module", func(t *testing.T) {
+		s, got := scan(t)
+		want := map[string][]string{
+			"lib":      {"Integration", "Lib"},
+			"cmd/tool": {"main"},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("packages mismatch (-want +got):\n%s", diff)
+		}
+
+		cfg := s.ConfigFile()
+		if cfg == nil || cfg.Path != filepath.Join(dir, goscan.ConfigFileName) {
+			t.Fatalf("ConfigFile() = %+v, want the config file of the module", cfg)
+		}
+		if !cfg.InScanPolicy("example.com/app/lib") || cfg.InScanPolicy("example.com/other") {
+			t.Errorf("InScanPolicy() does not match the scan-policy patterns")
+		}
+		if want := filepath.Join(dir, ".cache", "symbols.json"); s.CachePath != want {
+			t.Errorf("CachePath = %q, want %q", s.CachePath, want)
+		}
+		if !s.Logger.Enabled(ctx, slog.LevelDebug) {
+			t.Errorf("want the logger of the log-level")
+		}
+	})
+
+	t.Run("options take precedence", func(t *testing.T) {
+		_, got := scan(t, goscan.WithExcludeDirs(), goscan.WithBuildTags("other"))
+		want := map[string][]string{
+			"lib":       {"Lib", "Unit"},
+			"generated": {},
+			"cmd/tool":  {"main"},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("packages mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		s, got := scan(t, goscan.WithConfigFile(nil))
+		want := map[string][]string{
+			"lib":       {"Integration", "Lib", "Unit"},
+			"generated": {},
+			"cmd/tool":  {"main"},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("packages mismatch (-want +got):\n%s", diff)
+		}
+		if s.ConfigFile() != nil || s.CachePath != "" {
+			t.Errorf("want no settings of the config file")
+		}
+	})
+}
+
+func TestLoadConfigFile_Invalid(t *testing.T) {
+	dir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"unknown.yaml": "exclude_dirs: [testdata]\n",
+		"level.yaml":   "log-level: verbose\n",
+		"empty.yaml":   "",
+	})
+	defer cleanup()
+
+	for _, name := range []string{"unknown.yaml", "level.yaml"} {
+		if _, err := goscan.LoadConfigFile(filepath.Join(dir, name)); err == nil {
+			t.Errorf("LoadConfigFile(%q) succeeded, want an error", name)
+		}
+	}
+	if cfg, err := goscan.LoadConfigFile(filepath.Join(dir, "empty.yaml")); err != nil || cfg == nil {
+		t.Errorf("LoadConfigFile(%q) = %v, %v, want an empty config", "empty.yaml", cfg, err)
+	}
+}
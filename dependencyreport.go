@@ -0,0 +1,205 @@
+package goscan
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/podhmo/go-scan/locator"
+	"golang.org/x/mod/module"
+)
+
+// DependencyModule is the provenance of an external module whose packages were visited by the walker.
+type DependencyModule struct {
+	ModuleVersion
+	// Dir is the root directory of the module, in the module cache or a local replacement.
+	Dir string
+	// License is the SPDX identifier of the detected license (e.g. "MIT", "Apache-2.0"),
+	// "unknown" if a license file exists but is not recognized, and empty if there is no license file.
+	License string
+	// LicenseFile is the path of the license file, empty if there is none.
+	LicenseFile string
+	// Packages are the import paths of the visited packages of the module, sorted.
+	Packages []string
+}
+
+// DependencyReport lists the external modules encountered during walks.
+type DependencyReport struct {
+	// Modules are sorted by path and then version.
+	Modules []DependencyModule
+}
+
+// WithDependencyReport enables the collection of the external modules visited by the walker,
+// reported by Scanner.DependencyReport.
+func WithDependencyReport() ScannerOption {
+	return func(s *Scanner) error {
+		s.deps = &dependencyCollector{modules: make(map[string]*DependencyModule)}
+		return nil
+	}
+}
+
+// DependencyReport returns the external modules visited by the walker so far, with their version
+// and license. It returns nil if the scanner was not created with WithDependencyReport.
+func (s *Scanner) DependencyReport() *DependencyReport {
+	if s.deps == nil {
+		return nil
+	}
+	return s.deps.report()
+}
+
+// dependencyCollector records the external modules of the package directories found by the walker.
+type dependencyCollector struct {
+	mu      sync.Mutex
+	modules map[string]*DependencyModule // by module root directory
+}
+
+// record adds the package to the report, if it belongs to a module required by the main module.
+func (c *dependencyCollector) record(ctx context.Context, mf *locator.ModFile, importPath, pkgDir string) {
+	if mf == nil {
+		return
+	}
+	req, ok := requiredModule(mf, importPath)
+	if !ok {
+		return
+	}
+	modPath, version := req.Path, req.Version
+
+	// The module root is the package directory without the path elements under the module path.
+	dir := pkgDir
+	if rest := strings.TrimPrefix(strings.TrimPrefix(importPath, modPath), "/"); rest != "" {
+		for range strings.Split(rest, "/") {
+			dir = filepath.Dir(dir)
+		}
+	}
+	if i := strings.LastIndex(filepath.Base(dir), "@"); i >= 0 {
+		if v, err := module.UnescapeVersion(filepath.Base(dir)[i+1:]); err == nil {
+			version = v
+		}
+	} else if r, ok := mf.Replace(modPath, version); ok && r.IsLocal {
+		version = ""
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m, ok := c.modules[dir]
+	if !ok {
+		m = &DependencyModule{ModuleVersion: ModuleVersion{Path: modPath, Version: version}, Dir: dir}
+		m.LicenseFile, m.License = detectLicense(dir)
+		slog.DebugContext(ctx, "dependency module found", "module", m.String(), "license", m.License)
+		c.modules[dir] = m
+	}
+	i := sort.SearchStrings(m.Packages, importPath)
+	if i < len(m.Packages) && m.Packages[i] == importPath {
+		return
+	}
+	m.Packages = append(m.Packages, "")
+	copy(m.Packages[i+1:], m.Packages[i:])
+	m.Packages[i] = importPath
+}
+
+func (c *dependencyCollector) report() *DependencyReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r := &DependencyReport{Modules: make([]DependencyModule, 0, len(c.modules))}
+	for _, m := range c.modules {
+		dm := *m
+		dm.Packages = append([]string(nil), m.Packages...)
+		r.Modules = append(r.Modules, dm)
+	}
+	sort.Slice(r.Modules, func(i, j int) bool {
+		a, b := r.Modules[i], r.Modules[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Version < b.Version
+	})
+	return r
+}
+
+// requiredModule returns the module required by the main module which contains the package,
+// choosing the longest module path. Packages of the main module and of the standard library
+// are not part of any required module.
+func requiredModule(mf *locator.ModFile, importPath string) (locator.Require, bool) {
+	if importPath == mf.Path || strings.HasPrefix(importPath, mf.Path+"/") {
+		return locator.Require{}, false
+	}
+	var best locator.Require
+	for _, r := range mf.Requires {
+		if (importPath == r.Path || strings.HasPrefix(importPath, r.Path+"/")) && len(r.Path) > len(best.Path) {
+			best = r
+		}
+	}
+	return best, best.Path != ""
+}
+
+// licenseFileNames are the base names (without extension) of the files holding a module's license.
+var licenseFileNames = []string{"LICENSE", "LICENCE", "COPYING", "LICENSE-MIT", "LICENSE-APACHE"}
+
+// detectLicense finds the license file in the module root directory and classifies its text.
+func detectLicense(dir string) (file string, license string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", ""
+	}
+	for _, name := range licenseFileNames {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			base := entry.Name()
+			if !strings.EqualFold(strings.TrimSuffix(base, filepath.Ext(base)), name) {
+				continue
+			}
+			path := filepath.Join(dir, base)
+			content, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			return path, classifyLicense(string(content))
+		}
+	}
+	return "", ""
+}
+
+// classifyLicense returns the SPDX identifier of a license text, from the phrases of the common
+// licenses, or "unknown".
+func classifyLicense(text string) string {
+	text = strings.Join(strings.Fields(text), " ") // normalize line breaks and indentation
+	lower := strings.ToLower(text)
+	has := func(s string) bool { return strings.Contains(lower, strings.ToLower(s)) }
+
+	switch {
+	case has("Apache License") && has("Version 2.0"):
+		return "Apache-2.0"
+	case has("Mozilla Public License") && has("2.0"):
+		return "MPL-2.0"
+	case has("GNU Affero General Public License"):
+		return "AGPL-3.0"
+	case has("GNU Lesser General Public License"):
+		if has("Version 2.1") {
+			return "LGPL-2.1"
+		}
+		return "LGPL-3.0"
+	case has("GNU General Public License"):
+		if has("Version 2,") || has("Version 2 ") {
+			return "GPL-2.0"
+		}
+		return "GPL-3.0"
+	case has("Permission is hereby granted, free of charge"):
+		return "MIT"
+	case has("Redistribution and use in source and binary forms"):
+		if has("Neither the name") || has("names of its contributors") {
+			return "BSD-3-Clause"
+		}
+		return "BSD-2-Clause"
+	case has("Permission to use, copy, modify, and/or distribute this software for any purpose"):
+		return "ISC"
+	case has("This is free and unencumbered software released into the public domain"):
+		return "Unlicense"
+	}
+	return "unknown"
+}
@@ -0,0 +1,102 @@
+package goscan_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+type followAllVisitor struct{}
+
+func (followAllVisitor) Visit(pkg *goscan.PackageImports) ([]string, error) {
+	return pkg.Imports, nil
+}
+
+func TestDependencyReport(t *testing.T) {
+	files := map[string]string{
+		"app/go.mod": `module example.com/app
+
+go 1.22
+
+require (
+	example.com/lib v1.2.0
+	example.com/local v0.0.0
+	example.com/bare v0.1.0
+)
+
+replace example.com/local => ../local
+`,
+		"app/main.go": `package main
+
+import (
+	_ "example.com/app/internal"
+	_ "example.com/bare"
+	_ "example.com/lib/sub"
+	_ "example.com/local"
+	_ "fmt"
+)
+`,
+		"app/internal/internal.go": "package internal\n",
+
+		"modcache/example.com/lib@v1.2.0/LICENSE":    "MIT License\n\nPermission is hereby granted, free of charge, to any person obtaining a copy\n",
+		"modcache/example.com/lib@v1.2.0/lib.go":     "package lib\n",
+		"modcache/example.com/lib@v1.2.0/sub/sub.go": "package sub\n\nimport _ \"example.com/lib\"\n",
+		"modcache/example.com/bare@v0.1.0/bare.go":   "package bare\n",
+
+		"local/go.mod":      "module example.com/local\n",
+		"local/LICENSE.txt": "                                 Apache License\n                           Version 2.0, January 2004\n",
+		"local/local.go":    "package local\n",
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+	t.Setenv("GOMODCACHE", filepath.Join(dir, "modcache"))
+
+	s, err := goscan.New(goscan.WithWorkDir(filepath.Join(dir, "app")), goscan.WithGoModuleResolver(), goscan.WithDependencyReport())
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	if err := s.Walker.Walk(context.Background(), followAllVisitor{}, "example.com/app"); err != nil {
+		t.Fatalf("Walk() failed: %v", err)
+	}
+
+	want := &goscan.DependencyReport{
+		Modules: []goscan.DependencyModule{
+			{
+				ModuleVersion: goscan.ModuleVersion{Path: "example.com/bare", Version: "v0.1.0"},
+				Dir:           filepath.Join(dir, "modcache", "example.com", "bare@v0.1.0"),
+				Packages:      []string{"example.com/bare"},
+			},
+			{
+				ModuleVersion: goscan.ModuleVersion{Path: "example.com/lib", Version: "v1.2.0"},
+				Dir:           filepath.Join(dir, "modcache", "example.com", "lib@v1.2.0"),
+				License:       "MIT",
+				LicenseFile:   filepath.Join(dir, "modcache", "example.com", "lib@v1.2.0", "LICENSE"),
+				Packages:      []string{"example.com/lib", "example.com/lib/sub"},
+			},
+			{
+				ModuleVersion: goscan.ModuleVersion{Path: "example.com/local"},
+				Dir:           filepath.Join(dir, "local"),
+				License:       "Apache-2.0",
+				LicenseFile:   filepath.Join(dir, "local", "LICENSE.txt"),
+				Packages:      []string{"example.com/local"},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, s.DependencyReport()); diff != "" {
+		t.Errorf("DependencyReport() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDependencyReport_Disabled(t *testing.T) {
+	s, err := goscan.New(goscan.WithWorkDir("./testdata/walk"))
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	if r := s.DependencyReport(); r != nil {
+		t.Errorf("expected no report without WithDependencyReport, got %+v", r)
+	}
+}
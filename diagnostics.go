@@ -0,0 +1,433 @@
+package goscan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DiagnosticsFileName is the name of the file written by Scanner.Dump.
+const DiagnosticsFileName = "go-scan-diagnostics.json"
+
+// diagnosticsEnv lists the environment variables affecting how packages are located.
+var diagnosticsEnv = []string{"GO111MODULE", "GOFLAGS", "GOMODCACHE", "GOPATH", "GOPROXY", "GOPRIVATE", "GOROOT", "GOWORK"}
+
+// Diagnostics is a snapshot of the configuration and the state of a scanner, to attach to a bug
+// report so that a scan working in one environment but not in another can be reproduced.
+type Diagnostics struct {
+	GoVersion string            `json:"goVersion"`
+	GOOS      string            `json:"goos"`
+	GOARCH    string            `json:"goarch"`
+	Env       map[string]string `json:"env,omitempty"` // The environment variables in diagnosticsEnv which are set.
+
+	Config    DiagnosticsConfig     `json:"config"`
+	Modules   []DiagnosticsModule   `json:"modules"`
+	Workspace *DiagnosticsWorkspace `json:"workspace,omitempty"` // The go.work file in effect, if any.
+	Packages  []DiagnosticsPackage  `json:"packages"`
+	Cache     DiagnosticsCache      `json:"cache"`
+	// Logs are the last log events, if the scanner was created with WithLogRecorder.
+	Logs []LogEvent `json:"logs,omitempty"`
+}
+
+// DiagnosticsConfig is the effective configuration of a scanner.
+type DiagnosticsConfig struct {
+	WorkDir          string   `json:"workDir"`
+	Workspace        bool     `json:"workspace,omitempty"`
+	IncludeTests     bool     `json:"includeTests,omitempty"`
+	DryRun           bool     `json:"dryRun,omitempty"`
+	Inspect          bool     `json:"inspect,omitempty"`
+	GoModuleResolver bool     `json:"goModuleResolver,omitempty"`
+	AutoDownload     bool     `json:"autoDownload,omitempty"`
+	GoRoot           string   `json:"goRoot,omitempty"`
+	GoModCache       string   `json:"goModCache,omitempty"`
+	DefaultLoadMode  string   `json:"defaultLoadMode"`
+	LoadModeRules    []string `json:"loadModeRules,omitempty"` // In "pattern=mode" form, in order.
+	// Overlay and ExternalTypeOverrides hold the keys only, sorted.
+	Overlay               []string `json:"overlay,omitempty"`
+	ExternalTypeOverrides []string `json:"externalTypeOverrides,omitempty"`
+}
+
+// DiagnosticsModule is a main module of a scanner.
+type DiagnosticsModule struct {
+	Path      string   `json:"path"`
+	Dir       string   `json:"dir"`
+	GoVersion string   `json:"goVersion,omitempty"`
+	Toolchain string   `json:"toolchain,omitempty"`
+	Requires  int      `json:"requires"`           // The number of require directives.
+	Replaces  []string `json:"replaces,omitempty"` // In "old => new" form.
+	Error     string   `json:"error,omitempty"`    // Set if the go.mod could not be parsed.
+}
+
+// DiagnosticsWorkspace is the go.work file in effect.
+type DiagnosticsWorkspace struct {
+	File      string   `json:"file"`
+	GoVersion string   `json:"goVersion,omitempty"`
+	Uses      []string `json:"uses"`
+}
+
+// DiagnosticsPackage is a package scanned by a scanner. Its files are sorted by path.
+type DiagnosticsPackage struct {
+	ImportPath string            `json:"importPath"`
+	Dir        string            `json:"dir"`
+	ModulePath string            `json:"modulePath,omitempty"`
+	Files      []DiagnosticsFile `json:"files"`
+}
+
+// DiagnosticsFile is a file of a scanned package, with the hash of its content.
+type DiagnosticsFile struct {
+	Path    string `json:"path"`
+	SHA256  string `json:"sha256,omitempty"`
+	Overlay bool   `json:"overlay,omitempty"` // Whether the content is given by the overlay.
+	Error   string `json:"error,omitempty"`   // Set if the file could not be read, e.g. if it was removed since.
+}
+
+// DiagnosticsCache is the state of the symbol cache.
+type DiagnosticsCache struct {
+	Path    string `json:"path,omitempty"`
+	Enabled bool   `json:"enabled"`
+	Loaded  bool   `json:"loaded"` // Whether the cache was used by the scanner.
+	Symbols int    `json:"symbols"`
+	Files   int    `json:"files"`
+}
+
+// LogEvent is a log record kept by a LogRecorder.
+type LogEvent struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+}
+
+// LogRecorder is a slog.Handler keeping the last log records, for Scanner.Dump.
+// The records are also passed to the next handler, if any.
+type LogRecorder struct {
+	next   slog.Handler
+	buf    *logRing
+	attrs  []slog.Attr
+	prefix string // The group names, joined with a trailing ".".
+}
+
+type logRing struct {
+	mu     sync.Mutex
+	events []LogEvent
+	size   int
+	start  int // The index of the oldest event once the ring is full.
+}
+
+// NewLogRecorder creates a LogRecorder keeping the last size records, passing them to next
+// (which may be nil). All the records are kept, whatever the level of next, so that the debug
+// events are in the bundle. To also record the events logged with the default logger, as done
+// by most of go-scan, install it with slog.SetDefault(slog.New(r)).
+func NewLogRecorder(next slog.Handler, size int) *LogRecorder {
+	return &LogRecorder{next: next, buf: &logRing{size: size}}
+}
+
+// Enabled implements slog.Handler.
+func (r *LogRecorder) Enabled(ctx context.Context, level slog.Level) bool {
+	return r.buf.size > 0 || (r.next != nil && r.next.Enabled(ctx, level))
+}
+
+// Handle implements slog.Handler.
+func (r *LogRecorder) Handle(ctx context.Context, record slog.Record) error {
+	if r.buf.size > 0 {
+		ev := LogEvent{Time: record.Time, Level: record.Level.String(), Message: record.Message}
+		if len(r.attrs) > 0 || record.NumAttrs() > 0 {
+			ev.Attrs = make(map[string]string, len(r.attrs)+record.NumAttrs())
+		}
+		for _, a := range r.attrs {
+			ev.Attrs[a.Key] = a.Value.String()
+		}
+		add := func(a slog.Attr) bool {
+			ev.Attrs[r.prefix+a.Key] = a.Value.String()
+			return true
+		}
+		record.Attrs(add)
+		r.buf.add(ev)
+	}
+	if r.next != nil && r.next.Enabled(ctx, record.Level) {
+		return r.next.Handle(ctx, record)
+	}
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (r *LogRecorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *r
+	c.attrs = append(append([]slog.Attr(nil), r.attrs...), attrs...)
+	for i := len(r.attrs); i < len(c.attrs); i++ {
+		c.attrs[i].Key = r.prefix + c.attrs[i].Key
+	}
+	if r.next != nil {
+		c.next = r.next.WithAttrs(attrs)
+	}
+	return &c
+}
+
+// WithGroup implements slog.Handler.
+func (r *LogRecorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return r
+	}
+	c := *r
+	c.prefix = r.prefix + name + "."
+	if r.next != nil {
+		c.next = r.next.WithGroup(name)
+	}
+	return &c
+}
+
+// Events returns the recorded events, the oldest first.
+func (r *LogRecorder) Events() []LogEvent {
+	return r.buf.list()
+}
+
+func (b *logRing) add(ev LogEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.events) < b.size {
+		b.events = append(b.events, ev)
+		return
+	}
+	b.events[b.start] = ev
+	b.start = (b.start + 1) % b.size
+}
+
+func (b *logRing) list() []LogEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events := make([]LogEvent, 0, len(b.events))
+	events = append(events, b.events[b.start:]...)
+	return append(events, b.events[:b.start]...)
+}
+
+// WithLogRecorder keeps the last log events of the scanner in r, to include them in the bundle
+// written by Scanner.Dump. The logger of the scanner is replaced by one writing to r.
+func WithLogRecorder(r *LogRecorder) ScannerOption {
+	return func(s *Scanner) error {
+		s.logRecorder = r
+		s.Logger = slog.New(r)
+		return nil
+	}
+}
+
+// Diagnostics returns a snapshot of the configuration and the state of the scanner.
+// The paths are kept as is; see Diagnostics.Anonymize.
+func (s *Scanner) Diagnostics(ctx context.Context) *Diagnostics {
+	d := &Diagnostics{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+	}
+	for _, name := range diagnosticsEnv {
+		if v, ok := os.LookupEnv(name); ok {
+			if d.Env == nil {
+				d.Env = make(map[string]string)
+			}
+			d.Env[name] = v
+		}
+	}
+
+	d.Config = DiagnosticsConfig{
+		WorkDir:          s.workDir,
+		Workspace:        s.isWorkspace,
+		IncludeTests:     s.IncludeTests,
+		DryRun:           s.DryRun,
+		Inspect:          s.Inspect,
+		GoModuleResolver: s.useGoModuleResolver,
+		AutoDownload:     s.autoDownload,
+		DefaultLoadMode:  s.defaultLoadMode.String(),
+	}
+	if loc := s.Locator(); loc != nil {
+		d.Config.GoRoot = loc.GoRoot()
+		d.Config.GoModCache = loc.GoModCache()
+	}
+	for _, rule := range s.loadModeRules {
+		d.Config.LoadModeRules = append(d.Config.LoadModeRules, rule.Pattern+"="+rule.Mode.String())
+	}
+	for k := range s.overlay {
+		d.Config.Overlay = append(d.Config.Overlay, k)
+	}
+	sort.Strings(d.Config.Overlay)
+	for k := range s.ExternalTypeOverrides {
+		d.Config.ExternalTypeOverrides = append(d.Config.ExternalTypeOverrides, k)
+	}
+	sort.Strings(d.Config.ExternalTypeOverrides)
+
+	modFiles := make(map[string]bool)
+	for _, mf := range s.ModFiles() {
+		modFiles[mf.Dir()] = true
+		m := DiagnosticsModule{Path: mf.Path, Dir: mf.Dir(), GoVersion: mf.GoVersion, Toolchain: mf.Toolchain, Requires: len(mf.Requires)}
+		for _, r := range mf.Replaces {
+			old, replaced := r.OldPath, r.NewPath
+			if r.OldVersion != "" {
+				old += "@" + r.OldVersion
+			}
+			if r.NewVersion != "" {
+				replaced += "@" + r.NewVersion
+			}
+			m.Replaces = append(m.Replaces, old+" => "+replaced)
+		}
+		d.Modules = append(d.Modules, m)
+	}
+	for _, m := range s.Modules() {
+		if !modFiles[m.Dir] {
+			d.Modules = append(d.Modules, DiagnosticsModule{Path: m.Path, Dir: m.Dir, Error: "go.mod not found or invalid"})
+		}
+	}
+
+	if work, err := s.WorkFile(); err != nil {
+		slog.DebugContext(ctx, "could not load go.work for diagnostics", "error", err)
+	} else if work != nil {
+		d.Workspace = &DiagnosticsWorkspace{File: work.Filename, GoVersion: work.GoVersion, Uses: work.Uses}
+	}
+
+	s.mu.RLock()
+	pkgs := make([]*Package, 0, len(s.packageCache))
+	for _, pkg := range s.packageCache {
+		pkgs = append(pkgs, pkg)
+	}
+	s.mu.RUnlock()
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].ImportPath < pkgs[j].ImportPath })
+	for _, pkg := range pkgs {
+		p := DiagnosticsPackage{ImportPath: pkg.ImportPath, Dir: pkg.Path, ModulePath: pkg.ModulePath}
+		for _, file := range pkg.Files {
+			p.Files = append(p.Files, s.diagnosticsFile(file))
+		}
+		sort.Slice(p.Files, func(i, j int) bool { return p.Files[i].Path < p.Files[j].Path })
+		d.Packages = append(d.Packages, p)
+	}
+
+	d.Cache = DiagnosticsCache{Path: s.CachePath, Enabled: s.CachePath != ""}
+	if sc := s.symbolCache; sc != nil {
+		sc.mu.RLock()
+		d.Cache.Loaded = true
+		d.Cache.Enabled = sc.useCache
+		d.Cache.Symbols = len(sc.content.Symbols)
+		d.Cache.Files = len(sc.content.Files)
+		sc.mu.RUnlock()
+	}
+
+	if s.logRecorder != nil {
+		d.Logs = s.logRecorder.Events()
+	}
+	return d
+}
+
+// diagnosticsFile hashes the content of the file, taking the overlay into account.
+func (s *Scanner) diagnosticsFile(path string) DiagnosticsFile {
+	f := DiagnosticsFile{Path: path}
+	var content []byte
+	if rel, err := filepath.Rel(s.RootDir(), path); err == nil {
+		content, f.Overlay = s.overlay[rel]
+	}
+	if !f.Overlay {
+		var err error
+		if content, err = os.ReadFile(path); err != nil {
+			f.Error = err.Error()
+			return f
+		}
+	}
+	sum := sha256.Sum256(content)
+	f.SHA256 = hex.EncodeToString(sum[:])
+	return f
+}
+
+// Anonymize replaces the local directories in the paths and the log events with placeholders:
+// $MODULE (or $MODULE1, $MODULE2, ... for a workspace), $WORKDIR, $GOMODCACHE, $GOROOT and $HOME.
+// The module and import paths are kept, as they are needed to reproduce an issue.
+func (d *Diagnostics) Anonymize() {
+	type replacement struct{ dir, placeholder string }
+	var rs []replacement
+	for i, m := range d.Modules {
+		placeholder := "$MODULE"
+		if len(d.Modules) > 1 {
+			placeholder = fmt.Sprintf("$MODULE%d", i+1)
+		}
+		rs = append(rs, replacement{m.Dir, placeholder})
+	}
+	rs = append(rs,
+		replacement{d.Config.WorkDir, "$WORKDIR"},
+		replacement{d.Config.GoModCache, "$GOMODCACHE"},
+		replacement{d.Config.GoRoot, "$GOROOT"},
+	)
+	if home, err := os.UserHomeDir(); err == nil {
+		rs = append(rs, replacement{home, "$HOME"})
+	}
+	// The longest directories first, so that a module in the home directory becomes $MODULE.
+	sort.SliceStable(rs, func(i, j int) bool { return len(rs[i].dir) > len(rs[j].dir) })
+	var oldnew []string
+	for _, r := range rs {
+		if r.dir != "" && r.dir != string(filepath.Separator) {
+			oldnew = append(oldnew, r.dir, r.placeholder)
+		}
+	}
+	replacer := strings.NewReplacer(oldnew...)
+	anonymize := func(s *string) { *s = replacer.Replace(*s) }
+
+	for k, v := range d.Env {
+		d.Env[k] = replacer.Replace(v)
+	}
+	anonymize(&d.Config.WorkDir)
+	anonymize(&d.Config.GoRoot)
+	anonymize(&d.Config.GoModCache)
+	for i := range d.Modules {
+		anonymize(&d.Modules[i].Dir)
+		for j := range d.Modules[i].Replaces {
+			anonymize(&d.Modules[i].Replaces[j])
+		}
+	}
+	if d.Workspace != nil {
+		anonymize(&d.Workspace.File)
+		uses := make([]string, len(d.Workspace.Uses))
+		for i, u := range d.Workspace.Uses {
+			uses[i] = replacer.Replace(u)
+		}
+		d.Workspace.Uses = uses
+	}
+	for i := range d.Packages {
+		anonymize(&d.Packages[i].Dir)
+		for j := range d.Packages[i].Files {
+			anonymize(&d.Packages[i].Files[j].Path)
+			anonymize(&d.Packages[i].Files[j].Error)
+		}
+	}
+	anonymize(&d.Cache.Path)
+	for i := range d.Logs {
+		anonymize(&d.Logs[i].Message)
+		for k, v := range d.Logs[i].Attrs {
+			d.Logs[i].Attrs[k] = replacer.Replace(v)
+		}
+	}
+}
+
+// Dump writes the anonymized diagnostics of the scanner as DiagnosticsFileName in dir, which
+// is created if needed, and returns the path of the file. The bundle is meant to be attached
+// to a bug report; see Diagnostics.
+func (s *Scanner) Dump(ctx context.Context, dir string) (string, error) {
+	d := s.Diagnostics(ctx)
+	d.Anonymize()
+
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling diagnostics: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating directory for diagnostics: %w", err)
+	}
+	path := filepath.Join(dir, DiagnosticsFileName)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("writing diagnostics: %w", err)
+	}
+	slog.InfoContext(ctx, "diagnostics written", "path", path, "packages", len(d.Packages), "logs", len(d.Logs))
+	return path, nil
+}
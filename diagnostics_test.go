@@ -0,0 +1,120 @@
+package goscan_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestDump(t *testing.T) {
+	files := map[string]string{
+		"go.mod": `module example.com/diag
+
+go 1.22
+
+replace example.com/other => ../other
+`,
+		"models/models.go": "package models\n\ntype User struct{}\n",
+		"models/extra.go":  "package models\n",
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	ctx := context.Background()
+	recorder := goscan.NewLogRecorder(nil, 2)
+	s, err := goscan.New(
+		goscan.WithWorkDir(dir),
+		goscan.WithLogRecorder(recorder),
+		goscan.WithPackageLoadMode("example.com/diag/...", goscan.LoadDecls),
+		goscan.WithOverlay(scanner.Overlay{"models/extra.go": []byte("package models\n\ntype Extra struct{}\n")}),
+	)
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	if _, err := s.ScanPackageFromImportPath(ctx, "example.com/diag/models"); err != nil {
+		t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+	}
+	for i := range 3 {
+		s.Logger.InfoContext(ctx, fmt.Sprintf("event %d", i), "file", filepath.Join(dir, "models", "models.go"))
+	}
+
+	outDir := filepath.Join(t.TempDir(), "bundle")
+	path, err := s.Dump(ctx, outDir)
+	if err != nil {
+		t.Fatalf("Dump() failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), dir) {
+		t.Errorf("the bundle is not anonymized, it contains %q:\n%s", dir, data)
+	}
+
+	var d goscan.Diagnostics
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatalf("unmarshaling the bundle failed: %v", err)
+	}
+
+	t.Run("config", func(t *testing.T) {
+		want := goscan.DiagnosticsConfig{
+			WorkDir:         "$MODULE",
+			DefaultLoadMode: "full",
+			LoadModeRules:   []string{"example.com/diag/...=decls"},
+			Overlay:         []string{"models/extra.go"},
+		}
+		if diff := cmp.Diff(want, d.Config); diff != "" {
+			t.Errorf("config mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("modules", func(t *testing.T) {
+		want := []goscan.DiagnosticsModule{
+			{Path: "example.com/diag", Dir: "$MODULE", GoVersion: "1.22", Replaces: []string{"example.com/other => ../other"}},
+		}
+		if diff := cmp.Diff(want, d.Modules); diff != "" {
+			t.Errorf("modules mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("packages", func(t *testing.T) {
+		hash := func(content string) string {
+			sum := sha256.Sum256([]byte(content))
+			return hex.EncodeToString(sum[:])
+		}
+		want := []goscan.DiagnosticsPackage{{
+			ImportPath: "example.com/diag/models",
+			Dir:        "$MODULE/models",
+			ModulePath: "example.com/diag",
+			Files: []goscan.DiagnosticsFile{
+				{Path: "$MODULE/models/extra.go", SHA256: hash("package models\n\ntype Extra struct{}\n"), Overlay: true},
+				{Path: "$MODULE/models/models.go", SHA256: hash(files["models/models.go"])},
+			},
+		}}
+		if diff := cmp.Diff(want, d.Packages); diff != "" {
+			t.Errorf("packages mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		var got []string
+		for _, ev := range d.Logs {
+			got = append(got, ev.Level+" "+ev.Message+" "+ev.Attrs["file"])
+		}
+		want := []string{"INFO event 1 $MODULE/models/models.go", "INFO event 2 $MODULE/models/models.go"}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("logs mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -8,6 +8,8 @@ import (
 	"log"
 	"log/slog"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
 
 	goscan "github.com/podhmo/go-scan"
@@ -19,18 +21,50 @@ import (
 func main() {
 	// 1. Define and parse command-line flags.
 	var targetFunc string
-	flag.StringVar(&targetFunc, "target", "", "Target function to trace calls to (e.g., example.com/mylib.MyFunction)")
+	flag.StringVar(&targetFunc, "target", "", "Target function to trace calls to (e.g., example.com/mylib.MyFunction, (*example.com/mylib.MyType).MyMethod or example.com/mylib.MyInterface.MyMethod)")
 	var logLevel = slog.LevelWarn
 	flag.TextVar(&logLevel, "log-level", &logLevel, "Log level (debug, info, warn, error)")
+	var opts outputOptions
+	flag.StringVar(&opts.Format, "format", "text", "Output format (text, json, dot)")
+	flag.BoolVar(&opts.Dedup, "dedup", false, "Merge the call stacks which are the same once the ignored frames are removed")
+	var ignoreFrames string
+	flag.StringVar(&ignoreFrames, "ignore-frames", "", "Comma-separated regular expressions of the names of the frames to remove from the call stacks (e.g., '^<Symbolic:,mylib\\.helper')")
 
 	flag.Parse()
 
+	// The log level of .goscan.yaml is the default of the flag.
+	cfg, err := goscan.FindConfigFile(".")
+	if err != nil {
+		log.Fatalf("Error: %+v", err)
+	}
+	if level, ok := cfg.Level(); ok && !isFlagSet("log-level") {
+		logLevel = level
+	}
+
 	if targetFunc == "" {
 		fmt.Fprintln(os.Stderr, "Error: -target flag is required")
 		flag.Usage()
 		os.Exit(1)
 	}
 
+	switch opts.Format {
+	case "text", "json", "dot":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown -format %q\n", opts.Format)
+		flag.Usage()
+		os.Exit(1)
+	}
+	for _, pattern := range strings.Split(ignoreFrames, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			log.Fatalf("Error: invalid -ignore-frames pattern %q: %+v", pattern, err)
+		}
+		opts.IgnoreFrames = append(opts.IgnoreFrames, re)
+	}
+
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: &logLevel}))
 
 	// The package patterns to scan are taken from the remaining arguments
@@ -39,69 +73,30 @@ func main() {
 		pkgPatterns = []string{"./..."} // Default to scanning the current module
 	}
 
-	if err := run(context.Background(), os.Stdout, logger, targetFunc, pkgPatterns, ".", "", ""); err != nil {
+	if err := run(context.Background(), os.Stdout, logger, targetFunc, pkgPatterns, ".", "", "", opts); err != nil {
 		log.Fatalf("Error: %+v", err)
 	}
 }
 
-func getFuncTargetName(f *scanner.FunctionInfo) string {
-	if f == nil {
-		return ""
-	}
-	if f.Receiver == nil {
-		return fmt.Sprintf("%s.%s", f.PkgPath, f.Name)
-	}
-
-	// Method
-	recvType := f.Receiver.Type
-	recvString := recvType.Name
-	if recvType.IsPointer {
-		// Ensure the name is wrapped in parens for pointer types, e.g., "(*MyType)"
-		// The scanner's string representation might already do this, but we can be defensive.
-		if !strings.HasPrefix(recvString, "(*") {
-			recvString = fmt.Sprintf("(*%s)", recvString)
+// isFlagSet reports whether the flag is given on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
 		}
-	}
-	return fmt.Sprintf("%s.%s.%s", f.PkgPath, recvString, f.Name)
+	})
+	return set
 }
 
-func run(ctx context.Context, out io.Writer, logger *slog.Logger, targetFunc string, pkgPatterns []string, workDir string, mainPkgPath string, scanPolicyExclude string) error {
+func run(ctx context.Context, out io.Writer, logger *slog.Logger, targetFunc string, pkgPatterns []string, workDir string, mainPkgPath string, scanPolicyExclude string, opts outputOptions) error {
 	logger.Info("starting call-trace", "target", targetFunc, "packages", pkgPatterns, "workDir", workDir, "mainPkg", mainPkgPath, "exclude", scanPolicyExclude)
 
-	// a more robust way to extract the package path, handling methods like
-	// "pkg.path.(*Type).Method"
-	var pkgPath string
-	lastDot := strings.LastIndex(targetFunc, ".")
-	if lastDot == -1 {
-		return fmt.Errorf("invalid target function format: %q. Expected format: <pkg>.<func>", targetFunc)
-	}
-	// Check if it's a method with a receiver like (*Type) or Type
-	if endParen := strings.LastIndex(targetFunc[:lastDot], ")"); endParen != -1 && endParen > strings.LastIndex(targetFunc[:lastDot], "(") {
-		// e.g. "pkg.path.(*Type).Method"
-		// lastDot is at ".Method"
-		// endParen is at ")" in "(*Type)"
-		// we need to find the dot before the receiver type
-		pkgPathEnd := strings.LastIndex(targetFunc[:endParen], ".")
-		if pkgPathEnd != -1 {
-			pkgPath = targetFunc[:pkgPathEnd]
-		} else {
-			// This could be a type in the "main" package of the module root
-			// where there's no preceding dot.
-			// e.g. "my-module.main.(*MyType).MyMethod"
-			// In this case, we search for the package path up to the opening parenthesis.
-			openParen := strings.LastIndex(targetFunc[:lastDot], "(")
-			if openParen != -1 {
-				pkgPath = targetFunc[:openParen]
-				// trim the trailing dot if it exists
-				pkgPath = strings.TrimSuffix(pkgPath, ".")
-
-			} else {
-				return fmt.Errorf("could not extract pkg path from method target %q", targetFunc)
-			}
-		}
-	} else {
-		// simple function, or method on non-pointer receiver without parens
-		pkgPath = targetFunc[:lastDot]
+	// The target is a canonical name, e.g. "example.com/mylib.Func" or "(*example.com/mylib.Type).Method".
+	// The "example.com/mylib.(*Type).Method" and "example.com/mylib.Type.Method" forms are accepted as well.
+	target, err := scanner.ParseCanonicalName(targetFunc)
+	if err != nil {
+		return fmt.Errorf("invalid target function format: %w", err)
 	}
 
 	// 2. Initialize the scanner.
@@ -121,6 +116,19 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, targetFunc str
 		}
 	}
 
+	if method, ok := findLegacyMethod(ctx, s, target); ok {
+		target = method
+	}
+
+	// An interface method target matches the calls through the interface and the calls of
+	// any of its implementations.
+	targets := []scanner.CanonicalName{target}
+	ifaceTarget := findInterfaceMethod(ctx, s, target)
+	if ifaceTarget != nil {
+		targets = append([]scanner.CanonicalName{ifaceTarget.name}, ifaceTarget.implementations(ctx, s)...)
+		logger.Info("tracing an interface method", "interface", ifaceTarget.name.Receiver(), "method", ifaceTarget.name.Name, "implementations", len(targets)-1)
+	}
+
 	// 4. Build the reverse dependency map.
 	revDepMap, err := s.Walker.BuildReverseDependencyMap(ctx)
 	if err != nil {
@@ -129,8 +137,13 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, targetFunc str
 
 	// 5. Find all packages that could possibly call the target function.
 	analysisScope := make(map[string]bool)
-	queue := []string{pkgPath}
-	analysisScope[pkgPath] = true
+	var queue []string
+	for _, t := range targets {
+		if !analysisScope[t.PkgPath] {
+			analysisScope[t.PkgPath] = true
+			queue = append(queue, t.PkgPath)
+		}
+	}
 	head := 0
 	for head < len(queue) {
 		currentPkg := queue[head]
@@ -173,14 +186,23 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, targetFunc str
 		}
 
 		if calleeFunc != nil {
-			calleeName := getFuncTargetName(calleeFunc)
+			callee := calleeFunc.CanonicalName()
 			// Add verbose logging for debugging the no_call test case
 			if targetFunc == "fmt.Println" {
-				logger.Debug("checking no_call", "callee", calleeName, "target", targetFunc)
+				logger.Debug("checking no_call", "callee", callee.String(), "target", targetFunc)
 			}
-			if calleeName == targetFunc {
+			if matchesAny(callee, targets) || (ifaceTarget != nil && ifaceTarget.calledBy(calleeObj)) {
 				stack := i.CallStack()
-				directHits = append(directHits, stack)
+				switch {
+				case len(stack) == 0:
+					// The implementations of the called interface methods are marked by Finalize,
+					// outside of any call stack; those calls are reported at the interface method calls.
+				case ifaceTarget != nil && stack[len(stack)-1].Fn == nil:
+					// The call of the interface method is being dispatched to its possible
+					// implementations, and it is reported already.
+				default:
+					directHits = append(directHits, stack)
+				}
 			}
 		}
 		return nil
@@ -244,20 +266,134 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, targetFunc str
 	interp.Finalize(ctx)
 
 	// 9. Print the results.
-	if len(directHits) == 0 {
+	stacks := buildStacks(s.Fset(), directHits, opts)
+	switch opts.Format {
+	case "json":
+		return writeJSON(out, targetFunc, stacks)
+	case "dot":
+		writeDOT(out, targetFunc, stacks)
+		return nil
+	}
+	if len(stacks) == 0 {
 		fmt.Fprintf(out, "No calls to %s found.\n", targetFunc)
 		return nil
 	}
+	writeText(out, s.Fset(), targetFunc, stacks)
+	return nil
+}
 
-	fmt.Fprintf(out, "Found %d call stacks to %s:\n\n", len(directHits), targetFunc)
-	fset := s.Fset()
-	for i, stack := range directHits {
-		fmt.Fprintf(out, "--- Stack %d ---\n", i+1)
-		for _, frame := range stack {
-			fmt.Fprintln(out, frame.Format(fset))
+func matchesAny(name scanner.CanonicalName, targets []scanner.CanonicalName) bool {
+	for _, t := range targets {
+		if name.Matches(t) {
+			return true
 		}
-		fmt.Fprintln(out)
 	}
+	return false
+}
 
-	return nil
+// interfaceMethod is a method of an interface type, as a target.
+type interfaceMethod struct {
+	name  scanner.CanonicalName // e.g. (example.com/mylib.Repository).Save
+	iface *scanner.TypeInfo
+}
+
+// findLegacyMethod returns the method a target in the "example.com/mylib.Type.Method" form
+// refers to. Such a target is parsed as the function Method of the package
+// "example.com/mylib.Type"; if there is no such package but the type exists, it is the method
+// of the type, whose receiver is a pointer or not as declared.
+func findLegacyMethod(ctx context.Context, s *goscan.Scanner, target scanner.CanonicalName) (scanner.CanonicalName, bool) {
+	lastDot := strings.LastIndex(target.PkgPath, ".")
+	if target.IsMethod() || lastDot < strings.LastIndex(target.PkgPath, "/") {
+		return target, false
+	}
+	if _, err := lookupPackage(ctx, s, target.PkgPath); err == nil {
+		return target, false
+	}
+	name := scanner.CanonicalName{PkgPath: target.PkgPath[:lastDot], TypeName: target.PkgPath[lastDot+1:], Name: target.Name}
+	pkg, err := lookupPackage(ctx, s, name.PkgPath)
+	if err != nil || pkg.Lookup(name.TypeName) == nil {
+		return target, false
+	}
+	for _, f := range pkg.Functions {
+		if f.Receiver == nil || f.Name != name.Name {
+			continue
+		}
+		if n := f.CanonicalName(); n.TypeName == name.TypeName {
+			return n, true
+		}
+	}
+	return name, true // e.g. an interface method
+}
+
+// lookupPackage returns the package of the import path, scanning it if it is not seen yet.
+func lookupPackage(ctx context.Context, s *goscan.Scanner, importPath string) (*scanner.PackageInfo, error) {
+	if pkg, ok := s.AllSeenPackages()[importPath]; ok && pkg != nil {
+		return pkg, nil
+	}
+	return s.ScanPackageFromImportPath(ctx, importPath)
+}
+
+// findInterfaceMethod returns the interface method the target refers to, or nil if the target
+// is not an interface method, e.g. "(example.com/mylib.Repository).Save".
+func findInterfaceMethod(ctx context.Context, s *goscan.Scanner, target scanner.CanonicalName) *interfaceMethod {
+	if !target.IsMethod() || target.IsPointer {
+		return nil
+	}
+	pkg, err := lookupPackage(ctx, s, target.PkgPath)
+	if err != nil {
+		return nil
+	}
+	t := pkg.Lookup(target.TypeName)
+	if t == nil || t.Interface == nil {
+		return nil
+	}
+	return &interfaceMethod{name: target, iface: t}
+}
+
+// implementations returns the canonical names of the method of the scanned types implementing the interface.
+// The implementers are looked up in the index of the scanner, which the interpreter shares; the
+// interpreter itself is created later, as its scan policy depends on these implementations.
+func (m *interfaceMethod) implementations(ctx context.Context, s *goscan.Scanner) []scanner.CanonicalName {
+	seen := s.AllSeenPackages()
+	var names []scanner.CanonicalName
+	for _, t := range s.ImplementersOf(ctx, m.iface) {
+		pkg, ok := seen[t.PkgPath]
+		if !ok || pkg == nil {
+			continue
+		}
+		for _, f := range pkg.Functions {
+			if f.Receiver == nil || f.Name != m.name.Name {
+				continue
+			}
+			if n := f.CanonicalName(); n.TypeName == t.Name {
+				names = append(names, n)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i].String() < names[j].String() })
+	return names
+}
+
+// calledBy reports whether the callee is the method called through a value of the interface type,
+// whose implementation is not known.
+func (m *interfaceMethod) calledBy(callee object.Object) bool {
+	p, ok := callee.(*object.SymbolicPlaceholder)
+	if !ok || p.UnderlyingFunc == nil || p.UnderlyingFunc.Name != m.name.Name || p.Receiver == nil {
+		return false
+	}
+	recv := p.Receiver
+	for recv != nil {
+		if t := recv.TypeInfo(); t != nil {
+			return t.PkgPath == m.name.PkgPath && t.Name == m.name.TypeName
+		}
+		if ft := recv.FieldType(); ft != nil {
+			return ft.FullImportPath == m.name.PkgPath && ft.TypeName == m.name.TypeName
+		}
+		v, ok := recv.(*object.Variable)
+		if !ok {
+			return false
+		}
+		recv = v.Value
+	}
+	return false
 }
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -24,6 +25,7 @@ func TestCallTrace(t *testing.T) {
 		mainPkg           string
 		targetFunc        string
 		scanPolicyExclude string
+		opts              outputOptions
 	}{
 		{
 			name:       "direct_func_call",
@@ -49,6 +51,12 @@ func TestCallTrace(t *testing.T) {
 			mainPkg:    basePrefix + "/method_call/src/myapp",
 			targetFunc: basePrefix + "/method_call/src/mylib.(*Greeter).Greet",
 		},
+		{
+			name:       "method_call_legacy_form",
+			dir:        "./testdata/method_call",
+			mainPkg:    basePrefix + "/method_call/src/myapp",
+			targetFunc: basePrefix + "/method_call/src/mylib.Greeter.Greet",
+		},
 		{
 			name:       "indirect_method_call",
 			dir:        "./testdata/indirect_method_call",
@@ -62,6 +70,39 @@ func TestCallTrace(t *testing.T) {
 			targetFunc:        basePrefix + "/out_of_policy/src/mylib.InScope",
 			scanPolicyExclude: basePrefix + "/out_of_policy/src/anotherlib",
 		},
+		{
+			name:       "interface_method_call",
+			dir:        "./testdata/interface_method_call",
+			mainPkg:    basePrefix + "/interface_method_call/src/myapp",
+			targetFunc: basePrefix + "/interface_method_call/src/domain.UserRepository.Save",
+		},
+		{
+			name:       "interface_method_call_receiver_form",
+			dir:        "./testdata/interface_method_call",
+			mainPkg:    basePrefix + "/interface_method_call/src/myapp",
+			targetFunc: "(" + basePrefix + "/interface_method_call/src/domain.UserRepository).Save",
+		},
+		{
+			name:       "interface_method_call_json",
+			dir:        "./testdata/interface_method_call",
+			mainPkg:    basePrefix + "/interface_method_call/src/myapp",
+			targetFunc: basePrefix + "/interface_method_call/src/domain.UserRepository.Save",
+			opts:       outputOptions{Format: "json"},
+		},
+		{
+			name:       "interface_method_call_dot",
+			dir:        "./testdata/interface_method_call",
+			mainPkg:    basePrefix + "/interface_method_call/src/myapp",
+			targetFunc: basePrefix + "/interface_method_call/src/domain.UserRepository.Save",
+			opts:       outputOptions{Format: "dot"},
+		},
+		{
+			name:       "interface_method_call_dedup",
+			dir:        "./testdata/interface_method_call",
+			mainPkg:    basePrefix + "/interface_method_call/src/myapp",
+			targetFunc: basePrefix + "/interface_method_call/src/domain.UserRepository.Save",
+			opts:       outputOptions{Dedup: true, IgnoreFrames: []*regexp.Regexp{regexp.MustCompile(`^<Symbolic:`)}},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -79,6 +120,7 @@ func TestCallTrace(t *testing.T) {
 				tc.dir,                // workDir
 				tc.mainPkg,            // mainPkgPath
 				tc.scanPolicyExclude,  // scanPolicyExclude
+				tc.opts,
 			)
 			if err != nil {
 				t.Fatalf("run() failed: %v", err)
@@ -94,7 +136,10 @@ func TestCallTrace(t *testing.T) {
 			normalizedGot := strings.TrimSpace(buf.String())
 			normalizedGot = strings.ReplaceAll(normalizedGot, "\r\n", "\n")
 			normalizedGot = strings.ReplaceAll(normalizedGot, rootDir, "##WORKDIR##")
-			normalizedGot = strings.ReplaceAll(normalizedGot, "\\", "/")
+			if tc.opts.Format == "" {
+				// The JSON and DOT outputs escape the backslashes of the Windows paths.
+				normalizedGot = strings.ReplaceAll(normalizedGot, "\\", "/")
+			}
 
 			goldenFile := filepath.Join("testdata", tc.name+".golden")
 			if *update {
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// outputOptions are the options of the report of the hit stacks.
+type outputOptions struct {
+	// Format is "text" (the default), "json" or "dot".
+	Format string
+	// Dedup merges the stacks which go through the same functions once the ignored frames are
+	// removed, keeping the call sites of the first one.
+	Dedup bool
+	// IgnoreFrames are the patterns of the names of the frames removed from the stacks, e.g.
+	// the helpers or the symbolic frames of the calls through interfaces, matched against the
+	// qualified names of the functions, e.g. "example.com/mylib.(*Type).Method".
+	IgnoreFrames []*regexp.Regexp
+}
+
+// frame is a frame of a hit stack: the called function, and the position of its call.
+type frame struct {
+	Function string `json:"function"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	CallSite string `json:"callSite,omitempty"` // the source line of the call
+
+	raw *object.CallFrame
+}
+
+// hitStack is a call stack reaching the target.
+type hitStack struct {
+	Frames []frame `json:"frames"`
+	Count  int     `json:"count"` // the number of stacks merged into this one, see outputOptions.Dedup
+}
+
+// traceReport is the JSON output.
+type traceReport struct {
+	Target string     `json:"target"`
+	Stacks []hitStack `json:"stacks"`
+}
+
+// buildStacks converts the hit stacks, removing the ignored frames, and merging the same stacks
+// with opts.Dedup.
+func buildStacks(fset *token.FileSet, hits [][]*object.CallFrame, opts outputOptions) []hitStack {
+	lines := &sourceLines{files: make(map[string][]string)}
+	var stacks []hitStack
+	seen := make(map[string]int) // the index of a stack, by key
+	for _, hit := range hits {
+		var frames []frame
+		for _, f := range hit {
+			fr := newFrame(fset, f, lines)
+			if ignored(fr.Function, opts.IgnoreFrames) {
+				continue
+			}
+			frames = append(frames, fr)
+		}
+		if opts.Dedup {
+			key := stackKey(frames)
+			if i, ok := seen[key]; ok {
+				stacks[i].Count++
+				continue
+			}
+			seen[key] = len(stacks)
+		}
+		stacks = append(stacks, hitStack{Frames: frames, Count: 1})
+	}
+	return stacks
+}
+
+// newFrame returns the frame of a call frame, named with the canonical name of the function if
+// known, e.g. "example.com/mylib.(*Type).Method".
+func newFrame(fset *token.FileSet, f *object.CallFrame, lines *sourceLines) frame {
+	fr := frame{Function: f.Function, raw: f}
+	if f.Fn != nil && f.Fn.Def != nil {
+		fr.Function = f.Fn.Def.CanonicalName().String()
+	}
+	if f.Pos.IsValid() {
+		pos := fset.Position(f.Pos)
+		fr.File, fr.Line, fr.Column = pos.Filename, pos.Line, pos.Column
+		fr.CallSite = lines.line(pos.Filename, pos.Line)
+	}
+	return fr
+}
+
+func ignored(name string, patterns []*regexp.Regexp) bool {
+	for _, p := range patterns {
+		if p.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// stackKey identifies a stack by the path of its functions.
+func stackKey(frames []frame) string {
+	var b strings.Builder
+	for _, f := range frames {
+		b.WriteString(f.Function)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// writeText writes the stacks in the format of the symgo call stacks.
+func writeText(out io.Writer, fset *token.FileSet, target string, stacks []hitStack) {
+	fmt.Fprintf(out, "Found %d call stacks to %s:\n\n", len(stacks), target)
+	for i, stack := range stacks {
+		if stack.Count > 1 {
+			fmt.Fprintf(out, "--- Stack %d (x%d) ---\n", i+1, stack.Count)
+		} else {
+			fmt.Fprintf(out, "--- Stack %d ---\n", i+1)
+		}
+		for _, f := range stack.Frames {
+			fmt.Fprintln(out, f.raw.Format(fset))
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+// writeJSON writes the stacks as a traceReport.
+func writeJSON(out io.Writer, target string, stacks []hitStack) error {
+	if stacks == nil {
+		stacks = []hitStack{}
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(traceReport{Target: target, Stacks: stacks})
+}
+
+// writeDOT writes the union of the stacks as a graph: the functions are the nodes, and each call
+// of a stack is an edge from the caller to the callee, labeled with the positions of the calls.
+// The last function of each stack calls the target.
+func writeDOT(out io.Writer, target string, stacks []hitStack) {
+	type edge struct{ from, to string }
+	labels := make(map[edge]map[string]bool)
+	nodes := map[string]bool{target: true}
+	addEdge := func(from, to, label string) {
+		e := edge{from, to}
+		if labels[e] == nil {
+			labels[e] = make(map[string]bool)
+		}
+		if label != "" {
+			labels[e][label] = true
+		}
+	}
+	for _, stack := range stacks {
+		for i, f := range stack.Frames {
+			nodes[f.Function] = true
+			if i > 0 {
+				addEdge(stack.Frames[i-1].Function, f.Function, callPosition(f))
+			}
+		}
+		if n := len(stack.Frames); n > 0 {
+			addEdge(stack.Frames[n-1].Function, target, "")
+		}
+	}
+
+	fmt.Fprintln(out, "digraph calltrace {")
+	fmt.Fprintln(out, "  node [shape=box];")
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == target {
+			fmt.Fprintf(out, "  %q [style=bold];\n", name)
+		} else {
+			fmt.Fprintf(out, "  %q;\n", name)
+		}
+	}
+	edges := make([]edge, 0, len(labels))
+	for e := range labels {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].from != edges[j].from {
+			return edges[i].from < edges[j].from
+		}
+		return edges[i].to < edges[j].to
+	})
+	for _, e := range edges {
+		var positions []string
+		for label := range labels[e] {
+			positions = append(positions, label)
+		}
+		sort.Strings(positions)
+		if len(positions) == 0 {
+			fmt.Fprintf(out, "  %q -> %q;\n", e.from, e.to)
+			continue
+		}
+		fmt.Fprintf(out, "  %q -> %q [label=%q];\n", e.from, e.to, strings.Join(positions, "\n"))
+	}
+	fmt.Fprintln(out, "}")
+}
+
+// callPosition returns the position of the call of a frame, e.g. "main.go:12".
+func callPosition(f frame) string {
+	if f.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+// sourceLines reads the lines of the source files, once per file.
+type sourceLines struct {
+	files map[string][]string
+}
+
+func (s *sourceLines) line(filename string, n int) string {
+	lines, ok := s.files[filename]
+	if !ok {
+		if f, err := os.Open(filename); err == nil {
+			sc := bufio.NewScanner(f)
+			for sc.Scan() {
+				lines = append(lines, sc.Text())
+			}
+			f.Close()
+		}
+		s.files[filename] = lines
+	}
+	if n <= 0 || n > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[n-1])
+}
@@ -0,0 +1,10 @@
+package domain
+
+type User struct {
+	Name string
+}
+
+// UserRepository is implemented by the infrastructure layer.
+type UserRepository interface {
+	Save(u *User) error
+}
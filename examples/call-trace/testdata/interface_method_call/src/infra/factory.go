@@ -0,0 +1,11 @@
+package infra
+
+import "github.com/podhmo/go-scan/examples/call-trace/testdata/interface_method_call/src/domain"
+
+// NewUserRepository returns the repository selected by the kind.
+func NewUserRepository(kind string) domain.UserRepository {
+	if kind == "logging" {
+		return &LoggingUserRepository{Next: &MemoryUserRepository{}}
+	}
+	return &MemoryUserRepository{}
+}
@@ -0,0 +1,25 @@
+package infra
+
+import (
+	"fmt"
+
+	"github.com/podhmo/go-scan/examples/call-trace/testdata/interface_method_call/src/domain"
+)
+
+type MemoryUserRepository struct {
+	users []*domain.User
+}
+
+func (r *MemoryUserRepository) Save(u *domain.User) error {
+	r.users = append(r.users, u)
+	return nil
+}
+
+type LoggingUserRepository struct {
+	Next domain.UserRepository
+}
+
+func (r *LoggingUserRepository) Save(u *domain.User) error {
+	fmt.Println("saving", u.Name)
+	return r.Next.Save(u)
+}
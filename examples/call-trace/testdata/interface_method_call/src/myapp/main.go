@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+
+	"github.com/podhmo/go-scan/examples/call-trace/testdata/interface_method_call/src/domain"
+	"github.com/podhmo/go-scan/examples/call-trace/testdata/interface_method_call/src/infra"
+	"github.com/podhmo/go-scan/examples/call-trace/testdata/interface_method_call/src/usecase"
+)
+
+func main() {
+	register(&infra.MemoryUserRepository{})
+	register(infra.NewUserRepository(os.Getenv("REPOSITORY")))
+
+	// A direct call of an implementation is reported as well.
+	repo := &infra.MemoryUserRepository{}
+	repo.Save(&domain.User{Name: "admin"})
+}
+
+func register(repo domain.UserRepository) {
+	uc := &usecase.RegisterUser{Repo: repo}
+	uc.Do("alice")
+}
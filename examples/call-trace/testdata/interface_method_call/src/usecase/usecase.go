@@ -0,0 +1,11 @@
+package usecase
+
+import "github.com/podhmo/go-scan/examples/call-trace/testdata/interface_method_call/src/domain"
+
+type RegisterUser struct {
+	Repo domain.UserRepository
+}
+
+func (uc *RegisterUser) Do(name string) error {
+	return uc.Repo.Save(&domain.User{Name: name})
+}
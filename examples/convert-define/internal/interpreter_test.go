@@ -185,3 +185,33 @@ func TestRunner(t *testing.T) {
 		t.Errorf("Imports mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestRunner_LocalImport(t *testing.T) {
+	wd := filepath.Join("..", "testdata", "local-import")
+
+	runner, err := NewRunner(
+		goscan.WithWorkDir(wd),
+		goscan.WithGoModuleResolver(),
+	)
+	if err != nil {
+		t.Fatalf("NewRunner() failed: %+v", err)
+	}
+
+	// The rules are defined by a script package imported by the define file, "./rules".
+	if err := runner.Run(context.Background(), filepath.Join(wd, "define.go")); err != nil {
+		t.Fatalf("Run() failed: %+v", err)
+	}
+
+	if got, want := len(runner.Info.GlobalRules), 1; got != want {
+		t.Fatalf("expected %d global rule, but got %d", want, got)
+	}
+	if got, want := runner.Info.GlobalRules[0].UsingFunc, "convutil.TimeToString"; got != want {
+		t.Errorf("UsingFunc: want %q, got %q", want, got)
+	}
+	wantImports := map[string]string{
+		"convutil": "example.com/test/convutil",
+	}
+	if diff := cmp.Diff(wantImports, runner.Info.Imports); diff != "" {
+		t.Errorf("Imports mismatch (-want +got):\n%s", diff)
+	}
+}
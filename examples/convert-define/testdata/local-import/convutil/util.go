@@ -0,0 +1,7 @@
+package convutil
+
+import "time"
+
+func TimeToString(t time.Time) string {
+	return t.String()
+}
@@ -0,0 +1,7 @@
+package main
+
+import "./rules"
+
+func main() {
+	rules.Globals()
+}
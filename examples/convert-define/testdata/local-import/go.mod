@@ -0,0 +1,7 @@
+module example.com/test
+
+go 1.24
+
+toolchain go1.24.3
+
+require github.com/podhmo/go-scan/examples/convert-define v0.0.0-20250824154125-c8f0ebb23784
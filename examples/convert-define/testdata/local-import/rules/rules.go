@@ -0,0 +1,11 @@
+package rules
+
+import (
+	"example.com/test/convutil"
+	"github.com/podhmo/go-scan/examples/convert-define/define"
+)
+
+// Globals defines the rules shared by the define scripts.
+func Globals() {
+	define.Rule(convutil.TimeToString)
+}
@@ -122,6 +122,66 @@ func Generate(s *goscan.Scanner, info *model.ParsedInfo, header string) ([]byte,
 		im.Add(path, alias)
 	}
 
+	allPairs, err := collectPairs(ctx, s, info, im)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range info.GlobalRules {
+		if rule.SrcTypeInfo != nil {
+			im.Qualify(rule.SrcTypeInfo.PkgPath, rule.SrcTypeInfo.Name)
+		}
+		if rule.DstTypeInfo != nil {
+			im.Qualify(rule.DstTypeInfo.PkgPath, rule.DstTypeInfo.Name)
+		}
+	}
+
+	templateData := TemplateData{
+		PackageName: info.PackageName,
+		Imports:     im.Imports(),
+		Pairs:       allPairs,
+		Im:          im,
+		Info:        info,
+		Header:      header,
+	}
+
+	funcMap := template.FuncMap{
+		"getAssignment": func(im *goscan.ImportManager, info *model.ParsedInfo, field FieldMap, srcVar, dstVar, ecVar, ctxVar string) string {
+			return getAssignment(im, info, field, srcVar, dstVar, ecVar, ctxVar)
+		},
+		"getMapKeyAssignment": func(im *goscan.ImportManager, info *model.ParsedInfo, srcVar, dstVar string, srcT, dstT *scanner.FieldType, ecVar, ctxVar string) string {
+			return getMapKeyAssignment(im, info, srcVar, dstVar, srcT, dstT, ecVar, ctxVar)
+		},
+		"getValidator": func(im *goscan.ImportManager, info *model.ParsedInfo, field FieldMap, dstVar, ecVar, ctxVar string) string {
+			return getValidator(im, info, field, dstVar, ecVar, ctxVar)
+		},
+		"getQualifiedTypeName": func(im *goscan.ImportManager, structInfo *model.StructInfo) string {
+			if structInfo == nil || structInfo.Type == nil {
+				return "invalid"
+			}
+			// When generating code for a specific package, types within that package don't need qualification.
+			if structInfo.Type.PkgPath == info.PackagePath {
+				return structInfo.Name
+			}
+			return im.Qualify(structInfo.Type.PkgPath, structInfo.Name)
+		},
+	}
+
+	tmpl, err := template.New("converter").Funcs(funcMap).Parse(codeTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData); err != nil {
+		return nil, fmt.Errorf("executing template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// collectPairs returns the conversions to generate: the pairs of the annotations, and the pairs
+// of the struct fields they need, registering the imports of their types in im.
+func collectPairs(ctx context.Context, s *goscan.Scanner, info *model.ParsedInfo, im *goscan.ImportManager) ([]TemplatePair, error) {
 	worklist := make([]model.ConversionPair, 0, len(info.ConversionPairs))
 	processed := make(map[string]bool)
 	allPairs := make([]TemplatePair, 0, len(info.ConversionPairs))
@@ -138,18 +198,25 @@ func Generate(s *goscan.Scanner, info *model.ParsedInfo, header string) ([]byte,
 	for i := 0; i < len(worklist); i++ {
 		pair := worklist[i]
 
-		srcStruct, ok := info.Structs[pair.SrcTypeName]
+		srcStruct, ok := info.LookupStruct(pair.SrcTypeInfo, pair.SrcTypeName)
 		if !ok {
 			// This can happen if a struct is referenced but not defined in the scanned packages.
 			slog.WarnContext(ctx, "source struct for conversion not found in parsed info, skipping", "type", pair.SrcTypeName)
 			continue
 		}
-		dstStruct, ok := info.Structs[pair.DstTypeName]
+		dstStruct, ok := info.LookupStruct(pair.DstTypeInfo, pair.DstTypeName)
 		if !ok {
 			slog.WarnContext(ctx, "destination struct for conversion not found in parsed info, skipping", "type", pair.DstTypeName)
 			continue
 		}
 
+		// Register imports for the types of the pair, which may be in other packages
+		for _, st := range []*model.StructInfo{srcStruct, dstStruct} {
+			if st.Type != nil && st.Type.PkgPath != info.PackagePath {
+				im.Qualify(st.Type.PkgPath, st.Name)
+			}
+		}
+
 		// Register imports for the current pair's types
 		for _, field := range srcStruct.Fields {
 			registerImports(im, field.FieldType)
@@ -163,10 +230,22 @@ func Generate(s *goscan.Scanner, info *model.ParsedInfo, header string) ([]byte,
 			return nil, fmt.Errorf("creating field maps for %s -> %s: %w", srcStruct.Name, dstStruct.Name, err)
 		}
 
-		// Discover new pairs from fields
+		// Discover new pairs from fields, and from the implementations of interface-typed fields,
+		// which are converted by their own converters.
+		var fieldTypePairs [][2]*scanner.FieldType
 		for _, fm := range fieldMaps {
-			srcFieldType := getUnderlyingStructType(fm.SrcFieldT)
-			dstFieldType := getUnderlyingStructType(fm.DstFieldT)
+			fieldTypePairs = append(fieldTypePairs, [2]*scanner.FieldType{fm.SrcFieldT, fm.DstFieldT})
+			if rule := findInterfaceRuleInField(info, fm.SrcFieldT, fm.DstFieldT); rule != nil {
+				for _, impl := range rule.Impls {
+					registerImports(im, impl.SrcType)
+					registerImports(im, impl.DstType)
+					fieldTypePairs = append(fieldTypePairs, [2]*scanner.FieldType{impl.SrcType, impl.DstType})
+				}
+			}
+		}
+		for _, types := range fieldTypePairs {
+			srcFieldType := getUnderlyingStructType(types[0])
+			dstFieldType := getUnderlyingStructType(types[1])
 
 			if srcFieldType != nil && dstFieldType != nil {
 				if srcFieldType.Definition == nil || dstFieldType.Definition == nil {
@@ -197,57 +276,7 @@ func Generate(s *goscan.Scanner, info *model.ParsedInfo, header string) ([]byte,
 			UnmappedFields: unmappedFields,
 		})
 	}
-
-	for _, rule := range info.GlobalRules {
-		if rule.SrcTypeInfo != nil {
-			im.Qualify(rule.SrcTypeInfo.PkgPath, rule.SrcTypeInfo.Name)
-		}
-		if rule.DstTypeInfo != nil {
-			im.Qualify(rule.DstTypeInfo.PkgPath, rule.DstTypeInfo.Name)
-		}
-	}
-
-	templateData := TemplateData{
-		PackageName: info.PackageName,
-		Imports:     im.Imports(),
-		Pairs:       allPairs,
-		Im:          im,
-		Info:        info,
-		Header:      header,
-	}
-
-	funcMap := template.FuncMap{
-		"getAssignment": func(im *goscan.ImportManager, info *model.ParsedInfo, field FieldMap, srcVar, dstVar, ecVar, ctxVar string) string {
-			return getAssignment(im, info, field, srcVar, dstVar, ecVar, ctxVar)
-		},
-		"getMapKeyAssignment": func(im *goscan.ImportManager, info *model.ParsedInfo, srcVar, dstVar string, srcT, dstT *scanner.FieldType, ecVar, ctxVar string) string {
-			return getMapKeyAssignment(im, info, srcVar, dstVar, srcT, dstT, ecVar, ctxVar)
-		},
-		"getValidator": func(im *goscan.ImportManager, info *model.ParsedInfo, field FieldMap, dstVar, ecVar, ctxVar string) string {
-			return getValidator(im, info, field, dstVar, ecVar, ctxVar)
-		},
-		"getQualifiedTypeName": func(im *goscan.ImportManager, structInfo *model.StructInfo) string {
-			if structInfo == nil || structInfo.Type == nil {
-				return "invalid"
-			}
-			// When generating code for a specific package, types within that package don't need qualification.
-			if structInfo.Type.PkgPath == info.PackagePath {
-				return structInfo.Name
-			}
-			return im.Qualify(structInfo.Type.PkgPath, structInfo.Name)
-		},
-	}
-
-	tmpl, err := template.New("converter").Funcs(funcMap).Parse(codeTemplate)
-	if err != nil {
-		return nil, fmt.Errorf("parsing template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, templateData); err != nil {
-		return nil, fmt.Errorf("executing template: %w", err)
-	}
-	return buf.Bytes(), nil
+	return allPairs, nil
 }
 
 func createFieldMaps(ctx context.Context, s *goscan.Scanner, src, dst *model.StructInfo, pair *model.ConversionPair) ([]FieldMap, []string, error) {
@@ -255,6 +284,7 @@ func createFieldMaps(ctx context.Context, s *goscan.Scanner, src, dst *model.Str
 	dstFieldsByName := make(map[string]model.FieldInfo)
 	dstFieldsByNormalizedJSONTag := make(map[string]model.FieldInfo)
 	dstFieldsByNormalizedName := make(map[string]model.FieldInfo)
+	dstFieldsByMatchKey := make(map[string]model.FieldInfo)
 	unmappedDstFields := make(map[string]bool)
 
 	slog.DebugContext(ctx, "Destination struct", "name", dst.Name)
@@ -268,6 +298,15 @@ func createFieldMaps(ctx context.Context, s *goscan.Scanner, src, dst *model.Str
 		normalized := normalizeFieldName(f.Name)
 		dstFieldsByNormalizedName[normalized] = f
 		slog.DebugContext(ctx, "dst field", "name", f.Name, "normalized_name", normalized)
+		if pair.Match != "" {
+			key := matchKey(pair.Match, f)
+			if prev, dup := dstFieldsByMatchKey[key]; dup {
+				// e.g. "ID" and "Id" with case-insensitive; the first one is matched.
+				slog.WarnContext(ctx, "dst fields with the same match key", "struct", dst.Name, "key", key, "matched", prev.Name, "ignored", f.Name)
+			} else {
+				dstFieldsByMatchKey[key] = f
+			}
+		}
 		unmappedDstFields[f.Name] = true
 	}
 
@@ -294,15 +333,22 @@ func createFieldMaps(ctx context.Context, s *goscan.Scanner, src, dst *model.Str
 			reason = fmt.Sprintf("`convert` tag (%s)", srcField.Tag.DstFieldName)
 		}
 
-		// Priority 2: Normalized `json` tag
-		if !ok && srcField.JSONTag != "" && srcField.JSONTag != "-" {
+		// Priority 2: the matching strategy of the pair, if any
+		if !ok && pair.Match != "" {
+			key := matchKey(pair.Match, srcField)
+			dstField, ok = dstFieldsByMatchKey[key]
+			reason = fmt.Sprintf("%s match (%s)", pair.Match, key)
+		}
+
+		// Priority 3: Normalized `json` tag
+		if !ok && pair.Match == "" && srcField.JSONTag != "" && srcField.JSONTag != "-" {
 			normalizedJSONTag := normalizeFieldName(srcField.JSONTag)
 			dstField, ok = dstFieldsByNormalizedJSONTag[normalizedJSONTag]
 			reason = fmt.Sprintf("normalized `json` tag (%s -> %s)", srcField.JSONTag, normalizedJSONTag)
 		}
 
-		// Priority 3: Normalized field name
-		if !ok {
+		// Priority 4: Normalized field name
+		if !ok && pair.Match == "" {
 			normalizedSrcName := normalizeFieldName(srcField.Name)
 			dstField, ok = dstFieldsByNormalizedName[normalizedSrcName]
 			reason = fmt.Sprintf("normalized name (%s -> %s)", srcField.Name, normalizedSrcName)
@@ -340,6 +386,23 @@ func normalizeFieldName(name string) string {
 	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
 }
 
+// matchKey returns the key of a field for a matching strategy (see model.MatchExact); the
+// fields with the same key match.
+func matchKey(strategy string, f model.FieldInfo) string {
+	switch strategy {
+	case model.MatchCaseInsensitive:
+		return strings.ToLower(f.Name)
+	case model.MatchNormalized:
+		return normalizeFieldName(f.Name)
+	}
+	if tagName, ok := strings.CutPrefix(strategy, model.MatchTagPrefix); ok {
+		if name, _, _ := strings.Cut(f.StructTag.Get(tagName), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
 func resolveFieldType(ctx context.Context, s *goscan.Scanner, ft *scanner.FieldType) error {
 	if ft == nil {
 		return nil
@@ -348,6 +411,11 @@ func resolveFieldType(ctx context.Context, s *goscan.Scanner, ft *scanner.FieldT
 		// Non-fatal, just log it. The type might not be resolvable in this context.
 		slog.DebugContext(ctx, "could not resolve field type", "type", ft.Name, "error", err.Error())
 	}
+	if def := ft.Definition; def != nil && def.IsAlias && def.AliasTarget != nil {
+		if err := resolveFieldType(ctx, s, def.AliasTarget); err != nil {
+			return fmt.Errorf("resolving alias target: %w", err)
+		}
+	}
 	if ft.Elem != nil {
 		if err := resolveFieldType(ctx, s, ft.Elem); err != nil {
 			return fmt.Errorf("resolving element type: %w", err)
@@ -429,6 +497,17 @@ func findMatchingRule(info *model.ParsedInfo, srcT, dstT *scanner.FieldType) *ru
 		return nil
 	}
 
+	if match := findMatchingRuleByName(info, srcT, dstT); match != nil {
+		return match
+	}
+	// A rule declared for a type also applies to its aliases (`type A = B`).
+	if unaliasedSrcT, unaliasedDstT := unaliasFieldType(srcT), unaliasFieldType(dstT); unaliasedSrcT != srcT || unaliasedDstT != dstT {
+		return findMatchingRuleByName(info, unaliasedSrcT, unaliasedDstT)
+	}
+	return nil
+}
+
+func findMatchingRuleByName(info *model.ParsedInfo, srcT, dstT *scanner.FieldType) *ruleMatchResult {
 	srcFieldTypeName := getFullTypeNameFromFieldType(srcT)
 	dstFieldTypeName := getFullTypeNameFromFieldType(dstT)
 
@@ -449,6 +528,77 @@ func findMatchingRule(info *model.ParsedInfo, srcT, dstT *scanner.FieldType) *ru
 	return nil
 }
 
+// unaliasFieldType returns the aliased type if t refers to an alias declaration (`type A = B`).
+// Pointers to aliases are unaliased element-wise, e.g. `*A` becomes `*B`.
+func unaliasFieldType(t *scanner.FieldType) *scanner.FieldType {
+	if t == nil {
+		return nil
+	}
+	if t.IsPointer && t.Elem != nil {
+		elem := unaliasFieldType(t.Elem)
+		if elem == t.Elem {
+			return t
+		}
+		ptr := *t
+		ptr.Elem = elem
+		ptr.Definition = elem.Definition
+		return &ptr
+	}
+	if t.Definition == nil || !t.Definition.IsAlias {
+		return t
+	}
+	// Only the targets resolved beforehand are followed, so nothing is scanned here.
+	def, via, err := t.Definition.ResolveAliasFunc(context.Background(), func(target *scanner.FieldType) bool {
+		return target.Definition != nil
+	})
+	switch {
+	case err != nil:
+		return t
+	case def.IsAlias:
+		// The chain ends in a type literal or an unresolved type, named by the last alias.
+		return def.AliasTarget
+	default:
+		return via
+	}
+}
+
+// findInterfaceRule returns the `// convert:impl` rule for converting a value of the interface type
+// srcT to dstT, or nil.
+func findInterfaceRule(info *model.ParsedInfo, srcT, dstT *scanner.FieldType) *model.InterfaceRule {
+	if srcT == nil || dstT == nil || srcT.IsPointer || srcT.IsSlice || srcT.IsMap {
+		return nil
+	}
+	srcT = unaliasFieldType(srcT)
+	if srcT.Definition == nil || srcT.Definition.Kind != scanner.InterfaceKind {
+		return nil
+	}
+	srcName := getFullTypeNameFromFieldType(srcT)
+	dstName := getFullTypeNameFromFieldType(unaliasFieldType(dstT))
+	for i := range info.InterfaceRules {
+		rule := &info.InterfaceRules[i]
+		ruleDstName := getFullTypeNameFromTypeInfo(rule.DstTypeInfo)
+		if strings.HasPrefix(rule.DstTypeName, "*") {
+			ruleDstName = "*" + ruleDstName
+		}
+		if getFullTypeNameFromTypeInfo(rule.SrcTypeInfo) == srcName && ruleDstName == dstName {
+			return rule
+		}
+	}
+	return nil
+}
+
+// findInterfaceRuleInField is findInterfaceRule for the element types of slices, maps and pointers.
+func findInterfaceRuleInField(info *model.ParsedInfo, srcT, dstT *scanner.FieldType) *model.InterfaceRule {
+	srcT, dstT = unaliasFieldType(srcT), unaliasFieldType(dstT)
+	if srcT == nil || dstT == nil {
+		return nil
+	}
+	if (srcT.IsSlice && dstT.IsSlice) || (srcT.IsMap && dstT.IsMap) || (srcT.IsPointer && dstT.IsPointer) {
+		return findInterfaceRuleInField(info, srcT.Elem, dstT.Elem)
+	}
+	return findInterfaceRule(info, srcT, dstT)
+}
+
 func getMapKeyAssignment(im *goscan.ImportManager, info *model.ParsedInfo, srcVar, dstVar string, srcT, dstT *scanner.FieldType, ecVar, ctxVar string) string {
 	// Global conversion rule
 	if match := findMatchingRule(info, srcT, dstT); match != nil {
@@ -501,6 +651,13 @@ func generateConversion(im *goscan.ImportManager, info *model.ParsedInfo, src, d
 	if srcT == nil || dstT == nil {
 		return fmt.Sprintf("// srcT or dstT is nil for %s -> %s", src, dst)
 	}
+	// An alias denotes the same type as its target, so convert as if the target was written.
+	srcT, dstT = unaliasFieldType(srcT), unaliasFieldType(dstT)
+
+	// Interface with registered implementations
+	if rule := findInterfaceRule(info, srcT, dstT); rule != nil {
+		return generateInterfaceConversion(im, info, rule, src, dst, dstT, depth, ecVar, ctxVar)
+	}
 
 	// Pointer to Pointer
 	if srcT.IsPointer && dstT.IsPointer {
@@ -514,7 +671,11 @@ func generateConversion(im *goscan.ImportManager, info *model.ParsedInfo, src, d
 
 		// If the elements are structs that have a dedicated converter, use it directly.
 		if isStruct(srcT.Elem) && isStruct(dstT.Elem) {
-			return fmt.Sprintf("convert%sTo%s(%s, %s, %s)", srcT.Elem.Name, dstT.Elem.Name, ctxVar, ecVar, src)
+			conversion := fmt.Sprintf("convert%sTo%s(%s, %s, %s)", srcT.Elem.Name, dstT.Elem.Name, ctxVar, ecVar, src)
+			if dst != "" {
+				return fmt.Sprintf("%s = %s", dst, conversion)
+			}
+			return conversion
 		}
 
 		var b strings.Builder
@@ -590,6 +751,48 @@ func generateConversion(im *goscan.ImportManager, info *model.ParsedInfo, src, d
 	return src
 }
 
+// generateInterfaceConversion converts an interface value with a type switch over the registered
+// implementations of the rule, each one converted as a value of its concrete type.
+func generateInterfaceConversion(im *goscan.ImportManager, info *model.ParsedInfo, rule *model.InterfaceRule, src, dst string, dstT *scanner.FieldType, depth int, ecVar, ctxVar string) string {
+	target := dst
+	var b strings.Builder
+	if dst == "" {
+		// If dst is empty, we must generate an expression, which we do with an anonymous func.
+		target = "converted"
+		b.WriteString(fmt.Sprintf("func() %s {\n", getTypeName(im, dstT)))
+		b.WriteString(fmt.Sprintf("var %s %s\n", target, getTypeName(im, dstT)))
+	}
+
+	// The zero fallback does not use the value, so it is not bound if there are no implementations.
+	if len(rule.Impls) == 0 && rule.Fallback == "zero" {
+		b.WriteString(fmt.Sprintf("switch %s.(type) {\n", src))
+	} else {
+		b.WriteString(fmt.Sprintf("switch v := %s.(type) {\n", src))
+	}
+	b.WriteString("case nil:\n")
+	for _, impl := range rule.Impls {
+		b.WriteString(fmt.Sprintf("case %s:\n", getTypeName(im, impl.SrcType)))
+		b.WriteString(fmt.Sprintf("\t%s\n", generateConversion(im, info, "v", target, impl.SrcType, impl.DstType, depth+1, ecVar, ctxVar)))
+	}
+	b.WriteString("default:\n")
+	switch rule.Fallback {
+	case "", "error":
+		b.WriteString(fmt.Sprintf("\t%s.Add(fmt.Errorf(\"unsupported implementation %%T of %s\", v))\n", ecVar, rule.SrcTypeName))
+	case "zero":
+		b.WriteString("\t// the zero value is kept for the other implementations\n")
+	default:
+		funcName := qualifyFunc(im, info, rule.Fallback)
+		b.WriteString(fmt.Sprintf("\t%s = %s(%s, %s, v)\n", target, funcName, ctxVar, ecVar))
+	}
+	b.WriteString("}")
+
+	if dst == "" {
+		b.WriteString(fmt.Sprintf("\nreturn %s\n", target))
+		b.WriteString("}()")
+	}
+	return b.String()
+}
+
 func generateSliceConversion(im *goscan.ImportManager, info *model.ParsedInfo, src, dst string, srcT, dstT *scanner.FieldType, depth int, ecVar, ctxVar string) string {
 	if srcT.Elem == nil || dstT.Elem == nil {
 		return ""
@@ -696,6 +899,7 @@ func getTypeName(im *goscan.ImportManager, t *scanner.FieldType) string {
 }
 
 func isStruct(t *scanner.FieldType) bool {
+	t = unaliasFieldType(t)
 	if t == nil {
 		return false
 	}
@@ -709,6 +913,7 @@ func isStruct(t *scanner.FieldType) bool {
 }
 
 func getUnderlyingStructType(t *scanner.FieldType) *scanner.FieldType {
+	t = unaliasFieldType(t)
 	if t == nil {
 		return nil
 	}
@@ -0,0 +1,268 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"strings"
+	"text/template"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/examples/convert/model"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// testsTemplate is the template of the _test.go file generated for the conversions annotated
+// with `tests=true`.
+const testsTemplate = `
+// Code generated by convert. DO NOT EDIT.
+{{ .Header -}}
+package {{ .PackageName }}
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	{{- range $path, $alias := .Imports }}
+	{{ $alias }} "{{ $path }}"
+	{{- end }}
+)
+
+{{ range .Pairs -}}
+{{ if .DstFieldTypes -}}
+// The unkeyed literal stops compiling when a field is added to {{ .DstType }}, so that the
+// converter is regenerated to assign it.
+var _ = {{ .DstType }}{ {{- join .DstFieldTypes ", " -}} }
+
+{{ end -}}
+// new{{ .SrcName }}ForTest returns a {{ .SrcType }} with the fields of basic types filled from seed.
+func new{{ .SrcName }}ForTest(seed int) *{{ .SrcType }} {
+	return &{{ .SrcType }}{
+		{{- range .Fill }}
+		{{ .Name }}: {{ .Expr }},
+		{{- end }}
+	}
+}
+
+func TestConvert{{ .SrcName }}To{{ .DstName }}(t *testing.T) {
+	ctx := context.Background()
+	for seed := 1; seed <= 3; seed++ {
+		src := new{{ .SrcName }}ForTest(seed)
+		// The errors for the fields which are not filled, e.g. the required pointers, are ignored.
+		dst, _ := Convert{{ .SrcName }}To{{ .DstName }}(ctx, src)
+		if dst == nil {
+			t.Fatalf("seed=%d: Convert{{ .SrcName }}To{{ .DstName }} returned nil", seed)
+		}
+		{{- range .Assigned }}
+		if dst.{{ .DstName }} != src.{{ .SrcName }} {
+			t.Errorf("seed=%d: {{ .DstName }} is not assigned from {{ .SrcName }}: got %v, want %v", seed, dst.{{ .DstName }}, src.{{ .SrcName }})
+		}
+		{{- end }}
+	}
+}
+{{ if .RoundTrip }}
+func TestRoundTrip{{ .SrcName }}{{ .DstName }}(t *testing.T) {
+	ctx := context.Background()
+	for seed := 1; seed <= 3; seed++ {
+		src := new{{ .SrcName }}ForTest(seed)
+		dst, _ := Convert{{ .SrcName }}To{{ .DstName }}(ctx, src)
+		got, _ := Convert{{ .DstName }}To{{ .SrcName }}(ctx, dst)
+		if got == nil {
+			t.Fatalf("seed=%d: Convert{{ .DstName }}To{{ .SrcName }} returned nil", seed)
+		}
+		{{- range .RoundTrip }}
+		if got.{{ . }} != src.{{ . }} {
+			t.Errorf("seed=%d: {{ . }} changed in the round trip: got %v, want %v", seed, got.{{ . }}, src.{{ . }})
+		}
+		{{- end }}
+	}
+}
+{{ end }}
+{{ end -}}
+`
+
+// TestsTemplateData is the data of testsTemplate.
+type TestsTemplateData struct {
+	PackageName string
+	Imports     map[string]string
+	Pairs       []TestPair
+	Header      string
+}
+
+// TestPair holds the tests of the converter of a pair.
+type TestPair struct {
+	SrcType string // qualified
+	DstType string // qualified
+	SrcName string
+	DstName string
+	Fill    []FillField
+	// Assigned are the fields assigned as is, checked to be equal after the conversion.
+	Assigned []FieldMap
+	// RoundTrip are the source fields assigned as is by the converter and by the converter of
+	// the reverse pair, checked to be unchanged by the round trip. Empty without a reverse pair.
+	RoundTrip []string
+	// DstFieldTypes are the types of the fields of the destination, in the order of declaration,
+	// for the unkeyed literal. It is empty if the literal could not be written, e.g. for the
+	// unexported fields of a type of another package.
+	DstFieldTypes []string
+}
+
+// FillField is a field of a source value filled by the tests, Expr being an expression of the
+// int variable seed.
+type FillField struct {
+	Name string
+	Expr string
+}
+
+// GenerateTests generates the _test.go file of the conversions annotated with `tests=true`:
+// for each, a test that the fields of basic types are assigned, a round-trip test if the reverse
+// conversion is generated too, and a compile-time assertion on the fields of the destination.
+// It returns nil if no conversion is annotated so.
+func GenerateTests(s *goscan.Scanner, info *model.ParsedInfo, header string) ([]byte, error) {
+	wanted := false
+	for _, pair := range info.ConversionPairs {
+		wanted = wanted || pair.Tests
+	}
+	if !wanted {
+		return nil, nil
+	}
+
+	im := goscan.NewImportManager(&scanner.PackageInfo{ImportPath: info.PackagePath, Name: info.PackageName})
+	ctx := context.Background()
+	for alias, path := range info.Imports {
+		im.Add(path, alias)
+	}
+	allPairs, err := collectPairs(ctx, s, info, im)
+	if err != nil {
+		return nil, err
+	}
+
+	qualify := func(st *model.StructInfo) string {
+		if st.Type.PkgPath == info.PackagePath {
+			return st.Name
+		}
+		return im.Qualify(st.Type.PkgPath, st.Name)
+	}
+
+	var pairs []TestPair
+	for _, p := range allPairs {
+		if !p.Pair.Tests {
+			continue
+		}
+		tp := TestPair{
+			SrcType:       qualify(p.SrcType),
+			DstType:       qualify(p.DstType),
+			SrcName:       p.SrcType.Name,
+			DstName:       p.DstType.Name,
+			Assigned:      assignedAsIs(info, p),
+			DstFieldTypes: declaredFieldTypes(im, info, p.DstType),
+		}
+		srcOtherPkg := p.SrcType.Type.PkgPath != info.PackagePath
+		for _, f := range p.SrcType.Fields {
+			if srcOtherPkg && !ast.IsExported(f.Name) {
+				continue
+			}
+			if expr, ok := fillExpr(f.Name, f.FieldType); ok {
+				tp.Fill = append(tp.Fill, FillField{Name: f.Name, Expr: expr})
+			}
+		}
+		if reverse := findReversePair(allPairs, p); reverse != nil {
+			back := make(map[string]string) // dst field -> src field, assigned as is
+			for _, fm := range assignedAsIs(info, *reverse) {
+				back[fm.SrcName] = fm.DstName
+			}
+			for _, fm := range tp.Assigned {
+				if back[fm.DstName] == fm.SrcName {
+					tp.RoundTrip = append(tp.RoundTrip, fm.SrcName)
+				}
+			}
+		}
+		pairs = append(pairs, tp)
+	}
+
+	data := TestsTemplateData{
+		PackageName: info.PackageName,
+		Imports:     im.Imports(),
+		Pairs:       pairs,
+		Header:      header,
+	}
+	tmpl, err := template.New("tests").Funcs(template.FuncMap{"join": strings.Join}).Parse(testsTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing tests template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("executing tests template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// assignedAsIs returns the fields of the pair of the same basic type on both sides, assigned
+// without a conversion function and not overwritten by a computed field.
+func assignedAsIs(info *model.ParsedInfo, p TemplatePair) []FieldMap {
+	computed := make(map[string]bool)
+	for _, c := range p.Pair.Computed {
+		computed[c.DstName] = true
+	}
+	var fields []FieldMap
+	for _, fm := range p.Fields {
+		if fm.Tag.UsingFunc != "" || computed[fm.DstName] || findMatchingRule(info, fm.SrcFieldT, fm.DstFieldT) != nil {
+			continue
+		}
+		if _, ok := fillExpr(fm.SrcName, fm.SrcFieldT); ok && fm.DstFieldT != nil && fm.DstFieldT.IsBuiltin && fm.DstFieldT.Name == fm.SrcFieldT.Name {
+			fields = append(fields, fm)
+		}
+	}
+	return fields
+}
+
+// findReversePair returns the pair converting the destination of p back to its source, or nil.
+func findReversePair(pairs []TemplatePair, p TemplatePair) *TemplatePair {
+	for i := range pairs {
+		if pairs[i].SrcType.Type == p.DstType.Type && pairs[i].DstType.Type == p.SrcType.Type {
+			return &pairs[i]
+		}
+	}
+	return nil
+}
+
+// fillExpr returns the expression filling a field of a basic type from seed, non-zero and
+// different for each seed.
+func fillExpr(name string, ft *scanner.FieldType) (string, bool) {
+	if ft == nil || !ft.IsBuiltin || ft.IsPointer || ft.IsSlice || ft.IsMap {
+		return "", false
+	}
+	switch ft.Name {
+	case "string":
+		return fmt.Sprintf("fmt.Sprintf(%q, seed)", name+"-%d"), true
+	case "bool":
+		return "true", true
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune":
+		return fmt.Sprintf("%s(seed)", ft.Name), true
+	case "float32", "float64":
+		return fmt.Sprintf("%s(seed) + 0.5", ft.Name), true
+	}
+	return "", false
+}
+
+// declaredFieldTypes returns the types of the fields of the struct as declared, or nil if an
+// unkeyed literal of the struct cannot be written in the package being generated.
+func declaredFieldTypes(im *goscan.ImportManager, info *model.ParsedInfo, st *model.StructInfo) []string {
+	if st.Type == nil || st.Type.Struct == nil || len(st.Type.Struct.Fields) == 0 {
+		return nil
+	}
+	otherPkg := st.Type.PkgPath != info.PackagePath
+	var types []string
+	for _, f := range st.Type.Struct.Fields {
+		if otherPkg && !f.IsExported {
+			return nil
+		}
+		ft := f.Type
+		if ft == nil || ft.IsChan || strings.ContainsAny(ft.Name, "{([") || (ft.Elem != nil && strings.ContainsAny(ft.Elem.Name, "{([")) {
+			return nil // e.g. anonymous structs, funcs and arrays
+		}
+		types = append(types, fmt.Sprintf("*new(%s)", getTypeName(im, ft)))
+	}
+	return types
+}
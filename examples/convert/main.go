@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
 	goscan "github.com/podhmo/go-scan"
 	"github.com/podhmo/go-scan/examples/convert/generator"
@@ -120,6 +121,29 @@ func run(ctx context.Context, pkgpath, workdir, output, pkgname, outputPkgPath s
 		return fmt.Errorf("failed to generate code: %w", err)
 	}
 
+	if err := writeOutput(ctx, s, output, generatedCode); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "Successfully generated conversion functions", "output", output)
+
+	// The tests of the conversions annotated with `tests=true`, next to the generated file.
+	testsCode, err := generator.GenerateTests(s, info, header)
+	if err != nil {
+		return fmt.Errorf("failed to generate tests: %w", err)
+	}
+	if testsCode != nil {
+		testsOutput := strings.TrimSuffix(output, ".go") + "_test.go"
+		if err := writeOutput(ctx, s, testsOutput, testsCode); err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "Successfully generated tests of conversion functions", "output", testsOutput)
+	}
+	return nil
+}
+
+// writeOutput formats the generated code and writes it with the FileWriter of the context, or
+// prints it on a dry run.
+func writeOutput(ctx context.Context, s *goscan.Scanner, output string, generatedCode []byte) error {
 	slog.DebugContext(ctx, "Writing output", "file", output)
 
 	formatted, err := formatCode(ctx, output, generatedCode)
@@ -133,17 +157,15 @@ func run(ctx context.Context, pkgpath, workdir, output, pkgname, outputPkgPath s
 		slog.InfoContext(ctx, "Dry run: skipping file write", "path", output)
 		fmt.Fprintf(os.Stdout, "---\n// file: %s\n---\n", output)
 		os.Stdout.Write(formatted)
-	} else {
-		writer, ok := ctx.Value(FileWriterKey).(FileWriter)
-		if !ok {
-			return fmt.Errorf("file writer not found in context")
-		}
-		if err := writer.WriteFile(ctx, output, formatted, 0644); err != nil {
-			return fmt.Errorf("failed to write formatted code to %s: %w", output, err)
-		}
+		return nil
+	}
+	writer, ok := ctx.Value(FileWriterKey).(FileWriter)
+	if !ok {
+		return fmt.Errorf("file writer not found in context")
+	}
+	if err := writer.WriteFile(ctx, output, formatted, 0644); err != nil {
+		return fmt.Errorf("failed to write formatted code to %s: %w", output, err)
 	}
-
-	slog.InfoContext(ctx, "Successfully generated conversion functions", "output", output)
 	return nil
 }
 
@@ -1068,6 +1068,120 @@ type Dst struct {
 	}
 }
 
+func TestIntegration_WithMatchStrategies(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/m\ngo 1.24",
+		"matching.go": `
+package matching
+
+// @derivingconvert("ExactDst", match=exact)
+type ExactSrc struct {
+	ID        string
+	Url       string // not URL
+	User_Name string // not UserName
+}
+
+type ExactDst struct {
+	ID       string
+	URL      string
+	UserName string
+}
+
+// @derivingconvert("CaseDst", match=case-insensitive)
+type CaseSrc struct {
+	Url       string
+	User_Name string // not UserName
+}
+
+type CaseDst struct {
+	URL      string
+	UserName string
+}
+
+// @derivingconvert("NormalizedDst", match=normalized)
+type NormalizedSrc struct {
+	User_Name string
+	Nickname  string ` + "`convert:\"Alias\"`" + `
+}
+
+type NormalizedDst struct {
+	UserName string
+	Alias    string
+}
+
+// @derivingconvert("TagDst", match=tag:db)
+type TagSrc struct {
+	UserID string ` + "`db:\"user_id\" json:\"id\"`" + `
+	Note   string
+}
+
+type TagDst struct {
+	Ident string ` + "`db:\"user_id\"`" + `
+	ID    string ` + "`json:\"id\"`" + `
+	Note  string
+}
+`,
+	}
+
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := &memoryFileWriter{}
+	ctx = context.WithValue(ctx, FileWriterKey, writer)
+
+	outputFile := "generated.go"
+	goldenFile := "testdata/matchstrategies.go.golden"
+
+	if err := run(ctx, "example.com/m", tmpdir, outputFile, "matching", "", false, false, nil, ""); err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+
+	generatedCode, ok := writer.Outputs[outputFile]
+	if !ok {
+		t.Fatalf("output file %q not found in captured outputs", outputFile)
+	}
+
+	if *update {
+		if err := os.WriteFile(goldenFile, generatedCode, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		t.Logf("golden file updated: %s", goldenFile)
+		return
+	}
+
+	golden, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if diff := cmp.Diff(string(golden), string(generatedCode)); diff != "" {
+		t.Errorf("generated code mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIntegration_WithUnknownMatchStrategy(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/m\ngo 1.24",
+		"matching.go": `
+package matching
+
+// @derivingconvert("Dst", match=fuzzy)
+type Src struct{ Name string }
+
+type Dst struct{ Name string }
+`,
+	}
+
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	ctx := context.WithValue(context.Background(), FileWriterKey, &memoryFileWriter{})
+	err := run(ctx, "example.com/m", tmpdir, "generated.go", "matching", "", false, false, nil, "")
+	if err == nil || !strings.Contains(err.Error(), `unknown match strategy "fuzzy"`) {
+		t.Errorf("run() = %v, want an unknown match strategy error", err)
+	}
+}
+
 func TestIntegration_WithErrorHandling(t *testing.T) {
 	files := map[string]string{
 		"go.mod": `
@@ -1302,6 +1416,414 @@ func overrideTime(ctx context.Context, ec *model.ErrorCollector, t time.Time) st
 	}
 }
 
+func TestIntegration_WithRulesFile(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/m\ngo 1.24",
+		"convert.rules.go": `//go:build ignore
+
+package rules
+
+import "time"
+
+// convert:import convutil "example.com/m/convutil"
+// convert:rule "time.Time" -> "string", using=convutil.TimeToString
+// convert:rule "string" -> "time.Time", using=convutil.StringToTime
+`,
+		"convutil/convutil.go": `
+package convutil
+import (
+	"context"
+	"time"
+	"github.com/podhmo/go-scan/examples/convert/model"
+)
+
+func TimeToString(ctx context.Context, ec *model.ErrorCollector, t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+func StringToTime(ctx context.Context, ec *model.ErrorCollector, s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		ec.Add(err)
+	}
+	return t
+}
+`,
+		"a/a.go": `
+package a
+import "time"
+
+// @derivingconvert("Dst")
+type Src struct {
+	CreatedAt time.Time
+}
+
+// @derivingconvert("Src")
+type Dst struct {
+	CreatedAt string
+}
+`,
+		"b/b.go": `
+package b
+import (
+	"context"
+	"time"
+	"github.com/podhmo/go-scan/examples/convert/model"
+)
+
+// convert:rule "time.Time" -> "string", using=dateOnly
+
+// @derivingconvert("Dst")
+type Src struct {
+	UpdatedAt time.Time
+}
+
+type Dst struct {
+	UpdatedAt string
+}
+
+func dateOnly(ctx context.Context, ec *model.ErrorCollector, t time.Time) string {
+	return t.Format(time.DateOnly)
+}
+`,
+	}
+
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	generate := func(t *testing.T, pkgpath, pkgname string) []byte {
+		t.Helper()
+		ctx := context.Background()
+		writer := &memoryFileWriter{}
+		ctx = context.WithValue(ctx, FileWriterKey, writer)
+
+		outputFile := "generated.go"
+		if err := run(ctx, pkgpath, tmpdir, outputFile, pkgname, "", false, false, nil, ""); err != nil {
+			t.Fatalf("run() failed: %v", err)
+		}
+		generatedCode, ok := writer.Outputs[outputFile]
+		if !ok {
+			t.Fatalf("output file %q not found in captured outputs", outputFile)
+		}
+		return generatedCode
+	}
+
+	t.Run("rules are shared by packages", func(t *testing.T) {
+		generatedCode := generate(t, "example.com/m/a", "a")
+		goldenFile := "testdata/rulesfile.go.golden"
+
+		if *update {
+			if err := os.WriteFile(goldenFile, generatedCode, 0644); err != nil {
+				t.Fatalf("failed to update golden file: %v", err)
+			}
+			t.Logf("golden file updated: %s", goldenFile)
+			return
+		}
+
+		golden, err := os.ReadFile(goldenFile)
+		if err != nil {
+			t.Fatalf("failed to read golden file: %v", err)
+		}
+		if diff := cmp.Diff(string(golden), string(generatedCode)); diff != "" {
+			t.Errorf("generated code mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("package rules take precedence", func(t *testing.T) {
+		generatedCode := string(generate(t, "example.com/m/b", "b"))
+		if want := "dst.UpdatedAt = dateOnly(ctx, ec, src.UpdatedAt)"; !strings.Contains(generatedCode, want) {
+			t.Errorf("generated code does not contain %q:\n%s", want, generatedCode)
+		}
+		if strings.Contains(generatedCode, "convutil.") {
+			t.Errorf("generated code uses the shared rule instead of the package rule:\n%s", generatedCode)
+		}
+	})
+}
+
+func TestIntegration_WithAliases(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/m\ngo 1.24",
+		"aliases.go": `
+package aliases
+import (
+	"context"
+	"time"
+
+	"github.com/podhmo/go-scan/examples/convert/model"
+)
+
+// convert:rule "time.Time" -> "string", using=convertTimeToString
+
+type Timestamp = time.Time
+
+type Label = string
+
+type SrcAddress struct {
+	Street string
+}
+
+type DstAddress struct {
+	Street string
+}
+
+type AddressAlias = SrcAddress
+
+type ChainedAddressAlias = AddressAlias
+
+// @derivingconvert("Dst")
+type Src struct {
+	CreatedAt Timestamp
+	Name      Label
+	Home      AddressAlias
+	Work      *ChainedAddressAlias
+}
+
+type Dst struct {
+	CreatedAt string
+	Name      string
+	Home      DstAddress
+	Work      *DstAddress
+}
+
+func convertTimeToString(ctx context.Context, ec *model.ErrorCollector, t time.Time) string {
+	return t.Format("2006-01-02")
+}
+`,
+	}
+
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := &memoryFileWriter{}
+	ctx = context.WithValue(ctx, FileWriterKey, writer)
+
+	pkgpath := "example.com/m"
+	outputFile := "generated.go"
+	pkgname := "aliases"
+	goldenFile := "testdata/aliases.go.golden"
+
+	err := run(ctx, pkgpath, tmpdir, outputFile, pkgname, "", false, false, nil, "")
+	if err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+
+	generatedCode, ok := writer.Outputs[outputFile]
+	if !ok {
+		t.Fatalf("output file %q not found in captured outputs", outputFile)
+	}
+
+	if *update {
+		if err := os.WriteFile(goldenFile, generatedCode, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		t.Logf("golden file updated: %s", goldenFile)
+		return
+	}
+
+	golden, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	formattedGenerated, err := imports.Process(outputFile, generatedCode, nil)
+	if err != nil {
+		t.Fatalf("failed to format generated code: %v\n---\n%s", err, string(generatedCode))
+	}
+	formattedGolden, err := imports.Process(goldenFile, golden, nil)
+	if err != nil {
+		t.Fatalf("failed to format golden file: %v", err)
+	}
+
+	if diff := cmp.Diff(string(formattedGolden), string(formattedGenerated)); diff != "" {
+		t.Errorf("generated code mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIntegration_WithPointerStructFields(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/m\ngo 1.24",
+		"pointerfields.go": `
+package pointerfields
+
+type SrcAddress struct {
+	Street string
+}
+
+type DstAddress struct {
+	Street string
+}
+
+// @derivingconvert("Dst")
+type Src struct {
+	Home *SrcAddress
+}
+
+type Dst struct {
+	Home *DstAddress
+}
+`,
+	}
+
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := &memoryFileWriter{}
+	ctx = context.WithValue(ctx, FileWriterKey, writer)
+
+	pkgpath := "example.com/m"
+	outputFile := "generated.go"
+	pkgname := "pointerfields"
+	goldenFile := "testdata/pointerfields.go.golden"
+
+	err := run(ctx, pkgpath, tmpdir, outputFile, pkgname, "", false, false, nil, "")
+	if err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+
+	generatedCode, ok := writer.Outputs[outputFile]
+	if !ok {
+		t.Fatalf("output file %q not found in captured outputs", outputFile)
+	}
+
+	if *update {
+		if err := os.WriteFile(goldenFile, generatedCode, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		t.Logf("golden file updated: %s", goldenFile)
+		return
+	}
+
+	golden, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	formattedGenerated, err := imports.Process(outputFile, generatedCode, nil)
+	if err != nil {
+		t.Fatalf("failed to format generated code: %v\n---\n%s", err, string(generatedCode))
+	}
+	formattedGolden, err := imports.Process(goldenFile, golden, nil)
+	if err != nil {
+		t.Fatalf("failed to format golden file: %v", err)
+	}
+
+	if diff := cmp.Diff(string(formattedGolden), string(formattedGenerated)); diff != "" {
+		t.Errorf("generated code mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIntegration_WithInterfaceImpls(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/m\ngo 1.24",
+		"shapes.go": `
+package shapes
+import (
+	"context"
+	"fmt"
+
+	"github.com/podhmo/go-scan/examples/convert/model"
+)
+
+// convert:impl "Shape" -> "ShapeDTO", "*Circle" -> "*CircleDTO"
+// convert:impl "Shape" -> "ShapeDTO", "Square" -> "*SquareDTO"
+// convert:impl "Shape" -> "CircleDTO", "*Circle" -> "CircleDTO"
+// convert:impl "Shape" -> "CircleDTO", fallback=zero
+// convert:impl "Shape" -> "string", fallback=describeShape
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Radius float64
+}
+
+func (c *Circle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type Square struct {
+	Side float64
+}
+
+func (s Square) Area() float64 { return s.Side * s.Side }
+
+type ShapeDTO interface {
+	isShape()
+}
+
+type CircleDTO struct {
+	Radius float64
+}
+
+func (*CircleDTO) isShape() {}
+
+type SquareDTO struct {
+	Side float64
+}
+
+func (*SquareDTO) isShape() {}
+
+// @derivingconvert("Dst")
+type Src struct {
+	Main    Shape
+	Shapes  []Shape
+	Primary Shape
+	Label   Shape
+}
+
+type Dst struct {
+	Main    ShapeDTO
+	Shapes  []ShapeDTO
+	Primary CircleDTO
+	Label   string
+}
+
+func describeShape(ctx context.Context, ec *model.ErrorCollector, s Shape) string {
+	return fmt.Sprintf("%T", s)
+}
+`,
+	}
+
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := &memoryFileWriter{}
+	ctx = context.WithValue(ctx, FileWriterKey, writer)
+
+	pkgpath := "example.com/m"
+	outputFile := "generated.go"
+	pkgname := "shapes"
+	goldenFile := "testdata/interfaceimpls.go.golden"
+
+	err := run(ctx, pkgpath, tmpdir, outputFile, pkgname, "", false, false, nil, "")
+	if err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+
+	generatedCode, ok := writer.Outputs[outputFile]
+	if !ok {
+		t.Fatalf("output file %q not found in captured outputs", outputFile)
+	}
+
+	if *update {
+		if err := os.WriteFile(goldenFile, generatedCode, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		t.Logf("golden file updated: %s", goldenFile)
+		return
+	}
+
+	golden, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if diff := cmp.Diff(string(golden), string(generatedCode)); diff != "" {
+		t.Errorf("generated code mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestIntegration_WithMaps(t *testing.T) {
 	files := map[string]string{
 		"go.mod": "module example.com/m\ngo 1.24",
@@ -1705,3 +2227,206 @@ func convertIntToString(ctx context.Context, ec *model.ErrorCollector, i int) st
 		t.Errorf("generated code mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestIntegration_WithCrossPackageTypes(t *testing.T) {
+	// The destination types are in another package, and have the same names as the source types.
+	files := map[string]string{
+		"go.mod": "module example.com/m\ngo 1.24",
+		"src/src.go": `package src
+
+import "example.com/m/dto"
+
+var _ dto.User
+
+// @derivingconvert("dto.User")
+type User struct {
+	Name    string
+	Address Address
+}
+
+type Address struct {
+	City string
+}
+
+// @derivingconvert("example.com/m/dto.Item")
+type Item struct {
+	ID string
+}
+`,
+		"dto/dto.go": `package dto
+
+type User struct {
+	Name    string
+	Address Address
+}
+
+type Address struct {
+	City string
+}
+
+type Item struct {
+	ID string
+}
+`,
+	}
+
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := &memoryFileWriter{}
+	ctx = context.WithValue(ctx, FileWriterKey, writer)
+
+	pkgpath := "example.com/m/src"
+	outputFile := "generated.go"
+	pkgname := "src"
+	goldenFile := "testdata/crosspackage.go.golden"
+
+	err := run(ctx, pkgpath, tmpdir, outputFile, pkgname, "", false, false, nil, "")
+	if err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+
+	generatedCode, ok := writer.Outputs[outputFile]
+	if !ok {
+		t.Fatalf("output file %q not found in captured outputs", outputFile)
+	}
+
+	if *update {
+		if err := os.WriteFile(goldenFile, generatedCode, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		t.Logf("golden file updated: %s", goldenFile)
+		return
+	}
+
+	golden, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	formattedGenerated, err := imports.Process(outputFile, generatedCode, nil)
+	if err != nil {
+		t.Fatalf("failed to format generated code: %v\n---\n%s", err, string(generatedCode))
+	}
+	formattedGolden, err := imports.Process(goldenFile, golden, nil)
+	if err != nil {
+		t.Fatalf("failed to format golden file: %v", err)
+	}
+
+	if diff := cmp.Diff(string(formattedGolden), string(formattedGenerated)); diff != "" {
+		t.Errorf("generated code mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIntegration_WithTests(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/m\ngo 1.24",
+		"roundtrip.go": `
+package roundtrip
+
+import (
+	"context"
+	"strings"
+
+	"github.com/podhmo/go-scan/examples/convert/model"
+)
+
+// @derivingconvert("UserDTO", tests=true)
+type User struct {
+	ID      int64
+	Name    string
+	Score   float64
+	Active  bool
+	Email   string ` + "`convert:\",using=lowerEmail\"`" + `
+	Address *Address
+}
+
+// @derivingconvert("User", max_errors=1, tests=true)
+type UserDTO struct {
+	ID      int64
+	Name    string
+	Score   float64
+	Active  bool
+	Email   string
+	Address *Address
+}
+
+type Address struct {
+	City string
+}
+
+func lowerEmail(ctx context.Context, ec *model.ErrorCollector, s string) string {
+	return strings.ToLower(s)
+}
+`,
+	}
+
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	ctx := context.Background()
+	writer := &memoryFileWriter{}
+	ctx = context.WithValue(ctx, FileWriterKey, writer)
+
+	outputFile := "generated.go"
+	testsFile := "generated_test.go"
+	goldenFile := "testdata/roundtrip_test.go.golden"
+
+	if err := run(ctx, "example.com/m", tmpdir, outputFile, "roundtrip", "", false, false, nil, ""); err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+	if _, ok := writer.Outputs[outputFile]; !ok {
+		t.Fatalf("output file %q not found in captured outputs", outputFile)
+	}
+	generatedCode, ok := writer.Outputs[testsFile]
+	if !ok {
+		t.Fatalf("tests file %q not found in captured outputs", testsFile)
+	}
+
+	if *update {
+		if err := os.WriteFile(goldenFile, generatedCode, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		t.Logf("golden file updated: %s", goldenFile)
+		return
+	}
+
+	golden, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if diff := cmp.Diff(string(golden), string(generatedCode)); diff != "" {
+		t.Errorf("generated tests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIntegration_WithoutTests(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/m\ngo 1.24",
+		"plain.go": `
+package plain
+
+// @derivingconvert("Dst")
+type Src struct {
+	ID int
+}
+
+type Dst struct {
+	ID int
+}
+`,
+	}
+
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	writer := &memoryFileWriter{}
+	ctx := context.WithValue(context.Background(), FileWriterKey, writer)
+	if err := run(ctx, "example.com/m", tmpdir, "generated.go", "plain", "", false, false, nil, ""); err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+	if _, ok := writer.Outputs["generated_test.go"]; ok {
+		t.Errorf("tests are generated without tests=true")
+	}
+}
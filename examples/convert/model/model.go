@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/podhmo/go-scan/scanner"
@@ -13,12 +14,31 @@ type ParsedInfo struct {
 	PackagePath       string // Import path of the package being parsed
 	ConversionPairs   []ConversionPair
 	GlobalRules       []TypeRule
-	Imports           map[string]string // alias -> import path
-	Structs           map[string]*StructInfo
+	InterfaceRules    []InterfaceRule
+	Imports           map[string]string      // alias -> import path
+	Structs           map[string]*StructInfo // by name, and by StructKey for the structs of the other packages
 	NamedTypes        map[string]*scanner.TypeInfo
 	ProcessedPackages map[string]bool // Tracks import paths that have been parsed
 }
 
+// StructKey returns the key in ParsedInfo.Structs of a struct of another package than the one
+// being parsed, so that types with the same name in different packages can be told apart.
+func StructKey(pkgPath, name string) string {
+	return pkgPath + "." + name
+}
+
+// LookupStruct returns the struct of the type with the given name. The structs of the other
+// packages are found by their package path before falling back to the name.
+func (info *ParsedInfo) LookupStruct(t *scanner.TypeInfo, name string) (*StructInfo, bool) {
+	if t != nil && t.PkgPath != "" && t.PkgPath != info.PackagePath {
+		if s, ok := info.Structs[StructKey(t.PkgPath, name)]; ok {
+			return s, true
+		}
+	}
+	s, ok := info.Structs[name]
+	return s, ok
+}
+
 // Variable defines a variable to be declared in the converter function.
 type Variable struct {
 	Name string
@@ -53,10 +73,22 @@ type ConversionPair struct {
 	DstTypeInfo *scanner.TypeInfo
 	Mapping     *MappingInfo // Explicit mapping rules from define.Mapping
 	MaxErrors   int
+	Tests       bool   // generate the tests of the converter (`tests=true`)
+	Match       string // the strategy matching the fields by name (`match=`), see MatchExact
 	Variables   []Variable
 	Computed    []ComputedField // TODO: This might be deprecated in favor of Mapping.Computes
 }
 
+// The strategies matching the fields of a conversion pair by name, set with the `match=` option.
+// Without a strategy, a field matches by its normalized `json` tag, then by its normalized name.
+// A `convert` tag naming the destination field takes precedence over all of them.
+const (
+	MatchExact           = "exact"            // the same name
+	MatchCaseInsensitive = "case-insensitive" // the same name, ignoring the case, e.g. "Url" and "URL"
+	MatchNormalized      = "normalized"       // the same name, ignoring the case and "_", e.g. "user_id" and "UserID"
+	MatchTagPrefix       = "tag:"             // the same value of a tag, e.g. "tag:json", or the same name without it
+)
+
 // TypeRule defines a global rule for converting between types or validating a type.
 type TypeRule struct {
 	SrcTypeName   string
@@ -67,6 +99,28 @@ type TypeRule struct {
 	ValidatorFunc string
 }
 
+// InterfaceRule defines how to convert a value of an interface type, by a type switch over
+// its registered implementations.
+type InterfaceRule struct {
+	SrcTypeName string
+	DstTypeName string
+	SrcTypeInfo *scanner.TypeInfo
+	DstTypeInfo *scanner.TypeInfo
+	Impls       []ImplPair
+	// Fallback is the behavior for the other implementations: "error" (the default) records an
+	// error, "zero" leaves the zero value, and any other value is the name of a function
+	// called with the source value.
+	Fallback string
+}
+
+// ImplPair defines the conversion of an implementation of an interface, e.g. "*Circle" -> "*CircleDTO".
+type ImplPair struct {
+	SrcTypeName string
+	DstTypeName string
+	SrcType     *scanner.FieldType
+	DstType     *scanner.FieldType
+}
+
 // StructInfo holds information about a parsed struct.
 type StructInfo struct {
 	Name            string
@@ -81,6 +135,7 @@ type FieldInfo struct {
 	Name         string
 	OriginalName string
 	JSONTag      string
+	StructTag    reflect.StructTag  // the whole tag, for the `match=tag:<name>` strategy
 	TypeInfo     *scanner.TypeInfo  // The resolved TypeInfo for the field's type
 	FieldType    *scanner.FieldType // The detailed FieldType
 	Tag          ConvertTag
@@ -3,8 +3,12 @@ package parser
 import (
 	"context"
 	"fmt"
+	"go/ast"
+	goparser "go/parser"
 	"log/slog"
+	"os"
 	"path"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -18,11 +22,16 @@ import (
 var (
 	reDerivingConvert = regexp.MustCompile(`@derivingconvert\(([^,)]+)(?:,\s*([^)]+))?\)`)
 	reConvertRule     = regexp.MustCompile(`// convert:rule "([^"]+)"(?: -> "([^"]+)")?, (?:using=([a-zA-Z0-9_.]+)|validator=([a-zA-Z0-9_.]+))`)
+	reConvertImpl     = regexp.MustCompile(`// convert:impl "([^"]+)" -> "([^"]+)", (?:"([^"]+)" -> "([^"]+)"|fallback=([a-zA-Z0-9_.]+))`)
 	reConvertImport   = regexp.MustCompile(`// convert:import ([a-zA-Z0-9_.]+) "([^"]+)"`)
 	reConvertVariable = regexp.MustCompile(`// convert:variable (\w+)\s+(.+)`)
 	reConvertComputed = regexp.MustCompile(`^\s*convert:computed\s+([\w\d]+)\s*=\s*(.+)`)
 )
 
+// RulesFileName is the name of the module-level file declaring the rules shared by all packages.
+// It is looked up in the module root directory.
+const RulesFileName = "convert.rules.go"
+
 func Parse(ctx context.Context, s *goscan.Scanner, scannedPkg *scanner.PackageInfo) (*model.ParsedInfo, error) {
 	info := &model.ParsedInfo{
 		PackageName:       scannedPkg.Name,
@@ -39,6 +48,15 @@ func Parse(ctx context.Context, s *goscan.Scanner, scannedPkg *scanner.PackageIn
 		return nil, fmt.Errorf("failed to process package %q: %w", scannedPkg.ImportPath, err)
 	}
 
+	if s != nil && s.RootDir() != "" {
+		filename := filepath.Join(s.RootDir(), RulesFileName)
+		if _, err := os.Stat(filename); err == nil {
+			if err := ParseRulesFile(ctx, s, info, filename); err != nil {
+				return nil, fmt.Errorf("failed to parse rules file %q: %w", filename, err)
+			}
+		}
+	}
+
 	return info, nil
 }
 
@@ -49,18 +67,8 @@ func processPackage(ctx context.Context, s *goscan.Scanner, info *model.ParsedIn
 	info.ProcessedPackages[pkgInfo.ImportPath] = true
 	slog.DebugContext(ctx, "Processing package", "path", pkgInfo.ImportPath)
 
-	for _, astFile := range pkgInfo.AstFiles {
-		for _, commentGroup := range astFile.Comments {
-			for _, comment := range commentGroup.List {
-				if m := reConvertImport.FindStringSubmatch(comment.Text); m != nil {
-					alias, path := m[1], m[2]
-					if existingPath, ok := info.Imports[alias]; ok && existingPath != path {
-						return fmt.Errorf("duplicate import alias %q with different paths: %q vs %q", alias, existingPath, path)
-					}
-					info.Imports[alias] = path
-				}
-			}
-		}
+	if err := parseImports(info, pkgInfo); err != nil {
+		return err
 	}
 
 	for _, t := range pkgInfo.Types {
@@ -68,11 +76,21 @@ func processPackage(ctx context.Context, s *goscan.Scanner, info *model.ParsedIn
 			info.NamedTypes[t.Name] = t
 		}
 		if t.Kind == scanner.StructKind {
-			if _, exists := info.Structs[t.Name]; exists {
+			// The structs of the package being parsed are registered by name, taking precedence
+			// over the structs with the same name of other packages, which are also registered
+			// with their package path.
+			key := t.Name
+			if pkgInfo.ImportPath != info.PackagePath {
+				key = model.StructKey(pkgInfo.ImportPath, t.Name)
+			}
+			if existing, exists := info.Structs[key]; exists && (existing.Type == nil || existing.Type.PkgPath == t.PkgPath) {
 				continue
 			}
 			modelStructInfo := &model.StructInfo{Name: t.Name, Type: t}
-			info.Structs[t.Name] = modelStructInfo
+			info.Structs[key] = modelStructInfo
+			if _, exists := info.Structs[t.Name]; !exists {
+				info.Structs[t.Name] = modelStructInfo
+			}
 
 			fields, err := collectFields(ctx, s, info, t, pkgInfo, make(map[string]struct{}))
 			if err != nil {
@@ -115,12 +133,25 @@ func processPackage(ctx context.Context, s *goscan.Scanner, info *model.ParsedIn
 				SrcTypeInfo: srcTypeInfo, DstTypeInfo: dstTypeInfo,
 			}
 
-			if optionsStr != "" {
-				parts := strings.Split(strings.TrimSpace(optionsStr), "=")
-				if len(parts) == 2 && strings.TrimSpace(parts[0]) == "max_errors" {
-					if maxErrors, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
+			for _, option := range strings.Split(optionsStr, ",") {
+				key, value, ok := strings.Cut(option, "=")
+				if !ok {
+					continue
+				}
+				switch value = strings.TrimSpace(value); strings.TrimSpace(key) {
+				case "max_errors":
+					if maxErrors, err := strconv.Atoi(value); err == nil {
 						pair.MaxErrors = maxErrors
 					}
+				case "tests":
+					if tests, err := strconv.ParseBool(value); err == nil {
+						pair.Tests = tests
+					}
+				case "match":
+					if !isMatchStrategy(value) {
+						return fmt.Errorf("unknown match strategy %q for source %q, want %s, %s, %s or %s<name>", value, t.Name, model.MatchExact, model.MatchCaseInsensitive, model.MatchNormalized, model.MatchTagPrefix)
+					}
+					pair.Match = value
 				}
 			}
 
@@ -136,7 +167,40 @@ func processPackage(ctx context.Context, s *goscan.Scanner, info *model.ParsedIn
 		}
 	}
 
-	for _, astFile := range pkgInfo.AstFiles {
+	return parseRules(ctx, s, info, pkgInfo)
+}
+
+// isMatchStrategy reports whether the value of the `match=` option is a known strategy.
+func isMatchStrategy(value string) bool {
+	switch value {
+	case model.MatchExact, model.MatchCaseInsensitive, model.MatchNormalized:
+		return true
+	}
+	name, ok := strings.CutPrefix(value, model.MatchTagPrefix)
+	return ok && name != ""
+}
+
+// parseImports collects the `// convert:import` annotations of the package.
+func parseImports(info *model.ParsedInfo, pkgInfo *scanner.PackageInfo) error {
+	for _, astFile := range pkgInfo.SortedAstFiles() {
+		for _, commentGroup := range astFile.Comments {
+			for _, comment := range commentGroup.List {
+				if m := reConvertImport.FindStringSubmatch(comment.Text); m != nil {
+					alias, path := m[1], m[2]
+					if existingPath, ok := info.Imports[alias]; ok && existingPath != path {
+						return fmt.Errorf("duplicate import alias %q with different paths: %q vs %q", alias, existingPath, path)
+					}
+					info.Imports[alias] = path
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseRules collects the `// convert:rule` annotations of the package.
+func parseRules(ctx context.Context, s *goscan.Scanner, info *model.ParsedInfo, pkgInfo *scanner.PackageInfo) error {
+	for _, astFile := range pkgInfo.SortedAstFiles() {
 		for _, commentGroup := range astFile.Comments {
 			for _, comment := range commentGroup.List {
 				if m := reConvertRule.FindStringSubmatch(comment.Text); m != nil {
@@ -165,12 +229,98 @@ func processPackage(ctx context.Context, s *goscan.Scanner, info *model.ParsedIn
 					}
 					info.GlobalRules = append(info.GlobalRules, rule)
 				}
+				if m := reConvertImpl.FindStringSubmatch(comment.Text); m != nil {
+					if err := parseImplRule(ctx, s, info, pkgInfo, m[1], m[2], m[3], m[4], m[5]); err != nil {
+						return err
+					}
+				}
 			}
 		}
 	}
 	return nil
 }
 
+// parseImplRule adds a `// convert:impl` annotation to the interface rule of the pair of types,
+// either an implementation pair (`"*Circle" -> "*CircleDTO"`) or the fallback (`fallback=error`).
+func parseImplRule(ctx context.Context, s *goscan.Scanner, info *model.ParsedInfo, pkgInfo *scanner.PackageInfo, srcName, dstName, implSrcName, implDstName, fallback string) error {
+	var rule *model.InterfaceRule
+	for i := range info.InterfaceRules {
+		if info.InterfaceRules[i].SrcTypeName == srcName && info.InterfaceRules[i].DstTypeName == dstName {
+			rule = &info.InterfaceRules[i]
+			break
+		}
+	}
+	if rule == nil {
+		srcTypeInfo, err := resolveType(ctx, s, info, pkgInfo, srcName)
+		if err != nil {
+			return fmt.Errorf("resolving impl rule src type %q: %w", srcName, err)
+		}
+		if srcTypeInfo.Kind != scanner.InterfaceKind {
+			return fmt.Errorf("impl rule src type %q is not an interface", srcName)
+		}
+		dstTypeInfo, err := resolveType(ctx, s, info, pkgInfo, dstName)
+		if err != nil {
+			return fmt.Errorf("resolving impl rule dst type %q: %w", dstName, err)
+		}
+		info.InterfaceRules = append(info.InterfaceRules, model.InterfaceRule{
+			SrcTypeName: srcName, DstTypeName: dstName,
+			SrcTypeInfo: srcTypeInfo, DstTypeInfo: dstTypeInfo,
+		})
+		rule = &info.InterfaceRules[len(info.InterfaceRules)-1]
+	}
+
+	if fallback != "" {
+		// The first fallback wins, so that the one of a package takes precedence over the rules file.
+		if rule.Fallback == "" {
+			rule.Fallback = fallback
+		}
+		return nil
+	}
+
+	for _, impl := range rule.Impls {
+		if impl.SrcTypeName == implSrcName {
+			return nil // already registered, e.g. by the package before the rules file
+		}
+	}
+	implSrcType, err := resolveFieldType(ctx, s, info, pkgInfo, implSrcName)
+	if err != nil {
+		return fmt.Errorf("resolving implementation %q of %q: %w", implSrcName, srcName, err)
+	}
+	implDstType, err := resolveFieldType(ctx, s, info, pkgInfo, implDstName)
+	if err != nil {
+		return fmt.Errorf("resolving conversion %q of implementation %q: %w", implDstName, implSrcName, err)
+	}
+	rule.Impls = append(rule.Impls, model.ImplPair{
+		SrcTypeName: implSrcName, DstTypeName: implDstName,
+		SrcType: implSrcType, DstType: implDstType,
+	})
+	return nil
+}
+
+// ParseRulesFile reads the `// convert:import` and `// convert:rule` annotations of a rules file
+// (see RulesFileName) into info. The rules are appended after the ones already collected, so a rule
+// declared in a package takes precedence over a rule of the rules file for the same pair of types.
+// Types are resolved with the imports of the file, and functions are referenced through
+// `// convert:import` aliases, as the file is not part of the package being converted.
+func ParseRulesFile(ctx context.Context, s *goscan.Scanner, info *model.ParsedInfo, filename string) error {
+	astFile, err := goparser.ParseFile(s.Fset(), filename, nil, goparser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	rulesPkg := &scanner.PackageInfo{
+		Name:       astFile.Name.Name,
+		Path:       filepath.Dir(filename),
+		ImportPath: s.ModulePath(),
+		Files:      []string{filename},
+		AstFiles:   map[string]*ast.File{filename: astFile},
+	}
+	slog.DebugContext(ctx, "Parsing rules file", "file", filename)
+	if err := parseImports(info, rulesPkg); err != nil {
+		return err
+	}
+	return parseRules(ctx, s, info, rulesPkg)
+}
+
 func isBuiltin(name string) bool {
 	switch name {
 	case "bool", "byte", "complex128", "complex64", "error", "float32", "float64",
@@ -225,7 +375,7 @@ func resolveType(ctx context.Context, s *goscan.Scanner, info *model.ParsedInfo,
 		pkgAlias := pkgIdentifier
 		pkgPath, found = info.Imports[pkgAlias]
 		if !found {
-			for _, f := range p.AstFiles {
+			for _, f := range p.SortedAstFiles() {
 				for _, i := range f.Imports {
 					path := strings.Trim(i.Path.Value, `"`)
 					if i.Name != nil && i.Name.Name == pkgAlias {
@@ -274,6 +424,25 @@ func resolveType(ctx context.Context, s *goscan.Scanner, info *model.ParsedInfo,
 	return resolvedTypeInfo, nil
 }
 
+// resolveFieldType resolves a type name, optionally prefixed with "*", to a field type.
+func resolveFieldType(ctx context.Context, s *goscan.Scanner, info *model.ParsedInfo, p *scanner.PackageInfo, typeNameStr string) (*scanner.FieldType, error) {
+	ti, err := resolveType(ctx, s, info, p, typeNameStr)
+	if err != nil {
+		return nil, err
+	}
+	ft := &scanner.FieldType{
+		Name:           ti.Name,
+		TypeName:       ti.Name,
+		FullImportPath: ti.PkgPath,
+		Definition:     ti,
+		IsBuiltin:      ti.PkgPath == "" && isBuiltin(ti.Name),
+	}
+	if strings.HasPrefix(typeNameStr, "*") {
+		return &scanner.FieldType{IsPointer: true, Elem: ft, Definition: ti}, nil
+	}
+	return ft, nil
+}
+
 func collectFields(ctx context.Context, s *goscan.Scanner, info *model.ParsedInfo, t *scanner.TypeInfo, p *scanner.PackageInfo, visited map[string]struct{}) ([]model.FieldInfo, error) {
 	if _, ok := visited[t.Name]; ok {
 		return nil, nil
@@ -333,7 +502,7 @@ func collectFields(ctx context.Context, s *goscan.Scanner, info *model.ParsedInf
 			}
 			fields = append(fields, model.FieldInfo{
 				Name: f.Name, OriginalName: f.Name, JSONTag: parseJSONTag(reflect.StructTag(f.Tag)),
-				FieldType: f.Type, Tag: tag, TypeInfo: fieldTypeInfo,
+				StructTag: reflect.StructTag(f.Tag), FieldType: f.Type, Tag: tag, TypeInfo: fieldTypeInfo,
 			})
 		}
 	}
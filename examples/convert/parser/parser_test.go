@@ -55,6 +55,14 @@ type DestinationWithOption struct {
 	ID int
 }
 
+// @derivingconvert(DestinationWithTests, max_errors=2, tests=true)
+type SourceWithTests struct {
+	ID int
+}
+type DestinationWithTests struct {
+	ID int
+}
+
 
 // convert:rule "time.Time" -> "string", using=TimeToString
 // convert:rule "string" -> "time.Time", using=StringToTime
@@ -113,6 +121,7 @@ type MyTime time.Time
 		ConversionPairs: []model.ConversionPair{
 			{SrcTypeName: "Source", DstTypeName: "Destination", MaxErrors: 0, Variables: nil},
 			{SrcTypeName: "SourceWithOption", DstTypeName: "DestinationWithOption", MaxErrors: 5, Variables: nil},
+			{SrcTypeName: "SourceWithTests", DstTypeName: "DestinationWithTests", MaxErrors: 2, Tests: true, Variables: nil},
 		},
 		GlobalRules: []model.TypeRule{
 			{SrcTypeName: "time.Time", DstTypeName: "string", UsingFunc: "TimeToString"},
@@ -149,12 +158,26 @@ type MyTime time.Time
 					{Name: "ID", OriginalName: "ID"},
 				},
 			},
+			"SourceWithTests": {
+				Name: "SourceWithTests",
+				Fields: []model.FieldInfo{
+					{Name: "ID", OriginalName: "ID"},
+				},
+			},
+			"DestinationWithTests": {
+				Name: "DestinationWithTests",
+				Fields: []model.FieldInfo{
+					{Name: "ID", OriginalName: "ID"},
+				},
+			},
 		},
 		NamedTypes: map[string]*scanner.TypeInfo{
 			"Source":                {Name: "Source"},
 			"Destination":           {Name: "Destination"},
 			"SourceWithOption":      {Name: "SourceWithOption"},
 			"DestinationWithOption": {Name: "DestinationWithOption"},
+			"SourceWithTests":       {Name: "SourceWithTests"},
+			"DestinationWithTests":  {Name: "DestinationWithTests"},
 			"MyTime":                {Name: "MyTime"},
 		},
 	}
@@ -167,7 +190,7 @@ type MyTime time.Time
 	opts := []cmp.Option{
 		cmp.AllowUnexported(model.ParsedInfo{}, model.ConversionPair{}, model.StructInfo{}, model.FieldInfo{}),
 		cmpopts.IgnoreUnexported(scanner.TypeInfo{}, token.FileSet{}),
-		cmpopts.IgnoreFields(scanner.TypeInfo{}, "PkgPath", "FilePath", "Doc", "Kind", "Node", "Struct", "Func", "Interface", "Underlying", "TypeParams", "Inspect", "Logger", "ResolutionContext"),
+		cmpopts.IgnoreFields(scanner.TypeInfo{}, "PkgPath", "FilePath", "Doc", "Kind", "Node", "Struct", "Func", "Interface", "Underlying", "DeclHash", "TypeParams", "Inspect", "Logger", "ResolutionContext"),
 		cmpopts.IgnoreFields(model.ParsedInfo{}, "NamedTypes", "Structs"), // check them separately
 		cmpopts.IgnoreFields(model.ConversionPair{}, "SrcTypeInfo", "DstTypeInfo"),
 		cmpopts.IgnoreFields(model.TypeRule{}, "SrcTypeInfo", "DstTypeInfo"),
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// edge is a single dependency edge, from an importer to an imported package.
+type edge struct {
+	From string
+	To   string
+}
+
+func (e edge) String() string {
+	return e.From + " -> " + e.To
+}
+
+// graphSnapshot is a flattened view of a dependency graph, used for comparison.
+type graphSnapshot struct {
+	nodes map[string]bool
+	edges map[edge]bool
+}
+
+func newGraphSnapshot() *graphSnapshot {
+	return &graphSnapshot{nodes: make(map[string]bool), edges: make(map[edge]bool)}
+}
+
+func (g *graphSnapshot) addEdge(from, to string) {
+	g.nodes[from] = true
+	g.nodes[to] = true
+	g.edges[edge{From: from, To: to}] = true
+}
+
+// addDependencies adds the forward (`from -> to[]`) and reverse (`importer -> imported[]`)
+// dependency maps, as found in the JSON output, to the snapshot.
+func (g *graphSnapshot) addDependencies(deps, revDeps map[string][]string) {
+	for from, toList := range deps {
+		for _, to := range toList {
+			g.addEdge(from, to)
+		}
+	}
+	for importer, importedList := range revDeps {
+		for _, imported := range importedList {
+			g.addEdge(importer, imported)
+		}
+	}
+}
+
+// snapshot adds the visible part of the visitor's graph to the given snapshot.
+func (v *graphVisitor) snapshot(g *graphSnapshot) {
+	add := func(m map[string][]string) {
+		for from, toList := range m {
+			if v.isHidden(from) {
+				continue
+			}
+			for _, to := range toList {
+				if !v.isHidden(to) && !v.isTrivial(from, to) {
+					g.addEdge(from, to)
+				}
+			}
+		}
+	}
+	add(v.dependencies)
+	add(v.reverseDependencies)
+}
+
+// loadGraphSnapshot reads a previously saved JSON graph (the output of `--format=json`).
+// The file may contain multiple JSON documents, one per start package.
+func loadGraphSnapshot(path string) (*graphSnapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+
+	g := newGraphSnapshot()
+	decoder := json.NewDecoder(f)
+	for {
+		var doc jsonGraph
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decoding JSON graph in %s: %w", path, err)
+		}
+		g.addDependencies(doc.Dependencies, doc.ReverseDependencies)
+	}
+	return g, nil
+}
+
+// graphDiff holds the differences between two dependency graphs.
+type graphDiff struct {
+	AddedNodes   []string
+	RemovedNodes []string
+	AddedEdges   []edge
+	RemovedEdges []edge
+}
+
+func diffGraphSnapshots(old, cur *graphSnapshot) graphDiff {
+	var d graphDiff
+	for n := range cur.nodes {
+		if !old.nodes[n] {
+			d.AddedNodes = append(d.AddedNodes, n)
+		}
+	}
+	for n := range old.nodes {
+		if !cur.nodes[n] {
+			d.RemovedNodes = append(d.RemovedNodes, n)
+		}
+	}
+	for e := range cur.edges {
+		if !old.edges[e] {
+			d.AddedEdges = append(d.AddedEdges, e)
+		}
+	}
+	for e := range old.edges {
+		if !cur.edges[e] {
+			d.RemovedEdges = append(d.RemovedEdges, e)
+		}
+	}
+	sort.Strings(d.AddedNodes)
+	sort.Strings(d.RemovedNodes)
+	sortEdges(d.AddedEdges)
+	sortEdges(d.RemovedEdges)
+	return d
+}
+
+func sortEdges(edges []edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+}
+
+// forbiddenRule declares an architectural boundary, e.g. "domain/* must not import infrastructure/*".
+type forbiddenRule struct {
+	From string
+	To   string
+}
+
+func (r forbiddenRule) String() string {
+	return r.From + "->" + r.To
+}
+
+// parseForbiddenRules parses a comma-separated list of rules in `<from-pattern>-><to-pattern>` form.
+func parseForbiddenRules(s string) ([]forbiddenRule, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var rules []forbiddenRule
+	for _, raw := range strings.Split(s, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(raw, "->")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid forbidden-edge rule %q, expected <from-pattern>-><to-pattern>", raw)
+		}
+		if _, err := filepath.Match(from, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in rule %q: %w", from, raw, err)
+		}
+		if _, err := filepath.Match(to, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in rule %q: %w", to, raw, err)
+		}
+		rules = append(rules, forbiddenRule{From: from, To: to})
+	}
+	return rules, nil
+}
+
+// matchPackagePattern reports whether the pattern matches the package path, either as a
+// full import path or as a path relative to the module.
+func matchPackagePattern(pattern, pkgPath, modulePath string) bool {
+	if matched, _ := filepath.Match(pattern, pkgPath); matched {
+		return true
+	}
+	if modulePath != "" && strings.HasPrefix(pkgPath, modulePath+"/") {
+		shortPath := strings.TrimPrefix(pkgPath, modulePath+"/")
+		if matched, _ := filepath.Match(pattern, shortPath); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// violation is an edge that crosses a forbidden boundary.
+type violation struct {
+	Edge edge
+	Rule forbiddenRule
+}
+
+func findViolations(edges []edge, rules []forbiddenRule, modulePath string) []violation {
+	var violations []violation
+	for _, e := range edges {
+		for _, r := range rules {
+			if matchPackagePattern(r.From, e.From, modulePath) && matchPackagePattern(r.To, e.To, modulePath) {
+				violations = append(violations, violation{Edge: e, Rule: r})
+				break
+			}
+		}
+	}
+	return violations
+}
+
+func writeGraphDiff(w io.Writer, d graphDiff, violations []violation) {
+	for _, n := range d.AddedNodes {
+		fmt.Fprintf(w, "+ node %s\n", n)
+	}
+	for _, n := range d.RemovedNodes {
+		fmt.Fprintf(w, "- node %s\n", n)
+	}
+	for _, e := range d.AddedEdges {
+		fmt.Fprintf(w, "+ edge %s\n", e)
+	}
+	for _, e := range d.RemovedEdges {
+		fmt.Fprintf(w, "- edge %s\n", e)
+	}
+	writeViolations(w, violations)
+}
+
+func writeViolations(w io.Writer, violations []violation) {
+	for _, v := range violations {
+		fmt.Fprintf(w, "! forbidden edge %s (rule: %s)\n", v.Edge, v.Rule)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	goscan "github.com/podhmo/go-scan"
+)
+
+// licenseEntry is a module of the license report, as written in the JSON output.
+type licenseEntry struct {
+	Path        string   `json:"path"`
+	Version     string   `json:"version,omitempty"`
+	License     string   `json:"license,omitempty"`
+	LicenseFile string   `json:"licenseFile,omitempty"`
+	Packages    []string `json:"packages"`
+}
+
+// writeLicenseReport writes the external modules visited by the walks with their licenses,
+// as a table, or as JSON with the json format.
+func writeLicenseReport(w io.Writer, report *goscan.DependencyReport, format string) error {
+	if format == "json" {
+		entries := make([]licenseEntry, 0, len(report.Modules))
+		for _, m := range report.Modules {
+			entries = append(entries, licenseEntry{
+				Path:        m.Path,
+				Version:     m.Version,
+				License:     m.License,
+				LicenseFile: m.LicenseFile,
+				Packages:    m.Packages,
+			})
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODULE\tVERSION\tLICENSE\tPACKAGES")
+	for _, m := range report.Modules {
+		version, license := m.Version, m.License
+		if version == "" {
+			version = "-" // replaced by a local directory
+		}
+		if license == "" {
+			license = "none"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", m.Path, version, license, len(m.Packages))
+	}
+	return tw.Flush()
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	goscan "github.com/podhmo/go-scan"
@@ -31,6 +32,12 @@ func main() {
 		test        bool
 		dryRun      bool
 		inspect     bool
+		diff        string
+		forbid      string
+		style       string
+		licenses    bool
+		weights     bool
+		minWeight   int
 		logLevel    = slog.LevelWarn
 	)
 
@@ -48,6 +55,12 @@ func main() {
 	flag.BoolVar(&test, "test", false, "Include test files in the analysis")
 	flag.BoolVar(&dryRun, "dry-run", false, "don't write to output file, just print to stdout")
 	flag.BoolVar(&inspect, "inspect", false, "enable inspection logging")
+	flag.StringVar(&diff, "diff", "", "Compare against a previously saved JSON graph and report added/removed nodes and edges")
+	flag.StringVar(&forbid, "forbid", "", "A comma-separated list of forbidden edges in <from-pattern>-><to-pattern> form (checked against added edges in diff mode)")
+	flag.StringVar(&style, "style", "", "A comma-separated list of <pattern>=<color>[@<cluster>] rules, or a JSON file of rules, to color and group nodes in DOT and Mermaid output")
+	flag.BoolVar(&licenses, "licenses", false, "Report the external modules reached by the walk with their version and license, instead of the graph (implies --full)")
+	flag.BoolVar(&weights, "weights", false, "Weigh each edge by the number of distinct symbols of the imported package referenced by the importer (JSON weights, DOT edge thickness)")
+	flag.IntVar(&minWeight, "min-weight", 0, "Hide the edges referencing fewer symbols of the imported package than this (implies --weights)")
 	flag.TextVar(&logLevel, "log-level", &logLevel, "set log level (debug, info, warn, error)")
 	flag.Parse()
 
@@ -61,30 +74,101 @@ func main() {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &opts))
 	slog.SetDefault(logger)
 
-	if err := run(context.Background(), startPkgs, hops, ignore, hide, output, format, granularity, full, short, direction, aggressive, test, dryRun, inspect, logger); err != nil {
+	cfg := runConfig{
+		StartPkgs:   startPkgs,
+		Hops:        hops,
+		Ignore:      ignore,
+		Hide:        hide,
+		Output:      output,
+		Format:      format,
+		Granularity: granularity,
+		Full:        full,
+		Short:       short,
+		Direction:   direction,
+		Aggressive:  aggressive,
+		Test:        test,
+		DryRun:      dryRun,
+		Inspect:     inspect,
+		Diff:        diff,
+		Forbid:      forbid,
+		Style:       style,
+		Licenses:    licenses,
+		Weights:     weights,
+		MinWeight:   minWeight,
+	}
+	if err := run(context.Background(), cfg, logger); err != nil {
 		slog.ErrorContext(context.Background(), "Error", slog.Any("error", err))
 		os.Exit(1)
 	}
 }
 
-func run(ctx context.Context, startPkgs []string, hops int, ignore string, hide string, output string, format string, granularity string, full bool, short bool, direction string, aggressive bool, test bool, dryRun bool, inspect bool, logger *slog.Logger) error {
+// runConfig holds the settings of a run, from the command-line flags of the same names.
+type runConfig struct {
+	StartPkgs   []string // the positional arguments
+	Hops        int
+	Ignore      string // comma-separated package patterns
+	Hide        string // comma-separated package patterns
+	Output      string
+	Format      string
+	Granularity string
+	Full        bool
+	Short       bool
+	Direction   string
+	Aggressive  bool
+	Test        bool
+	DryRun      bool
+	Inspect     bool
+
+	Diff      string // the JSON graph to compare against
+	Forbid    string
+	Style     string
+	Licenses  bool
+	Weights   bool
+	MinWeight int
+}
+
+func run(ctx context.Context, cfg runConfig, logger *slog.Logger) error {
 	var finalOutput bytes.Buffer
 
+	if cfg.MinWeight > 0 {
+		cfg.Weights = true
+	}
+
+	if cfg.Licenses {
+		if cfg.Diff != "" {
+			return fmt.Errorf("--licenses is not compatible with --diff")
+		}
+		cfg.Full = true // The external packages must be walked to find their modules.
+	}
+
+	rules, err := parseForbiddenRules(cfg.Forbid)
+	if err != nil {
+		return fmt.Errorf("invalid --forbid: %w", err)
+	}
+	styleRules, err := parseStyleRules(cfg.Style)
+	if err != nil {
+		return fmt.Errorf("invalid --style: %w", err)
+	}
+	current := newGraphSnapshot()
+
 	var scannerOpts []goscan.ScannerOption
-	if full {
+	if cfg.Full {
 		scannerOpts = append(scannerOpts, goscan.WithGoModuleResolver())
 	}
-	scannerOpts = append(scannerOpts, goscan.WithIncludeTests(test))
-	scannerOpts = append(scannerOpts, goscan.WithDryRun(dryRun))
-	scannerOpts = append(scannerOpts, goscan.WithInspect(inspect))
+	scannerOpts = append(scannerOpts, goscan.WithIncludeTests(cfg.Test))
+	scannerOpts = append(scannerOpts, goscan.WithDryRun(cfg.DryRun))
+	scannerOpts = append(scannerOpts, goscan.WithInspect(cfg.Inspect))
 	scannerOpts = append(scannerOpts, goscan.WithLogger(logger))
+	if cfg.Licenses {
+		scannerOpts = append(scannerOpts, goscan.WithDependencyReport())
+	}
 
 	s, err := goscan.New(scannerOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to create scanner: %w", err)
 	}
 
-	for i, startPkg := range startPkgs {
+	for i, startPkg := range cfg.StartPkgs {
 		// Use the facade function from the root goscan package
 		resolvedStartPkg, err := goscan.ResolvePath(ctx, startPkg)
 		if err != nil {
@@ -93,32 +177,34 @@ func run(ctx context.Context, startPkgs []string, hops int, ignore string, hide
 		startPkg = resolvedStartPkg
 
 		ignorePatterns := []string{}
-		if ignore != "" {
-			ignorePatterns = strings.Split(ignore, ",")
+		if cfg.Ignore != "" {
+			ignorePatterns = strings.Split(cfg.Ignore, ",")
 		}
 		hidePatterns := []string{}
-		if hide != "" {
-			hidePatterns = strings.Split(hide, ",")
+		if cfg.Hide != "" {
+			hidePatterns = strings.Split(cfg.Hide, ",")
 		}
 
 		visitor := &graphVisitor{
 			startPkg:            startPkg,
 			s:                   s,
-			hops:                hops,
-			full:                full,
-			short:               short,
-			granularity:         granularity,
+			hops:                cfg.Hops,
+			full:                cfg.Full,
+			short:               cfg.Short,
+			granularity:         cfg.Granularity,
 			ignorePatterns:      ignorePatterns,
 			hidePatterns:        hidePatterns,
+			styleRules:          styleRules,
 			dependencies:        make(map[string][]string),
 			reverseDependencies: make(map[string][]string),
 			packageHops:         make(map[string]int),
+			minWeight:           cfg.MinWeight,
 		}
 
-		if aggressive && !(direction == "reverse" || direction == "bidi") {
+		if cfg.Aggressive && !(cfg.Direction == "reverse" || cfg.Direction == "bidi") {
 			return fmt.Errorf("--aggressive is only valid with --direction=reverse or --direction=bidi")
 		}
-		if granularity == "file" && (direction == "reverse" || direction == "bidi") {
+		if cfg.Granularity == "file" && (cfg.Direction == "reverse" || cfg.Direction == "bidi") {
 			return fmt.Errorf("--granularity=file is not compatible with --direction=reverse or --direction=bidi")
 		}
 
@@ -128,7 +214,7 @@ func run(ctx context.Context, startPkgs []string, hops int, ignore string, hide
 		}
 
 		doReverseSearch := func() error {
-			if aggressive {
+			if cfg.Aggressive {
 				// Aggressive search using git grep
 				queue := []string{startPkg}
 				pkgHops := map[string]int{startPkg: 0}
@@ -138,7 +224,7 @@ func run(ctx context.Context, startPkgs []string, hops int, ignore string, hide
 					head++
 
 					currentHops := pkgHops[currentPkg]
-					if currentHops >= hops {
+					if currentHops >= cfg.Hops {
 						continue
 					}
 
@@ -172,7 +258,7 @@ func run(ctx context.Context, startPkgs []string, hops int, ignore string, hide
 				head++
 
 				currentHops := pkgHops[currentPkg]
-				if currentHops >= hops {
+				if currentHops >= cfg.Hops {
 					continue
 				}
 
@@ -188,7 +274,7 @@ func run(ctx context.Context, startPkgs []string, hops int, ignore string, hide
 			return nil
 		}
 
-		switch direction {
+		switch cfg.Direction {
 		case "forward":
 			if err := doForwardSearch(); err != nil {
 				return fmt.Errorf("walk failed for %q: %w", startPkg, err)
@@ -205,11 +291,22 @@ func run(ctx context.Context, startPkgs []string, hops int, ignore string, hide
 				return fmt.Errorf("bidi walk (reverse part) failed for %q: %w", startPkg, err)
 			}
 		default:
-			return fmt.Errorf("invalid direction: %q. must be one of forward, reverse, or bidi", direction)
+			return fmt.Errorf("invalid direction: %q. must be one of forward, reverse, or bidi", cfg.Direction)
+		}
+
+		if cfg.Weights {
+			if err := visitor.computeWeights(ctx); err != nil {
+				return fmt.Errorf("failed to weigh the dependencies of %q: %w", startPkg, err)
+			}
+		}
+
+		visitor.snapshot(current)
+		if cfg.Diff != "" || cfg.Licenses {
+			continue // In diff and licenses modes, only the report is written.
 		}
 
 		var buf bytes.Buffer
-		switch format {
+		switch cfg.Format {
 		case "dot":
 			if err := visitor.WriteDOT(&buf); err != nil {
 				return fmt.Errorf("failed to generate DOT graph for %q: %w", startPkg, err)
@@ -219,30 +316,66 @@ func run(ctx context.Context, startPkgs []string, hops int, ignore string, hide
 				return fmt.Errorf("failed to generate Mermaid graph for %q: %w", startPkg, err)
 			}
 		case "json":
-			if err := visitor.WriteJSON(&buf, startPkg, direction); err != nil {
+			if err := visitor.WriteJSON(&buf, startPkg, cfg.Direction); err != nil {
 				return fmt.Errorf("failed to generate JSON output for %q: %w", startPkg, err)
 			}
 		default:
-			return fmt.Errorf("unsupported format: %q", format)
+			return fmt.Errorf("unsupported format: %q", cfg.Format)
 		}
 
 		finalOutput.Write(buf.Bytes())
-		if i < len(startPkgs)-1 {
+		if i < len(cfg.StartPkgs)-1 {
 			finalOutput.WriteString("\n\n")
 		}
 	}
 
+	if cfg.Licenses {
+		if err := writeLicenseReport(&finalOutput, s.DependencyReport(), cfg.Format); err != nil {
+			return fmt.Errorf("failed to write license report: %w", err)
+		}
+	}
+
+	var violations []violation
+	if cfg.Diff != "" {
+		old, err := loadGraphSnapshot(cfg.Diff)
+		if err != nil {
+			return fmt.Errorf("failed to load graph for --diff: %w", err)
+		}
+		d := diffGraphSnapshots(old, current)
+		violations = findViolations(d.AddedEdges, rules, s.ModulePath())
+		writeGraphDiff(&finalOutput, d, violations)
+	} else if len(rules) > 0 {
+		var edges []edge
+		for e := range current.edges {
+			edges = append(edges, e)
+		}
+		sortEdges(edges)
+		violations = findViolations(edges, rules, s.ModulePath())
+		for _, v := range violations {
+			slog.WarnContext(ctx, "forbidden edge", "from", v.Edge.From, "to", v.Edge.To, "rule", v.Rule.String())
+		}
+	}
+
+	if err := writeOutput(ctx, finalOutput.Bytes(), cfg.Output, cfg.DryRun); err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("found %d forbidden dependency edge(s)", len(violations))
+	}
+	return nil
+}
+
+func writeOutput(ctx context.Context, content []byte, output string, dryRun bool) error {
 	if output == "" || dryRun {
 		if dryRun && output != "" {
 			slog.InfoContext(ctx, "Dry run: skipping file write", "path", output)
 		}
-		_, err = os.Stdout.Write(finalOutput.Bytes())
-		if err != nil {
+		if _, err := os.Stdout.Write(content); err != nil {
 			return fmt.Errorf("writing to stdout: %w", err)
 		}
 		return nil
 	}
-	return os.WriteFile(output, finalOutput.Bytes(), 0644)
+	return os.WriteFile(output, content, 0644)
 }
 
 type graphVisitor struct {
@@ -254,9 +387,12 @@ type graphVisitor struct {
 	granularity         string
 	ignorePatterns      []string
 	hidePatterns        []string
+	styleRules          []*styleRule
 	dependencies        map[string][]string // from -> to[]
 	reverseDependencies map[string][]string // to -> from[]
 	packageHops         map[string]int      // package -> hop level
+	weights             map[edge]int        // edge -> number of referenced symbols, with --weights
+	minWeight           int
 }
 
 func (v *graphVisitor) Visit(pkg *goscan.PackageImports) ([]string, error) {
@@ -385,6 +521,7 @@ func (v *graphVisitor) WriteDOT(w io.Writer) error {
 	modulePath := v.s.ModulePath()
 	moduleRootDir := v.s.RootDir()
 
+	clustered := make(map[string][]string) // cluster -> node lines
 	for _, node := range sortedNodes {
 		if v.isHidden(node) {
 			continue
@@ -400,6 +537,11 @@ func (v *graphVisitor) WriteDOT(w io.Writer) error {
 			label = strings.TrimPrefix(label, "/")
 		}
 
+		var line, cluster, color string
+		if style := v.styleOf(node); style != nil {
+			cluster, color = style.Cluster, style.Color
+		}
+
 		// Highlight start node
 		if node == v.startPkg {
 			var attributes string
@@ -409,20 +551,47 @@ func (v *graphVisitor) WriteDOT(w io.Writer) error {
 			case "package":
 				attributes = `shape=box, style="rounded,filled", fillcolor=lightblue`
 			}
-			fmt.Fprintf(w, `  "%s" [label="%s", %s];`+"\n", node, label, attributes)
+			line = fmt.Sprintf(`"%s" [label="%s", %s];`, node, label, attributes)
+		} else {
+			switch allNodes[node] {
+			case "file":
+				if color == "" {
+					color = "khaki"
+				}
+				line = fmt.Sprintf(`"%s" [label="%s", shape=note, style=filled, fillcolor=%s];`, node, label, dotID(color))
+			case "package":
+				if v.granularity == "package" {
+					if color == "" {
+						line = fmt.Sprintf(`"%s" [label="%s"];`, node, label)
+					} else {
+						line = fmt.Sprintf(`"%s" [label="%s", fillcolor=%s];`, node, label, dotID(color))
+					}
+				} else {
+					if color == "" {
+						color = "lightgrey"
+					}
+					line = fmt.Sprintf(`"%s" [label="%s", shape=box, style="rounded,filled", fillcolor=%s];`, node, label, dotID(color))
+				}
+			}
+		}
+		if cluster == "" {
+			fmt.Fprintf(w, "  %s\n", line)
 			continue
 		}
+		clustered[cluster] = append(clustered[cluster], line)
+	}
 
-		switch allNodes[node] {
-		case "file":
-			fmt.Fprintf(w, `  "%s" [label="%s", shape=note, style=filled, fillcolor=khaki];`+"\n", node, label)
-		case "package":
-			if v.granularity == "package" {
-				fmt.Fprintf(w, `  "%s" [label="%s"];`+"\n", node, label)
-			} else {
-				fmt.Fprintf(w, `  "%s" [label="%s", shape=box, style="rounded,filled", fillcolor=lightgrey];`+"\n", node, label)
-			}
+	for i, cluster := range v.clusterOrder() {
+		lines, ok := clustered[cluster]
+		if !ok {
+			continue
 		}
+		fmt.Fprintf(w, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(w, "    label=%q;\n", cluster)
+		for _, line := range lines {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+		fmt.Fprintln(w, "  }")
 	}
 
 	fmt.Fprintln(w, "")
@@ -438,7 +607,11 @@ func (v *graphVisitor) WriteDOT(w io.Writer) error {
 		toList := v.dependencies[from]
 		sort.Strings(toList)
 		for _, to := range toList {
-			if v.isHidden(from) || v.isHidden(to) {
+			if v.isHidden(from) || v.isHidden(to) || v.isTrivial(from, to) {
+				continue
+			}
+			if weight, ok := v.weights[edge{From: from, To: to}]; ok {
+				fmt.Fprintf(w, `  "%s" -> "%s" [penwidth=%d, label="%d"];`+"\n", from, to, penWidth(weight), weight)
 				continue
 			}
 			fmt.Fprintf(w, `  "%s" -> "%s";`+"\n", from, to)
@@ -456,10 +629,14 @@ func (v *graphVisitor) WriteDOT(w io.Writer) error {
 		toList := v.reverseDependencies[from]
 		sort.Strings(toList)
 		for _, to := range toList {
-			if v.isHidden(from) || v.isHidden(to) {
+			if v.isHidden(from) || v.isHidden(to) || v.isTrivial(from, to) {
 				continue
 			}
 			// Using [dir=back] to indicate a reverse dependency
+			if weight, ok := v.weights[edge{From: from, To: to}]; ok {
+				fmt.Fprintf(w, `  "%s" -> "%s" [dir=back, style=dashed, penwidth=%d, label="%d"];`+"\n", to, from, penWidth(weight), weight)
+				continue
+			}
 			fmt.Fprintf(w, `  "%s" -> "%s" [dir=back, style=dashed];`+"\n", to, from)
 		}
 	}
@@ -468,6 +645,16 @@ func (v *graphVisitor) WriteDOT(w io.Writer) error {
 	return nil
 }
 
+// dotID quotes a DOT attribute value unless it is a plain identifier, e.g. a "#rrggbb" color.
+func dotID(s string) string {
+	for _, r := range s {
+		if !(r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
 func (v *graphVisitor) WriteMermaid(w io.Writer) error {
 	fmt.Fprintln(w, "graph LR")
 
@@ -511,6 +698,8 @@ func (v *graphVisitor) WriteMermaid(w io.Writer) error {
 
 	fmt.Fprintln(w, "")
 
+	clustered := make(map[string][]string) // cluster -> node lines
+	var styled []string                    // style lines of the colored nodes
 	for _, node := range sortedNodes {
 		if v.isHidden(node) {
 			continue
@@ -527,12 +716,35 @@ func (v *graphVisitor) WriteMermaid(w io.Writer) error {
 			label = strings.TrimPrefix(label, "/")
 		}
 
+		var line string
 		switch allNodes[node] {
 		case "file":
-			fmt.Fprintf(w, `%s%s("%s")`+"\n", indent, id, label)
+			line = fmt.Sprintf(`%s("%s")`, id, label)
 		case "package":
-			fmt.Fprintf(w, `%s%s["%s"]`+"\n", indent, id, label)
+			line = fmt.Sprintf(`%s["%s"]`, id, label)
+		}
+
+		style := v.styleOf(node)
+		if style != nil && style.Color != "" && node != v.startPkg {
+			styled = append(styled, fmt.Sprintf("style %s fill:%s", id, style.Color))
+		}
+		if style == nil || style.Cluster == "" {
+			fmt.Fprintf(w, "%s%s\n", indent, line)
+			continue
+		}
+		clustered[style.Cluster] = append(clustered[style.Cluster], line)
+	}
+
+	for i, cluster := range v.clusterOrder() {
+		lines, ok := clustered[cluster]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "%ssubgraph cluster_%d [%s]\n", indent, i, cluster)
+		for _, line := range lines {
+			fmt.Fprintf(w, "%s  %s\n", indent, line)
 		}
+		fmt.Fprintf(w, "%send\n", indent)
 	}
 
 	fmt.Fprintln(w, "")
@@ -549,7 +761,7 @@ func (v *graphVisitor) WriteMermaid(w io.Writer) error {
 		sort.Strings(toList)
 		fromID := nodeIDs[from]
 		for _, to := range toList {
-			if v.isHidden(from) || v.isHidden(to) {
+			if v.isHidden(from) || v.isHidden(to) || v.isTrivial(from, to) {
 				continue
 			}
 			toID, ok := nodeIDs[to]
@@ -572,7 +784,7 @@ func (v *graphVisitor) WriteMermaid(w io.Writer) error {
 		sort.Strings(toList)
 		fromID := nodeIDs[from]
 		for _, to := range toList {
-			if v.isHidden(from) || v.isHidden(to) {
+			if v.isHidden(from) || v.isHidden(to) || v.isTrivial(from, to) {
 				continue
 			}
 			toID, ok := nodeIDs[to]
@@ -588,6 +800,13 @@ func (v *graphVisitor) WriteMermaid(w io.Writer) error {
 		fmt.Fprintln(w, "  end")
 	}
 
+	if len(styled) > 0 {
+		fmt.Fprintln(w, "")
+		for _, line := range styled {
+			fmt.Fprintf(w, "%s%s\n", indent, line)
+		}
+	}
+
 	// Add styling for the start package
 	if startNodeID, ok := nodeIDs[v.startPkg]; ok {
 		fmt.Fprintf(w, "\n%sstyle %s fill:#add8e6,stroke:#333,stroke-width:2px\n", indent, startNodeID)
@@ -596,12 +815,18 @@ func (v *graphVisitor) WriteMermaid(w io.Writer) error {
 	return nil
 }
 
+// jsonGraph is the document written by `--format=json`, and read back by `--diff`.
+type jsonGraph struct {
+	Config              map[string]interface{} `json:"config"`
+	Dependencies        map[string][]string    `json:"dependencies"`
+	ReverseDependencies map[string][]string    `json:"reverseDependencies"`
+
+	// Weights are the numbers of distinct symbols of the imported packages referenced by the
+	// importers (from -> to -> weight), with --weights.
+	Weights map[string]map[string]int `json:"weights,omitempty"`
+}
+
 func (v *graphVisitor) WriteJSON(w io.Writer, startPkg, direction string) error {
-	type jsonOutput struct {
-		Config              map[string]interface{} `json:"config"`
-		Dependencies        map[string][]string    `json:"dependencies"`
-		ReverseDependencies map[string][]string    `json:"reverseDependencies"`
-	}
 
 	sortMap := func(m map[string][]string) map[string][]string {
 		sortedMap := make(map[string][]string, len(m))
@@ -625,7 +850,7 @@ func (v *graphVisitor) WriteJSON(w io.Writer, startPkg, direction string) error
 		}
 		var filteredToList []string
 		for _, to := range toList {
-			if !v.isHidden(to) {
+			if !v.isHidden(to) && !v.isTrivial(from, to) {
 				filteredToList = append(filteredToList, to)
 			}
 		}
@@ -641,7 +866,7 @@ func (v *graphVisitor) WriteJSON(w io.Writer, startPkg, direction string) error
 		}
 		var filteredToList []string
 		for _, to := range toList {
-			if !v.isHidden(to) {
+			if !v.isHidden(to) && !v.isTrivial(from, to) {
 				filteredToList = append(filteredToList, to)
 			}
 		}
@@ -650,7 +875,7 @@ func (v *graphVisitor) WriteJSON(w io.Writer, startPkg, direction string) error
 		}
 	}
 
-	output := jsonOutput{
+	output := jsonGraph{
 		Config: map[string]interface{}{
 			"startPkg":  startPkg,
 			"direction": direction,
@@ -659,6 +884,19 @@ func (v *graphVisitor) WriteJSON(w io.Writer, startPkg, direction string) error
 		Dependencies:        sortMap(filteredDeps),
 		ReverseDependencies: sortMap(filteredRevDeps),
 	}
+	if v.weights != nil {
+		output.Weights = make(map[string]map[string]int)
+		for _, m := range []map[string][]string{filteredDeps, filteredRevDeps} {
+			for from, toList := range m {
+				for _, to := range toList {
+					if output.Weights[from] == nil {
+						output.Weights[from] = make(map[string]int)
+					}
+					output.Weights[from][to] = v.weights[edge{From: from, To: to}]
+				}
+			}
+		}
+	}
 
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"os"
 	"path/filepath"
@@ -224,6 +225,32 @@ func TestRun(t *testing.T) {
 			},
 			goldenFile: "hide-c-short.golden",
 		},
+		{
+			name: "style-dot",
+			args: map[string]interface{}{
+				"start-pkgs": []string{"github.com/podhmo/go-scan/testdata/walk/a"},
+				"hops":       4,
+				"format":     "dot",
+				"full":       false,
+				"short":      true,
+				"ignore":     "",
+				"style":      "b=#aaccff@core,re:walk/[cd]$=lightyellow@core,e=lightgrey@leaf",
+			},
+			goldenFile: "style.golden",
+		},
+		{
+			name: "style-mermaid",
+			args: map[string]interface{}{
+				"start-pkgs": []string{"github.com/podhmo/go-scan/testdata/walk/a"},
+				"hops":       4,
+				"format":     "mermaid",
+				"full":       false,
+				"short":      true,
+				"ignore":     "",
+				"style":      "b=#aaccff@core,re:walk/[cd]$=lightyellow@core,e=lightgrey@leaf",
+			},
+			goldenFile: "style-mermaid.golden",
+		},
 		{
 			name: "full",
 			args: map[string]interface{}{
@@ -330,6 +357,18 @@ func TestRun(t *testing.T) {
 			},
 			goldenFile: "multiple.golden",
 		},
+		{
+			name: "diff",
+			args: map[string]interface{}{
+				"start-pkgs": []string{"github.com/podhmo/go-scan/testdata/walk/a"},
+				"hops":       2,
+				"full":       false,
+				"short":      false,
+				"ignore":     "",
+				"diff":       "default-json.golden",
+			},
+			goldenFile: "diff-hops2.golden",
+		},
 	}
 
 	for _, tc := range cases {
@@ -392,24 +431,34 @@ func TestRun(t *testing.T) {
 				hide = ""
 			}
 
-			err = run(
-				context.Background(),
-				startPkgs,
-				tc.args["hops"].(int),
-				tc.args["ignore"].(string),
-				hide,
-				outputFile,
-				format,
-				granularity,
-				tc.args["full"].(bool),
-				tc.args["short"].(bool),
-				direction,
-				aggressive,
-				test,
-				false, // dryRun
-				false, // inspect
-				nil,   // logger
-			)
+			style, ok := tc.args["style"].(string)
+			if !ok {
+				style = ""
+			}
+
+			// The old graph for diff mode is read from the original testdata directory.
+			diff, ok := tc.args["diff"].(string)
+			if ok {
+				diff = filepath.Join(originalWD, "testdata", diff)
+			}
+
+			cfg := runConfig{
+				StartPkgs:   startPkgs,
+				Hops:        tc.args["hops"].(int),
+				Ignore:      tc.args["ignore"].(string),
+				Hide:        hide,
+				Output:      outputFile,
+				Format:      format,
+				Granularity: granularity,
+				Full:        tc.args["full"].(bool),
+				Short:       tc.args["short"].(bool),
+				Direction:   direction,
+				Aggressive:  aggressive,
+				Test:        test,
+				Diff:        diff,
+				Style:       style,
+			}
+			err = run(context.Background(), cfg, nil)
 			if err != nil {
 				t.Fatalf("run() failed unexpectedly: %+v", err)
 			}
@@ -452,3 +501,215 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+func TestRunForbiddenEdges(t *testing.T) {
+	testdataFiles := loadTestdata(t, "testdata/walk")
+
+	cases := []struct {
+		name    string
+		diff    string
+		forbid  string
+		wantErr bool
+	}{
+		{name: "no violation", forbid: "a->c"},
+		{name: "violation", forbid: "b->c", wantErr: true},
+		{name: "violation with full path", forbid: "github.com/podhmo/go-scan/testdata/walk/b->github.com/podhmo/go-scan/testdata/walk/c", wantErr: true},
+		{name: "existing edge is not reported in diff mode", diff: "default-json.golden", forbid: "a->b"},
+		{name: "added edge is reported in diff mode", diff: "default-json.golden", forbid: "b->c", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpdir, cleanup := scantest.WriteFiles(t, testdataFiles)
+			defer cleanup()
+
+			originalWD, err := os.Getwd()
+			if err != nil {
+				t.Fatalf("failed to get wd: %v", err)
+			}
+			if err := os.Chdir(tmpdir); err != nil {
+				t.Fatalf("failed to change wd to tmpdir: %v", err)
+			}
+			defer os.Chdir(originalWD)
+
+			diff := tc.diff
+			if diff != "" {
+				diff = filepath.Join(originalWD, "testdata", diff)
+			}
+
+			cfg := runConfig{
+				StartPkgs:   []string{"github.com/podhmo/go-scan/testdata/walk/a"},
+				Hops:        2,
+				Output:      filepath.Join(tmpdir, "output.txt"),
+				Format:      "json",
+				Granularity: "package",
+				Direction:   "forward",
+				Diff:        diff,
+				Forbid:      tc.forbid,
+			}
+			err = run(context.Background(), cfg, nil)
+			if tc.wantErr && err == nil {
+				t.Fatal("expected an error for forbidden edges, but got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("run() failed unexpectedly: %+v", err)
+			}
+		})
+	}
+}
+
+func TestRunLicenses(t *testing.T) {
+	files := map[string]string{
+		"app/go.mod": `module example.com/app
+
+go 1.22
+
+require (
+	example.com/lib v1.2.0
+	example.com/local v0.0.0
+)
+
+replace example.com/local => ../local
+`,
+		"app/main.go": `package main
+
+import (
+	_ "example.com/lib/sub"
+	_ "example.com/local"
+)
+`,
+		"modcache/example.com/lib@v1.2.0/LICENSE":    "MIT License\n\nPermission is hereby granted, free of charge, to any person obtaining a copy\n",
+		"modcache/example.com/lib@v1.2.0/lib.go":     "package lib\n",
+		"modcache/example.com/lib@v1.2.0/sub/sub.go": "package sub\n\nimport _ \"example.com/lib\"\n",
+		"local/go.mod":   "module example.com/local\n",
+		"local/local.go": "package local\n",
+	}
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+	t.Setenv("GOMODCACHE", filepath.Join(tmpdir, "modcache"))
+
+	originalWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get wd: %v", err)
+	}
+	if err := os.Chdir(filepath.Join(tmpdir, "app")); err != nil {
+		t.Fatalf("failed to change wd to tmpdir: %v", err)
+	}
+	defer os.Chdir(originalWD)
+
+	outputFile := filepath.Join(tmpdir, "output.txt")
+	cfg := runConfig{
+		StartPkgs:   []string{"example.com/app"},
+		Hops:        3,
+		Output:      outputFile,
+		Format:      "dot",
+		Granularity: "package",
+		Direction:   "forward",
+		Licenses:    true,
+	}
+	err = run(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("run() failed unexpectedly: %+v", err)
+	}
+
+	got, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	want := `MODULE             VERSION  LICENSE  PACKAGES
+example.com/lib    v1.2.0   MIT      2
+example.com/local  -        none     1
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunWeights(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"main.go": `package main
+
+import (
+	_ "example.com/app/side"
+	"example.com/app/lib"
+	u "example.com/app/util"
+)
+
+func main() {
+	lib.A()
+	lib.A()
+	_ = lib.B{}
+	u.X()
+}
+`,
+		"lib/lib.go":   "package lib\n\nfunc A() {}\n\ntype B struct{}\n",
+		"util/util.go": "package util\n\nfunc X() {}\n",
+		"side/side.go": "package side\n",
+	}
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	originalWD, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get wd: %v", err)
+	}
+	if err := os.Chdir(tmpdir); err != nil {
+		t.Fatalf("failed to change wd to tmpdir: %v", err)
+	}
+	defer os.Chdir(originalWD)
+
+	runWith := func(t *testing.T, format string, minWeight int) string {
+		t.Helper()
+		outputFile := filepath.Join(tmpdir, "output.txt")
+		cfg := runConfig{
+			StartPkgs:   []string{"example.com/app"},
+			Hops:        1,
+			Output:      outputFile,
+			Format:      format,
+			Granularity: "package",
+			Short:       true,
+			Direction:   "forward",
+			Weights:     true,
+			MinWeight:   minWeight,
+		}
+		err := run(context.Background(), cfg, nil)
+		if err != nil {
+			t.Fatalf("run() failed unexpectedly: %+v", err)
+		}
+		got, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("failed to read output: %v", err)
+		}
+		return string(got)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var got jsonGraph
+		if err := json.Unmarshal([]byte(runWith(t, "json", 0)), &got); err != nil {
+			t.Fatalf("failed to unmarshal the output: %v", err)
+		}
+		want := map[string]map[string]int{
+			"example.com/app": {
+				"example.com/app/lib":  2,
+				"example.com/app/side": 0,
+				"example.com/app/util": 1,
+			},
+		}
+		if diff := cmp.Diff(want, got.Weights); diff != "" {
+			t.Errorf("weights mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("dot with min-weight", func(t *testing.T) {
+		got := runWith(t, "dot", 2)
+		if want := `"example.com/app" -> "example.com/app/lib" [penwidth=2, label="2"];`; !strings.Contains(got, want) {
+			t.Errorf("want the edge %s in\n%s", want, got)
+		}
+		for _, trivial := range []string{"example.com/app/side", "example.com/app/util"} {
+			if strings.Contains(got, `-> "`+trivial+`"`) {
+				t.Errorf("the trivial edge to %s is not hidden:\n%s", trivial, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// styleConfig is the content of the JSON file given by `--style`.
+type styleConfig struct {
+	Rules []styleRule `json:"rules"`
+}
+
+// styleRule assigns a color and/or a cluster to the nodes matching its pattern,
+// e.g. `*/internal/*` drawn in grey inside an "internal" cluster.
+type styleRule struct {
+	// Pattern is a glob pattern, with the same syntax as `--ignore`, matched against the full
+	// import path or the path relative to the module.
+	Pattern string `json:"pattern,omitempty"`
+	// Regexp is a regular expression matched against the full import path.
+	Regexp string `json:"regexp,omitempty"`
+	// Color is the fill color of the node (a Graphviz color name, or "#rrggbb").
+	Color string `json:"color,omitempty"`
+	// Cluster is the name of the group the node is drawn in.
+	Cluster string `json:"cluster,omitempty"`
+
+	re *regexp.Regexp
+}
+
+func (r *styleRule) String() string {
+	if r.re != nil {
+		return "re:" + r.Regexp
+	}
+	return r.Pattern
+}
+
+func (r *styleRule) match(nodePath, modulePath string) bool {
+	if r.re != nil {
+		return r.re.MatchString(nodePath)
+	}
+	return matchPackagePattern(r.Pattern, nodePath, modulePath)
+}
+
+// parseStyleRules parses the value of `--style`. It is either the path of a JSON file
+// (`{"rules": [{"pattern": "*/internal/*", "color": "grey", "cluster": "internal"}]}`), or a
+// comma-separated list of rules in `<pattern>=<color>[@<cluster>]` form. A pattern prefixed
+// with `re:` is a regular expression.
+func parseStyleRules(s string) ([]*styleRule, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var rules []*styleRule
+	if strings.HasSuffix(s, ".json") {
+		data, err := os.ReadFile(s)
+		if err != nil {
+			return nil, fmt.Errorf("reading style file: %w", err)
+		}
+		var config styleConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("decoding style file %s: %w", s, err)
+		}
+		for i := range config.Rules {
+			rules = append(rules, &config.Rules[i])
+		}
+	} else {
+		for _, raw := range strings.Split(s, ",") {
+			raw = strings.TrimSpace(raw)
+			if raw == "" {
+				continue
+			}
+			pattern, attrs, ok := strings.Cut(raw, "=")
+			if !ok || strings.TrimSpace(pattern) == "" {
+				return nil, fmt.Errorf("invalid style rule %q, expected <pattern>=<color>[@<cluster>]", raw)
+			}
+			color, cluster, _ := strings.Cut(attrs, "@")
+			rule := &styleRule{Color: strings.TrimSpace(color), Cluster: strings.TrimSpace(cluster)}
+			if re, found := strings.CutPrefix(strings.TrimSpace(pattern), "re:"); found {
+				rule.Regexp = re
+			} else {
+				rule.Pattern = strings.TrimSpace(pattern)
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	for _, rule := range rules {
+		if (rule.Pattern == "") == (rule.Regexp == "") {
+			return nil, fmt.Errorf("style rule %+v must have exactly one of pattern or regexp", *rule)
+		}
+		if rule.Color == "" && rule.Cluster == "" {
+			return nil, fmt.Errorf("style rule %q has neither a color nor a cluster", rule.String())
+		}
+		if rule.Regexp != "" {
+			re, err := regexp.Compile(rule.Regexp)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regexp in style rule %q: %w", rule.Regexp, err)
+			}
+			rule.re = re
+		} else if _, err := filepath.Match(rule.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern in style rule %q: %w", rule.Pattern, err)
+		}
+	}
+	return rules, nil
+}
+
+// styleOf returns the first style rule matching the node, or nil.
+func (v *graphVisitor) styleOf(nodePath string) *styleRule {
+	modulePath := v.s.ModulePath()
+	for _, rule := range v.styleRules {
+		if rule.match(nodePath, modulePath) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// clusterOrder returns the names of the clusters in the order of the rules declaring them.
+func (v *graphVisitor) clusterOrder() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, rule := range v.styleRules {
+		if rule.Cluster != "" && !seen[rule.Cluster] {
+			seen[rule.Cluster] = true
+			names = append(names, rule.Cluster)
+		}
+	}
+	return names
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	goscan "github.com/podhmo/go-scan"
+)
+
+// maxPenWidth caps the thickness of the heaviest edges in the DOT output.
+const maxPenWidth = 8
+
+// usageIndex is a reference index of the importing files: the distinct symbols of each imported
+// package referenced in a file, as `name.Symbol` selectors. Blank and dot imports reference none.
+type usageIndex struct {
+	s     *goscan.Scanner
+	fset  *token.FileSet
+	files map[string]map[string]map[string]bool // file -> import path -> symbols
+	names map[string]string                     // import path -> package name
+}
+
+func newUsageIndex(s *goscan.Scanner) *usageIndex {
+	return &usageIndex{
+		s:     s,
+		fset:  token.NewFileSet(),
+		files: make(map[string]map[string]map[string]bool),
+		names: make(map[string]string),
+	}
+}
+
+// weight returns the number of distinct symbols of the imported package referenced by the files.
+func (idx *usageIndex) weight(ctx context.Context, files []string, imported string) (int, error) {
+	symbols := make(map[string]bool)
+	for _, file := range files {
+		usage, err := idx.fileUsage(ctx, file)
+		if err != nil {
+			return 0, err
+		}
+		for sym := range usage[imported] {
+			symbols[sym] = true
+		}
+	}
+	return len(symbols), nil
+}
+
+// fileUsage returns the symbols referenced by the file, keyed by import path.
+func (idx *usageIndex) fileUsage(ctx context.Context, filename string) (map[string]map[string]bool, error) {
+	if usage, ok := idx.files[filename]; ok {
+		return usage, nil
+	}
+	f, err := parser.ParseFile(idx.fset, filename, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+
+	imports := make(map[string]string) // local name -> import path
+	for _, imp := range f.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := idx.packageName(ctx, importPath)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name != "_" && name != "." {
+			imports[name] = importPath
+		}
+	}
+
+	usage := make(map[string]map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		// A local variable shadowing the package name is counted as well, which is rare enough.
+		if x, ok := sel.X.(*ast.Ident); ok {
+			if importPath, ok := imports[x.Name]; ok {
+				if usage[importPath] == nil {
+					usage[importPath] = make(map[string]bool)
+				}
+				usage[importPath][sel.Sel.Name] = true
+			}
+		}
+		return true
+	})
+	idx.files[filename] = usage
+	return usage, nil
+}
+
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// packageName returns the name of the imported package, guessed from its import path if the
+// package cannot be found.
+func (idx *usageIndex) packageName(ctx context.Context, importPath string) string {
+	if name, ok := idx.names[importPath]; ok {
+		return name
+	}
+	var name string
+	if pkg, err := idx.s.Walker.ScanPackageFromFilePathImports(ctx, importPath); err == nil && pkg.Name != "" {
+		name = pkg.Name
+	} else {
+		name = path.Base(importPath)
+		if majorVersionSuffix.MatchString(name) {
+			name = path.Base(path.Dir(importPath))
+		}
+		name = strings.TrimPrefix(name, "go-")
+		if i := strings.Index(name, "."); i > 0 {
+			name = name[:i] // e.g. gopkg.in/yaml.v3
+		}
+		name = strings.ReplaceAll(name, "-", "")
+	}
+	idx.names[importPath] = name
+	return name
+}
+
+// computeWeights weighs every edge of the graph by the number of distinct symbols of the
+// imported package referenced by the importer.
+func (v *graphVisitor) computeWeights(ctx context.Context) error {
+	if v.weights == nil {
+		v.weights = make(map[edge]int)
+	}
+	idx := newUsageIndex(v.s)
+	add := func(m map[string][]string) error {
+		for from, toList := range m {
+			for _, to := range toList {
+				e := edge{From: from, To: to}
+				if _, ok := v.weights[e]; ok {
+					continue
+				}
+				files, err := v.importingFiles(ctx, from, to)
+				if err != nil {
+					return err
+				}
+				w, err := idx.weight(ctx, files, to)
+				if err != nil {
+					return err
+				}
+				v.weights[e] = w
+			}
+		}
+		return nil
+	}
+	if err := add(v.dependencies); err != nil {
+		return err
+	}
+	return add(v.reverseDependencies)
+}
+
+// importingFiles returns the files of the importer that import the package, the importer itself
+// with the file granularity.
+func (v *graphVisitor) importingFiles(ctx context.Context, importer, imported string) ([]string, error) {
+	if v.granularity == "file" {
+		return []string{importer}, nil
+	}
+	pkg, err := v.s.Walker.ScanPackageFromFilePathImports(ctx, importer)
+	if err != nil {
+		return nil, fmt.Errorf("scanning imports of %s: %w", importer, err)
+	}
+	var files []string
+	for file, imports := range pkg.FileImports {
+		for _, imp := range imports {
+			if imp == imported {
+				files = append(files, file)
+				break
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// isTrivial reports whether the edge is lighter than --min-weight, and so hidden from the output.
+func (v *graphVisitor) isTrivial(from, to string) bool {
+	return v.minWeight > 0 && v.weights[edge{From: from, To: to}] < v.minWeight
+}
+
+// penWidth returns the thickness of an edge of the given weight in the DOT output.
+func penWidth(weight int) int {
+	return max(1, min(weight, maxPenWidth))
+}
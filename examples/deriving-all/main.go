@@ -11,8 +11,6 @@ import (
 	"strings"
 
 	goscan "github.com/podhmo/go-scan"
-	bindgen "github.com/podhmo/go-scan/examples/derivingbind/gen"
-	jsongen "github.com/podhmo/go-scan/examples/derivingjson/gen"
 	"github.com/podhmo/go-scan/scanner"
 	"golang.org/x/tools/imports"
 )
@@ -32,6 +30,7 @@ func main() {
 		cwd      string
 		dryRun   bool
 		inspect  bool
+		list     bool
 		logLevel = slog.LevelWarn
 	)
 
@@ -39,6 +38,11 @@ func main() {
 	flag.BoolVar(&dryRun, "dry-run", false, "don't write files, just print to stdout")
 	flag.BoolVar(&inspect, "inspect", false, "enable inspection logging for annotations")
 	flag.TextVar(&logLevel, "log-level", &logLevel, "set log level (debug, info, warn, error)")
+	flag.BoolVar(&list, "list-generators", false, "list the available generators and exit")
+	enabled := make(map[string]*bool)
+	for _, g := range defaultRegistry.Generators() {
+		enabled[g.Name] = flag.Bool(g.Name, true, fmt.Sprintf("enable the %s generator (@%s)", g.Name, strings.Join(g.Annotations, ", @")))
+	}
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: deriving-all [options] <file_or_dir_path_1> [file_or_dir_path_2 ...]\n")
 		flag.PrintDefaults()
@@ -50,6 +54,13 @@ func main() {
 	slog.SetDefault(logger)
 
 	ctx := context.Background()
+	if list {
+		if err := defaultRegistry.PrintGenerators(os.Stdout); err != nil {
+			slog.ErrorContext(ctx, "Failed to list generators", slog.Any("error", err))
+			os.Exit(1)
+		}
+		return
+	}
 	if len(flag.Args()) == 0 {
 		flag.Usage()
 		os.Exit(1)
@@ -88,10 +99,7 @@ func main() {
 
 	var successCount, errorCount int
 
-	generators := []GeneratorFunc{
-		jsongen.Generate,
-		bindgen.Generate,
-	}
+	isEnabled := func(name string) bool { return *enabled[name] }
 
 	processPackage := func(pkgInfo *scanner.PackageInfo) {
 		if pkgInfo == nil {
@@ -104,10 +112,10 @@ func main() {
 		var masterCode bytes.Buffer
 		var totalErrors []error
 
-		for _, generate := range generators {
-			code, err := generate(ctx, gscn, pkgInfo, importManager)
+		for _, g := range defaultRegistry.Dispatch(ctx, pkgInfo, isEnabled) {
+			code, err := g.Generate(ctx, gscn, pkgInfo, importManager)
 			if err != nil {
-				totalErrors = append(totalErrors, err)
+				totalErrors = append(totalErrors, fmt.Errorf("%s: %w", g.Name, err))
 				continue
 			}
 			if len(code) > 0 {
@@ -10,8 +10,6 @@ import (
 	"github.com/podhmo/go-scan/scantest"
 
 	goscan "github.com/podhmo/go-scan"
-	bindgen "github.com/podhmo/go-scan/examples/derivingbind/gen"
-	jsongen "github.com/podhmo/go-scan/examples/derivingjson/gen"
 	"github.com/podhmo/go-scan/scanner"
 )
 
@@ -122,16 +120,11 @@ type User struct {
 				}
 				pkgInfo := pkgs[0]
 
-				generators := []GeneratorFunc{
-					jsongen.Generate,
-					bindgen.Generate,
-				}
-
 				importManager := goscan.NewImportManager(pkgInfo)
 				var masterCode bytes.Buffer
 
-				for _, generate := range generators {
-					code, err := generate(ctx, s, pkgInfo, importManager)
+				for _, g := range defaultRegistry.Dispatch(ctx, pkgInfo, nil) {
+					code, err := g.Generate(ctx, s, pkgInfo, importManager)
 					if err != nil {
 						return fmt.Errorf("generator failed: %w", err)
 					}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+
+	bindgen "github.com/podhmo/go-scan/examples/derivingbind/gen"
+	jsongen "github.com/podhmo/go-scan/examples/derivingjson/gen"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// Generator is a code generator run for the packages with a type annotated with one of its annotations.
+type Generator struct {
+	Name        string   // The name of the generator, also used for its enable/disable flag, e.g. "json".
+	Annotations []string // The annotations handled by the generator, without "@", e.g. "deriving:unmarshal".
+	Description string
+	Generate    GeneratorFunc
+}
+
+// Registry holds the generators, in the order they were registered, which is the order they are run in.
+type Registry struct {
+	generators []*Generator
+	byName     map[string]*Generator
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]*Generator)}
+}
+
+// Register adds a generator. The name must be unique and at least one annotation is required.
+func (r *Registry) Register(g *Generator) error {
+	if g.Name == "" || g.Generate == nil {
+		return fmt.Errorf("generator must have a name and a generate function")
+	}
+	if len(g.Annotations) == 0 {
+		return fmt.Errorf("generator %q handles no annotation", g.Name)
+	}
+	if _, ok := r.byName[g.Name]; ok {
+		return fmt.Errorf("generator %q is already registered", g.Name)
+	}
+	r.generators = append(r.generators, g)
+	r.byName[g.Name] = g
+	return nil
+}
+
+// Generators returns the registered generators.
+func (r *Registry) Generators() []*Generator {
+	return r.generators
+}
+
+// Dispatch returns the enabled generators to run for the package, i.e. those handling an annotation
+// found on one of its types, in the order of registration. enabled may be nil to enable all of them.
+func (r *Registry) Dispatch(ctx context.Context, pkgInfo *scanner.PackageInfo, enabled func(name string) bool) []*Generator {
+	found := make(map[string]bool)
+	for _, typeInfo := range pkgInfo.Types {
+		for _, g := range r.generators {
+			for _, annotation := range g.Annotations {
+				if _, ok := typeInfo.Annotation(ctx, annotation); ok {
+					slog.DebugContext(ctx, "dispatching type to generator", "type", typeInfo.Name, "annotation", annotation, "generator", g.Name)
+					found[g.Name] = true
+				}
+			}
+		}
+	}
+
+	var selected []*Generator
+	for _, g := range r.generators {
+		if !found[g.Name] {
+			continue
+		}
+		if enabled != nil && !enabled(g.Name) {
+			slog.InfoContext(ctx, "skipping disabled generator", "generator", g.Name, "package", pkgInfo.ImportPath)
+			continue
+		}
+		selected = append(selected, g)
+	}
+	return selected
+}
+
+// PrintGenerators writes the registered generators with their annotations, one per line.
+func (r *Registry) PrintGenerators(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, g := range r.generators {
+		annotations := make([]string, len(g.Annotations))
+		for i, a := range g.Annotations {
+			annotations[i] = "@" + a
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", g.Name, strings.Join(annotations, ", "), g.Description)
+	}
+	return tw.Flush()
+}
+
+// defaultRegistry holds the generators built into deriving-all.
+// A new generator is added by registering it in an init function.
+var defaultRegistry = NewRegistry()
+
+// Register adds a generator to the default registry. It panics if the generator is invalid
+// or a generator with the same name is already registered.
+func Register(g *Generator) {
+	if err := defaultRegistry.Register(g); err != nil {
+		panic(err)
+	}
+}
+
+func init() {
+	Register(&Generator{
+		Name:        "json",
+		Annotations: []string{"deriving:unmarshal", "deriving:marshal"},
+		Description: "UnmarshalJSON for the oneOf interface fields and MarshalJSON with a discriminator",
+		Generate:    jsongen.Generate,
+	})
+	Register(&Generator{
+		Name:        "binding",
+		Annotations: []string{"deriving:binding"},
+		Description: "Bind for binding the fields from an HTTP request",
+		Generate:    bindgen.Generate,
+	})
+}
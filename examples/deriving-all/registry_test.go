@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestRegistry(t *testing.T) {
+	noop := func(context.Context, *goscan.Scanner, *scanner.PackageInfo, *goscan.ImportManager) ([]byte, error) {
+		return nil, nil
+	}
+
+	t.Run("register", func(t *testing.T) {
+		r := NewRegistry()
+		if err := r.Register(&Generator{Name: "a", Annotations: []string{"deriving:a"}, Generate: noop}); err != nil {
+			t.Fatalf("Register() failed: %v", err)
+		}
+		if err := r.Register(&Generator{Name: "a", Annotations: []string{"deriving:other"}, Generate: noop}); err == nil {
+			t.Error("expected an error for a duplicated name")
+		}
+		if err := r.Register(&Generator{Name: "b", Generate: noop}); err == nil {
+			t.Error("expected an error for a generator without annotations")
+		}
+		if got := len(r.Generators()); got != 1 {
+			t.Errorf("expected 1 generator, got %d", got)
+		}
+	})
+
+	t.Run("dispatch", func(t *testing.T) {
+		dir, cleanup := scantest.WriteFiles(t, map[string]string{
+			"go.mod": "module mytest",
+			"models.go": `
+package models
+
+// @deriving:a
+type A struct{}
+
+// @deriving:c
+type C struct{}
+
+type Plain struct{}
+`,
+		})
+		defer cleanup()
+
+		r := NewRegistry()
+		for _, name := range []string{"a", "b", "c"} {
+			if err := r.Register(&Generator{Name: name, Annotations: []string{"deriving:" + name}, Generate: noop}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var dispatched [][]string
+		action := func(ctx context.Context, s *goscan.Scanner, pkgs []*scanner.PackageInfo) error {
+			for _, enabled := range []func(string) bool{nil, func(name string) bool { return name != "a" }} {
+				var names []string
+				for _, g := range r.Dispatch(ctx, pkgs[0], enabled) {
+					names = append(names, g.Name)
+				}
+				dispatched = append(dispatched, names)
+			}
+			return nil
+		}
+		if _, err := scantest.Run(t, context.Background(), dir, []string{"."}, action); err != nil {
+			t.Fatalf("scantest.Run failed: %v", err)
+		}
+
+		if got := strings.Join(dispatched[0], ","); got != "a,c" {
+			t.Errorf("all enabled: want a,c, got %s", got)
+		}
+		if got := strings.Join(dispatched[1], ","); got != "c" {
+			t.Errorf("a disabled: want c, got %s", got)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := defaultRegistry.PrintGenerators(&buf); err != nil {
+			t.Fatalf("PrintGenerators() failed: %v", err)
+		}
+		for _, want := range []string{"json", "@deriving:unmarshal, @deriving:marshal", "binding", "@deriving:binding"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("expected the list to contain %q, got:\n%s", want, buf.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package binding
+
+// Body is the source of the values decoded from the JSON request body. It is used only in the
+// descriptors of the parameters, as the body is not looked up by key.
+const Body Source = "body"
+
+// Parameter describes a value bound by a generated Bind method, so that documentation tools
+// (e.g. docgen) can link a request struct to its parameters without analyzing the method.
+type Parameter struct {
+	// Field is the name of the struct field, or "" for a struct decoded from the body as a whole.
+	Field string `json:"field,omitempty"`
+	In    Source `json:"in"`
+	// Name is the name of the parameter in the request, e.g. "X-Auth-Token", or "" for the body.
+	Name string `json:"name,omitempty"`
+	// Type is the Go type of the field, e.g. "[]int" or "*string".
+	Type string `json:"type"`
+	// Schema is the OpenAPI type of the value: "string", "integer", "number", "boolean",
+	// "array" or "object". Items is the OpenAPI type of the elements of an array.
+	Schema string `json:"schema"`
+	Items  string `json:"items,omitempty"`
+	// Required is true for the fields tagged with `required:"true"`, and for the path
+	// parameters, which OpenAPI requires.
+	Required bool `json:"required,omitempty"`
+}
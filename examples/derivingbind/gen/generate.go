@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"text/template"
 
 	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/examples/derivingbind/binding"
 	"github.com/podhmo/go-scan/scanner"
 )
 
@@ -48,10 +50,33 @@ type FieldBindingInfo struct {
 	IsSliceElementPointer   bool
 }
 
+// StructParameters are the parameters bound by the Bind method of a struct.
+type StructParameters struct {
+	Name       string              `json:"name"`
+	Parameters []binding.Parameter `json:"parameters"`
+}
+
+// PackageParameters are the parameters of the structs of a package with Bind methods, the
+// machine-readable descriptor written next to the generated code for documentation tools.
+type PackageParameters struct {
+	Package string             `json:"package"` // the import path
+	Structs []StructParameters `json:"structs"`
+}
+
+// Generate generates the Bind methods of the structs of the package annotated with @deriving:binding.
 func Generate(ctx context.Context, gscn *goscan.Scanner, pkgInfo *scanner.PackageInfo, importManager *goscan.ImportManager) ([]byte, error) {
+	code, _, err := GenerateWithParameters(ctx, gscn, pkgInfo, importManager, false)
+	return code, err
+}
+
+// GenerateWithParameters is Generate, also returning the parameters bound by each Bind method,
+// in the order of the structs. With parameterVars, the code also declares them as a
+// `var <Struct>BindingParameters = []binding.Parameter{...}` after each method.
+func GenerateWithParameters(ctx context.Context, gscn *goscan.Scanner, pkgInfo *scanner.PackageInfo, importManager *goscan.ImportManager, parameterVars bool) ([]byte, *PackageParameters, error) {
 	if pkgInfo == nil {
-		return nil, fmt.Errorf("cannot generate code for a nil package")
+		return nil, nil, fmt.Errorf("cannot generate code for a nil package")
 	}
+	params := &PackageParameters{Package: pkgInfo.ImportPath}
 	var generatedCodeForAllStructs bytes.Buffer
 	anyCodeGenerated := false
 
@@ -227,20 +252,103 @@ func Generate(ctx context.Context, gscn *goscan.Scanner, pkgInfo *scanner.Packag
 		funcMap := template.FuncMap{"TitleCase": strings.Title}
 		tmpl, err := template.New("bind").Funcs(funcMap).Parse(bindMethodTemplateString)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse template: %w", err)
+			return nil, nil, fmt.Errorf("failed to parse template: %w", err)
 		}
 		var currentGeneratedCode bytes.Buffer
 		if err := tmpl.Execute(&currentGeneratedCode, data); err != nil {
-			return nil, fmt.Errorf("failed to execute template for struct %s: %w", typeInfo.Name, err)
+			return nil, nil, fmt.Errorf("failed to execute template for struct %s: %w", typeInfo.Name, err)
 		}
 		generatedCodeForAllStructs.Write(currentGeneratedCode.Bytes())
 		generatedCodeForAllStructs.WriteString("\n\n")
+
+		structParams := StructParameters{Name: typeInfo.Name, Parameters: parametersOf(data)}
+		params.Structs = append(params.Structs, structParams)
+		if parameterVars {
+			importManager.Add("github.com/podhmo/go-scan/examples/derivingbind/binding", "")
+			writeParameterVar(&generatedCodeForAllStructs, structParams)
+		}
 	}
 
 	if !anyCodeGenerated {
 		slog.InfoContext(ctx, "No structs found requiring Bind method generation in package", slog.String("package_path", pkgInfo.Path))
-		return nil, nil
+		return nil, nil, nil
 	}
 
-	return generatedCodeForAllStructs.Bytes(), nil
+	return generatedCodeForAllStructs.Bytes(), params, nil
+}
+
+// parametersOf returns the parameters bound by the Bind method of the struct, the body of a
+// struct decoded as a whole first.
+func parametersOf(data TemplateData) []binding.Parameter {
+	params := []binding.Parameter{}
+	if data.NeedsBody && !data.HasSpecificBodyFieldTarget {
+		params = append(params, binding.Parameter{In: binding.Body, Type: data.StructName, Schema: "object"})
+	}
+	for _, f := range data.Fields {
+		p := binding.Parameter{
+			Field:    f.FieldName,
+			In:       binding.Source(f.BindFrom),
+			Name:     f.BindName,
+			Type:     f.OriginalFieldTypeString,
+			Schema:   schemaOf(f.FieldType),
+			Required: f.IsRequired || f.BindFrom == "path",
+		}
+		if f.IsBody {
+			p.Name = ""
+		}
+		if f.IsSlice {
+			p.Schema, p.Items = "array", p.Schema
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// schemaOf returns the OpenAPI type of a value of the Go type (without pointers and slices).
+func schemaOf(goType string) string {
+	switch goType {
+	case "string", "complex64", "complex128":
+		return "string"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr":
+		return "integer"
+	case "float32", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	}
+	return "object"
+}
+
+// sourceConstants are the names of the constants of the binding package for the sources.
+var sourceConstants = map[binding.Source]string{
+	binding.Path:   "binding.Path",
+	binding.Query:  "binding.Query",
+	binding.Header: "binding.Header",
+	binding.Cookie: "binding.Cookie",
+	binding.Body:   "binding.Body",
+}
+
+// writeParameterVar writes the declaration of the parameters of a struct.
+func writeParameterVar(w *bytes.Buffer, s StructParameters) {
+	fmt.Fprintf(w, "// %sBindingParameters are the parameters bound by (*%s).Bind.\n", s.Name, s.Name)
+	fmt.Fprintf(w, "var %sBindingParameters = []binding.Parameter{\n", s.Name)
+	for _, p := range s.Parameters {
+		fields := []string{}
+		if p.Field != "" {
+			fields = append(fields, "Field: "+strconv.Quote(p.Field))
+		}
+		fields = append(fields, "In: "+sourceConstants[p.In])
+		if p.Name != "" {
+			fields = append(fields, "Name: "+strconv.Quote(p.Name))
+		}
+		fields = append(fields, "Type: "+strconv.Quote(p.Type), "Schema: "+strconv.Quote(p.Schema))
+		if p.Items != "" {
+			fields = append(fields, "Items: "+strconv.Quote(p.Items))
+		}
+		if p.Required {
+			fields = append(fields, "Required: true")
+		}
+		fmt.Fprintf(w, "\t{%s},\n", strings.Join(fields, ", "))
+	}
+	w.WriteString("}\n\n")
 }
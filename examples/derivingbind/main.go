@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"go/format"
@@ -18,15 +19,17 @@ import (
 
 func main() {
 	var (
-		cwd      string
-		dryRun   bool
-		inspect  bool
-		logLevel = slog.LevelWarn
+		cwd        string
+		dryRun     bool
+		inspect    bool
+		parameters string
+		logLevel   = slog.LevelWarn
 	)
 
 	flag.StringVar(&cwd, "cwd", ".", "current working directory")
 	flag.BoolVar(&dryRun, "dry-run", false, "don't write files, just print to stdout")
 	flag.BoolVar(&inspect, "inspect", false, "enable inspection logging for annotations")
+	flag.StringVar(&parameters, "parameters", "", `also emit the descriptors of the bound parameters, for documentation tools: "go" (a <Struct>BindingParameters var per struct) or "json" (a <package>_parameters.json file)`)
 	flag.TextVar(&logLevel, "log-level", &logLevel, "set log level (debug, info, warn, error)")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: derivingbind [options] <file_or_dir_path_1> [file_or_dir_path_2 ...]\n")
@@ -45,6 +48,10 @@ func main() {
 		flag.Usage()
 		os.Exit(1)
 	}
+	if parameters != "" && parameters != "go" && parameters != "json" {
+		slog.ErrorContext(ctx, `Invalid -parameters, want "go" or "json"`, slog.String("parameters", parameters))
+		os.Exit(1)
+	}
 
 	scannerOptions := []goscan.ScannerOption{
 		goscan.WithWorkDir(cwd),
@@ -87,7 +94,7 @@ func main() {
 		}
 
 		importManager := goscan.NewImportManager(pkgInfo)
-		code, err := gen.Generate(ctx, gscn, pkgInfo, importManager)
+		code, params, err := gen.GenerateWithParameters(ctx, gscn, pkgInfo, importManager, parameters == "go")
 		if err != nil {
 			slog.ErrorContext(ctx, "Error generating code for package", "path", pkgInfo.Path, slog.Any("error", err))
 			errorCount++
@@ -108,6 +115,13 @@ func main() {
 		}
 
 		outputFilename := fmt.Sprintf("%s_deriving.go", strings.ToLower(pkgInfo.Name))
+		if parameters == "json" {
+			if err := writeParameters(ctx, gscn.DryRun, filepath.Join(outputDir.Path, fmt.Sprintf("%s_parameters.json", strings.ToLower(pkgInfo.Name))), params); err != nil {
+				slog.ErrorContext(ctx, "Failed to write the parameters of package", "path", pkgInfo.Path, slog.Any("error", err))
+				errorCount++
+				return
+			}
+		}
 		if gscn.DryRun {
 			slog.InfoContext(ctx, "Dry run: skipping file write", "path", filepath.Join(outputDir.Path, outputFilename))
 			fmt.Fprintf(os.Stdout, "---\n// file: %s\n---\n", filepath.Join(outputDir.Path, outputFilename))
@@ -178,3 +192,18 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// writeParameters writes the descriptor of the parameters of a package as JSON, or prints it with dryRun.
+func writeParameters(ctx context.Context, dryRun bool, path string, params *gen.PackageParameters) error {
+	data, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if dryRun {
+		fmt.Fprintf(os.Stdout, "---\n// file: %s\n---\n", path)
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return goscan.WriteFile(ctx, path, data, 0644)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/examples/derivingbind/binding"
 	"github.com/podhmo/go-scan/examples/derivingbind/gen"
 	"github.com/podhmo/go-scan/scanner"
 	"github.com/podhmo/go-scan/scantest"
@@ -125,3 +126,57 @@ func (s *Input) Bind(req *http.Request, pathVar func(string) string) error {
 		})
 	}
 }
+
+func TestGenerateWithParameters(t *testing.T) {
+	files := map[string]string{
+		"go.mod": `
+module github.com/podhmo/go-scan/examples/derivingbind/testdata/params
+go 1.22.4
+`,
+		"models.go": `
+package models
+// @deriving:binding
+type Input struct {
+	ID   string   ` + "`in:\"path\" path:\"id\"`" + `
+	Tags []string ` + "`in:\"query\" query:\"tag\"`" + `
+	Size *int     ` + "`in:\"query\" query:\"size\" required:\"true\"`" + `
+	Body Payload  ` + "`in:\"body\"`" + `
+}
+type Payload struct {
+	Title string
+}
+`,
+	}
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	var code []byte
+	var params *gen.PackageParameters
+	action := func(ctx context.Context, s *goscan.Scanner, pkgs []*scanner.PackageInfo) error {
+		var err error
+		code, params, err = gen.GenerateWithParameters(ctx, s, pkgs[0], goscan.NewImportManager(pkgs[0]), true)
+		return err
+	}
+	if _, err := scantest.Run(t, context.Background(), tmpdir, []string{"."}, action); err != nil {
+		t.Fatalf("scantest.Run failed: %+v", err)
+	}
+
+	want := &gen.PackageParameters{
+		Package: "github.com/podhmo/go-scan/examples/derivingbind/testdata/params",
+		Structs: []gen.StructParameters{{
+			Name: "Input",
+			Parameters: []binding.Parameter{
+				{Field: "ID", In: binding.Path, Name: "id", Type: "string", Schema: "string", Required: true},
+				{Field: "Tags", In: binding.Query, Name: "tag", Type: "[]string", Schema: "array", Items: "string"},
+				{Field: "Size", In: binding.Query, Name: "size", Type: "*int", Schema: "integer", Required: true},
+				{Field: "Body", In: binding.Body, Type: "Payload", Schema: "object"},
+			},
+		}},
+	}
+	if diff := cmp.Diff(want, params); diff != "" {
+		t.Errorf("parameters mismatch (-want +got):\n%s", diff)
+	}
+	if !strings.Contains(string(code), "var InputBindingParameters = []binding.Parameter{") {
+		t.Errorf("want the parameters var in the generated code, got:\n%s", code)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"go/ast"
 	"log/slog"
 	"path/filepath"
 	"strings"
@@ -20,6 +21,49 @@ var templateFile embed.FS
 const unmarshalAnnotation = "deriving:unmarshal"
 const marshalAnnotation = "deriving:marshal"
 
+const defaultDiscriminator = "type"
+
+// annotationOptions parses the options of an annotation, e.g.
+// `@deriving:unmarshal discriminator:"kind" packages:"example.com/m/plugins"`.
+// The quotes of the values are optional.
+func annotationOptions(value string) map[string]string {
+	options := make(map[string]string)
+	for _, part := range strings.Fields(value) {
+		k, v, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+		options[k] = strings.Trim(v, `"`)
+	}
+	return options
+}
+
+// discriminatorValue returns the value of the discriminator for an implementer of a oneOf
+// interface: the value option of its marshal annotation, e.g. `@deriving:marshal value:"user_profile"`,
+// or else the lowercased name of the type, without the "Event" suffix.
+func discriminatorValue(ctx context.Context, typeInfo *scanner.TypeInfo) string {
+	if annotation, ok := typeInfo.Annotation(ctx, marshalAnnotation); ok {
+		if v := annotationOptions(annotation)["value"]; v != "" {
+			return v
+		}
+	}
+	return strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(typeInfo.Name, "*"), "Event"))
+}
+
+// hasUnexportedMethods reports whether an interface has unexported methods, which only the types
+// of its own package can implement.
+func hasUnexportedMethods(interfaceDef *scanner.TypeInfo) bool {
+	if interfaceDef.Interface == nil {
+		return false
+	}
+	for _, m := range interfaceDef.Interface.Methods {
+		if !ast.IsExported(m.Name) {
+			return true
+		}
+	}
+	return false
+}
+
 type TemplateData struct {
 	StructName                 string
 	OtherFields                []FieldInfo
@@ -71,15 +115,26 @@ func Generate(ctx context.Context, gscn *goscan.Scanner, pkgInfo *scanner.Packag
 		if typeInfo.Kind != scanner.StructKind || typeInfo.Struct == nil {
 			continue
 		}
-		if _, ok := typeInfo.Annotation(ctx, unmarshalAnnotation); !ok {
+		annotation, ok := typeInfo.Annotation(ctx, unmarshalAnnotation)
+		if !ok {
 			continue
 		}
+		options := annotationOptions(annotation)
 
 		data := TemplateData{
 			StructName:                 typeInfo.Name,
 			OneOfFields:                []OneOfFieldDetail{},
 			OtherFields:                []FieldInfo{},
-			DiscriminatorFieldJSONName: "type", // Default discriminator
+			DiscriminatorFieldJSONName: defaultDiscriminator,
+		}
+		if v := options["discriminator"]; v != "" {
+			data.DiscriminatorFieldJSONName = v
+		}
+		// The implementers may be defined in other packages than those of the struct and the
+		// interface, e.g. plugins, listed by import path in the packages option.
+		var extraPkgPaths []string
+		if v := options["packages"]; v != "" {
+			extraPkgPaths = strings.Split(v, ",")
 		}
 
 		for _, field := range typeInfo.Struct.Fields {
@@ -148,16 +203,36 @@ func Generate(ctx context.Context, gscn *goscan.Scanner, pkgInfo *scanner.Packag
 						slog.WarnContext(ctx, "Failed to scan interface's defining package", "importPath", interfaceDefiningPkgImportPath, "error", errScan)
 					}
 				}
+				for _, extraPkgPath := range extraPkgPaths {
+					extraPkgPath = strings.TrimSpace(extraPkgPath)
+					if extraPkgPath == "" || isPackageInSlice(searchPkgs, extraPkgPath) {
+						continue
+					}
+					extraPkg, errScan := gscn.ScanPackageFromImportPath(ctx, extraPkgPath)
+					if errScan != nil {
+						return nil, fmt.Errorf("failed to scan package %q for the implementers of %s in %s.%s: %w", extraPkgPath, interfaceDef.Name, typeInfo.Name, field.Name, errScan)
+					}
+					searchPkgs = append(searchPkgs, extraPkg)
+				}
+				unexportedMethods := hasUnexportedMethods(interfaceDef)
 
 				processedImplementerKeys := make(map[string]bool)
+				implementerByValue := make(map[string]string)
 				for _, currentSearchPkg := range searchPkgs {
 					if currentSearchPkg == nil {
 						continue
 					}
+					otherPkg := currentSearchPkg.ImportPath != "" && currentSearchPkg.ImportPath != pkgInfo.ImportPath
 					for _, candidateType := range currentSearchPkg.Types {
 						if candidateType.Kind != scanner.StructKind || candidateType.Struct == nil {
 							continue
 						}
+						if otherPkg && !ast.IsExported(candidateType.Name) {
+							continue // Not accessible from the generated code.
+						}
+						if unexportedMethods && currentSearchPkg.ImportPath != interfaceDefiningPkgImportPath {
+							continue
+						}
 						implementerKey := currentSearchPkg.ImportPath + "." + candidateType.Name
 						if processedImplementerKeys[implementerKey] {
 							continue
@@ -165,7 +240,7 @@ func Generate(ctx context.Context, gscn *goscan.Scanner, pkgInfo *scanner.Packag
 						if gscn.Implements(ctx, candidateType, interfaceDef) {
 							processedImplementerKeys[implementerKey] = true
 							var goTypeString string
-							if currentSearchPkg.ImportPath != "" && currentSearchPkg.ImportPath != pkgInfo.ImportPath {
+							if otherPkg {
 								goTypeString = importManager.Qualify(currentSearchPkg.ImportPath, candidateType.Name)
 							} else {
 								goTypeString = candidateType.Name
@@ -173,10 +248,14 @@ func Generate(ctx context.Context, gscn *goscan.Scanner, pkgInfo *scanner.Packag
 							if !strings.HasPrefix(goTypeString, "*") {
 								goTypeString = "*" + goTypeString
 							}
-							discriminatorValue := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(candidateType.Name, "*"), "Event"))
+							value := discriminatorValue(ctx, candidateType)
+							if other, ok := implementerByValue[value]; ok {
+								return nil, fmt.Errorf("discriminator value %q of %s.%s is used by both %s and %s", value, typeInfo.Name, field.Name, other, goTypeString)
+							}
+							implementerByValue[value] = goTypeString
 
 							oneOfDetail.Implementers = append(oneOfDetail.Implementers, OneOfTypeMapping{
-								JSONValue: discriminatorValue,
+								JSONValue: value,
 								GoType:    goTypeString,
 							})
 						}
@@ -229,16 +308,24 @@ func Generate(ctx context.Context, gscn *goscan.Scanner, pkgInfo *scanner.Packag
 		if typeInfo.Kind != scanner.StructKind || typeInfo.Struct == nil {
 			continue
 		}
-		if _, ok := typeInfo.Annotation(ctx, marshalAnnotation); !ok {
+		annotation, ok := typeInfo.Annotation(ctx, marshalAnnotation)
+		if !ok {
 			continue
 		}
+		options := annotationOptions(annotation)
 
 		// Prepare data for the marshaling template
 		marshalData := MarshalTemplateData{
 			StructName:                 typeInfo.Name,
-			DiscriminatorFieldJSONName: "type", // Hardcoded for now
+			DiscriminatorFieldJSONName: defaultDiscriminator,
 			DiscriminatorValue:         strings.ToLower(typeInfo.Name),
 		}
+		if v := options["discriminator"]; v != "" {
+			marshalData.DiscriminatorFieldJSONName = v
+		}
+		if v := options["value"]; v != "" {
+			marshalData.DiscriminatorValue = v
+		}
 
 		// Generate code using the marshal template
 		tmpl, err := template.ParseFS(templateFile, "marshal.tmpl")
@@ -7,60 +7,6 @@ import (
 	fmt "fmt"
 )
 
-func (s *APIResponse) UnmarshalJSON(data []byte) error {
-	// Define an alias type to prevent infinite recursion with UnmarshalJSON.
-	type Alias APIResponse
-	aux := &struct {
-		Data json.RawMessage `json:"data"`
-
-		// All other fields will be handled by the standard unmarshaler via the Alias.
-		*Alias
-	}{
-		Alias: (*Alias)(s),
-	}
-
-	if err := json.Unmarshal(data, &aux); err != nil {
-		return fmt.Errorf("failed to unmarshal into aux struct for APIResponse: %w", err)
-	}
-
-	// Process Data
-	if aux.Data != nil && string(aux.Data) != "null" {
-		var discriminatorDoc struct {
-			Type string `json:"type"` // Discriminator field
-		}
-		if err := json.Unmarshal(aux.Data, &discriminatorDoc); err != nil {
-			return fmt.Errorf("could not detect type from field 'data' (content: %s): %w", string(aux.Data), err)
-		}
-
-		switch discriminatorDoc.Type {
-
-		case "userprofile":
-			var content *UserProfile
-			if err := json.Unmarshal(aux.Data, &content); err != nil {
-				return fmt.Errorf("failed to unmarshal 'data' as *UserProfile for type 'userprofile' (content: %s): %w", string(aux.Data), err)
-			}
-			s.Data = content
-
-		case "productinfo":
-			var content *ProductInfo
-			if err := json.Unmarshal(aux.Data, &content); err != nil {
-				return fmt.Errorf("failed to unmarshal 'data' as *ProductInfo for type 'productinfo' (content: %s): %w", string(aux.Data), err)
-			}
-			s.Data = content
-
-		default:
-			if discriminatorDoc.Type == "" {
-				return fmt.Errorf("discriminator field 'type' missing or empty in 'data' (content: %s)", string(aux.Data))
-			}
-			return fmt.Errorf("unknown data type '%s' for field 'data' (content: %s)", discriminatorDoc.Type, string(aux.Data))
-		}
-	} else {
-		s.Data = nil // Explicitly set to nil if null or empty
-	}
-
-	return nil
-}
-
 func (s *Scene) UnmarshalJSON(data []byte) error {
 	// Define an alias type to prevent infinite recursion with UnmarshalJSON.
 	type Alias Scene
@@ -327,26 +273,58 @@ func (s *PetOwner) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (s *UserProfile) MarshalJSON() ([]byte, error) {
-	type Alias UserProfile
-	return json.Marshal(&struct {
-		*Alias
-		Type string `json:"type"`
-	}{
-		Alias: (*Alias)(s),
-		Type:  "userprofile",
-	})
-}
+func (s *APIResponse) UnmarshalJSON(data []byte) error {
+	// Define an alias type to prevent infinite recursion with UnmarshalJSON.
+	type Alias APIResponse
+	aux := &struct {
+		Data json.RawMessage `json:"data"`
 
-func (s *ProductInfo) MarshalJSON() ([]byte, error) {
-	type Alias ProductInfo
-	return json.Marshal(&struct {
+		// All other fields will be handled by the standard unmarshaler via the Alias.
 		*Alias
-		Type string `json:"type"`
 	}{
 		Alias: (*Alias)(s),
-		Type:  "productinfo",
-	})
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return fmt.Errorf("failed to unmarshal into aux struct for APIResponse: %w", err)
+	}
+
+	// Process Data
+	if aux.Data != nil && string(aux.Data) != "null" {
+		var discriminatorDoc struct {
+			Type string `json:"type"` // Discriminator field
+		}
+		if err := json.Unmarshal(aux.Data, &discriminatorDoc); err != nil {
+			return fmt.Errorf("could not detect type from field 'data' (content: %s): %w", string(aux.Data), err)
+		}
+
+		switch discriminatorDoc.Type {
+
+		case "user_profile":
+			var content *UserProfile
+			if err := json.Unmarshal(aux.Data, &content); err != nil {
+				return fmt.Errorf("failed to unmarshal 'data' as *UserProfile for type 'user_profile' (content: %s): %w", string(aux.Data), err)
+			}
+			s.Data = content
+
+		case "product_info":
+			var content *ProductInfo
+			if err := json.Unmarshal(aux.Data, &content); err != nil {
+				return fmt.Errorf("failed to unmarshal 'data' as *ProductInfo for type 'product_info' (content: %s): %w", string(aux.Data), err)
+			}
+			s.Data = content
+
+		default:
+			if discriminatorDoc.Type == "" {
+				return fmt.Errorf("discriminator field 'type' missing or empty in 'data' (content: %s)", string(aux.Data))
+			}
+			return fmt.Errorf("unknown data type '%s' for field 'data' (content: %s)", discriminatorDoc.Type, string(aux.Data))
+		}
+	} else {
+		s.Data = nil // Explicitly set to nil if null or empty
+	}
+
+	return nil
 }
 
 func (s *Dog) MarshalJSON() ([]byte, error) {
@@ -403,3 +381,25 @@ func (s *Goldfish) MarshalJSON() ([]byte, error) {
 		Type:  "goldfish",
 	})
 }
+
+func (s *UserProfile) MarshalJSON() ([]byte, error) {
+	type Alias UserProfile
+	return json.Marshal(&struct {
+		*Alias
+		Type string `json:"type"`
+	}{
+		Alias: (*Alias)(s),
+		Type:  "user_profile",
+	})
+}
+
+func (s *ProductInfo) MarshalJSON() ([]byte, error) {
+	type Alias ProductInfo
+	return json.Marshal(&struct {
+		*Alias
+		Type string `json:"type"`
+	}{
+		Alias: (*Alias)(s),
+		Type:  "product_info",
+	})
+}
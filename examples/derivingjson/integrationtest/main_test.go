@@ -13,12 +13,8 @@ import (
 func TestUnmarshalAPIResponse(t *testing.T) {
 	fmt.Println("--- Unmarshaling Demo ---")
 
-	// NOTE: The JSON samples have been slightly modified from the initial user request.
-	// The "type" field values ("userprofile", "productinfo") match what the current
-	// generator.go is expected to produce as discriminator values (lowercase of type name).
-	// The original samples used "user_profile" and "product_info".
-	// To make the generator use the original values, its discriminator detection logic
-	// would need to be enhanced (e.g., by reading a struct tag or calling a method).
+	// The "type" field values ("user_profile", "product_info") are given by the value option
+	// of the @deriving:marshal annotations of the implementers.
 	jsonSamples := map[string]struct {
 		jsonString       string
 		wantErr          bool
@@ -27,13 +23,13 @@ func TestUnmarshalAPIResponse(t *testing.T) {
 		// We can add more specific checks for Data content if needed
 	}{
 		"Case 1: UserProfile": {
-			jsonString:       `{"status":"ok","data":{"type":"userprofile","userId":"u-abc","userName":"Jiro"}}`,
+			jsonString:       `{"status":"ok","data":{"type":"user_profile","userId":"u-abc","userName":"Jiro"}}`,
 			wantErr:          false,
 			expectedStatus:   "ok",
 			expectedDataType: reflect.TypeOf(&UserProfile{}),
 		},
 		"Case 2: ProductInfo": {
-			jsonString:       `{"status":"ok","data":{"type":"productinfo","productId":"p-xyz","productName":"Mouse","price":5000}}`,
+			jsonString:       `{"status":"ok","data":{"type":"product_info","productId":"p-xyz","productName":"Mouse","price":5000}}`,
 			wantErr:          false,
 			expectedStatus:   "ok",
 			expectedDataType: reflect.TypeOf(&ProductInfo{}),
@@ -428,7 +424,7 @@ func TestMarshalAPIResponse(t *testing.T) {
 		fmt.Printf("Marshaled JSON (UserProfile): %s\n", jsonString)
 
 		// Check for discriminator and other fields.
-		if !strings.Contains(jsonString, `"type":"userprofile"`) {
+		if !strings.Contains(jsonString, `"type":"user_profile"`) {
 			t.Errorf("marshaled JSON does not contain type discriminator for UserProfile")
 		}
 		if !strings.Contains(jsonString, `"userId":"u-123"`) {
@@ -454,7 +450,7 @@ func TestMarshalAPIResponse(t *testing.T) {
 		jsonString := string(b)
 		fmt.Printf("Marshaled JSON (ProductInfo): %s\n", jsonString)
 
-		if !strings.Contains(jsonString, `"type":"productinfo"`) {
+		if !strings.Contains(jsonString, `"type":"product_info"`) {
 			t.Errorf("marshaled JSON does not contain type discriminator for ProductInfo")
 		}
 		if !strings.Contains(jsonString, `"productId":"p-456"`) {
@@ -17,9 +17,9 @@ type APIResponse struct {
 
 // UserProfile is one of the possible types for the Data field.
 // It represents a user's profile information.
-// @deriving:marshal
+// @deriving:marshal value:"user_profile"
 type UserProfile struct {
-	Type     string `json:"type"` // Discriminator: "user_profile" (expected value based on original user request)
+	Type     string `json:"type"` // Discriminator: "user_profile"
 	UserID   string `json:"userId"`
 	UserName string `json:"userName"`
 }
@@ -29,9 +29,9 @@ func (UserProfile) isData() {}
 
 // ProductInfo is another possible type for the Data field.
 // It represents information about a product.
-// @deriving:marshal
+// @deriving:marshal value:"product_info"
 type ProductInfo struct {
-	Type        string `json:"type"` // Discriminator: "product_info" (expected value based on original user request)
+	Type        string `json:"type"` // Discriminator: "product_info"
 	ProductID   string `json:"productId"`
 	ProductName string `json:"productName"`
 	Price       int    `json:"price"`
@@ -39,14 +39,3 @@ type ProductInfo struct {
 
 // isData implements the DataInterface for ProductInfo.
 func (ProductInfo) isData() {}
-
-// Notes on discriminator values for the generator:
-// The current generator logic (as of the last check) uses:
-//   discriminatorValue = strings.ToLower(candidateType.Name)
-// This would result in "userprofile" for UserProfile and "productinfo" for ProductInfo.
-// The test JSON in main_test.go has been adjusted to use these lowercase values for now
-// to allow testing of the UnmarshalJSON structure.
-// For the generator to use "user_profile" and "product_info" (as in the struct tags here),
-// the generator's logic for determining `JSONValue` in `OneOfTypeMapping` would need to be enhanced
-// to inspect struct tags (e.g., from the 'Type' field) or a method on the implementing types.
-// This is a known TODO in the generator code.
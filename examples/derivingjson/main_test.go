@@ -26,9 +26,10 @@ func TestGenerate(t *testing.T) {
 		Code string
 	}
 	cases := []struct {
-		name  string
-		files map[string]string
-		want  want
+		name    string
+		files   map[string]string
+		pattern string // the package to generate the code for, "." if empty
+		want    want
 	}{
 		{
 			name: "simple",
@@ -129,6 +130,146 @@ func (s *Event) UnmarshalJSON(data []byte) error {
 
 	return nil
 }
+`,
+			},
+		},
+		{
+			name: "discriminator options and implementers in other packages",
+			files: map[string]string{
+				"go.mod": `
+module example.com/m
+go 1.22.4
+`,
+				"models/models.go": `
+package models
+
+import "example.com/m/shapes"
+
+// @deriving:unmarshal discriminator:"kind" packages:"example.com/m/plugins"
+type Container struct {
+	Content shapes.Shape ` + "`json:\"content\"`" + `
+}
+
+// @deriving:marshal discriminator:"kind" value:"box"
+type Box struct {
+	Size int ` + "`json:\"size\"`" + `
+}
+
+func (b *Box) Area() int { return b.Size * b.Size }
+`,
+				"shapes/shapes.go": `
+package shapes
+
+type Shape interface {
+	Area() int
+}
+
+// @deriving:marshal discriminator:"kind" value:"circle_v1"
+type Circle struct {
+	Radius int ` + "`json:\"radius\"`" + `
+}
+
+func (c *Circle) Area() int { return 3 * c.Radius * c.Radius }
+`,
+				"plugins/plugins.go": `
+package plugins
+
+type Square struct {
+	Side int ` + "`json:\"side\"`" + `
+}
+
+func (s *Square) Area() int { return s.Side * s.Side }
+
+// unexported types are not accessible from the generated code.
+type triangle struct{}
+
+func (t *triangle) Area() int { return 0 }
+`,
+			},
+			pattern: "./models",
+			want: want{
+				Code: `// Code generated by go-scan for package models. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	plugins "example.com/m/plugins"
+	shapes "example.com/m/shapes"
+	fmt "fmt"
+)
+
+func (s *Container) UnmarshalJSON(data []byte) error {
+	// Define an alias type to prevent infinite recursion with UnmarshalJSON.
+	type Alias Container
+	aux := &struct {
+		Content json.RawMessage ` + "`json:\"content\"`" + `
+
+		// All other fields will be handled by the standard unmarshaler via the Alias.
+		*Alias
+	}{
+		Alias: (*Alias)(s),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return fmt.Errorf("failed to unmarshal into aux struct for Container: %w", err)
+	}
+
+	// Process Content
+	if aux.Content != nil && string(aux.Content) != "null" {
+		var discriminatorDoc struct {
+			Type string ` + "`json:\"kind\"`" + ` // Discriminator field
+		}
+		if err := json.Unmarshal(aux.Content, &discriminatorDoc); err != nil {
+			return fmt.Errorf("could not detect type from field 'content' (content: %s): %w", string(aux.Content), err)
+		}
+
+		switch discriminatorDoc.Type {
+
+		case "box":
+			var content *Box
+			if err := json.Unmarshal(aux.Content, &content); err != nil {
+				return fmt.Errorf("failed to unmarshal 'content' as *Box for type 'box' (content: %s): %w", string(aux.Content), err)
+			}
+			s.Content = content
+
+		case "circle_v1":
+			var content *shapes.Circle
+			if err := json.Unmarshal(aux.Content, &content); err != nil {
+				return fmt.Errorf("failed to unmarshal 'content' as *shapes.Circle for type 'circle_v1' (content: %s): %w", string(aux.Content), err)
+			}
+			s.Content = content
+
+		case "square":
+			var content *plugins.Square
+			if err := json.Unmarshal(aux.Content, &content); err != nil {
+				return fmt.Errorf("failed to unmarshal 'content' as *plugins.Square for type 'square' (content: %s): %w", string(aux.Content), err)
+			}
+			s.Content = content
+
+		default:
+			if discriminatorDoc.Type == "" {
+				return fmt.Errorf("discriminator field 'kind' missing or empty in 'content' (content: %s)", string(aux.Content))
+			}
+			return fmt.Errorf("unknown data type '%s' for field 'content' (content: %s)", discriminatorDoc.Type, string(aux.Content))
+		}
+	} else {
+		s.Content = nil // Explicitly set to nil if null or empty
+	}
+
+	return nil
+}
+
+func (s *Box) MarshalJSON() ([]byte, error) {
+	type Alias Box
+	return json.Marshal(&struct {
+		*Alias
+		Type string ` + "`json:\"kind\"`" + `
+	}{
+		Alias: (*Alias)(s),
+		Type:  "box",
+	})
+}
 `,
 			},
 		},
@@ -160,7 +301,11 @@ func (s *Event) UnmarshalJSON(data []byte) error {
 				return outputDir.SaveGoFile(ctx, goFile, "models_deriving.go")
 			}
 
-			result, err := scantest.Run(t, context.Background(), tmpdir, []string{"."}, action)
+			pattern := tc.pattern
+			if pattern == "" {
+				pattern = "."
+			}
+			result, err := scantest.Run(t, context.Background(), tmpdir, []string{pattern}, action)
 			if err != nil {
 				t.Fatalf("scantest.Run failed: %+v", err)
 			}
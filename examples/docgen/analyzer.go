@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"go/token"
 	"log/slog"
 	"strings"
 
@@ -21,6 +22,9 @@ type Analyzer struct {
 	tracer         symgo.Tracer // Optional tracer
 	operationStack []*openapi.Operation
 	customPatterns []patterns.Pattern
+
+	operationIDStrategy string
+	operationID         operationIDGenerator
 }
 
 // Option is a functional option for configuring the Analyzer.
@@ -33,22 +37,21 @@ func WithTracer(tracer symgo.Tracer) Option {
 	}
 }
 
+// WithOperationIDStrategy sets how the operationIds are generated: OperationIDFunc (the default),
+// OperationIDMethodPath, or a text/template executed with OperationIDData, e.g.
+// `{{.Func}}` or `{{lower .Method}}{{slug .Path}}`.
+func WithOperationIDStrategy(strategy string) Option {
+	return func(a *Analyzer) {
+		a.operationIDStrategy = strategy
+	}
+}
+
 // NewAnalyzer creates a new Analyzer.
 func NewAnalyzer(s *goscan.Scanner, logger *slog.Logger, extraPkgs []string, options ...any) (*Analyzer, error) {
 	a := &Analyzer{
 		Scanner: s,
 		logger:  logger,
-		OpenAPI: &openapi.OpenAPI{
-			OpenAPI: "3.1.0",
-			Info: openapi.Info{
-				Title:   "Sample API",
-				Version: "0.0.1",
-			},
-			Paths: make(map[string]*openapi.PathItem),
-			Components: &openapi.Components{
-				Schemas: make(map[string]*openapi.Schema),
-			},
-		},
+		OpenAPI: newOpenAPI(),
 	}
 
 	// Process options
@@ -66,6 +69,12 @@ func NewAnalyzer(s *goscan.Scanner, logger *slog.Logger, extraPkgs []string, opt
 		}
 	}
 
+	operationID, err := newOperationIDGenerator(a.operationIDStrategy)
+	if err != nil {
+		return nil, err
+	}
+	a.operationID = operationID
+
 	// Define the analysis scopes.
 	// Primary scope includes the workspace modules and any extra packages specified via -include-pkg.
 	primaryScope := make([]string, 0, len(s.Modules())+len(extraPkgs))
@@ -119,6 +128,21 @@ func NewAnalyzer(s *goscan.Scanner, logger *slog.Logger, extraPkgs []string, opt
 	return a, nil
 }
 
+// newOpenAPI creates an empty OpenAPI document to be filled by the analysis.
+func newOpenAPI() *openapi.OpenAPI {
+	return &openapi.OpenAPI{
+		OpenAPI: "3.1.0",
+		Info: openapi.Info{
+			Title:   "Sample API",
+			Version: "0.0.1",
+		},
+		Paths: make(map[string]*openapi.PathItem),
+		Components: &openapi.Components{
+			Schemas: make(map[string]*openapi.Schema),
+		},
+	}
+}
+
 func (a *Analyzer) OperationStack() []*openapi.Operation {
 	return a.operationStack
 }
@@ -190,6 +214,7 @@ func (a *Analyzer) Analyze(ctx context.Context, importPath string, entrypoint st
 		return fmt.Errorf("error during entrypoint apply: %w", err)
 	}
 
+	uniqueOperationIDs(a.OpenAPI)
 	return nil
 }
 
@@ -237,8 +262,8 @@ func (a *Analyzer) analyzeHandle(ctx context.Context, interp *symgo.Interpreter,
 		return &symgo.Error{Message: fmt.Sprintf("Handle expects 3 arguments, but got %d", len(args))}
 	}
 
-	patternObj, ok := args[1].(*symgo.String)
-	if !ok {
+	patternObj := args[1]
+	if _, _, ok := routePattern(patternObj); !ok {
 		return &symgo.Error{Message: fmt.Sprintf("Handle pattern argument must be a string, but got %T", args[1])}
 	}
 
@@ -281,8 +306,8 @@ func (a *Analyzer) analyzeHandleFunc(ctx context.Context, interp *symgo.Interpre
 	}
 
 	// Arg 0 is the receiver, which we can ignore.
-	// Arg 1 is the pattern string.
-	patternObj, ok := args[1].(*symgo.String)
+	// Arg 1 is the pattern string, possibly built from non-constant parts.
+	pattern, wildcards, ok := routePattern(args[1])
 	if !ok {
 		return &symgo.Error{Message: fmt.Sprintf("HandleFunc pattern argument must be a string, but got %T", args[1])}
 	}
@@ -294,7 +319,6 @@ func (a *Analyzer) analyzeHandleFunc(ctx context.Context, interp *symgo.Interpre
 		return &symgo.Error{Message: fmt.Sprintf("HandleFunc handler argument must be a function, but got %T", args[2])}
 	}
 
-	pattern := patternObj.Value
 	method, path, _ := strings.Cut(pattern, " ")
 	if path == "" {
 		path = method
@@ -307,15 +331,11 @@ func (a *Analyzer) analyzeHandleFunc(ctx context.Context, interp *symgo.Interpre
 		return nil
 	}
 
-	// Generate a unique operation ID based on package and function name.
 	pkgPath := handlerObj.Package.ImportPath
-	pkgPathForID := strings.ReplaceAll(pkgPath, "/", "_")
-	pkgPathForID = strings.ReplaceAll(pkgPathForID, ".", "_")
-	parts := strings.Split(pkgPathForID, "_")
-	if len(parts) > 2 {
-		pkgPathForID = strings.Join(parts[len(parts)-2:], "_")
+	operationID, err := a.operationID(OperationIDData{Method: method, Path: path, Package: pkgPath, Func: handlerDecl.Name.Name})
+	if err != nil {
+		return &symgo.Error{Message: err.Error()}
 	}
-	operationID := fmt.Sprintf("%s_%s", pkgPathForID, handlerDecl.Name.Name)
 
 	op := &openapi.Operation{
 		OperationID: operationID,
@@ -328,34 +348,73 @@ func (a *Analyzer) analyzeHandleFunc(ctx context.Context, interp *symgo.Interpre
 	if handlerDecl.Body != nil {
 		op = a.analyzeHandlerBody(ctx, handlerObj, op)
 	}
+	for _, name := range wildcards {
+		if !hasPathParameter(op, name) {
+			op.Parameters = append(op.Parameters, &openapi.Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &openapi.Schema{Type: "string"},
+			})
+		}
+	}
+	if fset := handlerObj.Package.Fset; fset != nil {
+		pos := fset.Position(handlerDecl.Pos())
+		op.Handler = &openapi.Handler{Name: pkgPath + "." + handlerDecl.Name.Name, File: pos.Filename, Line: pos.Line}
+	}
 
 	if a.OpenAPI.Paths[path] == nil {
 		a.OpenAPI.Paths[path] = &openapi.PathItem{}
 	}
-	pathItem := a.OpenAPI.Paths[path]
-
-	switch method {
-	case "GET":
-		pathItem.Get = op
-	case "POST":
-		pathItem.Post = op
-	case "PUT":
-		pathItem.Put = op
-	case "DELETE":
-		pathItem.Delete = op
-	case "PATCH":
-		pathItem.Patch = op
-	case "HEAD":
-		pathItem.Head = op
-	case "OPTIONS":
-		pathItem.Options = op
-	case "TRACE":
-		pathItem.Trace = op
-	}
+	a.OpenAPI.Paths[path].SetOperation(method, op)
 
 	return nil
 }
 
+// routePattern returns the pattern of a route registration, e.g. "GET /users". A pattern built
+// from non-constant parts, e.g. `"GET /" + tenant + "/users"`, is a string template, and each
+// of its holes becomes a path wildcard (`/{tenant}/users`), also returned as wildcards.
+func routePattern(obj symgo.Object) (pattern string, wildcards []string, ok bool) {
+	switch v := obj.(type) {
+	case *symgo.String:
+		return v.Value, nil, true
+	case *symgo.StringTemplate:
+		var b strings.Builder
+		for _, p := range v.Parts {
+			if p.Const {
+				b.WriteString(p.Value)
+				continue
+			}
+			name := wildcardName(p.Name, len(wildcards)+1)
+			wildcards = append(wildcards, name)
+			b.WriteString("{" + name + "}")
+		}
+		return b.String(), wildcards, true
+	}
+	return "", nil, false
+}
+
+// wildcardName returns the name of a path wildcard for the source expression of a hole:
+// the last identifier of `version` or `cfg.Version`, and "paramN" for other expressions.
+func wildcardName(expr string, n int) string {
+	if i := strings.LastIndex(expr, "."); i >= 0 {
+		expr = expr[i+1:]
+	}
+	if token.IsIdentifier(expr) {
+		return expr
+	}
+	return fmt.Sprintf("param%d", n)
+}
+
+func hasPathParameter(op *openapi.Operation, name string) bool {
+	for _, p := range op.Parameters {
+		if p.In == "path" && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // analyzeHandlerBody analyzes the body of an HTTP handler function to find
 // request and response schemas.
 func (a *Analyzer) analyzeHandlerBody(ctx context.Context, handler *symgo.Function, op *openapi.Operation) *openapi.Operation {
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+)
+
+func TestDocgen_dynamicRoutes(t *testing.T) {
+	// This test verifies that the route patterns built from constants are folded,
+	// and the patterns built from values unknown at analysis time become path templates.
+	const apiPath = "dynamicroutes"
+	moduleDir := "testdata/dynamic-routes"
+	goldenFile := "testdata/dynamic-routes.golden.json"
+
+	logger := newTestLogger(io.Discard)
+	s, err := goscan.New(
+		goscan.WithWorkDir(moduleDir),
+		goscan.WithGoModuleResolver(),
+		goscan.WithLogger(logger),
+	)
+	if err != nil {
+		t.Fatalf("failed to create scanner: %v", err)
+	}
+	analyzer, err := NewAnalyzer(s, logger, nil)
+	if err != nil {
+		t.Fatalf("failed to create analyzer: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := analyzer.Analyze(ctx, apiPath, "main"); err != nil {
+		t.Fatalf("failed to analyze package: %+v", err)
+	}
+
+	var got bytes.Buffer
+	enc := json.NewEncoder(&got)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(analyzer.OpenAPI); err != nil {
+		t.Fatalf("failed to marshal OpenAPI spec to json: %v", err)
+	}
+
+	if *update {
+		if err := os.WriteFile(goldenFile, got.Bytes(), 0644); err != nil {
+			t.Fatalf("failed to write golden file %s: %v", goldenFile, err)
+		}
+		t.Logf("golden file updated: %s", goldenFile)
+	}
+
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s: %v", goldenFile, err)
+	}
+	if diff := cmp.Diff(string(want), got.String()); diff != "" {
+		t.Errorf("OpenAPI spec mismatch (-want +got):\n%s", diff)
+	}
+}
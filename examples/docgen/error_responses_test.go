@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/examples/docgen/patterns"
+)
+
+func TestDocgen_errorResponses(t *testing.T) {
+	// This test verifies that the status codes passed to http.Error, directly or through
+	// a helper mapping sentinel errors, and to a custom error helper detected by a pattern
+	// are documented as error responses.
+	const apiPath = "error-responses"
+	moduleDir := "testdata/error-responses"
+	goldenFile := "testdata/error-responses.golden.json"
+
+	logger := newTestLogger(io.Discard)
+	s, err := goscan.New(
+		goscan.WithWorkDir(moduleDir),
+		goscan.WithGoModuleResolver(),
+		goscan.WithLogger(logger),
+	)
+	if err != nil {
+		t.Fatalf("failed to create scanner: %v", err)
+	}
+	customPatterns, err := convertConfigsToPatterns([]patterns.PatternConfig{
+		{Key: "error-responses.respondError", Type: patterns.ErrorResponse, ArgIndex: 1},
+	}, logger, s)
+	if err != nil {
+		t.Fatalf("failed to convert custom patterns: %v", err)
+	}
+	var opts []any
+	for _, p := range customPatterns {
+		opts = append(opts, p)
+	}
+	analyzer, err := NewAnalyzer(s, logger, nil, opts...)
+	if err != nil {
+		t.Fatalf("failed to create analyzer: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := analyzer.Analyze(ctx, apiPath, "main"); err != nil {
+		t.Fatalf("failed to analyze package: %+v", err)
+	}
+
+	var got bytes.Buffer
+	enc := json.NewEncoder(&got)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(analyzer.OpenAPI); err != nil {
+		t.Fatalf("failed to marshal OpenAPI spec to json: %v", err)
+	}
+
+	if *update {
+		if err := os.WriteFile(goldenFile, got.Bytes(), 0644); err != nil {
+			t.Fatalf("failed to write golden file %s: %v", goldenFile, err)
+		}
+		t.Logf("golden file updated: %s", goldenFile)
+	}
+
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s: %v", goldenFile, err)
+	}
+	if diff := cmp.Diff(string(want), got.String()); diff != "" {
+		t.Errorf("OpenAPI spec mismatch (-want +got):\n%s", diff)
+	}
+}
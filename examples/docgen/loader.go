@@ -111,8 +111,10 @@ func convertConfigsToPatterns(configs []patterns.PatternConfig, logger *slog.Log
 
 		// Validate the pattern type string and required fields.
 		switch c.Type {
-		case patterns.RequestBody, patterns.ResponseBody, patterns.DefaultResponse:
+		case patterns.RequestBody, patterns.ResponseBody, patterns.DefaultResponse, patterns.WebSocket, patterns.ServerSentEvents:
 			// valid
+		case patterns.ErrorResponse:
+			// The status code is taken from the argument at ArgIndex if StatusCode is empty.
 		case patterns.CustomResponse:
 			if c.StatusCode == "" {
 				return nil, fmt.Errorf("pattern %q: 'StatusCode' is required for type %q", c.Name, c.Type)
@@ -135,8 +137,14 @@ func convertConfigsToPatterns(configs []patterns.PatternConfig, logger *slog.Log
 			result[i].Apply = patterns.HandleCustomResponse(c.StatusCode, c.ArgIndex)
 		case patterns.DefaultResponse:
 			result[i].Apply = patterns.HandleDefaultResponse(c.ArgIndex)
+		case patterns.ErrorResponse:
+			result[i].Apply = patterns.HandleErrorResponse(c.StatusCode, c.ArgIndex)
 		case patterns.PathParameter, patterns.QueryParameter, patterns.HeaderParameter:
 			result[i].Apply = patterns.HandleCustomParameter(string(c.Type), c.Description, c.NameArgIndex, c.ArgIndex)
+		case patterns.WebSocket:
+			result[i].Apply = patterns.HandleWebSocket()
+		case patterns.ServerSentEvents:
+			result[i].Apply = patterns.HandleServerSentEvents()
 		default:
 			// This case should be unreachable due to the validation above
 			logger.Warn("unreachable: unknown pattern type", "type", c.Type, "key", key)
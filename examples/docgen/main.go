@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
 
 	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/examples/docgen/openapi"
 	"github.com/podhmo/go-scan/examples/docgen/patterns"
 	"gopkg.in/yaml.v3"
 )
@@ -29,29 +32,73 @@ func main() {
 	var (
 		format       string
 		patternsFile string
-		entrypoint   string
+		entrypoints  stringSlice
+		discover     bool
+		routerTypes  stringSlice
+		outputDir    string
 		extraPkgs    stringSlice
+		operationID  string
+		baseURL      string
 		logLevel     = slog.LevelWarn
 	)
-	flag.StringVar(&format, "format", "json", "Output format (json or yaml)")
+	// `docgen verify [flags] <package-path>` checks the generated spec against the handlers.
+	verify := len(os.Args) > 1 && os.Args[1] == "verify"
+	if verify {
+		os.Args = append(os.Args[:1:1], os.Args[2:]...)
+	}
+	flag.StringVar(&format, "format", "json", "Output format (json, yaml or markdown)")
 	flag.StringVar(&patternsFile, "patterns", "", "Path to a Go file with custom pattern configurations")
-	flag.StringVar(&entrypoint, "entrypoint", "NewServeMux", "The entrypoint function name")
+	flag.Var(&entrypoints, "entrypoint", "The entrypoint function name (can be used multiple times, default: NewServeMux)")
+	flag.BoolVar(&discover, "discover", false, "Use all functions returning a router type as entrypoints")
+	flag.Var(&routerTypes, "router-type", "A router type for -discover, e.g. '*net/http.ServeMux' (can be used multiple times)")
+	flag.StringVar(&outputDir, "output-dir", "", "Write one spec file per entrypoint into this directory, instead of a merged spec to stdout")
 	flag.Var(&extraPkgs, "include-pkg", "Specify an external package to treat as internal (can be used multiple times)")
+	flag.StringVar(&operationID, "operation-id", OperationIDFunc, "The operationId strategy: 'func', 'method-path', or a template, e.g. '{{.Func}}'")
+	flag.StringVar(&baseURL, "base-url", "", "For verify, the URL of a running server to verify, instead of serving the entrypoints with httptest")
 	flag.TextVar(&logLevel, "log-level", &logLevel, "set log level (debug, info, warn, error)")
 	flag.Parse()
 
 	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: &logLevel}))
 
-	if err := run(logger, format, patternsFile, entrypoint, extraPkgs); err != nil {
+	cfg := runConfig{
+		Format:       format,
+		PatternsFile: patternsFile,
+		Entrypoints:  entrypoints,
+		Discover:     discover,
+		RouterTypes:  routerTypes,
+		OutputDir:    outputDir,
+		ExtraPkgs:    extraPkgs,
+		OperationID:  operationID,
+		Verify:       verify,
+		BaseURL:      baseURL,
+	}
+	if err := run(logger, cfg); err != nil {
 		logger.Error("docgen failed", "error", err)
 		os.Exit(1)
 	}
 }
 
-func run(logger *slog.Logger, format string, patternsFile string, entrypoint string, extraPkgs []string) error {
+// runConfig holds the settings of a run, from the command-line flags of the same names.
+type runConfig struct {
+	Format       string
+	PatternsFile string   // -patterns
+	Entrypoints  []string // -entrypoint
+	Discover     bool
+	RouterTypes  []string // -router-type
+	OutputDir    string
+	ExtraPkgs    []string // -include-pkg
+	OperationID  string
+	Verify       bool // the verify subcommand
+	BaseURL      string
+}
+
+func run(logger *slog.Logger, cfg runConfig) error {
 	if flag.NArg() == 0 {
 		return fmt.Errorf("required argument: <package-path>")
 	}
+	if cfg.Format != "json" && cfg.Format != "yaml" && cfg.Format != "markdown" {
+		return fmt.Errorf("unsupported format: %q", cfg.Format)
+	}
 	ctx := context.Background()
 	sampleAPIPath, err := goscan.ResolvePath(ctx, flag.Arg(0))
 	if err != nil {
@@ -67,38 +114,105 @@ func run(logger *slog.Logger, format string, patternsFile string, entrypoint str
 		return err
 	}
 
-	customPatterns, err := loadCustomPatterns(patternsFile, logger, s)
+	customPatterns, err := loadCustomPatterns(cfg.PatternsFile, logger, s)
 	if err != nil {
 		return err
 	}
 
-	var opts []any
+	opts := []any{WithOperationIDStrategy(cfg.OperationID)}
 	for _, p := range customPatterns {
 		opts = append(opts, p)
 	}
 
-	analyzer, err := NewAnalyzer(s, logger, extraPkgs, opts...)
+	analyzer, err := NewAnalyzer(s, logger, cfg.ExtraPkgs, opts...)
 	if err != nil {
 		return err
 	}
 
-	if err := analyzer.Analyze(ctx, sampleAPIPath, entrypoint); err != nil {
+	if cfg.Discover {
+		discovered, err := analyzer.DiscoverEntrypoints(ctx, sampleAPIPath, cfg.RouterTypes)
+		if err != nil {
+			return err
+		}
+		if len(discovered) == 0 {
+			return fmt.Errorf("no entrypoint returning a router type found in %q", sampleAPIPath)
+		}
+		cfg.Entrypoints = append(cfg.Entrypoints, discovered...)
+	}
+	if len(cfg.Entrypoints) == 0 {
+		cfg.Entrypoints = []string{"NewServeMux"}
+	}
+
+	services, err := analyzer.AnalyzeServices(ctx, sampleAPIPath, cfg.Entrypoints)
+	if err != nil {
 		return err
 	}
 
+	if cfg.Verify {
+		return verifyServices(ctx, os.Stdout, s, sampleAPIPath, services, cfg.BaseURL)
+	}
+
+	if cfg.OutputDir != "" {
+		if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		for _, svc := range services {
+			filename := filepath.Join(cfg.OutputDir, svc.Name+fileExt(cfg.Format))
+			if err := writeSpecFile(filename, cfg.Format, svc.OpenAPI); err != nil {
+				return err
+			}
+			logger.Info("wrote spec", "entrypoint", svc.Name, "file", filename)
+		}
+		return nil
+	}
+
+	doc := services[0].OpenAPI
+	if len(services) > 1 {
+		doc = analyzer.MergeServices(ctx, services)
+	}
+	return writeSpec(os.Stdout, cfg.Format, doc)
+}
+
+func writeSpecFile(filename string, format string, doc *openapi.OpenAPI) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filename, err)
+	}
+	if err := writeSpec(f, format, doc); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", filename, err)
+	}
+	return f.Close()
+}
+
+func writeSpec(w io.Writer, format string, doc *openapi.OpenAPI) error {
 	switch format {
 	case "json":
-		enc := json.NewEncoder(os.Stdout)
+		enc := json.NewEncoder(w)
 		enc.SetIndent("", "  ")
-		return enc.Encode(analyzer.OpenAPI)
+		return enc.Encode(doc)
 	case "yaml":
-		enc := yaml.NewEncoder(os.Stdout)
-		return enc.Encode(analyzer.OpenAPI)
+		enc := yaml.NewEncoder(w)
+		return enc.Encode(doc)
+	case "markdown":
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		return writeMarkdown(w, doc, wd)
 	default:
 		return fmt.Errorf("unsupported format: %q", format)
 	}
 }
 
+// fileExt returns the extension of the spec files of the format.
+func fileExt(format string) string {
+	if format == "markdown" {
+		return ".md"
+	}
+	return "." + format
+}
+
 func loadCustomPatterns(filePath string, logger *slog.Logger, scanner *goscan.Scanner) ([]patterns.Pattern, error) {
 	if filePath == "" {
 		return nil, nil
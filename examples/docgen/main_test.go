@@ -64,6 +64,17 @@ func TestDocgen(t *testing.T) {
 				return enc.Encode(spec)
 			},
 		},
+		{
+			format:     "markdown",
+			goldenFile: "golden.md",
+			marshalFunc: func(w io.Writer, spec *openapi.OpenAPI) error {
+				wd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				return writeMarkdown(w, spec, wd)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
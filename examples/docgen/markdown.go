@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/podhmo/go-scan/examples/docgen/openapi"
+)
+
+// writeMarkdown writes a human-readable API reference of the document, with one section per
+// operation. The locations of the handlers are written relative to baseDir.
+func writeMarkdown(w io.Writer, doc *openapi.OpenAPI, baseDir string) error {
+	mw := &markdownWriter{w: w, doc: doc, baseDir: baseDir}
+	mw.printf("# %s\n", doc.Info.Title)
+	if doc.Info.Version != "" {
+		mw.printf("\nVersion: %s\n", doc.Info.Version)
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, method := range openapi.Methods {
+			if op := doc.Paths[path].Operation(method); op != nil {
+				mw.writeOperation(method, path, op)
+			}
+		}
+	}
+	return mw.err
+}
+
+// markdownWriter keeps the first write error, so that the sections can be written without checks.
+type markdownWriter struct {
+	w       io.Writer
+	doc     *openapi.OpenAPI
+	baseDir string
+	err     error
+}
+
+func (mw *markdownWriter) printf(format string, args ...any) {
+	if mw.err != nil {
+		return
+	}
+	_, mw.err = fmt.Fprintf(mw.w, format, args...)
+}
+
+func (mw *markdownWriter) writeOperation(method, path string, op *openapi.Operation) {
+	mw.printf("\n## %s %s\n\n", method, path)
+	if op.Description != "" {
+		mw.printf("%s\n\n", op.Description)
+	}
+	if op.OperationID != "" {
+		mw.printf("- Operation ID: `%s`\n", op.OperationID)
+	}
+	if len(op.Tags) > 0 {
+		mw.printf("- Tags: %s\n", strings.Join(op.Tags, ", "))
+	}
+	if op.Handler != nil {
+		file := op.Handler.File
+		if rel, err := filepath.Rel(mw.baseDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = filepath.ToSlash(rel)
+		}
+		mw.printf("- Handler: `%s` (%s:%d)\n", op.Handler.Name, file, op.Handler.Line)
+	}
+	if op.XWebSocket {
+		mw.printf("- WebSocket: the connection is upgraded\n")
+	}
+
+	if len(op.Parameters) > 0 {
+		mw.printf("\n### Parameters\n\n")
+		mw.printf("| Name | In | Type | Required | Description |\n")
+		mw.printf("|------|----|------|----------|-------------|\n")
+		for _, p := range op.Parameters {
+			required := "no"
+			if p.Required {
+				required = "yes"
+			}
+			mw.printf("| %s | %s | %s | %s | %s |\n", p.Name, p.In, schemaTypeName(p.Schema), required, tableCell(p.Description))
+		}
+	}
+
+	if op.RequestBody != nil {
+		mw.printf("\n### Request\n")
+		if op.RequestBody.Description != "" {
+			mw.printf("\n%s\n", op.RequestBody.Description)
+		}
+		mw.writeContent(op.RequestBody.Content)
+	}
+
+	if len(op.Responses) > 0 {
+		mw.printf("\n### Responses\n")
+		codes := make([]string, 0, len(op.Responses))
+		for code := range op.Responses {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			res := op.Responses[code]
+			mw.printf("\n#### %s %s\n", code, res.Description)
+			mw.writeContent(res.Content)
+		}
+	}
+}
+
+// writeContent writes an example of each media type, sorted by content type.
+func (mw *markdownWriter) writeContent(content map[string]openapi.MediaType) {
+	types := make([]string, 0, len(content))
+	for ct := range content {
+		types = append(types, ct)
+	}
+	sort.Strings(types)
+	for _, ct := range types {
+		mw.printf("\nContent type: `%s`\n", ct)
+		schema := content[ct].Schema
+		if schema == nil {
+			continue
+		}
+		if !strings.Contains(ct, "json") {
+			mw.printf("\nSchema: %s\n", schemaTypeName(schema))
+			continue
+		}
+		example, err := json.MarshalIndent(schemaExample(mw.doc, schema, nil), "", "  ")
+		if err != nil {
+			mw.err = err
+			return
+		}
+		mw.printf("\n```json\n%s\n```\n", example)
+	}
+}
+
+// schemaExample builds an example value of the schema, following the references to the component
+// schemas of the document. seen holds the references being expanded, to stop at recursive types.
+func schemaExample(doc *openapi.OpenAPI, s *openapi.Schema, seen map[string]bool) any {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		if seen[s.Ref] {
+			return nil
+		}
+		target, ok := componentSchema(doc, s.Ref)
+		if !ok {
+			return nil
+		}
+		next := make(map[string]bool, len(seen)+1)
+		for k := range seen {
+			next[k] = true
+		}
+		next[s.Ref] = true
+		return schemaExample(doc, target, next)
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+
+	switch s.Type {
+	case "object":
+		obj := make(map[string]any, len(s.Properties))
+		for name, prop := range s.Properties {
+			obj[name] = schemaExample(doc, prop, seen)
+		}
+		if s.AdditionalProperties != nil {
+			obj["key"] = schemaExample(doc, s.AdditionalProperties, seen)
+		}
+		return obj
+	case "array":
+		return []any{schemaExample(doc, s.Items, seen)}
+	case "integer", "number":
+		if s.Minimum != nil {
+			return *s.Minimum
+		}
+		return 0
+	case "boolean":
+		return false
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "2006-01-02T15:04:05Z"
+		case "date":
+			return "2006-01-02"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "uri", "url":
+			return "https://example.com"
+		}
+		return "string"
+	}
+	return nil
+}
+
+// componentSchema returns the component schema of a reference, e.g. "#/components/schemas/User".
+func componentSchema(doc *openapi.OpenAPI, ref string) (*openapi.Schema, bool) {
+	if doc.Components == nil {
+		return nil, false
+	}
+	s, ok := doc.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+	return s, ok
+}
+
+// schemaTypeName returns a short name of the schema type for the tables, e.g. "[]User" or "integer (int64)".
+func schemaTypeName(s *openapi.Schema) string {
+	if s == nil {
+		return ""
+	}
+	if s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+	switch s.Type {
+	case "array":
+		return "[]" + schemaTypeName(s.Items)
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "map[string]" + schemaTypeName(s.AdditionalProperties)
+		}
+	}
+	if s.Format != "" {
+		return fmt.Sprintf("%s (%s)", s.Type, s.Format)
+	}
+	return s.Type
+}
+
+// tableCell makes the text fit in a cell of a Markdown table.
+func tableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
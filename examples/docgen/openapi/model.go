@@ -7,6 +7,13 @@ type OpenAPI struct {
 	Info       Info                 `json:"info" yaml:"info"`
 	Paths      map[string]*PathItem `json:"paths,omitempty" yaml:"paths,omitempty"`
 	Components *Components          `json:"components,omitempty" yaml:"components,omitempty"`
+	Tags       []*Tag               `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// Tag adds metadata to a single tag that is used by operations.
+type Tag struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 }
 
 // Components holds a set of reusable objects for different aspects of the OAS.
@@ -32,14 +39,78 @@ type PathItem struct {
 	Trace   *Operation `json:"trace,omitempty" yaml:"trace,omitempty"`
 }
 
+// Methods lists the HTTP methods supported by PathItem, in upper case.
+var Methods = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS", "TRACE"}
+
+// Operation returns the operation for the given HTTP method (in upper case), or nil.
+func (p *PathItem) Operation(method string) *Operation {
+	switch method {
+	case "GET":
+		return p.Get
+	case "POST":
+		return p.Post
+	case "PUT":
+		return p.Put
+	case "DELETE":
+		return p.Delete
+	case "PATCH":
+		return p.Patch
+	case "HEAD":
+		return p.Head
+	case "OPTIONS":
+		return p.Options
+	case "TRACE":
+		return p.Trace
+	}
+	return nil
+}
+
+// SetOperation sets the operation for the given HTTP method (in upper case).
+// Unknown methods are ignored.
+func (p *PathItem) SetOperation(method string, op *Operation) {
+	switch method {
+	case "GET":
+		p.Get = op
+	case "POST":
+		p.Post = op
+	case "PUT":
+		p.Put = op
+	case "DELETE":
+		p.Delete = op
+	case "PATCH":
+		p.Patch = op
+	case "HEAD":
+		p.Head = op
+	case "OPTIONS":
+		p.Options = op
+	case "TRACE":
+		p.Trace = op
+	}
+}
+
 // Operation describes a single API operation on a path.
 type Operation struct {
 	Summary     string               `json:"summary,omitempty" yaml:"summary,omitempty"`
 	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
 	OperationID string               `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Tags        []string             `json:"tags,omitempty" yaml:"tags,omitempty"`
 	Parameters  []*Parameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 	RequestBody *RequestBody         `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
 	Responses   map[string]*Response `json:"responses,omitempty" yaml:"responses,omitempty"`
+
+	// XWebSocket is the `x-websocket` extension, set if the operation upgrades the connection to a WebSocket.
+	XWebSocket bool `json:"x-websocket,omitempty" yaml:"x-websocket,omitempty"`
+
+	// Handler is the handler function of the operation. It is not part of the specification,
+	// and is used by the Markdown output.
+	Handler *Handler `json:"-" yaml:"-"`
+}
+
+// Handler is the source location of the function handling an operation.
+type Handler struct {
+	Name string // e.g. "example.com/api.getUser"
+	File string // absolute path
+	Line int
 }
 
 // Parameter describes a single operation parameter.
@@ -78,4 +149,16 @@ type Schema struct {
 	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
 	Format               string             `json:"format,omitempty" yaml:"format,omitempty"` // e.g., "int32", "int64"
 	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+
+	// Validation keywords, e.g. from enum constants or `validate` tags.
+	Enum             []any    `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Minimum          *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	MinLength        *int     `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinItems         *int     `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems         *int     `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	Pattern          string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
 }
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/podhmo/go-scan/examples/docgen/openapi"
+)
+
+// The operationId strategies of WithOperationIDStrategy. A strategy containing "{{" is a
+// text/template instead.
+const (
+	// OperationIDFunc names an operation after the last two elements of the package path and
+	// the name of its handler function, e.g. "sampleapi_getUser".
+	OperationIDFunc = "func"
+	// OperationIDMethodPath names an operation after its method and path, e.g. "get_users_id"
+	// for "GET /users/{id}".
+	OperationIDMethodPath = "method-path"
+)
+
+// OperationIDData is the data of an operationId template.
+type OperationIDData struct {
+	Method  string // in upper case, e.g. "GET"
+	Path    string // e.g. "/users/{id}"
+	Package string // the import path of the package of the handler
+	Func    string // the name of the handler function
+}
+
+// operationIDFuncs are the functions of the operationId templates.
+var operationIDFuncs = template.FuncMap{
+	"slug":  slug,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// operationIDGenerator generates the operationId of an operation.
+type operationIDGenerator func(data OperationIDData) (string, error)
+
+// newOperationIDGenerator returns the generator of the strategy, "" being OperationIDFunc.
+func newOperationIDGenerator(strategy string) (operationIDGenerator, error) {
+	switch {
+	case strategy == "" || strategy == OperationIDFunc:
+		return funcOperationID, nil
+	case strategy == OperationIDMethodPath:
+		return methodPathOperationID, nil
+	case strings.Contains(strategy, "{{"):
+		tmpl, err := template.New("operationId").Funcs(operationIDFuncs).Option("missingkey=error").Parse(strategy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid operationId template %q: %w", strategy, err)
+		}
+		return func(data OperationIDData) (string, error) {
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, data); err != nil {
+				return "", fmt.Errorf("executing operationId template %q: %w", strategy, err)
+			}
+			return buf.String(), nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown operationId strategy %q (want %q, %q or a template)", strategy, OperationIDFunc, OperationIDMethodPath)
+	}
+}
+
+func funcOperationID(data OperationIDData) (string, error) {
+	pkgPathForID := strings.ReplaceAll(data.Package, "/", "_")
+	pkgPathForID = strings.ReplaceAll(pkgPathForID, ".", "_")
+	parts := strings.Split(pkgPathForID, "_")
+	if len(parts) > 2 {
+		pkgPathForID = strings.Join(parts[len(parts)-2:], "_")
+	}
+	return fmt.Sprintf("%s_%s", pkgPathForID, data.Func), nil
+}
+
+func methodPathOperationID(data OperationIDData) (string, error) {
+	path := slug(data.Path)
+	if path == "" {
+		path = "root"
+	}
+	return strings.ToLower(data.Method) + "_" + path, nil
+}
+
+// slug replaces every run of characters other than letters and digits with a single "_",
+// trimming them at both ends, e.g. "users_id" for "/users/{id}".
+func slug(s string) string {
+	var b strings.Builder
+	pending := false
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pending && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			pending = false
+			b.WriteRune(r)
+			continue
+		}
+		pending = true
+	}
+	return b.String()
+}
+
+// uniqueOperationIDs makes the operationIds of the document unique, as a handler may be
+// registered on several paths. The operations are visited in the order of their paths and
+// methods, the first one keeping its operationId and the others getting a "_2", "_3", ...
+// suffix, so that the result is the same for the same input.
+func uniqueOperationIDs(doc *openapi.OpenAPI) {
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	used := make(map[string]bool)
+	for _, path := range paths {
+		for _, method := range openapi.Methods {
+			op := doc.Paths[path].Operation(method)
+			if op == nil || op.OperationID == "" {
+				continue
+			}
+			id := op.OperationID
+			for n := 2; used[id]; n++ {
+				id = fmt.Sprintf("%s_%d", op.OperationID, n)
+			}
+			used[id] = true
+			op.OperationID = id
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/examples/docgen/openapi"
+)
+
+func TestDocgen_operationIDStrategy(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     map[string]string // "METHOD path" -> operationId
+	}{
+		{
+			strategy: "",
+			want: map[string]string{
+				"GET /health":     "multi-service_api_Health",
+				"GET /users/{id}": "multi-service_api_GetUser",
+			},
+		},
+		{
+			strategy: OperationIDMethodPath,
+			want: map[string]string{
+				"GET /health":     "get_health",
+				"GET /users/{id}": "get_users_id",
+			},
+		},
+		{
+			strategy: "{{lower .Method}}{{.Func}}",
+			want: map[string]string{
+				"GET /health":     "getHealth",
+				"GET /users/{id}": "getGetUser",
+			},
+		},
+		{
+			// The operations sharing an operationId get suffixes in the order of their paths.
+			strategy: "{{slug .Package}}",
+			want: map[string]string{
+				"GET /health":     "multi_service_api",
+				"GET /users/{id}": "multi_service_api_2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			logger := newTestLogger(io.Discard)
+			s, err := goscan.New(
+				goscan.WithWorkDir("testdata/multi-service"),
+				goscan.WithGoModuleResolver(),
+				goscan.WithLogger(logger),
+			)
+			if err != nil {
+				t.Fatalf("failed to create scanner: %v", err)
+			}
+			analyzer, err := NewAnalyzer(s, logger, nil, WithOperationIDStrategy(tt.strategy))
+			if err != nil {
+				t.Fatalf("failed to create analyzer: %v", err)
+			}
+			if err := analyzer.Analyze(context.Background(), "multi-service/api", "NewPublicMux"); err != nil {
+				t.Fatalf("failed to analyze: %+v", err)
+			}
+
+			got := make(map[string]string)
+			for path, item := range analyzer.OpenAPI.Paths {
+				for _, method := range openapi.Methods {
+					if op := item.Operation(method); op != nil {
+						got[method+" "+path] = op.OperationID
+					}
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("operationId mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDocgen_operationIDStrategy_invalid(t *testing.T) {
+	for _, strategy := range []string{"unknown", "{{.Func"} {
+		t.Run(strategy, func(t *testing.T) {
+			logger := newTestLogger(io.Discard)
+			s, err := goscan.New(goscan.WithWorkDir("testdata/multi-service"), goscan.WithGoModuleResolver(), goscan.WithLogger(logger))
+			if err != nil {
+				t.Fatalf("failed to create scanner: %v", err)
+			}
+			if _, err := NewAnalyzer(s, logger, nil, WithOperationIDStrategy(strategy)); err == nil {
+				t.Errorf("want an error for the operationId strategy %q", strategy)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package patterns
+
+import (
+	"go/constant"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/podhmo/go-scan/examples/docgen/openapi"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// validatePatterns maps the `validate` rules that restrict the characters of a string to a pattern.
+var validatePatterns = map[string]string{
+	"alpha":    "^[a-zA-Z]+$",
+	"alphanum": "^[a-zA-Z0-9]+$",
+	"numeric":  "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":   "^[0-9]+$",
+	"hexcolor": "^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$",
+}
+
+// validateFormats maps the `validate` rules that check a well-known string format to an OpenAPI format.
+var validateFormats = map[string]string{
+	"email":    "email",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"url":      "uri",
+	"uri":      "uri",
+	"hostname": "hostname",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"datetime": "date-time",
+}
+
+// applyEnum sets the enum values of the schema of a named type from its constants.
+// Constants whose value cannot be represented in JSON are skipped.
+func applyEnum(schema *openapi.Schema, members []*scanner.ConstantInfo) {
+	for _, c := range members {
+		if c.ConstVal == nil {
+			continue
+		}
+		if v, ok := constantToJSON(c.ConstVal); ok {
+			schema.Enum = append(schema.Enum, v)
+		}
+	}
+}
+
+func constantToJSON(v constant.Value) (any, bool) {
+	switch v.Kind() {
+	case constant.String:
+		return constant.StringVal(v), true
+	case constant.Bool:
+		return constant.BoolVal(v), true
+	case constant.Int:
+		if i, exact := constant.Int64Val(v); exact {
+			return i, true
+		}
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return f, true
+	}
+	return nil, false
+}
+
+// applyValidateTag adds the constraints of a `validate` struct tag (as used by
+// github.com/go-playground/validator) to the schema of a field, e.g.
+// `validate:"required,oneof=admin member"` or `validate:"min=1,max=100"`.
+// The rules after `dive` apply to the elements of slices and maps.
+// Unknown rules, and the schemas that are references to components, are left as is.
+func applyValidateTag(schema *openapi.Schema, tag string) {
+	rules := reflect.StructTag(tag).Get("validate")
+	if schema == nil || rules == "" {
+		return
+	}
+	for _, rule := range strings.Split(rules, ",") {
+		if schema == nil {
+			return
+		}
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if name == "dive" {
+			if schema.Items != nil {
+				schema = schema.Items
+			} else {
+				schema = schema.AdditionalProperties
+			}
+			continue
+		}
+		if schema.Ref != "" {
+			continue
+		}
+		applyValidateRule(schema, name, param)
+	}
+}
+
+func applyValidateRule(schema *openapi.Schema, name, param string) {
+	switch name {
+	case "oneof":
+		schema.Enum = nil
+		for _, v := range strings.Fields(param) {
+			schema.Enum = append(schema.Enum, enumValue(schema.Type, v))
+		}
+	case "min", "gte":
+		setBound(schema, param, &schema.Minimum, &schema.MinLength, &schema.MinItems)
+	case "max", "lte":
+		setBound(schema, param, &schema.Maximum, &schema.MaxLength, &schema.MaxItems)
+	case "len":
+		setBound(schema, param, &schema.Minimum, &schema.MinLength, &schema.MinItems)
+		setBound(schema, param, &schema.Maximum, &schema.MaxLength, &schema.MaxItems)
+	case "gt":
+		if isNumeric(schema) {
+			schema.ExclusiveMinimum = parseFloat(param)
+		}
+	case "lt":
+		if isNumeric(schema) {
+			schema.ExclusiveMaximum = parseFloat(param)
+		}
+	default:
+		if schema.Type != "string" {
+			return
+		}
+		if pattern, ok := validatePatterns[name]; ok {
+			schema.Pattern = pattern
+		} else if format, ok := validateFormats[name]; ok {
+			schema.Format = format
+		}
+	}
+}
+
+// setBound sets the bound that applies to the type of the schema: the value of numbers,
+// the length of strings, or the number of items of arrays.
+func setBound(schema *openapi.Schema, param string, value **float64, length, items **int) {
+	switch {
+	case isNumeric(schema):
+		*value = parseFloat(param)
+	case schema.Type == "string":
+		*length = parseInt(param)
+	case schema.Type == "array":
+		*items = parseInt(param)
+	}
+}
+
+func isNumeric(schema *openapi.Schema) bool {
+	return schema.Type == "integer" || schema.Type == "number"
+}
+
+func enumValue(typ, v string) any {
+	switch typ {
+	case "integer":
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+func parseFloat(s string) *float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+func parseInt(s string) *int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &i
+}
@@ -4,6 +4,10 @@ package patterns
 import (
 	"context"
 	"fmt"
+	"go/constant"
+	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/podhmo/go-scan/examples/docgen/openapi"
@@ -36,6 +40,13 @@ const (
 	QueryParameter PatternType = "query"
 	// HeaderParameter indicates the pattern should extract a header parameter.
 	HeaderParameter PatternType = "header"
+	// WebSocket indicates the function upgrades the connection to a WebSocket.
+	WebSocket PatternType = "websocket"
+	// ServerSentEvents indicates the function starts a stream of server-sent events.
+	ServerSentEvents PatternType = "sse"
+	// ErrorResponse indicates the function writes an error response, e.g. `respondError(w, 404, err)`.
+	// The status code is StatusCode, or the value of the argument at ArgIndex if StatusCode is empty.
+	ErrorResponse PatternType = "errorResponse"
 )
 
 // PatternConfig defines a user-configurable pattern for docgen analysis.
@@ -62,10 +73,11 @@ type PatternConfig struct {
 	// For "requestBody", this is the argument that will be decoded into.
 	// For "responseBody", this is the argument that will be encoded from.
 	// For "path" or "query", this is the argument holding the parameter's value.
+	// For "errorResponse", this is the argument holding the status code.
 	ArgIndex int
 
 	// StatusCode is the HTTP status code for the response.
-	// Required for "customResponse" type.
+	// Required for "customResponse" type, optional for "errorResponse".
 	// e.g., "400", "500"
 	StatusCode string
 
@@ -263,6 +275,45 @@ func HandleCustomParameter(in, description string, nameArgIndex, valueArgIndex i
 	}
 }
 
+// HandleWebSocket returns a pattern handler that marks the operation as a WebSocket endpoint,
+// similar to `(*websocket.Upgrader).Upgrade`.
+func HandleWebSocket() func(ctx context.Context, interp *symgo.Interpreter, a Analyzer, args []symgo.Object) symgo.Object {
+	return func(ctx context.Context, interp *symgo.Interpreter, a Analyzer, args []symgo.Object) symgo.Object {
+		markWebSocket(a.OperationStack()[len(a.OperationStack())-1])
+		// The return value of the custom function is not known, so we return a placeholder.
+		return &symgo.SymbolicPlaceholder{Reason: "result of custom websocket function"}
+	}
+}
+
+// HandleServerSentEvents returns a pattern handler that marks the operation as streaming
+// server-sent events, similar to setting the `Content-Type` header to `text/event-stream`.
+func HandleServerSentEvents() func(ctx context.Context, interp *symgo.Interpreter, a Analyzer, args []symgo.Object) symgo.Object {
+	return func(ctx context.Context, interp *symgo.Interpreter, a Analyzer, args []symgo.Object) symgo.Object {
+		markServerSentEvents(a.OperationStack()[len(a.OperationStack())-1])
+		// The return value of the custom function is not known, so we return a placeholder.
+		return &symgo.SymbolicPlaceholder{Reason: "result of custom sse function"}
+	}
+}
+
+// HandleErrorResponse returns a pattern handler that records an error response written by a
+// helper function, with the standard error schema. If statusCode is empty, the status code is
+// the value of the argument at argIndex.
+func HandleErrorResponse(statusCode string, argIndex int) func(ctx context.Context, interp *symgo.Interpreter, a Analyzer, args []symgo.Object) symgo.Object {
+	return func(ctx context.Context, interp *symgo.Interpreter, a Analyzer, args []symgo.Object) symgo.Object {
+		op := a.OperationStack()[len(a.OperationStack())-1]
+		code := statusCode
+		if code == "" {
+			if len(args) <= argIndex {
+				return &symgo.SymbolicPlaceholder{Reason: fmt.Sprintf("error response pattern: not enough args (want %d, got %d)", argIndex+1, len(args))}
+			}
+			code = statusCodeOf(ctx, interp, args[argIndex])
+		}
+		addErrorResponse(op, code, "application/json", errorSchemaRef(a))
+		// The return value of the custom function is not known, so we return a placeholder.
+		return &symgo.SymbolicPlaceholder{Reason: "result of custom error response function"}
+	}
+}
+
 // GetDefaultPatterns returns a slice of all the default call patterns
 // used for analyzing standard net/http handlers.
 func GetDefaultPatterns() []Pattern {
@@ -272,6 +323,8 @@ func GetDefaultPatterns() []Pattern {
 		{Key: "(net/http.ResponseWriter).Write", Apply: handleResponseWriterWrite},
 		{Key: "(net/http.ResponseWriter).WriteHeader", Apply: handleWriteHeader},
 		{Key: "(net/http.Header).Set", Apply: handleHeaderSet},
+		{Key: "net/http.Error", Apply: handleHTTPError},
+		{Key: "net/http.NotFound", Apply: handleHTTPNotFound},
 
 		// httptest.ResponseRecorder, for when ResponseWriter is bound to it.
 		{Key: "(*net/http/httptest.ResponseRecorder).Header", Apply: handleHeader},
@@ -287,6 +340,11 @@ func GetDefaultPatterns() []Pattern {
 		{Key: "(*encoding/json.Decoder).Decode", Apply: handleDecode},
 		{Key: "encoding/json.NewEncoder", Apply: handleNewEncoder},
 		{Key: "(*encoding/json.Encoder).Encode", Apply: handleEncode},
+
+		// websocket related
+		{Key: "(*github.com/gorilla/websocket.Upgrader).Upgrade", Apply: handleWebSocketUpgrade},
+		{Key: "nhooyr.io/websocket.Accept", Apply: handleWebSocketUpgrade},
+		{Key: "github.com/coder/websocket.Accept", Apply: handleWebSocketUpgrade},
 	}
 }
 
@@ -300,12 +358,16 @@ func handleResponseWriterWrite(ctx context.Context, interp *symgo.Interpreter, a
 	// Find the response object, assuming WriteHeader was called first.
 	var resp *openapi.Response
 	var statusCode string
-	if op.Responses != nil {
-		for code, r := range op.Responses {
-			statusCode = code
-			resp = r
-			break // Assume first status code found is the one we want to add content to.
+	if len(op.Responses) > 0 {
+		// Assume the lowest status code found is the one we want to add content to, so that the
+		// choice does not depend on the iteration order of the map.
+		codes := make([]string, 0, len(op.Responses))
+		for code := range op.Responses {
+			codes = append(codes, code)
 		}
+		sort.Strings(codes)
+		statusCode = codes[0]
+		resp = op.Responses[statusCode]
 	}
 
 	// If no response entry exists (e.g., WriteHeader wasn't called or detected), default to 200.
@@ -321,6 +383,15 @@ func handleResponseWriterWrite(ctx context.Context, interp *symgo.Interpreter, a
 	if resp.Content == nil {
 		resp.Content = make(map[string]openapi.MediaType)
 	}
+	if _, ok := resp.Content[eventStreamContentType]; ok {
+		// The writes of a server-sent events stream are the events, not a plain text body.
+		return &symgo.MultiReturn{
+			Values: []symgo.Object{
+				&symgo.SymbolicPlaceholder{Reason: "return value from Write (int)"},
+				&symgo.Nil{},
+			},
+		}
+	}
 
 	// For a raw Write, we assume text/plain content.
 	// A more sophisticated analysis could check for a prior `Header.Set("Content-Type", ...)` call.
@@ -360,7 +431,150 @@ func handleWriteHeader(ctx context.Context, interp *symgo.Interpreter, a Analyze
 }
 
 func handleHeaderSet(ctx context.Context, interp *symgo.Interpreter, a Analyzer, args []symgo.Object) symgo.Object {
-	return nil // We don't need to track header values for now.
+	// args[0] is the header itself, args[1] and args[2] are the key and the value.
+	// Only `Content-Type: text/event-stream` is tracked, to detect server-sent events.
+	if len(args) != 3 {
+		return nil
+	}
+	key, ok := args[1].(*symgo.String)
+	if !ok || !strings.EqualFold(key.Value, "Content-Type") {
+		return nil
+	}
+	value, ok := args[2].(*symgo.String)
+	if !ok || !strings.HasPrefix(value.Value, eventStreamContentType) {
+		return nil
+	}
+	markServerSentEvents(a.OperationStack()[len(a.OperationStack())-1])
+	return nil
+}
+
+// eventStreamContentType is the media type of server-sent events.
+const eventStreamContentType = "text/event-stream"
+
+func handleWebSocketUpgrade(ctx context.Context, interp *symgo.Interpreter, a Analyzer, args []symgo.Object) symgo.Object {
+	markWebSocket(a.OperationStack()[len(a.OperationStack())-1])
+	// Both Upgrade and Accept return (conn, error).
+	return &symgo.MultiReturn{
+		Values: []symgo.Object{
+			&symgo.SymbolicPlaceholder{Reason: "websocket connection"},
+			&symgo.Nil{},
+		},
+	}
+}
+
+// markWebSocket records that the operation upgrades the connection to a WebSocket:
+// it is flagged with the `x-websocket` extension and responds with 101 Switching Protocols.
+func markWebSocket(op *openapi.Operation) {
+	op.XWebSocket = true
+	if op.Responses == nil {
+		op.Responses = make(map[string]*openapi.Response)
+	}
+	if _, ok := op.Responses["101"]; !ok {
+		op.Responses["101"] = &openapi.Response{Description: "Switching Protocols"}
+	}
+}
+
+// markServerSentEvents records that the operation responds with a stream of server-sent events.
+// The content of the 200 response is replaced, as the stream is not a JSON or plain text body.
+func markServerSentEvents(op *openapi.Operation) {
+	if op.Responses == nil {
+		op.Responses = make(map[string]*openapi.Response)
+	}
+	if _, ok := op.Responses["200"]; !ok {
+		op.Responses["200"] = &openapi.Response{Description: "OK"}
+	}
+	op.Responses["200"].Content = map[string]openapi.MediaType{
+		eventStreamContentType: {Schema: &openapi.Schema{Type: "string"}},
+	}
+}
+
+func handleHTTPError(ctx context.Context, interp *symgo.Interpreter, a Analyzer, args []symgo.Object) symgo.Object {
+	// http.Error(w, error, code) writes the message as plain text.
+	if len(args) != 3 {
+		return nil
+	}
+	addErrorResponse(a.OperationStack()[len(a.OperationStack())-1], statusCodeOf(ctx, interp, args[2]), "text/plain", &openapi.Schema{Type: "string"})
+	return nil
+}
+
+func handleHTTPNotFound(ctx context.Context, interp *symgo.Interpreter, a Analyzer, args []symgo.Object) symgo.Object {
+	addErrorResponse(a.OperationStack()[len(a.OperationStack())-1], "404", "text/plain", &openapi.Schema{Type: "string"})
+	return nil
+}
+
+// statusCodeOf returns the status code held by obj, e.g. `404` or `http.StatusNotFound`,
+// or "default" if it is not a constant.
+func statusCodeOf(ctx context.Context, interp *symgo.Interpreter, obj symgo.Object) string {
+	if v, ok := obj.(*symgo.Variable); ok {
+		obj = v.Value
+	}
+	switch obj := obj.(type) {
+	case *symgo.Integer:
+		return strconv.FormatInt(obj.Value, 10)
+	case *symgo.UnresolvedFunction:
+		// The constants of packages outside of the primary analysis scope, like
+		// `http.StatusNotFound`, are not evaluated, so they are looked up in the package.
+		pkg, err := interp.Scanner().ScanPackageFromImportPath(ctx, obj.PkgPath)
+		if err != nil {
+			return "default"
+		}
+		for _, c := range pkg.Constants {
+			if c.Name == obj.FuncName && c.ConstVal != nil {
+				if v, ok := constant.Int64Val(constant.ToInt(c.ConstVal)); ok {
+					return strconv.FormatInt(v, 10)
+				}
+			}
+		}
+	}
+	return "default"
+}
+
+// addErrorResponse records the error response of the operation for the status code.
+// The first content recorded for a status code and a media type is kept.
+func addErrorResponse(op *openapi.Operation, statusCode, mediaType string, schema *openapi.Schema) {
+	if op.Responses == nil {
+		op.Responses = make(map[string]*openapi.Response)
+	}
+	resp, ok := op.Responses[statusCode]
+	if !ok {
+		description := "Error response"
+		if code, err := strconv.Atoi(statusCode); err == nil && http.StatusText(code) != "" {
+			description = http.StatusText(code)
+		}
+		resp = &openapi.Response{Description: description}
+		op.Responses[statusCode] = resp
+	}
+	if resp.Content == nil {
+		resp.Content = make(map[string]openapi.MediaType)
+	}
+	if _, ok := resp.Content[mediaType]; !ok {
+		resp.Content[mediaType] = openapi.MediaType{Schema: schema}
+	}
+}
+
+// errorSchemaName is the name of the standard schema of the error responses written by
+// the helpers matched by "errorResponse" patterns.
+const errorSchemaName = "Error"
+
+// errorSchemaRef registers the standard error schema as a component, if needed, and returns
+// a reference to it.
+func errorSchemaRef(a Analyzer) *openapi.Schema {
+	doc := a.GetOpenAPI()
+	if doc.Components == nil {
+		doc.Components = &openapi.Components{}
+	}
+	if doc.Components.Schemas == nil {
+		doc.Components.Schemas = make(map[string]*openapi.Schema)
+	}
+	if _, ok := doc.Components.Schemas[errorSchemaName]; !ok {
+		doc.Components.Schemas[errorSchemaName] = &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"error": {Type: "string", Description: "The error message."},
+			},
+		}
+	}
+	return &openapi.Schema{Ref: "#/components/schemas/" + errorSchemaName}
 }
 
 func handleURLQuery(ctx context.Context, interp *symgo.Interpreter, a Analyzer, args []symgo.Object) symgo.Object {
@@ -494,12 +708,20 @@ func BuildSchemaForType(ctx context.Context, a Analyzer, typeInfo *scanner.TypeI
 		return &openapi.Schema{Type: "object", Description: "unknown type"}
 	}
 	if typeInfo.Underlying != nil {
-		return buildSchemaFromFieldType(ctx, a, typeInfo.Underlying, cache)
+		schema := buildSchemaFromFieldType(ctx, a, typeInfo.Underlying, cache)
+		if schema != nil && schema.Ref == "" && typeInfo.IsEnum {
+			applyEnum(schema, typeInfo.EnumMembers)
+		}
+		return schema
+	}
+	if typeInfo.Unresolved {
+		return &openapi.Schema{Type: "object", Description: "unresolved type"}
 	}
 	if typeInfo.Kind != scanner.StructKind || typeInfo.Struct == nil {
-		// Not a struct, or not a struct we can analyze.
-		// Fallback to building the schema directly without creating a component.
-		return buildSchemaFromFieldType(ctx, a, &scanner.FieldType{Definition: typeInfo}, cache)
+		// Not a struct, or not a struct we can analyze (e.g. an interface).
+		// Building the schema from a FieldType wrapping this definition would resolve
+		// back to this same definition, so stop here.
+		return &openapi.Schema{Type: "object"}
 	}
 
 	// It's a struct. We will register it as a component.
@@ -552,7 +774,9 @@ func BuildSchemaForType(ctx context.Context, a Analyzer, typeInfo *scanner.TypeI
 		if jsonName == "" {
 			jsonName = field.Name
 		}
-		schema.Properties[jsonName] = buildSchemaFromFieldType(ctx, a, field.Type, cache)
+		fieldSchema := buildSchemaFromFieldType(ctx, a, field.Type, cache)
+		applyValidateTag(fieldSchema, field.Tag)
+		schema.Properties[jsonName] = fieldSchema
 	}
 
 	// Add the complete schema to the components and remove from progress cache.
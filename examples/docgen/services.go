@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/podhmo/go-scan/examples/docgen/openapi"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// defaultRouterTypes are the result types that mark a function as an entrypoint
+// when entrypoints are discovered automatically.
+var defaultRouterTypes = []string{"*net/http.ServeMux", "net/http.Handler"}
+
+// Service is the result of analyzing a single entrypoint.
+type Service struct {
+	// Name is the name of the entrypoint function. It is also used as the tag
+	// and the operationId namespace when services are merged.
+	Name    string
+	OpenAPI *openapi.OpenAPI
+}
+
+// DiscoverEntrypoints returns the names of the top-level functions in the package
+// that return one of the given router types (e.g. "*net/http.ServeMux").
+// If routerTypes is empty, defaultRouterTypes is used. The names are sorted.
+func (a *Analyzer) DiscoverEntrypoints(ctx context.Context, importPath string, routerTypes []string) ([]string, error) {
+	pkg, err := a.Scanner.ScanPackageFromImportPath(ctx, importPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load package %q: %w", importPath, err)
+	}
+	if len(routerTypes) == 0 {
+		routerTypes = defaultRouterTypes
+	}
+	wanted := make(map[string]bool, len(routerTypes))
+	for _, t := range routerTypes {
+		wanted[t] = true
+	}
+
+	var names []string
+	for _, f := range pkg.Functions {
+		if f.Receiver != nil || len(f.TypeParams) > 0 || f.AstDecl == nil || f.AstDecl.Body == nil {
+			continue
+		}
+		for _, r := range f.Results {
+			if wanted[qualifiedTypeName(r.Type)] {
+				names = append(names, f.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// qualifiedTypeName returns the type name in `*path/to/pkg.Name` form.
+func qualifiedTypeName(ft *scanner.FieldType) string {
+	if ft == nil {
+		return ""
+	}
+	if ft.IsPointer && ft.Elem != nil {
+		return "*" + qualifiedTypeName(ft.Elem)
+	}
+	if ft.FullImportPath == "" {
+		return ft.TypeName
+	}
+	return ft.FullImportPath + "." + ft.TypeName
+}
+
+// AnalyzeServices analyzes each entrypoint as an independent service.
+// Each service gets its own OpenAPI document, holding only the paths registered by
+// its entrypoint and the component schemas they reference. After the call,
+// a.OpenAPI holds the document of the last service.
+func (a *Analyzer) AnalyzeServices(ctx context.Context, importPath string, entrypoints []string) ([]*Service, error) {
+	services := make([]*Service, 0, len(entrypoints))
+	for _, entrypoint := range entrypoints {
+		a.OpenAPI = newOpenAPI()
+		if err := a.Analyze(ctx, importPath, entrypoint); err != nil {
+			return nil, fmt.Errorf("analyzing entrypoint %q: %w", entrypoint, err)
+		}
+		services = append(services, &Service{Name: entrypoint, OpenAPI: a.OpenAPI})
+	}
+	return services, nil
+}
+
+// MergeServices merges the documents of multiple services into a single document.
+// Every operation is tagged with its service name, and its operationId is namespaced
+// as `<service>_<operationId>` so that handlers shared between services stay unique.
+// Component schemas shared between services are emitted once.
+func (a *Analyzer) MergeServices(ctx context.Context, services []*Service) *openapi.OpenAPI {
+	merged := newOpenAPI()
+	for _, svc := range services {
+		merged.Tags = append(merged.Tags, &openapi.Tag{Name: svc.Name})
+
+		for _, path := range sortedKeys(svc.OpenAPI.Paths) {
+			item := svc.OpenAPI.Paths[path]
+			if merged.Paths[path] == nil {
+				merged.Paths[path] = &openapi.PathItem{}
+			}
+			mergedItem := merged.Paths[path]
+			for _, method := range openapi.Methods {
+				op := item.Operation(method)
+				if op == nil {
+					continue
+				}
+				if existing := mergedItem.Operation(method); existing != nil {
+					a.logger.WarnContext(ctx, "operation is defined by multiple services, keeping the first one",
+						"method", method, "path", path, "kept", existing.OperationID, "service", svc.Name)
+					continue
+				}
+				namespaced := *op
+				namespaced.OperationID = svc.Name + "_" + op.OperationID
+				namespaced.Tags = append(append([]string(nil), op.Tags...), svc.Name)
+				mergedItem.SetOperation(method, &namespaced)
+			}
+		}
+
+		if svc.OpenAPI.Components == nil {
+			continue
+		}
+		for _, name := range sortedKeys(svc.OpenAPI.Components.Schemas) {
+			schema := svc.OpenAPI.Components.Schemas[name]
+			existing, ok := merged.Components.Schemas[name]
+			if !ok {
+				merged.Components.Schemas[name] = schema
+				continue
+			}
+			if !reflect.DeepEqual(existing, schema) {
+				a.logger.WarnContext(ctx, "component schema differs between services, keeping the first one",
+					"schema", name, "service", svc.Name)
+			}
+		}
+	}
+	return merged
+}
+
+// sortedKeys returns the keys of the map in order, for the output and the logs not to depend on
+// the iteration order of the maps.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
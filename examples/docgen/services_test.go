@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/examples/docgen/openapi"
+)
+
+func TestDocgen_multiService(t *testing.T) {
+	const apiPath = "multi-service/api"
+	moduleDir := "testdata/multi-service"
+
+	logger := newTestLogger(io.Discard)
+	s, err := goscan.New(
+		goscan.WithWorkDir(moduleDir),
+		goscan.WithGoModuleResolver(),
+		goscan.WithLogger(logger),
+	)
+	if err != nil {
+		t.Fatalf("failed to create scanner: %v", err)
+	}
+	analyzer, err := NewAnalyzer(s, logger, nil)
+	if err != nil {
+		t.Fatalf("failed to create analyzer: %v", err)
+	}
+	ctx := context.Background()
+
+	entrypoints, err := analyzer.DiscoverEntrypoints(ctx, apiPath, nil)
+	if err != nil {
+		t.Fatalf("failed to discover entrypoints: %+v", err)
+	}
+	if diff := cmp.Diff([]string{"NewAdminMux", "NewPublicMux"}, entrypoints); diff != "" {
+		t.Fatalf("discovered entrypoints mismatch (-want +got):\n%s", diff)
+	}
+
+	services, err := analyzer.AnalyzeServices(ctx, apiPath, entrypoints)
+	if err != nil {
+		t.Fatalf("failed to analyze services: %+v", err)
+	}
+
+	t.Run("separate", func(t *testing.T) {
+		for _, svc := range services {
+			assertGoldenJSON(t, filepath.Join(moduleDir, svc.Name+".golden.json"), svc.OpenAPI)
+		}
+	})
+	t.Run("merged", func(t *testing.T) {
+		merged := analyzer.MergeServices(ctx, services)
+		assertGoldenJSON(t, filepath.Join(moduleDir, "merged.golden.json"), merged)
+	})
+}
+
+func assertGoldenJSON(t *testing.T, goldenFile string, spec *openapi.OpenAPI) {
+	t.Helper()
+
+	var got bytes.Buffer
+	enc := json.NewEncoder(&got)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(spec); err != nil {
+		t.Fatalf("failed to marshal OpenAPI spec to json: %v", err)
+	}
+
+	if *update {
+		if err := os.WriteFile(goldenFile, got.Bytes(), 0644); err != nil {
+			t.Fatalf("failed to write golden file %s: %v", goldenFile, err)
+		}
+		t.Logf("golden file updated: %s", goldenFile)
+		return
+	}
+
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Fatalf("golden file not found: %s. Run with -update to create it.", goldenFile)
+		}
+		t.Fatalf("failed to read golden file %s: %v", goldenFile, err)
+	}
+	if diff := cmp.Diff(string(want), got.String()); diff != "" {
+		t.Errorf("OpenAPI spec mismatch for %s (-want +got):\n%s", goldenFile, diff)
+	}
+}
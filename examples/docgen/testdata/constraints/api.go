@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Role is the role of a user.
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+	RoleGuest  Role = "guest"
+)
+
+// Priority is the priority of a task.
+type Priority int
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityMedium
+	PriorityHigh
+)
+
+// CreateUserRequest is the request body of CreateUser.
+type CreateUserRequest struct {
+	Name     string     `json:"name" validate:"required,min=1,max=64,alphanum"`
+	Email    string     `json:"email" validate:"required,email"`
+	Role     Role       `json:"role"`
+	Plan     string     `json:"plan" validate:"oneof=free pro enterprise"`
+	Age      int        `json:"age" validate:"gte=0,lte=150"`
+	Score    float64    `json:"score" validate:"gt=0,lt=1"`
+	Tags     []string   `json:"tags" validate:"max=10,dive,min=2"`
+	Priority Priority   `json:"priority"`
+	Levels   []Priority `json:"levels" validate:"len=3"`
+}
+
+// CreateUser creates a user.
+func CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func main() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users", CreateUser)
+	http.ListenAndServe(":8080", mux)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+)
+
+const apiPrefix = "/api/v1"
+
+// listItems lists the items.
+func listItems(w http.ResponseWriter, r *http.Request) {}
+
+// getItem returns an item of a version of the API chosen at startup.
+func getItem(w http.ResponseWriter, r *http.Request) {}
+
+// listTenantItems lists the items of the tenant.
+func listTenantItems(w http.ResponseWriter, r *http.Request) {}
+
+func main() {
+	version := os.Getenv("API_VERSION")
+	tenant := os.Getenv("TENANT")
+
+	mux := http.NewServeMux()
+	// A concatenation of constants is folded.
+	mux.HandleFunc("GET "+apiPrefix+"/items", listItems)
+	// The holes of a formatted pattern are not named after the arguments.
+	mux.HandleFunc(fmt.Sprintf("GET /api/%s/items", version), getItem)
+	// The holes of a concatenation are named after the operands.
+	mux.HandleFunc("GET /tenants/"+tenant+"/items", listTenantItems)
+	http.ListenAndServe(":8080", mux)
+}
@@ -0,0 +1,3 @@
+module dynamicroutes
+
+go 1.24
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrInvalid  = errors.New("invalid")
+)
+
+// User represents a user in the system.
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func validateID(id string) error {
+	if id == "" {
+		return ErrInvalid
+	}
+	if id == "0" {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// writeError maps the sentinel errors to status codes.
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// respondError writes the error as JSON. It is detected by a custom pattern.
+func respondError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// GetUser returns a user.
+func GetUser(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := validateID(id); err != nil {
+		writeError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(User{ID: id, Name: "John Doe"})
+}
+
+// CreateUser creates a user.
+func CreateUser(w http.ResponseWriter, r *http.Request) {
+	var user User
+	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		respondError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if user.Name == "" {
+		respondError(w, 409, ErrInvalid)
+		return
+	}
+	json.NewEncoder(w).Encode(user)
+}
+
+// DeleteUser is not implemented yet.
+func DeleteUser(w http.ResponseWriter, r *http.Request) {
+	http.NotFound(w, r)
+}
+
+func main() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", GetUser)
+	mux.HandleFunc("POST /users", CreateUser)
+	mux.HandleFunc("DELETE /users/{id}", DeleteUser)
+	http.ListenAndServe(":8080", mux)
+}
@@ -0,0 +1,3 @@
+module error-responses
+
+go 1.24
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"multi-service/models"
+)
+
+// NewPublicMux is the entrypoint of the public service.
+func NewPublicMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", GetUser)
+	mux.HandleFunc("GET /health", Health)
+	return mux
+}
+
+// NewAdminMux is the entrypoint of the admin service.
+func NewAdminMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/users/{id}", GetUser)
+	mux.HandleFunc("GET /admin/audit", ListAuditLogs)
+	mux.HandleFunc("GET /admin/health", Health)
+	return mux
+}
+
+// NewAnonymousUser is not an entrypoint, as it does not return a router.
+func NewAnonymousUser() models.User {
+	return models.User{Name: "anonymous"}
+}
+
+// GetUser returns a user.
+func GetUser(w http.ResponseWriter, r *http.Request) {
+	user := models.User{ID: r.PathValue("id")}
+	json.NewEncoder(w).Encode(user)
+}
+
+// ListAuditLogs returns the audit logs.
+func ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	logs := []models.AuditLog{}
+	json.NewEncoder(w).Encode(logs)
+}
+
+// Health reports the service status.
+func Health(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
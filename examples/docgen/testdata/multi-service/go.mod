@@ -0,0 +1,5 @@
+module multi-service
+
+go 1.21
+
+replace github.com/podhmo/go-scan => ../../../../
@@ -0,0 +1,13 @@
+package models
+
+// User is shared by the public and the admin services.
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AuditLog is only used by the admin service.
+type AuditLog struct {
+	Action string `json:"action"`
+	User   User   `json:"user"`
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"streaming/sse"
+)
+
+// Message is a message sent to the clients.
+type Message struct {
+	Text string `json:"text"`
+}
+
+var upgrader = websocket.Upgrader{}
+
+// Chat upgrades the connection to a WebSocket and sends messages.
+func Chat(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.WriteJSON(Message{Text: "hello"})
+}
+
+// Events streams the messages as server-sent events.
+func Events(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Write([]byte("data: hello\n\n"))
+}
+
+// Notifications streams the notifications with a helper.
+func Notifications(w http.ResponseWriter, r *http.Request) {
+	f := sse.Stream(w)
+	fmt.Fprintf(w, "data: %s\n\n", "hello")
+	f.Flush()
+}
+
+// GetMessage returns a message as JSON.
+func GetMessage(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(Message{Text: "hello"})
+}
+
+func main() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /chat", Chat)
+	mux.HandleFunc("GET /events", Events)
+	mux.HandleFunc("GET /notifications", Notifications)
+	mux.HandleFunc("GET /messages/latest", GetMessage)
+	http.ListenAndServe(":8080", mux)
+}
@@ -0,0 +1,7 @@
+module streaming
+
+go 1.24
+
+require github.com/gorilla/websocket v1.5.3
+
+replace github.com/gorilla/websocket => ./third_party/gorilla/websocket
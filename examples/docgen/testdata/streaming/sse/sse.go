@@ -0,0 +1,10 @@
+// Package sse is a small helper for streaming server-sent events.
+package sse
+
+import "net/http"
+
+// Stream prepares the response for streaming events.
+func Stream(w http.ResponseWriter) http.Flusher {
+	f, _ := w.(http.Flusher)
+	return f
+}
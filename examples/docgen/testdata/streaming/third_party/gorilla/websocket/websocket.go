@@ -0,0 +1,21 @@
+// Package websocket is a minimal stand-in for github.com/gorilla/websocket.
+package websocket
+
+import "net/http"
+
+// Conn represents a WebSocket connection.
+type Conn struct{}
+
+// WriteJSON writes the JSON encoding of v as a message.
+func (c *Conn) WriteJSON(v any) error { return nil }
+
+// Close closes the connection.
+func (c *Conn) Close() error { return nil }
+
+// Upgrader specifies parameters for upgrading an HTTP connection to a WebSocket connection.
+type Upgrader struct{}
+
+// Upgrade upgrades the HTTP server connection to the WebSocket protocol.
+func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request, responseHeader http.Header) (*Conn, error) {
+	return &Conn{}, nil
+}
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/examples/docgen/openapi"
+)
+
+// Mismatch is a difference between the generated spec and the behavior of the running handlers.
+type Mismatch struct {
+	Method  string
+	Path    string
+	Handler *openapi.Handler // nil if unknown
+	Message string
+}
+
+// format returns the mismatch with the location of its handler, relative to baseDir.
+func (m Mismatch) format(baseDir string) string {
+	var loc string
+	if m.Handler != nil {
+		file := m.Handler.File
+		if rel, err := filepath.Rel(baseDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = filepath.ToSlash(rel)
+		}
+		loc = fmt.Sprintf("%s:%d: ", file, m.Handler.Line)
+	}
+	return fmt.Sprintf("%s%s %s: %s", loc, m.Method, m.Path, m.Message)
+}
+
+// VerifyDocument issues a request synthesized from the parameters and the request body of each
+// operation of the document to the server at baseURL, and checks that the status code of the
+// response is documented, and that its body matches the documented schema.
+func VerifyDocument(ctx context.Context, client *http.Client, baseURL string, doc *openapi.OpenAPI) ([]Mismatch, error) {
+	var mismatches []Mismatch
+	for _, path := range sortedKeys(doc.Paths) {
+		for _, method := range openapi.Methods {
+			op := doc.Paths[path].Operation(method)
+			if op == nil || op.XWebSocket {
+				continue
+			}
+			report := func(format string, args ...any) {
+				mismatches = append(mismatches, Mismatch{Method: method, Path: path, Handler: op.Handler, Message: fmt.Sprintf(format, args...)})
+			}
+
+			req, err := synthesizeRequest(ctx, doc, baseURL, method, path, op)
+			if err != nil {
+				return nil, fmt.Errorf("building the request of %s %s: %w", method, path, err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				report("request failed: %v", err)
+				continue
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				report("reading the response failed: %v", err)
+				continue
+			}
+			for _, msg := range checkResponse(doc, op, resp, body) {
+				report("%s", msg)
+			}
+		}
+	}
+	return mismatches, nil
+}
+
+// synthesizeRequest builds a request for the operation, with example values of the schemas of
+// its parameters and of its request body.
+func synthesizeRequest(ctx context.Context, doc *openapi.OpenAPI, baseURL, method, path string, op *openapi.Operation) (*http.Request, error) {
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+	for _, p := range op.Parameters {
+		value := exampleString(schemaExample(doc, p.Schema, nil))
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(value))
+		case "query":
+			query.Set(p.Name, value)
+		case "header":
+			header.Set(p.Name, value)
+		case "cookie":
+			cookies = append(cookies, &http.Cookie{Name: p.Name, Value: value})
+		}
+	}
+	target := strings.TrimSuffix(baseURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	var contentType string
+	if op.RequestBody != nil {
+		for _, ct := range sortedKeys(op.RequestBody.Content) {
+			contentType = ct
+			example := schemaExample(doc, op.RequestBody.Content[ct].Schema, nil)
+			if strings.Contains(ct, "json") {
+				b, err := json.Marshal(example)
+				if err != nil {
+					return nil, err
+				}
+				body = bytes.NewReader(b)
+			} else {
+				body = strings.NewReader(exampleString(example))
+			}
+			break
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+// exampleString returns an example value as the string of a parameter.
+func exampleString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "1"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// checkResponse checks that the status code of the response is documented, and that its body
+// has a documented content type and matches the documented schema.
+func checkResponse(doc *openapi.OpenAPI, op *openapi.Operation, resp *http.Response, body []byte) []string {
+	res, ok := op.Responses[strconv.Itoa(resp.StatusCode)]
+	if !ok {
+		res, ok = op.Responses["default"]
+	}
+	if !ok {
+		return []string{fmt.Sprintf("status %d is not documented, want one of %s", resp.StatusCode, strings.Join(sortedKeys(op.Responses), ", "))}
+	}
+	if len(res.Content) == 0 || len(body) == 0 {
+		return nil
+	}
+
+	contentType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		contentType = http.DetectContentType(body)
+		contentType, _, _ = mime.ParseMediaType(contentType)
+	}
+	media, ok := res.Content[contentType]
+	if !ok {
+		return []string{fmt.Sprintf("status %d: content type %q is not documented, want one of %s", resp.StatusCode, contentType, strings.Join(sortedKeys(res.Content), ", "))}
+	}
+	if media.Schema == nil || !strings.Contains(contentType, "json") {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{fmt.Sprintf("status %d: invalid JSON body: %v", resp.StatusCode, err)}
+	}
+	var msgs []string
+	for _, msg := range validateSchema(doc, media.Schema, value, "body") {
+		msgs = append(msgs, fmt.Sprintf("status %d: %s", resp.StatusCode, msg))
+	}
+	return msgs
+}
+
+// validateSchema validates a decoded JSON value against the schema, and returns the mismatches
+// found, located by at, e.g. "body.items[0].name". As encoding/json writes the nil slices, maps
+// and pointers as null, null matches any schema.
+func validateSchema(doc *openapi.OpenAPI, s *openapi.Schema, value any, at string) []string {
+	if s == nil || value == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		target, ok := componentSchema(doc, s.Ref)
+		if !ok {
+			return []string{fmt.Sprintf("%s: unknown schema %s", at, s.Ref)}
+		}
+		return validateSchema(doc, target, value, at)
+	}
+	mismatch := func(format string, args ...any) []string {
+		return []string{at + ": " + fmt.Sprintf(format, args...)}
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if reflect.DeepEqual(normalizeJSON(e), value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return mismatch("%v is not one of %v", value, s.Enum)
+		}
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return mismatch("got %s, want object", jsonTypeName(value))
+		}
+		var msgs []string
+		for _, name := range sortedKeys(obj) {
+			prop, ok := s.Properties[name]
+			if !ok {
+				prop = s.AdditionalProperties
+			}
+			if prop == nil {
+				if len(s.Properties) > 0 || s.AdditionalProperties != nil {
+					msgs = append(msgs, fmt.Sprintf("%s: property %q is not documented", at, name))
+				}
+				continue
+			}
+			msgs = append(msgs, validateSchema(doc, prop, obj[name], at+"."+name)...)
+		}
+		return msgs
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return mismatch("got %s, want array", jsonTypeName(value))
+		}
+		var msgs []string
+		for i, item := range items {
+			msgs = append(msgs, validateSchema(doc, s.Items, item, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+		return msgs
+	case "string":
+		if _, ok := value.(string); !ok {
+			return mismatch("got %s, want string", jsonTypeName(value))
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			return mismatch("got %s, want integer", jsonTypeName(value))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return mismatch("got %s, want number", jsonTypeName(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return mismatch("got %s, want boolean", jsonTypeName(value))
+		}
+	}
+	return nil
+}
+
+// normalizeJSON converts a value to its form decoded from JSON, e.g. an int to a float64.
+func normalizeJSON(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var normalized any
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return v
+	}
+	return normalized
+}
+
+// jsonTypeName returns the JSON type of a decoded value, e.g. "string".
+func jsonTypeName(v any) string {
+	switch v := v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+// serverTemplate is the program serving the handler of an entrypoint with httptest: it prints
+// the URL of the server, and serves until its standard input is closed.
+var serverTemplate = template.Must(template.New("server").Parse(`// Code generated by docgen verify. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	target {{printf "%q" .ImportPath}}
+)
+
+func main() {
+	var h http.Handler = target.{{.Entrypoint}}()
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	fmt.Println(srv.URL)
+	io.Copy(io.Discard, os.Stdin)
+}
+`))
+
+// startServer builds and runs a program serving the handler returned by the entrypoint function
+// of the package with httptest, in a temporary directory of the module of the package so that
+// the package can be imported. It returns the URL of the server, and the function stopping it.
+func startServer(ctx context.Context, s *goscan.Scanner, importPath, entrypoint string) (string, func() error, error) {
+	pkg, err := s.ScanPackageFromImportPath(ctx, importPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load package %q: %w", importPath, err)
+	}
+	if pkg.Name == "main" {
+		return "", nil, fmt.Errorf("package %q is a main package which cannot be imported, use -base-url with a running server instead", importPath)
+	}
+	found := false
+	for _, f := range pkg.Functions {
+		if f.Name == entrypoint && f.Receiver == nil && len(f.Parameters) == 0 && len(f.Results) == 1 {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", nil, fmt.Errorf("entrypoint %q must be a function without parameters returning a handler to be served", entrypoint)
+	}
+
+	moduleDir := pkg.ModuleDir
+	if moduleDir == "" {
+		moduleDir = pkg.Path
+	}
+	dir, err := os.MkdirTemp(moduleDir, ".docgen-verify-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create the directory of the server: %w", err)
+	}
+	var src bytes.Buffer
+	if err := serverTemplate.Execute(&src, map[string]string{"ImportPath": pkg.ImportPath, "Entrypoint": entrypoint}); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src.Bytes(), 0644); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", "run", "./"+filepath.Base(dir))
+	cmd.Dir = moduleDir
+	cmd.Stderr = &stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("failed to run the server: %w", err)
+	}
+	stop := func() error {
+		stdin.Close()
+		err := cmd.Wait()
+		os.RemoveAll(dir)
+		return err
+	}
+
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		stop()
+		return "", nil, fmt.Errorf("failed to start the server of %s.%s: %w\n%s", importPath, entrypoint, err, stderr.String())
+	}
+	return strings.TrimSpace(line), stop, nil
+}
+
+// verifyServices verifies the document of each service against its handler, served by a
+// program built for the entrypoint, or against the server at baseURL if given. The mismatches
+// are written to w, and an error is returned if there is any.
+func verifyServices(ctx context.Context, w io.Writer, s *goscan.Scanner, importPath string, services []*Service, baseURL string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	var mismatches []Mismatch
+	for _, svc := range services {
+		serverURL := baseURL
+		if serverURL == "" {
+			started, stop, err := startServer(ctx, s, importPath, svc.Name)
+			if err != nil {
+				return err
+			}
+			defer stop()
+			serverURL = started
+		}
+		found, err := VerifyDocument(ctx, http.DefaultClient, serverURL, svc.OpenAPI)
+		if err != nil {
+			return err
+		}
+		mismatches = append(mismatches, found...)
+	}
+
+	for _, m := range mismatches {
+		fmt.Fprintln(w, m.format(wd))
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%d mismatches between the spec and the handlers", len(mismatches))
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/examples/docgen/openapi"
+)
+
+func TestVerifyDocument(t *testing.T) {
+	limitMin := 5.0
+	doc := newOpenAPI()
+	doc.Components.Schemas["User"] = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"id":   {Type: "integer"},
+			"name": {Type: "string"},
+			"role": {Type: "string", Enum: []any{"admin", "member"}},
+		},
+	}
+	userResponse := map[string]*openapi.Response{
+		"200": {Description: "OK", Content: map[string]openapi.MediaType{
+			"application/json": {Schema: &openapi.Schema{Ref: "#/components/schemas/User"}},
+		}},
+	}
+	handler := &openapi.Handler{Name: "example.com/api.getUser", File: "/src/api/users.go", Line: 10}
+	doc.Paths["/users/{id}"] = &openapi.PathItem{
+		Get: &openapi.Operation{
+			Parameters: []*openapi.Parameter{{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer"}}},
+			Responses:  userResponse,
+			Handler:    handler,
+		},
+		Put: &openapi.Operation{
+			Parameters:  []*openapi.Parameter{{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer"}}},
+			RequestBody: &openapi.RequestBody{Content: map[string]openapi.MediaType{"application/json": {Schema: &openapi.Schema{Ref: "#/components/schemas/User"}}}},
+			Responses:   userResponse,
+		},
+	}
+	doc.Paths["/users"] = &openapi.PathItem{
+		Get: &openapi.Operation{
+			Parameters: []*openapi.Parameter{{Name: "limit", In: "query", Schema: &openapi.Schema{Type: "integer", Minimum: &limitMin}}},
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "OK", Content: map[string]openapi.MediaType{
+					"application/json": {Schema: &openapi.Schema{Type: "array", Items: &openapi.Schema{Ref: "#/components/schemas/User"}}},
+				}},
+			},
+		},
+	}
+
+	var gotRequests []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		gotRequests = append(gotRequests, "GET "+r.URL.String())
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"id": "1", "name": "foo", "role": "owner", "email": "foo@example.com"})
+	})
+	mux.HandleFunc("PUT /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		gotRequests = append(gotRequests, "PUT "+r.URL.String()+" "+r.Header.Get("Content-Type"))
+		if body["role"] != "admin" {
+			http.Error(w, "invalid role", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	})
+	mux.HandleFunc("GET /users", func(w http.ResponseWriter, r *http.Request) {
+		gotRequests = append(gotRequests, "GET "+r.URL.String())
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": 1, "name": "foo", "role": "admin"}, {"id": 2, "name": null}]`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mismatches, err := VerifyDocument(context.Background(), srv.Client(), srv.URL, doc)
+	if err != nil {
+		t.Fatalf("VerifyDocument() failed: %v", err)
+	}
+	var got []string
+	for _, m := range mismatches {
+		got = append(got, m.format("/src"))
+	}
+	want := []string{
+		`api/users.go:10: GET /users/{id}: status 200: body: property "email" is not documented`,
+		`api/users.go:10: GET /users/{id}: status 200: body.id: got string, want integer`,
+		`api/users.go:10: GET /users/{id}: status 200: body.role: owner is not one of [admin member]`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatches (-want +got):\n%s", diff)
+	}
+	wantRequests := []string{
+		"GET /users?limit=5",
+		"GET /users/0",
+		"PUT /users/0 application/json",
+	}
+	if diff := cmp.Diff(wantRequests, gotRequests); diff != "" {
+		t.Errorf("requests (-want +got):\n%s", diff)
+	}
+
+	t.Run("undocumented status", func(t *testing.T) {
+		delete(doc.Paths, "/users")
+		delete(doc.Paths, "/users/{id}")
+		doc.Paths["/items"] = &openapi.PathItem{Post: &openapi.Operation{Responses: userResponse}}
+		mux.HandleFunc("POST /items", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "not implemented", http.StatusNotImplemented)
+		})
+		mismatches, err := VerifyDocument(context.Background(), srv.Client(), srv.URL, doc)
+		if err != nil {
+			t.Fatalf("VerifyDocument() failed: %v", err)
+		}
+		if len(mismatches) != 1 || mismatches[0].Message != "status 501 is not documented, want one of 200" {
+			t.Errorf("unexpected mismatches: %+v", mismatches)
+		}
+	})
+}
+
+func TestVerifyServices_sampleAPI(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs the sample API")
+	}
+	const sampleAPIPath = "github.com/podhmo/go-scan/examples/docgen/sampleapi"
+	logger := newTestLogger(os.Stderr)
+	s, err := goscan.New(goscan.WithGoModuleResolver(), goscan.WithLogger(logger))
+	if err != nil {
+		t.Fatalf("failed to create scanner: %v", err)
+	}
+	analyzer, err := NewAnalyzer(s, logger, []string{"net/http"})
+	if err != nil {
+		t.Fatalf("failed to create analyzer: %v", err)
+	}
+	ctx := context.Background()
+	services, err := analyzer.AnalyzeServices(ctx, sampleAPIPath, []string{"NewServeMux"})
+	if err != nil {
+		t.Fatalf("failed to analyze package: %+v", err)
+	}
+
+	// The status of createUser is documented as 200, as the code given to WriteHeader is not
+	// analyzed yet, while the handler writes 201.
+	var out bytes.Buffer
+	if err := verifyServices(ctx, &out, s, sampleAPIPath, services, ""); err == nil {
+		t.Fatalf("expected mismatches")
+	}
+	want := "sampleapi/api.go:46: POST /users: status 201 is not documented, want one of 200\n"
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("output (-want +got):\n%s", diff)
+	}
+}
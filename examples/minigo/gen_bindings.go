@@ -1,10 +1,12 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"flag"
 	"fmt"
 	"go/ast"
+	"go/format"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -20,30 +22,32 @@ var tmplGenBindings = template.Must(template.New("install.go").Parse(`// Code ge
 package {{ .PackageName }}
 
 import (
-	{{- if .TypeSymbols }}
-	"reflect"
+	{{- range .StdImports }}
+	{{ if .Alias }}{{ .Alias }} {{ end }}"{{ .Path }}"
+	{{- end }}
+	{{ range .Imports }}
+	{{ if .Alias }}{{ .Alias }} {{ end }}"{{ .Path }}"
 	{{- end }}
-	"{{ .PackagePath }}"
-	"github.com/podhmo/go-scan/minigo"
 )
 
 // Install binds all exported symbols from the "{{ .PackagePath }}" package to the interpreter.
 func Install(interp *minigo.Interpreter) {
 	interp.Register("{{ .PackagePath }}", map[string]any{
-		{{- range .ValueSymbols }}
-		"{{ . }}": {{ $.PackageName }}.{{ . }},
-		{{- end }}
-		{{- range .TypeSymbols }}
-		"{{ . }}": reflect.TypeOf((*{{ $.PackageName }}.{{ . }})(nil)).Elem(),
+		{{- range .Symbols }}
+		"{{ .Name }}": {{ .Expr }},{{ if .Comment }} // {{ .Comment }}{{ end }}
 		{{- end }}
 	})
 }
+{{ range .Funcs }}
+{{ . }}
+{{ end }}
 `))
 
 func runGenBindings(args []string) {
 	fs := flag.NewFlagSet("gen-bindings", flag.ExitOnError)
 	var (
 		outputDir = fs.String("output", "", "output directory")
+		pkg       = fs.String("pkg", "", "package path to generate bindings for (in addition to the arguments)")
 	)
 	fs.Parse(args)
 
@@ -53,6 +57,9 @@ func runGenBindings(args []string) {
 	}
 
 	pkgPaths := fs.Args()
+	if *pkg != "" {
+		pkgPaths = append([]string{*pkg}, pkgPaths...)
+	}
 	if len(pkgPaths) == 0 {
 		slog.Error("at least one package path is required")
 		os.Exit(1)
@@ -83,6 +90,13 @@ func runGenBindingsInternal(ctx context.Context, outputDir string, pkgPaths []st
 	return nil
 }
 
+// bindingSymbol is an entry of the map passed to interp.Register.
+type bindingSymbol struct {
+	Name    string
+	Expr    string
+	Comment string
+}
+
 func generate(ctx context.Context, s *goscan.Scanner, outputDir, pkgPath string) error {
 	var pkgInfo *goscan.Package
 	var err error
@@ -95,57 +109,113 @@ func generate(ctx context.Context, s *goscan.Scanner, outputDir, pkgPath string)
 		return fmt.Errorf("failed to scan package %s: %w", pkgPath, err)
 	}
 
-	valueSymbols := make(map[string]struct{})
-	typeSymbols := make(map[string]struct{})
+	// The names used by the generated code itself are reserved first, so that the bound
+	// package (and the packages in its signatures) are renamed on conflict instead.
+	imports := newImportSet()
+	for _, path := range []string{"reflect", "go/token", "github.com/podhmo/go-scan/minigo", "github.com/podhmo/go-scan/minigo/object", "github.com/podhmo/go-scan/minigo/ffibridge"} {
+		imports.reserve(path)
+	}
+	pkgName := imports.use(pkgInfo.Name, pkgInfo.ImportPath)
+
+	var symbols []bindingSymbol
+	var funcs []string
+	seen := make(map[string]bool)
+	addSymbol := func(sym bindingSymbol) {
+		if seen[sym.Name] {
+			return
+		}
+		seen[sym.Name] = true
+		symbols = append(symbols, sym)
+	}
 
 	for _, f := range pkgInfo.Functions {
 		if len(f.TypeParams) > 0 {
 			continue
 		}
-		if f.Receiver == nil && f.AstDecl != nil && f.AstDecl.Name != nil && f.AstDecl.Name.IsExported() {
-			valueSymbols[f.Name] = struct{}{}
+		if f.Receiver != nil || f.AstDecl == nil || f.AstDecl.Name == nil || !f.AstDecl.Name.IsExported() {
+			continue
+		}
+		file := pkgInfo.AstFiles[f.FilePath]
+		var importLookup map[string]string
+		if file != nil {
+			importLookup = s.BuildImportLookup(file)
 		}
+		g := &funcGenerator{pkgName: pkgName, importLookup: importLookup, imports: imports}
+		code, reason := g.generate(f.Name, f.AstDecl.Type)
+		if reason != "" {
+			// The wrapper cannot be generated, so the function is called through reflection.
+			slog.DebugContext(ctx, "falling back to reflection", slog.String("func", f.Name), slog.String("reason", reason))
+			addSymbol(bindingSymbol{Name: f.Name, Expr: pkgName + "." + f.Name, Comment: "reflection-based: " + reason})
+			continue
+		}
+		addSymbol(bindingSymbol{Name: f.Name, Expr: fmt.Sprintf("&object.Builtin{Fn: %s}", bindFuncName(f.Name))})
+		funcs = append(funcs, code)
 	}
 	for _, c := range pkgInfo.Constants {
 		if c.IsExported {
-			valueSymbols[c.Name] = struct{}{}
+			addSymbol(bindingSymbol{Name: c.Name, Expr: pkgName + "." + c.Name})
 		}
 	}
 	for _, v := range pkgInfo.Variables {
 		if v.IsExported {
-			valueSymbols[v.Name] = struct{}{}
+			addSymbol(bindingSymbol{Name: v.Name, Expr: pkgName + "." + v.Name})
 		}
 	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Name < symbols[j].Name })
+	sort.Strings(funcs)
+
+	var typeSymbols []bindingSymbol
 	for _, t := range pkgInfo.Types {
-		if t.Name != "" && ast.IsExported(t.Name) {
-			typeSymbols[t.Name] = struct{}{}
+		if t.Name != "" && ast.IsExported(t.Name) && !seen[t.Name] {
+			seen[t.Name] = true
+			typeSymbols = append(typeSymbols, bindingSymbol{Name: t.Name, Expr: fmt.Sprintf("reflect.TypeOf((*%s.%s)(nil)).Elem()", pkgName, t.Name)})
 		}
 	}
+	sort.Slice(typeSymbols, func(i, j int) bool { return typeSymbols[i].Name < typeSymbols[j].Name })
+	symbols = append(symbols, typeSymbols...)
 
-	valueSymbolSlice := make([]string, 0, len(valueSymbols))
-	for s := range valueSymbols {
-		valueSymbolSlice = append(valueSymbolSlice, s)
+	imports.use("minigo", "github.com/podhmo/go-scan/minigo")
+	if len(funcs) > 0 {
+		imports.use("token", "go/token")
+		imports.use("object", "github.com/podhmo/go-scan/minigo/object")
+		imports.use("ffibridge", "github.com/podhmo/go-scan/minigo/ffibridge")
 	}
-	sort.Strings(valueSymbolSlice)
-
-	typeSymbolSlice := make([]string, 0, len(typeSymbols))
-	for s := range typeSymbols {
-		typeSymbolSlice = append(typeSymbolSlice, s)
+	if len(typeSymbols) > 0 {
+		imports.use("reflect", "reflect")
 	}
-	sort.Strings(typeSymbolSlice)
 
-	pkgName := pkgInfo.Name
+	var stdImports, otherImports []importSpec
+	for _, spec := range imports.specs() {
+		if strings.Contains(strings.SplitN(spec.Path, "/", 2)[0], ".") {
+			otherImports = append(otherImports, spec)
+		} else {
+			stdImports = append(stdImports, spec)
+		}
+	}
 
 	params := struct {
-		PackageName  string
-		PackagePath  string
-		ValueSymbols []string
-		TypeSymbols  []string
+		PackageName string
+		PackagePath string
+		StdImports  []importSpec
+		Imports     []importSpec
+		Symbols     []bindingSymbol
+		Funcs       []string
 	}{
-		PackageName:  pkgName,
-		PackagePath:  pkgInfo.ImportPath,
-		ValueSymbols: valueSymbolSlice,
-		TypeSymbols:  typeSymbolSlice,
+		PackageName: pkgInfo.Name,
+		PackagePath: pkgInfo.ImportPath,
+		StdImports:  stdImports,
+		Imports:     otherImports,
+		Symbols:     symbols,
+		Funcs:       funcs,
+	}
+
+	var buf bytes.Buffer
+	if err := tmplGenBindings.Execute(&buf, params); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format generated code: %w\n%s", err, buf.String())
 	}
 
 	pkgOutputDir := filepath.Join(outputDir, pkgPath)
@@ -154,15 +224,379 @@ func generate(ctx context.Context, s *goscan.Scanner, outputDir, pkgPath string)
 	}
 
 	filePath := filepath.Join(pkgOutputDir, "install.go")
-	f, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file %q: %w", filePath, err)
-	}
-	defer f.Close()
-
-	if err := tmplGenBindings.Execute(f, params); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	if err := os.WriteFile(filePath, source, 0644); err != nil {
+		return fmt.Errorf("failed to write output file %q: %w", filePath, err)
 	}
 	slog.InfoContext(ctx, "generated", slog.String("path", filePath))
 	return nil
 }
+
+func bindFuncName(name string) string {
+	return "bind" + name
+}
+
+// convKind classifies a Go type by the conversion the generated code uses for it.
+type convKind int
+
+const (
+	convValue convKind = iota // any other type, converted with ffibridge.ToValue[T] and ffibridge.FromValue
+	convString
+	convInt
+	convUint
+	convFloat
+	convBool
+	convError
+	convBytes
+	convInts
+	convFloat64s
+	convStrings
+)
+
+// paramConverters are the ffibridge functions converting a minigo object to a Go value.
+var paramConverters = map[convKind]string{
+	convString:   "ffibridge.ToString",
+	convInt:      "ffibridge.ToInt64",
+	convUint:     "ffibridge.ToUint64",
+	convFloat:    "ffibridge.ToFloat64",
+	convBool:     "ffibridge.ToBool",
+	convError:    "ffibridge.ToValue[error]",
+	convBytes:    "ffibridge.ToBytes",
+	convInts:     "ffibridge.ToInts",
+	convFloat64s: "ffibridge.ToFloat64s",
+	convStrings:  "ffibridge.ToStrings",
+}
+
+var builtinConvKinds = map[string]convKind{
+	"string": convString,
+	"int":    convInt, "int8": convInt, "int16": convInt, "int32": convInt, "int64": convInt, "rune": convInt,
+	"uint": convUint, "uint8": convUint, "uint16": convUint, "uint32": convUint, "uint64": convUint, "uintptr": convUint, "byte": convUint,
+	"float32": convFloat, "float64": convFloat,
+	"bool":  convBool,
+	"error": convError,
+}
+
+// goType is a type of the bound function, as written in the generated package.
+type goType struct {
+	kind convKind
+	expr string
+}
+
+// funcGenerator generates the Go code of the builtin function wrapping a bound function.
+type funcGenerator struct {
+	pkgName      string
+	importLookup map[string]string // of the file declaring the function
+	imports      *importSet
+}
+
+// generate returns the code of the wrapper, or the reason why no wrapper can be generated.
+func (g *funcGenerator) generate(name string, ft *ast.FuncType) (string, string) {
+	var params []goType
+	variadic := false
+	if ft.Params != nil {
+		for _, field := range ft.Params.List {
+			expr := field.Type
+			if ellipsis, ok := expr.(*ast.Ellipsis); ok {
+				expr = ellipsis.Elt
+				variadic = true
+			}
+			t, reason := g.paramType(expr)
+			if reason != "" {
+				return "", reason
+			}
+			for range max(1, len(field.Names)) {
+				params = append(params, t)
+			}
+		}
+	}
+	var results []convKind
+	if ft.Results != nil {
+		for _, field := range ft.Results.List {
+			kind := resultKind(field.Type)
+			for range max(1, len(field.Names)) {
+				results = append(results, kind)
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "func %s(ctx *object.BuiltinContext, pos token.Pos, args ...object.Object) (ret object.Object) {\n", bindFuncName(name))
+	b.WriteString("defer ffibridge.Recover(&ret)\n")
+	if variadic {
+		fmt.Fprintf(&b, "if len(args) < %d {\n", len(params)-1)
+		fmt.Fprintf(&b, "return ctx.NewError(pos, \"wrong number of arguments for variadic function: got %%d, want at least %%d\", len(args), %d)\n}\n", len(params)-1)
+	} else {
+		fmt.Fprintf(&b, "if len(args) != %d {\n", len(params))
+		fmt.Fprintf(&b, "return ctx.NewError(pos, \"wrong number of arguments: got %%d, want %%d\", len(args), %d)\n}\n", len(params))
+	}
+
+	var callArgs []string
+	var copyBacks []string
+	for i, p := range params {
+		arg := fmt.Sprintf("a%d", i)
+		if variadic && i == len(params)-1 {
+			fmt.Fprintf(&b, "var %s []%s\n", arg, p.expr)
+			fmt.Fprintf(&b, "for j, arg := range args[%d:] {\n", i)
+			fmt.Fprintf(&b, "v, err := %s(arg)\n", g.converter(p))
+			fmt.Fprintf(&b, "if err != nil {\nreturn ctx.NewError(pos, \"argument %%d type mismatch: %%v\", %d+j, err)\n}\n", i+1)
+			fmt.Fprintf(&b, "%s = append(%s, %s)\n}\n", arg, arg, castExpr(p, "v"))
+			callArgs = append(callArgs, arg+"...")
+			continue
+		}
+		fmt.Fprintf(&b, "%s, err := %s(args[%d])\n", arg, g.converter(p), i)
+		fmt.Fprintf(&b, "if err != nil {\nreturn ctx.NewError(pos, \"argument %%d type mismatch: %%v\", %d, err)\n}\n", i+1)
+		callArgs = append(callArgs, castExpr(p, arg))
+		switch p.kind {
+		case convBytes, convInts, convFloat64s, convStrings:
+			copyBacks = append(copyBacks, fmt.Sprintf("ffibridge.CopyBack(args[%d], %s)\n", i, arg))
+		}
+	}
+
+	call := fmt.Sprintf("%s.%s(%s)", g.pkgName, name, strings.Join(callArgs, ", "))
+	resultVars := make([]string, len(results))
+	for i := range results {
+		resultVars[i] = fmt.Sprintf("r%d", i)
+	}
+	if len(results) == 0 {
+		b.WriteString(call + "\n")
+	} else {
+		fmt.Fprintf(&b, "%s := %s\n", strings.Join(resultVars, ", "), call)
+	}
+	for _, cb := range copyBacks {
+		b.WriteString(cb)
+	}
+	switch len(results) {
+	case 0:
+		b.WriteString("return object.NIL\n")
+	case 1:
+		fmt.Fprintf(&b, "return %s\n", resultExpr(results[0], resultVars[0]))
+	default:
+		b.WriteString("return &object.Tuple{Elements: []object.Object{\n")
+		for i, kind := range results {
+			fmt.Fprintf(&b, "%s,\n", resultExpr(kind, resultVars[i]))
+		}
+		b.WriteString("}}\n")
+	}
+	b.WriteString("}")
+	return b.String(), ""
+}
+
+func (g *funcGenerator) converter(t goType) string {
+	if c, ok := paramConverters[t.kind]; ok {
+		return c
+	}
+	return fmt.Sprintf("ffibridge.ToValue[%s]", t.expr)
+}
+
+// paramType returns the type of a parameter, or the reason why it cannot be converted
+// without the evaluator (e.g. minigo functions passed as callbacks, or structs passed as `any`).
+func (g *funcGenerator) paramType(expr ast.Expr) (goType, string) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if kind, ok := builtinConvKinds[t.Name]; ok {
+			return goType{kind: kind, expr: t.Name}, ""
+		}
+		if t.Name == "any" {
+			return goType{}, "empty interface parameter"
+		}
+	case *ast.ArrayType:
+		if t.Len == nil {
+			if elem, ok := t.Elt.(*ast.Ident); ok {
+				switch elem.Name {
+				case "byte", "uint8":
+					return goType{kind: convBytes, expr: "[]byte"}, ""
+				case "int":
+					return goType{kind: convInts, expr: "[]int"}, ""
+				case "float64":
+					return goType{kind: convFloat64s, expr: "[]float64"}, ""
+				case "string":
+					return goType{kind: convStrings, expr: "[]string"}, ""
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		if t.Methods == nil || len(t.Methods.List) == 0 {
+			return goType{}, "empty interface parameter"
+		}
+		return goType{}, "interface literal parameter"
+	case *ast.FuncType:
+		return goType{}, "func parameter"
+	case *ast.StructType:
+		return goType{}, "struct literal parameter"
+	}
+
+	rendered, reason := g.render(expr)
+	if reason != "" {
+		return goType{}, reason
+	}
+	return goType{kind: convValue, expr: rendered}, ""
+}
+
+// render writes a type expression as it is spelled in the generated package.
+func (g *funcGenerator) render(expr ast.Expr) (string, string) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if _, ok := builtinConvKinds[t.Name]; ok || t.Name == "any" || t.Name == "complex64" || t.Name == "complex128" {
+			return t.Name, ""
+		}
+		if !t.IsExported() {
+			return "", "unexported type parameter"
+		}
+		return g.pkgName + "." + t.Name, ""
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok {
+			return "", "unsupported qualified type"
+		}
+		path, ok := g.importLookup[x.Name]
+		if !ok {
+			return "", fmt.Sprintf("unknown package %q", x.Name)
+		}
+		return g.imports.use(x.Name, path) + "." + t.Sel.Name, ""
+	case *ast.StarExpr:
+		elem, reason := g.render(t.X)
+		return "*" + elem, reason
+	case *ast.ArrayType:
+		elem, reason := g.render(t.Elt)
+		if reason != "" {
+			return "", reason
+		}
+		if t.Len == nil {
+			return "[]" + elem, ""
+		}
+		if lit, ok := t.Len.(*ast.BasicLit); ok {
+			return "[" + lit.Value + "]" + elem, ""
+		}
+		return "", "array length expression"
+	case *ast.MapType:
+		key, reason := g.render(t.Key)
+		if reason != "" {
+			return "", reason
+		}
+		value, reason := g.render(t.Value)
+		if reason != "" {
+			return "", reason
+		}
+		return "map[" + key + "]" + value, ""
+	case *ast.ChanType:
+		elem, reason := g.render(t.Value)
+		if reason != "" {
+			return "", reason
+		}
+		switch t.Dir {
+		case ast.SEND:
+			return "chan<- " + elem, ""
+		case ast.RECV:
+			return "<-chan " + elem, ""
+		default:
+			return "chan " + elem, ""
+		}
+	case *ast.ParenExpr:
+		return g.render(t.X)
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return "", "generic type parameter"
+	case *ast.FuncType:
+		return "", "func parameter"
+	case *ast.InterfaceType:
+		if t.Methods == nil || len(t.Methods.List) == 0 {
+			return "interface{}", ""
+		}
+		return "", "interface literal parameter"
+	case *ast.StructType:
+		return "", "struct literal parameter"
+	}
+	return "", fmt.Sprintf("unsupported type %T", expr)
+}
+
+// resultKind classifies a result type. Results are never spelled out in the generated
+// code, so every type is supported.
+func resultKind(expr ast.Expr) convKind {
+	if id, ok := expr.(*ast.Ident); ok {
+		if kind, ok := builtinConvKinds[id.Name]; ok {
+			return kind
+		}
+	}
+	return convValue
+}
+
+func resultExpr(kind convKind, v string) string {
+	switch kind {
+	case convString:
+		return fmt.Sprintf("&object.String{Value: %s}", v)
+	case convInt, convUint:
+		return fmt.Sprintf("&object.Integer{Value: int64(%s)}", v)
+	case convFloat:
+		return fmt.Sprintf("&object.Float{Value: float64(%s)}", v)
+	case convBool:
+		return fmt.Sprintf("ffibridge.FromBool(%s)", v)
+	case convError:
+		return fmt.Sprintf("ffibridge.FromError(%s)", v)
+	default:
+		return fmt.Sprintf("ffibridge.FromValue(%s)", v)
+	}
+}
+
+// castExpr converts the value returned by a numeric converter to the declared parameter type.
+func castExpr(t goType, v string) string {
+	switch t.kind {
+	case convInt, convUint, convFloat:
+		return fmt.Sprintf("%s(%s)", t.expr, v)
+	}
+	return v
+}
+
+type importSpec struct {
+	Alias string
+	Path  string
+}
+
+// importSet tracks the imports of the generated file, giving each import path a unique name.
+type importSet struct {
+	names map[string]string // import path -> name used in the generated code
+	paths map[string]string // name -> import path
+	used  map[string]bool   // import paths referenced by the generated code
+}
+
+func newImportSet() *importSet {
+	return &importSet{names: make(map[string]string), paths: make(map[string]string), used: make(map[string]bool)}
+}
+
+// reserve registers the import path under its default name, without importing it.
+func (s *importSet) reserve(path string) {
+	s.assign(path[strings.LastIndex(path, "/")+1:], path)
+}
+
+// use imports the path, preferring the given name, and returns the name to use.
+func (s *importSet) use(name, path string) string {
+	s.used[path] = true
+	return s.assign(name, path)
+}
+
+func (s *importSet) assign(name, path string) string {
+	if n, ok := s.names[path]; ok {
+		return n
+	}
+	candidate := name
+	for i := 2; ; i++ {
+		if _, taken := s.paths[candidate]; !taken {
+			break
+		}
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	s.names[path] = candidate
+	s.paths[candidate] = path
+	return candidate
+}
+
+func (s *importSet) specs() []importSpec {
+	specs := make([]importSpec, 0, len(s.used))
+	for path := range s.used {
+		name := s.names[path]
+		spec := importSpec{Path: path}
+		if name != path[strings.LastIndex(path, "/")+1:] {
+			spec.Alias = name
+		}
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Path < specs[j].Path })
+	return specs
+}
@@ -29,6 +29,11 @@ func TestGenerate(t *testing.T) {
 			pkgPath:    "github.com/podhmo/go-scan/examples/minigo/testdata/bindings/varsandtypes",
 			goldenFile: "bindings/varsandtypes.golden",
 		},
+		{
+			name:       "signatures",
+			pkgPath:    "github.com/podhmo/go-scan/examples/minigo/testdata/bindings/signatures",
+			goldenFile: "bindings/signatures.golden",
+		},
 	}
 
 	for _, tc := range cases {
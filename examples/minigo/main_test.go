@@ -210,6 +210,59 @@ func Config() MyConfig {
 		}
 	})
 
+	t.Run("file-based config with local import", func(t *testing.T) {
+		files := map[string]string{
+			"split/config.go": `
+package main
+
+import "./defaults"
+
+func Config() defaults.Server {
+	return defaults.Server{Name: "split-server", Port: defaults.Port}
+}`,
+			"split/defaults/defaults.go": `
+package defaults
+
+type Server struct {
+	Name string
+	Port int
+}
+
+const Port = 8081`,
+		}
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+		}
+
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := run(ctx, filepath.Join(dir, "split", "config.go"), "Config", "json", "")
+		if err != nil {
+			t.Errorf("run() error = %v", err)
+		}
+
+		w.Close()
+		os.Stdout = oldStdout
+		var out bytes.Buffer
+		out.ReadFrom(r)
+
+		expectedJSON := `{
+  "Name": "split-server",
+  "Port": 8081
+}`
+		if strings.TrimSpace(out.String()) != expectedJSON {
+			t.Errorf("Expected JSON output:\n%s\nGot:\n%s", expectedJSON, out.String())
+		}
+	})
+
 	t.Run("inline code", func(t *testing.T) {
 		evalCode := `
 package main
@@ -0,0 +1,44 @@
+package signatures
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+// Level is a named integer type.
+type Level int
+
+// Repeat returns s repeated n times.
+func Repeat(s string, n int) string { return strings.Repeat(s, n) }
+
+// Sum returns the sum of the numbers.
+func Sum(base int64, nums ...int) int64 {
+	for _, n := range nums {
+		base += int64(n)
+	}
+	return base
+}
+
+// Parse returns an error for empty input.
+func Parse(s string) (Level, error) {
+	if s == "" {
+		return 0, io.EOF
+	}
+	return Level(len(s)), nil
+}
+
+// Wait takes a named type from another package.
+func Wait(d time.Duration) bool { return d > 0 }
+
+// ReadAll takes an interface.
+func ReadAll(r io.Reader) ([]byte, error) { return io.ReadAll(r) }
+
+// Sort sorts the strings in place.
+func Sort(ss []string) {}
+
+// Apply takes a callback, so it is bound through reflection.
+func Apply(s string, fn func(string) string) string { return fn(s) }
+
+// Print takes an empty interface, so it is bound through reflection.
+func Print(args ...any) {}
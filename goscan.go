@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/token"
 	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -29,12 +31,25 @@ type ConstantInfo = scanner.ConstantInfo
 // FunctionInfo is an alias for scanner.FunctionInfo.
 type FunctionInfo = scanner.FunctionInfo
 
+// Directive is an alias for scanner.Directive.
+type Directive = scanner.Directive
+
 // PackageImports is an alias for scanner.PackageImports.
 type PackageImports = scanner.PackageImports
 
 // Visitor is an alias for scanner.Visitor.
 type Visitor = scanner.Visitor
 
+// LoadMode is an alias for scanner.LoadMode.
+type LoadMode = scanner.LoadMode
+
+// Re-export scanner load modes for convenience.
+const (
+	LoadFull    = scanner.LoadFull
+	LoadDecls   = scanner.LoadDecls
+	LoadImports = scanner.LoadImports
+)
+
 // Re-export scanner kinds for convenience.
 const (
 	StructKind    = scanner.StructKind
@@ -60,10 +75,39 @@ type Scanner struct {
 	Walker *ModuleWalker
 
 	// For multi-module workspace support
-	isWorkspace              bool
-	locators                 []*locator.Locator
-	moduleDirs               []string // temporary holder for module directories
-	declarationsOnlyPackages []string
+	isWorkspace     bool
+	locators        []*locator.Locator
+	moduleDirs      []string // temporary holder for module directories
+	defaultLoadMode scanner.LoadMode
+	loadModeRules   []scanner.LoadModeRule
+	collectCalls    bool // For the calls of the functions (WithStaticCalls)
+	partialResults  bool // For the files which cannot be parsed (WithPartialResults)
+
+	// For the lookup of the implementers of the interfaces (ImplementersOf)
+	implementers *implementerIndex
+
+	// For downloading the missing modules (WithAutoDownload)
+	autoDownload   bool
+	downloadBudget int
+
+	// For the diagnostics bundle (WithLogRecorder)
+	logRecorder *LogRecorder
+
+	// For the packages outside of the modules (WithPackageMapping)
+	packageMapping map[string]string
+
+	// For the legacy projects without go.mod (WithGOPATHMode)
+	gopathMode  bool
+	gopathRoots []string
+
+	// For the progress reports (WithProgress)
+	progress *progressReporter
+
+	// For the shared settings (WithConfigFile, WithExcludeDirs, WithBuildTags)
+	configFile    *FileConfig
+	configFileSet bool
+	excludeDirs   []string
+	buildTags     []string
 }
 
 // Fset returns the FileSet associated with the scanner.
@@ -118,28 +162,67 @@ func (s *Scanner) Modules() []*scanner.ModuleInfo {
 	return modules
 }
 
-// locatorForImportPath finds the correct locator for a given import path in workspace mode.
-func (s *Scanner) locatorForImportPath(importPath string) (*locator.Locator, error) {
+// ModuleOf returns the module that the package of the import path belongs to, among the modules
+// of the scanner (all of the modules in workspace mode), or nil if it belongs to none of them, e.g.
+// for the standard library and the dependencies. The package identities of a workspace are the
+// pairs of a module and an import path, see PackageInfo.ModulePath.
+func (s *Scanner) ModuleOf(importPath string) *scanner.ModuleInfo {
+	loc := s.ownerLocator(importPath)
+	if loc == nil {
+		return nil
+	}
+	return &scanner.ModuleInfo{Path: loc.ModulePath(), Dir: loc.RootDir()}
+}
+
+// ownerLocator returns the locator of the module that the import path belongs to, the module
+// with the longest module path matching it, or nil.
+func (s *Scanner) ownerLocator(importPath string) *locator.Locator {
+	locators := s.locators
 	if !s.isWorkspace {
-		return s.locator, nil
+		locators = []*locator.Locator{s.locator}
 	}
 
 	var bestMatch *locator.Locator
 	var bestMatchLen int
-
-	for _, loc := range s.locators {
+	for _, loc := range locators {
+		if loc == nil {
+			continue
+		}
 		modulePath := loc.ModulePath()
 		// Check for exact match or if the import path is a sub-package of the module.
-		if importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/") {
+		if modulePath != "" && (importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")) {
 			if len(modulePath) > bestMatchLen {
 				bestMatch = loc
 				bestMatchLen = len(modulePath)
 			}
 		}
 	}
+	return bestMatch
+}
+
+// locatorForImportPath finds the correct locator for a given import path in workspace mode.
+func (s *Scanner) locatorForImportPath(importPath string) (*locator.Locator, error) {
+	if !s.isWorkspace {
+		return s.locator, nil
+	}
 
-	if bestMatch != nil {
-		return bestMatch, nil
+	if loc := s.ownerLocator(importPath); loc != nil {
+		return loc, nil
+	}
+
+	// The mapped packages can be found by any locator, as they share the package mapping.
+	if len(s.locators) > 0 {
+		if _, ok := s.locators[0].MappedPackageDir(importPath); ok {
+			return s.locators[0], nil
+		}
+	}
+
+	// A package outside of the workspace modules can be imported through a replace directive
+	// of one of them, e.g. `replace example.com/lib => ./third_party/lib`.
+	for _, loc := range s.locators {
+		if loc.Replaces(importPath) {
+			return loc, nil
+		}
 	}
 
 	// Fallback for standard library packages: any locator can find them.
@@ -152,6 +235,19 @@ func (s *Scanner) locatorForImportPath(importPath string) (*locator.Locator, err
 	return nil, fmt.Errorf("could not find a module responsible for import path %q in workspace", importPath)
 }
 
+// locatorForDir returns the locator of the workspace module whose root directory contains the
+// directory, the innermost one for the nested modules, or nil.
+func (s *Scanner) locatorForDir(dir string) *locator.Locator {
+	var best *locator.Locator
+	for _, loc := range s.locators {
+		root := loc.RootDir()
+		if (dir == root || strings.HasPrefix(dir, root+string(filepath.Separator))) && (best == nil || len(root) > len(best.RootDir())) {
+			best = loc
+		}
+	}
+	return best
+}
+
 // BuildImportLookup creates a map of local import names to their full package paths for a given file.
 func (s *Scanner) BuildImportLookup(file *ast.File) map[string]string {
 	return s.scanner.BuildImportLookup(file)
@@ -191,8 +287,70 @@ func (s *Scanner) LookupOverride(qualifiedName string) (*scanner.TypeInfo, bool)
 // Each pattern can be a directory path or a file path relative to the scanner's workDir.
 // It returns a list of scanned packages.
 func (s *Scanner) Scan(ctx context.Context, patterns ...string) ([]*Package, error) {
+	s.progress.reset()
+	targets, err := s.locateTargets(ctx, patterns)
+	if err != nil {
+		return nil, err
+	}
+	s.progress.located(len(targets))
+
 	pkgsMap := make(map[string]*Package) // Use map to handle duplicates
+	for _, t := range targets {
+		var pkg *Package
+		var err error
+		targetCtx := withScanTarget(ctx, true)
+		switch {
+		case t.dir != "":
+			pkg, err = s.ScanPackageFromFilePath(targetCtx, t.dir)
+		case t.file != "":
+			pkg, err = s.ScanFiles(targetCtx, []string{t.file})
+		default:
+			pkg, err = s.ScanPackageFromImportPath(targetCtx, t.importPath)
+		}
+		if err != nil {
+			if t.wildcard {
+				// Log error but continue walking
+				slog.WarnContext(ctx, "failed to scan package during wildcard walk", "path", t.dir, "error", err)
+				s.progress.packageParsed("", false)
+				continue
+			}
+			return nil, fmt.Errorf("failed to scan pattern %q: %w", t.pattern, err)
+		}
+		if pkg != nil && pkg.ImportPath != "" {
+			pkgsMap[pkg.ImportPath] = pkg
+			s.progress.packageParsed(pkg.ImportPath, false)
+		} else {
+			s.progress.packageParsed("", false)
+		}
+	}
+	s.progress.packageParsed("", true)
+
+	// Convert map to slice
+	pkgs := make([]*Package, 0, len(pkgsMap))
+	for _, pkg := range pkgsMap {
+		pkgs = append(pkgs, pkg)
+	}
+	// Sort for deterministic output
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].ImportPath < pkgs[j].ImportPath
+	})
+	return pkgs, nil
+}
+
+// scanTarget is a package to scan, found by Scan for a pattern. Exactly one of dir, file and
+// importPath is set.
+type scanTarget struct {
+	pattern    string
+	dir        string // the directory of the package
+	file       string // a single file of the package
+	importPath string // an import path, for a pattern not found on the filesystem
+	wildcard   bool   // found by walking a "..." pattern, skipped if the scan fails
+}
 
+// locateTargets finds the packages matching the patterns, walking the directories of the
+// wildcard patterns.
+func (s *Scanner) locateTargets(ctx context.Context, patterns []string) ([]scanTarget, error) {
+	var targets []scanTarget
 	for _, pattern := range patterns {
 		if strings.Contains(pattern, "...") {
 			// Handle wildcard pattern
@@ -205,7 +363,7 @@ func (s *Scanner) Scan(ctx context.Context, patterns ...string) ([]*Package, err
 				absBasePath = filepath.Join(s.workDir, basePath)
 			} else {
 				var err error
-				absBasePath, err = s.locator.FindPackageDir(basePath)
+				absBasePath, err = s.locator.FindPackageDirContext(ctx, basePath)
 				if err != nil {
 					return nil, fmt.Errorf("could not find directory for import path pattern %q: %w", pattern, err)
 				}
@@ -218,6 +376,9 @@ func (s *Scanner) Scan(ctx context.Context, patterns ...string) ([]*Package, err
 				if !d.IsDir() {
 					return nil
 				}
+				if path != absBasePath && slices.Contains(s.excludeDirs, d.Name()) {
+					return filepath.SkipDir
+				}
 				// Check if the directory contains any .go files.
 				entries, err := os.ReadDir(path)
 				if err != nil {
@@ -225,25 +386,12 @@ func (s *Scanner) Scan(ctx context.Context, patterns ...string) ([]*Package, err
 					slog.DebugContext(ctx, "cannot read directory during walk, skipping", "path", path, "error", err)
 					return nil
 				}
-				hasGoFiles := false
 				for _, entry := range entries {
 					if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
-						hasGoFiles = true
+						targets = append(targets, scanTarget{pattern: pattern, dir: path, wildcard: true})
 						break
 					}
 				}
-
-				if hasGoFiles {
-					pkg, err := s.ScanPackageFromFilePath(ctx, path)
-					if err != nil {
-						// Log error but continue walking
-						slog.WarnContext(ctx, "failed to scan package during wildcard walk", "path", path, "error", err)
-						return nil
-					}
-					if pkg != nil && pkg.ImportPath != "" {
-						pkgsMap[pkg.ImportPath] = pkg
-					}
-				}
 				return nil
 			})
 
@@ -252,9 +400,6 @@ func (s *Scanner) Scan(ctx context.Context, patterns ...string) ([]*Package, err
 			}
 		} else {
 			// Handle single file, directory, or import path pattern
-			var pkg *Package
-			var err error
-
 			// Attempt to resolve as a file/directory path first.
 			absPath := pattern
 			if !filepath.IsAbs(pattern) {
@@ -262,35 +407,17 @@ func (s *Scanner) Scan(ctx context.Context, patterns ...string) ([]*Package, err
 			}
 
 			info, statErr := os.Stat(absPath)
-			if statErr == nil { // Path exists on filesystem
-				if info.IsDir() {
-					pkg, err = s.ScanPackageFromFilePath(ctx, absPath)
-				} else {
-					pkg, err = s.ScanFiles(ctx, []string{absPath})
-				}
-			} else { // Path does not exist, assume it's an import path
-				pkg, err = s.ScanPackageFromImportPath(ctx, pattern)
-			}
-
-			if err != nil {
-				return nil, fmt.Errorf("failed to scan pattern %q: %w", pattern, err)
-			}
-			if pkg != nil && pkg.ImportPath != "" {
-				pkgsMap[pkg.ImportPath] = pkg
+			switch {
+			case statErr != nil: // Path does not exist, assume it's an import path
+				targets = append(targets, scanTarget{pattern: pattern, importPath: pattern})
+			case info.IsDir():
+				targets = append(targets, scanTarget{pattern: pattern, dir: absPath})
+			default:
+				targets = append(targets, scanTarget{pattern: pattern, file: absPath})
 			}
 		}
 	}
-
-	// Convert map to slice
-	pkgs := make([]*Package, 0, len(pkgsMap))
-	for _, pkg := range pkgsMap {
-		pkgs = append(pkgs, pkg)
-	}
-	// Sort for deterministic output
-	sort.Slice(pkgs, func(i, j int) bool {
-		return pkgs[i].ImportPath < pkgs[j].ImportPath
-	})
-	return pkgs, nil
+	return targets, nil
 }
 
 // ScannerOption is a function that configures a Scanner.
@@ -348,6 +475,56 @@ func WithGoModuleResolver() ScannerOption {
 	}
 }
 
+// WithAutoDownload enables downloading the modules required by go.mod but missing from the
+// module cache, with `go mod download`, instead of failing to resolve their packages.
+// It is effective only with WithGoModuleResolver. The number of downloads of a scanner is
+// limited, see WithAutoDownloadBudget.
+func WithAutoDownload(enabled bool) ScannerOption {
+	return func(s *Scanner) error {
+		s.autoDownload = enabled
+		return nil
+	}
+}
+
+// WithAutoDownloadBudget sets the maximum number of modules downloaded with WithAutoDownload
+// (default: locator.DefaultDownloadBudget).
+func WithAutoDownloadBudget(n int) ScannerOption {
+	return func(s *Scanner) error {
+		s.downloadBudget = n
+		return nil
+	}
+}
+
+// WithPackageMapping maps import paths to directories directly, bypassing the resolution with go.mod,
+// so that the packages of a monorepo laid out by Bazel or please, or vendored forks at nonstandard
+// paths, can be scanned. An import path also covers its sub-packages, e.g. "example.com/fork" mapped
+// to "third_party/fork" makes "example.com/fork/sub" resolve to "third_party/fork/sub".
+// Relative directories are relative to the module root. This option can be given several times.
+func WithPackageMapping(mapping map[string]string) ScannerOption {
+	return func(s *Scanner) error {
+		if s.packageMapping == nil {
+			s.packageMapping = make(map[string]string)
+		}
+		for importPath, dir := range mapping {
+			s.packageMapping[importPath] = dir
+		}
+		return nil
+	}
+}
+
+// WithGOPATHMode lets the scanner analyze a legacy project without go.mod laid out in GOPATH:
+// the import paths are derived from the paths under the src directories of the GOPATH roots,
+// and the imports are resolved in the vendor directories of the project, in GOROOT and in the
+// roots. The roots are given, or $GOPATH if none. A project with a go.mod is still resolved in
+// module mode.
+func WithGOPATHMode(roots ...string) ScannerOption {
+	return func(s *Scanner) error {
+		s.gopathMode = true
+		s.gopathRoots = append(s.gopathRoots, roots...)
+		return nil
+	}
+}
+
 // WithModuleDirs configures the scanner to operate in workspace mode over a set of modules.
 // It stores the directories, and the actual locator initialization happens in `New`.
 func WithModuleDirs(moduleDirs []string) ScannerOption {
@@ -387,13 +564,73 @@ func WithExternalTypeOverrides(overrides scanner.ExternalTypeOverride) ScannerOp
 }
 
 // WithDeclarationsOnlyPackages sets packages that should be scanned for declarations only.
+// It is a shorthand for WithPackageLoadMode(pattern, LoadDecls) for each pattern.
 func WithDeclarationsOnlyPackages(importPaths []string) ScannerOption {
 	return func(s *Scanner) error {
+		for _, path := range importPaths {
+			s.SetPackageLoadMode(path, scanner.LoadDecls)
+		}
+		return nil
+	}
+}
+
+// WithLoadMode sets the load mode for packages that are not matched by any WithPackageLoadMode pattern.
+// The default is LoadFull.
+func WithLoadMode(mode LoadMode) ScannerOption {
+	return func(s *Scanner) error {
+		s.defaultLoadMode = mode
+		if s.scanner != nil {
+			s.scanner.DefaultLoadMode = mode
+		}
+		return nil
+	}
+}
+
+// WithPackageLoadMode sets the load mode for the packages matching the import path pattern.
+// A pattern ending with "/..." also matches all sub-packages. When several patterns match,
+// the last one wins, e.g. LoadDecls for "..." dependencies followed by LoadFull for "example.com/me/...".
+func WithPackageLoadMode(pattern string, mode LoadMode) ScannerOption {
+	return func(s *Scanner) error {
+		s.SetPackageLoadMode(pattern, mode)
+		return nil
+	}
+}
+
+// WithStaticCalls enables the collection of the calls in the bodies of the functions, in
+// FunctionInfo.Calls. The calls are found from the syntax only: the callees of the calls of
+// the imported functions and of the functions of the same package are known, but not the
+// callees of the method calls, for which symgo is needed.
+func WithStaticCalls(enabled bool) ScannerOption {
+	return func(s *Scanner) error {
+		s.collectCalls = enabled
 		if s.scanner != nil {
-			s.scanner.DeclarationsOnlyPackages = append(s.scanner.DeclarationsOnlyPackages, importPaths...)
+			s.scanner.CollectCalls = enabled
 		}
-		// Also store it on the goscan.Scanner for initialization phase
-		s.declarationsOnlyPackages = append(s.declarationsOnlyPackages, importPaths...)
+		return nil
+	}
+}
+
+// WithPartialResults makes the scans of the packages skip the files which cannot be parsed, e.g.
+// with syntax errors in work-in-progress code, instead of failing: the package holds the other
+// files, and the errors of the skipped ones, by file and position, are in PackageInfo.Errors.
+// Without it, a scan fails with a *scanner.PackageError listing the errors of all the files.
+func WithPartialResults(enabled bool) ScannerOption {
+	return func(s *Scanner) error {
+		s.partialResults = enabled
+		if s.scanner != nil {
+			s.scanner.PartialResults = enabled
+		}
+		return nil
+	}
+}
+
+// WithUnorderedWalk makes the Walker follow the imports returned by the visitor in the order they
+// are returned, as it did before, instead of sorting them. It saves the sorting on large walks,
+// but the order of the visits may change between runs if the visitor returns the imports in the
+// order of a map.
+func WithUnorderedWalk(enabled bool) ScannerOption {
+	return func(s *Scanner) error {
+		s.unorderedWalk = enabled
 		return nil
 	}
 }
@@ -418,6 +655,7 @@ func New(options ...ScannerOption) (*Scanner, error) {
 		},
 	}
 
+	defaultLogger := s.Logger
 	for _, option := range options {
 		if err := option(s); err != nil {
 			return nil, err
@@ -425,8 +663,18 @@ func New(options ...ScannerOption) (*Scanner, error) {
 	}
 
 	locatorOpts := []locator.Option{locator.WithOverlay(s.overlay)}
+	if len(s.packageMapping) > 0 {
+		locatorOpts = append(locatorOpts, locator.WithPackageMapping(s.packageMapping))
+	}
+	if s.gopathMode {
+		locatorOpts = append(locatorOpts, locator.WithGOPATHMode(s.gopathRoots...))
+	}
 	if s.useGoModuleResolver {
 		locatorOpts = append(locatorOpts, locator.WithGoModuleResolver())
+		if s.autoDownload {
+			// A single downloader is shared by the locators of a workspace, for a budget per scanner.
+			locatorOpts = append(locatorOpts, locator.WithDownloader(locator.NewDownloader(s.downloadBudget, s.Logger)))
+		}
 	}
 
 	if s.isWorkspace {
@@ -459,6 +707,10 @@ func New(options ...ScannerOption) (*Scanner, error) {
 		s.locator = loc
 	}
 
+	if err := s.applyConfigFile(s.Logger != defaultLogger); err != nil {
+		return nil, err
+	}
+
 	// The internal scanner needs a module path and root dir to initialize.
 	// In workspace mode, we use the primary (first) locator's info.
 	// This is a slight simplification, but the internal scanner's primary role
@@ -467,10 +719,11 @@ func New(options ...ScannerOption) (*Scanner, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create internal scanner: %w", err)
 	}
-	// Propagate declarations-only packages to the internal scanner
-	if s.declarationsOnlyPackages != nil {
-		initialScanner.DeclarationsOnlyPackages = append(initialScanner.DeclarationsOnlyPackages, s.declarationsOnlyPackages...)
-	}
+	// Propagate the load modes to the internal scanner
+	initialScanner.DefaultLoadMode = s.defaultLoadMode
+	initialScanner.LoadModeRules = s.loadModeRules
+	initialScanner.CollectCalls = s.collectCalls
+	initialScanner.PartialResults = s.partialResults
 	s.scanner = initialScanner
 
 	return s, nil
@@ -498,16 +751,39 @@ func (s *Scanner) SetExternalTypeOverrides(ctx context.Context, overrides scanne
 		slog.WarnContext(ctx, "Failed to re-initialize internal scanner with new overrides. Continuing with previous scanner settings.", slog.Any("error", err))
 		return
 	}
+	newInternalScanner.DefaultLoadMode = s.defaultLoadMode
+	newInternalScanner.LoadModeRules = s.loadModeRules
+	newInternalScanner.CollectCalls = s.collectCalls
+	newInternalScanner.PartialResults = s.partialResults
+	newInternalScanner.DeclarationsOnlyPackages = s.scanner.DeclarationsOnlyPackages
 	s.scanner = newInternalScanner
 }
 
 // AddDeclarationsOnlyPackages adds packages to the list of packages that should be scanned for declarations only.
+// It is a shorthand for SetPackageLoadMode(pattern, LoadDecls) for each pattern.
 func (s *Scanner) AddDeclarationsOnlyPackages(importPaths []string) {
+	for _, path := range importPaths {
+		s.SetPackageLoadMode(path, scanner.LoadDecls)
+	}
+}
+
+// SetPackageLoadMode sets the load mode for the packages matching the import path pattern.
+// The rule takes precedence over the rules added before it. Packages that are already
+// cached keep the mode they were loaded with.
+func (s *Scanner) SetPackageLoadMode(pattern string, mode LoadMode) {
+	s.loadModeRules = append(s.loadModeRules, scanner.LoadModeRule{Pattern: pattern, Mode: mode})
+	if s.scanner != nil {
+		s.scanner.LoadModeRules = s.loadModeRules
+	}
+}
+
+// LoadModeFor returns the load mode used for the package with the given import path.
+func (s *Scanner) LoadModeFor(importPath string) LoadMode {
 	if s.scanner == nil {
 		// This should not happen if called after New()
-		return
+		return s.defaultLoadMode
 	}
-	s.scanner.DeclarationsOnlyPackages = append(s.scanner.DeclarationsOnlyPackages, importPaths...)
+	return s.scanner.LoadModeFor(importPath)
 }
 
 // ResolveType starts the type resolution process for a given field type.
@@ -531,8 +807,15 @@ func (s *Scanner) ResolveType(ctx context.Context, fieldType *scanner.FieldType)
 
 // listGoFiles lists all .go files in a directory.
 // If includeTests is false, it excludes _test.go files.
+// With build tags, it excludes the files not matching them (see WithBuildTags).
 // It returns a list of absolute file paths.
-func listGoFiles(dirPath string, includeTests bool) ([]string, error) {
+func listGoFiles(dirPath string, includeTests bool, buildTags []string) ([]string, error) {
+	var buildCtx *build.Context
+	if len(buildTags) > 0 {
+		c := build.Default
+		c.BuildTags = buildTags
+		buildCtx = &c
+	}
 	var files []string
 	entries, err := os.ReadDir(dirPath)
 	if err != nil {
@@ -549,6 +832,11 @@ func listGoFiles(dirPath string, includeTests bool) ([]string, error) {
 		if !includeTests && strings.HasSuffix(name, "_test.go") {
 			continue
 		}
+		if buildCtx != nil {
+			if ok, err := buildCtx.MatchFile(dirPath, name); err != nil || !ok {
+				continue
+			}
+		}
 
 		absPath, err := filepath.Abs(filepath.Join(dirPath, name))
 		if err != nil {
@@ -575,7 +863,7 @@ func (s *Scanner) privateScan(ctx context.Context, pkgDirAbs string, importPath
 	slog.DebugContext(ctx, "privateScan CACHE MISS", slog.String("importPath", importPath))
 
 	// 2. Scan files.
-	allGoFilesInPkg, err := listGoFiles(pkgDirAbs, s.IncludeTests)
+	allGoFilesInPkg, err := listGoFiles(pkgDirAbs, s.IncludeTests, s.buildTags)
 	if err != nil {
 		return nil, fmt.Errorf("privateScan: failed to list go files in %s: %w", pkgDirAbs, err)
 	}
@@ -606,15 +894,21 @@ func (s *Scanner) privateScan(ctx context.Context, pkgDirAbs string, importPath
 
 	var pkgInfo *scanner.PackageInfo
 	if len(filesToParseThisCall) > 0 {
-		isExternalModule := !strings.HasPrefix(pkgDirAbs, s.RootDir())
+		rootDir := s.RootDir()
+		isExternalModule := pkgDirAbs != rootDir && !strings.HasPrefix(pkgDirAbs, rootDir+string(filepath.Separator))
+		// In GOPATH mode, a package vendored in the project does not have the import path of its directory.
+		isExternalModule = isExternalModule || s.locator.GOPATHSrcDirs() != nil
+		// The packages resolved while parsing are not the target of Scan, if this one is.
+		parseCtx := withScanTarget(ctx, false)
 		if isExternalModule {
-			pkgInfo, err = s.scanner.ScanFilesWithKnownImportPath(ctx, filesToParseThisCall, pkgDirAbs, importPath)
+			pkgInfo, err = s.scanner.ScanFilesWithKnownImportPath(parseCtx, filesToParseThisCall, pkgDirAbs, importPath)
 		} else {
-			pkgInfo, err = s.scanner.ScanFiles(ctx, filesToParseThisCall, pkgDirAbs)
+			pkgInfo, err = s.scanner.ScanFiles(parseCtx, filesToParseThisCall, pkgDirAbs)
 		}
 		if err != nil {
 			return nil, fmt.Errorf("privateScan: scanning files for %s failed: %w", importPath, err)
 		}
+		s.progress.addFiles(filesToParseThisCall)
 
 		// Mark the newly parsed files as visited.
 		if pkgInfo != nil {
@@ -622,6 +916,11 @@ func (s *Scanner) privateScan(ctx context.Context, pkgDirAbs string, importPath
 			for _, fp := range pkgInfo.Files {
 				s.visitedFiles[fp] = struct{}{}
 			}
+			if pkgInfo.XTest != nil {
+				for _, fp := range pkgInfo.XTest.Files {
+					s.visitedFiles[fp] = struct{}{}
+				}
+			}
 			s.mu.Unlock()
 		}
 	}
@@ -638,18 +937,40 @@ func (s *Scanner) privateScan(ctx context.Context, pkgDirAbs string, importPath
 	// 4. Generate ID and finalize PackageInfo.
 	pkgInfo.ImportPath = importPath
 	pkgInfo.Path = pkgDirAbs
+	// In workspace mode, the package belongs to the module of its import path, not to the
+	// first module the files were scanned with.
+	if s.isWorkspace {
+		if mod := s.ModuleOf(importPath); mod != nil {
+			pkgInfo.ModulePath, pkgInfo.ModuleDir = mod.Path, mod.Dir
+		} else {
+			pkgInfo.ModulePath, pkgInfo.ModuleDir = "", ""
+		}
+	}
 	if pkgInfo.Name == "main" {
 		pkgInfo.ID = importPath + ".main"
 	} else {
 		pkgInfo.ID = importPath
 	}
 
+	// The external test package is cached under its own import path, e.g. "example.com/foo_test".
+	if xtest := pkgInfo.XTest; xtest != nil {
+		xtest.ImportPath = importPath + "_test"
+		xtest.Path = pkgDirAbs
+		xtest.ID = xtest.ImportPath
+	}
+
 	// 5. Update cache.
 	s.updateSymbolCacheWithPackageInfo(ctx, importPath, pkgInfo) // Update symbol cache
 	s.mu.Lock()
 	s.packageCache[importPath] = pkgInfo // Update in-memory package cache
+	if pkgInfo.XTest != nil {
+		s.packageCache[pkgInfo.XTest.ImportPath] = pkgInfo.XTest
+	}
 	s.mu.Unlock()
 
+	if !isScanTarget(ctx) {
+		s.progress.packageResolved(importPath)
+	}
 	slog.DebugContext(ctx, "privateScan finished", slog.String("importPath", importPath), slog.String("id", pkgInfo.ID))
 	return pkgInfo, nil
 }
@@ -670,15 +991,22 @@ func (s *Scanner) ScanPackageFromFilePath(ctx context.Context, pkgPath string) (
 
 	// Since we have the directory, we need to find the corresponding import path.
 	// This is a critical step that must handle main module, workspace modules, and replaced modules.
-	importPath, err := s.locator.PathToImport(pkgDirAbs)
+	// In workspace mode, the module containing the directory is asked first.
+	loc := s.locator
+	if s.isWorkspace {
+		if owner := s.locatorForDir(pkgDirAbs); owner != nil {
+			loc = owner
+		}
+	}
+	importPath, err := loc.PathToImport(pkgDirAbs)
 	if err != nil {
 		// Try other locators in workspace mode
 		if s.isWorkspace {
-			for _, loc := range s.locators {
-				if loc == s.locator {
+			for _, l := range s.locators {
+				if l == loc {
 					continue // Already tried
 				}
-				importPath, err = loc.PathToImport(pkgDirAbs)
+				importPath, err = l.PathToImport(pkgDirAbs)
 				if err == nil {
 					break // Found it
 				}
@@ -839,10 +1167,11 @@ func (s *Scanner) ScanFiles(ctx context.Context, filePaths []string) (*scanner.P
 		}, nil
 	}
 
-	pkgInfo, err := s.scanner.ScanFiles(ctx, filesToParse, pkgDirAbs) // Scan only unvisited files
+	pkgInfo, err := s.scanner.ScanFiles(withScanTarget(ctx, false), filesToParse, pkgDirAbs) // Scan only unvisited files
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan files in %s (import path %s): %w", pkgDirAbs, importPath, err)
 	}
+	s.progress.addFiles(filesToParse)
 
 	if pkgInfo != nil {
 		pkgInfo.ImportPath = importPath // Set the calculated import path
@@ -902,7 +1231,7 @@ func (s *Scanner) UnscannedGoFiles(packagePathOrImportPath string) ([]string, er
 		}
 	}
 
-	allGoFilesInDir, err := listGoFiles(pkgDirAbs, s.IncludeTests) // listGoFiles returns absolute paths
+	allGoFilesInDir, err := listGoFiles(pkgDirAbs, s.IncludeTests, s.buildTags) // listGoFiles returns absolute paths
 	if err != nil {
 		return nil, fmt.Errorf("UnscannedGoFiles: could not list go files in %s: %w", pkgDirAbs, err)
 	}
@@ -927,18 +1256,40 @@ func isDir(path string) bool {
 }
 
 // ScanPackageFromImportPath scans a single Go package identified by its import path.
+// With WithIncludeTests, the import path of an external test package (the import path of the
+// package it tests with a "_test" suffix) returns the XTest of the tested package.
 func (s *Scanner) ScanPackageFromImportPath(ctx context.Context, importPath string) (*scanner.PackageInfo, error) {
 	loc, err := s.locatorForImportPath(importPath)
 	if err != nil {
+		if xtest, ok := s.scanXTest(ctx, importPath); ok {
+			return xtest, nil
+		}
 		return nil, fmt.Errorf("ScanPackageFromImportPath: %w", err)
 	}
-	pkgDirAbs, err := loc.FindPackageDir(importPath)
+	pkgDirAbs, err := loc.FindPackageDirContext(ctx, importPath)
 	if err != nil {
+		if xtest, ok := s.scanXTest(ctx, importPath); ok {
+			return xtest, nil
+		}
 		return nil, fmt.Errorf("could not find directory for import path %s: %w", importPath, err)
 	}
 	return s.privateScan(ctx, pkgDirAbs, importPath)
 }
 
+// scanXTest returns the external test package for an import path like "example.com/foo_test",
+// by scanning the package it tests. It reports false if there is no such package.
+func (s *Scanner) scanXTest(ctx context.Context, importPath string) (*scanner.PackageInfo, bool) {
+	basePath, ok := strings.CutSuffix(importPath, "_test")
+	if !ok || !s.IncludeTests {
+		return nil, false
+	}
+	base, err := s.ScanPackageFromImportPath(ctx, basePath)
+	if err != nil || base.XTest == nil {
+		return nil, false
+	}
+	return base.XTest, true
+}
+
 // getOrCreateSymbolCache ensures the symbolCache is initialized.
 func (s *Scanner) getOrCreateSymbolCache(ctx context.Context) (*symbolCache, error) {
 	if s.CachePath == "" {
@@ -1185,6 +1536,14 @@ func (s *Scanner) AllSeenPackages() map[string]*Package {
 	return copied
 }
 
+// SeenPackage returns the package with the import path from the scanner's cache, without scanning it.
+func (s *Scanner) SeenPackage(importPath string) (*Package, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	pkg, ok := s.packageCache[importPath]
+	return pkg, ok && pkg != nil
+}
+
 // FindSymbolInPackage searches for a specific symbol within a package by scanning its files one by one.
 // It only scans files that have not yet been visited by this scanner instance.
 // If the symbol is found, it returns a cumulative PackageInfo of all files scanned in the package up to that point
@@ -1200,7 +1559,7 @@ func (s *Scanner) FindSymbolInPackage(ctx context.Context, importPath string, sy
 	var pkgDirAbs string
 	if isStdLib {
 		// We need the absolute path for ScanFilesWithKnownImportPath
-		pkgDirAbs, err = s.locator.FindPackageDir(importPath)
+		pkgDirAbs, err = s.locator.FindPackageDirContext(ctx, importPath)
 		if err != nil {
 			return nil, fmt.Errorf("could not find directory for stdlib import path %s: %w", importPath, err)
 		}
@@ -1265,9 +1624,12 @@ func (s *Scanner) FindSymbolInPackage(ctx context.Context, importPath string, sy
 			for _, f := range cumulativePkgInfo.Files {
 				existingFiles[f] = struct{}{}
 			}
-			for _, f := range pkgInfo.Files {
+			for i, f := range pkgInfo.Files {
 				if _, exists := existingFiles[f]; !exists {
 					cumulativePkgInfo.Files = append(cumulativePkgInfo.Files, f)
+					if i < len(pkgInfo.FileInfos) {
+						cumulativePkgInfo.FileInfos = append(cumulativePkgInfo.FileInfos, pkgInfo.FileInfos[i])
+					}
 					existingFiles[f] = struct{}{}
 				}
 			}
@@ -0,0 +1,107 @@
+package goscan
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanner_WithGOPATHMode(t *testing.T) {
+	// A legacy project without go.mod, with a vendored package, importing a library of another GOPATH root.
+	root1, root2 := t.TempDir(), t.TempDir()
+	projectDir := filepath.Join(root1, "src", "example.com", "legacy")
+	files := map[string]string{
+		filepath.Join(projectDir, "main.go"): `
+package main
+
+import (
+	"time"
+
+	"example.com/lib/model"
+	"github.com/dep/log"
+)
+
+type Server struct {
+	User    model.User
+	Logger  log.Logger
+	Started time.Time
+}
+`,
+		filepath.Join(projectDir, "handler", "handler.go"): `
+package handler
+
+type Handler struct{}
+`,
+		filepath.Join(projectDir, "vendor", "github.com", "dep", "log", "log.go"): `
+package log
+
+type Logger struct {
+	Prefix string
+}
+`,
+		filepath.Join(root2, "src", "example.com", "lib", "model", "model.go"): `
+package model
+
+type User struct {
+	Name string
+}
+`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	s, err := New(WithWorkDir(projectDir), WithGOPATHMode(root1, root2))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	t.Run("import paths", func(t *testing.T) {
+		pkgs, err := s.Scan(ctx, "./...")
+		if err != nil {
+			t.Fatalf("Scan() failed: %v", err)
+		}
+		got := make(map[string]bool)
+		for _, pkg := range pkgs {
+			got[pkg.ImportPath] = true
+		}
+		for _, want := range []string{"example.com/legacy", "example.com/legacy/handler"} {
+			if !got[want] {
+				t.Errorf("%s is not scanned, got %v", want, got)
+			}
+		}
+	})
+
+	t.Run("field types", func(t *testing.T) {
+		pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/legacy")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+		}
+		server := pkg.Lookup("Server")
+		if server == nil || server.Struct == nil {
+			t.Fatalf("Server not found")
+		}
+		want := map[string]string{
+			"User":    "example.com/lib/model",
+			"Logger":  "github.com/dep/log",
+			"Started": "time",
+		}
+		for _, f := range server.Struct.Fields {
+			ti, err := f.Type.Resolve(ctx)
+			if err != nil {
+				t.Errorf("%s: Resolve() failed: %v", f.Name, err)
+				continue
+			}
+			if ti.PkgPath != want[f.Name] {
+				t.Errorf("%s: want the type of %q, got %q", f.Name, want[f.Name], ti.PkgPath)
+			}
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package goscan_test
+
+import (
+	"context"
+	"go/ast"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestLoadMode(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"main.go": `package app
+
+import "example.com/app/vendored/lib"
+
+func Run() { lib.Do() }
+`,
+		"vendored/lib/lib.go": `package lib
+
+import "example.com/app/vendored/lib/internal/util"
+
+type Config struct{ Name string }
+
+func Do() { util.Help() }
+`,
+		"vendored/lib/internal/util/util.go": `package util
+
+import "strings"
+
+type Helper struct{}
+
+func Help() { _ = strings.TrimSpace("") }
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	s, err := goscan.New(
+		goscan.WithWorkDir(dir),
+		goscan.WithLoadMode(goscan.LoadImports),
+		goscan.WithPackageLoadMode("example.com/app/...", goscan.LoadFull),
+		goscan.WithPackageLoadMode("example.com/app/vendored/lib", goscan.LoadDecls),
+	)
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+
+	cases := []struct {
+		importPath string
+		wantMode   goscan.LoadMode
+	}{
+		{importPath: "example.com/app", wantMode: goscan.LoadFull},
+		{importPath: "example.com/app/vendored/lib", wantMode: goscan.LoadDecls},
+		{importPath: "example.com/app/vendored/lib/internal/util", wantMode: goscan.LoadFull},
+		{importPath: "strings", wantMode: goscan.LoadImports},
+	}
+	for _, tc := range cases {
+		if got := s.LoadModeFor(tc.importPath); got != tc.wantMode {
+			t.Errorf("LoadModeFor(%q) = %s, want %s", tc.importPath, got, tc.wantMode)
+		}
+	}
+
+	ctx := context.Background()
+	type summary struct {
+		Mode      goscan.LoadMode
+		Types     []string
+		Functions []string
+		Bodies    int
+		Imports   []string
+	}
+	summarize := func(t *testing.T, importPath string) summary {
+		t.Helper()
+		pkg, err := s.ScanPackageFromImportPath(ctx, importPath)
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath(%q) failed: %v", importPath, err)
+		}
+		got := summary{Mode: pkg.LoadMode}
+		for _, ti := range pkg.Types {
+			got.Types = append(got.Types, ti.Name)
+		}
+		for _, fn := range pkg.Functions {
+			got.Functions = append(got.Functions, fn.Name)
+			if fn.AstDecl != nil && fn.AstDecl.Body != nil {
+				got.Bodies++
+			}
+		}
+		for _, f := range pkg.AstFiles {
+			for _, imp := range f.Imports {
+				got.Imports = append(got.Imports, imp.Path.Value)
+			}
+			for _, decl := range f.Decls {
+				if _, ok := decl.(*ast.FuncDecl); ok && got.Mode == goscan.LoadImports {
+					t.Errorf("unexpected function declaration in imports-only AST of %s", importPath)
+				}
+			}
+		}
+		sort.Strings(got.Imports)
+		return got
+	}
+
+	t.Run("full", func(t *testing.T) {
+		want := summary{Mode: goscan.LoadFull, Functions: []string{"Run"}, Bodies: 1, Imports: []string{`"example.com/app/vendored/lib"`}}
+		if diff := cmp.Diff(want, summarize(t, "example.com/app")); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("decls", func(t *testing.T) {
+		want := summary{Mode: goscan.LoadDecls, Types: []string{"Config"}, Functions: []string{"Do"}, Bodies: 0, Imports: []string{`"example.com/app/vendored/lib/internal/util"`}}
+		if diff := cmp.Diff(want, summarize(t, "example.com/app/vendored/lib")); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("imports", func(t *testing.T) {
+		s.SetPackageLoadMode("example.com/app/vendored/lib/internal/...", goscan.LoadImports)
+		want := summary{Mode: goscan.LoadImports, Imports: []string{`"strings"`}}
+		if diff := cmp.Diff(want, summarize(t, "example.com/app/vendored/lib/internal/util")); diff != "" {
+			t.Errorf("mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package goscan
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanner_WithPackageMapping(t *testing.T) {
+	// The app module imports a package generated outside of it (as Bazel does in bazel-bin)
+	// and a fork vendored at a nonstandard path, neither of which is known to go.mod.
+	dir := t.TempDir()
+	files := map[string]string{
+		"app/go.mod": "module example.com/app\n",
+		"app/main.go": `
+package main
+
+import (
+	"example.com/fork/util"
+	"example.com/gen/api"
+)
+
+type Server struct {
+	Req  api.Request
+	Opts util.Options
+}
+`,
+		"bazel-bin/gen/api/api.go": `
+package api
+
+type Request struct {
+	Name string
+}
+`,
+		"app/third_party/fork/util/util.go": `
+package util
+
+type Options struct {
+	Verbose bool
+}
+`,
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	s, err := New(
+		WithWorkDir(filepath.Join(dir, "app")),
+		WithPackageMapping(map[string]string{
+			"example.com/gen/api": filepath.Join(dir, "bazel-bin", "gen", "api"),
+			"example.com/fork":    "third_party/fork", // relative to the module root, covers the sub-packages
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	t.Run("import paths", func(t *testing.T) {
+		for _, importPath := range []string{"example.com/gen/api", "example.com/fork/util"} {
+			pkg, err := s.ScanPackageFromImportPath(ctx, importPath)
+			if err != nil {
+				t.Fatalf("ScanPackageFromImportPath(%q) failed: %v", importPath, err)
+			}
+			if len(pkg.Types) != 1 {
+				t.Errorf("%s: want 1 type, got %d", importPath, len(pkg.Types))
+			}
+		}
+	})
+
+	t.Run("directories", func(t *testing.T) {
+		// The mapped directory inside the module root gets its mapped import path.
+		pkg, err := s.ScanPackageFromFilePath(ctx, filepath.Join(dir, "app", "third_party", "fork", "util"))
+		if err != nil {
+			t.Fatalf("ScanPackageFromFilePath() failed: %v", err)
+		}
+		if want := "example.com/fork/util"; pkg.ImportPath != want {
+			t.Errorf("import path: want %q, got %q", want, pkg.ImportPath)
+		}
+	})
+
+	t.Run("field types", func(t *testing.T) {
+		pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/app")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+		}
+		server := pkg.Lookup("Server")
+		if server == nil || server.Struct == nil {
+			t.Fatalf("Server not found")
+		}
+		for _, f := range server.Struct.Fields {
+			ti, err := f.Type.Resolve(ctx)
+			if err != nil {
+				t.Errorf("%s: Resolve() failed: %v", f.Name, err)
+				continue
+			}
+			if ti.Struct == nil || len(ti.Struct.Fields) != 1 {
+				t.Errorf("%s: want a struct with 1 field, got %+v", f.Name, ti)
+			}
+		}
+	})
+}
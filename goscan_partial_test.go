@@ -0,0 +1,106 @@
+package goscan_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestPartialResults(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"app/ok.go": `package app
+
+type Config struct{ Name string }
+
+func Run() {}
+`,
+		"app/broken.go": `package app
+
+func Broken() {
+	x :=
+}
+
+type Half struct {
+`,
+		"app/wip.go": `package app
+
+func WIP( {}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+	ctx := context.Background()
+
+	type fileError struct {
+		File string
+		Line int
+	}
+	summarize := func(errs []*scanner.FileError) []fileError {
+		var got []fileError
+		for _, e := range errs {
+			if e.Msg == "" {
+				t.Errorf("no message for the error at %s", e.Pos)
+			}
+			got = append(got, fileError{File: filepath.Base(e.Pos.Filename), Line: e.Pos.Line})
+		}
+		return got
+	}
+	want := []fileError{{File: "broken.go", Line: 5}, {File: "broken.go", Line: 7}, {File: "wip.go", Line: 3}}
+
+	t.Run("fatal", func(t *testing.T) {
+		s, err := goscan.New(goscan.WithWorkDir(dir))
+		if err != nil {
+			t.Fatalf("goscan.New() failed: %v", err)
+		}
+		_, err = s.ScanPackageFromImportPath(ctx, "example.com/app/app")
+		var pkgErr *scanner.PackageError
+		if !errors.As(err, &pkgErr) {
+			t.Fatalf("expected a *scanner.PackageError, got %v", err)
+		}
+		if diff := cmp.Diff(want, summarize(pkgErr.Errors)); diff != "" {
+			t.Errorf("errors mismatch (-want +got):\n%s", diff)
+		}
+		if !strings.Contains(err.Error(), "(and 2 more errors)") {
+			t.Errorf("unexpected message %q", err.Error())
+		}
+		var fileErr *scanner.FileError
+		if !errors.As(err, &fileErr) || filepath.Base(fileErr.Pos.Filename) != "broken.go" {
+			t.Errorf("expected the errors of the files to be unwrapped, got %v", fileErr)
+		}
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		s, err := goscan.New(goscan.WithWorkDir(dir), goscan.WithPartialResults(true))
+		if err != nil {
+			t.Fatalf("goscan.New() failed: %v", err)
+		}
+		pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/app/app")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+		}
+		var names []string
+		for _, typ := range pkg.Types {
+			names = append(names, typ.Name)
+		}
+		for _, fn := range pkg.Functions {
+			names = append(names, fn.Name)
+		}
+		if diff := cmp.Diff([]string{"Config", "Run"}, names); diff != "" {
+			t.Errorf("declarations mismatch (-want +got):\n%s", diff)
+		}
+		if len(pkg.Files) != 1 || filepath.Base(pkg.Files[0]) != "ok.go" {
+			t.Errorf("expected only ok.go to be scanned, got %v", pkg.Files)
+		}
+		if diff := cmp.Diff(want, summarize(pkg.Errors)); diff != "" {
+			t.Errorf("errors mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -28,7 +28,6 @@ func TestScanPackageFromFilePathImports(t *testing.T) {
 		"github.com/podhmo/go-scan/testdata/walk/c",
 		"github.com/podhmo/go-scan/testdata/walk/d",
 	}
-	sort.Strings(pkg.Imports) // sort for stable comparison
 	if diff := cmp.Diff(expectedImports, pkg.Imports); diff != "" {
 		t.Errorf("mismatch imports (-want +got):\n%s", diff)
 	}
@@ -100,3 +99,45 @@ func TestWalk_Wildcard(t *testing.T) {
 		t.Errorf("mismatch visited packages (-want +got):\n%s", diff)
 	}
 }
+
+// reversingVisitor follows the imports in reverse order.
+type reversingVisitor struct {
+	visited []string
+}
+
+func (v *reversingVisitor) Visit(pkg *PackageImports) (importsToFollow []string, err error) {
+	v.visited = append(v.visited, pkg.ImportPath)
+	for i := len(pkg.Imports) - 1; i >= 0; i-- {
+		importsToFollow = append(importsToFollow, pkg.Imports[i])
+	}
+	return importsToFollow, nil
+}
+
+func TestWalk_Order(t *testing.T) {
+	const prefix = "github.com/podhmo/go-scan/testdata/walk/"
+	walk := func(t *testing.T, options ...ScannerOption) []string {
+		t.Helper()
+		s, err := New(append([]ScannerOption{WithWorkDir("./testdata/walk")}, options...)...)
+		if err != nil {
+			t.Fatalf("New() failed: %v", err)
+		}
+		visitor := &reversingVisitor{}
+		if err := s.Walker.Walk(context.Background(), visitor, prefix+"a"); err != nil {
+			t.Fatalf("Walk() failed: %v", err)
+		}
+		return visitor.visited
+	}
+
+	t.Run("stable", func(t *testing.T) {
+		want := []string{prefix + "a", prefix + "b", prefix + "c", prefix + "d"}
+		if diff := cmp.Diff(want, walk(t)); diff != "" {
+			t.Errorf("mismatch visited packages (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("unordered", func(t *testing.T) {
+		want := []string{prefix + "a", prefix + "d", prefix + "c", prefix + "b"}
+		if diff := cmp.Diff(want, walk(t, WithUnorderedWalk(true))); diff != "" {
+			t.Errorf("mismatch visited packages (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package goscan_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestWorkspace_PackageIdentities(t *testing.T) {
+	// The modules live in sibling directories sharing a prefix, and both have an internal/util.
+	files := map[string]string{
+		"api/go.mod":                       "module example.com/api\n\ngo 1.21\n\nrequire example.com/client v0.0.0\n\nreplace example.com/client => ../api-client\n\nreplace example.com/lib => ./third_party/lib\n",
+		"api/internal/util/util.go":        "package util\n",
+		"api/third_party/lib/lib.go":       "package lib\n",
+		"api-client/go.mod":                "module example.com/client\n\ngo 1.21\n",
+		"api-client/internal/util/util.go": "package util\n",
+		"api-client/sdk/sdk.go":            "package sdk\n",
+	}
+	tmpdir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	s, err := goscan.New(
+		goscan.WithWorkDir(tmpdir),
+		goscan.WithModuleDirs([]string{
+			filepath.Join(tmpdir, "api"),
+			filepath.Join(tmpdir, "api-client"),
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("scanned from a directory", func(t *testing.T) {
+		for _, tt := range []struct {
+			dir, importPath, modulePath string
+		}{
+			{"api/internal/util", "example.com/api/internal/util", "example.com/api"},
+			{"api-client/internal/util", "example.com/client/internal/util", "example.com/client"},
+		} {
+			pkg, err := s.ScanPackageFromFilePath(ctx, filepath.Join(tmpdir, tt.dir))
+			if err != nil {
+				t.Fatalf("ScanPackageFromFilePath(%s) failed: %v", tt.dir, err)
+			}
+			if pkg.ImportPath != tt.importPath || pkg.ModulePath != tt.modulePath {
+				t.Errorf("%s: want %s in %s, got %s in %s", tt.dir, tt.importPath, tt.modulePath, pkg.ImportPath, pkg.ModulePath)
+			}
+		}
+	})
+
+	t.Run("scanned from an import path", func(t *testing.T) {
+		pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/client/sdk")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath failed: %v", err)
+		}
+		if want := filepath.Join(tmpdir, "api-client", "sdk"); pkg.Path != want || pkg.ModulePath != "example.com/client" {
+			t.Errorf("want %s in example.com/client, got %s in %s", want, pkg.Path, pkg.ModulePath)
+		}
+	})
+
+	t.Run("replaced package outside of the modules", func(t *testing.T) {
+		pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/lib")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath failed: %v", err)
+		}
+		if want := filepath.Join(tmpdir, "api", "third_party", "lib"); pkg.Path != want {
+			t.Errorf("want %s, got %s", want, pkg.Path)
+		}
+		if pkg.ModulePath != "" {
+			t.Errorf("want no module, got %s", pkg.ModulePath)
+		}
+	})
+
+	t.Run("ModuleOf", func(t *testing.T) {
+		for importPath, want := range map[string]string{
+			"example.com/api/internal/util": "example.com/api",
+			"example.com/client/sdk":        "example.com/client",
+			"example.com/apiv2":             "",
+			"example.com/lib":               "",
+			"fmt":                           "",
+		} {
+			got := ""
+			if mod := s.ModuleOf(importPath); mod != nil {
+				got = mod.Path
+			}
+			if got != want {
+				t.Errorf("ModuleOf(%q): want %q, got %q", importPath, want, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package goscan
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// implementerIndex is the index of the struct types of the seen packages used by ImplementersOf.
+// It is built on the first call, and updated when the scanner has seen new packages since.
+type implementerIndex struct {
+	mu       sync.Mutex
+	packages map[string]*Package // the indexed packages, by import path
+
+	structs  []*scanner.TypeInfo            // all the struct types of the indexed packages
+	byMethod map[string][]*scanner.TypeInfo // the struct types, by the names of their declared methods
+	opaque   []*scanner.TypeInfo            // the types whose methods cannot be known without resolving them, see isOpaqueCandidate
+
+	cache map[string][]*scanner.TypeInfo // the implementers, by "<pkg>.<Interface>"
+}
+
+// ImplementersOf returns the struct types of the packages seen by the scanner which implement the
+// interface, in the sense of Implements, sorted by import path and name. The aliases of the
+// struct types are not reported, as the struct types themselves are.
+//
+// Unlike checking every struct type with Implements, the candidates are looked up in an index of
+// the struct types by the names of their methods, built lazily and kept up to date with the seen
+// packages, and the results are cached per interface until new packages are seen. The returned
+// slice must not be modified.
+func (s *Scanner) ImplementersOf(ctx context.Context, iface *scanner.TypeInfo) []*scanner.TypeInfo {
+	iface = resolveAliasOrSelf(ctx, iface)
+	if iface == nil || iface.Interface == nil || iface.Interface.IsConstraint() {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.implementers == nil {
+		s.implementers = &implementerIndex{}
+	}
+	idx := s.implementers
+	s.mu.Unlock()
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.update(s.AllSeenPackages())
+
+	key := iface.PkgPath + "." + iface.Name
+	if found, ok := idx.cache[key]; ok {
+		return found
+	}
+
+	var found []*scanner.TypeInfo
+	for _, t := range idx.candidates(s.getAllInterfaceMethods(ctx, iface, make(map[string]struct{}))) {
+		if s.Implements(ctx, t, iface) {
+			found = append(found, t)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].PkgPath != found[j].PkgPath {
+			return found[i].PkgPath < found[j].PkgPath
+		}
+		return found[i].Name < found[j].Name
+	})
+	idx.cache[key] = found
+	return found
+}
+
+// update indexes the packages not indexed yet. The index is rebuilt if a package was dropped or
+// scanned again, and the cached results are cleared whenever the packages change.
+func (idx *implementerIndex) update(seen map[string]*Package) {
+	rebuild := idx.packages == nil
+	for path, pkg := range idx.packages {
+		if seen[path] != pkg {
+			rebuild = true
+			break
+		}
+	}
+	if rebuild {
+		idx.packages = make(map[string]*Package, len(seen))
+		idx.structs = nil
+		idx.byMethod = make(map[string][]*scanner.TypeInfo)
+		idx.opaque = nil
+		idx.cache = make(map[string][]*scanner.TypeInfo)
+	}
+	if !rebuild && len(seen) == len(idx.packages) {
+		return
+	}
+
+	paths := make([]string, 0, len(seen))
+	for path := range seen {
+		if _, ok := idx.packages[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		pkg := seen[path]
+		idx.packages[path] = pkg
+		if pkg != nil {
+			idx.add(pkg)
+		}
+	}
+	idx.cache = make(map[string][]*scanner.TypeInfo)
+}
+
+// add indexes the struct types of a package by the names of their methods.
+func (idx *implementerIndex) add(pkg *Package) {
+	types := make(map[string]*scanner.TypeInfo)
+	for _, t := range pkg.Types {
+		if t.Kind != scanner.StructKind || t.Struct == nil {
+			continue
+		}
+		idx.structs = append(idx.structs, t)
+		if isOpaqueCandidate(t) {
+			idx.opaque = append(idx.opaque, t)
+			continue
+		}
+		types[t.Name] = t
+	}
+	for _, f := range pkg.Functions {
+		if f.Receiver == nil {
+			continue
+		}
+		if t, ok := types[f.CanonicalName().TypeName]; ok {
+			idx.byMethod[f.Name] = append(idx.byMethod[f.Name], t)
+		}
+	}
+}
+
+// isOpaqueCandidate reports whether the methods of the struct type are not all declared on it,
+// because it has embedded fields whose methods are promoted. These types are candidates for
+// every interface.
+func isOpaqueCandidate(t *scanner.TypeInfo) bool {
+	for _, field := range t.Struct.Fields {
+		if field.Embedded {
+			return true
+		}
+	}
+	return false
+}
+
+// candidates returns the types which may have all the methods: those declaring the method with
+// the fewest declarations, and the opaque ones.
+func (idx *implementerIndex) candidates(methods []*scanner.MethodInfo) []*scanner.TypeInfo {
+	if len(methods) == 0 {
+		return idx.structs
+	}
+	smallest := idx.byMethod[methods[0].Name]
+	for _, m := range methods[1:] {
+		if bucket := idx.byMethod[m.Name]; len(bucket) < len(smallest) {
+			smallest = bucket
+		}
+	}
+	candidates := make([]*scanner.TypeInfo, 0, len(smallest)+len(idx.opaque))
+	candidates = append(candidates, smallest...)
+	return append(candidates, idx.opaque...)
+}
@@ -0,0 +1,69 @@
+package goscan
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+func TestImplementersOf(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(WithWorkDir("./testdata/implements2"))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	ifaces, err := s.ScanPackageFromFilePath(ctx, filepath.Join(s.locator.RootDir(), "ifaces"))
+	if err != nil {
+		t.Fatalf("ScanPackageFromFilePath(ifaces) failed: %v", err)
+	}
+	names := func(types []*scanner.TypeInfo) []string {
+		var names []string
+		for _, t := range types {
+			names = append(names, t.PkgPath+"."+t.Name)
+		}
+		return names
+	}
+
+	// Only the interfaces are seen yet.
+	if got := s.ImplementersOf(ctx, ifaces.Lookup("SimpleReader")); len(got) != 0 {
+		t.Errorf("ImplementersOf(SimpleReader) before scanning impls = %v, want none", names(got))
+	}
+
+	// The index is updated with the packages seen since.
+	if _, err := s.ScanPackageFromFilePath(ctx, filepath.Join(s.locator.RootDir(), "impls")); err != nil {
+		t.Fatalf("ScanPackageFromFilePath(impls) failed: %v", err)
+	}
+
+	tests := []struct {
+		iface string
+		want  []string
+	}{
+		{"SimpleReader", []string{"example.com/implements2/impls.MyEmbeddedReader", "example.com/implements2/impls.MyReader", "example.com/implements2/impls.PartialImplementer"}},
+		{"ReaderAlias", []string{"example.com/implements2/impls.MyEmbeddedReader", "example.com/implements2/impls.MyReader", "example.com/implements2/impls.PartialImplementer"}},
+		{"EmbeddedReader", []string{"example.com/implements2/impls.MyEmbeddedReader"}},
+		{"AnotherInterface", []string{"example.com/implements2/impls.EmbeddedStruct", "example.com/implements2/impls.StructWithEmbedded", "example.com/implements2/impls.StructWithEmbeddedConcrete"}},
+		{"Store", []string{"example.com/implements2/impls.MemoryStore"}},
+		{"ReaderConstraint", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.iface, func(t *testing.T) {
+			iface := ifaces.Lookup(tt.iface)
+			if iface == nil {
+				t.Fatalf("interface %q not found", tt.iface)
+			}
+			got := s.ImplementersOf(ctx, iface)
+			if diff := cmp.Diff(tt.want, names(got)); diff != "" {
+				t.Errorf("ImplementersOf(%s) mismatch (-want +got):\n%s", tt.iface, diff)
+			}
+			for _, impl := range got {
+				if !s.Implements(ctx, impl, iface) {
+					t.Errorf("ImplementersOf(%s) reported %s, which does not implement it", tt.iface, impl.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package locator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultDownloadBudget is the number of modules a Downloader downloads at most, if not specified.
+const DefaultDownloadBudget = 20
+
+// Downloader downloads the required modules missing from the module cache with
+// `go mod download`, which respects GOFLAGS, GOPROXY, GOPRIVATE, etc. of the environment.
+// A module is tried only once, and the number of downloads is limited by a budget,
+// so that an unreachable proxy does not make every lookup slow.
+// A Downloader can be shared by the locators of a workspace. The lookups of the same module
+// wait for a single download, while the other modules are downloaded in parallel.
+type Downloader struct {
+	mu        sync.Mutex
+	budget    int
+	attempted map[string]error // by module@version
+	inflight  singleflight.Group
+	logger    *slog.Logger
+}
+
+// NewDownloader creates a Downloader which downloads budget modules at most.
+// A non-positive budget means DefaultDownloadBudget.
+func NewDownloader(budget int, logger *slog.Logger) *Downloader {
+	if budget <= 0 {
+		budget = DefaultDownloadBudget
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Downloader{budget: budget, attempted: make(map[string]error), logger: logger}
+}
+
+// WithDownloader enables downloading the required modules missing from the module cache,
+// when WithGoModuleResolver is also used.
+func WithDownloader(d *Downloader) Option {
+	return func(l *Locator) {
+		l.downloader = d
+	}
+}
+
+// Download runs `go mod download modPath@version` in dir (the root of the main module).
+// It returns the error of the earlier attempt for a module already tried, and an error
+// without running the command when the budget is exhausted. A download cancelled with ctx
+// is not recorded as an attempt.
+func (d *Downloader) Download(ctx context.Context, dir, modPath, version string) error {
+	key := modPath + "@" + version
+	_, err, _ := d.inflight.Do(key, func() (any, error) {
+		return nil, d.download(ctx, dir, key)
+	})
+	return err
+}
+
+func (d *Downloader) download(ctx context.Context, dir, key string) error {
+	d.mu.Lock()
+	if err, ok := d.attempted[key]; ok {
+		d.mu.Unlock()
+		return err
+	}
+	if d.budget <= 0 {
+		d.mu.Unlock()
+		d.logger.WarnContext(ctx, "module download budget exhausted", "module", key)
+		return fmt.Errorf("not downloading %s: download budget exhausted", key)
+	}
+	d.budget--
+	d.mu.Unlock()
+
+	d.logger.InfoContext(ctx, "downloading missing module", "module", key)
+	cmd := exec.CommandContext(ctx, "go", "mod", "download", key)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		err = fmt.Errorf("go mod download %s: %w: %s", key, err, strings.TrimSpace(stderr.String()))
+		d.logger.WarnContext(ctx, "failed to download module", "module", key, "error", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if ctx.Err() != nil {
+		d.budget++ // may be retried with another context
+		return err
+	}
+	d.attempted[key] = err
+	return err
+}
+
+// Remaining returns the number of downloads left in the budget.
+func (d *Downloader) Remaining() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.budget
+}
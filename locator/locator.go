@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"go/build"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -30,12 +31,20 @@ type Locator struct {
 	rootDir    string
 	replaces   []ReplaceDirective
 	overlay    scanner.Overlay
+	modFile    *ModFile
 
 	// Options for advanced resolution
 	UseGoModuleResolver bool
 	goRoot              string
 	goModCache          string
 	requires            map[string]string // module path -> version
+	downloader          *Downloader       // nil unless WithDownloader is used
+	packageMapping      map[string]string // import path -> absolute directory, see WithPackageMapping
+
+	// GOPATH mode, see WithGOPATHMode
+	gopathMode    bool
+	gopathRoots   []string // the roots given to WithGOPATHMode, $GOPATH if empty
+	gopathSrcDirs []string // the src directories of the roots, set if no go.mod is found
 }
 
 // Option is a functional option for configuring the Locator.
@@ -60,6 +69,34 @@ func WithGoModuleResolver() Option {
 	}
 }
 
+// WithPackageMapping maps import paths to directories directly, without go.mod, e.g. for the
+// packages of a monorepo laid out by Bazel or please, or for vendored forks at nonstandard paths.
+// An import path also covers its sub-packages, in the sub-directories of its directory.
+// Relative directories are relative to the module root. The mapping takes precedence over
+// the replace directives and the module itself.
+func WithPackageMapping(mapping map[string]string) Option {
+	return func(l *Locator) {
+		if l.packageMapping == nil {
+			l.packageMapping = make(map[string]string)
+		}
+		for k, v := range mapping {
+			l.packageMapping[strings.TrimSuffix(k, "/")] = v
+		}
+	}
+}
+
+// WithGOPATHMode resolves the packages of a legacy project without go.mod laid out in GOPATH:
+// the import path of a directory is its path under the src directory of a GOPATH root, and an
+// import is searched in the vendor directories of the project, in GOROOT, then in the src
+// directories of the roots in order. The roots are given, or $GOPATH if none (`go env GOPATH`).
+// It only applies if no go.mod is found; a module is always resolved in module mode.
+func WithGOPATHMode(roots ...string) Option {
+	return func(l *Locator) {
+		l.gopathMode = true
+		l.gopathRoots = append(l.gopathRoots, roots...)
+	}
+}
+
 // New creates a new Locator by searching for a go.mod file.
 // It starts searching from startPath and moves up the directory tree.
 func New(startPath string, options ...Option) (*Locator, error) {
@@ -76,6 +113,13 @@ func New(startPath string, options ...Option) (*Locator, error) {
 	}
 
 	rootDir, err := findModuleRoot(absPath)
+	if err != nil && l.gopathMode {
+		// The project is the directory of startPath, with its path under GOPATH as module path.
+		if err := l.initGOPATH(absPath); err != nil {
+			return nil, err
+		}
+		rootDir, err = absPath, nil
+	}
 	if err != nil {
 		// If resolver is enabled, not finding a go.mod is not a fatal error
 		// as we might be resolving stdlib packages.
@@ -88,6 +132,13 @@ func New(startPath string, options ...Option) (*Locator, error) {
 	}
 	l.rootDir = rootDir
 
+	for importPath, dir := range l.packageMapping {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(l.rootDir, dir)
+		}
+		l.packageMapping[importPath] = filepath.Clean(dir)
+	}
+
 	var goModContent []byte
 	if l.overlay != nil {
 		if content, ok := l.overlay["go.mod"]; ok {
@@ -116,6 +167,12 @@ func New(startPath string, options ...Option) (*Locator, error) {
 		}
 		l.replaces = replaces
 
+		modFile, err := ParseModFile(filepath.Join(l.rootDir, "go.mod"), goModContent)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not parse go.mod: %v\n", err)
+		}
+		l.modFile = modFile
+
 		if l.UseGoModuleResolver {
 			requires, err := getRequireDirectivesFromBytes(goModContent)
 			if err != nil {
@@ -147,8 +204,36 @@ func (l *Locator) ModulePath() string {
 	return l.modulePath
 }
 
+// GoRoot returns the GOROOT used to resolve the standard library packages.
+// It is empty unless the locator is created with WithGoModuleResolver.
+func (l *Locator) GoRoot() string {
+	return l.goRoot
+}
+
+// GoModCache returns the module cache directory used to resolve the external packages.
+// It is empty unless the locator is created with WithGoModuleResolver.
+func (l *Locator) GoModCache() string {
+	return l.goModCache
+}
+
+// ModFile returns the parsed go.mod of the module, or nil if no go.mod was found.
+func (l *Locator) ModFile() *ModFile {
+	return l.modFile
+}
+
 // FindPackageDir converts an import path to a physical directory path.
 func (l *Locator) FindPackageDir(importPath string) (string, error) {
+	return l.FindPackageDirContext(context.Background(), importPath)
+}
+
+// FindPackageDirContext is FindPackageDir with a context, which cancels the download of
+// a missing module (see WithDownloader).
+func (l *Locator) FindPackageDirContext(ctx context.Context, importPath string) (string, error) {
+	// 0. Check the package mapping
+	if dir, ok := l.MappedPackageDir(importPath); ok {
+		return dir, nil
+	}
+
 	// 1. Check replace directives
 	for _, r := range l.replaces {
 		if strings.HasPrefix(importPath, r.OldPath) {
@@ -184,7 +269,7 @@ func (l *Locator) FindPackageDir(importPath string) (string, error) {
 				// or the original importPath itself matches the current module (which it wouldn't if a replace rule was hit).
 				// This implies that module-to-module replaces that point to *other* modules are not fully supported by this iteration.
 				// Let's try to resolve it within the current module context.
-				if l.modulePath != "" && strings.HasPrefix(newImportPath, l.modulePath) {
+				if l.modulePath != "" && withinImportPath(newImportPath, l.modulePath) {
 					relPath := strings.TrimPrefix(newImportPath, l.modulePath)
 					candidatePath := filepath.Join(l.rootDir, relPath)
 					if stat, err := os.Stat(candidatePath); err == nil && stat.IsDir() {
@@ -196,7 +281,7 @@ func (l *Locator) FindPackageDir(importPath string) (string, error) {
 	}
 
 	// 2. Try with the current module context
-	if l.modulePath != "" && strings.HasPrefix(importPath, l.modulePath) {
+	if l.modulePath != "" && withinImportPath(importPath, l.modulePath) {
 		relPath := strings.TrimPrefix(importPath, l.modulePath)
 		candidatePath := filepath.Join(l.rootDir, relPath)
 		if stat, err := os.Stat(candidatePath); err == nil && stat.IsDir() {
@@ -204,7 +289,13 @@ func (l *Locator) FindPackageDir(importPath string) (string, error) {
 		}
 	}
 
-	// 3. If resolver is enabled, try GOROOT and GOMODCACHE
+	// 3. In GOPATH mode, try the vendor directories, GOROOT and the GOPATH roots
+	if dir, ok := l.findGOPATHPackageDir(importPath); ok {
+		return dir, nil
+	}
+
+	// 4. If resolver is enabled, try GOROOT and GOMODCACHE
+	var downloadErr error // the cause, if a missing module could not be downloaded
 	if l.UseGoModuleResolver {
 		// Try standard library in GOROOT
 		if l.goRoot != "" {
@@ -217,7 +308,7 @@ func (l *Locator) FindPackageDir(importPath string) (string, error) {
 		// Try external modules in GOMODCACHE
 		if l.goModCache != "" {
 			for mod, ver := range l.requires {
-				if strings.HasPrefix(importPath, mod) {
+				if withinImportPath(importPath, mod) {
 					// Path in cache is ${GOMODCACHE}/${module}@${version}/${subpath}
 					// Module paths with uppercase letters are encoded.
 					escapedMod, err := module.EscapePath(mod)
@@ -232,16 +323,169 @@ func (l *Locator) FindPackageDir(importPath string) (string, error) {
 					if stat, err := os.Stat(candidatePath); err == nil && stat.IsDir() {
 						return candidatePath, nil
 					}
+					// The module may be missing from the cache, e.g. on a fresh CI machine.
+					if l.downloader == nil {
+						continue
+					}
+					if _, err := os.Stat(baseDir); err == nil {
+						continue // the module is there, but not the package
+					}
+					if err := l.downloader.Download(ctx, l.rootDir, mod, ver); err != nil {
+						downloadErr = err
+						continue
+					}
+					if stat, err := os.Stat(candidatePath); err == nil && stat.IsDir() {
+						return candidatePath, nil
+					}
 				}
 			}
 		}
 	}
 
 	// If no resolution method succeeded, return an error.
+	err := fmt.Errorf("import path %q could not be resolved", importPath)
 	if l.modulePath != "" {
-		return "", fmt.Errorf("import path %q could not be resolved. Current module is %q (root: %s)", importPath, l.modulePath, l.rootDir)
+		err = fmt.Errorf("import path %q could not be resolved. Current module is %q (root: %s)", importPath, l.modulePath, l.rootDir)
+	}
+	if downloadErr != nil {
+		return "", fmt.Errorf("%w: %w", err, downloadErr)
+	}
+	return "", err
+}
+
+// GOPATHSrcDirs returns the src directories of the GOPATH roots, if the locator resolves the
+// packages in GOPATH mode (see WithGOPATHMode), or nil.
+func (l *Locator) GOPATHSrcDirs() []string {
+	return l.gopathSrcDirs
+}
+
+// initGOPATH sets up the resolution in GOPATH mode for the project at absPath, which must be in
+// the src directory of a root.
+func (l *Locator) initGOPATH(absPath string) error {
+	roots := l.gopathRoots
+	if len(roots) == 0 {
+		roots = filepath.SplitList(build.Default.GOPATH)
+	}
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path for GOPATH root %s: %w", root, err)
+		}
+		l.gopathSrcDirs = append(l.gopathSrcDirs, filepath.Join(absRoot, "src"))
+	}
+	for _, srcDir := range l.gopathSrcDirs {
+		if withinDir(absPath, srcDir) {
+			relPath, err := filepath.Rel(srcDir, absPath)
+			if err != nil {
+				return fmt.Errorf("failed to get relative path for %s from %s: %w", absPath, srcDir, err)
+			}
+			if relPath != "." {
+				l.modulePath = filepath.ToSlash(relPath)
+			}
+			l.goRoot = runtime.GOROOT()
+			return nil
+		}
+	}
+	return fmt.Errorf("go.mod not found, and %s is not in the src directory of a GOPATH root (%s)", absPath, strings.Join(roots, string(filepath.ListSeparator)))
+}
+
+// findGOPATHPackageDir returns the directory of importPath in GOPATH mode: in the vendor
+// directories from the project up to the src directory, in GOROOT, then in the GOPATH roots.
+func (l *Locator) findGOPATHPackageDir(importPath string) (string, bool) {
+	if len(l.gopathSrcDirs) == 0 {
+		return "", false
+	}
+	isDir := func(dir string) bool {
+		stat, err := os.Stat(dir)
+		return err == nil && stat.IsDir()
+	}
+	relPath := filepath.FromSlash(importPath)
+	for _, srcDir := range l.gopathSrcDirs {
+		if !withinDir(l.rootDir, srcDir) {
+			continue
+		}
+		for dir := l.rootDir; withinDir(dir, srcDir) && dir != srcDir; dir = filepath.Dir(dir) {
+			if candidate := filepath.Join(dir, "vendor", relPath); isDir(candidate) {
+				return candidate, true
+			}
+		}
+	}
+	if l.goRoot != "" {
+		if candidate := filepath.Join(l.goRoot, "src", relPath); isDir(candidate) {
+			return candidate, true
+		}
+	}
+	for _, srcDir := range l.gopathSrcDirs {
+		if candidate := filepath.Join(srcDir, relPath); isDir(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// Replaces reports whether a replace directive of the module applies to the import path.
+func (l *Locator) Replaces(importPath string) bool {
+	for _, r := range l.replaces {
+		if withinImportPath(importPath, r.OldPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// MappedPackageDir returns the directory of importPath given by the package mapping
+// (see WithPackageMapping), if it exists.
+func (l *Locator) MappedPackageDir(importPath string) (string, bool) {
+	var best string
+	for path := range l.packageMapping {
+		if (importPath == path || strings.HasPrefix(importPath, path+"/")) && len(path) > len(best) {
+			best = path
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	dir := filepath.Join(l.packageMapping[best], filepath.FromSlash(strings.TrimPrefix(importPath, best)))
+	if stat, err := os.Stat(dir); err != nil || !stat.IsDir() {
+		return "", false
+	}
+	return dir, true
+}
+
+// mappedImportPath returns the import path of the directory absPath given by the package mapping.
+func (l *Locator) mappedImportPath(absPath string) (string, bool) {
+	var bestPath, bestDir string
+	for path, dir := range l.packageMapping {
+		if withinDir(absPath, dir) && len(dir) > len(bestDir) {
+			bestPath, bestDir = path, dir
+		}
+	}
+	if bestDir == "" {
+		return "", false
+	}
+	relPath, err := filepath.Rel(bestDir, absPath)
+	if err != nil {
+		return "", false
+	}
+	if relPath == "." {
+		return bestPath, true
 	}
-	return "", fmt.Errorf("import path %q could not be resolved", importPath)
+	return bestPath + "/" + filepath.ToSlash(relPath), true
+}
+
+// withinDir reports whether path is dir or inside it. A sibling sharing the prefix, e.g.
+// "ws/api-client" for "ws/api", is not inside it.
+func withinDir(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// withinImportPath reports whether importPath is the package prefix or a package under it, in
+// the same way as withinDir for the directories.
+func withinImportPath(importPath, prefix string) bool {
+	return importPath == prefix || strings.HasPrefix(importPath, prefix+"/")
 }
 
 // findModuleRoot searches for any go.mod starting from a given directory and moving upwards.
@@ -486,15 +730,36 @@ func parseReplaceLine(line string) (ReplaceDirective, error) {
 }
 
 // PathToImport converts an absolute directory path to its corresponding Go import path.
-// It considers the module's own path and any `replace` directives.
+// It considers the package mapping, the module's own path and any `replace` directives.
 func (l *Locator) PathToImport(absPath string) (string, error) {
 	absPath, err := filepath.Abs(absPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to get absolute path for %s: %w", absPath, err)
 	}
 
+	// 0. Check the package mapping, as a mapped directory may be inside the module root.
+	if importPath, ok := l.mappedImportPath(absPath); ok {
+		return importPath, nil
+	}
+
+	// In GOPATH mode, the import path is the path under the src directory, the project included,
+	// without the vendor directory prefix.
+	for _, srcDir := range l.gopathSrcDirs {
+		if withinDir(absPath, srcDir) && absPath != srcDir {
+			relPath, err := filepath.Rel(srcDir, absPath)
+			if err != nil {
+				return "", fmt.Errorf("failed to get relative path for %s from %s: %w", absPath, srcDir, err)
+			}
+			importPath := filepath.ToSlash(relPath)
+			if i := strings.LastIndex("/"+importPath, "/vendor/"); i >= 0 {
+				importPath = importPath[i+len("vendor/"):]
+			}
+			return importPath, nil
+		}
+	}
+
 	// 1. Check if it's inside the main module root.
-	if strings.HasPrefix(absPath, l.rootDir) {
+	if withinDir(absPath, l.rootDir) {
 		relPath, err := filepath.Rel(l.rootDir, absPath)
 		if err != nil {
 			return "", fmt.Errorf("failed to get relative path for %s from root %s: %w", absPath, l.rootDir, err)
@@ -517,7 +782,7 @@ func (l *Locator) PathToImport(absPath string) (string, error) {
 			replacedDirAbs = filepath.Join(l.rootDir, r.NewPath)
 		}
 
-		if strings.HasPrefix(absPath, replacedDirAbs) {
+		if withinDir(absPath, filepath.Clean(replacedDirAbs)) {
 			relPath, err := filepath.Rel(replacedDirAbs, absPath)
 			if err != nil {
 				return "", fmt.Errorf("failed to get relative path for %s from replaced dir %s: %w", absPath, replacedDirAbs, err)
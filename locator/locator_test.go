@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -455,6 +456,169 @@ replace example.com/parent => ../
 	}
 }
 
+func TestPathToImport_SiblingModule(t *testing.T) {
+	// api and api-client are sibling modules; the directory prefix is not a module boundary.
+	root := t.TempDir()
+	apiDir := filepath.Join(root, "api")
+	clientPkgDir := filepath.Join(root, "api-client", "sdk")
+	for _, dir := range []string{apiDir, clientPkgDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(apiDir, "go.mod"), []byte("module example.com/api\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	l, err := New(apiDir)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if got, err := l.PathToImport(clientPkgDir); err == nil {
+		t.Errorf("PathToImport() of the sibling module: want an error, got %q", got)
+	}
+	if got, err := l.FindPackageDir("example.com/api-client/sdk"); err == nil {
+		t.Errorf("FindPackageDir() of example.com/api-client/sdk: want an error, got %q", got)
+	}
+}
+
+func TestFindPackageDirWithPackageMapping(t *testing.T) {
+	rootDir, _, cleanup := setupTestModuleWithContent(t, "module example.com/me", []string{
+		filepath.Join("bazel-bin", "gen", "api"),
+		filepath.Join("third_party", "fork", "sub"),
+		"lib",
+	})
+	defer cleanup()
+
+	l, err := New(rootDir, WithPackageMapping(map[string]string{
+		"example.com/gen/api":   filepath.Join(rootDir, "bazel-bin", "gen", "api"),
+		"github.com/other/fork": "third_party/fork",
+	}))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	testCases := []struct {
+		importPath string
+		dir        string
+	}{
+		{"example.com/gen/api", filepath.Join(rootDir, "bazel-bin", "gen", "api")},
+		{"github.com/other/fork", filepath.Join(rootDir, "third_party", "fork")},
+		{"github.com/other/fork/sub", filepath.Join(rootDir, "third_party", "fork", "sub")},
+		{"example.com/me/lib", filepath.Join(rootDir, "lib")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.importPath, func(t *testing.T) {
+			dir, err := l.FindPackageDir(tc.importPath)
+			if err != nil {
+				t.Fatalf("FindPackageDir() failed: %v", err)
+			}
+			if dir != tc.dir {
+				t.Errorf("FindPackageDir() = %q, want %q", dir, tc.dir)
+			}
+			// The mapped directories inside the module root get their mapped import paths back.
+			got, err := l.PathToImport(tc.dir)
+			if err != nil {
+				t.Fatalf("PathToImport() failed: %v", err)
+			}
+			if got != tc.importPath {
+				t.Errorf("PathToImport() = %q, want %q", got, tc.importPath)
+			}
+		})
+	}
+
+	if _, err := l.FindPackageDir("github.com/other/fork/missing"); err == nil {
+		t.Errorf("FindPackageDir() of a missing sub-package should fail")
+	}
+}
+
+func TestGOPATHMode(t *testing.T) {
+	// Two GOPATH roots: the legacy project with a vendored package in the first, a library in the second.
+	root1, root2 := t.TempDir(), t.TempDir()
+	projectDir := filepath.Join(root1, "src", "example.com", "legacy")
+	vendoredDir := filepath.Join(projectDir, "vendor", "github.com", "dep", "log")
+	libDir := filepath.Join(root2, "src", "example.com", "lib", "util")
+	for _, dir := range []string{filepath.Join(projectDir, "model"), vendoredDir, libDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+	}
+
+	if _, err := New(projectDir); err == nil {
+		t.Fatalf("New() without go.mod and WithGOPATHMode: want an error")
+	}
+	if _, err := New(t.TempDir(), WithGOPATHMode(root1, root2)); err == nil {
+		t.Fatalf("New() out of the GOPATH roots: want an error")
+	}
+
+	l, err := New(projectDir, WithGOPATHMode(root1, root2))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if got, want := l.ModulePath(), "example.com/legacy"; got != want {
+		t.Errorf("ModulePath() = %q, want %q", got, want)
+	}
+
+	findCases := map[string]string{
+		"example.com/legacy/model": filepath.Join(projectDir, "model"),
+		"github.com/dep/log":       vendoredDir,
+		"example.com/lib/util":     libDir,
+		"fmt":                      filepath.Join(runtime.GOROOT(), "src", "fmt"),
+	}
+	for importPath, want := range findCases {
+		got, err := l.FindPackageDir(importPath)
+		if err != nil {
+			t.Errorf("FindPackageDir(%q) failed: %v", importPath, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("FindPackageDir(%q) = %q, want %q", importPath, got, want)
+		}
+	}
+	if got, err := l.FindPackageDir("example.com/missing"); err == nil {
+		t.Errorf("FindPackageDir() of a missing package: want an error, got %q", got)
+	}
+
+	importCases := map[string]string{
+		projectDir:                         "example.com/legacy",
+		filepath.Join(projectDir, "model"): "example.com/legacy/model",
+		vendoredDir:                        "github.com/dep/log",
+		libDir:                             "example.com/lib/util",
+	}
+	for dir, want := range importCases {
+		got, err := l.PathToImport(dir)
+		if err != nil {
+			t.Errorf("PathToImport(%q) failed: %v", dir, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("PathToImport(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+func TestGOPATHMode_ModuleTakesPrecedence(t *testing.T) {
+	root := t.TempDir()
+	projectDir := filepath.Join(root, "src", "example.com", "legacy")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module example.com/modern\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	l, err := New(projectDir, WithGOPATHMode(root))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if got, want := l.ModulePath(), "example.com/modern"; got != want {
+		t.Errorf("ModulePath() = %q, want %q", got, want)
+	}
+	if l.GOPATHSrcDirs() != nil {
+		t.Errorf("GOPATHSrcDirs() = %v, want nil in module mode", l.GOPATHSrcDirs())
+	}
+}
+
 func TestFindPackageDirWithReplaceParent(t *testing.T) {
 	// This test simulates a common scenario in development where a tool (sub-module)
 	// wants to use the development version of its dependency (the parent module).
@@ -0,0 +1,266 @@
+package locator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// Require represents a single require directive in a go.mod file.
+type Require struct {
+	Path     string
+	Version  string
+	Indirect bool // True if the requirement is marked with `// indirect`
+}
+
+// Exclude represents a single exclude directive in a go.mod file.
+type Exclude struct {
+	Path    string
+	Version string
+}
+
+// ModFile is a typed view of a parsed go.mod file.
+type ModFile struct {
+	// Filename is the path of the go.mod file. It may be empty for in-memory content.
+	Filename  string
+	Path      string // The module path
+	GoVersion string // The version from the `go` directive, empty if not specified
+	Toolchain string // The version from the `toolchain` directive, empty if not specified
+	Requires  []Require
+	Replaces  []ReplaceDirective
+	Excludes  []Exclude
+}
+
+// Dir returns the directory containing the go.mod file.
+func (f *ModFile) Dir() string {
+	if f.Filename == "" {
+		return ""
+	}
+	return filepath.Dir(f.Filename)
+}
+
+// Require returns the require directive for the given module path, if any.
+func (f *ModFile) Require(modulePath string) (Require, bool) {
+	for _, r := range f.Requires {
+		if r.Path == modulePath {
+			return r, true
+		}
+	}
+	return Require{}, false
+}
+
+// Replace returns the replace directive applied to the given module version, if any.
+// A directive with an explicit old version takes precedence over a wildcard one.
+func (f *ModFile) Replace(modulePath, version string) (ReplaceDirective, bool) {
+	var wildcard *ReplaceDirective
+	for i, r := range f.Replaces {
+		if r.OldPath != modulePath {
+			continue
+		}
+		if r.OldVersion == version && version != "" {
+			return r, true
+		}
+		if r.OldVersion == "" && wildcard == nil {
+			wildcard = &f.Replaces[i]
+		}
+	}
+	if wildcard != nil {
+		return *wildcard, true
+	}
+	return ReplaceDirective{}, false
+}
+
+// IsExcluded reports whether the given module version is excluded by this go.mod file.
+func (f *ModFile) IsExcluded(modulePath, version string) bool {
+	for _, e := range f.Excludes {
+		if e.Path == modulePath && e.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseModFile parses the content of a go.mod file.
+// The filename is used for error messages and to resolve local replace paths.
+func ParseModFile(filename string, content []byte) (*ModFile, error) {
+	f, err := modfile.Parse(filename, content, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return newModFile(filename, f), nil
+}
+
+// parseModFileLax is like ParseModFile but ignores unknown statements.
+// It is used for go.mod files of dependency modules.
+func parseModFileLax(filename string, content []byte) (*ModFile, error) {
+	f, err := modfile.ParseLax(filename, content, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return newModFile(filename, f), nil
+}
+
+func newModFile(filename string, f *modfile.File) *ModFile {
+	mf := &ModFile{Filename: filename}
+	if f.Module != nil {
+		mf.Path = f.Module.Mod.Path
+	}
+	if f.Go != nil {
+		mf.GoVersion = f.Go.Version
+	}
+	if f.Toolchain != nil {
+		mf.Toolchain = f.Toolchain.Name
+	}
+	for _, r := range f.Require {
+		mf.Requires = append(mf.Requires, Require{Path: r.Mod.Path, Version: r.Mod.Version, Indirect: r.Indirect})
+	}
+	for _, r := range f.Replace {
+		mf.Replaces = append(mf.Replaces, newReplaceDirective(r))
+	}
+	for _, e := range f.Exclude {
+		mf.Excludes = append(mf.Excludes, Exclude{Path: e.Mod.Path, Version: e.Mod.Version})
+	}
+	return mf
+}
+
+func newReplaceDirective(r *modfile.Replace) ReplaceDirective {
+	return ReplaceDirective{
+		OldPath:    r.Old.Path,
+		OldVersion: r.Old.Version,
+		NewPath:    r.New.Path,
+		NewVersion: r.New.Version,
+		IsLocal:    r.New.Version == "" && modfile.IsDirectoryPath(r.New.Path),
+	}
+}
+
+// WorkFile is a typed view of a parsed go.work file.
+type WorkFile struct {
+	Filename  string
+	GoVersion string
+	Toolchain string
+	// Uses holds the absolute directories of the modules listed in `use` directives.
+	Uses     []string
+	Replaces []ReplaceDirective
+}
+
+// ParseWorkFile parses the content of a go.work file.
+// Relative `use` paths are resolved against the directory of filename.
+func ParseWorkFile(filename string, content []byte) (*WorkFile, error) {
+	f, err := modfile.ParseWork(filename, content, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	wf := &WorkFile{Filename: filename}
+	if f.Go != nil {
+		wf.GoVersion = f.Go.Version
+	}
+	if f.Toolchain != nil {
+		wf.Toolchain = f.Toolchain.Name
+	}
+	baseDir := filepath.Dir(filename)
+	for _, u := range f.Use {
+		dir := u.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(baseDir, dir)
+		}
+		wf.Uses = append(wf.Uses, dir)
+	}
+	for _, r := range f.Replace {
+		wf.Replaces = append(wf.Replaces, newReplaceDirective(r))
+	}
+	return wf, nil
+}
+
+// FindWorkFile searches for a go.work file starting from dir and moving upwards.
+// It returns an empty string (and no error) if no go.work file is found.
+func FindWorkFile(dir string) (string, error) {
+	currentDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path for %s: %w", dir, err)
+	}
+	for {
+		workPath := filepath.Join(currentDir, "go.work")
+		if _, err := os.Stat(workPath); err == nil {
+			return workPath, nil
+		}
+		parentDir := filepath.Dir(currentDir)
+		if parentDir == currentDir {
+			return "", nil
+		}
+		currentDir = parentDir
+	}
+}
+
+// DependencyModFile returns the parsed go.mod of a required module version.
+// Replace directives of the main module are honored. Modules that are not
+// replaced by a local directory are looked up in the module cache, which is
+// only available when the locator was created with WithGoModuleResolver.
+// It returns nil (and no error) if the go.mod cannot be found locally.
+func (l *Locator) DependencyModFile(modulePath, version string) (*ModFile, error) {
+	if l.modFile != nil {
+		if r, ok := l.modFile.Replace(modulePath, version); ok {
+			return l.ReplacedModFile(r, l.rootDir)
+		}
+	}
+	return l.cachedModFile(modulePath, version)
+}
+
+// ReplacedModFile returns the parsed go.mod of the replacement of a replace directive,
+// which may come from another go.mod or go.work file than the main module's.
+// A local replacement is resolved against baseDir, the directory of the file declaring the directive.
+// It returns nil (and no error) if the go.mod cannot be found locally.
+func (l *Locator) ReplacedModFile(r ReplaceDirective, baseDir string) (*ModFile, error) {
+	if r.IsLocal {
+		dir := r.NewPath
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(baseDir, dir)
+		}
+		return readModFileLax(filepath.Join(dir, "go.mod"))
+	}
+	return l.cachedModFile(r.NewPath, r.NewVersion)
+}
+
+// cachedModFile returns the parsed go.mod of a module version in the module cache.
+func (l *Locator) cachedModFile(modulePath, version string) (*ModFile, error) {
+	if l.goModCache == "" || version == "" {
+		return nil, nil
+	}
+
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("escaping module path %q: %w", modulePath, err)
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("escaping module version %q: %w", version, err)
+	}
+
+	// The download cache keeps the go.mod of every module version in the build list,
+	// even if the module's source has never been extracted.
+	candidates := []string{
+		filepath.Join(l.goModCache, "cache", "download", escapedPath, "@v", escapedVersion+".mod"),
+		filepath.Join(l.goModCache, escapedPath+"@"+escapedVersion, "go.mod"),
+	}
+	for _, candidate := range candidates {
+		mf, err := readModFileLax(candidate)
+		if err != nil || mf != nil {
+			return mf, err
+		}
+	}
+	return nil, nil
+}
+
+// readModFileLax reads and parses a dependency go.mod, returning nil if the file does not exist.
+func readModFileLax(filename string) (*ModFile, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	return parseModFileLax(filename, content)
+}
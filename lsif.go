@@ -0,0 +1,429 @@
+package goscan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"io"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// LSIFVersion is the version of the LSIF format written by LSIFIndex.
+const LSIFVersion = "0.4.3"
+
+// LSIFIndex is the index of the definitions and references of the top-level declarations
+// (functions, methods, types, constants and variables) of a set of scanned packages, written
+// in the LSIF format (https://microsoft.github.io/language-server-protocol/specifications/lsif/0.4.0/specification/),
+// so that code navigation in Sourcegraph and editors works without running gopls.
+//
+// The references found in the syntax are the uses of the declarations of the package itself,
+// and of the imported packages of the index through their package name, e.g. `pkg.Func`. The
+// method calls, which need the types of their receivers, are added with AddCall, e.g. from
+// the calls resolved by symgo.
+//
+// SCIP, the successor of LSIF, is encoded with protocol buffers and is not written.
+type LSIFIndex struct {
+	fset    *token.FileSet
+	symbols []*lsifSymbol
+	byName  map[string]map[string]*lsifSymbol // by import path, then name; methods are left out
+	byFunc  map[*scanner.FunctionInfo]*lsifSymbol
+	names   map[string]string // the package names, by import path
+	// calls are the identifiers naming the callee of the calls, by the position of the calls.
+	calls map[token.Pos][]*ast.Ident
+	// referenced is the symbol each identifier refers to, so that an identifier is a single range.
+	referenced map[token.Pos]*lsifSymbol
+}
+
+type lsifSymbol struct {
+	pkgPath string
+	name    string // e.g. "Run", or "Server.Start" for a method
+	ident   *ast.Ident
+	hover   string
+	export  bool
+	refs    []*ast.Ident
+}
+
+// NewLSIFIndex indexes the declarations of the packages and their references in the syntax.
+// The packages must be scanned with their function bodies, by the scanner of fset.
+func NewLSIFIndex(fset *token.FileSet, pkgs ...*scanner.PackageInfo) *LSIFIndex {
+	x := &LSIFIndex{
+		fset:       fset,
+		byName:     make(map[string]map[string]*lsifSymbol),
+		byFunc:     make(map[*scanner.FunctionInfo]*lsifSymbol),
+		names:      make(map[string]string),
+		calls:      make(map[token.Pos][]*ast.Ident),
+		referenced: make(map[token.Pos]*lsifSymbol),
+	}
+	for _, pkg := range pkgs {
+		if pkg != nil {
+			x.addDefinitions(pkg)
+		}
+	}
+	for _, pkg := range pkgs {
+		if pkg != nil {
+			x.addReferences(pkg)
+		}
+	}
+	sort.SliceStable(x.symbols, func(i, j int) bool {
+		return x.lessPos(x.symbols[i].ident.Pos(), x.symbols[j].ident.Pos())
+	})
+	return x
+}
+
+func (x *LSIFIndex) addDefinitions(pkg *scanner.PackageInfo) {
+	x.names[pkg.ImportPath] = pkg.Name
+	if x.byName[pkg.ImportPath] == nil {
+		x.byName[pkg.ImportPath] = make(map[string]*lsifSymbol)
+	}
+	define := func(ident *ast.Ident, name, hover, doc string) *lsifSymbol {
+		if ident == nil || ident.Name == "_" {
+			return nil
+		}
+		if doc != "" {
+			hover += "\n\n" + doc
+		}
+		sym := &lsifSymbol{pkgPath: pkg.ImportPath, name: name, ident: ident, hover: hover, export: ident.IsExported()}
+		x.symbols = append(x.symbols, sym)
+		return sym
+	}
+
+	for _, fn := range pkg.Functions {
+		if fn.AstDecl == nil {
+			continue
+		}
+		decl := *fn.AstDecl
+		decl.Doc, decl.Body = nil, nil
+		name := fn.Name
+		if recv := fn.CanonicalName(); recv.IsMethod() {
+			name = recv.TypeName + "." + fn.Name
+		}
+		if sym := define(fn.AstDecl.Name, name, x.source(&decl), fn.Doc); sym != nil {
+			x.byFunc[fn] = sym
+			if fn.Receiver == nil && fn.Name != "init" {
+				x.byName[pkg.ImportPath][fn.Name] = sym
+			}
+		}
+	}
+	for _, t := range pkg.Types {
+		ts, ok := t.Node.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+		spec := *ts
+		spec.Doc, spec.Comment = nil, nil
+		if sym := define(ts.Name, t.Name, "type "+x.source(&spec), t.Doc); sym != nil {
+			x.byName[pkg.ImportPath][t.Name] = sym
+		}
+	}
+	for _, c := range pkg.Constants {
+		ident, _ := c.Node.(*ast.Ident)
+		hover := "const " + c.Name
+		if c.Type != nil {
+			hover += " " + c.Type.String()
+		}
+		if c.Value != "" {
+			hover += " = " + c.Value
+		}
+		if sym := define(ident, c.Name, hover, c.Doc); sym != nil {
+			x.byName[pkg.ImportPath][c.Name] = sym
+		}
+	}
+	for _, v := range pkg.Variables {
+		ident, _ := v.Node.(*ast.Ident)
+		hover := "var " + v.Name
+		if v.Type != nil {
+			hover += " " + v.Type.String()
+		}
+		if sym := define(ident, v.Name, hover, v.Doc); sym != nil {
+			x.byName[pkg.ImportPath][v.Name] = sym
+		}
+	}
+}
+
+// addReferences finds the references of the indexed declarations in the files of the package.
+// An identifier refers to a declaration of the package if the parser resolved it to the
+// declaration, or did not resolve it (for the declarations of the other files), and refers to a
+// declaration of an imported package as the selector of its package name.
+func (x *LSIFIndex) addReferences(pkg *scanner.PackageInfo) {
+	local := x.byName[pkg.ImportPath]
+	for _, file := range pkg.SortedAstFiles() {
+		imports := x.importLookup(file)
+		skip := make(map[*ast.Ident]bool) // the identifiers which are not references to top-level declarations
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.File:
+				skip[n.Name] = true // the package clause
+			case *ast.ImportSpec:
+				return false
+			case *ast.FuncDecl:
+				skip[n.Name] = true // a method name is not resolved by the parser
+			case *ast.Field:
+				for _, name := range n.Names {
+					skip[name] = true
+				}
+			case *ast.KeyValueExpr:
+				if key, ok := n.Key.(*ast.Ident); ok {
+					skip[key] = true // a field name of a struct literal
+				}
+			case *ast.CallExpr:
+				if ident := calleeIdent(n.Fun); ident != nil {
+					x.calls[n.Pos()] = append(x.calls[n.Pos()], ident)
+				}
+			case *ast.SelectorExpr:
+				skip[n.Sel] = true
+				if pkgIdent, ok := n.X.(*ast.Ident); ok && pkgIdent.Obj == nil {
+					if importPath, ok := imports[pkgIdent.Name]; ok {
+						if sym, ok := x.byName[importPath][n.Sel.Name]; ok {
+							x.addReference(sym, n.Sel)
+						}
+					}
+				}
+			case *ast.Ident:
+				if skip[n] {
+					return false
+				}
+				sym, ok := local[n.Name]
+				if !ok || n.Pos() == sym.ident.Pos() {
+					return false
+				}
+				if n.Obj == nil || n.Obj.Pos() == sym.ident.Pos() {
+					x.addReference(sym, n)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// AddCall adds the call at pos (the position of the *ast.CallExpr) as a reference to the
+// callee, e.g. a method call resolved by symgo. It reports whether the callee is indexed and
+// the call is found.
+func (x *LSIFIndex) AddCall(pos token.Pos, callee *scanner.FunctionInfo) bool {
+	sym, ok := x.byFunc[callee]
+	if !ok {
+		return false
+	}
+	for _, ident := range x.calls[pos] {
+		if ident.Name == callee.Name {
+			x.addReference(sym, ident)
+			return true
+		}
+	}
+	return false
+}
+
+func (x *LSIFIndex) addReference(sym *lsifSymbol, ident *ast.Ident) {
+	if _, ok := x.referenced[ident.Pos()]; ok {
+		return
+	}
+	x.referenced[ident.Pos()] = sym
+	sym.refs = append(sym.refs, ident)
+}
+
+// calleeIdent returns the identifier naming the function of a call, e.g. `Start` for `s.Start()`.
+func calleeIdent(fun ast.Expr) *ast.Ident {
+	for {
+		switch f := fun.(type) {
+		case *ast.ParenExpr:
+			fun = f.X
+		case *ast.IndexExpr: // an instantiation, e.g. `Map[int](xs)`
+			fun = f.X
+		case *ast.IndexListExpr:
+			fun = f.X
+		case *ast.Ident:
+			return f
+		case *ast.SelectorExpr:
+			return f.Sel
+		default:
+			return nil
+		}
+	}
+}
+
+// importLookup maps the package names of the imports of the file to their paths. The name of
+// a package not in the index is the last element of its path.
+func (x *LSIFIndex) importLookup(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		switch {
+		case imp.Name == nil:
+			name, ok := x.names[path]
+			if !ok {
+				name = pathpkg.Base(path)
+			}
+			imports[name] = path
+		case imp.Name.Name != "_" && imp.Name.Name != ".":
+			imports[imp.Name.Name] = path
+		}
+	}
+	return imports
+}
+
+func (x *LSIFIndex) source(node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, x.fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+func (x *LSIFIndex) lessPos(a, b token.Pos) bool {
+	pa, pb := x.fset.Position(a), x.fset.Position(b)
+	if pa.Filename != pb.Filename {
+		return pa.Filename < pb.Filename
+	}
+	return pa.Offset < pb.Offset
+}
+
+// WriteLSIF writes the index as an LSIF dump, one JSON vertex or edge per line, with the
+// documents relative to projectRoot. The output is the same for the same index.
+func (x *LSIFIndex) WriteLSIF(w io.Writer, projectRoot string) error {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return fmt.Errorf("resolving the project root %q: %w", projectRoot, err)
+	}
+	lw := &lsifWriter{enc: json.NewEncoder(w), fset: x.fset, sources: make(map[string][]byte)}
+	lw.emit(map[string]any{
+		"type": "vertex", "label": "metaData", "version": LSIFVersion,
+		"projectRoot": fileURI(root), "positionEncoding": "utf-16",
+		"toolInfo": map[string]any{"name": "go-scan"},
+	})
+	project := lw.emit(map[string]any{"type": "vertex", "label": "project", "kind": "go"})
+
+	// The documents of the definitions and of the references, in the order of their paths.
+	var filenames []string
+	seen := make(map[string]bool)
+	addFile := func(ident *ast.Ident) {
+		if name := x.fset.Position(ident.Pos()).Filename; !seen[name] {
+			seen[name] = true
+			filenames = append(filenames, name)
+		}
+	}
+	for _, sym := range x.symbols {
+		addFile(sym.ident)
+		for _, ref := range sym.refs {
+			addFile(ref)
+		}
+	}
+	sort.Strings(filenames)
+	documents := make(map[string]int, len(filenames))
+	var documentIDs []int
+	for _, name := range filenames {
+		id := lw.emit(map[string]any{"type": "vertex", "label": "document", "uri": fileURI(name), "languageId": "go"})
+		documents[name] = id
+		documentIDs = append(documentIDs, id)
+	}
+
+	contains := make(map[int][]int) // the ranges of each document
+	rangeOf := func(ident *ast.Ident) (rangeID, documentID int) {
+		start, end := lw.position(ident.Pos()), lw.position(ident.End())
+		rangeID = lw.emit(map[string]any{"type": "vertex", "label": "range", "start": start, "end": end})
+		documentID = documents[x.fset.Position(ident.Pos()).Filename]
+		contains[documentID] = append(contains[documentID], rangeID)
+		return rangeID, documentID
+	}
+	for _, sym := range x.symbols {
+		resultSet := lw.emit(map[string]any{"type": "vertex", "label": "resultSet"})
+		defRange, defDocument := rangeOf(sym.ident)
+		lw.emit(map[string]any{"type": "edge", "label": "next", "outV": defRange, "inV": resultSet})
+
+		refs := append([]*ast.Ident(nil), sym.refs...)
+		sort.Slice(refs, func(i, j int) bool { return x.lessPos(refs[i].Pos(), refs[j].Pos()) })
+		refRanges := make(map[int][]int) // by document
+		var refDocuments []int
+		for _, ref := range refs {
+			rangeID, documentID := rangeOf(ref)
+			lw.emit(map[string]any{"type": "edge", "label": "next", "outV": rangeID, "inV": resultSet})
+			if _, ok := refRanges[documentID]; !ok {
+				refDocuments = append(refDocuments, documentID)
+			}
+			refRanges[documentID] = append(refRanges[documentID], rangeID)
+		}
+
+		definition := lw.emit(map[string]any{"type": "vertex", "label": "definitionResult"})
+		lw.emit(map[string]any{"type": "edge", "label": "textDocument/definition", "outV": resultSet, "inV": definition})
+		lw.emit(map[string]any{"type": "edge", "label": "item", "outV": definition, "inVs": []int{defRange}, "document": defDocument})
+
+		references := lw.emit(map[string]any{"type": "vertex", "label": "referenceResult"})
+		lw.emit(map[string]any{"type": "edge", "label": "textDocument/references", "outV": resultSet, "inV": references})
+		lw.emit(map[string]any{"type": "edge", "label": "item", "outV": references, "inVs": []int{defRange}, "document": defDocument, "property": "definitions"})
+		for _, documentID := range refDocuments {
+			lw.emit(map[string]any{"type": "edge", "label": "item", "outV": references, "inVs": refRanges[documentID], "document": documentID, "property": "references"})
+		}
+
+		hover := lw.emit(map[string]any{"type": "vertex", "label": "hoverResult", "result": map[string]any{
+			"contents": []map[string]string{{"language": "go", "value": sym.hover}},
+		}})
+		lw.emit(map[string]any{"type": "edge", "label": "textDocument/hover", "outV": resultSet, "inV": hover})
+
+		if sym.export {
+			moniker := lw.emit(map[string]any{"type": "vertex", "label": "moniker", "scheme": "gomod", "kind": "export", "identifier": sym.pkgPath + ":" + sym.name})
+			lw.emit(map[string]any{"type": "edge", "label": "moniker", "outV": resultSet, "inV": moniker})
+		}
+	}
+
+	for _, documentID := range documentIDs {
+		if ranges := contains[documentID]; len(ranges) > 0 {
+			lw.emit(map[string]any{"type": "edge", "label": "contains", "outV": documentID, "inVs": ranges})
+		}
+	}
+	if len(documentIDs) > 0 {
+		lw.emit(map[string]any{"type": "edge", "label": "contains", "outV": project, "inVs": documentIDs})
+	}
+	return lw.err
+}
+
+// lsifWriter writes the vertices and edges of an LSIF dump, numbering them in order.
+type lsifWriter struct {
+	enc     *json.Encoder
+	fset    *token.FileSet
+	sources map[string][]byte // the contents of the files, for the UTF-16 offsets
+	id      int
+	err     error
+}
+
+func (lw *lsifWriter) emit(element map[string]any) int {
+	lw.id++
+	element["id"] = lw.id
+	if lw.err == nil {
+		lw.err = lw.enc.Encode(element)
+	}
+	return lw.id
+}
+
+// position returns the LSP position (0-based line, and UTF-16 character offset) of pos.
+func (lw *lsifWriter) position(pos token.Pos) map[string]int {
+	p := lw.fset.Position(pos)
+	character := p.Column - 1
+	src, ok := lw.sources[p.Filename]
+	if !ok {
+		src, _ = os.ReadFile(p.Filename)
+		lw.sources[p.Filename] = src
+	}
+	if lineStart := p.Offset - (p.Column - 1); lineStart >= 0 && p.Offset <= len(src) {
+		character = len(utf16.Encode([]rune(string(src[lineStart:p.Offset]))))
+	}
+	return map[string]int{"line": p.Line - 1, "character": character}
+}
+
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if len(path) == 0 || path[0] != '/' {
+		path = "/" + path // e.g. "C:/src" on Windows
+	}
+	return "file://" + path
+}
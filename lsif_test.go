@@ -0,0 +1,183 @@
+package goscan_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestLSIFIndex(t *testing.T) {
+	dir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"lib/lib.go": `package lib
+
+// Greeting is the default greeting.
+const Greeting = "héllo"
+
+type Server struct{ Name string }
+
+func (s *Server) Start() string { return Greeting + s.Name }
+
+func New(name string) *Server { return &Server{Name: name} }
+`,
+		"app/app.go": `package app
+
+import mylib "example.com/app/lib"
+
+var Default = mylib.New("default")
+var Label = "é🙂" + mylib.Greeting // the columns are in UTF-16 code units
+
+func Run() string {
+	s := mylib.New(mylib.Greeting)
+	Run := func() string { return "shadowed" }
+	_ = Run()
+	return s.Start() + Default.Name
+}
+`,
+	})
+	defer cleanup()
+
+	ctx := context.Background()
+	s, err := goscan.New(goscan.WithWorkDir(dir), goscan.WithGoModuleResolver())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	var pkgs []*scanner.PackageInfo
+	for _, path := range []string{"example.com/app/lib", "example.com/app/app"} {
+		pkg, err := s.ScanPackageFromImportPath(ctx, path)
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath(%q) failed: %v", path, err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	lib, app := pkgs[0], pkgs[1]
+
+	index := goscan.NewLSIFIndex(s.Fset(), pkgs...)
+
+	// The method call needs the type of s, so it is added as a resolved call, as symgo does.
+	var start *scanner.FunctionInfo
+	for _, fn := range lib.Functions {
+		if fn.Name == "Start" {
+			start = fn
+		}
+	}
+	var added bool
+	for _, file := range app.AstFiles {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Start" {
+					added = index.AddCall(call.Pos(), start)
+				}
+			}
+			return true
+		})
+	}
+	if !added {
+		t.Fatalf("AddCall() did not add the call of Start")
+	}
+
+	var buf bytes.Buffer
+	if err := index.WriteLSIF(&buf, dir); err != nil {
+		t.Fatalf("WriteLSIF() failed: %v", err)
+	}
+	got := summarizeLSIF(t, buf.Bytes(), dir)
+	want := map[string][]string{
+		"example.com/app/lib:Greeting":     {"app/app.go:6:27", "app/app.go:9:23", "lib/lib.go:8:42"},
+		"example.com/app/lib:Server":       {"lib/lib.go:10:24", "lib/lib.go:10:41", "lib/lib.go:8:10"},
+		"example.com/app/lib:Server.Start": {"app/app.go:12:11"},
+		"example.com/app/lib:New":          {"app/app.go:5:21", "app/app.go:9:13"},
+		"example.com/app/app:Default":      {"app/app.go:12:21"},
+		"example.com/app/app:Label":        nil,
+		"example.com/app/app:Run":          nil, // the calls of Run are the calls of the shadowing variable
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("references mismatch (-want +got):\n%s\n%s", diff, buf.String())
+	}
+
+	var again bytes.Buffer
+	if err := index.WriteLSIF(&again, dir); err != nil {
+		t.Fatalf("WriteLSIF() failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+		t.Errorf("WriteLSIF() is not deterministic")
+	}
+}
+
+// summarizeLSIF returns the references of the exported symbols of an LSIF dump by their monikers,
+// as "file:line:column" with 1-based lines and UTF-16 columns.
+func summarizeLSIF(t *testing.T, dump []byte, root string) map[string][]string {
+	t.Helper()
+	type element struct {
+		ID         int    `json:"id"`
+		Type       string `json:"type"`
+		Label      string `json:"label"`
+		URI        string `json:"uri"`
+		Identifier string `json:"identifier"`
+		Property   string `json:"property"`
+		OutV       int    `json:"outV"`
+		InV        int    `json:"inV"`
+		InVs       []int  `json:"inVs"`
+		Document   int    `json:"document"`
+		Start      struct {
+			Line      int `json:"line"`
+			Character int `json:"character"`
+		} `json:"start"`
+	}
+	elements := make(map[int]element)
+	var edges []element
+	sc := bufio.NewScanner(bytes.NewReader(dump))
+	for sc.Scan() {
+		var e element
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("invalid LSIF line %q: %v", sc.Text(), err)
+		}
+		elements[e.ID] = e
+		if e.Type == "edge" {
+			edges = append(edges, e)
+		}
+	}
+
+	monikers := make(map[int]string) // by result set
+	references := make(map[int]int)  // the reference result of each result set
+	refs := make(map[int][]string)   // by reference result
+	for _, e := range edges {
+		switch e.Label {
+		case "moniker":
+			monikers[e.OutV] = elements[e.InV].Identifier
+		case "textDocument/references":
+			references[e.OutV] = e.InV
+		case "item":
+			if e.Property != "references" {
+				continue
+			}
+			doc := strings.TrimPrefix(elements[e.Document].URI, "file://")
+			rel, err := filepath.Rel(root, doc)
+			if err != nil {
+				t.Fatalf("document out of the project root: %s", doc)
+			}
+			for _, id := range e.InVs {
+				r := elements[id]
+				refs[e.OutV] = append(refs[e.OutV], fmt.Sprintf("%s:%d:%d", filepath.ToSlash(rel), r.Start.Line+1, r.Start.Character+1))
+			}
+		}
+	}
+	got := make(map[string][]string)
+	for resultSet, moniker := range monikers {
+		list := refs[references[resultSet]]
+		sort.Strings(list)
+		got[moniker] = list
+	}
+	return got
+}
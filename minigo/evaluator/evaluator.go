@@ -9,8 +9,10 @@ import (
 	"go/token"
 	"io"
 	"reflect"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	goscan "github.com/podhmo/go-scan"
 	"github.com/podhmo/go-scan/minigo/ffibridge"
@@ -138,6 +140,9 @@ var builtins = map[string]*object.Builtin{
 					return ctx.NewError(pos, "make(map) takes at most 1 size argument")
 				}
 				// The optional size argument is ignored for now.
+				if err := ctx.Allocate(pos, 1); err != nil {
+					return err
+				}
 				return &object.Map{Pairs: make(map[object.HashKey]object.MapPair)}
 
 			case *object.ArrayType:
@@ -164,6 +169,9 @@ var builtins = map[string]*object.Builtin{
 				if length < 0 || capacity < 0 || length > capacity {
 					return ctx.NewError(pos, "invalid arguments: len=%d, cap=%d", length, capacity)
 				}
+				if err := ctx.Allocate(pos, 1+int(capacity)); err != nil {
+					return err
+				}
 
 				elements := make([]object.Object, length, capacity)
 				for i := range elements {
@@ -200,11 +208,16 @@ var builtins = map[string]*object.Builtin{
 				return ctx.NewError(pos, "wrong number of arguments. got=%d, want=2", len(args))
 			}
 
+			// complex(float32, float32) is a complex64.
+			kind := reflect.Complex128
 			getNumberAsFloat := func(arg object.Object) (float64, bool) {
 				switch n := arg.(type) {
 				case *object.Integer:
-					return float64(n.Value), true
+					return integerToFloat(n), true
 				case *object.Float:
+					if n.Kind == reflect.Float32 {
+						kind = reflect.Complex64
+					}
 					return n.Value, true
 				default:
 					return 0, false
@@ -220,7 +233,7 @@ var builtins = map[string]*object.Builtin{
 				return ctx.NewError(pos, "argument 2 to `complex` must be a number, got %s", args[1].Type())
 			}
 
-			return &object.Complex{Real: r, Imag: i}
+			return object.NewComplex(kind, r, i)
 		},
 	},
 	"real": {
@@ -232,6 +245,9 @@ var builtins = map[string]*object.Builtin{
 			if !ok {
 				return ctx.NewError(pos, "argument to `real` must be a complex number, got %s", args[0].Type())
 			}
+			if c.Kind == reflect.Complex64 {
+				return object.NewFloat(reflect.Float32, c.Real)
+			}
 			return &object.Float{Value: c.Real}
 		},
 	},
@@ -244,6 +260,9 @@ var builtins = map[string]*object.Builtin{
 			if !ok {
 				return ctx.NewError(pos, "argument to `imag` must be a complex number, got %s", args[0].Type())
 			}
+			if c.Kind == reflect.Complex64 {
+				return object.NewFloat(reflect.Float32, c.Imag)
+			}
 			return &object.Float{Value: c.Imag}
 		},
 	},
@@ -262,6 +281,9 @@ var builtins = map[string]*object.Builtin{
 				return ctx.NewError(pos, "argument to `append` must be array or nil, got %s", args[0].Type())
 			}
 
+			if err := ctx.Allocate(pos, len(args)-1); err != nil {
+				return err
+			}
 			newElements := make([]object.Object, len(elements), len(elements)+len(args)-1)
 			copy(newElements, elements)
 			newElements = append(newElements, args[1:]...)
@@ -325,18 +347,27 @@ var builtins = map[string]*object.Builtin{
 				return ctx.NewError(pos, "argument to `new` must be a struct type, got %s", args[0].Type())
 			}
 
-			// Create a zero-valued instance of the struct.
-			instance := &object.StructInstance{
-				Def:    def,
-				Fields: make(map[string]object.Object),
+			if err := ctx.Allocate(pos, 1); err != nil {
+				return err
 			}
-			for _, field := range def.Fields {
-				// For now, we'll just initialize with NIL. A more advanced implementation
-				// would handle zero values for different types (0, "", false).
-				instance.Fields[field.Names[0].Name] = object.NIL
+			// Create a zero-valued instance of the struct.
+			var obj object.Object
+			if ctx.ZeroValue != nil {
+				obj = ctx.ZeroValue(def)
+			} else {
+				instance := &object.StructInstance{
+					Def:    def,
+					Fields: make(map[string]object.Object),
+				}
+				for _, field := range def.Fields {
+					// For now, we'll just initialize with NIL. A more advanced implementation
+					// would handle zero values for different types (0, "", false).
+					for _, name := range field.Names {
+						instance.Fields[name.Name] = object.NIL
+					}
+				}
+				obj = instance
 			}
-
-			var obj object.Object = instance
 			return &object.Pointer{Element: &obj}
 		},
 	},
@@ -408,6 +439,11 @@ type Evaluator struct {
 	callStack        []*object.CallFrame
 	currentPanic     *object.Panic // The currently active panic
 	isExecutingDefer bool          // True if the evaluator is currently running a deferred function
+	sandbox          sandbox
+
+	// instantiations caches the instantiations of the generic types and functions by their
+	// type arguments, see instantiate.
+	instantiations map[object.Object]map[string]*object.InstantiatedType
 }
 
 // Config holds the configuration for creating a new Evaluator.
@@ -420,6 +456,17 @@ type Config struct {
 	Stdin        io.Reader
 	Stdout       io.Writer
 	Stderr       io.Writer
+
+	// MaxSteps limits the number of evaluated nodes per execution (0 means unlimited).
+	MaxSteps int
+	// MaxObjects limits the number of objects allocated per execution by composite literals,
+	// make, new and append, and the bytes of the strings concatenated with + (0 means unlimited).
+	MaxObjects int
+	// AllowedImports is the list of package patterns a script can import (nil means any package).
+	AllowedImports []string
+	// DeniedImports is the list of package patterns which cannot be used at all, even from the
+	// packages loaded from source.
+	DeniedImports []string
 }
 
 // New creates a new Evaluator.
@@ -430,6 +477,12 @@ func New(cfg Config) *Evaluator {
 		specialForms: cfg.SpecialForms,
 		packages:     cfg.Packages,
 		callStack:    make([]*object.CallFrame, 0),
+		sandbox: sandbox{
+			maxSteps:       cfg.MaxSteps,
+			maxObjects:     cfg.MaxObjects,
+			allowedImports: cfg.AllowedImports,
+			deniedImports:  cfg.DeniedImports,
+		},
 	}
 	e.BuiltinContext = object.BuiltinContext{
 		Stdin:  cfg.Stdin,
@@ -448,6 +501,8 @@ func New(cfg Config) *Evaluator {
 		NewError: func(pos token.Pos, format string, v ...interface{}) *object.Error {
 			return e.newError(pos, format, v...)
 		},
+		ZeroValue: e.getZeroValueForResolvedType,
+		Allocate:  e.allocate,
 	}
 	return e
 }
@@ -458,9 +513,11 @@ func (e *Evaluator) inferTypeOf(obj object.Object) object.Object {
 	case *object.TypedNil:
 		return o.TypeObject
 	case *object.Integer:
-		return &object.Type{Name: "int"}
+		return &object.Type{Name: o.GoKind().String()}
 	case *object.Float:
-		return &object.Type{Name: "float64"}
+		return &object.Type{Name: o.GoKind().String()}
+	case *object.Complex:
+		return &object.Type{Name: o.GoKind().String()}
 	case *object.String:
 		return &object.Type{Name: "string"}
 	case *object.Boolean:
@@ -743,25 +800,16 @@ func (e *Evaluator) evalBangOperatorExpression(right object.Object) object.Objec
 	}
 }
 
-// evalMinusPrefixOperatorExpression evaluates the '-' prefix expression.
-func (e *Evaluator) evalMinusPrefixOperatorExpression(node ast.Node, right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
-		return e.newError(node.Pos(), "unknown operator: -%s", right.Type())
-	}
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
-}
-
 // evalPrefixExpression dispatches to the correct prefix evaluation function.
 func (e *Evaluator) evalPrefixExpression(node *ast.UnaryExpr, operator string, right object.Object) object.Object {
 	switch operator {
 	case "!":
 		return e.evalBangOperatorExpression(right)
-	case "-":
-		return e.evalMinusPrefixOperatorExpression(node, right)
+	case "-", "^":
+		return e.evalNumericPrefixExpression(node, operator, right)
 	case "+":
 		// Unary plus is a no-op for numbers.
-		if right.Type() != object.INTEGER_OBJ && right.Type() != object.FLOAT_OBJ {
+		if !isNumber(right) {
 			return e.newError(node.Pos(), "invalid operation: unary + on non-number %s", right.Type())
 		}
 		return right
@@ -803,127 +851,17 @@ func (e *Evaluator) evalAddressOfExpression(node *ast.UnaryExpr, env *object.Env
 	}
 }
 
-// unwrapToInt64 is a helper to extract an int64 from an Integer or a GoValue.
-func (e *Evaluator) unwrapToInt64(obj object.Object) (int64, bool) {
-	switch o := obj.(type) {
-	case *object.Integer:
-		return o.Value, true
-	case *object.GoValue:
-		// Check if the underlying Go value is some kind of integer.
-		switch o.Value.Kind() {
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return o.Value.Int(), true
-		}
-	}
-	return 0, false
-}
-
 // evalMixedIntInfixExpression handles infix expressions for combinations of Integer and GoValue(int).
 func (e *Evaluator) evalMixedIntInfixExpression(node ast.Node, operator string, left, right object.Object) object.Object {
-	leftVal, ok1 := e.unwrapToInt64(left)
+	leftVal, ok1 := e.unwrapToInteger(left)
 	if !ok1 {
 		return e.newError(node.Pos(), "left operand is not a valid integer: %s", left.Type())
 	}
-	rightVal, ok2 := e.unwrapToInt64(right)
+	rightVal, ok2 := e.unwrapToInteger(right)
 	if !ok2 {
 		return e.newError(node.Pos(), "right operand is not a valid integer: %s", right.Type())
 	}
-
-	// Now that we have two int64s, we can perform the operation.
-	// This logic is duplicated from evalIntegerInfixExpression.
-	// A future refactor could merge them.
-	switch operator {
-	case "+":
-		return &object.Integer{Value: leftVal + rightVal}
-	case "-":
-		return &object.Integer{Value: leftVal - rightVal}
-	case "*":
-		return &object.Integer{Value: leftVal * rightVal}
-	case "/":
-		if rightVal == 0 {
-			return e.newError(node.Pos(), "division by zero")
-		}
-		return &object.Integer{Value: leftVal / rightVal}
-	case "%":
-		if rightVal == 0 {
-			return e.newError(node.Pos(), "division by zero")
-		}
-		return &object.Integer{Value: leftVal % rightVal}
-	case "<<":
-		return &object.Integer{Value: leftVal << rightVal}
-	case ">>":
-		return &object.Integer{Value: leftVal >> rightVal}
-	case "&":
-		return &object.Integer{Value: leftVal & rightVal}
-	case "|":
-		return &object.Integer{Value: leftVal | rightVal}
-	case "^":
-		return &object.Integer{Value: leftVal ^ rightVal}
-	case "<":
-		return e.nativeBoolToBooleanObject(leftVal < rightVal)
-	case "<=":
-		return e.nativeBoolToBooleanObject(leftVal <= rightVal)
-	case ">":
-		return e.nativeBoolToBooleanObject(leftVal > rightVal)
-	case ">=":
-		return e.nativeBoolToBooleanObject(leftVal >= rightVal)
-	case "==":
-		return e.nativeBoolToBooleanObject(leftVal == rightVal)
-	case "!=":
-		return e.nativeBoolToBooleanObject(leftVal != rightVal)
-	default:
-		// This should be caught by the outer switch, but as a safeguard:
-		return e.newError(node.Pos(), "unknown integer operator: %s", operator)
-	}
-}
-
-// evalIntegerInfixExpression evaluates infix expressions for integers.
-func (e *Evaluator) evalIntegerInfixExpression(node ast.Node, operator string, left, right object.Object) object.Object {
-	leftVal := left.(*object.Integer).Value
-	rightVal := right.(*object.Integer).Value
-
-	switch operator {
-	case "+":
-		return &object.Integer{Value: leftVal + rightVal}
-	case "-":
-		return &object.Integer{Value: leftVal - rightVal}
-	case "*":
-		return &object.Integer{Value: leftVal * rightVal}
-	case "/":
-		if rightVal == 0 {
-			return e.newError(node.Pos(), "division by zero")
-		}
-		return &object.Integer{Value: leftVal / rightVal}
-	case "%":
-		if rightVal == 0 {
-			return e.newError(node.Pos(), "division by zero")
-		}
-		return &object.Integer{Value: leftVal % rightVal}
-	case "<<":
-		return &object.Integer{Value: leftVal << rightVal}
-	case ">>":
-		return &object.Integer{Value: leftVal >> rightVal}
-	case "&":
-		return &object.Integer{Value: leftVal & rightVal}
-	case "|":
-		return &object.Integer{Value: leftVal | rightVal}
-	case "^":
-		return &object.Integer{Value: leftVal ^ rightVal}
-	case "<":
-		return e.nativeBoolToBooleanObject(leftVal < rightVal)
-	case "<=":
-		return e.nativeBoolToBooleanObject(leftVal <= rightVal)
-	case ">":
-		return e.nativeBoolToBooleanObject(leftVal > rightVal)
-	case ">=":
-		return e.nativeBoolToBooleanObject(leftVal >= rightVal)
-	case "==":
-		return e.nativeBoolToBooleanObject(leftVal == rightVal)
-	case "!=":
-		return e.nativeBoolToBooleanObject(leftVal != rightVal)
-	default:
-		return e.newError(node.Pos(), "unknown operator: %s %s %s", left.Type(), operator, right.Type())
-	}
+	return e.evalIntegerInfixExpression(node, operator, leftVal, rightVal)
 }
 
 // unwrapToString is a helper to extract a string from a String or a GoValue.
@@ -954,6 +892,9 @@ func (e *Evaluator) evalMixedStringInfixExpression(node ast.Node, operator strin
 
 	switch operator {
 	case "+":
+		if err := e.allocateString(node.Pos(), leftVal, rightVal); err != nil {
+			return err
+		}
 		return &object.String{Value: leftVal + rightVal}
 	case "==":
 		return e.nativeBoolToBooleanObject(leftVal == rightVal)
@@ -989,30 +930,10 @@ func (e *Evaluator) nativeToValue(val reflect.Value) object.Object {
 	// whose underlying type is a primitive.
 	i := val.Interface()
 	switch v := i.(type) {
-	case int:
-		return &object.Integer{Value: int64(v)}
-	case int8:
-		return &object.Integer{Value: int64(v)}
-	case int16:
-		return &object.Integer{Value: int64(v)}
-	case int32:
-		return &object.Integer{Value: int64(v)}
-	case int64:
-		return &object.Integer{Value: v}
-	case uint:
-		return &object.Integer{Value: int64(v)}
-	case uint8: // byte
-		return &object.Integer{Value: int64(v)}
-	case uint16:
-		return &object.Integer{Value: int64(v)}
-	case uint32:
-		return &object.Integer{Value: int64(v)}
-	case uint64:
-		return &object.Integer{Value: int64(v)}
-	case float32:
-		return &object.Float{Value: float64(v)}
-	case float64:
-		return &object.Float{Value: v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr, float32, float64, complex64, complex128:
+		// The numbers keep their kinds, e.g. a uint8 is an Integer of reflect.Uint8.
+		n, _ := goValueToNumber(reflect.ValueOf(v))
+		return n
 	case string:
 		return &object.String{Value: v}
 	case bool:
@@ -1020,7 +941,7 @@ func (e *Evaluator) nativeToValue(val reflect.Value) object.Object {
 	case []byte:
 		elements := make([]object.Object, len(v))
 		for i, b := range v {
-			elements[i] = &object.Integer{Value: int64(b)}
+			elements[i] = object.NewInteger(reflect.Uint8, int64(b))
 		}
 		return &object.Array{Elements: elements}
 	case []string:
@@ -1035,12 +956,11 @@ func (e *Evaluator) nativeToValue(val reflect.Value) object.Object {
 
 	// If direct conversion fails, fall back to Kind-based conversion.
 	switch val.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return &object.Integer{Value: val.Int()}
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return &object.Integer{Value: int64(val.Uint())} // Treat unsigned as signed for simplicity
-	case reflect.Float32, reflect.Float64:
-		return &object.Float{Value: val.Float()}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		n, _ := goValueToNumber(val)
+		return n
 	case reflect.Ptr, reflect.Interface:
 		if val.IsNil() {
 			return object.NIL
@@ -1085,6 +1005,13 @@ func (e *Evaluator) objectToReflectValue(obj object.Object, targetType reflect.T
 		return val, nil
 	}
 
+	// A struct defined in the script implements an interface with methods through an adapter.
+	if targetType.Kind() == reflect.Interface {
+		if val, ok, err := e.interfaceAdapter(obj, targetType); ok {
+			return val, err
+		}
+	}
+
 	// If the object is already a GoValue, try to use its underlying value directly if compatible.
 	if goVal, ok := obj.(*object.GoValue); ok {
 		if goVal.Value.Type().AssignableTo(targetType) {
@@ -1113,11 +1040,11 @@ func (e *Evaluator) objectToReflectValue(obj object.Object, targetType reflect.T
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			val.SetInt(o.Value)
 			return val, nil
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 			val.SetUint(uint64(o.Value))
 			return val, nil
 		case reflect.Float32, reflect.Float64:
-			val.SetFloat(float64(o.Value))
+			val.SetFloat(integerToFloat(o))
 			return val, nil
 		default:
 			return reflect.Value{}, fmt.Errorf("cannot convert integer to %s", targetType)
@@ -1128,9 +1055,21 @@ func (e *Evaluator) objectToReflectValue(obj object.Object, targetType reflect.T
 		case reflect.Float32, reflect.Float64:
 			val.SetFloat(o.Value)
 			return val, nil
+		case reflect.Complex64, reflect.Complex128:
+			val.SetComplex(complex(o.Value, 0))
+			return val, nil
 		default:
 			return reflect.Value{}, fmt.Errorf("cannot convert float to %s", targetType)
 		}
+	case *object.Complex:
+		val := reflect.New(targetType).Elem()
+		switch targetType.Kind() {
+		case reflect.Complex64, reflect.Complex128:
+			val.SetComplex(complex(o.Real, o.Imag))
+			return val, nil
+		default:
+			return reflect.Value{}, fmt.Errorf("cannot convert complex to %s", targetType)
+		}
 	case *object.String:
 		if targetType.Kind() != reflect.String {
 			return reflect.Value{}, fmt.Errorf("cannot convert string to %s", targetType)
@@ -1212,11 +1151,110 @@ func (e *Evaluator) objectToReflectValue(obj object.Object, targetType reflect.T
 	return reflect.Value{}, fmt.Errorf("unsupported conversion from %s to %s", obj.Type(), targetType)
 }
 
+// interfaceAdapter converts a struct defined in the script, or a pointer to it, to a Go value
+// implementing the interface targetType, with the adapter registered in ffibridge. The methods
+// of the adapter call the methods of the struct in the interpreter. It returns false if obj is
+// not a struct.
+func (e *Evaluator) interfaceAdapter(obj object.Object, targetType reflect.Type) (reflect.Value, bool, error) {
+	var instance *object.StructInstance
+	switch o := obj.(type) {
+	case *object.StructInstance:
+		instance = o
+	case *object.Pointer:
+		if o.Element == nil {
+			return reflect.Value{}, false, nil
+		}
+		si, ok := (*o.Element).(*object.StructInstance)
+		if !ok {
+			return reflect.Value{}, false, nil
+		}
+		instance = si
+	default:
+		return reflect.Value{}, false, nil
+	}
+
+	adapter, ok := ffibridge.LookupAdapter(targetType)
+	if !ok {
+		return reflect.Value{}, true, fmt.Errorf("no adapter is registered for %s to pass struct %s, see ffibridge.RegisterAdapter", targetType, instance.Def.Name.Name)
+	}
+	methods := make(ffibridge.Methods, targetType.NumMethod())
+	for i := 0; i < targetType.NumMethod(); i++ {
+		m := targetType.Method(i)
+		fn, recv := e.findMethodInStruct(obj, instance, m.Name)
+		if fn == nil {
+			return reflect.Value{}, true, fmt.Errorf("struct %s does not implement %s (missing method %s)", instance.Def.Name.Name, targetType, m.Name)
+		}
+		bound := &object.BoundMethod{Fn: fn, Receiver: recv}
+		methods[m.Name] = reflect.MakeFunc(m.Type, func(in []reflect.Value) []reflect.Value {
+			return e.callScriptMethod(bound, m.Type, in)
+		})
+	}
+	return reflect.ValueOf(adapter(methods)), true, nil
+}
+
+// scriptFailure is the panic value raised when a script method called from Go code through an
+// interface adapter fails. It is converted back to the error or the panic of the script when
+// the panic reaches the Go function called by the script, see goPanic.
+type scriptFailure struct {
+	obj object.Object
+}
+
+// callScriptMethod calls a script method from Go code, converting the arguments and the results
+// between the Go values and the minigo objects. The method is evaluated in the environment of the
+// Go function call in progress, so it must be called from the goroutine running the script.
+func (e *Evaluator) callScriptMethod(method *object.BoundMethod, funcType reflect.Type, in []reflect.Value) []reflect.Value {
+	args := make([]object.Object, len(in))
+	for i, v := range in {
+		args[i] = e.nativeToValue(v)
+	}
+	result := e.applyFunction(nil, method, args, e.BuiltinContext.Env, e.BuiltinContext.FScope)
+	if rv, ok := result.(*object.ReturnValue); ok {
+		result = rv.Value
+	}
+	switch result.(type) {
+	case *object.Error, *object.Panic:
+		panic(scriptFailure{obj: result})
+	}
+
+	var results []object.Object
+	switch funcType.NumOut() {
+	case 0:
+	case 1:
+		results = []object.Object{result}
+	default:
+		tuple, ok := result.(*object.Tuple)
+		if !ok || len(tuple.Elements) != funcType.NumOut() {
+			panic(scriptFailure{obj: e.newError(method.Fn.Name.Pos(), "method %s must return %d values", method.Fn.Name.Name, funcType.NumOut())})
+		}
+		results = tuple.Elements
+	}
+	out := make([]reflect.Value, funcType.NumOut())
+	for i, res := range results {
+		val, err := e.objectToReflectValue(res, funcType.Out(i))
+		if err != nil {
+			panic(scriptFailure{obj: e.newError(method.Fn.Name.Pos(), "result %d of method %s: %v", i+1, method.Fn.Name.Name, err)})
+		}
+		out[i] = val
+	}
+	return out
+}
+
 // objectToNativeGoValue converts a minigo object to its most natural Go counterpart.
 func (e *Evaluator) objectToNativeGoValue(obj object.Object) (any, error) {
 	switch o := obj.(type) {
 	case *object.Integer:
+		// The value has the Go type of its kind, e.g. uint8 for a byte.
+		return reflect.ValueOf(o.Value).Convert(integerTypes[o.GoKind()]).Interface(), nil
+	case *object.Float:
+		if o.Kind == reflect.Float32 {
+			return float32(o.Value), nil
+		}
 		return o.Value, nil
+	case *object.Complex:
+		if o.Kind == reflect.Complex64 {
+			return complex64(complex(o.Real, o.Imag)), nil
+		}
+		return complex(o.Real, o.Imag), nil
 	case *object.String:
 		return o.Value, nil
 	case *object.Boolean:
@@ -1329,6 +1367,9 @@ func (e *Evaluator) evalStringInfixExpression(node ast.Node, operator string, le
 
 	switch operator {
 	case "+":
+		if err := e.allocateString(node.Pos(), leftVal, rightVal); err != nil {
+			return err
+		}
 		return &object.String{Value: leftVal + rightVal}
 	case "==":
 		return e.nativeBoolToBooleanObject(leftVal == rightVal)
@@ -1345,6 +1386,13 @@ func (e *Evaluator) evalInfixExpression(node ast.Node, operator string, left, ri
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return e.evalIntegerInfixExpression(node, operator, left, right)
 
+	case (left.Type() == object.FLOAT_OBJ || left.Type() == object.INTEGER_OBJ) &&
+		(right.Type() == object.FLOAT_OBJ || right.Type() == object.INTEGER_OBJ):
+		return e.evalFloatInfixExpression(node, operator, left, right)
+
+	case (left.Type() == object.COMPLEX_OBJ && isNumber(right)) || (isNumber(left) && right.Type() == object.COMPLEX_OBJ):
+		return e.evalComplexInfixExpression(node, operator, left, right)
+
 	// Handle arithmetic with injected Go values (integers).
 	case (left.Type() == object.INTEGER_OBJ || left.Type() == object.GO_VALUE_OBJ) &&
 		(right.Type() == object.INTEGER_OBJ || right.Type() == object.GO_VALUE_OBJ):
@@ -1788,7 +1836,7 @@ func (e *Evaluator) evalRangeString(rs *ast.RangeStmt, str *object.String, env *
 				return e.newError(rs.Value.Pos(), "range value must be an identifier")
 			}
 			if valueIdent.Name != "_" {
-				loopEnv.Set(valueIdent.Name, &object.Integer{Value: int64(r)}) // rune is an alias for int32
+				loopEnv.Set(valueIdent.Name, object.NewInteger(reflect.Int32, int64(r)))
 			}
 		}
 
@@ -1978,17 +2026,24 @@ func (e *Evaluator) getZeroValueForResolvedType(typeObj object.Object) object.Ob
 				instance.Fields[name.Name] = object.NIL
 			}
 		}
+		e.setEmbeddedZeroValues(instance)
 		return instance
+	case *object.InstantiatedType:
+		if def, ok := rt.GenericDef.(*object.StructDefinition); ok {
+			instance := e.getZeroValueForResolvedType(def).(*object.StructInstance)
+			instance.TypeArgs = rt.TypeArgs
+			return instance
+		}
 	case *object.Type:
+		if kind, ok := numericKinds[rt.Name]; ok {
+			zero, _ := convertNumeric(kind, &object.Integer{Value: 0})
+			return zero
+		}
 		switch rt.Name {
-		case "int", "int64", "int32", "int16", "int8", "uint", "uint64", "uint32", "uint16", "uint8", "byte":
-			return &object.Integer{Value: 0}
 		case "string":
 			return &object.String{Value: ""}
 		case "bool":
 			return object.FALSE
-		case "float64", "float32":
-			return &object.Float{Value: 0.0}
 		}
 	}
 	// For any other type (pointers, interfaces, arrays, maps, etc.), the zero value is a typed nil.
@@ -2012,6 +2067,25 @@ func (e *Evaluator) getZeroValueForType(typeExpr ast.Expr, env *object.Environme
 	return e.getZeroValueForResolvedType(resolvedType)
 }
 
+// zeroStructInstance creates a zero-valued instance of a struct, with the zero values of its
+// fields. For an instantiation of a generic struct, the type parameters of the field types are
+// bound to the type arguments.
+func (e *Evaluator) zeroStructInstance(def *object.StructDefinition, typeArgs []object.Object, env *object.Environment, fscope *object.FileScope) *object.StructInstance {
+	instance := &object.StructInstance{Def: def, TypeArgs: typeArgs, Fields: make(map[string]object.Object)}
+	if len(typeArgs) > 0 {
+		env = object.NewEnclosedEnvironment(env)
+		e.bindTypeParams(env, def.TypeParams, typeArgs)
+	}
+	for _, field := range def.Fields {
+		zeroVal := e.getZeroValueForType(field.Type, env, fscope)
+		for _, name := range field.Names {
+			instance.Fields[name.Name] = zeroVal
+		}
+	}
+	e.setEmbeddedZeroValues(instance)
+	return instance
+}
+
 func (e *Evaluator) applyFunction(call *ast.CallExpr, fn object.Object, args []object.Object, env *object.Environment, fscope *object.FileScope) object.Object {
 	var function *object.Function
 	var typeArgs []object.Object
@@ -2044,6 +2118,22 @@ func (e *Evaluator) applyFunction(call *ast.CallExpr, fn object.Object, args []o
 		receiver = f.Receiver
 		// Generic methods on generic structs are handled by the receiver's type args,
 		// which are already bound in extendMethodEnv.
+	case *object.GoMethodValue:
+		// A method expression is called with the receiver as its first argument.
+		var pos token.Pos
+		if call != nil {
+			pos = call.Pos()
+		}
+		if len(args) == 0 {
+			return e.newError(pos, "not enough arguments in call to method expression %s: missing receiver", f.Inspect())
+		}
+		recv, errObj := e.bindMethodValueReceiver(pos, f, args[0])
+		if errObj != nil {
+			return errObj
+		}
+		function = f.Fn
+		receiver = recv
+		args = args[1:]
 	case *object.GoSourceFunction:
 		// Convert GoSourceFunction to a standard Function object for execution.
 		// The key is that we will use its DefEnv as the outer environment.
@@ -2125,37 +2215,13 @@ func (e *Evaluator) applyFunction(call *ast.CallExpr, fn object.Object, args []o
 	// Check type constraints before setting up the environment.
 	if function.TypeParams != nil {
 		// We need an environment to evaluate the constraint expressions.
-		// It should be based on the function's definition environment...
-		var baseConstraintEnv *object.Environment
-		if function.Env != nil {
-			baseConstraintEnv = function.Env
-		} else {
-			baseConstraintEnv = env // Fallback to the calling environment
-		}
-		// ... and it must also contain the type parameters themselves, since a
-		// constraint for one parameter might refer to another (e.g., S ~[]E).
-		constraintEnv := object.NewEnclosedEnvironment(baseConstraintEnv)
-		e.bindTypeParams(constraintEnv, function.TypeParams, typeArgs)
-
-		// This loop needs to be careful with multi-name fields.
-		typeArgIndex := 0
-		for _, param := range function.TypeParams.List {
-			for range param.Names {
-				if typeArgIndex < len(typeArgs) {
-					concreteType := typeArgs[typeArgIndex]
-					constraintExpr := param.Type
-
-					constraintObj := e.Eval(constraintExpr, constraintEnv, function.FScope)
-					if isError(constraintObj) {
-						return constraintObj
-					}
-
-					if err := e.checkTypeConstraint(param.Pos(), concreteType, constraintObj, constraintEnv, function.FScope); err != nil {
-						return err
-					}
-					typeArgIndex++
-				}
-			}
+		// It should be based on the function's definition environment.
+		constraintEnv := function.Env
+		if constraintEnv == nil {
+			constraintEnv = env // Fallback to the calling environment
+		}
+		if err := e.checkTypeArgs(function.TypeParams, typeArgs, constraintEnv, function.FScope); err != nil {
+			return err
 		}
 	}
 
@@ -2231,9 +2297,13 @@ func (e *Evaluator) applyFunction(call *ast.CallExpr, fn object.Object, args []o
 	}
 
 	// If a panic occurred but was recovered, the function's normal execution
-	// was aborted. It should return NIL, not continue processing the original
-	// panic object as a return value.
+	// was aborted. It returns its named results, which may be set by the deferred
+	// function that recovered (e.g. `err = fmt.Errorf(...)`), and NIL otherwise,
+	// not the original panic object.
 	if isPanic {
+		if frame.NamedReturns != nil {
+			return e.constructNamedReturnValue(function, frame.NamedReturns)
+		}
 		return object.NIL
 	}
 
@@ -2355,9 +2425,7 @@ func (e *Evaluator) extendMethodEnv(method *object.BoundMethod, args []object.Ob
 	env := object.NewEnclosedEnvironment(method.Fn.Env)
 
 	// Bind type parameters from the generic struct instance to the environment.
-	if instance, ok := method.Receiver.(*object.StructInstance); ok {
-		e.bindTypeParams(env, instance.Def.TypeParams, instance.TypeArgs)
-	}
+	e.bindReceiverTypeParams(env, method.Fn, method.Receiver)
 
 	// Bind the receiver variable (e.g., 's' in 'func (s MyType) ...')
 	if method.Fn.Recv != nil && len(method.Fn.Recv.List) == 1 {
@@ -2377,7 +2445,7 @@ func (e *Evaluator) extendMethodEnv(method *object.BoundMethod, args []object.Ob
 		// Bind non-variadic parameters
 		for i, param := range fn.Parameters.List[:len(fn.Parameters.List)-1] {
 			for _, paramName := range param.Names {
-				env.Set(paramName.Name, args[i])
+				env.Set(paramName.Name, paramValue(param, args[i]))
 			}
 		}
 
@@ -2396,7 +2464,7 @@ func (e *Evaluator) extendMethodEnv(method *object.BoundMethod, args []object.Ob
 			if len(param.Names) > 0 {
 				for _, paramName := range param.Names {
 					if argIndex < len(args) {
-						env.Set(paramName.Name, args[argIndex])
+						env.Set(paramName.Name, paramValue(param, args[argIndex]))
 						argIndex++
 					}
 				}
@@ -2439,7 +2507,7 @@ func (e *Evaluator) extendFunctionEnv(env *object.Environment, fn *object.Functi
 		for i, param := range fn.Parameters.List[:len(fn.Parameters.List)-1] {
 			// A single parameter can have multiple names (e.g., `a, b int`).
 			for _, paramName := range param.Names {
-				env.Set(paramName.Name, args[i])
+				env.Set(paramName.Name, paramValue(param, args[i]))
 			}
 		}
 
@@ -2460,7 +2528,7 @@ func (e *Evaluator) extendFunctionEnv(env *object.Environment, fn *object.Functi
 			if len(param.Names) > 0 {
 				for _, paramName := range param.Names {
 					if argIndex < len(args) {
-						env.Set(paramName.Name, args[argIndex])
+						env.Set(paramName.Name, paramValue(param, args[argIndex]))
 						argIndex++
 					}
 				}
@@ -2620,7 +2688,10 @@ func (e *Evaluator) evalBranchStmt(bs *ast.BranchStmt, env *object.Environment,
 func (e *Evaluator) EvalToplevel(decls []object.DeclWithScope, env *object.Environment) object.Object {
 	// Pass 1: Register all types and functions first.
 	// This pass does not evaluate any variable or constant initializers.
-	varDecls, constDecls := e.registerDecls(decls, env)
+	varDecls, constDecls, err := e.registerDecls(decls, env)
+	if err != nil {
+		return err
+	}
 
 	// Pass 2: Evaluate the initializers for variables and constants.
 	// Now that all functions and types are known, these initializers can refer to them.
@@ -2635,7 +2706,8 @@ func (e *Evaluator) EvalToplevel(decls []object.DeclWithScope, env *object.Envir
 // registerDecls is the first pass of the evaluation. It scans for all top-level
 // type and function declarations and adds them to the environment. It returns
 // slices of the variable and constant declarations to be processed in the second pass.
-func (e *Evaluator) registerDecls(decls []object.DeclWithScope, env *object.Environment) (varDecls, constDecls []object.DeclWithScope) {
+// An import which is not allowed by the sandbox is returned as an error.
+func (e *Evaluator) registerDecls(decls []object.DeclWithScope, env *object.Environment) (varDecls, constDecls []object.DeclWithScope, err *object.Error) {
 	for _, item := range decls {
 		switch d := item.Decl.(type) {
 		case *ast.FuncDecl:
@@ -2647,7 +2719,10 @@ func (e *Evaluator) registerDecls(decls []object.DeclWithScope, env *object.Envi
 			case token.TYPE, token.IMPORT:
 				// Register type and import definitions.
 				// The existing Eval logic for these GenDecls is sufficient.
-				e.Eval(d, env, item.Scope)
+				result := e.Eval(d, env, item.Scope)
+				if err, ok := result.(*object.Error); ok && err.Kind != "" {
+					return nil, nil, err
+				}
 			case token.VAR:
 				varDecls = append(varDecls, item)
 			case token.CONST:
@@ -2655,7 +2730,7 @@ func (e *Evaluator) registerDecls(decls []object.DeclWithScope, env *object.Envi
 			}
 		}
 	}
-	return varDecls, constDecls
+	return varDecls, constDecls, nil
 }
 
 // evalInitializers is the second pass of the evaluation. It evaluates the
@@ -2685,9 +2760,9 @@ func (e *Evaluator) evalTypeConversion(call *ast.CallExpr, typeObj object.Object
 		}
 		return e.newError(call.Pos(), "cannot convert non-nil value to pointer type %s", typeObj.Inspect())
 	case *object.ArrayType:
-		// Handle []byte("a string")
+		// Handle []byte("a string") and []rune("a string")
 		eltType, ok := t.ElementType.(*object.Type)
-		if !ok || eltType.Name != "byte" {
+		if !ok || (eltType.Name != "byte" && eltType.Name != "uint8" && eltType.Name != "rune" && eltType.Name != "int32") {
 			return e.newError(call.Pos(), "unsupported array type conversion to %s", typeObj.Inspect())
 		}
 
@@ -2696,46 +2771,61 @@ func (e *Evaluator) evalTypeConversion(call *ast.CallExpr, typeObj object.Object
 			return e.newError(call.Pos(), "cannot convert %s to type %s", arg.Type(), typeObj.Inspect())
 		}
 
-		bytes := []byte(str.Value)
-		elements := make([]object.Object, len(bytes))
-		for i, b := range bytes {
-			elements[i] = &object.Integer{Value: int64(b)}
+		var elements []object.Object
+		if eltType.Name == "rune" || eltType.Name == "int32" {
+			for _, r := range str.Value {
+				elements = append(elements, object.NewInteger(reflect.Int32, int64(r)))
+			}
+		} else {
+			bytes := []byte(str.Value)
+			elements = make([]object.Object, len(bytes))
+			for i, b := range bytes {
+				elements[i] = object.NewInteger(reflect.Uint8, int64(b))
+			}
 		}
 		return &object.Array{Elements: elements}
 
 	case *object.Type:
 		typeName := t.Name
-		switch typeName {
-		case "int", "uint", "uint64": // For now, treat uint as int.
-			switch input := arg.(type) {
-			case *object.Integer:
-				return input // It's already an integer, no-op.
-			case *object.Float:
-				return &object.Integer{Value: int64(input.Value)}
-			default:
+		if kind, ok := numericKinds[typeName]; ok {
+			converted, ok := convertNumeric(kind, arg)
+			if !ok {
 				return e.newError(call.Pos(), "cannot convert %s to type %s", arg.Type(), typeName)
 			}
+			return converted
+		}
+		switch typeName {
 		case "string":
-			// Handle string([]byte{...})
+			// Handle string([]byte{...}) and string([]rune{...})
 			if arr, ok := arg.(*object.Array); ok {
-				bytes := make([]byte, len(arr.Elements))
-				for i, el := range arr.Elements {
+				bytes := make([]byte, 0, len(arr.Elements))
+				for _, el := range arr.Elements {
 					integer, ok := el.(*object.Integer)
 					if !ok {
 						return e.newError(call.Pos(), "cannot convert non-integer element in array to byte for string conversion")
 					}
+					if integer.Kind == reflect.Int32 {
+						bytes = utf8.AppendRune(bytes, rune(integer.Value))
+						continue
+					}
 					if integer.Value < 0 || integer.Value > 255 {
 						return e.newError(call.Pos(), "byte value out of range for string conversion: %d", integer.Value)
 					}
-					bytes[i] = byte(integer.Value)
+					bytes = append(bytes, byte(integer.Value))
 				}
 				return &object.String{Value: string(bytes)}
 			}
 
-			// In a real implementation, you might convert integers, etc.
-			// For now, we only support string(string) which is a no-op.
-			if str, ok := arg.(*object.String); ok {
-				return str
+			switch v := arg.(type) {
+			case *object.String:
+				return v
+			case *object.Integer:
+				// string(r) is the UTF-8 encoding of the rune, including the unsigned values
+				// above math.MaxInt64.
+				if v.Value < 0 || v.Value > utf8.MaxRune {
+					return &object.String{Value: string(utf8.RuneError)}
+				}
+				return &object.String{Value: string(rune(v.Value))}
 			}
 			return e.newError(call.Pos(), "cannot convert %s to type string", arg.Type())
 		default:
@@ -2829,6 +2919,11 @@ func (e *Evaluator) evalProgram(program *ast.File, env *object.Environment, fsco
 }
 
 func (e *Evaluator) Eval(node ast.Node, env *object.Environment, fscope *object.FileScope) object.Object {
+	if e.sandbox.maxSteps > 0 && node != nil {
+		if err := e.step(node.Pos()); err != nil {
+			return err
+		}
+	}
 	switch n := node.(type) {
 	// Statements
 	case *ast.File:
@@ -2859,6 +2954,7 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment, fscope *object.
 				Results:    n.Type.Results,
 				Body:       n.Body,
 				Env:        env,
+				FScope:     fscope,
 			}
 			env.Set(n.Name.Name, fn)
 			return nil
@@ -2870,24 +2966,10 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment, fscope *object.
 		}
 		recvField := n.Recv.List[0]
 
-		var typeName string
-		switch recvType := recvField.Type.(type) {
-		case *ast.Ident:
-			typeName = recvType.Name
-		case *ast.StarExpr:
-			if ident, ok := recvType.X.(*ast.Ident); ok {
-				typeName = ident.Name
-			} else {
-				return e.newError(recvType.Pos(), "invalid receiver type: expected identifier")
-			}
-		case *ast.IndexExpr: // For generic receivers like `Box[T]`
-			if ident, ok := recvType.X.(*ast.Ident); ok {
-				typeName = ident.Name
-			} else {
-				return e.newError(recvType.Pos(), "invalid receiver type: expected identifier for generic type base")
-			}
-		default:
-			return e.newError(recvField.Type.Pos(), "unsupported receiver type: %T", recvField.Type)
+		// The receiver is `T`, `*T`, or for a generic type, `Box[T]`, `*Box[T]` or `Pair[K, V]`.
+		typeName, recvTypeParams, ok := receiverType(recvField.Type)
+		if !ok {
+			return e.newError(recvField.Type.Pos(), "invalid receiver type: expected identifier")
 		}
 
 		obj, ok := env.Get(typeName)
@@ -2905,6 +2987,9 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment, fscope *object.
 		if !ok {
 			return e.newError(n.Pos(), "receiver for method '%s' is not a struct type", n.Name.Name)
 		}
+		if want := countTypeParams(def.TypeParams); len(recvTypeParams) != want {
+			return e.newError(recvField.Type.Pos(), "receiver of method '%s' must have %d type parameters, got %d", n.Name.Name, want, len(recvTypeParams))
+		}
 
 		fn := &object.Function{
 			Name:       n.Name,
@@ -2914,6 +2999,7 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment, fscope *object.
 			Results:    n.Type.Results,
 			Body:       n.Body,
 			Env:        env, // The environment where the method is defined.
+			FScope:     fscope,
 		}
 
 		def.Methods[n.Name.Name] = fn
@@ -2961,6 +3047,7 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment, fscope *object.
 				if len(values) == 1 && isError(values[0]) {
 					return values[0]
 				}
+				values = resultValues(currentFrame.Fn, values)
 
 				// This is a simplified assignment; it assumes the number of return
 				// expressions matches the number of named return variables.
@@ -2995,12 +3082,18 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment, fscope *object.
 			if ret, ok := val.(*object.ReturnValue); ok {
 				return ret
 			}
+			if currentFrame != nil {
+				val = resultValues(currentFrame.Fn, []object.Object{val})[0]
+			}
 			return &object.ReturnValue{Value: val}
 		}
 		results := e.evalExpressions(n.Results, env, fscope, nil)
 		if len(results) > 0 && isError(results[0]) {
 			return results[0]
 		}
+		if currentFrame != nil {
+			results = resultValues(currentFrame.Fn, results)
+		}
 		return &object.ReturnValue{Value: &object.Tuple{Elements: results}}
 	case *ast.GenDecl:
 		return e.evalGenDecl(n, env, fscope)
@@ -3025,7 +3118,7 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment, fscope *object.
 		// Check if this is a generic type instantiation or a regular index access.
 		switch l := left.(type) {
 		case *object.StructDefinition, *object.Function:
-			return &object.InstantiatedType{GenericDef: left, TypeArgs: []object.Object{index}}
+			return e.instantiate(n.Pos(), left, []object.Object{index}, fscope)
 		case *object.TypeAlias:
 			// This is a generic alias instantiation, e.g., List[int]
 			return e.instantiateTypeAlias(n.Pos(), l, []object.Object{index})
@@ -3045,7 +3138,7 @@ func (e *Evaluator) Eval(node ast.Node, env *object.Environment, fscope *object.
 		}
 		switch l := left.(type) {
 		case *object.StructDefinition, *object.Function:
-			return &object.InstantiatedType{GenericDef: left, TypeArgs: indices}
+			return e.instantiate(n.Pos(), left, indices, fscope)
 		case *object.TypeAlias:
 			// This is a generic alias instantiation, e.g., Pair[int, string]
 			return e.instantiateTypeAlias(n.Pos(), l, indices)
@@ -3404,6 +3497,15 @@ func (e *Evaluator) evalGenDecl(n *ast.GenDecl, env *object.Environment, fscope
 				parts := strings.Split(path, "/")
 				alias = parts[len(parts)-1]
 			}
+			if loaded, ok := fscope.LocalImports[path]; ok {
+				// A script package loaded by the interpreter, named by its package clause.
+				path = loaded
+				if pkg, ok := e.packages[path]; ok && importSpec.Name == nil {
+					alias = pkg.Name
+				}
+			} else if err := e.checkImport(importSpec.Path.Pos(), path, true); err != nil {
+				return err
+			}
 
 			switch alias {
 			case "_":
@@ -3494,6 +3596,9 @@ func (e *Evaluator) evalGenDecl(n *ast.GenDecl, env *object.Environment, fscope
 					iotaEnv := object.NewEnclosedEnvironment(env)
 					iotaEnv.SetConstant("iota", &object.Integer{Value: int64(iotaValue)})
 					val = e.Eval(valueSpec.Values[i], iotaEnv, fscope)
+					if kind, ok := numericKindOfExpr(valueSpec.Type); ok && !isError(val) {
+						val = assignNumeric(kind, val)
+					}
 				} else if n.Tok == token.VAR {
 					// Handle `var x T` (no initial value)
 					if valueSpec.Type != nil {
@@ -3516,14 +3621,15 @@ func (e *Evaluator) evalGenDecl(n *ast.GenDecl, env *object.Environment, fscope
 							val = &object.GoValue{Value: ptr.Elem()}
 						case *object.StructDefinition:
 							// It's a minigo-defined struct, so initialize a zero-valued instance.
-							instance := &object.StructInstance{Def: rt, Fields: make(map[string]object.Object)}
-							for _, field := range rt.Fields {
-								zeroVal := e.getZeroValueForType(field.Type, env, fscope)
-								for _, name := range field.Names {
-									instance.Fields[name.Name] = zeroVal
-								}
+							val = e.zeroStructInstance(rt, nil, env, fscope)
+						case *object.InstantiatedType:
+							if def, ok := rt.GenericDef.(*object.StructDefinition); ok {
+								val = e.zeroStructInstance(def, rt.TypeArgs, env, fscope)
+							} else {
+								val = &object.TypedNil{TypeObject: resolvedType}
 							}
-							val = instance
+						case *object.Type:
+							val = e.getZeroValueForResolvedType(rt)
 						default:
 							// For other types (slices, maps, pointers, interfaces), the zero value is a typed nil.
 							val = &object.TypedNil{TypeObject: resolvedType}
@@ -3732,7 +3838,7 @@ func (e *Evaluator) evalStringIndexExpression(node ast.Node, str, index object.O
 		return e.newError(node.Pos(), "runtime error: index out of range [%d] with length %d", i, len(stringObject.Value))
 	}
 
-	return &object.Integer{Value: int64(stringObject.Value[i])}
+	return object.NewInteger(reflect.Uint8, int64(stringObject.Value[i]))
 }
 
 func (e *Evaluator) evalMapIndexExpression(node ast.Node, m, index object.Object) object.Object {
@@ -3759,23 +3865,24 @@ func (e *Evaluator) evalIncDecStmt(n *ast.IncDecStmt, env *object.Environment, f
 		return currentVal
 	}
 
-	// 2. Ensure the value is an integer.
-	integer, ok := currentVal.(*object.Integer)
-	if !ok {
+	// 2. Ensure the value is a number.
+	if !isNumber(currentVal) {
 		return e.newError(n.Pos(), "cannot %s non-integer type %s", n.Tok, currentVal.Type())
 	}
 
-	// 3. Calculate the new value.
-	var newVal int64
-	if n.Tok == token.INC {
-		newVal = integer.Value + 1
-	} else {
-		newVal = integer.Value - 1
+	// 3. Calculate the new value, wrapping around as the kind of the number does.
+	operator := "+"
+	if n.Tok == token.DEC {
+		operator = "-"
+	}
+	newVal := e.evalInfixExpression(n, operator, currentVal, &object.Integer{Value: 1})
+	if isError(newVal) {
+		return newVal
 	}
 
 	// 4. Assign the new value back to the variable.
 	// We can reuse the `assignValue` logic.
-	return e.assignValue(n.X, &object.Integer{Value: newVal}, env, fscope)
+	return e.assignValue(n.X, newVal, env, fscope)
 }
 
 func (e *Evaluator) evalAssignStmt(n *ast.AssignStmt, env *object.Environment, fscope *object.FileScope) object.Object {
@@ -3856,6 +3963,17 @@ func (e *Evaluator) evalSingleAssign(n *ast.AssignStmt, env *object.Environment,
 	}
 
 	lhs := n.Lhs[0]
+	if operator, ok := compoundOperators[n.Tok]; ok { // e.g. +=
+		current := e.Eval(lhs, env, fscope)
+		if isError(current) {
+			return current
+		}
+		val = e.evalInfixExpression(n, operator, current, val)
+		if isError(val) {
+			return val
+		}
+		return e.assignValue(lhs, val, env, fscope)
+	}
 	switch n.Tok {
 	case token.ASSIGN: // =
 		return e.assignValue(lhs, val, env, fscope)
@@ -3902,6 +4020,9 @@ func (e *Evaluator) assignValue(lhs ast.Expr, val object.Object, env *object.Env
 		if _, ok := env.GetConstant(lhsNode.Name); ok {
 			return e.newError(lhsNode.Pos(), "cannot assign to constant %s", lhsNode.Name)
 		}
+		if existing, ok := env.Get(lhsNode.Name); ok {
+			val = keepNumericKind(existing, val)
+		}
 		if !env.Assign(lhsNode.Name, val) {
 			return e.newError(lhsNode.Pos(), "undeclared variable: %s", lhsNode.Name)
 		}
@@ -3924,6 +4045,10 @@ func (e *Evaluator) assignValue(lhs ast.Expr, val object.Object, env *object.Env
 
 		switch base := underlying.(type) {
 		case *object.StructInstance:
+			// A promoted field is set on the embedded struct declaring it.
+			if owner := e.findFieldOwner(base, lhsNode.Sel.Name); owner != nil {
+				base = owner
+			}
 			base.Fields[lhsNode.Sel.Name] = val
 			return val
 		case *object.GoValue:
@@ -4044,63 +4169,170 @@ func (e *Evaluator) evalMultiAssign(n *ast.AssignStmt, env *object.Environment,
 // findFieldInStruct recursively searches for a field within a struct instance,
 // including its embedded structs. It returns the found object and a boolean indicating success.
 func (e *Evaluator) findFieldInStruct(instance *object.StructInstance, fieldName string) (object.Object, bool) {
+	owner := e.findFieldOwner(instance, fieldName)
+	if owner == nil {
+		return nil, false
+	}
+	return owner.Fields[fieldName], true
+}
+
+// findFieldOwner returns the struct instance holding the field: the instance itself, or the
+// embedded struct the field is promoted from. It returns nil if the field is not found.
+func (e *Evaluator) findFieldOwner(instance *object.StructInstance, fieldName string) *object.StructInstance {
 	// 1. Check direct fields first. This handles explicit fields and field shadowing.
-	if val, ok := instance.Fields[fieldName]; ok {
-		return val, true
+	if _, ok := instance.Fields[fieldName]; ok {
+		return instance
 	}
 
 	// 2. If not found, search in embedded structs in the order they are defined.
-	for _, fieldDef := range instance.Def.Fields {
-		// An embedded field in Go's AST has no names.
-		if len(fieldDef.Names) == 0 {
-			// The type of the embedded field, e.g., 'T' in 'struct { T }'.
-			// We need to resolve this type name to an object in the instance's fields.
-			var typeName string
-			switch t := fieldDef.Type.(type) {
-			case *ast.Ident:
-				typeName = t.Name
-			// Handle pointer to embedded type, e.g., struct { *T }
-			case *ast.StarExpr:
-				if ident, ok := t.X.(*ast.Ident); ok {
-					typeName = ident.Name
-				}
-			}
+	for _, typeName := range instance.Def.EmbeddedFields() {
+		// The embedded struct instance is stored in the parent's fields map under its type name.
+		embeddedObj, ok := instance.Fields[typeName]
+		if !ok {
+			// This can happen if an embedded field is nil.
+			continue
+		}
 
-			if typeName == "" {
-				continue // Unsupported embedded field type, e.g. struct { io.Writer }
+		// Automatically dereference if the embedded field is a pointer.
+		if ptr, ok := embeddedObj.(*object.Pointer); ok {
+			// If the pointer is nil, we can't search its fields.
+			if ptr.Element == nil || *ptr.Element == nil {
+				continue
 			}
+			embeddedObj = *ptr.Element
+		}
 
-			// The embedded struct instance is stored in the parent's fields map under its type name.
-			embeddedObj, ok := instance.Fields[typeName]
-			if !ok {
-				// This can happen if an embedded field is nil.
+		embeddedInstance, ok := embeddedObj.(*object.StructInstance)
+		if !ok {
+			// It's an embedded field but the value isn't a struct instance.
+			continue
+		}
+
+		// Recursively search in the embedded struct.
+		if owner := e.findFieldOwner(embeddedInstance, fieldName); owner != nil {
+			return owner // First match wins.
+		}
+	}
+
+	// 3. Field not found anywhere in the hierarchy.
+	return nil
+}
+
+// findMethodInStruct looks up a method of a struct, walking the embedding chain like Go: the
+// methods declared on the struct shadow the promoted ones, which are searched in the embedded
+// fields in the order they are defined. It returns the method and the receiver to bind it to,
+// which is the embedded value for a promoted method, or nil if the method is not found.
+//
+// The receiver is the instance itself or a pointer to it. An embedded struct value is passed
+// by pointer when the receiver is a pointer, so that its pointer methods can modify it.
+func (e *Evaluator) findMethodInStruct(receiver object.Object, instance *object.StructInstance, name string) (*object.Function, object.Object) {
+	if method, ok := instance.Def.Methods[name]; ok {
+		return method, receiver
+	}
+
+	_, viaPointer := receiver.(*object.Pointer)
+	for _, fieldName := range instance.Def.EmbeddedFields() {
+		switch embedded := instance.Fields[fieldName].(type) {
+		case *object.Pointer:
+			if embedded.Element == nil || *embedded.Element == nil {
 				continue
 			}
-
-			// Automatically dereference if the embedded field is a pointer.
-			if ptr, ok := embeddedObj.(*object.Pointer); ok {
-				// If the pointer is nil, we can't search its fields.
-				if ptr.Element == nil || *ptr.Element == nil {
-					continue
+			if embeddedInstance, ok := (*embedded.Element).(*object.StructInstance); ok {
+				if method, recv := e.findMethodInStruct(embedded, embeddedInstance, name); method != nil {
+					return method, recv
 				}
-				embeddedObj = *ptr.Element
 			}
+		case *object.StructInstance:
+			var recv object.Object = embedded
+			if viaPointer {
+				var elem object.Object = embedded
+				recv = &object.Pointer{Element: &elem}
+			}
+			if method, recv := e.findMethodInStruct(recv, embedded, name); method != nil {
+				return method, recv
+			}
+		case *object.TypedNil:
+			// A nil embedded pointer still has the methods of its type.
+			if def := embeddedPointerDef(embedded); def != nil {
+				if method, ok := def.Methods[name]; ok {
+					return method, embedded
+				}
+			}
+		}
+	}
+	return nil, nil
+}
 
-			embeddedInstance, ok := embeddedObj.(*object.StructInstance)
-			if !ok {
-				// It's an embedded field but the value isn't a struct instance.
+// methodSetOf returns the methods of a struct instance, including the ones promoted from
+// its embedded fields. The methods are resolved as findMethodInStruct does.
+func (e *Evaluator) methodSetOf(instance *object.StructInstance) map[string]*object.Function {
+	methods := make(map[string]*object.Function, len(instance.Def.Methods))
+	for _, fieldName := range instance.Def.EmbeddedFields() {
+		var promoted map[string]*object.Function
+		switch embedded := instance.Fields[fieldName].(type) {
+		case *object.Pointer:
+			if embedded.Element == nil || *embedded.Element == nil {
 				continue
 			}
-
-			// Recursively search in the embedded struct.
-			if val, found := e.findFieldInStruct(embeddedInstance, fieldName); found {
-				return val, true // First match wins.
+			if embeddedInstance, ok := (*embedded.Element).(*object.StructInstance); ok {
+				promoted = e.methodSetOf(embeddedInstance)
+			}
+		case *object.StructInstance:
+			promoted = e.methodSetOf(embedded)
+		case *object.TypedNil:
+			if def := embeddedPointerDef(embedded); def != nil {
+				promoted = def.Methods
+			}
+		}
+		for name, method := range promoted {
+			if _, ok := methods[name]; !ok {
+				methods[name] = method // First match wins.
 			}
 		}
 	}
+	for name, method := range instance.Def.Methods {
+		methods[name] = method
+	}
+	return methods
+}
 
-	// 3. Field not found anywhere in the hierarchy.
-	return nil, false
+// embeddedPointerDef returns the struct definition of a nil embedded pointer, or nil.
+func embeddedPointerDef(nilPtr *object.TypedNil) *object.StructDefinition {
+	ptrType, ok := nilPtr.TypeObject.(*object.PointerType)
+	if !ok {
+		return nil
+	}
+	def, _ := ptrType.ElementType.(*object.StructDefinition)
+	return def
+}
+
+// setEmbeddedZeroValues sets the embedded fields of a struct instance which are not set yet to
+// their zero values: a zero-valued instance for an embedded struct, and a typed nil for an
+// embedded pointer. The promoted fields and methods are reached through them.
+func (e *Evaluator) setEmbeddedZeroValues(instance *object.StructInstance) {
+	for _, field := range instance.Def.Fields {
+		name, ok := object.EmbeddedFieldName(field)
+		if !ok {
+			continue
+		}
+		if val, ok := instance.Fields[name]; ok && val != object.NIL {
+			continue
+		}
+		instance.Fields[name] = object.NIL
+		if instance.Def.Env == nil {
+			continue
+		}
+		// The type is resolved in the environment where the struct was defined.
+		typeObj := e.Eval(field.Type, instance.Def.Env, nil)
+		if isError(typeObj) {
+			continue // e.g. a type of an imported package, which needs the file scope.
+		}
+		resolved := e.resolveType(typeObj, instance.Def.Env, nil)
+		if isError(resolved) {
+			continue
+		}
+		instance.Fields[name] = e.getZeroValueForResolvedType(resolved)
+	}
 }
 
 // constantInfoToObject converts a goscan.ConstantInfo into a minigo object.
@@ -4418,6 +4650,20 @@ func (e *Evaluator) convertNativeToMiniGo(
 	}
 }
 
+// goPanic converts a panic of a Go function called from the script into a script-level panic,
+// which can be recovered by the script, keeping the Go stack trace for debugging. The failure of
+// a script method called back by the Go function is returned as is.
+// It must be called by the deferred function recovering the panic.
+func goPanic(r any) object.Object {
+	if f, ok := r.(scriptFailure); ok {
+		return f.obj
+	}
+	return &object.Panic{
+		Value:   &object.String{Value: fmt.Sprintf("%v", r)},
+		GoStack: string(debug.Stack()),
+	}
+}
+
 // WrapGoFunction is a public method to wrap a native Go function into a minigo object.
 func (e *Evaluator) WrapGoFunction(pos token.Pos, funcVal reflect.Value) object.Object {
 	funcType := funcVal.Type()
@@ -4425,8 +4671,7 @@ func (e *Evaluator) WrapGoFunction(pos token.Pos, funcVal reflect.Value) object.
 		Fn: func(ctx *object.BuiltinContext, callPos token.Pos, args ...object.Object) (ret object.Object) {
 			defer func() {
 				if r := recover(); r != nil {
-					panicValue := &object.String{Value: fmt.Sprintf("%v", r)}
-					ret = &object.Panic{Value: panicValue}
+					ret = goPanic(r)
 				}
 			}()
 
@@ -4452,7 +4697,7 @@ func (e *Evaluator) WrapGoFunction(pos token.Pos, funcVal reflect.Value) object.
 					targetType = funcType.In(i)
 				}
 
-				if ptr, isPtr := arg.(*object.Pointer); isPtr && targetType.Kind() == reflect.Interface {
+				if ptr, isPtr := arg.(*object.Pointer); isPtr && targetType.Kind() == reflect.Interface && targetType.NumMethod() == 0 {
 					var nativePtr any
 					underlying := *ptr.Element
 					if _, ok := underlying.(*object.StructInstance); ok {
@@ -4538,9 +4783,17 @@ func (e *Evaluator) WrapGoFunction(pos token.Pos, funcVal reflect.Value) object.
 
 // evalMethodCall handles resolving and binding a method to a receiver.
 // The receiver can be a struct instance or a pointer to a struct instance.
+// Methods promoted from embedded fields are bound to the embedded value.
 func (e *Evaluator) evalMethodCall(n *ast.SelectorExpr, receiver object.Object, def *object.StructDefinition) object.Object {
-	method, ok := def.Methods[n.Sel.Name]
+	instance, ok := receiver.(*object.StructInstance)
+	if ptr, isPointer := receiver.(*object.Pointer); isPointer {
+		instance, ok = (*ptr.Element).(*object.StructInstance)
+	}
 	if !ok {
+		return nil
+	}
+	method, receiver := e.findMethodInStruct(receiver, instance, n.Sel.Name)
+	if method == nil {
 		return nil // Not a method, signal to caller to check for fields.
 	}
 
@@ -4552,6 +4805,14 @@ func (e *Evaluator) evalMethodCall(n *ast.SelectorExpr, receiver object.Object,
 		}
 	}
 
+	// A nil embedded pointer, which only has the methods of its own type.
+	if typedNil, isNil := receiver.(*object.TypedNil); isNil {
+		if !isPointerReceiver {
+			return e.newError(n.Pos(), "nil pointer dereference")
+		}
+		return &object.BoundMethod{Fn: method, Receiver: typedNil}
+	}
+
 	// Check if the receiver is compatible.
 	if isPointerReceiver {
 		if _, isPointer := receiver.(*object.Pointer); !isPointer {
@@ -4572,8 +4833,59 @@ func (e *Evaluator) evalMethodCall(n *ast.SelectorExpr, receiver object.Object,
 
 	// Receiver is a value, and method wants a value. This is correct.
 	// We pass a copy to prevent the method from modifying the original struct.
-	instance := receiver.(*object.StructInstance)
-	return &object.BoundMethod{Fn: method, Receiver: instance.Copy()}
+	return &object.BoundMethod{Fn: method, Receiver: receiver.(*object.StructInstance).Copy()}
+}
+
+// evalMethodExpression resolves a method expression such as `T.Method` or `(*T).Method`.
+// The result is an unbound method value whose first argument is the receiver.
+func (e *Evaluator) evalMethodExpression(n *ast.SelectorExpr, def *object.StructDefinition, viaPointer bool) object.Object {
+	method, ok := def.Methods[n.Sel.Name]
+	if !ok {
+		return e.newError(n.Pos(), "undefined method %s for type %s", n.Sel.Name, def.Name.Name)
+	}
+	if !viaPointer && method.Recv != nil && len(method.Recv.List) > 0 {
+		if _, ok := method.Recv.List[0].Type.(*ast.StarExpr); ok {
+			return e.newError(n.Pos(), "invalid method expression %s.%s (needs pointer receiver (*%s).%s)", def.Name.Name, n.Sel.Name, def.Name.Name, n.Sel.Name)
+		}
+	}
+	return &object.GoMethodValue{Fn: method, RecvDef: def}
+}
+
+// bindMethodValueReceiver adapts the first argument of a method value call to the
+// receiver kind the method expects, dereferencing or copying as a Go call would.
+func (e *Evaluator) bindMethodValueReceiver(pos token.Pos, mv *object.GoMethodValue, recv object.Object) (object.Object, *object.Error) {
+	isPointerReceiver := false
+	if mv.Fn.Recv != nil && len(mv.Fn.Recv.List) > 0 {
+		if _, ok := mv.Fn.Recv.List[0].Type.(*ast.StarExpr); ok {
+			isPointerReceiver = true
+		}
+	}
+
+	switch r := recv.(type) {
+	case *object.Pointer:
+		if isPointerReceiver {
+			return r, nil
+		}
+		if r.Element == nil || *r.Element == nil {
+			return nil, e.newError(pos, "nil pointer dereference")
+		}
+		if instance, ok := (*r.Element).(*object.StructInstance); ok {
+			return instance.Copy(), nil
+		}
+		return *r.Element, nil
+	case *object.StructInstance:
+		if isPointerReceiver {
+			return nil, e.newError(pos, "cannot use value of type %s as pointer receiver for method %s", r.Def.Name.Name, mv.Fn.Name.Name)
+		}
+		return r.Copy(), nil
+	case *object.TypedNil:
+		if isPointerReceiver {
+			return r, nil
+		}
+		return nil, e.newError(pos, "nil pointer dereference")
+	default:
+		return recv, nil
+	}
 }
 
 func (e *Evaluator) evalSelectorExpr(n *ast.SelectorExpr, env *object.Environment, fscope *object.FileScope) object.Object {
@@ -4647,7 +4959,7 @@ func (e *Evaluator) evalSelectorExpr(n *ast.SelectorExpr, env *object.Environmen
 		if !ok {
 			return e.newError(n.Pos(), "internal error: TypedNil does not contain a pointer type")
 		}
-		structDef, ok := ptrType.ElementType.(*object.StructDefinition)
+		structDef, ok := genericStructDef(ptrType.ElementType)
 		if !ok {
 			return e.newError(n.Pos(), "cannot get method from nil pointer to non-struct type %s", ptrType.ElementType.Inspect())
 		}
@@ -4657,6 +4969,26 @@ func (e *Evaluator) evalSelectorExpr(n *ast.SelectorExpr, env *object.Environmen
 		}
 		return &object.GoMethodValue{Fn: method, RecvDef: structDef}
 
+	case *object.StructDefinition:
+		// A method expression, e.g. `MyType.Method`.
+		return e.evalMethodExpression(n, l, false)
+
+	case *object.InstantiatedType:
+		// A method expression on an instantiated generic type, e.g. `Box[int].Get`.
+		structDef, ok := l.GenericDef.(*object.StructDefinition)
+		if !ok {
+			return e.newError(n.Pos(), "cannot get method from non-struct type %s", l.Inspect())
+		}
+		return e.evalMethodExpression(n, structDef, false)
+
+	case *object.PointerType:
+		// A method expression on a pointer type, e.g. `(*MyType).Method`.
+		structDef, ok := genericStructDef(l.ElementType)
+		if !ok {
+			return e.newError(n.Pos(), "cannot get method from pointer to non-struct type %s", l.ElementType.Inspect())
+		}
+		return e.evalMethodExpression(n, structDef, true)
+
 	case *object.Pointer:
 		if l.Element == nil || *l.Element == nil {
 			return e.newError(n.Pos(), "nil pointer dereference")
@@ -4697,6 +5029,10 @@ func (e *Evaluator) evalSelectorExpr(n *ast.SelectorExpr, env *object.Environmen
 // findSymbolInPackage resolves a symbol within a given package. It handles caching,
 // consulting the symbol registry, and triggering on-demand scanning.
 func (e *Evaluator) findSymbolInPackage(pkg *object.Package, symbolName *ast.Ident, pos token.Pos) object.Object {
+	if err := e.checkImport(pos, pkg.Path, false); err != nil {
+		return err
+	}
+
 	// 1. Check member cache first.
 	if member, ok := pkg.Members[symbolName.Name]; ok {
 		return member
@@ -4705,8 +5041,10 @@ func (e *Evaluator) findSymbolInPackage(pkg *object.Package, symbolName *ast.Ide
 	// 2. Check the registry for pre-registered symbols (values and types).
 	if symbol, ok := e.registry.Lookup(pkg.Path, symbolName.Name); ok {
 		var member object.Object
-		val := reflect.ValueOf(symbol)
-		if val.Kind() == reflect.Func {
+		if obj, ok := symbol.(object.Object); ok {
+			// Already a minigo object, e.g. a *object.Builtin generated by `minigo gen-bindings`.
+			member = obj
+		} else if val := reflect.ValueOf(symbol); val.Kind() == reflect.Func {
 			member = e.WrapGoFunction(pos, val)
 		} else {
 			member = &object.GoValue{Value: val}
@@ -4734,14 +5072,9 @@ func (e *Evaluator) findSymbolInPackage(pkg *object.Package, symbolName *ast.Ide
 
 		// Create a new, unified FileScope for the entire package from all its files.
 		if cumulativePkgInfo != nil && len(cumulativePkgInfo.AstFiles) > 0 {
-			var representativeAST *ast.File
-			for _, astFile := range cumulativePkgInfo.AstFiles {
-				if representativeAST == nil {
-					representativeAST = astFile
-				}
-			}
-			unifiedFScope := object.NewFileScope(representativeAST)
-			for _, astFile := range cumulativePkgInfo.AstFiles {
+			astFiles := cumulativePkgInfo.SortedAstFiles()
+			unifiedFScope := object.NewFileScope(astFiles[0])
+			for _, astFile := range astFiles {
 				for _, importSpec := range astFile.Imports {
 					path, err := strconv.Unquote(importSpec.Path.Value)
 					if err != nil {
@@ -4834,7 +5167,7 @@ func (e *Evaluator) evalGoValueSelectorExpr(node ast.Node, goVal *object.GoValue
 			Fn: func(ctx *object.BuiltinContext, callPos token.Pos, args ...object.Object) (ret object.Object) {
 				defer func() {
 					if r := recover(); r != nil {
-						ret = ctx.NewError(callPos, "panic in Go method call '%s': %v", sel, r)
+						ret = goPanic(r)
 					}
 				}()
 
@@ -4936,6 +5269,9 @@ func (e *Evaluator) evalCompositeLit(n *ast.CompositeLit, env *object.Environmen
 
 // evalCompositeLitWithType evaluates a composite literal against a given, already-evaluated type object.
 func (e *Evaluator) evalCompositeLitWithType(n *ast.CompositeLit, typeObj object.Object, env *object.Environment, fscope *object.FileScope) object.Object {
+	if err := e.allocate(n.Pos(), 1+len(n.Elts)); err != nil {
+		return err
+	}
 	// Now, resolve the evaluated type object. This handles non-generic aliases.
 	// For generic types, `typeObj` will already be the instantiated type object
 	// (e.g., a StructDefinition or an ArrayType from `instantiateTypeAlias`).
@@ -4995,11 +5331,11 @@ func (e *Evaluator) checkImplements(pos token.Pos, concrete object.Object, iface
 	// This handles both value receivers (StructInstance) and pointer receivers (*Pointer to StructInstance).
 	switch c := concrete.(type) {
 	case *object.StructInstance:
-		concreteMethods = c.Def.Methods
+		concreteMethods = e.methodSetOf(c)
 		concreteTypeName = c.Def.Name.Name
 	case *object.Pointer:
 		if s, ok := (*c.Element).(*object.StructInstance); ok {
-			concreteMethods = s.Def.Methods
+			concreteMethods = e.methodSetOf(s)
 			concreteTypeName = s.Def.Name.Name
 		} else {
 			// A pointer to a non-struct cannot have methods.
@@ -5104,6 +5440,7 @@ func (e *Evaluator) evalStructLiteral(n *ast.CompositeLit, def *object.StructDef
 			return e.newError(elt.Pos(), "unsupported literal element in struct literal: %T", elt)
 		}
 	}
+	e.setEmbeddedZeroValues(instance)
 	return instance
 }
 
@@ -5248,7 +5585,8 @@ func (e *Evaluator) evalBasicLit(n *ast.BasicLit) object.Object {
 		}
 		// A char literal in Go is a rune, which is an alias for int32.
 		// We represent it as our standard Integer object.
-		return &object.Integer{Value: int64(rune(s[0]))}
+		r, _ := utf8.DecodeRuneInString(s)
+		return &object.Integer{Value: int64(r)}
 	default:
 		return e.newError(n.Pos(), "unsupported literal type: %s", n.Kind)
 	}
@@ -899,6 +899,77 @@ func TestGenericStructs(t *testing.T) {
 			`,
 			"world",
 		},
+		{
+			`
+			type Box[T any] struct { Value T }
+			func (b *Box[T]) Set(v T) { b.Value = v }
+			func (b *Box[T]) Get() T { return b.Value }
+			func main() {
+				b := &Box[int]{}
+				b.Set(7)
+				return b.Get()
+			}
+			`,
+			int64(7),
+		},
+		{
+			`
+			type Box[T any] struct { Value T }
+			func (b Box[U]) Zero() U { var z U; return z }
+			func main() {
+				b := Box[string]{Value: "x"}
+				return b.Zero() + "!"
+			}
+			`,
+			"!",
+		},
+		{
+			`
+			type Box[T any] struct { Value T }
+			func (b Box[T]) Get() T { return b.Value }
+			func main() {
+				var b Box[int]
+				return b.Get() + 1
+			}
+			`,
+			int64(1),
+		},
+		{
+			`
+			type Box[T any] struct { Value T }
+			func (b Box[T]) Get() T { return b.Value }
+			func main() {
+				get := Box[int].Get
+				return get(Box[int]{Value: 5})
+			}
+			`,
+			int64(5),
+		},
+		{
+			`
+			type Pair[K comparable, V any] struct { Key K; Value V }
+			func (p Pair[K, V]) Swap() Pair[V, K] { return Pair[V, K]{Key: p.Value, Value: p.Key} }
+			func main() {
+				p := Pair[string, int]{Key: "a", Value: 1}
+				return p.Swap().Key
+			}
+			`,
+			int64(1),
+		},
+		{
+			`
+			type List[T any] struct { items []T }
+			func NewList[T any](first T) *List[T] { return &List[T]{items: []T{first}} }
+			func (l *List[T]) Push(v T) { l.items = append(l.items, v) }
+			func (l *List[T]) Len() int { return len(l.items) }
+			func main() {
+				l := NewList("a")
+				l.Push("b")
+				return l.Len()
+			}
+			`,
+			int64(2),
+		},
 	}
 
 	for i, tt := range tests {
@@ -916,6 +987,93 @@ func TestGenericStructs(t *testing.T) {
 	}
 }
 
+func TestGenericStructs_Errors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`
+			type Number interface { ~int | ~float64 }
+			type Box[T Number] struct { Value T }
+			func main() {
+				b := Box[string]{Value: "x"}
+				return b.Value
+			}
+			`,
+			"type string does not satisfy interface constraint Number",
+		},
+		{
+			`
+			type Pair[K comparable, V any] struct { Key K; Value V }
+			func main() {
+				p := Pair[string]{Key: "a"}
+				return p.Key
+			}
+			`,
+			"wrong number of type arguments for Pair: got 1, want 2",
+		},
+		{
+			`
+			type Box[T any] struct { Value T }
+			func (b Box) Get() int { return 0 }
+			func main() {
+				return 0
+			}
+			`,
+			"receiver of method 'Get' must have 1 type parameters, got 0",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test-%d", i), func(t *testing.T) {
+			evaluated := testEvalFile(t, tt.input)
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Fatalf("expected error, got %T (%+v)", evaluated, evaluated)
+			}
+			if !strings.Contains(errObj.Message, tt.expected) {
+				t.Errorf("wrong error message. expected to contain %q, got=%q", tt.expected, errObj.Message)
+			}
+		})
+	}
+}
+
+func TestGenericStructs_InstantiationCache(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", "package main\ntype Box[T any] struct { Value T }\n", 0)
+	if err != nil {
+		t.Fatalf("ParseFile error: %v", err)
+	}
+	env := object.NewEnvironment()
+	fscope := object.NewFileScope(file)
+	eval := New(Config{Fset: fset, Registry: object.NewSymbolRegistry(), Packages: make(map[string]*object.Package)})
+	for _, decl := range file.Decls {
+		if result := eval.Eval(decl, env, fscope); isError(result) {
+			t.Fatalf("unexpected error: %s", result.Inspect())
+		}
+	}
+
+	instantiate := func(src string) object.Object {
+		t.Helper()
+		expr, err := parser.ParseExpr(src)
+		if err != nil {
+			t.Fatalf("ParseExpr error: %v", err)
+		}
+		result := eval.Eval(expr, env, fscope)
+		if _, ok := result.(*object.InstantiatedType); !ok {
+			t.Fatalf("expected InstantiatedType for %s, got %T (%+v)", src, result, result)
+		}
+		return result
+	}
+	if a, b := instantiate("Box[int]"), instantiate("Box[int]"); a != b {
+		t.Errorf("Box[int] is instantiated twice: %p != %p", a, b)
+	}
+	if a, b := instantiate("Box[int]"), instantiate("Box[string]"); a == b {
+		t.Errorf("Box[int] and Box[string] share the instantiation")
+	}
+}
+
 func TestMethodCalls(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -0,0 +1,192 @@
+package evaluator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/podhmo/go-scan/minigo/object"
+)
+
+// instantiate returns the instantiation of a generic struct type or function with the type
+// arguments, e.g. Box[int], checking them against the constraints of the type parameters like
+// a call of a generic function does. The instantiations are cached, so that the same type
+// arguments give the same object.
+func (e *Evaluator) instantiate(pos token.Pos, def object.Object, typeArgs []object.Object, fscope *object.FileScope) object.Object {
+	var name string
+	var typeParams *ast.FieldList
+	var defEnv *object.Environment
+	switch d := def.(type) {
+	case *object.StructDefinition:
+		name, typeParams, defEnv = d.Name.Name, d.TypeParams, d.Env
+	case *object.Function:
+		typeParams, defEnv = d.TypeParams, d.Env
+		if d.Name != nil {
+			name = d.Name.Name
+		}
+		if d.FScope != nil {
+			fscope = d.FScope
+		}
+	}
+	if typeParams == nil || len(typeParams.List) == 0 {
+		return &object.InstantiatedType{GenericDef: def, TypeArgs: typeArgs}
+	}
+
+	key := typeArgsKey(typeArgs)
+	if cached, ok := e.instantiations[def][key]; ok {
+		return cached
+	}
+	if want := countTypeParams(typeParams); len(typeArgs) != want {
+		return e.newError(pos, "wrong number of type arguments for %s: got %d, want %d", name, len(typeArgs), want)
+	}
+	if defEnv == nil {
+		defEnv = object.NewEnvironment()
+	}
+	if err := e.checkTypeArgs(typeParams, typeArgs, defEnv, fscope); err != nil {
+		return err
+	}
+
+	inst := &object.InstantiatedType{GenericDef: def, TypeArgs: typeArgs}
+	if e.instantiations == nil {
+		e.instantiations = make(map[object.Object]map[string]*object.InstantiatedType)
+	}
+	if e.instantiations[def] == nil {
+		e.instantiations[def] = make(map[string]*object.InstantiatedType)
+	}
+	e.instantiations[def][key] = inst
+	return inst
+}
+
+// checkTypeArgs checks the type arguments against the constraints of the type parameters,
+// which are evaluated in an environment enclosing env, where the type parameters are bound,
+// since a constraint for one parameter might refer to another (e.g., S ~[]E).
+func (e *Evaluator) checkTypeArgs(typeParams *ast.FieldList, typeArgs []object.Object, env *object.Environment, fscope *object.FileScope) *object.Error {
+	constraintEnv := object.NewEnclosedEnvironment(env)
+	e.bindTypeParams(constraintEnv, typeParams, typeArgs)
+
+	// This loop needs to be careful with multi-name fields.
+	typeArgIndex := 0
+	for _, param := range typeParams.List {
+		for range param.Names {
+			if typeArgIndex >= len(typeArgs) {
+				return nil
+			}
+			constraintObj := e.Eval(param.Type, constraintEnv, fscope)
+			if err, ok := constraintObj.(*object.Error); ok {
+				return err
+			}
+			if err := e.checkTypeConstraint(param.Pos(), typeArgs[typeArgIndex], constraintObj, constraintEnv, fscope); err != nil {
+				return err
+			}
+			typeArgIndex++
+		}
+	}
+	return nil
+}
+
+// countTypeParams returns the number of the type parameters, e.g. 2 for [K comparable, V any].
+func countTypeParams(typeParams *ast.FieldList) int {
+	if typeParams == nil {
+		return 0
+	}
+	n := 0
+	for _, param := range typeParams.List {
+		n += len(param.Names)
+	}
+	return n
+}
+
+// typeArgsKey returns the key of the type arguments of an instantiation. The type definitions
+// are identified by themselves, as the types of different scopes may have the same name.
+func typeArgsKey(typeArgs []object.Object) string {
+	keys := make([]string, len(typeArgs))
+	for i, arg := range typeArgs {
+		switch arg := arg.(type) {
+		case *object.StructDefinition, *object.InterfaceDefinition:
+			keys[i] = fmt.Sprintf("%p", arg)
+		default:
+			keys[i] = fmt.Sprintf("%s:%s", arg.Type(), arg.Inspect())
+		}
+	}
+	return strings.Join(keys, ", ")
+}
+
+// receiverType returns the name of the receiver type of a method declaration, and the names of
+// its type parameters for a generic type, e.g. "Box" and [T] for `func (b *Box[T]) Get() T`.
+func receiverType(expr ast.Expr) (string, []*ast.Ident, bool) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	var indices []ast.Expr
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr, indices = t.X, []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		expr, indices = t.X, t.Indices
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return "", nil, false
+	}
+	names := make([]*ast.Ident, len(indices))
+	for i, index := range indices {
+		name, ok := index.(*ast.Ident)
+		if !ok {
+			return "", nil, false
+		}
+		names[i] = name
+	}
+	return ident.Name, names, true
+}
+
+// receiverTypeArgs returns the type arguments of the instance of a generic struct type a
+// method is called on: a struct instance, a pointer to it, or a typed nil pointer.
+func receiverTypeArgs(receiver object.Object) []object.Object {
+	switch r := receiver.(type) {
+	case *object.StructInstance:
+		return r.TypeArgs
+	case *object.Pointer:
+		if r.Element != nil {
+			if instance, ok := (*r.Element).(*object.StructInstance); ok {
+				return instance.TypeArgs
+			}
+		}
+	case *object.TypedNil:
+		if ptrType, ok := r.TypeObject.(*object.PointerType); ok {
+			if inst, ok := ptrType.ElementType.(*object.InstantiatedType); ok {
+				return inst.TypeArgs
+			}
+		}
+	}
+	return nil
+}
+
+// bindReceiverTypeParams binds the type parameters of the receiver of a method of a generic
+// struct type to the type arguments of the receiver, by the names of the method declaration,
+// which may differ from the ones of the type declaration, e.g. `func (b Box[U]) Get() U`.
+func (e *Evaluator) bindReceiverTypeParams(env *object.Environment, fn *object.Function, receiver object.Object) {
+	typeArgs := receiverTypeArgs(receiver)
+	if len(typeArgs) == 0 || fn.Recv == nil || len(fn.Recv.List) != 1 {
+		return
+	}
+	_, names, ok := receiverType(fn.Recv.List[0].Type)
+	if !ok {
+		return
+	}
+	for i, name := range names {
+		if i < len(typeArgs) && name.Name != "_" {
+			env.SetType(name.Name, typeArgs[i])
+		}
+	}
+}
+
+// genericStructDef returns the struct definition of a type, which may be an instantiation of a
+// generic struct type.
+func genericStructDef(typeObj object.Object) (*object.StructDefinition, bool) {
+	if inst, ok := typeObj.(*object.InstantiatedType); ok {
+		typeObj = inst.GenericDef
+	}
+	def, ok := typeObj.(*object.StructDefinition)
+	return def, ok
+}
@@ -0,0 +1,425 @@
+package evaluator
+
+import (
+	"go/ast"
+	"go/token"
+	"math"
+	"reflect"
+
+	"github.com/podhmo/go-scan/minigo/object"
+)
+
+// numericKinds are the kinds of the predeclared numeric types.
+var numericKinds = map[string]reflect.Kind{
+	"int": reflect.Int, "int8": reflect.Int8, "int16": reflect.Int16, "int32": reflect.Int32, "int64": reflect.Int64,
+	"uint": reflect.Uint, "uint8": reflect.Uint8, "uint16": reflect.Uint16, "uint32": reflect.Uint32, "uint64": reflect.Uint64,
+	"uintptr": reflect.Uintptr, "byte": reflect.Uint8, "rune": reflect.Int32,
+	"float32": reflect.Float32, "float64": reflect.Float64,
+	"complex64": reflect.Complex64, "complex128": reflect.Complex128,
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Int64) || isUnsignedKind(k)
+}
+
+func isUnsignedKind(k reflect.Kind) bool { return k >= reflect.Uint && k <= reflect.Uintptr }
+
+func isFloatKind(k reflect.Kind) bool { return k == reflect.Float32 || k == reflect.Float64 }
+
+func isComplexKind(k reflect.Kind) bool { return k == reflect.Complex64 || k == reflect.Complex128 }
+
+// numericKindOf returns the kind of a type object denoting a predeclared numeric type.
+func numericKindOf(typeObj object.Object) (reflect.Kind, bool) {
+	t, ok := typeObj.(*object.Type)
+	if !ok {
+		return reflect.Invalid, false
+	}
+	kind, ok := numericKinds[t.Name]
+	return kind, ok
+}
+
+// numericKindOfExpr returns the kind of a type expression naming a predeclared numeric type,
+// e.g. the type of a parameter, without evaluating it.
+func numericKindOfExpr(expr ast.Expr) (reflect.Kind, bool) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return reflect.Invalid, false
+	}
+	kind, ok := numericKinds[ident.Name]
+	return kind, ok
+}
+
+// convertNumeric converts a number to the kind as the conversion `T(x)` does: integers wrap
+// around, floats are truncated towards zero, and float32 and complex64 values are rounded.
+// It reports false if arg is not a number convertible to the kind.
+func convertNumeric(kind reflect.Kind, arg object.Object) (object.Object, bool) {
+	if goVal, ok := arg.(*object.GoValue); ok {
+		if arg, ok = goValueToNumber(goVal.Value); !ok {
+			return nil, false
+		}
+	}
+	switch {
+	case isIntegerKind(kind):
+		switch v := arg.(type) {
+		case *object.Integer:
+			return object.NewInteger(kind, v.Value), true
+		case *object.Float:
+			if math.IsNaN(v.Value) || math.IsInf(v.Value, 0) {
+				return nil, false
+			}
+			if v.Value >= math.MaxInt64 {
+				return object.NewInteger(kind, int64(uint64(v.Value))), true
+			}
+			return object.NewInteger(kind, int64(v.Value)), true
+		}
+	case isFloatKind(kind):
+		switch v := arg.(type) {
+		case *object.Integer:
+			return object.NewFloat(kind, integerToFloat(v)), true
+		case *object.Float:
+			return object.NewFloat(kind, v.Value), true
+		}
+	case isComplexKind(kind):
+		switch v := arg.(type) {
+		case *object.Integer:
+			return object.NewComplex(kind, integerToFloat(v), 0), true
+		case *object.Float:
+			return object.NewComplex(kind, v.Value, 0), true
+		case *object.Complex:
+			return object.NewComplex(kind, v.Real, v.Imag), true
+		}
+	}
+	return nil, false
+}
+
+// goValueToNumber converts a Go number to a minigo one, keeping its kind.
+func goValueToNumber(v reflect.Value) (object.Object, bool) {
+	switch {
+	case v.CanInt():
+		return object.NewInteger(v.Kind(), v.Int()), true
+	case v.CanUint():
+		return object.NewInteger(v.Kind(), int64(v.Uint())), true
+	case v.CanFloat():
+		return object.NewFloat(v.Kind(), v.Float()), true
+	case v.CanComplex():
+		c := v.Complex()
+		return object.NewComplex(v.Kind(), real(c), imag(c)), true
+	}
+	return nil, false
+}
+
+// integerToFloat returns the value of the integer as a float64.
+func integerToFloat(i *object.Integer) float64 {
+	if i.IsUnsigned() {
+		return float64(uint64(i.Value))
+	}
+	return float64(i.Value)
+}
+
+// assignNumeric returns the value assigned to a variable, parameter or result of a numeric
+// type. minigo has no untyped constants, so an integer or a float of the default kind (the
+// type of the constants) takes the declared kind, e.g. `var h uint32 = 2166136261`. The other
+// values are returned as they are.
+func assignNumeric(kind reflect.Kind, val object.Object) object.Object {
+	switch v := val.(type) {
+	case *object.Integer:
+		if v.Kind != reflect.Invalid || kind == reflect.Int {
+			return val
+		}
+	case *object.Float:
+		if v.Kind != reflect.Invalid || kind == reflect.Float64 {
+			return val
+		}
+		if isIntegerKind(kind) && v.Value != math.Trunc(v.Value) {
+			return val
+		}
+	default:
+		return val
+	}
+	if converted, ok := convertNumeric(kind, val); ok {
+		return converted
+	}
+	return val
+}
+
+// keepNumericKind returns the value assigned to a variable holding a number of a sized kind,
+// which keeps its kind, e.g. `x = 0` for `var x uint8`.
+func keepNumericKind(existing, val object.Object) object.Object {
+	switch old := existing.(type) {
+	case *object.Integer:
+		if old.Kind != reflect.Invalid {
+			return assignNumeric(old.Kind, val)
+		}
+	case *object.Float:
+		if old.Kind != reflect.Invalid {
+			return assignNumeric(old.Kind, val)
+		}
+	case *object.Complex:
+		if old.Kind != reflect.Invalid {
+			return assignNumeric(old.Kind, val)
+		}
+	}
+	return val
+}
+
+// binaryKind returns the kind of the result of a binary operation. minigo does not check the
+// types, so an operand of a sized kind wins over one of the default kind (an untyped constant
+// in Go), and the left one wins otherwise.
+func binaryKind(left, right reflect.Kind) reflect.Kind {
+	if left != reflect.Invalid {
+		return left
+	}
+	return right
+}
+
+// unwrapToInteger returns the integer of an Integer or a GoValue holding a Go integer.
+func (e *Evaluator) unwrapToInteger(obj object.Object) (*object.Integer, bool) {
+	switch o := obj.(type) {
+	case *object.Integer:
+		return o, true
+	case *object.GoValue:
+		if o.Value.CanInt() || o.Value.CanUint() {
+			n, _ := goValueToNumber(o.Value)
+			return n.(*object.Integer), true
+		}
+	}
+	return nil, false
+}
+
+// evalIntegerInfixExpression evaluates infix expressions for integers, with the overflow,
+// division and shift semantics of their kind.
+func (e *Evaluator) evalIntegerInfixExpression(node ast.Node, operator string, left, right object.Object) object.Object {
+	l := left.(*object.Integer)
+	r := right.(*object.Integer)
+
+	if operator == "<<" || operator == ">>" {
+		if !r.IsUnsigned() && r.Value < 0 {
+			return e.newError(node.Pos(), "runtime error: negative shift amount")
+		}
+		count := uint64(r.Value)
+		if l.IsUnsigned() {
+			if operator == "<<" {
+				return object.NewInteger(l.Kind, int64(uint64(l.Value)<<count))
+			}
+			return object.NewInteger(l.Kind, int64(uint64(l.Value)>>count))
+		}
+		if operator == "<<" {
+			return object.NewInteger(l.Kind, l.Value<<count)
+		}
+		return object.NewInteger(l.Kind, l.Value>>count)
+	}
+
+	kind := binaryKind(l.Kind, r.Kind)
+	unsigned := isUnsignedKind(kind)
+	leftVal, rightVal := l.Value, r.Value
+	switch operator {
+	case "+":
+		return object.NewInteger(kind, leftVal+rightVal)
+	case "-":
+		return object.NewInteger(kind, leftVal-rightVal)
+	case "*":
+		return object.NewInteger(kind, leftVal*rightVal)
+	case "/", "%":
+		if rightVal == 0 {
+			return e.newError(node.Pos(), "division by zero")
+		}
+		switch {
+		case unsigned && operator == "/":
+			return object.NewInteger(kind, int64(uint64(leftVal)/uint64(rightVal)))
+		case unsigned:
+			return object.NewInteger(kind, int64(uint64(leftVal)%uint64(rightVal)))
+		case operator == "/":
+			return object.NewInteger(kind, leftVal/rightVal)
+		default:
+			return object.NewInteger(kind, leftVal%rightVal)
+		}
+	case "&":
+		return object.NewInteger(kind, leftVal&rightVal)
+	case "|":
+		return object.NewInteger(kind, leftVal|rightVal)
+	case "^":
+		return object.NewInteger(kind, leftVal^rightVal)
+	case "&^":
+		return object.NewInteger(kind, leftVal&^rightVal)
+	case "==":
+		return e.nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return e.nativeBoolToBooleanObject(leftVal != rightVal)
+	}
+
+	var less, equal bool
+	if unsigned {
+		less, equal = uint64(leftVal) < uint64(rightVal), leftVal == rightVal
+	} else {
+		less, equal = leftVal < rightVal, leftVal == rightVal
+	}
+	switch operator {
+	case "<":
+		return e.nativeBoolToBooleanObject(less)
+	case "<=":
+		return e.nativeBoolToBooleanObject(less || equal)
+	case ">":
+		return e.nativeBoolToBooleanObject(!less && !equal)
+	case ">=":
+		return e.nativeBoolToBooleanObject(!less)
+	default:
+		return e.newError(node.Pos(), "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalFloatInfixExpression evaluates infix expressions for floats. An integer operand is
+// converted to the kind of the float one, as an untyped constant is.
+func (e *Evaluator) evalFloatInfixExpression(node ast.Node, operator string, left, right object.Object) object.Object {
+	l, lok := left.(*object.Float)
+	r, rok := right.(*object.Float)
+	switch {
+	case !lok:
+		l = object.NewFloat(r.Kind, integerToFloat(left.(*object.Integer)))
+	case !rok:
+		r = object.NewFloat(l.Kind, integerToFloat(right.(*object.Integer)))
+	}
+
+	kind := binaryKind(l.Kind, r.Kind)
+	leftVal, rightVal := l.Value, r.Value
+	switch operator {
+	case "+":
+		return object.NewFloat(kind, leftVal+rightVal)
+	case "-":
+		return object.NewFloat(kind, leftVal-rightVal)
+	case "*":
+		return object.NewFloat(kind, leftVal*rightVal)
+	case "/":
+		return object.NewFloat(kind, leftVal/rightVal)
+	case "<":
+		return e.nativeBoolToBooleanObject(leftVal < rightVal)
+	case "<=":
+		return e.nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">":
+		return e.nativeBoolToBooleanObject(leftVal > rightVal)
+	case ">=":
+		return e.nativeBoolToBooleanObject(leftVal >= rightVal)
+	case "==":
+		return e.nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return e.nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return e.newError(node.Pos(), "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalComplexInfixExpression evaluates infix expressions for complex numbers. An integer or
+// float operand is converted to the kind of the complex one.
+func (e *Evaluator) evalComplexInfixExpression(node ast.Node, operator string, left, right object.Object) object.Object {
+	l, lok := left.(*object.Complex)
+	r, rok := right.(*object.Complex)
+	if !lok {
+		converted, ok := convertNumeric(r.GoKind(), left)
+		if !ok {
+			return e.newError(node.Pos(), "type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		}
+		l = converted.(*object.Complex)
+	}
+	if !rok {
+		converted, ok := convertNumeric(l.GoKind(), right)
+		if !ok {
+			return e.newError(node.Pos(), "type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		}
+		r = converted.(*object.Complex)
+	}
+
+	kind := binaryKind(l.Kind, r.Kind)
+	leftVal, rightVal := complex(l.Real, l.Imag), complex(r.Real, r.Imag)
+	var result complex128
+	switch operator {
+	case "+":
+		result = leftVal + rightVal
+	case "-":
+		result = leftVal - rightVal
+	case "*":
+		result = leftVal * rightVal
+	case "/":
+		result = leftVal / rightVal
+	case "==":
+		return e.nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return e.nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return e.newError(node.Pos(), "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+	return object.NewComplex(kind, real(result), imag(result))
+}
+
+// evalNumericPrefixExpression evaluates `-x` and `^x` for numbers.
+func (e *Evaluator) evalNumericPrefixExpression(node ast.Node, operator string, right object.Object) object.Object {
+	switch v := right.(type) {
+	case *object.Integer:
+		if operator == "^" {
+			return object.NewInteger(v.Kind, ^v.Value)
+		}
+		return object.NewInteger(v.Kind, -v.Value)
+	case *object.Float:
+		if operator == "-" {
+			return object.NewFloat(v.Kind, -v.Value)
+		}
+	case *object.Complex:
+		if operator == "-" {
+			return object.NewComplex(v.Kind, -v.Real, -v.Imag)
+		}
+	}
+	return e.newError(node.Pos(), "unknown operator: %s%s", operator, right.Type())
+}
+
+// compoundOperators are the operators of the compound assignments, e.g. "+" for `x += y`.
+var compoundOperators = map[token.Token]string{
+	token.ADD_ASSIGN: "+", token.SUB_ASSIGN: "-", token.MUL_ASSIGN: "*", token.QUO_ASSIGN: "/", token.REM_ASSIGN: "%",
+	token.AND_ASSIGN: "&", token.OR_ASSIGN: "|", token.XOR_ASSIGN: "^", token.AND_NOT_ASSIGN: "&^",
+	token.SHL_ASSIGN: "<<", token.SHR_ASSIGN: ">>",
+}
+
+// isNumber reports whether the object is an integer, a float or a complex number.
+func isNumber(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Integer, *object.Float, *object.Complex:
+		return true
+	}
+	return false
+}
+
+// paramValue returns the value bound to a parameter declared by the field, taking its numeric
+// kind, e.g. for `func hash(seed uint32)`.
+func paramValue(param *ast.Field, arg object.Object) object.Object {
+	if kind, ok := numericKindOfExpr(param.Type); ok {
+		return assignNumeric(kind, arg)
+	}
+	return arg
+}
+
+// resultValues returns the values returned by the function, taking the numeric kinds of its
+// results, e.g. `return 0` for `func f() uint8`.
+func resultValues(fn *object.Function, values []object.Object) []object.Object {
+	if fn == nil || fn.Results == nil {
+		return values
+	}
+	i := 0
+	for _, field := range fn.Results.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for ; n > 0 && i < len(values); n-- {
+			if kind, ok := numericKindOfExpr(field.Type); ok {
+				values[i] = assignNumeric(kind, values[i])
+			}
+			i++
+		}
+	}
+	return values
+}
+
+// integerTypes are the Go types of the integer kinds.
+var integerTypes = map[reflect.Kind]reflect.Type{
+	reflect.Int: reflect.TypeOf(int(0)), reflect.Int8: reflect.TypeOf(int8(0)), reflect.Int16: reflect.TypeOf(int16(0)),
+	reflect.Int32: reflect.TypeOf(int32(0)), reflect.Int64: reflect.TypeOf(int64(0)),
+	reflect.Uint: reflect.TypeOf(uint(0)), reflect.Uint8: reflect.TypeOf(uint8(0)), reflect.Uint16: reflect.TypeOf(uint16(0)),
+	reflect.Uint32: reflect.TypeOf(uint32(0)), reflect.Uint64: reflect.TypeOf(uint64(0)), reflect.Uintptr: reflect.TypeOf(uintptr(0)),
+}
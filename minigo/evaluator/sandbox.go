@@ -0,0 +1,81 @@
+package evaluator
+
+import (
+	"go/token"
+
+	"github.com/podhmo/go-scan/minigo/object"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// sandbox holds the limits and the import rules of the evaluator, with the counts of the
+// current execution.
+type sandbox struct {
+	maxSteps       int
+	maxObjects     int
+	allowedImports []string
+	deniedImports  []string
+
+	steps   int
+	objects int
+}
+
+// ResetLimits resets the counts of the evaluation steps and the allocated objects, so that each
+// execution gets the full budget of MaxSteps and MaxObjects.
+func (e *Evaluator) ResetLimits() {
+	e.sandbox.steps = 0
+	e.sandbox.objects = 0
+}
+
+// step counts an evaluation step, and returns an error if the steps exceed the limit.
+// Once exceeded, every following step fails, which stops the evaluation of loops and calls.
+func (e *Evaluator) step(pos token.Pos) *object.Error {
+	e.sandbox.steps++
+	if e.sandbox.steps > e.sandbox.maxSteps {
+		return e.newSandboxError(pos, object.ErrorKindStepLimit, "evaluation step limit exceeded (max %d)", e.sandbox.maxSteps)
+	}
+	return nil
+}
+
+// allocate counts n new objects, and returns an error if the objects exceed the limit.
+func (e *Evaluator) allocate(pos token.Pos, n int) *object.Error {
+	if e.sandbox.maxObjects <= 0 {
+		return nil
+	}
+	e.sandbox.objects += n
+	if e.sandbox.objects > e.sandbox.maxObjects {
+		return e.newSandboxError(pos, object.ErrorKindObjectLimit, "object allocation limit exceeded (max %d)", e.sandbox.maxObjects)
+	}
+	return nil
+}
+
+// allocateString counts the bytes of the string concatenating left and right as objects, so
+// that a loop doing `s += s` hits the limit as a growing slice does.
+func (e *Evaluator) allocateString(pos token.Pos, left, right string) *object.Error {
+	return e.allocate(pos, len(left)+len(right))
+}
+
+// checkImport returns an error if the package cannot be used. The allowed imports are only
+// checked for the imports of the script (direct), while the denied imports are checked for
+// every use of a package, including from the packages loaded from source.
+func (e *Evaluator) checkImport(pos token.Pos, path string, direct bool) *object.Error {
+	for _, pattern := range e.sandbox.deniedImports {
+		if scanner.MatchPattern(pattern, path) {
+			return e.newSandboxError(pos, object.ErrorKindImportDenied, "use of package %q is not allowed", path)
+		}
+	}
+	if !direct || e.sandbox.allowedImports == nil {
+		return nil
+	}
+	for _, pattern := range e.sandbox.allowedImports {
+		if scanner.MatchPattern(pattern, path) {
+			return nil
+		}
+	}
+	return e.newSandboxError(pos, object.ErrorKindImportDenied, "import of package %q is not allowed", path)
+}
+
+func (e *Evaluator) newSandboxError(pos token.Pos, kind object.ErrorKind, format string, args ...any) *object.Error {
+	err := e.newError(pos, format, args...)
+	err.Kind = kind
+	return err
+}
@@ -0,0 +1,145 @@
+package ffibridge
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Go cannot create a type with methods at runtime, so a script value passed to a Go
+// function expecting an interface is wrapped in an adapter: a Go type implementing the
+// interface, declared in advance, whose methods call functions made with reflect.MakeFunc
+// that forward the calls back into the interpreter.
+//
+// The adapters of a few common interfaces are registered by this package. Others are
+// registered with RegisterAdapter:
+//
+//	type handlerAdapter struct {
+//		serveHTTP func(http.ResponseWriter, *http.Request)
+//	}
+//
+//	func (a *handlerAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) { a.serveHTTP(w, r) }
+//
+//	ffibridge.RegisterAdapter(func(m ffibridge.Methods) http.Handler {
+//		a := &handlerAdapter{}
+//		m.Bind("ServeHTTP", &a.serveHTTP)
+//		return a
+//	})
+
+// Methods holds the implementations of the methods of an interface, keyed by method name.
+// Each one is a function value with the signature of the method, without the receiver.
+type Methods map[string]reflect.Value
+
+// Bind stores the implementation of the method name in the function variable pointed to by fn,
+// which must have the signature of the method.
+func (m Methods) Bind(name string, fn any) {
+	impl, ok := m[name]
+	if !ok {
+		panic(fmt.Sprintf("ffibridge: no implementation of method %s", name))
+	}
+	reflect.ValueOf(fn).Elem().Set(impl)
+}
+
+// AdapterFunc builds a value implementing an interface from the implementations of its methods.
+type AdapterFunc func(m Methods) any
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   = map[reflect.Type]AdapterFunc{}
+)
+
+// RegisterAdapter registers the adapter of the interface type T, replacing any previous one.
+func RegisterAdapter[T any](fn func(m Methods) T) {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("ffibridge: cannot register an adapter for %s, which is not an interface", typ))
+	}
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+	adapters[typ] = func(m Methods) any { return fn(m) }
+}
+
+// LookupAdapter returns the adapter registered for the interface type typ.
+func LookupAdapter(typ reflect.Type) (AdapterFunc, bool) {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+	fn, ok := adapters[typ]
+	return fn, ok
+}
+
+type readerAdapter struct {
+	read func([]byte) (int, error)
+}
+
+func (a *readerAdapter) Read(p []byte) (int, error) { return a.read(p) }
+
+type writerAdapter struct {
+	write func([]byte) (int, error)
+}
+
+func (a *writerAdapter) Write(p []byte) (int, error) { return a.write(p) }
+
+type closerAdapter struct {
+	close func() error
+}
+
+func (a *closerAdapter) Close() error { return a.close() }
+
+type stringerAdapter struct {
+	string func() string
+}
+
+func (a *stringerAdapter) String() string { return a.string() }
+
+type errorAdapter struct {
+	error func() string
+}
+
+func (a *errorAdapter) Error() string { return a.error() }
+
+type sortAdapter struct {
+	len  func() int
+	less func(i, j int) bool
+	swap func(i, j int)
+}
+
+func (a *sortAdapter) Len() int           { return a.len() }
+func (a *sortAdapter) Less(i, j int) bool { return a.less(i, j) }
+func (a *sortAdapter) Swap(i, j int)      { a.swap(i, j) }
+
+func init() {
+	RegisterAdapter(func(m Methods) io.Reader {
+		a := &readerAdapter{}
+		m.Bind("Read", &a.read)
+		return a
+	})
+	RegisterAdapter(func(m Methods) io.Writer {
+		a := &writerAdapter{}
+		m.Bind("Write", &a.write)
+		return a
+	})
+	RegisterAdapter(func(m Methods) io.Closer {
+		a := &closerAdapter{}
+		m.Bind("Close", &a.close)
+		return a
+	})
+	RegisterAdapter(func(m Methods) fmt.Stringer {
+		a := &stringerAdapter{}
+		m.Bind("String", &a.string)
+		return a
+	})
+	RegisterAdapter(func(m Methods) error {
+		a := &errorAdapter{}
+		m.Bind("Error", &a.error)
+		return a
+	})
+	RegisterAdapter(func(m Methods) sort.Interface {
+		a := &sortAdapter{}
+		m.Bind("Len", &a.len)
+		m.Bind("Less", &a.less)
+		m.Bind("Swap", &a.swap)
+		return a
+	})
+}
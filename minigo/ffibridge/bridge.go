@@ -5,8 +5,9 @@
 // internal object model and the host language's type system. This package
 // contains data structures that act as a "bridge" for complex scenarios.
 //
-// Currently, it provides a bridge for passing mutable pointers from MiniGo to Go,
-// which is essential for functions like `json.Unmarshal`.
+// It provides a bridge for passing mutable pointers from MiniGo to Go, which is
+// essential for functions like `json.Unmarshal`, and the adapters passing the structs
+// defined in scripts to Go functions expecting interfaces (see RegisterAdapter).
 //
 // In the future, this package could be extended to handle other complex FFI
 // scenarios. For example, to support Go channels, a `ChannelBridge` could be
@@ -20,7 +21,6 @@
 //	}
 //
 // Other potential uses include:
-// - Handling function pointers or callbacks from Go to MiniGo.
 // - More complex memory management or ownership transfer helpers.
 package ffibridge
 
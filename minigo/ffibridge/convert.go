@@ -0,0 +1,293 @@
+package ffibridge
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/podhmo/go-scan/minigo/object"
+)
+
+// The functions in this file are used by the bindings generated by `minigo gen-bindings`.
+// The generator knows the Go signature of each bound function, so it emits a call to the
+// converter matching each parameter and result type, instead of relying on reflection
+// to discover them on every call.
+
+// ToString converts a minigo object to a Go string.
+func ToString(obj object.Object) (string, error) {
+	switch o := obj.(type) {
+	case *object.String:
+		return o.Value, nil
+	case *object.GoValue:
+		if o.Value.Kind() == reflect.String {
+			return o.Value.String(), nil
+		}
+	}
+	return "", mismatch(obj, "string")
+}
+
+// ToInt64 converts a minigo object to a Go int64. The generated code converts the result
+// to the declared integer type of the parameter. An unsigned integer above math.MaxInt64
+// is an error.
+func ToInt64(obj object.Object) (int64, error) {
+	switch o := obj.(type) {
+	case *object.Integer:
+		if o.IsUnsigned() && o.Value < 0 {
+			return 0, fmt.Errorf("cannot convert %s to integer: overflows int64", o.Inspect())
+		}
+		return o.Value, nil
+	case *object.GoValue:
+		switch o.Value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return o.Value.Int(), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if v := o.Value.Uint(); v > math.MaxInt64 {
+				return 0, fmt.Errorf("cannot convert %d to integer: overflows int64", v)
+			}
+			return int64(o.Value.Uint()), nil
+		}
+	}
+	return 0, mismatch(obj, "integer")
+}
+
+// ToUint64 converts a minigo object to a Go uint64. The generated code converts the result
+// to the declared unsigned integer type of the parameter. A negative integer is an error.
+func ToUint64(obj object.Object) (uint64, error) {
+	switch o := obj.(type) {
+	case *object.Integer:
+		if !o.IsUnsigned() && o.Value < 0 {
+			return 0, fmt.Errorf("cannot convert %s to unsigned integer: negative value", o.Inspect())
+		}
+		return uint64(o.Value), nil
+	case *object.GoValue:
+		switch o.Value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if o.Value.Int() < 0 {
+				return 0, fmt.Errorf("cannot convert %d to unsigned integer: negative value", o.Value.Int())
+			}
+			return uint64(o.Value.Int()), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return o.Value.Uint(), nil
+		}
+	}
+	return 0, mismatch(obj, "unsigned integer")
+}
+
+// ToFloat64 converts a minigo object to a Go float64. Integers are accepted as well.
+func ToFloat64(obj object.Object) (float64, error) {
+	switch o := obj.(type) {
+	case *object.Float:
+		return o.Value, nil
+	case *object.Integer:
+		if o.IsUnsigned() {
+			return float64(uint64(o.Value)), nil
+		}
+		return float64(o.Value), nil
+	case *object.GoValue:
+		if k := o.Value.Kind(); k == reflect.Float32 || k == reflect.Float64 {
+			return o.Value.Float(), nil
+		}
+	}
+	return 0, mismatch(obj, "float")
+}
+
+// ToBool converts a minigo object to a Go bool.
+func ToBool(obj object.Object) (bool, error) {
+	switch o := obj.(type) {
+	case *object.Boolean:
+		return o.Value, nil
+	case *object.GoValue:
+		if o.Value.Kind() == reflect.Bool {
+			return o.Value.Bool(), nil
+		}
+	}
+	return false, mismatch(obj, "boolean")
+}
+
+// ToBytes converts a minigo array of integers (or a Go []byte) to a Go []byte.
+func ToBytes(obj object.Object) ([]byte, error) {
+	return toSlice(obj, func(elem object.Object) (byte, error) {
+		v, err := ToUint64(elem)
+		return byte(v), err
+	})
+}
+
+// ToInts converts a minigo array of integers (or a Go []int) to a Go []int.
+func ToInts(obj object.Object) ([]int, error) {
+	return toSlice(obj, func(elem object.Object) (int, error) {
+		v, err := ToInt64(elem)
+		return int(v), err
+	})
+}
+
+// ToFloat64s converts a minigo array of floats (or a Go []float64) to a Go []float64.
+func ToFloat64s(obj object.Object) ([]float64, error) {
+	return toSlice(obj, ToFloat64)
+}
+
+// ToStrings converts a minigo array of strings (or a Go []string) to a Go []string.
+func ToStrings(obj object.Object) ([]string, error) {
+	return toSlice(obj, ToString)
+}
+
+func toSlice[T any](obj object.Object, convert func(object.Object) (T, error)) ([]T, error) {
+	switch o := obj.(type) {
+	case *object.Nil:
+		return nil, nil
+	case *object.Array:
+		s := make([]T, len(o.Elements))
+		for i, elem := range o.Elements {
+			v, err := convert(elem)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			s[i] = v
+		}
+		return s, nil
+	case *object.GoValue:
+		if !o.Value.IsValid() || !o.Value.CanInterface() {
+			break
+		}
+		if s, ok := o.Value.Interface().([]T); ok {
+			return s, nil
+		}
+	}
+	return nil, mismatch(obj, reflect.TypeFor[[]T]().String())
+}
+
+// ToValue converts a minigo object to a value of the Go type T. It is used for the
+// parameter types that have no dedicated converter, such as named types, pointers and
+// interfaces. A GoValue must hold a value assignable or convertible to T, nil becomes
+// the zero value of a nillable T, and minigo primitives are converted to named types
+// with a matching underlying type (e.g. an integer to time.Duration).
+func ToValue[T any](obj object.Object) (T, error) {
+	var zero T
+	typ := reflect.TypeFor[T]()
+	switch o := obj.(type) {
+	case *object.Nil:
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+			return zero, nil
+		}
+	case *object.GoValue:
+		if !o.Value.IsValid() || !o.Value.CanInterface() {
+			return zero, fmt.Errorf("cannot convert %s to %s: the Go value is invalid or unexported", obj.Type(), typ)
+		}
+		if v, ok := o.Value.Interface().(T); ok {
+			return v, nil
+		}
+		if o.Value.Type().ConvertibleTo(typ) {
+			return o.Value.Convert(typ).Interface().(T), nil
+		}
+	case *object.Integer:
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			if o.IsUnsigned() {
+				return reflect.ValueOf(uint64(o.Value)).Convert(typ).Interface().(T), nil
+			}
+			return reflect.ValueOf(o.Value).Convert(typ).Interface().(T), nil
+		}
+	case *object.Float:
+		if k := typ.Kind(); k == reflect.Float32 || k == reflect.Float64 {
+			return reflect.ValueOf(o.Value).Convert(typ).Interface().(T), nil
+		}
+	case *object.String:
+		if typ.Kind() == reflect.String {
+			return reflect.ValueOf(o.Value).Convert(typ).Interface().(T), nil
+		}
+	case *object.Boolean:
+		if typ.Kind() == reflect.Bool {
+			return reflect.ValueOf(o.Value).Convert(typ).Interface().(T), nil
+		}
+	}
+	return zero, mismatch(obj, typ.String())
+}
+
+// FromBool converts a Go bool to a minigo boolean.
+func FromBool(b bool) object.Object {
+	if b {
+		return object.TRUE
+	}
+	return object.FALSE
+}
+
+// FromError converts a Go error result to a minigo object. A nil error becomes nil, and
+// a non-nil error is wrapped in a GoValue, so that the script can check it with `err != nil`.
+func FromError(err error) object.Object {
+	if err == nil {
+		return object.NIL
+	}
+	return &object.GoValue{Value: reflect.ValueOf(err)}
+}
+
+// FromValue converts a Go value of any other type to a minigo object, following the same
+// rules as values returned from reflection-based calls: numbers, strings and booleans
+// (including named types based on them), []byte and []string become minigo values,
+// nil pointers and interfaces become nil, and everything else is wrapped in a GoValue.
+// The numbers keep their kinds, e.g. a uint64 is an Integer of reflect.Uint64.
+func FromValue(v any) object.Object {
+	switch v := v.(type) {
+	case nil:
+		return object.NIL
+	case []byte:
+		elements := make([]object.Object, len(v))
+		for i, b := range v {
+			elements[i] = object.NewInteger(reflect.Uint8, int64(b))
+		}
+		return &object.Array{Elements: elements}
+	case []string:
+		elements := make([]object.Object, len(v))
+		for i, s := range v {
+			elements[i] = &object.String{Value: s}
+		}
+		return &object.Array{Elements: elements}
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return object.NewInteger(val.Kind(), val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return object.NewInteger(val.Kind(), int64(val.Uint()))
+	case reflect.Float32, reflect.Float64:
+		return object.NewFloat(val.Kind(), val.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := val.Complex()
+		return object.NewComplex(val.Kind(), real(c), imag(c))
+	case reflect.String:
+		return &object.String{Value: val.String()}
+	case reflect.Bool:
+		return FromBool(val.Bool())
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return object.NIL
+		}
+	}
+	return &object.GoValue{Value: val}
+}
+
+// CopyBack writes the elements of a Go slice back into the minigo array it was converted from,
+// so that in-place modifications (e.g. by sort.Ints) are visible to the script.
+func CopyBack[T any](obj object.Object, s []T) {
+	arr, ok := obj.(*object.Array)
+	if !ok {
+		return
+	}
+	for i := 0; i < len(s) && i < len(arr.Elements); i++ {
+		arr.Elements[i] = FromValue(s[i])
+	}
+}
+
+// Recover converts a panic raised by a bound Go function into a minigo panic.
+// It must be deferred directly: `defer ffibridge.Recover(&ret)`.
+func Recover(ret *object.Object) {
+	if r := recover(); r != nil {
+		*ret = &object.Panic{Value: &object.String{Value: fmt.Sprintf("%v", r)}}
+	}
+}
+
+func mismatch(obj object.Object, want string) error {
+	return fmt.Errorf("cannot convert %s to %s", obj.Type(), want)
+}
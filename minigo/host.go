@@ -0,0 +1,170 @@
+package minigo
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithClock sets the clock of the interpreter, used by time.Now, time.Since and time.Until when
+// the "time" package is registered, e.g. with minigo/stdlib/time, so that the scripts reading
+// the current time can be tested deterministically.
+func WithClock(now func() time.Time) Option {
+	return func(i *Interpreter) {
+		i.clock = now
+	}
+}
+
+// WithEnv replaces the environment variables seen by the scripts through the "os" package, e.g.
+// os.Getenv and os.Setenv, with the given ones, so that the process environment is neither read
+// nor modified.
+func WithEnv(env map[string]string) Option {
+	return func(i *Interpreter) {
+		i.env.vars = make(map[string]string, len(env))
+		for k, v := range env {
+			i.env.vars[k] = v
+		}
+	}
+}
+
+// hostOverrides returns the symbols replacing the registered ones of a package to apply the
+// clock (WithClock) and the environment controls (WithEnv and Sandbox.AllowedEnv). Only the
+// symbols being registered are replaced.
+func (i *Interpreter) hostOverrides(pkgPath string, symbols map[string]any) map[string]any {
+	var overrides map[string]any
+	switch pkgPath {
+	case "time":
+		if i.clock == nil {
+			return nil
+		}
+		now := i.clock
+		overrides = map[string]any{
+			"Now":   now,
+			"Since": func(t time.Time) time.Duration { return now().Sub(t) },
+			"Until": func(t time.Time) time.Duration { return t.Sub(now()) },
+		}
+	case "os":
+		if i.env.vars == nil && i.sandbox.AllowedEnv == nil {
+			return nil
+		}
+		env := &i.env
+		env.allowed = i.sandbox.AllowedEnv
+		overrides = map[string]any{
+			"Getenv":    env.getenv,
+			"LookupEnv": env.lookup,
+			"Environ":   env.environ,
+			"ExpandEnv": func(s string) string { return os.Expand(s, env.getenv) },
+			"Setenv":    env.setenv,
+			"Unsetenv":  env.unsetenv,
+			"Clearenv":  env.clear,
+		}
+	default:
+		return nil
+	}
+	for name := range overrides {
+		if _, ok := symbols[name]; !ok {
+			delete(overrides, name)
+		}
+	}
+	return overrides
+}
+
+// environment is the environment of the scripts: the process environment or the variables given
+// with WithEnv, restricted to the variables allowed by the sandbox.
+type environment struct {
+	mu      sync.Mutex
+	vars    map[string]string // nil for the process environment
+	allowed []string          // nil for all the variables
+}
+
+// isAllowed reports whether the variable is in the allowlist, whose entries are names or
+// patterns, e.g. "APP_*".
+func (e *environment) isAllowed(key string) bool {
+	if e.allowed == nil {
+		return true
+	}
+	for _, pattern := range e.allowed {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *environment) lookup(key string) (string, bool) {
+	if !e.isAllowed(key) {
+		return "", false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.vars == nil {
+		return os.LookupEnv(key)
+	}
+	v, ok := e.vars[key]
+	return v, ok
+}
+
+func (e *environment) getenv(key string) string {
+	v, _ := e.lookup(key)
+	return v
+}
+
+// environ returns the allowed variables as "key=value" strings, sorted for the given variables.
+func (e *environment) environ() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var env []string
+	if e.vars == nil {
+		for _, kv := range os.Environ() {
+			if key, _, _ := strings.Cut(kv, "="); e.isAllowed(key) {
+				env = append(env, kv)
+			}
+		}
+		return env
+	}
+	for k, v := range e.vars {
+		if e.isAllowed(k) {
+			env = append(env, k+"="+v)
+		}
+	}
+	sort.Strings(env)
+	return env
+}
+
+func (e *environment) setenv(key, value string) error {
+	if !e.isAllowed(key) {
+		return fmt.Errorf("setenv %s: the environment variable is not allowed", key)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.vars == nil {
+		return os.Setenv(key, value)
+	}
+	e.vars[key] = value
+	return nil
+}
+
+func (e *environment) unsetenv(key string) error {
+	if !e.isAllowed(key) {
+		return fmt.Errorf("unsetenv %s: the environment variable is not allowed", key)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.vars == nil {
+		return os.Unsetenv(key)
+	}
+	delete(e.vars, key)
+	return nil
+}
+
+// clear removes the allowed variables.
+func (e *environment) clear() {
+	for _, kv := range e.environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		e.unsetenv(key)
+	}
+}
@@ -0,0 +1,184 @@
+package minigo
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/podhmo/go-scan/minigo/object"
+)
+
+// ScriptCache caches the parsed files of the script packages imported with local imports, e.g.
+// `import "./helpers"`, so that the scripts run repeatedly, e.g. by interpreters sharing a
+// scanner, parse each file once. A file is parsed again if its modification time or size
+// changed. The files are cached by the file set of the scanner, as their positions are in it.
+// It is safe for concurrent use.
+type ScriptCache struct {
+	mu    sync.Mutex
+	files map[scriptCacheKey]*cachedScript
+}
+
+type scriptCacheKey struct {
+	fset *token.FileSet
+	path string
+}
+
+type cachedScript struct {
+	modTime time.Time
+	size    int64
+	file    *ast.File
+}
+
+// NewScriptCache creates an empty ScriptCache.
+func NewScriptCache() *ScriptCache {
+	return &ScriptCache{files: make(map[scriptCacheKey]*cachedScript)}
+}
+
+// parseFile returns the parsed file, from the cache if it did not change.
+func (c *ScriptCache) parseFile(fset *token.FileSet, path string) (*ast.File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	key := scriptCacheKey{fset: fset, path: path}
+	c.mu.Lock()
+	cached, ok := c.files[key]
+	c.mu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.file, nil
+	}
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := parser.ParseFile(fset, path, source, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing script %q: %w", path, err)
+	}
+	c.mu.Lock()
+	c.files[key] = &cachedScript{modTime: info.ModTime(), size: info.Size(), file: file}
+	c.mu.Unlock()
+	return file, nil
+}
+
+// WithScriptCache sets the cache of the parsed script packages, to share it between
+// interpreters. By default, each interpreter has its own cache.
+func WithScriptCache(cache *ScriptCache) Option {
+	return func(i *Interpreter) {
+		i.scriptCache = cache
+	}
+}
+
+// isLocalImport reports whether an import path is relative to the importing script file.
+func isLocalImport(path string) bool {
+	return path == "." || path == ".." || strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../")
+}
+
+// scriptStack returns the stack of the packages being loaded for the local imports of a script
+// file: the directory of the file, as a script package importing it would be a cycle.
+func scriptStack(filename string) []string {
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil
+	}
+	return []string{dir}
+}
+
+// loadLocalImports loads the script packages of the local imports of a file in dir, and records
+// them in the file scope. The stack holds the directories of the packages being loaded, for the
+// detection of the import cycles.
+func (i *Interpreter) loadLocalImports(fscope *object.FileScope, dir string, stack []string) error {
+	for _, spec := range fscope.AST.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || !isLocalImport(path) {
+			continue
+		}
+		if i.sandbox.NoFilesystem {
+			return fmt.Errorf("%s: local import %q is not allowed by the sandbox", i.scanner.Fset().Position(spec.Path.Pos()), path)
+		}
+		pkg, err := i.loadScriptPackage(filepath.Join(dir, filepath.FromSlash(path)), stack)
+		if err != nil {
+			return err
+		}
+		fscope.LocalImports[path] = pkg.Path
+	}
+	return nil
+}
+
+// loadScriptPackage loads the script package of a directory: its files are evaluated in the
+// environment of the package, each one with its own file scope, once per interpreter.
+func (i *Interpreter) loadScriptPackage(dir string, stack []string) (*object.Package, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if pkg, ok := i.packages[dir]; ok {
+		return pkg, nil
+	}
+	for n, loading := range stack {
+		if loading == dir {
+			return nil, fmt.Errorf("import cycle not allowed: %s", strings.Join(append(stack[n:], dir), " -> "))
+		}
+	}
+	stack = append(stack, dir)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading script package %q: %w", dir, err)
+	}
+	var filenames []string
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			filenames = append(filenames, filepath.Join(dir, name))
+		}
+	}
+	if len(filenames) == 0 {
+		return nil, fmt.Errorf("no script files in %q", dir)
+	}
+	sort.Strings(filenames)
+
+	var scopes []*object.FileScope
+	var decls []object.DeclWithScope
+	for _, filename := range filenames {
+		file, err := i.scriptCache.parseFile(i.scanner.Fset(), filename)
+		if err != nil {
+			return nil, err
+		}
+		if len(scopes) > 0 && file.Name.Name != scopes[0].AST.Name.Name {
+			return nil, fmt.Errorf("found packages %s and %s in %q", scopes[0].AST.Name.Name, file.Name.Name, dir)
+		}
+		fscope := object.NewFileScope(file)
+		if err := i.loadLocalImports(fscope, dir, stack); err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, fscope)
+		for _, decl := range file.Decls {
+			decls = append(decls, object.DeclWithScope{Decl: decl, Scope: fscope})
+		}
+	}
+
+	pkg := &object.Package{
+		Name:    scopes[0].AST.Name.Name,
+		Path:    dir,
+		Env:     object.NewEnclosedEnvironment(i.globalEnv),
+		FScope:  scopes[0],
+		Members: make(map[string]object.Object),
+	}
+	if result := i.eval.EvalToplevel(decls, pkg.Env); isError(result) {
+		return nil, fmt.Errorf("loading script package %q: %w", dir, toError(result.(*object.Error)))
+	}
+	for name, obj := range pkg.Env.GetAll() {
+		pkg.Members[name] = obj
+	}
+	i.packages[dir] = pkg
+	return pkg, nil
+}
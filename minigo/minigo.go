@@ -8,9 +8,11 @@ import (
 	"go/token"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	goscan "github.com/podhmo/go-scan"
 	"github.com/podhmo/go-scan/minigo/evaluator"
@@ -38,6 +40,13 @@ type Options struct {
 	// Scanner is an optional, pre-configured go-scan scanner.
 	// If nil, a new default scanner is created.
 	Scanner *goscan.Scanner
+
+	// Sandbox optionally limits what the script can do, e.g. for running untrusted scripts.
+	Sandbox *Sandbox
+
+	// ScriptCache optionally shares the parsed script packages of the local imports between
+	// runs, with the same Scanner.
+	ScriptCache *ScriptCache
 }
 
 // Run executes a minigo script in a single, self-contained call.
@@ -52,7 +61,14 @@ func Run(ctx context.Context, opts Options) (*Result, error) {
 		}
 	}
 
-	interp, err := NewInterpreter(scanner, WithGlobals(opts.Globals))
+	options := []Option{WithGlobals(opts.Globals)}
+	if opts.Sandbox != nil {
+		options = append(options, WithSandbox(*opts.Sandbox))
+	}
+	if opts.ScriptCache != nil {
+		options = append(options, WithScriptCache(opts.ScriptCache))
+	}
+	interp, err := NewInterpreter(scanner, options...)
 	if err != nil {
 		return nil, fmt.Errorf("creating interpreter: %w", err)
 	}
@@ -94,10 +110,15 @@ type Interpreter struct {
 	files         []*object.FileScope
 	packages      map[string]*object.Package
 	replFileScope *object.FileScope
+	scriptCache   *ScriptCache
 
-	stdin  io.Reader
-	stdout io.Writer
-	stderr io.Writer
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+	sandbox Sandbox
+
+	clock func() time.Time // see WithClock
+	env   environment      // see WithEnv and Sandbox.AllowedEnv
 }
 
 // Option is a functional option for configuring the Interpreter.
@@ -124,6 +145,13 @@ func WithStderr(w io.Writer) Option {
 	}
 }
 
+// WithSandbox sets the safety controls of the interpreter (see Sandbox).
+func WithSandbox(sandbox Sandbox) Option {
+	return func(i *Interpreter) {
+		i.sandbox = sandbox
+	}
+}
+
 // WithGlobals allows injecting Go variables and functions into the script's global scope.
 func WithGlobals(globals map[string]any) Option {
 	return func(i *Interpreter) {
@@ -210,6 +238,7 @@ func NewInterpreter(scanner *goscan.Scanner, options ...Option) (*Interpreter, e
 		specialForms: make(map[string]*evaluator.SpecialForm),
 		files:        make([]*object.FileScope, 0),
 		packages:     make(map[string]*object.Package),
+		scriptCache:  NewScriptCache(),
 		stdin:        os.Stdin,
 		stdout:       os.Stdout,
 		stderr:       os.Stderr,
@@ -228,6 +257,11 @@ func NewInterpreter(scanner *goscan.Scanner, options ...Option) (*Interpreter, e
 		Stdin:        i.stdin,
 		Stdout:       i.stdout,
 		Stderr:       i.stderr,
+
+		MaxSteps:       i.sandbox.MaxSteps,
+		MaxObjects:     i.sandbox.MaxObjects,
+		AllowedImports: i.sandbox.AllowedImports,
+		DeniedImports:  i.sandbox.deniedImports(),
 	})
 
 	return i, nil
@@ -236,8 +270,13 @@ func NewInterpreter(scanner *goscan.Scanner, options ...Option) (*Interpreter, e
 // Register makes Go symbols (variables or functions) available for import by a script.
 // For example, `interp.Register("strings", map[string]any{"ToUpper": strings.ToUpper})`
 // allows a script to `import "strings"` and call `strings.ToUpper()`.
+// The time functions of the "time" package and the environment functions of the "os" package are
+// replaced to apply WithClock, WithEnv and Sandbox.AllowedEnv.
 func (i *Interpreter) Register(pkgPath string, symbols map[string]any) {
 	i.Registry.Register(pkgPath, symbols)
+	if overrides := i.hostOverrides(pkgPath, symbols); len(overrides) > 0 {
+		i.Registry.Register(pkgPath, overrides)
+	}
 }
 
 // RegisterSpecial registers a "special form" function.
@@ -292,7 +331,7 @@ func (i *Interpreter) LoadGoSourceAsPackage(pkgName, source string) error {
 	}
 	result := i.eval.EvalToplevel(decls, pkgObj.Env)
 	if isError(result) {
-		return fmt.Errorf("error evaluating package %s: %s", pkgName, result.Inspect())
+		return fmt.Errorf("error evaluating package %s: %w", pkgName, toError(result.(*object.Error)))
 	}
 
 	// Populate the package's public members from its environment.
@@ -307,6 +346,7 @@ func (i *Interpreter) LoadGoSourceAsPackage(pkgName, source string) error {
 // EvalString evaluates the given source code string as a complete file.
 // It parses the source, evaluates all declarations, and then executes the main function if it exists.
 func (i *Interpreter) EvalString(source string) (object.Object, error) {
+	i.eval.ResetLimits()
 	fset := i.scanner.Fset()
 	node, err := parser.ParseFile(fset, "main.go", source, parser.ParseComments)
 	if err != nil {
@@ -316,7 +356,7 @@ func (i *Interpreter) EvalString(source string) (object.Object, error) {
 	fileScope := object.NewFileScope(node)
 	result := i.eval.Eval(node, i.globalEnv, fileScope)
 	if err, ok := result.(*object.Error); ok {
-		return nil, fmt.Errorf("%s", err.Inspect())
+		return nil, toError(err)
 	}
 	return result, nil
 }
@@ -324,6 +364,8 @@ func (i *Interpreter) EvalString(source string) (object.Object, error) {
 // PanicError is a special error type that represents an unrecovered panic from the script.
 type PanicError struct {
 	Value object.Object
+	// GoStack is the Go stack trace, if the panic was raised by a Go function called from the script.
+	GoStack string
 }
 
 // Error implements the error interface.
@@ -534,6 +576,8 @@ func unmarshal(src object.Object, dst reflect.Value) error {
 
 // LoadFile parses a file and adds it to the interpreter's state without evaluating it yet.
 // This is the first stage of a multi-file evaluation.
+// The script packages of its local imports, e.g. `import "./helpers"`, relative to the
+// directory of the file, are loaded and evaluated (see ScriptCache).
 func (i *Interpreter) LoadFile(filename string, source []byte) error {
 	fset := i.scanner.Fset()
 	node, err := parser.ParseFile(fset, filename, source, parser.ParseComments)
@@ -541,12 +585,16 @@ func (i *Interpreter) LoadFile(filename string, source []byte) error {
 		return fmt.Errorf("parsing script %q: %w", filename, err)
 	}
 	fileScope := object.NewFileScope(node)
+	if err := i.loadLocalImports(fileScope, filepath.Dir(filename), scriptStack(filename)); err != nil {
+		return err
+	}
 	i.files = append(i.files, fileScope)
 	return nil
 }
 
 // EvalDeclarations evaluates all top-level declarations in the loaded files.
 func (i *Interpreter) EvalDeclarations(ctx context.Context) error {
+	i.eval.ResetLimits()
 	// Associate each declaration with its original file scope to respect
 	// file-scoped imports.
 	var allDecls []object.DeclWithScope
@@ -559,7 +607,7 @@ func (i *Interpreter) EvalDeclarations(ctx context.Context) error {
 	// The new top-level evaluation function will handle the two-pass evaluation.
 	result := i.eval.EvalToplevel(allDecls, i.globalEnv)
 	if err, ok := result.(*object.Error); ok {
-		return fmt.Errorf("%s", err.Inspect())
+		return toError(err)
 	}
 	return nil
 }
@@ -597,13 +645,14 @@ func (i *Interpreter) FindFunction(name string) (*object.Function, *object.FileS
 
 // Execute runs a given function with the provided arguments using the interpreter's persistent evaluator.
 func (i *Interpreter) Execute(ctx context.Context, fn *object.Function, args []object.Object, fscope *object.FileScope) (*Result, error) {
+	i.eval.ResetLimits()
 	result := i.eval.ApplyFunction(nil, fn, args, fscope)
 	switch res := result.(type) {
 	case *object.Error:
-		return nil, fmt.Errorf("%s", res.Inspect())
+		return nil, toError(res)
 	case *object.Panic:
 		// An unrecovered panic becomes a Go error at the interpreter boundary.
-		return nil, &PanicError{Value: res.Value}
+		return nil, &PanicError{Value: res.Value, GoStack: res.GoStack}
 	}
 	return &Result{Value: result}, nil
 }
@@ -611,6 +660,7 @@ func (i *Interpreter) Execute(ctx context.Context, fn *object.Function, args []o
 // EvalLine evaluates a single line of input for the REPL.
 // It maintains state across calls by using a persistent, single FileScope.
 func (i *Interpreter) EvalLine(ctx context.Context, line string) (object.Object, error) {
+	i.eval.ResetLimits()
 	if i.replFileScope == nil {
 		node, err := parser.ParseFile(i.scanner.Fset(), "REPL", "package REPL", parser.ParseComments)
 		if err != nil {
@@ -626,7 +676,7 @@ func (i *Interpreter) EvalLine(ctx context.Context, line string) (object.Object,
 		for _, decl := range node.Decls {
 			result = i.eval.Eval(decl, i.globalEnv, i.replFileScope)
 			if err, ok := result.(*object.Error); ok {
-				return nil, fmt.Errorf("%s", err.Inspect())
+				return nil, toError(err)
 			}
 		}
 		return result, nil
@@ -644,7 +694,7 @@ func (i *Interpreter) EvalLine(ctx context.Context, line string) (object.Object,
 	for _, stmt := range stmts {
 		result = i.eval.Eval(stmt, i.globalEnv, i.replFileScope)
 		if err, ok := result.(*object.Error); ok {
-			return nil, fmt.Errorf("%s", err.Inspect())
+			return nil, toError(err)
 		}
 	}
 	return result, nil
@@ -686,6 +736,10 @@ func (i *Interpreter) EvalFileInREPL(ctx context.Context, filename string) error
 		return fmt.Errorf("parsing script %q: %w", filename, err)
 	}
 
+	if err := i.loadLocalImports(i.replFileScope, filepath.Dir(filename), scriptStack(filename)); err != nil {
+		return err
+	}
+
 	// Add the declarations from the loaded file to the REPL's scope AST.
 	i.replFileScope.AST.Decls = append(i.replFileScope.AST.Decls, node.Decls...)
 
@@ -694,7 +748,7 @@ func (i *Interpreter) EvalFileInREPL(ctx context.Context, filename string) error
 	for _, decl := range node.Decls {
 		result := i.eval.Eval(decl, i.globalEnv, i.replFileScope)
 		if err, ok := result.(*object.Error); ok {
-			return fmt.Errorf("%s", err.Inspect())
+			return toError(err)
 		}
 	}
 
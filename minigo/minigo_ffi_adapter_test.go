@@ -0,0 +1,239 @@
+package minigo_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/go-scan/minigo"
+)
+
+func TestRun_ScriptTypeAsGoInterface(t *testing.T) {
+	register := func(interp *minigo.Interpreter) {
+		interp.Register("example.com/ffi", map[string]any{
+			"Sort": sort.Sort,
+			"WriteAll": func(w io.Writer, parts []string) error {
+				for _, p := range parts {
+					if _, err := io.WriteString(w, p); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			"Describe": func(s fmt.Stringer) string { return "<" + s.String() + ">" },
+			"Check": func(err error) string {
+				if err == nil {
+					return "ok"
+				}
+				return "error: " + err.Error()
+			},
+			"Serve": func(h http.Handler) {},
+		})
+	}
+	run := func(t *testing.T, script string) (string, error) {
+		t.Helper()
+		var stdout bytes.Buffer
+		interp := newTestInterpreter(t, minigo.WithStdout(&stdout))
+		register(interp)
+		if err := interp.LoadFile("main.go", []byte(script)); err != nil {
+			t.Fatalf("LoadFile() failed: %v", err)
+		}
+		_, err := interp.Eval(context.Background())
+		return stdout.String(), err
+	}
+
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{
+			name: "sort.Interface",
+			script: `
+package main
+
+import "example.com/ffi"
+
+type byLen struct {
+	items []string
+}
+
+func (s *byLen) Len() int           { return len(s.items) }
+func (s *byLen) Less(i, j int) bool { return len(s.items[i]) < len(s.items[j]) }
+func (s *byLen) Swap(i, j int)      { s.items[i], s.items[j] = s.items[j], s.items[i] }
+
+func main() {
+	s := &byLen{items: []string{"ccc", "a", "bb"}}
+	ffi.Sort(s)
+	for _, item := range s.items {
+		println(item)
+	}
+}
+`,
+			want: "a\nbb\nccc\n",
+		},
+		{
+			name: "io.Writer",
+			script: `
+package main
+
+import "example.com/ffi"
+
+type collector struct {
+	buf   string
+	calls int
+}
+
+func (c *collector) Write(p []byte) (int, error) {
+	c.buf = c.buf + string(p)
+	c.calls++
+	return len(p), nil
+}
+
+func main() {
+	c := &collector{buf: "", calls: 0}
+	err := ffi.WriteAll(c, []string{"hello", ", ", "world"})
+	println(err == nil, c.calls, c.buf)
+}
+`,
+			want: "true 3 hello, world\n",
+		},
+		{
+			name: "fmt.Stringer by value",
+			script: `
+package main
+
+import "example.com/ffi"
+
+type point struct{ x, y int }
+
+func (p point) String() string { return "point" }
+
+func main() {
+	println(ffi.Describe(point{x: 1, y: 2}))
+}
+`,
+			want: "<point>\n",
+		},
+		{
+			name: "error",
+			script: `
+package main
+
+import "example.com/ffi"
+
+type notFound struct{ name string }
+
+func (e *notFound) Error() string { return e.name + " not found" }
+
+func main() {
+	println(ffi.Check(&notFound{name: "x"}))
+	println(ffi.Check(nil))
+}
+`,
+			want: "error: x not found\nok\n",
+		},
+		{
+			name: "promoted method",
+			script: `
+package main
+
+import "example.com/ffi"
+
+type named struct{ name string }
+
+func (n named) String() string { return n.name }
+
+type user struct {
+	named
+	age int
+}
+
+func main() {
+	println(ffi.Describe(user{named: named{name: "bob"}}))
+}
+`,
+			want: "<bob>\n",
+		},
+		{
+			name: "panic in the script method",
+			script: `
+package main
+
+import "example.com/ffi"
+
+type broken struct{}
+
+func (b broken) String() string { panic("boom") }
+
+func describe() (result string) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = "recovered: " + r
+		}
+	}()
+	return ffi.Describe(broken{})
+}
+
+func main() {
+	println(describe())
+}
+`,
+			want: "recovered: boom\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := run(t, tt.script)
+			if err != nil {
+				t.Fatalf("Eval() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("stdout\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing method", func(t *testing.T) {
+		_, err := run(t, `
+package main
+
+import "example.com/ffi"
+
+type half struct{}
+
+func (h *half) Len() int { return 0 }
+
+func main() {
+	ffi.Sort(&half{})
+}
+`)
+		if err == nil || !strings.Contains(err.Error(), "missing method Less") {
+			t.Errorf("want an error about the missing method, got %v", err)
+		}
+	})
+
+	t.Run("no adapter", func(t *testing.T) {
+		_, err := run(t, `
+package main
+
+import "example.com/ffi"
+
+type handler struct{}
+
+func (h *handler) ServeHTTP(w any, r any) {}
+
+func main() {
+	ffi.Serve(&handler{})
+}
+`)
+		if err == nil || !strings.Contains(err.Error(), "no adapter is registered for http.Handler") {
+			t.Errorf("want an error about the missing adapter, got %v", err)
+		}
+	})
+}
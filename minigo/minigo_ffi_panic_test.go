@@ -0,0 +1,111 @@
+package minigo_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/go-scan/minigo"
+)
+
+type ffiResource struct{ name string }
+
+func (r *ffiResource) Close() {
+	panic("close of " + r.name)
+}
+
+func ffiMustPositive(n int) int {
+	if n <= 0 {
+		panic("not positive")
+	}
+	return n
+}
+
+func TestRun_PanicInGoFunction(t *testing.T) {
+	register := func(interp *minigo.Interpreter) {
+		interp.Register("example.com/ffi", map[string]any{
+			"MustPositive": ffiMustPositive,
+			"Open":         func(name string) *ffiResource { return &ffiResource{name: name} },
+		})
+	}
+
+	t.Run("recovered by the script", func(t *testing.T) {
+		script := `
+package main
+
+import "example.com/ffi"
+
+func call(n int) (result string) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = "recovered: " + r
+		}
+	}()
+	ffi.MustPositive(n)
+	return "ok"
+}
+
+func closeResource() (result string) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = "recovered: " + r
+		}
+	}()
+	res := ffi.Open("db")
+	res.Close()
+	return "closed"
+}
+
+func main() {
+	println(call(1))
+	println(call(-1))
+	println(closeResource())
+}
+`
+		var stdout bytes.Buffer
+		interp := newTestInterpreter(t, minigo.WithStdout(&stdout))
+		register(interp)
+		if err := interp.LoadFile("main.go", []byte(script)); err != nil {
+			t.Fatalf("LoadFile() failed: %v", err)
+		}
+		if _, err := interp.Eval(context.Background()); err != nil {
+			t.Fatalf("Eval() failed: %v", err)
+		}
+		want := "ok\nrecovered: not positive\nrecovered: close of db\n"
+		if got := stdout.String(); got != want {
+			t.Errorf("stdout\n got: %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("unrecovered", func(t *testing.T) {
+		script := `
+package main
+
+import "example.com/ffi"
+
+func main() {
+	ffi.MustPositive(0)
+}
+`
+		interp := newTestInterpreter(t)
+		register(interp)
+		if err := interp.LoadFile("main.go", []byte(script)); err != nil {
+			t.Fatalf("LoadFile() failed: %v", err)
+		}
+		_, err := interp.Eval(context.Background())
+
+		var panicErr *minigo.PanicError
+		if !errors.As(err, &panicErr) {
+			t.Fatalf("expected a PanicError, got %T: %v", err, err)
+		}
+		if got := panicErr.Error(); got != "panic: not positive" {
+			t.Errorf("unexpected error message %q", got)
+		}
+		// The Go stack trace points at the panicking Go function.
+		if !strings.Contains(panicErr.GoStack, "ffiMustPositive") {
+			t.Errorf("expected the Go stack trace to contain the Go function, got:\n%s", panicErr.GoStack)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package minigo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	scan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestMinigo_FirstClassFunctionsFromScannedPackage(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module mytest\n\ngo 1.24\n",
+		"textutil/textutil.go": `package textutil
+
+func Shout(s string) string {
+	return s + "!"
+}
+
+type Counter struct {
+	N int
+}
+
+func (c Counter) Plus(n int) int {
+	return c.N + n
+}
+
+func (c *Counter) Inc() {
+	c.N++
+}
+`,
+	}
+
+	cases := []struct {
+		name   string
+		script string
+		want   any
+	}{
+		{
+			name: "function value assigned to variable",
+			script: `package main
+import "mytest/textutil"
+func main() {
+	f := textutil.Shout
+	result = f("hi")
+}
+var result string
+`,
+			want: "hi!",
+		},
+		{
+			name: "function value passed as argument",
+			script: `package main
+import "mytest/textutil"
+func apply(f func(string) string, xs []string) []string {
+	var out []string
+	for _, x := range xs {
+		out = append(out, f(x))
+	}
+	return out
+}
+func main() {
+	result = apply(textutil.Shout, []string{"a", "b"})
+}
+var result []string
+`,
+			want: []any{"a!", "b!"},
+		},
+		{
+			name: "method expression with value receiver",
+			script: `package main
+import "mytest/textutil"
+func main() {
+	plus := textutil.Counter.Plus
+	result = plus(textutil.Counter{N: 40}, 2)
+}
+var result int
+`,
+			want: int64(42),
+		},
+		{
+			name: "method expression with pointer receiver",
+			script: `package main
+import "mytest/textutil"
+func main() {
+	inc := (*textutil.Counter).Inc
+	c := &textutil.Counter{N: 1}
+	inc(c)
+	inc(c)
+	result = c.N
+}
+var result int
+`,
+			want: int64(3),
+		},
+		{
+			name: "method expression on a script-defined type",
+			script: `package main
+type Greeter struct {
+	Name string
+}
+func (g Greeter) Hello(prefix string) string {
+	return prefix + g.Name
+}
+func main() {
+	hello := Greeter.Hello
+	result = hello(Greeter{Name: "world"}, "hello ")
+}
+var result string
+`,
+			want: "hello world",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tcFiles := make(map[string]string, len(files)+1)
+			for k, v := range files {
+				tcFiles[k] = v
+			}
+			tcFiles["main.mgo"] = tc.script
+
+			dir, cleanup := scantest.WriteFiles(t, tcFiles)
+			defer cleanup()
+
+			action := func(ctx context.Context, s *scan.Scanner, pkgs []*scan.Package) error {
+				interp, err := NewInterpreter(s)
+				if err != nil {
+					return err
+				}
+				source, err := os.ReadFile(filepath.Join(dir, "main.mgo"))
+				if err != nil {
+					return err
+				}
+				if err := interp.LoadFile("main.mgo", source); err != nil {
+					return err
+				}
+				if _, err := interp.Eval(ctx); err != nil {
+					return err
+				}
+
+				val, ok := interp.globalEnv.Get("result")
+				if !ok {
+					return fmt.Errorf("variable 'result' not found")
+				}
+				got, err := ToGoValue(val)
+				if err != nil {
+					return fmt.Errorf("converting result: %w", err)
+				}
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					return fmt.Errorf("result mismatch (-want +got):\n%s", diff)
+				}
+				return nil
+			}
+
+			if _, err := scantest.Run(t, context.Background(), dir, nil, action); err != nil {
+				t.Fatalf("scantest.Run() failed: %+v", err)
+			}
+		})
+	}
+}
@@ -2,13 +2,19 @@ package minigo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/token"
+	"math"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/podhmo/go-scan/minigo/evaluator"
+	"github.com/podhmo/go-scan/minigo/ffibridge"
 	"github.com/podhmo/go-scan/minigo/object"
 )
 
@@ -357,3 +363,211 @@ var result = func() int {
 		})
 	}
 }
+
+// The bindings below are written the way `minigo gen-bindings` generates them:
+// builtins converting their arguments and results with the ffibridge helpers.
+func bindRepeatForTest(ctx *object.BuiltinContext, pos token.Pos, args ...object.Object) (ret object.Object) {
+	defer ffibridge.Recover(&ret)
+	if len(args) != 2 {
+		return ctx.NewError(pos, "wrong number of arguments: got %d, want %d", len(args), 2)
+	}
+	a0, err := ffibridge.ToString(args[0])
+	if err != nil {
+		return ctx.NewError(pos, "argument %d type mismatch: %v", 1, err)
+	}
+	a1, err := ffibridge.ToInt64(args[1])
+	if err != nil {
+		return ctx.NewError(pos, "argument %d type mismatch: %v", 2, err)
+	}
+	r0 := strings.Repeat(a0, int(a1))
+	return &object.String{Value: r0}
+}
+
+func bindJoinForTest(ctx *object.BuiltinContext, pos token.Pos, args ...object.Object) (ret object.Object) {
+	defer ffibridge.Recover(&ret)
+	if len(args) < 0 {
+		return ctx.NewError(pos, "wrong number of arguments for variadic function: got %d, want at least %d", len(args), 0)
+	}
+	var a0 []error
+	for j, arg := range args[0:] {
+		v, err := ffibridge.ToValue[error](arg)
+		if err != nil {
+			return ctx.NewError(pos, "argument %d type mismatch: %v", 1+j, err)
+		}
+		a0 = append(a0, v)
+	}
+	r0 := errors.Join(a0...)
+	return ffibridge.FromError(r0)
+}
+
+func bindParseDurationForTest(ctx *object.BuiltinContext, pos token.Pos, args ...object.Object) (ret object.Object) {
+	defer ffibridge.Recover(&ret)
+	if len(args) != 1 {
+		return ctx.NewError(pos, "wrong number of arguments: got %d, want %d", len(args), 1)
+	}
+	a0, err := ffibridge.ToString(args[0])
+	if err != nil {
+		return ctx.NewError(pos, "argument %d type mismatch: %v", 1, err)
+	}
+	r0, r1 := time.ParseDuration(a0)
+	return &object.Tuple{Elements: []object.Object{
+		ffibridge.FromValue(r0),
+		ffibridge.FromError(r1),
+	}}
+}
+
+func bindDurationStringForTest(ctx *object.BuiltinContext, pos token.Pos, args ...object.Object) (ret object.Object) {
+	defer ffibridge.Recover(&ret)
+	if len(args) != 1 {
+		return ctx.NewError(pos, "wrong number of arguments: got %d, want %d", len(args), 1)
+	}
+	a0, err := ffibridge.ToValue[time.Duration](args[0])
+	if err != nil {
+		return ctx.NewError(pos, "argument %d type mismatch: %v", 1, err)
+	}
+	r0 := a0.String()
+	return &object.String{Value: r0}
+}
+
+func bindMaxUint64ForTest(ctx *object.BuiltinContext, pos token.Pos, args ...object.Object) (ret object.Object) {
+	defer ffibridge.Recover(&ret)
+	if len(args) != 0 {
+		return ctx.NewError(pos, "wrong number of arguments: got %d, want %d", len(args), 0)
+	}
+	r0 := uint64(math.MaxUint64)
+	return ffibridge.FromValue(r0)
+}
+
+func bindFormatUintForTest(ctx *object.BuiltinContext, pos token.Pos, args ...object.Object) (ret object.Object) {
+	defer ffibridge.Recover(&ret)
+	if len(args) != 1 {
+		return ctx.NewError(pos, "wrong number of arguments: got %d, want %d", len(args), 1)
+	}
+	a0, err := ffibridge.ToUint64(args[0])
+	if err != nil {
+		return ctx.NewError(pos, "argument %d type mismatch: %v", 1, err)
+	}
+	r0 := strconv.FormatUint(a0, 10)
+	return &object.String{Value: r0}
+}
+
+func TestGoInterop_GeneratedBindings(t *testing.T) {
+	register := func(interp *Interpreter) {
+		interp.Register("example.com/bound", map[string]any{
+			"Repeat":         &object.Builtin{Fn: bindRepeatForTest},
+			"Join":           &object.Builtin{Fn: bindJoinForTest},
+			"ParseDuration":  &object.Builtin{Fn: bindParseDurationForTest},
+			"DurationString": &object.Builtin{Fn: bindDurationStringForTest},
+			"MaxUint64":      &object.Builtin{Fn: bindMaxUint64ForTest},
+			"FormatUint":     &object.Builtin{Fn: bindFormatUintForTest},
+		})
+	}
+
+	tests := []struct {
+		name    string
+		script  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "arguments are converted to the declared types",
+			script: `var result = bound.Repeat("ab", 3)`,
+			want:   "ababab",
+		},
+		{
+			name: "error results are returned as values",
+			script: `
+var result = check()
+
+func check() string {
+	d, err := bound.ParseDuration("1m30s")
+	_, err2 := bound.ParseDuration("x")
+	if err != nil || err2 == nil {
+		return "unexpected errors"
+	}
+	return bound.DurationString(d)
+}`,
+			want: "1m30s",
+		},
+		{
+			name:   "variadic arguments",
+			script: `var result = fmt.Sprintf("%v", bound.Join(nil, nil) == nil)`,
+			want:   "true",
+		},
+		{
+			name:   "unsigned results keep their kind",
+			script: `var result = fmt.Sprintf("%v %v %v", bound.MaxUint64(), bound.MaxUint64()/2, bound.FormatUint(bound.MaxUint64()))`,
+			want:   "18446744073709551615 9223372036854775807 18446744073709551615",
+		},
+		{
+			name:    "unsigned argument overflowing an integer parameter",
+			script:  `var result = bound.Repeat("a", bound.MaxUint64())`,
+			wantErr: "argument 2 type mismatch: cannot convert 18446744073709551615 to integer: overflows int64",
+		},
+		{
+			name:    "negative argument for an unsigned parameter",
+			script:  `var result = bound.FormatUint(-1)`,
+			wantErr: "argument 1 type mismatch: cannot convert -1 to unsigned integer: negative value",
+		},
+		{
+			name:    "argument type mismatch",
+			script:  `var result = bound.Repeat(1, 2)`,
+			wantErr: "argument 1 type mismatch: cannot convert INTEGER to string",
+		},
+		{
+			name:    "wrong number of arguments",
+			script:  `var result = bound.Repeat("a")`,
+			wantErr: "wrong number of arguments: got 1, want 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := newTestInterpreter(t)
+			register(interp)
+			interp.Register("fmt", map[string]any{"Sprintf": fmt.Sprintf})
+
+			script := "package main\n\nimport (\n\t\"fmt\"\n\t\"example.com/bound\"\n)\n\nvar _ = fmt.Sprintf\n" + tt.script
+			if err := interp.LoadFile("test.mgo", []byte(script)); err != nil {
+				t.Fatalf("LoadFile() failed: %v", err)
+			}
+			_, err := interp.Eval(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Eval() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() returned an error: %v", err)
+			}
+
+			res, ok := interp.GlobalEnvForTest().Get("result")
+			if !ok {
+				t.Fatal("result not found in global environment")
+			}
+			str, ok := res.(*object.String)
+			if !ok {
+				t.Fatalf("result is not a String object, but %T (%s)", res, res.Inspect())
+			}
+			if str.Value != tt.want {
+				t.Errorf("wrong result value. got=%q, want=%q", str.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoInterop_ToValueWithUnusableGoValue(t *testing.T) {
+	unexported := reflect.ValueOf(struct{ n int }{n: 1}).Field(0)
+	for name, obj := range map[string]object.Object{
+		"invalid":    &object.GoValue{},
+		"unexported": &object.GoValue{Value: unexported},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ffibridge.ToValue[int](obj)
+			if err == nil || !strings.Contains(err.Error(), "the Go value is invalid or unexported") {
+				t.Errorf("ToValue() error = %v, want an error for the unusable value", err)
+			}
+		})
+	}
+}
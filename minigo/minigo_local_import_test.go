@@ -0,0 +1,193 @@
+package minigo_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/minigo"
+	"github.com/podhmo/go-scan/minigo/object"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestLocalImport(t *testing.T) {
+	dir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"./helpers"
+	u "./lib"
+)
+
+func main() string {
+	cfg := helpers.Config{Name: "app"}
+	return helpers.Describe(cfg) + " " + u.Version
+}
+`,
+		// The files of a package keep their own imports: "s" is another package in each file.
+		"helpers/describe.go": `package helpers
+
+import s "../shared"
+
+func Describe(cfg Config) string {
+	return s.Quote(cfg.Name) + ":" + port()
+}
+`,
+		"helpers/config.go": `package helpers
+
+import s "../lib"
+
+type Config struct {
+	Name string
+}
+
+func port() string { return s.Version }
+`,
+		"lib/lib.go": `package util
+
+import "../shared"
+
+var Version = shared.Quote("v1")
+`,
+		"shared/shared.go": `package shared
+
+func Quote(s string) string { return "<" + s + ">" }
+`,
+		"cycle/main.go": `package main
+
+import "./a"
+
+func main() string { return a.A() }
+`,
+		"cycle/a/a.go": `package a
+
+import "../b"
+
+func A() string { return b.B() }
+`,
+		"cycle/b/b.go": `package b
+
+import "../a"
+
+func B() string { return a.A() }
+`,
+	})
+	defer cleanup()
+
+	ctx := context.Background()
+	s, err := goscan.New(goscan.WithGoModuleResolver())
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	run := func(t *testing.T, filename string, opts minigo.Options) (string, error) {
+		t.Helper()
+		source, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("reading %s: %v", filename, err)
+		}
+		opts.Source, opts.Filename, opts.Scanner = source, filename, s
+		result, err := minigo.Run(ctx, opts)
+		if err != nil {
+			return "", err
+		}
+		var got string
+		if err := result.As(&got); err != nil {
+			t.Fatalf("result.As() failed: %v", err)
+		}
+		return got, nil
+	}
+
+	t.Run("packages", func(t *testing.T) {
+		got, err := run(t, filepath.Join(dir, "main.go"), minigo.Options{})
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if want := "<app>:<v1> <v1>"; got != want {
+			t.Errorf("want %q, got %q", want, got)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := run(t, filepath.Join(dir, "cycle", "main.go"), minigo.Options{})
+		if err == nil || !strings.Contains(err.Error(), "import cycle not allowed") {
+			t.Fatalf("expected an import cycle error, got %v", err)
+		}
+		want := strings.Join([]string{filepath.Join(dir, "cycle", "a"), filepath.Join(dir, "cycle", "b"), filepath.Join(dir, "cycle", "a")}, " -> ")
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the cycle %q, got %v", want, err)
+		}
+	})
+
+	t.Run("sandbox", func(t *testing.T) {
+		_, err := run(t, filepath.Join(dir, "main.go"), minigo.Options{Sandbox: &minigo.Sandbox{NoFilesystem: true}})
+		if err == nil || !strings.Contains(err.Error(), `local import "./helpers" is not allowed`) {
+			t.Errorf("expected the local import to be denied, got %v", err)
+		}
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		cache := minigo.NewScriptCache()
+		filename := filepath.Join(dir, "shared", "shared.go")
+		if _, err := run(t, filepath.Join(dir, "main.go"), minigo.Options{ScriptCache: cache}); err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		info, err := os.Stat(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// A change keeping the modification time and the size is not seen: the file is cached.
+		original, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		changed := strings.Replace(string(original), `"<" + s + ">"`, `"[" + s + "]"`, 1)
+		if err := os.WriteFile(filename, []byte(changed), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filename, info.ModTime(), info.ModTime()); err != nil {
+			t.Fatal(err)
+		}
+		got, err := run(t, filepath.Join(dir, "main.go"), minigo.Options{ScriptCache: cache})
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if want := "<app>:<v1> <v1>"; got != want {
+			t.Errorf("expected the cached file, want %q, got %q", want, got)
+		}
+
+		// A file modified since it was parsed is parsed again.
+		if err := os.Chtimes(filename, info.ModTime().Add(time.Second), info.ModTime().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		got, err = run(t, filepath.Join(dir, "main.go"), minigo.Options{ScriptCache: cache})
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if want := "[app]:[v1] [v1]"; got != want {
+			t.Errorf("expected the modified file, want %q, got %q", want, got)
+		}
+	})
+
+	t.Run("error in package", func(t *testing.T) {
+		broken, cleanup := scantest.WriteFiles(t, map[string]string{
+			"main.go":       "package main\n\nimport \"./conf\"\n\nfunc main() int { return conf.N }\n",
+			"conf/conf.go":  "package conf\n\nvar N = undefinedFunc()\n",
+			"conf/other.go": "package other\n",
+		})
+		defer cleanup()
+		_, err := run(t, filepath.Join(broken, "main.go"), minigo.Options{})
+		if err == nil || !strings.Contains(err.Error(), "found packages conf and other") {
+			t.Errorf("expected the mixed packages to be reported, got %v", err)
+		}
+		var sandboxErr *minigo.SandboxError
+		if errors.As(err, &sandboxErr) && sandboxErr.Kind != object.ErrorKindImportDenied {
+			t.Errorf("unexpected sandbox error %v", sandboxErr)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package minigo_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	stdfmt "github.com/podhmo/go-scan/minigo/stdlib/fmt"
+)
+
+func TestNumericTypes(t *testing.T) {
+	cases := []struct {
+		name           string
+		script         string
+		wantErr        string
+		expectedOutput string
+	}{
+		{
+			name: "fnv-1a hash with uint32 overflow",
+			script: `
+package main
+import "fmt"
+var out string
+func fnv32a(s string) uint32 {
+	var h uint32 = 2166136261
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= 16777619
+	}
+	return h
+}
+func main() {
+	out += fmt.Sprintln(fnv32a("hello"))
+}`,
+			expectedOutput: "1335831723\n",
+		},
+		{
+			name: "conversions wrap around and truncate",
+			script: `
+package main
+import "fmt"
+var out string
+func main() {
+	n, big, f := 200, 70000, 3.9
+	out += fmt.Sprintln(int8(n), uint16(big), int(f), int(-f))
+}`,
+			expectedOutput: "-56 4464 3 -3\n",
+		},
+		{
+			name: "unsigned division and shifts",
+			script: `
+package main
+import "fmt"
+var out string
+func main() {
+	var u uint64 = 1<<63 + 1
+	out += fmt.Sprintln(u, u/3, u>>62)
+	x := -7
+	out += fmt.Sprintln(x/2, x%2, x>>1)
+}`,
+			expectedOutput: "9223372036854775809 3074457345618258603 2\n-3 -1 -4\n",
+		},
+		{
+			name: "byte arithmetic",
+			script: `
+package main
+import "fmt"
+var out string
+func main() {
+	var b uint8 = 250
+	b += 10
+	var m uint8 = 255
+	m++
+	out += fmt.Sprintln(b, m, ^m, -b)
+}`,
+			expectedOutput: "4 0 255 252\n",
+		},
+		{
+			name: "float32 precision",
+			script: `
+package main
+import "fmt"
+var out string
+func main() {
+	var f32 float32 = 0.1
+	out += fmt.Sprintln(f32, float64(f32) == 0.1, f32/3)
+}`,
+			expectedOutput: "0.1 false 0.033333335\n",
+		},
+		{
+			name: "runes and bytes",
+			script: `
+package main
+import "fmt"
+var out string
+func main() {
+	var r rune = 'é'
+	out += fmt.Sprintln(string(r), []byte("é")[0], string([]rune("héllo")[1]))
+}`,
+			expectedOutput: "é 195 é\n",
+		},
+		{
+			name: "complex64",
+			script: `
+package main
+import "fmt"
+var out string
+func main() {
+	var f32 float32 = 0.1
+	c := complex(f32, 2)
+	out += fmt.Sprintln(c*c, real(c))
+}`,
+			expectedOutput: "(-3.99+0.4i) 0.1\n",
+		},
+		{
+			name: "types",
+			script: `
+package main
+import "fmt"
+var out string
+func half(x uint16) uint16 { return x / 2 }
+func main() {
+	var b byte
+	var f float32
+	out += fmt.Sprintf("%T %T %T %T\n", b, f, complex(f, 0), half(3))
+}`,
+			expectedOutput: "uint8 float32 complex64 uint16\n",
+		},
+		{
+			name: "negative shift amount",
+			script: `
+package main
+func main() {
+	n := -1
+	_ = 1 << n
+}`,
+			wantErr: "negative shift amount",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := newTestInterpreter(t)
+			stdfmt.Install(interp)
+
+			if err := interp.LoadFile("test.mgo", []byte(tt.script)); err != nil {
+				t.Fatalf("LoadFile() unexpected error = %v", err)
+			}
+
+			_, err := interp.Eval(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Eval() error = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() unexpected error = %v", err)
+			}
+			out, ok := interp.GlobalEnvForTest().Get("out")
+			if !ok {
+				t.Fatalf("variable 'out' not found in global scope")
+			}
+			if got := out.Inspect(); got != tt.expectedOutput {
+				t.Errorf("expected output %q, but got %q", tt.expectedOutput, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package minigo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/podhmo/go-scan/minigo"
+	"github.com/podhmo/go-scan/minigo/object"
+	stdstrings "github.com/podhmo/go-scan/minigo/stdlib/strings"
+)
+
+func TestRun_Sandbox(t *testing.T) {
+	cases := []struct {
+		name     string
+		script   string
+		sandbox  minigo.Sandbox
+		register func(interp *minigo.Interpreter)
+		wantKind object.ErrorKind
+		wantMsg  string
+	}{
+		{
+			name: "infinite loop",
+			script: `
+package main
+
+func main() {
+	n := 0
+	for {
+		n++
+	}
+}
+`,
+			sandbox:  minigo.Sandbox{MaxSteps: 1000},
+			wantKind: object.ErrorKindStepLimit,
+			wantMsg:  "evaluation step limit exceeded (max 1000)",
+		},
+		{
+			name: "growing slice",
+			script: `
+package main
+
+func main() {
+	var xs []int
+	for i := 0; i < 1000; i++ {
+		xs = append(xs, i)
+	}
+}
+`,
+			sandbox:  minigo.Sandbox{MaxObjects: 100},
+			wantKind: object.ErrorKindObjectLimit,
+			wantMsg:  "object allocation limit exceeded (max 100)",
+		},
+		{
+			name: "growing string",
+			script: `
+package main
+
+func main() {
+	s := "x"
+	for i := 0; i < 64; i++ {
+		s += s
+	}
+}
+`,
+			sandbox:  minigo.Sandbox{MaxObjects: 1000},
+			wantKind: object.ErrorKindObjectLimit,
+			wantMsg:  "object allocation limit exceeded (max 1000)",
+		},
+		{
+			name: "import not in the allowlist",
+			script: `
+package main
+
+import "os"
+
+func main() {}
+`,
+			sandbox:  minigo.Sandbox{AllowedImports: []string{"strings"}},
+			wantKind: object.ErrorKindImportDenied,
+			wantMsg:  `import of package "os" is not allowed`,
+		},
+		{
+			name: "os package without filesystem",
+			script: `
+package main
+
+import "os"
+
+func main() {
+	os.Getenv("HOME")
+}
+`,
+			sandbox: minigo.Sandbox{NoFilesystem: true},
+			register: func(interp *minigo.Interpreter) {
+				interp.Register("os", map[string]any{"Getenv": func(string) string { return "" }})
+			},
+			wantKind: object.ErrorKindImportDenied,
+			wantMsg:  `use of package "os" is not allowed`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			interp := newTestInterpreter(t, minigo.WithSandbox(tc.sandbox))
+			if tc.register != nil {
+				tc.register(interp)
+			}
+			if err := interp.LoadFile("main.go", []byte(tc.script)); err != nil {
+				t.Fatalf("LoadFile() failed: %v", err)
+			}
+			_, err := interp.Eval(context.Background())
+
+			var sandboxErr *minigo.SandboxError
+			if !errors.As(err, &sandboxErr) {
+				t.Fatalf("expected a SandboxError, got %v", err)
+			}
+			if sandboxErr.Kind != tc.wantKind {
+				t.Errorf("Kind = %q, want %q", sandboxErr.Kind, tc.wantKind)
+			}
+			if sandboxErr.Message != tc.wantMsg {
+				t.Errorf("Message = %q, want %q", sandboxErr.Message, tc.wantMsg)
+			}
+			if sandboxErr.Position.Filename != "main.go" {
+				t.Errorf("unexpected position %v", sandboxErr.Position)
+			}
+		})
+	}
+}
+
+func TestRun_SandboxAllowsScriptsWithinLimits(t *testing.T) {
+	script := `
+package main
+
+import "strings"
+
+func main() string {
+	words := []string{"a", "b", "c"}
+	return strings.Join(words, ",")
+}
+`
+	interp := newTestInterpreter(t, minigo.WithSandbox(minigo.Sandbox{
+		MaxSteps:       1000,
+		MaxObjects:     100,
+		AllowedImports: []string{"strings"},
+		NoFilesystem:   true,
+	}))
+	stdstrings.Install(interp)
+	if err := interp.LoadFile("main.go", []byte(script)); err != nil {
+		t.Fatalf("LoadFile() failed: %v", err)
+	}
+	result, err := interp.Eval(context.Background())
+	if err != nil {
+		t.Fatalf("Eval() failed: %v", err)
+	}
+	if got := result.Value.Inspect(); got != "a,b,c" {
+		t.Errorf("unexpected result %q", got)
+	}
+}
@@ -0,0 +1,114 @@
+package minigo_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/minigo"
+	stdgoscan "github.com/podhmo/go-scan/minigo/stdlib/goscan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestStdlib_goscan(t *testing.T) {
+	dir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"models/models.go": `package models
+
+// User is a user.
+// @deriving:json
+// @table users
+type User struct {
+	ID   int    ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+
+func (u *User) Validate() error { return nil }
+
+type Role string
+
+// NewUser creates a user.
+func NewUser(name string) *User { return &User{Name: name} }
+`,
+	})
+	defer cleanup()
+
+	script := `
+package main
+
+import "goscan"
+
+func main() {
+	pkgs, err := goscan.Scan("./models")
+	if err != nil {
+		panic(err)
+	}
+	for _, pkg := range pkgs {
+		println(pkg.Name, pkg.ImportPath)
+		for _, t := range goscan.Types(pkg) {
+			println(t.Kind, t.Name)
+			for _, f := range t.Fields {
+				println("  field", f.Name, f.Type, f.Tag)
+			}
+			for _, m := range t.Methods {
+				println("  method", m.Name)
+			}
+			for name, value := range goscan.Annotations(t) {
+				if name == "table" {
+					println("  table", value)
+				}
+			}
+			if _, ok := goscan.Annotation(t, "deriving:json"); ok {
+				println("  deriving:json")
+			}
+		}
+		for _, fn := range goscan.Functions(pkg) {
+			println("func", fn.Receiver, fn.Name, len(fn.Params), len(fn.Results))
+		}
+	}
+}
+`
+	s, err := goscan.New(goscan.WithWorkDir(dir))
+	if err != nil {
+		t.Fatalf("failed to create scanner: %v", err)
+	}
+	var stdout bytes.Buffer
+	interp, err := minigo.NewInterpreter(s, minigo.WithStdout(&stdout))
+	if err != nil {
+		t.Fatalf("NewInterpreter() failed: %v", err)
+	}
+	stdgoscan.Install(interp)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if err := interp.LoadFile("main.go", []byte(script)); err != nil {
+		t.Fatalf("LoadFile() failed: %v", err)
+	}
+	if _, err := interp.Eval(context.Background()); err != nil {
+		t.Fatalf("Eval() failed: %v", err)
+	}
+
+	want := `models example.com/app/models
+struct User
+  field ID int json:"id"
+  field Name string json:"name"
+  method Validate
+  table users
+  deriving:json
+alias Role
+func *User Validate 0 1
+func  NewUser 1 1
+`
+	if got := stdout.String(); got != want {
+		t.Errorf("stdout\n got: %q\nwant: %q", got, want)
+	}
+}
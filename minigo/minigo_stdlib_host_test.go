@@ -0,0 +1,106 @@
+package minigo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/minigo"
+	stdos "github.com/podhmo/go-scan/minigo/stdlib/os"
+	stdtime "github.com/podhmo/go-scan/minigo/stdlib/time"
+)
+
+func TestStdlib_time_WithClock(t *testing.T) {
+	script := `
+package main
+
+import "time"
+
+func main() (any, any, any) {
+	start := time.Date(2024, time.July, 26, 10, 0, 0, 0, time.UTC)
+	return time.Now(), time.Since(start), time.Until(start)
+}
+`
+	now := time.Date(2024, time.July, 26, 10, 30, 0, 0, time.UTC)
+	interp := newTestInterpreter(t, minigo.WithClock(func() time.Time { return now }))
+	stdtime.Install(interp)
+
+	if err := interp.LoadFile("main.go", []byte(script)); err != nil {
+		t.Fatalf("LoadFile() failed: %v", err)
+	}
+	result, err := interp.Eval(context.Background())
+	if err != nil {
+		t.Fatalf("Eval() failed: %+v", err)
+	}
+
+	var got struct {
+		Now   time.Time
+		Since time.Duration
+		Until time.Duration
+	}
+	if err := result.As(&got); err != nil {
+		t.Fatalf("As() failed: %v", err)
+	}
+	if !got.Now.Equal(now) {
+		t.Errorf("time.Now() = %v, want %v", got.Now, now)
+	}
+	if got.Since != 30*time.Minute || got.Until != -30*time.Minute {
+		t.Errorf("time.Since() = %v, time.Until() = %v, want 30m0s and -30m0s", got.Since, got.Until)
+	}
+}
+
+func TestStdlib_os_Env(t *testing.T) {
+	script := `
+package main
+
+import "os"
+
+func main() (any, any, any, any, any) {
+	_, hasSecret := os.LookupEnv("SECRET")
+	setErr := os.Setenv("SECRET", "x")
+	os.Setenv("APP_MODE", "test")
+	return os.Getenv("APP_NAME"), hasSecret, setErr != nil, os.ExpandEnv("$APP_NAME-$APP_MODE$SECRET"), os.Environ()
+}
+`
+	interp := newTestInterpreter(t,
+		minigo.WithEnv(map[string]string{"APP_NAME": "demo", "SECRET": "s3cr3t"}),
+		minigo.WithSandbox(minigo.Sandbox{AllowedEnv: []string{"APP_*"}}),
+	)
+	stdos.Install(interp)
+
+	if err := interp.LoadFile("main.go", []byte(script)); err != nil {
+		t.Fatalf("LoadFile() failed: %v", err)
+	}
+	result, err := interp.Eval(context.Background())
+	if err != nil {
+		t.Fatalf("Eval() failed: %+v", err)
+	}
+
+	var got struct {
+		Name      string
+		HasSecret bool
+		SetFailed bool
+		Expanded  string
+		Environ   []string
+	}
+	if err := result.As(&got); err != nil {
+		t.Fatalf("As() failed: %v", err)
+	}
+	want := struct {
+		Name      string
+		HasSecret bool
+		SetFailed bool
+		Expanded  string
+		Environ   []string
+	}{
+		Name:      "demo",
+		HasSecret: false,
+		SetFailed: true,
+		Expanded:  "demo-test",
+		Environ:   []string{"APP_MODE=test", "APP_NAME=demo"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,164 @@
+package minigo_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/minigo"
+)
+
+// TestStructEmbedding tests promoted fields and methods of embedded structs.
+func TestStructEmbedding(t *testing.T) {
+	cases := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name: "promoted field",
+			source: `
+package main
+
+type Base struct { Name string }
+type Config struct {
+	Base
+	Port int
+}
+
+func main() {
+	cfg := Config{Base: Base{Name: "app"}, Port: 8080}
+	println(cfg.Name, cfg.Base.Name, cfg.Port)
+}
+`,
+			want: "app app 8080\n",
+		},
+		{
+			name: "assign to promoted field of zero value",
+			source: `
+package main
+
+type Base struct { Name string }
+type Config struct { Base }
+
+func main() {
+	var cfg Config
+	cfg.Name = "app"
+	println(cfg.Base.Name)
+}
+`,
+			want: "app\n",
+		},
+		{
+			name: "promoted methods",
+			source: `
+package main
+
+type Base struct { Name string }
+func (b Base) Hello() string { return "hello " + b.Name }
+func (b *Base) Rename(name string) { b.Name = name }
+
+type Config struct { Base }
+
+func main() {
+	cfg := &Config{Base: Base{Name: "app"}}
+	cfg.Rename("server")
+	println(cfg.Hello(), cfg.Name)
+}
+`,
+			want: "hello server server\n",
+		},
+		{
+			name: "embedded pointer and multiple levels",
+			source: `
+package main
+
+type A struct { X int }
+func (a *A) Inc() { a.X++ }
+
+type B struct { *A }
+type C struct { B }
+
+func main() {
+	c := C{B: B{A: &A{X: 1}}}
+	c.Inc()
+	println(c.X, c.A.X)
+}
+`,
+			want: "2 2\n",
+		},
+		{
+			name: "method of outer struct shadows promoted one",
+			source: `
+package main
+
+type Base struct {}
+func (b Base) Name() string { return "base" }
+
+type Config struct { Base }
+func (c Config) Name() string { return "config" }
+
+func main() {
+	cfg := Config{}
+	println(cfg.Name(), cfg.Base.Name())
+}
+`,
+			want: "config base\n",
+		},
+		{
+			name: "interface satisfied by promoted method",
+			source: `
+package main
+
+type Namer interface { Name() string }
+
+type Base struct { name string }
+func (b Base) Name() string { return b.name }
+
+type Config struct { Base }
+
+func main() {
+	var n Namer = Config{Base: Base{name: "app"}}
+	println(n.Name())
+}
+`,
+			want: "app\n",
+		},
+		{
+			name: "value receiver gets a copy of the embedded struct",
+			source: `
+package main
+
+type Base struct { Name string }
+type Config struct { Base }
+func (c Config) Renamed() Config {
+	c.Name = "copy"
+	return c
+}
+
+func main() {
+	cfg := Config{Base: Base{Name: "orig"}}
+	renamed := cfg.Renamed()
+	println(cfg.Name, renamed.Name)
+}
+`,
+			want: "orig copy\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			m := newTestInterpreter(t,
+				minigo.WithStdout(&out),
+			)
+
+			if _, err := m.EvalString(tc.source); err != nil {
+				t.Fatalf("EvalString failed: %+v", err)
+			}
+			if diff := cmp.Diff(tc.want, out.String()); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/podhmo/go-scan/scanner"
@@ -130,16 +131,63 @@ type Object interface {
 
 // --- Integer Object ---
 
-// Integer represents an integer value.
+// Integer represents an integer value. Kind is its Go type, e.g. reflect.Uint8, the zero value
+// standing for int (the type of the untyped constants); the value of an unsigned integer holds
+// the bits of its uint64 value. Use NewInteger to get the value of a kind wrapped around.
 type Integer struct {
 	Value int64
+	Kind  reflect.Kind
+}
+
+// NewInteger returns the integer of the kind, wrapping the value around as a conversion in Go
+// does, e.g. 44 for uint8(300).
+func NewInteger(kind reflect.Kind, value int64) *Integer {
+	switch kind {
+	case reflect.Int8:
+		value = int64(int8(value))
+	case reflect.Int16:
+		value = int64(int16(value))
+	case reflect.Int32:
+		value = int64(int32(value))
+	case reflect.Uint8:
+		value = int64(uint8(value))
+	case reflect.Uint16:
+		value = int64(uint16(value))
+	case reflect.Uint32:
+		value = int64(uint32(value))
+	case reflect.Int:
+		kind = reflect.Invalid
+	}
+	return &Integer{Value: value, Kind: kind}
+}
+
+// GoKind returns the Go type of the integer, reflect.Int for the zero Kind.
+func (i *Integer) GoKind() reflect.Kind {
+	if i.Kind == reflect.Invalid {
+		return reflect.Int
+	}
+	return i.Kind
+}
+
+// IsUnsigned reports whether the integer is of an unsigned type.
+func (i *Integer) IsUnsigned() bool {
+	switch i.Kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
 }
 
 // Type returns the type of the Integer object.
 func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
 
 // Inspect returns a string representation of the Integer's value.
-func (i *Integer) Inspect() string { return fmt.Sprintf("%d", i.Value) }
+func (i *Integer) Inspect() string {
+	if i.IsUnsigned() {
+		return strconv.FormatUint(uint64(i.Value), 10)
+	}
+	return strconv.FormatInt(i.Value, 10)
+}
 
 // HashKey returns the hash key for an Integer.
 func (i *Integer) HashKey() HashKey {
@@ -148,16 +196,39 @@ func (i *Integer) HashKey() HashKey {
 
 // --- Float Object ---
 
-// Float represents a floating-point number.
+// Float represents a floating-point number. Kind is reflect.Float32 for a float32, and the zero
+// value for a float64. Use NewFloat to get a float32 rounded to its precision.
 type Float struct {
 	Value float64
+	Kind  reflect.Kind
+}
+
+// NewFloat returns the float of the kind, rounding the value of a float32.
+func NewFloat(kind reflect.Kind, value float64) *Float {
+	if kind == reflect.Float32 {
+		return &Float{Value: float64(float32(value)), Kind: kind}
+	}
+	return &Float{Value: value}
+}
+
+// GoKind returns the Go type of the float, reflect.Float64 for the zero Kind.
+func (f *Float) GoKind() reflect.Kind {
+	if f.Kind == reflect.Float32 {
+		return reflect.Float32
+	}
+	return reflect.Float64
 }
 
 // Type returns the type of the Float object.
 func (f *Float) Type() ObjectType { return FLOAT_OBJ }
 
 // Inspect returns a string representation of the Float's value.
-func (f *Float) Inspect() string { return fmt.Sprintf("%g", f.Value) }
+func (f *Float) Inspect() string {
+	if f.Kind == reflect.Float32 {
+		return fmt.Sprintf("%g", float32(f.Value))
+	}
+	return fmt.Sprintf("%g", f.Value)
+}
 
 // HashKey returns the hash key for a Float.
 func (f *Float) HashKey() HashKey {
@@ -168,10 +239,28 @@ func (f *Float) HashKey() HashKey {
 
 // --- Complex Object ---
 
-// Complex represents a complex number.
+// Complex represents a complex number. Kind is reflect.Complex64 for a complex64, and the zero
+// value for a complex128. Use NewComplex to get a complex64 rounded to its precision.
 type Complex struct {
 	Real float64
 	Imag float64
+	Kind reflect.Kind
+}
+
+// NewComplex returns the complex number of the kind, rounding the parts of a complex64.
+func NewComplex(kind reflect.Kind, real, imag float64) *Complex {
+	if kind == reflect.Complex64 {
+		return &Complex{Real: float64(float32(real)), Imag: float64(float32(imag)), Kind: kind}
+	}
+	return &Complex{Real: real, Imag: imag}
+}
+
+// GoKind returns the Go type of the complex number, reflect.Complex128 for the zero Kind.
+func (c *Complex) GoKind() reflect.Kind {
+	if c.Kind == reflect.Complex64 {
+		return reflect.Complex64
+	}
+	return reflect.Complex128
 }
 
 // Type returns the type of the Complex object.
@@ -276,6 +365,9 @@ func (rv *ReturnValue) Inspect() string { return rv.Value.Inspect() }
 // Panic represents a panic signal. It wraps the value passed to panic().
 type Panic struct {
 	Value Object
+	// GoStack is the Go stack trace of a panic raised by a Go function called from the script,
+	// and empty for the panics of the script.
+	GoStack string
 }
 
 // Type returns the type of the Panic object.
@@ -377,15 +469,22 @@ func (mt *MapType) Inspect() string {
 }
 
 // Copy creates a shallow copy of the struct instance.
+// Embedded struct values are copied as well, as they are part of the struct.
 func (si *StructInstance) Copy() *StructInstance {
 	newFields := make(map[string]Object, len(si.Fields))
 	for k, v := range si.Fields {
 		// Note: This is a shallow copy of the field values.
 		newFields[k] = v
 	}
+	for _, name := range si.Def.EmbeddedFields() {
+		if embedded, ok := newFields[name].(*StructInstance); ok {
+			newFields[name] = embedded.Copy()
+		}
+	}
 	return &StructInstance{
-		Def:    si.Def,
-		Fields: newFields,
+		Def:      si.Def,
+		TypeArgs: si.TypeArgs,
+		Fields:   newFields,
 	}
 }
 
@@ -405,6 +504,10 @@ type BuiltinContext struct {
 	GetPanic         func() *Panic
 	ClearPanic       func()
 	NewError         func(pos token.Pos, format string, args ...interface{}) *Error
+	ZeroValue        func(typeObj Object) Object // Creates the zero value of a resolved type.
+	// Allocate counts n new objects against the object limit of the sandbox, if any.
+	// It returns an error if the limit is exceeded.
+	Allocate func(pos token.Pos, n int) *Error
 }
 
 // BuiltinFunction is the signature for built-in functions.
@@ -442,6 +545,43 @@ type StructDefinition struct {
 // Type returns the type of the StructDefinition object.
 func (sd *StructDefinition) Type() ObjectType { return STRUCT_DEFINITION_OBJ }
 
+// EmbeddedFields returns the names of the embedded fields, in the order they are defined.
+func (sd *StructDefinition) EmbeddedFields() []string {
+	var names []string
+	for _, field := range sd.Fields {
+		if name, ok := EmbeddedFieldName(field); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// EmbeddedFieldName returns the name of an embedded field, which is the name of its type
+// without the pointer and the package qualifier (e.g. `T` for `T`, `*T` and `pkg.T`).
+// It returns false if the field is not an embedded one.
+func EmbeddedFieldName(field *ast.Field) (string, bool) {
+	if len(field.Names) > 0 {
+		return "", false
+	}
+	typ := field.Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr: // generic type, e.g. `T[int]`
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return t.Name, true
+	case *ast.SelectorExpr:
+		return t.Sel.Name, true
+	}
+	return "", false
+}
+
 // Inspect returns a string representation of the struct definition.
 func (sd *StructDefinition) Inspect() string {
 	return fmt.Sprintf("struct %s", sd.Name.String())
@@ -665,17 +805,19 @@ func (t *Tuple) Inspect() string {
 
 // FileScope holds the AST and file-specific import aliases for a single file.
 type FileScope struct {
-	AST        *ast.File
-	Aliases    map[string]string // alias -> import path
-	DotImports []string          // list of package paths for dot imports
+	AST          *ast.File
+	Aliases      map[string]string // alias -> import path
+	DotImports   []string          // list of package paths for dot imports
+	LocalImports map[string]string // local import path, e.g. "./helpers" -> path of the loaded script package
 }
 
 // NewFileScope creates a new file scope.
 func NewFileScope(ast *ast.File) *FileScope {
 	return &FileScope{
-		AST:        ast,
-		Aliases:    make(map[string]string),
-		DotImports: make([]string, 0),
+		AST:          ast,
+		Aliases:      make(map[string]string),
+		DotImports:   make([]string, 0),
+		LocalImports: make(map[string]string),
 	}
 }
 
@@ -790,12 +932,26 @@ func (f *GoSourceFunction) Inspect() string {
 
 // --- Error Object ---
 
+// ErrorKind classifies the errors raised by the sandbox controls of the evaluator.
+type ErrorKind string
+
+const (
+	// ErrorKindStepLimit is the kind of the error raised when the evaluation steps exceed the limit.
+	ErrorKindStepLimit ErrorKind = "step-limit"
+	// ErrorKindObjectLimit is the kind of the error raised when the allocated objects exceed the limit.
+	ErrorKindObjectLimit ErrorKind = "object-limit"
+	// ErrorKindImportDenied is the kind of the error raised when a package which is not allowed is used.
+	ErrorKindImportDenied ErrorKind = "import-denied"
+)
+
 // Error represents a runtime error. It contains a message and a call stack.
 type Error struct {
 	Pos       token.Pos
 	Message   string
 	CallStack []*CallFrame
-	fset      *token.FileSet // FileSet to resolve positions
+	// Kind is set for the errors raised by the sandbox controls, and empty otherwise.
+	Kind ErrorKind
+	fset *token.FileSet // FileSet to resolve positions
 }
 
 // Type returns the type of the Error object.
@@ -842,6 +998,14 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// Position returns the position of the error, or the zero value if it is unknown.
+func (e *Error) Position() token.Position {
+	if e.fset == nil || !e.Pos.IsValid() {
+		return token.Position{}
+	}
+	return e.fset.Position(e.Pos)
+}
+
 // --- AstNode Object ---
 
 // AstNode wraps a go/ast.Node. This is used to pass AST fragments
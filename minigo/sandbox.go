@@ -0,0 +1,80 @@
+package minigo
+
+import (
+	"fmt"
+	"go/token"
+
+	"github.com/podhmo/go-scan/minigo/object"
+)
+
+// Sandbox holds the safety controls of the interpreter, for running untrusted scripts such as
+// user-supplied configuration. The zero value has no limits.
+type Sandbox struct {
+	// MaxSteps limits the number of evaluation steps (AST nodes) of each execution, so that an
+	// infinite loop stops with an error. 0 means unlimited.
+	MaxSteps int
+
+	// MaxObjects limits the number of objects allocated by each execution with composite
+	// literals, make, new and append, counting each element, and with the concatenation of
+	// strings (+ and +=), counting each byte of the result. The values returned by the Go
+	// functions, e.g. strings.Repeat, are not counted. 0 means unlimited.
+	MaxObjects int
+
+	// AllowedImports is the list of the packages a script can import, as import path patterns,
+	// e.g. "strings" or "example.com/config/...". The packages registered with Register are
+	// imported like the others. nil means any package.
+	AllowedImports []string
+
+	// NoFilesystem denies the packages touching the operating system (os, net, syscall, ...),
+	// even when they are registered or used by a package loaded from source.
+	NoFilesystem bool
+
+	// AllowedEnv is the list of the environment variables a script can read and write through
+	// the registered "os" package, as names or patterns, e.g. "HOME" or "APP_*". The other
+	// variables are unset for the script, and setting them fails. nil means any variable.
+	AllowedEnv []string
+}
+
+// osPackages are the packages denied by Sandbox.NoFilesystem.
+var osPackages = []string{
+	"os/...",
+	"io/ioutil",
+	"net/...",
+	"syscall",
+	"path/filepath",
+	"plugin",
+	"log",
+	"goscan", // minigo/stdlib/goscan reads the source files
+}
+
+func (s Sandbox) deniedImports() []string {
+	if !s.NoFilesystem {
+		return nil
+	}
+	return osPackages
+}
+
+// SandboxError is the error returned when a script exceeds a limit of the Sandbox, or uses a
+// package which is not allowed.
+type SandboxError struct {
+	// Kind is object.ErrorKindStepLimit, object.ErrorKindObjectLimit or object.ErrorKindImportDenied.
+	Kind     object.ErrorKind
+	Message  string
+	Position token.Position
+
+	err *object.Error
+}
+
+// Error returns the message of the error, with its position and call stack.
+func (e *SandboxError) Error() string {
+	return e.err.Inspect()
+}
+
+// toError converts a runtime error of a script to a Go error, a *SandboxError for the errors
+// raised by the sandbox controls.
+func toError(err *object.Error) error {
+	if err.Kind != "" {
+		return &SandboxError{Kind: err.Kind, Message: err.Message, Position: err.Position(), err: err}
+	}
+	return fmt.Errorf("%s", err.Inspect())
+}
@@ -0,0 +1,219 @@
+// Package goscan exposes go-scan to minigo scripts, so that small code generation and
+// reporting tools can be written as scripts instead of compiled Go programs:
+//
+//	import "goscan"
+//
+//	func main() {
+//		pkgs, err := goscan.Scan("./models")
+//		if err != nil {
+//			panic(err)
+//		}
+//		for _, pkg := range pkgs {
+//			for _, t := range goscan.Types(pkg) {
+//				if _, ok := goscan.Annotation(t, "deriving:json"); ok {
+//					println(t.Name)
+//				}
+//			}
+//		}
+//	}
+//
+// The packages, types and functions are plain structs with string fields, so that scripts can
+// read them without knowing the scanner's models.
+package goscan
+
+import (
+	"context"
+	"strings"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/minigo"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// Package is a scanned package.
+type Package struct {
+	Name       string
+	ImportPath string
+	Dir        string
+	Files      []string
+
+	info *scanner.PackageInfo
+}
+
+// Type is a type declared in a package.
+type Type struct {
+	Name    string
+	PkgPath string
+	Kind    string // "struct", "interface", "func", "alias" (also for `type Role string`) or "unknown"
+	Doc     string
+	Fields  []*Field // for a struct
+	Methods []*Function
+
+	info *scanner.TypeInfo
+}
+
+// Field is a field of a struct, or a parameter or a result of a function.
+type Field struct {
+	Name     string
+	Type     string
+	Tag      string
+	Doc      string
+	Embedded bool
+}
+
+// Function is a function or a method declared in a package.
+type Function struct {
+	Name     string
+	PkgPath  string
+	Doc      string
+	Receiver string // the receiver type of a method, e.g. "*User"
+	Params   []*Field
+	Results  []*Field
+}
+
+// Install binds the "goscan" package to the interpreter, scanning with its scanner.
+func Install(interp *minigo.Interpreter) {
+	s := interp.Scanner()
+	interp.Register("goscan", map[string]any{
+		"Scan": func(pattern string) ([]*Package, error) {
+			return scan(context.Background(), s, pattern)
+		},
+		"Types":       types,
+		"Functions":   functions,
+		"Annotations": annotations,
+		"Annotation": func(t *Type, name string) (string, bool) {
+			return t.info.Annotation(context.Background(), name)
+		},
+	})
+}
+
+// scan scans the packages matching the pattern, a directory or an import path, optionally
+// followed by "/...".
+func scan(ctx context.Context, s *goscan.Scanner, pattern string) ([]*Package, error) {
+	infos, err := s.Scan(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	pkgs := make([]*Package, 0, len(infos))
+	for _, info := range infos {
+		pkgs = append(pkgs, &Package{
+			Name:       info.Name,
+			ImportPath: info.ImportPath,
+			Dir:        info.Path,
+			Files:      info.Files,
+			info:       info,
+		})
+	}
+	return pkgs, nil
+}
+
+// types returns the types declared in the package, with their methods.
+func types(pkg *Package) []*Type {
+	var types []*Type
+	for _, ti := range pkg.info.Types {
+		t := &Type{
+			Name:    ti.Name,
+			PkgPath: ti.PkgPath,
+			Kind:    kindName(ti.Kind),
+			Doc:     ti.Doc,
+			info:    ti,
+		}
+		if ti.Struct != nil {
+			for _, f := range ti.Struct.Fields {
+				t.Fields = append(t.Fields, newField(f))
+			}
+		}
+		for _, fn := range pkg.info.Functions {
+			if fn.Receiver != nil && receiverTypeName(fn.Receiver) == ti.Name {
+				t.Methods = append(t.Methods, newFunction(fn))
+			}
+		}
+		types = append(types, t)
+	}
+	return types
+}
+
+// functions returns the functions and the methods declared in the package.
+func functions(pkg *Package) []*Function {
+	var fns []*Function
+	for _, fn := range pkg.info.Functions {
+		fns = append(fns, newFunction(fn))
+	}
+	return fns
+}
+
+// annotations returns all the annotations in the doc comment of the type, e.g. "deriving:json" for
+// `// @deriving:json`, or "name" with the value "v" for `// @name v`.
+func annotations(t *Type) map[string]string {
+	annotations := make(map[string]string)
+	for _, line := range strings.Split(t.info.Doc, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "//"))
+		if !strings.HasPrefix(line, "@") || len(line) == 1 {
+			continue
+		}
+		name, value := line[1:], ""
+		if i := strings.IndexAny(name, " \t"); i >= 0 {
+			name, value = name[:i], name[i:]
+		}
+		if _, ok := annotations[name]; !ok {
+			annotations[name] = strings.TrimSpace(value)
+		}
+	}
+	return annotations
+}
+
+func newField(f *scanner.FieldInfo) *Field {
+	field := &Field{Name: f.Name, Tag: f.Tag, Doc: f.Doc, Embedded: f.Embedded}
+	if f.Type != nil {
+		field.Type = f.Type.String()
+	}
+	return field
+}
+
+func newFunction(fn *scanner.FunctionInfo) *Function {
+	f := &Function{Name: fn.Name, PkgPath: fn.PkgPath, Doc: fn.Doc}
+	if fn.Receiver != nil && fn.Receiver.Type != nil {
+		f.Receiver = fn.Receiver.Type.String()
+	}
+	for _, p := range fn.Parameters {
+		f.Params = append(f.Params, newField(p))
+	}
+	for _, r := range fn.Results {
+		f.Results = append(f.Results, newField(r))
+	}
+	return f
+}
+
+// receiverTypeName returns the name of the receiver type of a method, without the pointer and
+// the type arguments.
+func receiverTypeName(recv *scanner.FieldInfo) string {
+	ft := recv.Type
+	if ft == nil {
+		return ""
+	}
+	if ft.IsPointer && ft.Elem != nil {
+		ft = ft.Elem
+	}
+	name := ft.TypeName
+	if name == "" {
+		name = ft.Name
+	}
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func kindName(k scanner.Kind) string {
+	switch k {
+	case scanner.StructKind:
+		return "struct"
+	case scanner.InterfaceKind:
+		return "interface"
+	case scanner.FuncKind:
+		return "func"
+	case scanner.AliasKind:
+		return "alias"
+	}
+	return "unknown"
+}
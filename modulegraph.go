@@ -0,0 +1,208 @@
+package goscan
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/podhmo/go-scan/locator"
+)
+
+// ModuleVersion identifies a module at a specific version.
+// The version is empty for main modules (and for modules replaced by a local directory).
+type ModuleVersion struct {
+	Path    string
+	Version string
+}
+
+// String returns the module version in `path@version` form, or just the path if no version is set.
+func (m ModuleVersion) String() string {
+	if m.Version == "" {
+		return m.Path
+	}
+	return m.Path + "@" + m.Version
+}
+
+// ModuleEdge is a requirement from one module to another, as declared in the go.mod of From.
+type ModuleEdge struct {
+	From     ModuleVersion
+	To       ModuleVersion
+	Indirect bool
+	// Replace is the replace directive applied to To, if any: the one of the go.work file,
+	// or else of the main module owning the requirement, or else of another main module.
+	Replace *locator.ReplaceDirective
+}
+
+// ModuleGraph is the module-level requirement graph.
+type ModuleGraph struct {
+	// Main holds the go.mod of each main module (one per module in workspace mode).
+	Main []*locator.ModFile
+	// Work is the go.work file in effect, if any.
+	Work *locator.WorkFile
+	// Edges holds all requirement edges, sorted by From and then To.
+	Edges []ModuleEdge
+	// Incomplete lists module versions whose go.mod could not be found locally,
+	// so their own requirements are missing from the graph.
+	Incomplete []ModuleVersion
+}
+
+// ModFiles returns the parsed go.mod of every main module known to the scanner.
+func (s *Scanner) ModFiles() []*locator.ModFile {
+	var files []*locator.ModFile
+	for _, loc := range s.mainLocators() {
+		files = append(files, loc.ModFile())
+	}
+	return files
+}
+
+// mainLocators returns the locators of the main modules having a go.mod.
+func (s *Scanner) mainLocators() []*locator.Locator {
+	var locs []*locator.Locator
+	if s.isWorkspace {
+		locs = s.locators
+	} else if s.locator != nil {
+		locs = []*locator.Locator{s.locator}
+	}
+	var withModFile []*locator.Locator
+	for _, loc := range locs {
+		if loc.ModFile() != nil {
+			withModFile = append(withModFile, loc)
+		}
+	}
+	return withModFile
+}
+
+// WorkFile returns the go.work file found by searching upwards from the scanner's working directory.
+// It returns nil (and no error) if there is no go.work file.
+func (s *Scanner) WorkFile() (*locator.WorkFile, error) {
+	path, err := locator.FindWorkFile(s.workDir)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return locator.ParseWorkFile(path, content)
+}
+
+// ModuleGraph computes the module-level requirement graph, starting from the main module(s).
+// Requirements of dependency modules are followed when their go.mod is available locally
+// (through a local replace directive, or in the module cache when the scanner is created
+// with WithGoModuleResolver). Versions excluded by a main module are skipped.
+//
+// A requirement is resolved with the replace directives of the go.work file first, and then
+// with the ones of the main module owning it, that is, the main module declaring it or, for
+// the requirements of a dependency, the main module through which the dependency is reached.
+func (s *Scanner) ModuleGraph(ctx context.Context) (*ModuleGraph, error) {
+	work, err := s.WorkFile()
+	if err != nil {
+		return nil, fmt.Errorf("loading go.work: %w", err)
+	}
+	mainLocs := s.mainLocators()
+	graph := &ModuleGraph{Main: s.ModFiles(), Work: work}
+	if len(graph.Main) == 0 {
+		return graph, nil
+	}
+
+	mainModules := make(map[string]bool, len(graph.Main))
+	for _, mf := range graph.Main {
+		mainModules[mf.Path] = true
+	}
+	isExcluded := func(m ModuleVersion) bool {
+		for _, mf := range graph.Main {
+			if mf.IsExcluded(m.Path, m.Version) {
+				return true
+			}
+		}
+		return false
+	}
+	// findReplace returns the replace directive applied to m, and the directory against which
+	// its local replacement is resolved.
+	findReplace := func(m ModuleVersion, owner *locator.Locator) (*locator.ReplaceDirective, string) {
+		if work != nil {
+			wf := &locator.ModFile{Replaces: work.Replaces}
+			if r, ok := wf.Replace(m.Path, m.Version); ok {
+				return &r, filepath.Dir(work.Filename)
+			}
+		}
+		if r, ok := owner.ModFile().Replace(m.Path, m.Version); ok {
+			return &r, owner.RootDir()
+		}
+		for _, loc := range mainLocs {
+			if r, ok := loc.ModFile().Replace(m.Path, m.Version); ok {
+				return &r, loc.RootDir()
+			}
+		}
+		return nil, ""
+	}
+
+	visited := make(map[ModuleVersion]bool)
+	type item struct {
+		mv    ModuleVersion
+		file  *locator.ModFile
+		owner *locator.Locator // the locator of the main module owning the requirements of file
+	}
+	var queue []item
+	for _, loc := range mainLocs {
+		mv := ModuleVersion{Path: loc.ModFile().Path}
+		visited[mv] = true
+		queue = append(queue, item{mv: mv, file: loc.ModFile(), owner: loc})
+	}
+
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cur := queue[0]
+		queue = queue[1:]
+
+		for _, req := range cur.file.Requires {
+			to := ModuleVersion{Path: req.Path, Version: req.Version}
+			if isExcluded(to) {
+				continue
+			}
+			replace, baseDir := findReplace(to, cur.owner)
+			edge := ModuleEdge{From: cur.mv, To: to, Indirect: req.Indirect, Replace: replace}
+			graph.Edges = append(graph.Edges, edge)
+
+			if mainModules[to.Path] {
+				continue // The requirements of main modules are already part of the graph.
+			}
+			if visited[to] {
+				continue
+			}
+			visited[to] = true
+
+			var depFile *locator.ModFile
+			if replace != nil {
+				depFile, err = cur.owner.ReplacedModFile(*replace, baseDir)
+			} else {
+				depFile, err = cur.owner.DependencyModFile(to.Path, to.Version)
+			}
+			if err != nil {
+				slog.WarnContext(ctx, "could not load go.mod of dependency", "module", to.String(), "error", err)
+			}
+			if depFile == nil {
+				graph.Incomplete = append(graph.Incomplete, to)
+				continue
+			}
+			queue = append(queue, item{mv: to, file: depFile, owner: cur.owner})
+		}
+	}
+
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From.String() < b.From.String()
+		}
+		return a.To.String() < b.To.String()
+	})
+	sort.Slice(graph.Incomplete, func(i, j int) bool {
+		return graph.Incomplete[i].String() < graph.Incomplete[j].String()
+	})
+	return graph, nil
+}
@@ -0,0 +1,190 @@
+package goscan_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/locator"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestModuleGraph(t *testing.T) {
+	files := map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.22
+
+require (
+	example.com/dep v1.0.0
+	example.com/util v0.3.0 // indirect
+)
+
+exclude example.com/leaf v0.0.1
+
+replace example.com/dep => ./dep
+`,
+		"main.go": "package app\n",
+		"dep/go.mod": `module example.com/dep
+
+go 1.21
+
+require (
+	example.com/leaf v0.1.0
+	example.com/leaf v0.0.1
+)
+`,
+		"dep/dep.go": "package dep\n",
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithWorkDir(dir))
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+
+	modFiles := s.ModFiles()
+	if len(modFiles) != 1 {
+		t.Fatalf("expected 1 main go.mod, got %d", len(modFiles))
+	}
+	mf := modFiles[0]
+	if diff := cmp.Diff("1.22", mf.GoVersion); diff != "" {
+		t.Errorf("GoVersion mismatch (-want +got):\n%s", diff)
+	}
+	wantRequires := []locator.Require{
+		{Path: "example.com/dep", Version: "v1.0.0"},
+		{Path: "example.com/util", Version: "v0.3.0", Indirect: true},
+	}
+	if diff := cmp.Diff(wantRequires, mf.Requires); diff != "" {
+		t.Errorf("Requires mismatch (-want +got):\n%s", diff)
+	}
+	wantExcludes := []locator.Exclude{{Path: "example.com/leaf", Version: "v0.0.1"}}
+	if diff := cmp.Diff(wantExcludes, mf.Excludes); diff != "" {
+		t.Errorf("Excludes mismatch (-want +got):\n%s", diff)
+	}
+
+	graph, err := s.ModuleGraph(context.Background())
+	if err != nil {
+		t.Fatalf("ModuleGraph() failed: %v", err)
+	}
+
+	replace := &locator.ReplaceDirective{OldPath: "example.com/dep", NewPath: "./dep", IsLocal: true}
+	wantEdges := []goscan.ModuleEdge{
+		{From: goscan.ModuleVersion{Path: "example.com/app"}, To: goscan.ModuleVersion{Path: "example.com/dep", Version: "v1.0.0"}, Replace: replace},
+		{From: goscan.ModuleVersion{Path: "example.com/app"}, To: goscan.ModuleVersion{Path: "example.com/util", Version: "v0.3.0"}, Indirect: true},
+		{From: goscan.ModuleVersion{Path: "example.com/dep", Version: "v1.0.0"}, To: goscan.ModuleVersion{Path: "example.com/leaf", Version: "v0.1.0"}},
+	}
+	if diff := cmp.Diff(wantEdges, graph.Edges); diff != "" {
+		t.Errorf("Edges mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without the module resolver, modules outside the workspace cannot be followed.
+	wantIncomplete := []goscan.ModuleVersion{
+		{Path: "example.com/leaf", Version: "v0.1.0"},
+		{Path: "example.com/util", Version: "v0.3.0"},
+	}
+	if diff := cmp.Diff(wantIncomplete, graph.Incomplete); diff != "" {
+		t.Errorf("Incomplete mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkFile(t *testing.T) {
+	files := map[string]string{
+		"go.work":  "go 1.22\n\nuse (\n\t./a\n\t./b\n)\n",
+		"a/go.mod": "module example.com/a\n\ngo 1.22\n",
+		"a/a.go":   "package a\n",
+		"b/go.mod": "module example.com/b\n\ngo 1.22\n\nrequire example.com/a v0.0.0\n",
+		"b/b.go":   "package b\n",
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithModuleDirs([]string{filepath.Join(dir, "a"), filepath.Join(dir, "b")}))
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+
+	graph, err := s.ModuleGraph(context.Background())
+	if err != nil {
+		t.Fatalf("ModuleGraph() failed: %v", err)
+	}
+	if graph.Work == nil {
+		t.Fatal("expected go.work to be found")
+	}
+	wantUses := []string{filepath.Join(dir, "a"), filepath.Join(dir, "b")}
+	if diff := cmp.Diff(wantUses, graph.Work.Uses); diff != "" {
+		t.Errorf("Uses mismatch (-want +got):\n%s", diff)
+	}
+	wantEdges := []goscan.ModuleEdge{
+		{From: goscan.ModuleVersion{Path: "example.com/b"}, To: goscan.ModuleVersion{Path: "example.com/a", Version: "v0.0.0"}},
+	}
+	if diff := cmp.Diff(wantEdges, graph.Edges); diff != "" {
+		t.Errorf("Edges mismatch (-want +got):\n%s", diff)
+	}
+	if len(graph.Incomplete) != 0 {
+		t.Errorf("expected no incomplete modules, got %v", graph.Incomplete)
+	}
+}
+
+func TestModuleGraph_WorkspaceReplaces(t *testing.T) {
+	files := map[string]string{
+		"go.work":  "go 1.22\n\nuse (\n\t./a\n\t./b\n)\n\nreplace example.com/x => ./x\n",
+		"a/go.mod": "module example.com/a\n\ngo 1.22\n",
+		"a/a.go":   "package a\n",
+		"b/go.mod": `module example.com/b
+
+go 1.22
+
+require (
+	example.com/x v1.0.0
+	example.com/y v1.0.0
+)
+
+replace (
+	example.com/x => ./x-of-b
+	example.com/y => ../y
+)
+`,
+		"b/b.go":      "package b\n",
+		"x/go.mod":    "module example.com/x\n\ngo 1.22\n\nrequire example.com/leaf v0.1.0\n",
+		"y/go.mod":    "module example.com/y\n\ngo 1.22\n\nrequire example.com/leaf v0.2.0\n",
+		"leaf/go.mod": "module example.com/leaf\n\ngo 1.22\n",
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithModuleDirs([]string{filepath.Join(dir, "a"), filepath.Join(dir, "b")}))
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+
+	graph, err := s.ModuleGraph(context.Background())
+	if err != nil {
+		t.Fatalf("ModuleGraph() failed: %v", err)
+	}
+
+	// The replace of the go.work file takes precedence over the one of b, and the replace of b
+	// is resolved against the directory of b, although a is the first module of the workspace.
+	b := goscan.ModuleVersion{Path: "example.com/b"}
+	x := goscan.ModuleVersion{Path: "example.com/x", Version: "v1.0.0"}
+	y := goscan.ModuleVersion{Path: "example.com/y", Version: "v1.0.0"}
+	wantEdges := []goscan.ModuleEdge{
+		{From: b, To: x, Replace: &locator.ReplaceDirective{OldPath: "example.com/x", NewPath: "./x", IsLocal: true}},
+		{From: b, To: y, Replace: &locator.ReplaceDirective{OldPath: "example.com/y", NewPath: "../y", IsLocal: true}},
+		{From: x, To: goscan.ModuleVersion{Path: "example.com/leaf", Version: "v0.1.0"}},
+		{From: y, To: goscan.ModuleVersion{Path: "example.com/leaf", Version: "v0.2.0"}},
+	}
+	if diff := cmp.Diff(wantEdges, graph.Edges); diff != "" {
+		t.Errorf("Edges mismatch (-want +got):\n%s", diff)
+	}
+	wantIncomplete := []goscan.ModuleVersion{
+		{Path: "example.com/leaf", Version: "v0.1.0"},
+		{Path: "example.com/leaf", Version: "v0.2.0"},
+	}
+	if diff := cmp.Diff(wantIncomplete, graph.Incomplete); diff != "" {
+		t.Errorf("Incomplete mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -31,6 +32,8 @@ type Config struct {
 	Inspect             bool
 	Logger              *slog.Logger
 	overlay             scanner.Overlay
+	deps                *dependencyCollector // nil unless WithDependencyReport is used
+	unorderedWalk       bool                 // follow the imports in the order of the visitor, see WithUnorderedWalk
 }
 
 // ModuleWalker is responsible for lightweight, dependency-focused scanning operations.
@@ -58,10 +61,13 @@ func (w *ModuleWalker) ScanPackageFromFilePathImports(ctx context.Context, impor
 	}
 	slog.DebugContext(ctx, "ScanPackageFromFilePathImports CACHE MISS", slog.String("importPath", importPath))
 
-	pkgDirAbs, err := w.locator.FindPackageDir(importPath)
+	pkgDirAbs, err := w.locator.FindPackageDirContext(ctx, importPath)
 	if err != nil {
 		return nil, fmt.Errorf("could not find directory for import path %s: %w", importPath, err)
 	}
+	if w.deps != nil {
+		w.deps.record(ctx, w.locator.ModFile(), importPath, pkgDirAbs)
+	}
 
 	allGoFilesInPkg, err := listGoFilesForWalker(pkgDirAbs, w.IncludeTests)
 	if err != nil {
@@ -336,6 +342,8 @@ func (w *ModuleWalker) BuildReverseDependencyMap(ctx context.Context) (map[strin
 // The provided Visitor's Visit method is called for each discovered package,
 // allowing the caller to inspect the package and control which of its dependencies
 // are followed next.
+// The packages are visited in a stable order: breadth-first from the sorted root packages,
+// following the imports returned by the visitor in sorted order (see WithUnorderedWalk).
 // Patterns can include the `...` wildcard to specify all packages under a directory.
 func (w *ModuleWalker) Walk(ctx context.Context, visitor Visitor, patterns ...string) error {
 	initialQueue, err := w.resolvePatternsToImportPaths(ctx, patterns)
@@ -367,6 +375,10 @@ func (w *ModuleWalker) Walk(ctx context.Context, visitor Visitor, patterns ...st
 			return fmt.Errorf("visitor failed for package %s: %w", currentImportPath, err)
 		}
 
+		if !w.unorderedWalk {
+			importsToFollow = slices.Clone(importsToFollow)
+			sort.Strings(importsToFollow)
+		}
 		for _, imp := range importsToFollow {
 			if _, ok := visited[imp]; !ok {
 				queue = append(queue, imp)
@@ -0,0 +1,333 @@
+package goscan
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/build"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/podhmo/go-scan/scanner"
+	"golang.org/x/mod/module"
+)
+
+// ScanModuleZip scans the packages of a version of a module from its zip, as served by the
+// module proxy, without extracting it: the zip is read from the module cache if it was already
+// downloaded, or else fetched from the proxies of GOPROXY, and its files are parsed in memory.
+// It allows to analyze a dependency at another version than the one of the local checkout,
+// e.g. to compare its API or check its license.
+//
+// The packages are returned sorted by import path. Their files have paths under a virtual
+// directory which does not exist, "/@modzip/<module>@<version>". They are not cached by the
+// scanner, so that the packages found by the import paths stay the ones of the local build;
+// the imports of the packages of the module itself are resolved in the zip, the others as usual.
+// Fetching from the version control systems (GOPROXY=direct) is not supported.
+func (s *Scanner) ScanModuleZip(ctx context.Context, modPath, version string) ([]*Package, error) {
+	data, err := fetchModuleZip(ctx, modPath, version)
+	if err != nil {
+		return nil, err
+	}
+	files, err := readModuleZip(data, modPath, version)
+	if err != nil {
+		return nil, fmt.Errorf("reading the zip of %s@%s: %w", modPath, version, err)
+	}
+
+	root := filepath.Join(string(filepath.Separator), "@modzip", filepath.FromSlash(modPath)+"@"+version)
+	overlay := make(scanner.Overlay, len(files))
+	dirs := make(map[string][]string) // the files of the packages, by slash-separated directory
+	for name, content := range files {
+		overlay[filepath.FromSlash(name)] = content
+		if strings.HasSuffix(name, ".go") {
+			dirs[path.Dir(name)] = append(dirs[path.Dir(name)], name)
+		}
+	}
+
+	zr := &moduleZipResolver{
+		base:     s,
+		modPath:  modPath,
+		root:     root,
+		files:    files,
+		dirs:     dirs,
+		packages: make(map[string]*Package),
+	}
+	zr.scanner, err = scanner.New(s.fset, s.ExternalTypeOverrides, overlay, modPath, root, zr, s.Inspect, s.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the scanner of %s@%s: %w", modPath, version, err)
+	}
+	zr.scanner.DefaultLoadMode = s.defaultLoadMode
+	zr.scanner.LoadModeRules = s.loadModeRules
+	zr.scanner.CollectCalls = s.collectCalls
+	zr.scanner.PartialResults = s.partialResults
+
+	relDirs := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		relDirs = append(relDirs, dir)
+	}
+	sort.Strings(relDirs)
+	var pkgs []*Package
+	for _, dir := range relDirs {
+		pkg, err := zr.scanDir(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		if pkg != nil {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	return pkgs, nil
+}
+
+// moduleZipResolver scans the packages of a module zip, and resolves the imports of the
+// packages of the module in the zip, and the other ones with the scanner.
+type moduleZipResolver struct {
+	base    *Scanner
+	scanner *scanner.Scanner
+	modPath string
+	root    string
+	files   map[string][]byte   // by slash-separated path in the module
+	dirs    map[string][]string // the .go files, by slash-separated directory in the module
+
+	mu       sync.Mutex
+	packages map[string]*Package // by import path
+}
+
+// ScanPackageFromImportPath implements scanner.PackageResolver.
+func (zr *moduleZipResolver) ScanPackageFromImportPath(ctx context.Context, importPath string) (*scanner.PackageInfo, error) {
+	if importPath == zr.modPath || strings.HasPrefix(importPath, zr.modPath+"/") {
+		dir := strings.TrimPrefix(strings.TrimPrefix(importPath, zr.modPath), "/")
+		if dir == "" {
+			dir = "."
+		}
+		if _, ok := zr.dirs[dir]; ok {
+			pkg, err := zr.scanDir(ctx, dir)
+			if err == nil && pkg == nil {
+				err = fmt.Errorf("package %s has no Go files to scan in %s", importPath, zr.root)
+			}
+			return pkg, err
+		}
+	}
+	return zr.base.ScanPackageFromImportPath(ctx, importPath)
+}
+
+// scanDir scans the package of a directory of the module, or returns nil if it has no files
+// to scan, e.g. only tests.
+func (zr *moduleZipResolver) scanDir(ctx context.Context, dir string) (*Package, error) {
+	importPath := path.Join(zr.modPath, dir)
+	zr.mu.Lock()
+	defer zr.mu.Unlock()
+	if pkg, ok := zr.packages[importPath]; ok {
+		return pkg, nil
+	}
+
+	pkgDir := filepath.Join(zr.root, filepath.FromSlash(dir))
+	var filePaths []string
+	for _, name := range zr.dirs[dir] {
+		if !zr.matchFile(dir, path.Base(name)) {
+			continue
+		}
+		filePaths = append(filePaths, filepath.Join(zr.root, filepath.FromSlash(name)))
+	}
+	sort.Strings(filePaths)
+	zr.packages[importPath] = nil // A package importing itself through its dependencies is not rescanned.
+	if len(filePaths) == 0 {
+		return nil, nil
+	}
+
+	// The imports of the package are resolved lazily, while the lock is released.
+	zr.mu.Unlock()
+	pkg, err := zr.scanner.ScanFilesWithKnownImportPath(ctx, filePaths, pkgDir, importPath)
+	zr.mu.Lock()
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", importPath, err)
+	}
+	pkg.ImportPath = importPath
+	pkg.Path = pkgDir
+	pkg.ModulePath, pkg.ModuleDir = zr.modPath, zr.root
+	if pkg.Name == "main" {
+		pkg.ID = importPath + ".main"
+	} else {
+		pkg.ID = importPath
+	}
+	zr.packages[importPath] = pkg
+	return pkg, nil
+}
+
+// matchFile reports whether a .go file of a directory of the module is scanned, following the
+// rules of the scanning of the directories (see listGoFiles).
+func (zr *moduleZipResolver) matchFile(dir, name string) bool {
+	if !zr.base.IncludeTests && strings.HasSuffix(name, "_test.go") {
+		return false
+	}
+	if len(zr.base.buildTags) == 0 {
+		return true
+	}
+	c := build.Default
+	c.BuildTags = zr.base.buildTags
+	c.OpenFile = func(p string) (io.ReadCloser, error) {
+		content, ok := zr.files[path.Join(dir, path.Base(filepath.ToSlash(p)))]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+	ok, err := c.MatchFile(filepath.Join(zr.root, filepath.FromSlash(dir)), name)
+	return err == nil && ok
+}
+
+// readModuleZip returns the files of a module zip by their slash-separated paths in the module.
+// As in a build, the files of the nested modules, of the testdata, vendor and hidden
+// directories are left out.
+func readModuleZip(data []byte, modPath, version string) (map[string][]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	prefix := modPath + "@" + version + "/"
+	files := make(map[string][]byte)
+	nested := make(map[string]bool) // the directories of the nested modules
+	for _, f := range zr.File {
+		name, ok := strings.CutPrefix(f.Name, prefix)
+		if !ok {
+			return nil, fmt.Errorf("unexpected file %q outside of %s", f.Name, prefix)
+		}
+		if strings.HasSuffix(name, "/") || !isModuleZipFileScanned(name) {
+			continue
+		}
+		if path.Base(name) == "go.mod" && name != "go.mod" {
+			nested[path.Dir(name)] = true
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", f.Name, err)
+		}
+		files[name] = content
+	}
+	for name := range files {
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			if nested[dir] {
+				delete(files, name)
+				break
+			}
+		}
+	}
+	return files, nil
+}
+
+// isModuleZipFileScanned reports whether a file of a module zip may belong to a package.
+func isModuleZipFileScanned(name string) bool {
+	dirs := strings.Split(name, "/")
+	for _, dir := range dirs[:len(dirs)-1] {
+		if dir == "testdata" || dir == "vendor" || strings.HasPrefix(dir, ".") || strings.HasPrefix(dir, "_") {
+			return false
+		}
+	}
+	return true
+}
+
+// fetchModuleZip returns the zip of a module version, from the download cache of the module
+// cache, or else from the first proxy of GOPROXY which has it.
+func fetchModuleZip(ctx context.Context, modPath, version string) ([]byte, error) {
+	escPath, err := module.EscapePath(modPath)
+	if err != nil {
+		return nil, err
+	}
+	escVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	zipPath := escPath + "/@v/" + escVersion + ".zip"
+
+	out, err := exec.CommandContext(ctx, "go", "env", "GOMODCACHE", "GOPROXY").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run 'go env GOMODCACHE GOPROXY': %w", err)
+	}
+	modCache, goproxy, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if modCache != "" {
+		if data, err := os.ReadFile(filepath.Join(modCache, "cache", "download", filepath.FromSlash(zipPath))); err == nil {
+			return data, nil
+		}
+	}
+
+	errs := []error{}
+	for goproxy != "" {
+		// A proxy separated by "|" is followed after any error, one separated by "," only
+		// when it does not have the module.
+		var proxy string
+		fallback := true
+		if i := strings.IndexAny(goproxy, ",|"); i >= 0 {
+			proxy, fallback, goproxy = goproxy[:i], goproxy[i] == '|', goproxy[i+1:]
+		} else {
+			proxy, goproxy = goproxy, ""
+		}
+		switch proxy = strings.TrimSpace(proxy); proxy {
+		case "":
+			continue
+		case "off":
+			return nil, fmt.Errorf("fetching %s@%s: module lookup disabled by GOPROXY=off", modPath, version)
+		case "direct":
+			errs = append(errs, fmt.Errorf("fetching %s@%s from its repository is not supported", modPath, version))
+			continue
+		}
+		data, notFound, err := fetchFromProxy(ctx, proxy, zipPath)
+		if err == nil {
+			return data, nil
+		}
+		errs = append(errs, err)
+		if !notFound && !fallback {
+			break
+		}
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("fetching %s@%s: no proxy in GOPROXY", modPath, version)
+	}
+	return nil, fmt.Errorf("fetching %s@%s: %w", modPath, version, errors.Join(errs...))
+}
+
+// fetchFromProxy fetches a file of a module proxy, an http(s) or file URL. It reports whether
+// the proxy does not have the file.
+func fetchFromProxy(ctx context.Context, proxy, name string) ([]byte, bool, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid proxy %q: %w", proxy, err)
+	}
+	switch u.Scheme {
+	case "file":
+		data, err := os.ReadFile(filepath.Join(filepath.FromSlash(u.Path), filepath.FromSlash(name)))
+		return data, errors.Is(err, fs.ErrNotExist), err
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(proxy, "/")+"/"+name, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, false, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			notFound := resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone
+			return nil, notFound, fmt.Errorf("%s: %s", req.URL, resp.Status)
+		}
+		data, err := io.ReadAll(resp.Body)
+		return data, false, err
+	default:
+		return nil, false, fmt.Errorf("unsupported proxy %q", proxy)
+	}
+}
@@ -0,0 +1,130 @@
+package goscan_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestScanModuleZip(t *testing.T) {
+	dir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"app/go.mod":  "module example.com/app\n\ngo 1.22\n\nrequire example.com/lib v1.0.0\n",
+		"app/main.go": "package main\n\nimport _ \"example.com/lib\"\n",
+	})
+	defer cleanup()
+
+	proxyDir := filepath.Join(dir, "proxy")
+	writeModuleProxy(t, proxyDir, "example.com/lib", "v1.0.0", map[string]string{
+		"go.mod": "module example.com/lib\n\ngo 1.22\n",
+		"lib.go": "package lib\n\n// Value is a value.\ntype Value struct{ Name string }\n",
+	})
+	writeModuleProxy(t, proxyDir, "example.com/lib", "v1.1.0", map[string]string{
+		"go.mod":                "module example.com/lib\n\ngo 1.22\n",
+		"lib.go":                "package lib\n\nimport \"example.com/lib/sub\"\n\n// Value is a value.\ntype Value struct {\n\tName string\n\tID   sub.ID\n}\n\n// NewValue creates a Value.\nfunc NewValue(name string) *Value { return &Value{Name: name} }\n",
+		"lib_test.go":           "package lib\n\nfunc helperForTest() {}\n",
+		"sub/sub.go":            "package sub\n\n// ID is an identifier.\ntype ID string\n",
+		"testdata/data.go":      "package data\n",
+		"tools/go.mod":          "module example.com/lib/tools\n\ngo 1.22\n",
+		"tools/tools.go":        "package tools\n",
+		"internal/only_test.go": "package internal\n",
+	})
+
+	modCache := filepath.Join(t.TempDir(), "modcache")
+	t.Setenv("GOMODCACHE", modCache)
+	t.Setenv("GOPROXY", "file://"+filepath.ToSlash(proxyDir))
+	t.Setenv("GOFLAGS", "-modcacherw")
+	t.Setenv("GOTOOLCHAIN", "local")
+
+	ctx := context.Background()
+	s, err := goscan.New(goscan.WithWorkDir(filepath.Join(dir, "app")), goscan.WithGoModuleResolver())
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+
+	t.Run("versions", func(t *testing.T) {
+		for version, wantFuncs := range map[string][]string{"v1.0.0": nil, "v1.1.0": {"NewValue"}} {
+			pkgs, err := s.ScanModuleZip(ctx, "example.com/lib", version)
+			if err != nil {
+				t.Fatalf("ScanModuleZip(%s) failed: %v", version, err)
+			}
+			if len(pkgs) == 0 || pkgs[0].ImportPath != "example.com/lib" {
+				t.Fatalf("%s: expected example.com/lib first, got %d packages", version, len(pkgs))
+			}
+			var gotFuncs []string
+			for _, f := range pkgs[0].Functions {
+				gotFuncs = append(gotFuncs, f.Name)
+			}
+			if diff := cmp.Diff(wantFuncs, gotFuncs); diff != "" {
+				t.Errorf("%s: functions mismatch (-want +got):\n%s", version, diff)
+			}
+		}
+	})
+
+	t.Run("packages", func(t *testing.T) {
+		pkgs, err := s.ScanModuleZip(ctx, "example.com/lib", "v1.1.0")
+		if err != nil {
+			t.Fatalf("ScanModuleZip() failed: %v", err)
+		}
+		var got []string
+		for _, pkg := range pkgs {
+			got = append(got, pkg.ImportPath)
+		}
+		// The testdata, the nested module and the directory with only tests are left out.
+		if diff := cmp.Diff([]string{"example.com/lib", "example.com/lib/sub"}, got); diff != "" {
+			t.Errorf("packages mismatch (-want +got):\n%s", diff)
+		}
+
+		// The imports of the module are resolved in the zip.
+		value := pkgs[0].Lookup("Value")
+		if value == nil || value.Struct == nil {
+			t.Fatalf("expected the struct Value")
+		}
+		var idField *scanner.FieldType
+		for _, f := range value.Struct.Fields {
+			if f.Name == "ID" {
+				idField = f.Type
+			}
+		}
+		if idField == nil {
+			t.Fatalf("expected the field ID")
+		}
+		id, err := s.ResolveType(ctx, idField)
+		if err != nil {
+			t.Fatalf("ResolveType() failed: %v", err)
+		}
+		if id.PkgPath != "example.com/lib/sub" || id.Name != "ID" {
+			t.Errorf("expected sub.ID, got %s.%s", id.PkgPath, id.Name)
+		}
+	})
+
+	t.Run("not extracted", func(t *testing.T) {
+		if _, err := os.Stat(filepath.Join(modCache, "example.com", "lib@v1.1.0")); !os.IsNotExist(err) {
+			t.Errorf("expected the module not to be extracted into the module cache, got %v", err)
+		}
+		if _, err := s.ScanPackageFromImportPath(ctx, "example.com/lib"); err == nil {
+			t.Errorf("expected the scanned zip not to be found by import path")
+		}
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := s.ScanModuleZip(ctx, "example.com/lib", "v9.9.9")
+		if err == nil || !strings.Contains(err.Error(), "fetching example.com/lib@v9.9.9") {
+			t.Errorf("expected a fetch error, got %v", err)
+		}
+	})
+
+	t.Run("GOPROXY=off", func(t *testing.T) {
+		t.Setenv("GOPROXY", "off")
+		_, err := s.ScanModuleZip(ctx, "example.com/lib", "v1.1.0")
+		if err == nil || !strings.Contains(err.Error(), "GOPROXY=off") {
+			t.Errorf("expected the lookup to be disabled, got %v", err)
+		}
+	})
+}
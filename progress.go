@@ -0,0 +1,182 @@
+package goscan
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// ProgressPhase is a phase of a scan reported by WithProgress.
+type ProgressPhase string
+
+const (
+	// PhaseLocate finds the directories of the packages matching the patterns given to Scan.
+	PhaseLocate ProgressPhase = "locate"
+	// PhaseParse parses the packages found by PhaseLocate, one event per package.
+	PhaseParse ProgressPhase = "parse"
+	// PhaseResolve parses a package found by its import path outside of Scan, e.g. when the type
+	// of a field is resolved, one event per package.
+	PhaseResolve ProgressPhase = "resolve"
+)
+
+// ProgressEvent reports the progress of the scanner to the function given to WithProgress.
+// The counts are reset at the beginning of each call of Scan.
+type ProgressEvent struct {
+	Phase ProgressPhase
+	// Done is true for the last event of a phase of Scan: once the packages are located, and
+	// once they are all parsed.
+	Done bool
+	// Package is the import path of the package just parsed, empty for the other events and for
+	// a package which failed to parse.
+	Package string
+
+	PackagesDone  int           // the packages parsed in this phase so far
+	PackagesTotal int           // the packages found by PhaseLocate, 0 for PhaseResolve
+	Files         int           // the files parsed so far, in all phases
+	Bytes         int64         // the size of the files parsed so far
+	Elapsed       time.Duration // since the beginning of the scan
+}
+
+// WithProgress reports the progress of the scans to fn, e.g. to render a progress bar. fn is
+// called synchronously, from the goroutine scanning; see ProgressEvery for a CI log.
+func WithProgress(fn func(ev ProgressEvent)) ScannerOption {
+	return func(s *Scanner) error {
+		s.progress = &progressReporter{fn: fn, start: time.Now()}
+		return nil
+	}
+}
+
+// ProgressEvery forwards the events to fn at most once per interval, except for the last
+// events of the phases, which are always forwarded.
+func ProgressEvery(interval time.Duration, fn func(ev ProgressEvent)) func(ev ProgressEvent) {
+	var mu sync.Mutex
+	var last time.Time
+	return func(ev ProgressEvent) {
+		mu.Lock()
+		now := time.Now()
+		forward := ev.Done || now.Sub(last) >= interval
+		if forward {
+			last = now
+		}
+		mu.Unlock()
+		if forward {
+			fn(ev)
+		}
+	}
+}
+
+// progressReporter keeps the counts of the events reported by WithProgress. The methods do
+// nothing on a nil reporter, the scanner without WithProgress.
+type progressReporter struct {
+	fn func(ev ProgressEvent)
+
+	mu       sync.Mutex
+	start    time.Time
+	files    int
+	bytes    int64
+	parsed   int // the packages parsed by Scan
+	resolved int // the packages parsed outside of Scan
+	total    int
+}
+
+// reset starts the counts of a new call of Scan.
+func (p *progressReporter) reset() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.start = time.Now()
+	p.files, p.bytes, p.parsed, p.resolved, p.total = 0, 0, 0, 0, 0
+	p.mu.Unlock()
+}
+
+// addFiles counts the files just parsed.
+func (p *progressReporter) addFiles(files []string) {
+	if p == nil {
+		return
+	}
+	var size int64
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil {
+			size += info.Size()
+		}
+	}
+	p.mu.Lock()
+	p.files += len(files)
+	p.bytes += size
+	p.mu.Unlock()
+}
+
+// located reports the end of PhaseLocate.
+func (p *progressReporter) located(total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.total = total
+	ev := p.event(PhaseLocate, "")
+	ev.Done = true
+	p.mu.Unlock()
+	p.fn(ev)
+}
+
+// packageParsed reports a package parsed by Scan, and the end of PhaseParse with done.
+func (p *progressReporter) packageParsed(importPath string, done bool) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	if !done {
+		p.parsed++
+	}
+	ev := p.event(PhaseParse, importPath)
+	ev.Done = done
+	p.mu.Unlock()
+	p.fn(ev)
+}
+
+// packageResolved reports a package parsed outside of Scan.
+func (p *progressReporter) packageResolved(importPath string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.resolved++
+	ev := p.event(PhaseResolve, importPath)
+	p.mu.Unlock()
+	p.fn(ev)
+}
+
+// event returns an event with the current counts. p.mu must be held.
+func (p *progressReporter) event(phase ProgressPhase, importPath string) ProgressEvent {
+	ev := ProgressEvent{
+		Phase:   phase,
+		Package: importPath,
+		Files:   p.files,
+		Bytes:   p.bytes,
+		Elapsed: time.Since(p.start),
+	}
+	switch phase {
+	case PhaseParse:
+		ev.PackagesDone, ev.PackagesTotal = p.parsed, p.total
+	case PhaseResolve:
+		ev.PackagesDone = p.resolved
+	case PhaseLocate:
+		ev.PackagesTotal = p.total
+	}
+	return ev
+}
+
+// scanTargetKey marks the context of the scan of a package found by PhaseLocate, so that the
+// package is not reported as resolved.
+type scanTargetKey struct{}
+
+func withScanTarget(ctx context.Context, target bool) context.Context {
+	return context.WithValue(ctx, scanTargetKey{}, target)
+}
+
+func isScanTarget(ctx context.Context) bool {
+	target, _ := ctx.Value(scanTargetKey{}).(bool)
+	return target
+}
@@ -0,0 +1,75 @@
+package goscan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestWithProgress(t *testing.T) {
+	files := map[string]string{
+		"go.mod":       "module example.com/app\n\ngo 1.21\n",
+		"api/api.go":   "package api\n\ntype User struct{ Name string }\n",
+		"api/types.go": "package api\n\ntype Role string\n",
+		"lib/lib.go":   "package lib\n\nfunc Help() {}\n",
+		"extra/x.go":   "package extra\n",
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	var events []goscan.ProgressEvent
+	s, err := goscan.New(
+		goscan.WithWorkDir(dir),
+		goscan.WithProgress(func(ev goscan.ProgressEvent) { events = append(events, ev) }),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := s.Scan(ctx, "./api", "./lib"); err != nil {
+		t.Fatalf("Scan() failed: %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("want 4 events (located, 2 packages, done), got %+v", events)
+	}
+	if ev := events[0]; ev.Phase != goscan.PhaseLocate || !ev.Done || ev.PackagesTotal != 2 {
+		t.Errorf("located: got %+v", ev)
+	}
+	for i, want := range []string{"example.com/app/api", "example.com/app/lib"} {
+		ev := events[i+1]
+		if ev.Phase != goscan.PhaseParse || ev.Done || ev.Package != want || ev.PackagesDone != i+1 || ev.PackagesTotal != 2 {
+			t.Errorf("package %d: got %+v", i+1, ev)
+		}
+	}
+	last := events[3]
+	if last.Phase != goscan.PhaseParse || !last.Done || last.PackagesDone != 2 {
+		t.Errorf("done: got %+v", last)
+	}
+	if last.Files != 3 || last.Bytes == 0 || last.Elapsed <= 0 {
+		t.Errorf("want the counts of the 3 parsed files, got %+v", last)
+	}
+
+	// A package scanned by its import path outside of Scan is reported as resolved.
+	events = nil
+	if _, err := s.ScanPackageFromImportPath(ctx, "example.com/app/extra"); err != nil {
+		t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Phase != goscan.PhaseResolve || events[0].Package != "example.com/app/extra" || events[0].PackagesDone != 1 {
+		t.Errorf("resolve: got %+v", events)
+	}
+}
+
+func TestProgressEvery(t *testing.T) {
+	var got []goscan.ProgressEvent
+	fn := goscan.ProgressEvery(time.Hour, func(ev goscan.ProgressEvent) { got = append(got, ev) })
+	fn(goscan.ProgressEvent{Phase: goscan.PhaseParse, PackagesDone: 1})
+	fn(goscan.ProgressEvent{Phase: goscan.PhaseParse, PackagesDone: 2}) // throttled
+	fn(goscan.ProgressEvent{Phase: goscan.PhaseParse, PackagesDone: 3, Done: true})
+	if len(got) != 2 || got[0].PackagesDone != 1 || !got[1].Done {
+		t.Errorf("want the first and the last events, got %+v", got)
+	}
+}
@@ -0,0 +1,129 @@
+package rewrite
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around the changes in a diff.
+const contextLines = 3
+
+// diffOp is a line of a diff: ' ' for an unchanged line, '-' for a deleted one, '+' for an added one.
+type diffOp struct {
+	kind byte
+	line string
+	// The 0-based index of the line in the old and the new text.
+	oldIndex, newIndex int
+}
+
+// unifiedDiff returns the unified diff between the old and the new text.
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// A hunk starts with the context before the change, and ends when the changes are
+		// separated by more than twice the context.
+		start := max(i-contextLines, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*contextLines {
+				end = min(end+contextLines, len(ops))
+				break
+			}
+			end = next
+		}
+		writeHunk(&b, ops[start:end])
+		i = end
+	}
+	return b.String()
+}
+
+func writeHunk(b *strings.Builder, ops []diffOp) {
+	oldStart, newStart := ops[0].oldIndex, ops[0].newIndex
+	oldCount, newCount := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+	}
+	// The line numbers are 1-based, except for an empty range, which refers to the line before it.
+	if oldCount > 0 {
+		oldStart++
+	}
+	if newCount > 0 {
+		newStart++
+	}
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, op := range ops {
+		b.WriteByte(op.kind)
+		b.WriteString(op.line)
+		if !strings.HasSuffix(op.line, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// diffLines computes the shortest edit script between two lists of lines from their longest
+// common subsequence. The files rewritten are small, so the quadratic table is acceptable.
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', line: a[i], oldIndex: i, newIndex: j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: '-', line: a[i], oldIndex: i, newIndex: j})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: b[j], oldIndex: i, newIndex: j})
+			j++
+		}
+	}
+	return ops
+}
+
+// splitLines splits text into lines, keeping the line terminators.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
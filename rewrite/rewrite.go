@@ -0,0 +1,481 @@
+// Package rewrite provides helpers for modifying Go source files, e.g. the files found by go-scan,
+// and writing them back with their formatting and comments preserved.
+//
+// A File holds the current source of a file and its AST. Every edit is applied immediately: the
+// source is reformatted with go/format and parsed again, so the nodes and positions obtained from
+// File.AST before an edit must not be used after it. The edits addressing declarations by name
+// (AddStructTag, DeleteDecl, CommentOutDecl) do not have this restriction.
+package rewrite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	goscan "github.com/podhmo/go-scan"
+)
+
+// Edit replaces the source between Pos and End with Text.
+// An insertion has Pos == End, a deletion has an empty Text.
+type Edit struct {
+	Pos  token.Pos
+	End  token.Pos
+	Text string
+}
+
+// File is a Go source file being rewritten.
+type File struct {
+	Path string
+	Fset *token.FileSet
+	AST  *ast.File
+
+	original []byte
+	src      []byte
+}
+
+// ReadFile reads and parses the file at path.
+func ReadFile(path string) (*File, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+	return NewFile(path, src)
+}
+
+// NewFile parses src as the content of the file at path.
+func NewFile(path string, src []byte) (*File, error) {
+	f := &File{Path: path, original: src}
+	if err := f.reset(src); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// reset parses src as the new current source of the file.
+func (f *File) reset(src []byte) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, f.Path, src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("could not parse %s: %w", f.Path, err)
+	}
+	f.Fset, f.AST, f.src = fset, file, src
+	return nil
+}
+
+// Source returns the current source of the file.
+func (f *File) Source() []byte {
+	return f.src
+}
+
+// Changed reports whether the current source differs from the original one.
+func (f *File) Changed() bool {
+	return !bytes.Equal(f.original, f.src)
+}
+
+// Diff returns the changes made to the file as a unified diff, or "" if unchanged.
+// It is meant for a dry run, showing the changes instead of writing them.
+func (f *File) Diff() string {
+	if !f.Changed() {
+		return ""
+	}
+	return unifiedDiff("a/"+f.Path, "b/"+f.Path, string(f.original), string(f.src))
+}
+
+// WriteFile writes the current source back to the file, if changed.
+// It uses goscan.WriteFile, so the writing can be intercepted through the context.
+func (f *File) WriteFile(ctx context.Context) error {
+	if !f.Changed() {
+		return nil
+	}
+	if err := goscan.WriteFile(ctx, f.Path, f.src, 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", f.Path, err)
+	}
+	return nil
+}
+
+// Apply applies position-based text edits to the current source, then formats it.
+// The positions must come from the current AST, and the edits must not overlap.
+// If the result cannot be formatted, e.g. because it is not valid Go, the file is left unchanged.
+func (f *File) Apply(edits ...Edit) error {
+	if len(edits) == 0 {
+		return nil
+	}
+	tf := f.Fset.File(f.AST.Pos())
+	sorted := make([]Edit, len(edits))
+	copy(sorted, edits)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Pos < sorted[j].Pos })
+
+	var buf bytes.Buffer
+	last := 0
+	for _, e := range sorted {
+		if !e.Pos.IsValid() || !e.End.IsValid() || e.End < e.Pos {
+			return fmt.Errorf("invalid edit range [%d, %d) in %s", e.Pos, e.End, f.Path)
+		}
+		start, end := tf.Offset(e.Pos), tf.Offset(e.End)
+		if start < last {
+			return fmt.Errorf("overlapping edits at %s", f.Fset.Position(e.Pos))
+		}
+		buf.Write(f.src[last:start])
+		buf.WriteString(e.Text)
+		last = end
+	}
+	buf.Write(f.src[last:])
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("edits of %s produce invalid source: %w", f.Path, err)
+	}
+	return f.reset(formatted)
+}
+
+// AddImport adds the import of path, with the given name if not empty, unless already imported.
+// It is added to the first import declaration, where go/format sorts it.
+func (f *File) AddImport(name, path string) error {
+	if _, spec := f.importSpec(name, path); spec != nil {
+		return nil
+	}
+	text := strconv.Quote(path)
+	if name != "" {
+		text = name + " " + text
+	}
+
+	for _, decl := range f.AST.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.IMPORT {
+			continue
+		}
+		if d.Lparen.IsValid() {
+			return f.Apply(Edit{Pos: d.Rparen, End: d.Rparen, Text: text + "\n"})
+		}
+		tf := f.Fset.File(f.AST.Pos())
+		spec := string(f.src[tf.Offset(d.Specs[0].Pos()):tf.Offset(d.Specs[0].End())])
+		return f.Apply(Edit{Pos: d.Pos(), End: d.End(), Text: "import (\n" + spec + "\n" + text + "\n)"})
+	}
+	return f.Apply(Edit{Pos: f.AST.Name.End(), End: f.AST.Name.End(), Text: "\n\nimport " + text})
+}
+
+// DeleteImport deletes the import of path with the given name (empty for an unnamed import).
+func (f *File) DeleteImport(name, path string) error {
+	decl, spec := f.importSpec(name, path)
+	if spec == nil {
+		return nil
+	}
+	if len(decl.Specs) == 1 {
+		return f.Apply(f.nodeLines(decl, decl.Doc))
+	}
+	return f.Apply(f.nodeLines(spec, spec.Doc))
+}
+
+// UsesImport reports whether the file refers to the package imported with path. The name of an
+// unnamed import is guessed from the last element of the path; a blank or dot import is
+// always considered used.
+func (f *File) UsesImport(path string) bool {
+	var spec *ast.ImportSpec
+	for _, s := range f.AST.Imports {
+		if p, err := strconv.Unquote(s.Path.Value); err == nil && p == path {
+			spec = s
+			break
+		}
+	}
+	if spec == nil {
+		return false
+	}
+	name := path[strings.LastIndex(path, "/")+1:]
+	if spec.Name != nil {
+		name = spec.Name.Name
+	}
+	if name == "_" || name == "." {
+		return true
+	}
+
+	used := false
+	ast.Inspect(f.AST, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			// A package name is not resolved to an object by the parser, unlike a local variable.
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == name && id.Obj == nil {
+				used = true
+			}
+		}
+		return !used
+	})
+	return used
+}
+
+// importSpec returns the import of path with the given name, and its declaration.
+func (f *File) importSpec(name, path string) (*ast.GenDecl, *ast.ImportSpec) {
+	for _, decl := range f.AST.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range d.Specs {
+			s := spec.(*ast.ImportSpec)
+			specName := ""
+			if s.Name != nil {
+				specName = s.Name.Name
+			}
+			if p, err := strconv.Unquote(s.Path.Value); err == nil && p == path && specName == name {
+				return d, s
+			}
+		}
+	}
+	return nil, nil
+}
+
+// AddStructTag sets the key of the tag of a field of a struct type, e.g. `json:"name"` for
+// the key "json" and the value "name". The other keys of the tag are kept.
+// If the field shares its declaration with other fields (`A, B string`), they get the tag too.
+func (f *File) AddStructTag(typeName, fieldName, key, value string) error {
+	field, err := f.lookupField(typeName, fieldName)
+	if err != nil {
+		return err
+	}
+	if field.Tag == nil {
+		return f.Apply(Edit{
+			Pos:  field.Type.End(),
+			End:  field.Type.End(),
+			Text: " " + quoteTag(setTag("", key, value)),
+		})
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return fmt.Errorf("invalid tag of %s.%s: %w", typeName, fieldName, err)
+	}
+	return f.Apply(Edit{
+		Pos:  field.Tag.Pos(),
+		End:  field.Tag.End(),
+		Text: quoteTag(setTag(tag, key, value)),
+	})
+}
+
+// lookupField returns the field of a struct type declared in the file.
+func (f *File) lookupField(typeName, fieldName string) (*ast.Field, error) {
+	for _, decl := range f.AST.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != typeName {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return nil, fmt.Errorf("type %s is not a struct in %s", typeName, f.Path)
+			}
+			for _, field := range st.Fields.List {
+				if len(field.Names) == 0 && embeddedName(field.Type) == fieldName {
+					return field, nil
+				}
+				for _, name := range field.Names {
+					if name.Name == fieldName {
+						return field, nil
+					}
+				}
+			}
+			return nil, fmt.Errorf("field %s.%s not found in %s", typeName, fieldName, f.Path)
+		}
+	}
+	return nil, fmt.Errorf("type %s not found in %s", typeName, f.Path)
+}
+
+// embeddedName returns the field name of an embedded field, e.g. "Buffer" for *bytes.Buffer.
+func embeddedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedName(e.X)
+	case *ast.IndexListExpr:
+		return embeddedName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+// setTag returns tag with the value of key set, replacing the existing value if any.
+func setTag(tag, key, value string) string {
+	entry := key + ":" + strconv.Quote(value)
+	var parts []string
+	found := false
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			break
+		}
+		// Scan the key up to the colon, then the quoted value, as reflect.StructTag.Lookup does.
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			parts = append(parts, tag) // malformed, kept as is
+			break
+		}
+		name := tag[:i]
+		j := i + 2
+		for j < len(tag) && tag[j] != '"' {
+			if tag[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(tag) {
+			parts = append(parts, tag)
+			break
+		}
+		if name == key {
+			if !found {
+				parts = append(parts, entry)
+			}
+			found = true
+		} else {
+			parts = append(parts, tag[:j+1])
+		}
+		tag = tag[j+1:]
+	}
+	if !found {
+		parts = append(parts, entry)
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteTag returns the literal of a tag, a raw string unless the tag contains a backquote.
+func quoteTag(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// DeleteDecl deletes the declaration of name with its doc comment: a function, a method
+// ("Type.Method" or "(*Type).Method"), a type, a variable, or a constant. A name declared
+// in a group, e.g. `var ( ... )`, is deleted from the group; a spec declaring several names,
+// e.g. `var a, b int`, is deleted entirely.
+func (f *File) DeleteDecl(name string) error {
+	edit, err := f.declLines(name)
+	if err != nil {
+		return err
+	}
+	return f.Apply(edit)
+}
+
+// CommentOutDecl comments out the declaration of name with its doc comment, see DeleteDecl.
+// If marker is not empty, it is added as a comment line above, e.g. to find the declaration later.
+func (f *File) CommentOutDecl(name, marker string) error {
+	edit, err := f.declLines(name)
+	if err != nil {
+		return err
+	}
+	tf := f.Fset.File(f.AST.Pos())
+	text := string(f.src[tf.Offset(edit.Pos):tf.Offset(edit.End)])
+
+	var b strings.Builder
+	if marker != "" {
+		b.WriteString("// " + marker + "\n")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			b.WriteString("//\n")
+		} else {
+			b.WriteString("// " + line + "\n")
+		}
+	}
+	edit.Text = b.String()
+	return f.Apply(edit)
+}
+
+// declLines returns the edit deleting the lines of the declaration of name.
+func (f *File) declLines(name string) (Edit, error) {
+	recv, funcName := "", name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		recv = strings.Trim(name[:i], "()*")
+		funcName = name[i+1:]
+	}
+
+	for _, decl := range f.AST.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Name.Name == funcName && receiverName(d) == recv {
+				return f.nodeLines(d, d.Doc), nil
+			}
+		case *ast.GenDecl:
+			if recv != "" || d.Tok == token.IMPORT {
+				continue
+			}
+			for _, spec := range d.Specs {
+				if !declaresName(spec, name) {
+					continue
+				}
+				if len(d.Specs) == 1 {
+					return f.nodeLines(d, d.Doc), nil
+				}
+				var doc *ast.CommentGroup
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					doc = s.Doc
+				case *ast.ValueSpec:
+					doc = s.Doc
+				}
+				return f.nodeLines(spec, doc), nil
+			}
+		}
+	}
+	return Edit{}, fmt.Errorf("declaration of %s not found in %s", name, f.Path)
+}
+
+// nodeLines returns the edit deleting the lines of a node, from its doc comment to the end of
+// its last line, including a trailing comment on that line.
+func (f *File) nodeLines(node ast.Node, doc *ast.CommentGroup) Edit {
+	tf := f.Fset.File(f.AST.Pos())
+	start := node.Pos()
+	if doc != nil {
+		start = doc.Pos()
+	}
+	startOffset := tf.Offset(start)
+	for startOffset > 0 && (f.src[startOffset-1] == ' ' || f.src[startOffset-1] == '\t') {
+		startOffset--
+	}
+	endOffset := tf.Offset(node.End())
+	if i := bytes.IndexByte(f.src[endOffset:], '\n'); i >= 0 {
+		endOffset += i + 1
+	} else {
+		endOffset = len(f.src)
+	}
+	return Edit{Pos: tf.Pos(startOffset), End: tf.Pos(endOffset)}
+}
+
+// receiverName returns the name of the receiver type of a method, or "" for a function.
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	return embeddedName(fn.Recv.List[0].Type)
+}
+
+// declaresName reports whether a type or value spec declares name.
+func declaresName(spec ast.Spec, name string) bool {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return s.Name.Name == name
+	case *ast.ValueSpec:
+		for _, n := range s.Names {
+			if n.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
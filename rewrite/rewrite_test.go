@@ -0,0 +1,311 @@
+package rewrite_test
+
+import (
+	"context"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/rewrite"
+)
+
+const source = `// Package models is a sample.
+package models
+
+import "fmt"
+
+// User is a user.
+type User struct {
+	ID   int    // the identifier
+	Name string ` + "`" + `json:"name" yaml:"name"` + "`" + `
+}
+
+// unused is not called anymore.
+func unused() {
+	fmt.Println("unused")
+}
+
+// String returns the name.
+func (u *User) String() string { return u.Name }
+
+var (
+	// Default is the default user.
+	Default = User{}
+	Admin   = User{ID: 1} // the admin
+)
+`
+
+func TestFile(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(f *rewrite.File) error
+		want string
+	}{
+		{
+			name: "add import",
+			edit: func(f *rewrite.File) error { return f.AddImport("", "strings") },
+			want: `// Package models is a sample.
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+`,
+		},
+		{
+			name: "add tag to a field without tag",
+			edit: func(f *rewrite.File) error { return f.AddStructTag("User", "ID", "json", "id") },
+			want: "\tID   int    `json:\"id\"` // the identifier\n",
+		},
+		{
+			name: "replace a key of an existing tag",
+			edit: func(f *rewrite.File) error { return f.AddStructTag("User", "Name", "json", "name,omitempty") },
+			want: "\tName string `json:\"name,omitempty\" yaml:\"name\"`\n",
+		},
+		{
+			name: "delete a function with its doc comment",
+			edit: func(f *rewrite.File) error { return f.DeleteDecl("unused") },
+			want: `}
+
+// String returns the name.
+`,
+		},
+		{
+			name: "delete a method",
+			edit: func(f *rewrite.File) error { return f.DeleteDecl("(*User).String") },
+			want: `}
+
+var (
+`,
+		},
+		{
+			name: "comment out a function with a marker",
+			edit: func(f *rewrite.File) error { return f.CommentOutDecl("unused", "orphan") },
+			want: `// orphan
+// // unused is not called anymore.
+// func unused() {
+// 	fmt.Println("unused")
+// }
+
+// String returns the name.
+`,
+		},
+		{
+			name: "delete a variable from a group",
+			edit: func(f *rewrite.File) error { return f.DeleteDecl("Admin") },
+			want: `var (
+	// Default is the default user.
+	Default = User{}
+)
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := rewrite.NewFile("models.go", []byte(source))
+			if err != nil {
+				t.Fatalf("NewFile() failed: %v", err)
+			}
+			if err := tt.edit(f); err != nil {
+				t.Fatalf("edit failed: %v", err)
+			}
+			got := string(f.Source())
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("rewritten source does not contain %q:\n%s", tt.want, got)
+			}
+			// The comments of the other declarations are preserved.
+			for _, comment := range []string{"// Package models is a sample.", "// User is a user.", "// the identifier"} {
+				if !strings.Contains(got, comment) {
+					t.Errorf("comment %q is lost:\n%s", comment, got)
+				}
+			}
+		})
+	}
+}
+
+func TestFile_Apply(t *testing.T) {
+	f, err := rewrite.NewFile("models.go", []byte(source))
+	if err != nil {
+		t.Fatalf("NewFile() failed: %v", err)
+	}
+
+	var edits []rewrite.Edit
+	for _, decl := range f.AST.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "unused" {
+			edits = append(edits, rewrite.Edit{Pos: fn.Name.Pos(), End: fn.Name.End(), Text: "used"})
+		}
+	}
+	if err := f.Apply(edits...); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+	if !strings.Contains(string(f.Source()), "func used() {") {
+		t.Errorf("function is not renamed:\n%s", f.Source())
+	}
+
+	// An edit producing invalid source is rejected, and the file is left unchanged.
+	before := string(f.Source())
+	pos := f.AST.Name.Pos()
+	if err := f.Apply(rewrite.Edit{Pos: pos, End: pos, Text: "}"}); err == nil {
+		t.Errorf("Apply() should fail for invalid source")
+	}
+	if got := string(f.Source()); got != before {
+		t.Errorf("source changed by a failed edit:\n%s", got)
+	}
+}
+
+func TestFile_Imports(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		edit func(f *rewrite.File) error
+		want string
+	}{
+		{
+			name: "add the first import",
+			src:  "package p\n\nvar x = 1\n",
+			edit: func(f *rewrite.File) error { return f.AddImport("", "strings") },
+			want: "package p\n\nimport \"strings\"\n\nvar x = 1\n",
+		},
+		{
+			name: "add a named import to a group, sorted",
+			src:  "package p\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n",
+			edit: func(f *rewrite.File) error { return f.AddImport("str", "strings") },
+			want: "package p\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\tstr \"strings\"\n)\n",
+		},
+		{
+			name: "add an existing import",
+			src:  "package p\n\nimport \"fmt\"\n",
+			edit: func(f *rewrite.File) error { return f.AddImport("", "fmt") },
+			want: "package p\n\nimport \"fmt\"\n",
+		},
+		{
+			name: "delete an import from a group",
+			src:  "package p\n\nimport (\n\t\"fmt\"\n\t\"os\" // the os\n)\n",
+			edit: func(f *rewrite.File) error { return f.DeleteImport("", "os") },
+			want: "package p\n\nimport (\n\t\"fmt\"\n)\n",
+		},
+		{
+			name: "delete the only import",
+			src:  "package p\n\nimport \"fmt\"\n\nvar x = 1\n",
+			edit: func(f *rewrite.File) error { return f.DeleteImport("", "fmt") },
+			want: "package p\n\nvar x = 1\n",
+		},
+		{
+			name: "delete an import with another name",
+			src:  "package p\n\nimport f \"fmt\"\n",
+			edit: func(f *rewrite.File) error { return f.DeleteImport("", "fmt") },
+			want: "package p\n\nimport f \"fmt\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := rewrite.NewFile("p.go", []byte(tt.src))
+			if err != nil {
+				t.Fatalf("NewFile() failed: %v", err)
+			}
+			if err := tt.edit(f); err != nil {
+				t.Fatalf("edit failed: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, string(f.Source())); diff != "" {
+				t.Errorf("source mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFile_UsesImport(t *testing.T) {
+	src := `package p
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	str "strings"
+	"os"
+)
+
+func f(os string) string {
+	fmt.Println(http.StatusOK)
+	return os.Name
+}
+`
+	f, err := rewrite.NewFile("p.go", []byte(src))
+	if err != nil {
+		t.Fatalf("NewFile() failed: %v", err)
+	}
+	got := map[string]bool{}
+	for _, path := range []string{"embed", "fmt", "net/http", "strings", "os", "io"} {
+		got[path] = f.UsesImport(path)
+	}
+	// os.Name refers to the parameter, not to the package.
+	want := map[string]bool{"embed": true, "fmt": true, "net/http": true, "strings": false, "os": false, "io": false}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("UsesImport() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFile_DiffAndWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "models.go")
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := rewrite.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+	if f.Changed() || f.Diff() != "" {
+		t.Errorf("unmodified file should have no diff")
+	}
+	if err := f.DeleteDecl("unused"); err != nil {
+		t.Fatalf("DeleteDecl() failed: %v", err)
+	}
+
+	wantDiff := strings.Join([]string{
+		"--- a/" + path,
+		"+++ b/" + path,
+		"@@ -9,11 +9,6 @@",
+		" \tName string `json:\"name\" yaml:\"name\"`",
+		" }",
+		" ",
+		"-// unused is not called anymore.",
+		"-func unused() {",
+		"-\tfmt.Println(\"unused\")",
+		"-}",
+		"-",
+		" // String returns the name.",
+		" func (u *User) String() string { return u.Name }",
+		" ",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(wantDiff, f.Diff()); diff != "" {
+		t.Errorf("Diff() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The writing goes through the FileWriter of the context, as for the generated files.
+	written := map[string][]byte{}
+	ctx := context.WithValue(context.Background(), goscan.FileWriterKey, writerFunc(func(path string, data []byte) {
+		written[path] = data
+	}))
+	if err := f.WriteFile(ctx); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+	if got := string(written[path]); got != string(f.Source()) {
+		t.Errorf("unexpected written content:\n%s", got)
+	}
+}
+
+type writerFunc func(path string, data []byte)
+
+func (w writerFunc) WriteFile(ctx context.Context, path string, data []byte, perm os.FileMode) error {
+	w(path, data)
+	return nil
+}
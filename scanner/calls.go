@@ -0,0 +1,105 @@
+package scanner
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// CallInfo is a call in the body of a function, found from the syntax only, without type
+// information. It is collected when Scanner.CollectCalls is set, for the tools which do not
+// need the precision of the symbolic execution, e.g. to find the callees of a function quickly.
+type CallInfo struct {
+	// Name is the name of the called function or method, e.g. "Println" for fmt.Println(x).
+	Name string `json:"name"`
+	// PkgPath is the import path of the package of the called function, for a call of an
+	// imported function (pkg.Func) or of a function of the same package (Func). It is empty when
+	// the callee is not known statically, e.g. for a method call or a call of a variable.
+	PkgPath string `json:"pkgPath,omitempty"`
+	// Receiver is the expression the method is selected from, e.g. "s.db" for s.db.Query(q),
+	// or empty for a function call.
+	Receiver string `json:"receiver,omitempty"`
+	// Pos is the position of the call.
+	Pos token.Pos `json:"-"`
+
+	local bool // Whether Name is declared in the function, e.g. a variable holding a function.
+}
+
+// IsStatic reports whether the callee is known from the syntax, i.e. PkgPath is set.
+func (c CallInfo) IsStatic() bool {
+	return c.PkgPath != ""
+}
+
+// collectCalls returns the calls in the body of a function, including the ones in its function
+// literals, in the order of the source. The calls of the builtin functions and the conversions
+// to the predeclared types are skipped.
+func collectCalls(body *ast.BlockStmt, importLookup map[string]string) []CallInfo {
+	if body == nil {
+		return nil
+	}
+	var calls []CallInfo
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fun := ast.Unparen(call.Fun)
+		switch x := fun.(type) { // The instantiations of the generic functions, e.g. Map[int](xs).
+		case *ast.IndexExpr:
+			fun = x.X
+		case *ast.IndexListExpr:
+			fun = x.X
+		}
+		switch fun := fun.(type) {
+		case *ast.Ident:
+			if fun.Obj == nil && types.Universe.Lookup(fun.Name) != nil {
+				return true // A builtin function, or a conversion to a predeclared type.
+			}
+			c := CallInfo{Name: fun.Name, Pos: call.Pos()}
+			// The parser resolves the identifiers declared in the file; the functions of the
+			// other files of the package are unresolved, and are found by resolveLocalCalls.
+			c.local = fun.Obj != nil && fun.Obj.Kind != ast.Fun
+			calls = append(calls, c)
+		case *ast.SelectorExpr:
+			if x, ok := fun.X.(*ast.Ident); ok && x.Obj == nil {
+				if path, ok := importLookup[x.Name]; ok {
+					calls = append(calls, CallInfo{Name: fun.Sel.Name, PkgPath: path, Pos: call.Pos()})
+					return true
+				}
+			}
+			calls = append(calls, CallInfo{Name: fun.Sel.Name, Receiver: types.ExprString(fun.X), Pos: call.Pos()})
+		}
+		return true
+	})
+	return calls
+}
+
+// resolveLocalCalls sets the package of the calls of the functions of the package by name,
+// and drops the conversions to the types of the package.
+func resolveLocalCalls(info *PackageInfo) {
+	funcs := make(map[string]bool)
+	for _, fn := range info.Functions {
+		if fn.Receiver == nil {
+			funcs[fn.Name] = true
+		}
+	}
+	typeNames := make(map[string]bool, len(info.Types))
+	for _, t := range info.Types {
+		typeNames[t.Name] = true
+	}
+	for _, fn := range info.Functions {
+		calls := fn.Calls[:0]
+		for _, c := range fn.Calls {
+			if c.PkgPath == "" && c.Receiver == "" && !c.local {
+				if typeNames[c.Name] {
+					continue
+				}
+				if funcs[c.Name] {
+					c.PkgPath = info.ImportPath
+				}
+			}
+			calls = append(calls, c)
+		}
+		fn.Calls = calls
+	}
+}
@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestScanner_CollectCalls(t *testing.T) {
+	files := map[string]string{
+		"main.go": `package mymodule
+
+import (
+	"fmt"
+	str "strings"
+)
+
+type Server struct{ name string }
+
+func (s *Server) Start() {
+	fmt.Println(str.ToUpper(s.name))
+	s.log("start")
+	helper(len(s.name))
+}
+
+func Run(handle func()) {
+	s := &Server{}
+	s.Start()
+	handle()
+	fmt := Server{} // shadows the package
+	fmt.Start()
+	_ = Map[int](nil)
+	func() { other() }()
+	_ = Config(nil)
+	_ = string("conversion")
+}
+
+func helper(n int) {}
+`,
+		"other.go": `package mymodule
+
+type Config []string
+
+func (s *Server) log(msg string) {}
+
+func other() {}
+
+func Map[T any](xs []T) []T { return xs }
+`,
+	}
+	dir := t.TempDir()
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	for _, mode := range []LoadMode{LoadFull, LoadDecls} {
+		t.Run(mode.String(), func(t *testing.T) {
+			s := newTestScanner(t, "example.com/mymodule", dir)
+			s.DefaultLoadMode = mode
+			s.CollectCalls = true
+			pkg, err := s.ScanFiles(context.Background(), paths, dir)
+			if err != nil {
+				t.Fatalf("ScanFiles failed: %v", err)
+			}
+			got := make(map[string][]CallInfo)
+			for _, fn := range pkg.Functions {
+				got[fn.Name] = fn.Calls
+			}
+			want := map[string][]CallInfo{
+				"Start": {
+					{Name: "Println", PkgPath: "fmt"},
+					{Name: "ToUpper", PkgPath: "strings"},
+					{Name: "log", Receiver: "s"},
+					{Name: "helper", PkgPath: "example.com/mymodule"},
+				},
+				"Run": {
+					{Name: "Start", Receiver: "s"},
+					{Name: "handle"},
+					{Name: "Start", Receiver: "fmt"},
+					{Name: "Map", PkgPath: "example.com/mymodule"},
+					{Name: "other", PkgPath: "example.com/mymodule"},
+				},
+				"helper": nil,
+				"log":    nil,
+				"other":  nil,
+				"Map":    nil,
+			}
+			if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(CallInfo{}, "Pos"), cmpopts.IgnoreUnexported(CallInfo{}), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Calls mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		s := newTestScanner(t, "example.com/mymodule", dir)
+		pkg, err := s.ScanFiles(context.Background(), paths, dir)
+		if err != nil {
+			t.Fatalf("ScanFiles failed: %v", err)
+		}
+		for _, fn := range pkg.Functions {
+			if fn.Calls != nil {
+				t.Errorf("%s: want no calls without CollectCalls, got %v", fn.Name, fn.Calls)
+			}
+		}
+	})
+}
@@ -0,0 +1,241 @@
+package scanner
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// CanonicalName is the fully-qualified name of a function or method, in the form used by
+// go/types and go/ssa:
+//
+//	example.com/pkg.Func
+//	(example.com/pkg.Foo).Method
+//	(*example.com/pkg.Foo).Method
+//	(*example.com/pkg.Box[T]).Get
+//
+// The zero TypeName means a function.
+type CanonicalName struct {
+	PkgPath    string
+	TypeName   string   // The receiver's type name, without type parameters; empty for functions.
+	TypeParams []string // The receiver's type parameters as written in the method declaration.
+	IsPointer  bool     // Whether the receiver is a pointer.
+	Name       string
+}
+
+// IsMethod reports whether the name refers to a method.
+func (n CanonicalName) IsMethod() bool {
+	return n.TypeName != ""
+}
+
+// Receiver returns the canonical receiver type, e.g. "*example.com/pkg.Box[T]",
+// or "" for functions.
+func (n CanonicalName) Receiver() string {
+	if !n.IsMethod() {
+		return ""
+	}
+	var sb strings.Builder
+	if n.IsPointer {
+		sb.WriteString("*")
+	}
+	sb.WriteString(n.PkgPath)
+	sb.WriteString(".")
+	sb.WriteString(n.TypeName)
+	if len(n.TypeParams) > 0 {
+		sb.WriteString("[")
+		sb.WriteString(strings.Join(n.TypeParams, ", "))
+		sb.WriteString("]")
+	}
+	return sb.String()
+}
+
+// String returns the canonical form of the name.
+func (n CanonicalName) String() string {
+	if !n.IsMethod() {
+		return n.PkgPath + "." + n.Name
+	}
+	return "(" + n.Receiver() + ")." + n.Name
+}
+
+// Matches reports whether both names refer to the same function or method.
+// The names of the receiver's type parameters are not significant, so they are ignored.
+func (n CanonicalName) Matches(other CanonicalName) bool {
+	return n.PkgPath == other.PkgPath &&
+		n.TypeName == other.TypeName &&
+		n.IsPointer == other.IsPointer &&
+		n.Name == other.Name
+}
+
+// ParseCanonicalName parses a name in the form returned by CanonicalName.String.
+// For compatibility with the names accepted by existing tools, the receiver may also be
+// qualified outside the parentheses, as in "example.com/pkg.(*Foo).Method", and the pointer
+// may follow the package path, as in "(example.com/pkg.*Foo).Method".
+// The legacy "example.com/pkg.Foo.Method" form is parsed as the function Method of the package
+// "example.com/pkg.Foo", as the two can't be told apart without knowing the packages.
+func ParseCanonicalName(s string) (CanonicalName, error) {
+	var n CanonicalName
+	if strings.HasPrefix(s, "(") {
+		end := strings.LastIndex(s, ").")
+		if end == -1 {
+			return n, fmt.Errorf("invalid canonical name %q: unclosed receiver", s)
+		}
+		recv, name := s[1:end], s[end+2:]
+		if strings.HasPrefix(recv, "*") {
+			n.IsPointer = true
+			recv = recv[1:]
+		}
+		lastDot := strings.LastIndex(stripTypeParams(recv), ".")
+		if lastDot == -1 {
+			return n, fmt.Errorf("invalid canonical name %q: receiver is not qualified by a package path", s)
+		}
+		n.PkgPath, recv = recv[:lastDot], recv[lastDot+1:]
+		if strings.HasPrefix(recv, "*") {
+			n.IsPointer = true
+			recv = recv[1:]
+		}
+		n.TypeName, n.TypeParams = splitTypeParams(recv)
+		n.Name = name
+	} else if open := strings.Index(s, ".("); open != -1 {
+		end := strings.LastIndex(s, ").")
+		if end < open {
+			return n, fmt.Errorf("invalid canonical name %q: unclosed receiver", s)
+		}
+		n.PkgPath = s[:open]
+		recv := s[open+2 : end]
+		if strings.HasPrefix(recv, "*") {
+			n.IsPointer = true
+			recv = recv[1:]
+		}
+		n.TypeName, n.TypeParams = splitTypeParams(recv)
+		n.Name = s[end+2:]
+	} else {
+		lastDot := strings.LastIndex(s, ".")
+		if lastDot == -1 {
+			return n, fmt.Errorf("invalid canonical name %q: expected <pkg>.<func>", s)
+		}
+		n.PkgPath, n.Name = s[:lastDot], s[lastDot+1:]
+	}
+
+	if n.PkgPath == "" || n.Name == "" || strings.ContainsAny(n.Name, ".()[]*") {
+		return CanonicalName{}, fmt.Errorf("invalid canonical name %q", s)
+	}
+	if !n.IsMethod() && (n.IsPointer || len(n.TypeParams) > 0) {
+		return CanonicalName{}, fmt.Errorf("invalid canonical name %q: empty receiver type", s)
+	}
+	if strings.ContainsAny(n.TypeName, ".()[]*") {
+		return CanonicalName{}, fmt.Errorf("invalid canonical name %q: invalid receiver type %q", s, n.TypeName)
+	}
+	return n, nil
+}
+
+// stripTypeParams removes a trailing type parameter list, e.g. "Box[T]" -> "Box".
+func stripTypeParams(s string) string {
+	if i := strings.Index(s, "["); i != -1 {
+		return s[:i]
+	}
+	return s
+}
+
+// splitTypeParams splits "Box[K, V]" into "Box" and ["K", "V"].
+func splitTypeParams(s string) (string, []string) {
+	open := strings.Index(s, "[")
+	if open == -1 || !strings.HasSuffix(s, "]") {
+		return s, nil
+	}
+	var params []string
+	for _, p := range strings.Split(s[open+1:len(s)-1], ",") {
+		params = append(params, strings.TrimSpace(p))
+	}
+	return s[:open], params
+}
+
+// CanonicalName returns the canonical name of the function or method.
+// See CanonicalName for the format.
+func (f *FunctionInfo) CanonicalName() CanonicalName {
+	n := CanonicalName{PkgPath: f.PkgPath, Name: f.Name}
+	if n.PkgPath == "" && f.Pkg != nil {
+		n.PkgPath = f.Pkg.ImportPath
+	}
+
+	if f.AstDecl != nil && f.AstDecl.Recv != nil && len(f.AstDecl.Recv.List) > 0 {
+		n.TypeName, n.IsPointer, n.TypeParams = receiverFromExpr(f.AstDecl.Recv.List[0].Type)
+		return n
+	}
+	if f.Receiver != nil && f.Receiver.Type != nil {
+		// Without the declaration (e.g. for a synthesized FunctionInfo), fall back to the parsed type.
+		ft := f.Receiver.Type
+		n.IsPointer = ft.IsPointer
+		if ft.IsPointer && ft.Elem != nil {
+			ft = ft.Elem
+		}
+		n.TypeName = ft.TypeName
+		if n.TypeName == "" {
+			n.TypeName = ft.Name
+			if i := strings.LastIndex(n.TypeName, "."); i != -1 {
+				n.TypeName = n.TypeName[i+1:]
+			}
+		}
+		n.TypeParams = f.ReceiverTypeParams
+	}
+	return n
+}
+
+// ReceiverCanonical returns the canonical receiver type of a method, fully qualified by its
+// package path, e.g. "*example.com/pkg.Foo" or "example.com/pkg.Box[T]". It returns "" for functions.
+func (f *FunctionInfo) ReceiverCanonical() string {
+	return f.CanonicalName().Receiver()
+}
+
+// receiverFromExpr extracts the type name, pointer-ness and type parameter names
+// from a receiver type expression, e.g. `*Box[T]`.
+func receiverFromExpr(expr ast.Expr) (typeName string, isPointer bool, typeParams []string) {
+	expr = unparenExpr(expr)
+	if star, ok := expr.(*ast.StarExpr); ok {
+		isPointer = true
+		expr = unparenExpr(star.X)
+	}
+	var indices []ast.Expr
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr, indices = t.X, []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		expr, indices = t.X, t.Indices
+	}
+	if id, ok := expr.(*ast.Ident); ok {
+		typeName = id.Name
+	}
+	for _, idx := range indices {
+		if id, ok := idx.(*ast.Ident); ok {
+			typeParams = append(typeParams, id.Name)
+		}
+	}
+	return typeName, isPointer, typeParams
+}
+
+func unparenExpr(expr ast.Expr) ast.Expr {
+	for {
+		p, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = p.X
+	}
+}
+
+// renameReceiverTypeParams returns the type parameters of a receiver's base type, renamed to the
+// names used in the method's receiver (e.g. `func (b *Box[U]) Get() U` for `type Box[T any]`),
+// so that the method's signature resolves them.
+func renameReceiverTypeParams(typeParams []*TypeParamInfo, names []string) []*TypeParamInfo {
+	if len(names) != len(typeParams) {
+		return typeParams
+	}
+	renamed := make([]*TypeParamInfo, len(typeParams))
+	for i, tp := range typeParams {
+		if tp.Name == names[i] || names[i] == "_" {
+			renamed[i] = tp
+			continue
+		}
+		renamed[i] = &TypeParamInfo{Name: names[i], Constraint: tp.Constraint}
+	}
+	return renamed
+}
@@ -0,0 +1,138 @@
+package scanner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestFunctionInfo_CanonicalName(t *testing.T) {
+	source := `
+package lib
+
+type Foo struct{}
+
+func (f Foo) Value() {}
+func (f *Foo) Pointer() {}
+
+type Box[T any] struct{ v T }
+
+func (b *Box[U]) Get() U { return b.v }
+
+type Pair[K comparable, V any] struct{}
+
+func (Pair[K, V]) Keys() []K { return nil }
+
+func Func() {}
+`
+	workdir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod":     "module example.com/lib",
+		"lib/lib.go": source,
+	})
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithWorkDir(workdir), goscan.WithGoModuleResolver())
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	pkg, err := s.ScanPackageFromImportPath(context.Background(), "example.com/lib/lib")
+	if err != nil {
+		t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+	}
+
+	type result struct {
+		Canonical string
+		Receiver  string
+	}
+	got := make(map[string]result)
+	for _, f := range pkg.Functions {
+		got[f.Name] = result{Canonical: f.CanonicalName().String(), Receiver: f.ReceiverCanonical()}
+	}
+	want := map[string]result{
+		"Value":   {"(example.com/lib/lib.Foo).Value", "example.com/lib/lib.Foo"},
+		"Pointer": {"(*example.com/lib/lib.Foo).Pointer", "*example.com/lib/lib.Foo"},
+		"Get":     {"(*example.com/lib/lib.Box[U]).Get", "*example.com/lib/lib.Box[U]"},
+		"Keys":    {"(example.com/lib/lib.Pair[K, V]).Keys", "example.com/lib/lib.Pair[K, V]"},
+		"Func":    {"example.com/lib/lib.Func", ""},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("canonical names mismatch (-want +got):\n%s", diff)
+	}
+
+	// The type parameters renamed in the receiver resolve in the method's signature.
+	for _, f := range pkg.Functions {
+		if f.Name != "Get" {
+			continue
+		}
+		if diff := cmp.Diff([]string{"U"}, f.ReceiverTypeParams); diff != "" {
+			t.Errorf("ReceiverTypeParams mismatch (-want +got):\n%s", diff)
+		}
+		if len(f.Results) != 1 || !f.Results[0].Type.IsTypeParam {
+			t.Errorf("expected the result of Get to be the type parameter U, got %+v", f.Results)
+		}
+	}
+}
+
+func TestParseCanonicalName(t *testing.T) {
+	cases := []struct {
+		in   string
+		want scanner.CanonicalName
+		str  string // the canonical form, if it differs from in
+	}{
+		{in: "example.com/pkg.Func", want: scanner.CanonicalName{PkgPath: "example.com/pkg", Name: "Func"}},
+		{in: "gopkg.in/yaml.v3.Marshal", want: scanner.CanonicalName{PkgPath: "gopkg.in/yaml.v3", Name: "Marshal"}},
+		// The legacy "pkg.Type.Method" form can't be told from a function syntactically.
+		{in: "example.com/pkg.Foo.M", want: scanner.CanonicalName{PkgPath: "example.com/pkg.Foo", Name: "M"}},
+		{in: "(example.com/pkg.Foo).M", want: scanner.CanonicalName{PkgPath: "example.com/pkg", TypeName: "Foo", Name: "M"}},
+		{in: "(*example.com/pkg.Foo).M", want: scanner.CanonicalName{PkgPath: "example.com/pkg", TypeName: "Foo", IsPointer: true, Name: "M"}},
+		{
+			in:   "(*example.com/pkg.Pair[K, V]).M",
+			want: scanner.CanonicalName{PkgPath: "example.com/pkg", TypeName: "Pair", TypeParams: []string{"K", "V"}, IsPointer: true, Name: "M"},
+		},
+		{
+			in:   "example.com/pkg.(*Foo).M",
+			want: scanner.CanonicalName{PkgPath: "example.com/pkg", TypeName: "Foo", IsPointer: true, Name: "M"},
+			str:  "(*example.com/pkg.Foo).M",
+		},
+		{
+			in:   "(example.com/pkg.*Foo).M",
+			want: scanner.CanonicalName{PkgPath: "example.com/pkg", TypeName: "Foo", IsPointer: true, Name: "M"},
+			str:  "(*example.com/pkg.Foo).M",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := scanner.ParseCanonicalName(tc.in)
+			if err != nil {
+				t.Fatalf("ParseCanonicalName() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+			str := tc.str
+			if str == "" {
+				str = tc.in
+			}
+			if got.String() != str {
+				t.Errorf("String() = %q, want %q", got.String(), str)
+			}
+		})
+	}
+
+	for _, in := range []string{"Func", "(Foo).M", "(*example.com/pkg.Foo", "example.com/pkg.", "(example.com/pkg.Foo).a.b"} {
+		if _, err := scanner.ParseCanonicalName(in); err == nil {
+			t.Errorf("ParseCanonicalName(%q) succeeded, want error", in)
+		}
+	}
+
+	// Type parameter names are not significant when matching.
+	a, _ := scanner.ParseCanonicalName("(*example.com/pkg.Box[T]).Get")
+	b, _ := scanner.ParseCanonicalName("(*example.com/pkg.Box).Get")
+	if !a.Matches(b) {
+		t.Errorf("expected %s to match %s", a, b)
+	}
+}
@@ -0,0 +1,24 @@
+package scanner
+
+import (
+	"go/ast"
+	"strconv"
+)
+
+// CgoImportPath is the import path of the pseudo-package of cgo. It does not
+// refer to a directory, so it is never scanned: C.xxx references are left
+// unresolved, and the pure-Go declarations of the file are collected as usual.
+const CgoImportPath = "C"
+
+// importsC reports whether the file uses cgo, i.e. it has `import "C"`.
+func importsC(file *ast.File) bool {
+	for _, imp := range file.Imports {
+		if imp.Path == nil {
+			continue
+		}
+		if path, err := strconv.Unquote(imp.Path.Value); err == nil && path == CgoImportPath {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package scanner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestScanner_Cgo(t *testing.T) {
+	cgoSource := `package lib
+
+/*
+#include <stdlib.h>
+
+static int add(int a, int b) { return a + b; }
+*/
+import "C"
+
+import "unsafe"
+
+// Buffer wraps a C allocated buffer.
+type Buffer struct {
+	ptr  *C.char
+	Size int
+}
+
+// Add adds two numbers in C.
+func Add(a, b int) int {
+	return int(C.add(C.int(a), C.int(b)))
+}
+
+// Free releases the buffer.
+func (b *Buffer) Free() {
+	C.free(unsafe.Pointer(b.ptr))
+}
+`
+	workdir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod":     "module example.com/cgo",
+		"lib/lib.go": cgoSource,
+		"lib/go.go":  "package lib\n\nimport \"strings\"\n\nfunc Upper(s string) string { return strings.ToUpper(s) }\n",
+	})
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithWorkDir(workdir), goscan.WithGoModuleResolver())
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("package", func(t *testing.T) {
+		pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/cgo/lib")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+		}
+		if !pkg.UsesCgo {
+			t.Errorf("UsesCgo = false, want true")
+		}
+		if len(pkg.CgoFiles) != 1 || pkg.CgoFiles[0] != workdir+"/lib/lib.go" {
+			t.Errorf("CgoFiles = %v, want only lib.go", pkg.CgoFiles)
+		}
+
+		var funcs []string
+		for _, f := range pkg.Functions {
+			funcs = append(funcs, f.Name)
+		}
+		if diff := cmp.Diff([]string{"Add", "Free", "Upper"}, funcs); diff != "" {
+			t.Errorf("functions mismatch (-want +got):\n%s", diff)
+		}
+
+		buffer := pkg.Lookup("Buffer")
+		if buffer == nil || buffer.Struct == nil {
+			t.Fatalf("struct Buffer not found")
+		}
+		ptr := buffer.Struct.Fields[0].Type
+		if got := ptr.String(); got != "*C.char" {
+			t.Errorf("field type = %q, want %q", got, "*C.char")
+		}
+		def, err := s.ResolveType(ctx, ptr)
+		if err != nil {
+			t.Fatalf("ResolveType(*C.char) failed: %v", err)
+		}
+		want := scanner.NewUnresolvedTypeInfo("C", "char")
+		if def == nil || def.PkgPath != want.PkgPath || def.Name != want.Name || !def.Unresolved {
+			t.Errorf("ResolveType(*C.char) = %+v, want an unresolved C.char", def)
+		}
+	})
+
+	t.Run("imports", func(t *testing.T) {
+		imports, err := s.Walker.ScanPackageFromFilePathImports(ctx, "example.com/cgo/lib")
+		if err != nil {
+			t.Fatalf("ScanPackageFromFilePathImports() failed: %v", err)
+		}
+		if !imports.UsesCgo {
+			t.Errorf("UsesCgo = false, want true")
+		}
+		want := map[string]bool{"strings": true, "unsafe": true}
+		got := make(map[string]bool)
+		for _, imp := range imports.Imports {
+			got[imp] = true
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("imports mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package scanner
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// Directive is a comment directive: a line comment without a space after the slashes,
+// e.g. `//go:generate stringer -type=Kind`, `//nolint:errcheck` or `//go:scan:ignore`.
+type Directive struct {
+	// Name is the first word of the directive, e.g. "go:generate" or "nolint:errcheck".
+	Name string `json:"name"`
+	// Args is the rest of the directive, with the surrounding spaces trimmed,
+	// e.g. "stringer -type=Kind" for `//go:generate stringer -type=Kind`.
+	Args     string `json:"args,omitempty"`
+	FilePath string `json:"filePath"`
+	Line     int    `json:"line"`
+	// Decl is the name of the declaration the directive is attached to, either as (a part of)
+	// its doc comment or inside it, e.g. "Run" for a function, "(*Server).Start" for a method,
+	// "Kind" for a type, or "" for a directive outside of the declarations, like `//go:build`.
+	// It is also "" for the doc comment of a group declaration with several specs.
+	Decl string `json:"decl,omitempty"`
+	// DeclNode is the declaration (an *ast.FuncDecl, *ast.GenDecl, or ast.Spec), or nil.
+	DeclNode ast.Node     `json:"-"`
+	Comment  *ast.Comment `json:"-"`
+}
+
+// DirectivesNamed returns the directives of the package with the given name, in source order.
+func (p *PackageInfo) DirectivesNamed(name string) []*Directive {
+	var found []*Directive
+	for _, d := range p.Directives {
+		if d.Name == name {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+// parseDirective parses the text of a comment as a directive.
+// Only `//` comments starting with a lowercase letter or a digit are directives,
+// as `// text` and `//TODO` are not.
+func parseDirective(text string) (name, args string, ok bool) {
+	rest, found := strings.CutPrefix(text, "//")
+	if !found || rest == "" || !(('a' <= rest[0] && rest[0] <= 'z') || ('0' <= rest[0] && rest[0] <= '9')) {
+		return "", "", false
+	}
+	if i := strings.IndexAny(rest, " \t"); i >= 0 {
+		return rest[:i], strings.TrimSpace(rest[i:]), true
+	}
+	return rest, "", true
+}
+
+// collectDirectives returns the directives of the file, in source order.
+func collectDirectives(fset *token.FileSet, file *ast.File, filePath string) []*Directive {
+	var directives []*Directive
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			name, args, ok := parseDirective(c.Text)
+			if !ok {
+				continue
+			}
+			d := &Directive{
+				Name:     name,
+				Args:     args,
+				FilePath: filePath,
+				Line:     fset.Position(c.Pos()).Line,
+				Comment:  c,
+			}
+			d.Decl, d.DeclNode = directiveDecl(file, c.Pos())
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
+
+// directiveDecl finds the declaration a comment at pos belongs to, by the doc comment or by the range.
+func directiveDecl(file *ast.File, pos token.Pos) (string, ast.Node) {
+	within := func(doc *ast.CommentGroup, node ast.Node) bool {
+		return (doc != nil && doc.Pos() <= pos && pos < doc.End()) || (node.Pos() <= pos && pos < node.End())
+	}
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if within(decl.Doc, decl) {
+				return funcDeclName(decl), decl
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if within(specDoc(spec), spec) {
+					return specName(spec), spec
+				}
+			}
+			if within(decl.Doc, decl) {
+				if len(decl.Specs) == 1 {
+					return specName(decl.Specs[0]), decl
+				}
+				return "", decl
+			}
+		}
+	}
+	return "", nil
+}
+
+func specDoc(spec ast.Spec) *ast.CommentGroup {
+	switch spec := spec.(type) {
+	case *ast.TypeSpec:
+		return spec.Doc
+	case *ast.ValueSpec:
+		return spec.Doc
+	case *ast.ImportSpec:
+		return spec.Doc
+	}
+	return nil
+}
+
+// specName returns the name declared by the spec, or the first one of a value spec.
+func specName(spec ast.Spec) string {
+	switch spec := spec.(type) {
+	case *ast.TypeSpec:
+		return spec.Name.Name
+	case *ast.ValueSpec:
+		if len(spec.Names) > 0 {
+			return spec.Names[0].Name
+		}
+	}
+	return ""
+}
+
+// funcDeclName returns the name of the function, or "T.Name" or "(*T).Name" for a method.
+func funcDeclName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+	recv := decl.Recv.List[0].Type
+	star, isPointer := recv.(*ast.StarExpr)
+	if isPointer {
+		recv = star.X
+	}
+	switch r := recv.(type) {
+	case *ast.IndexExpr:
+		recv = r.X
+	case *ast.IndexListExpr:
+		recv = r.X
+	}
+	ident, ok := recv.(*ast.Ident)
+	if !ok {
+		return decl.Name.Name
+	}
+	if isPointer {
+		return "(*" + ident.Name + ")." + decl.Name.Name
+	}
+	return ident.Name + "." + decl.Name.Name
+}
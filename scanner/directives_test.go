@@ -0,0 +1,84 @@
+package scanner_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestScanner_Directives(t *testing.T) {
+	source := `//go:build !windows
+
+//go:generate stringer -type=Kind
+package app
+
+// Kind is a kind.
+//
+//go:scan:ignore
+type Kind int
+
+const (
+	//enum:default
+	A Kind = iota
+	B
+)
+
+//TODO: not a directive
+// not a directive either
+
+type Server struct {
+	name string //nolint:unused
+}
+
+//export Start
+func (s *Server) Start() {
+	_ = s.name //nolint:errcheck,gosec because
+}
+
+/* //go:noinline is in a block comment */
+func Run() {}
+`
+	workdir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod": "module example.com/app",
+		"app.go": source,
+	})
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithWorkDir(workdir), goscan.WithGoModuleResolver())
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	pkg, err := s.ScanPackageFromImportPath(context.Background(), "example.com/app")
+	if err != nil {
+		t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+	}
+
+	type directive struct {
+		Name, Args, File, Decl string
+		Line                   int
+	}
+	var got []directive
+	for _, d := range pkg.Directives {
+		got = append(got, directive{Name: d.Name, Args: d.Args, File: filepath.Base(d.FilePath), Decl: d.Decl, Line: d.Line})
+	}
+	want := []directive{
+		{Name: "go:build", Args: "!windows", File: "app.go", Line: 1},
+		{Name: "go:generate", Args: "stringer -type=Kind", File: "app.go", Line: 3},
+		{Name: "go:scan:ignore", File: "app.go", Decl: "Kind", Line: 8},
+		{Name: "enum:default", File: "app.go", Decl: "A", Line: 12},
+		{Name: "nolint:unused", File: "app.go", Decl: "Server", Line: 21},
+		{Name: "export", Args: "Start", File: "app.go", Decl: "(*Server).Start", Line: 24},
+		{Name: "nolint:errcheck,gosec", Args: "because", File: "app.go", Decl: "(*Server).Start", Line: 26},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Directives mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := pkg.DirectivesNamed("go:generate"); len(got) != 1 || got[0].Args != "stringer -type=Kind" {
+		t.Errorf("DirectivesNamed(go:generate) = %v", got)
+	}
+}
@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EmbedInfo is a variable initialized with the files matched by its `//go:embed` directives.
+type EmbedInfo struct {
+	// Variable is the embedding variable, whose type is string, []byte or embed.FS.
+	Variable *VariableInfo
+	// Patterns are the patterns of all the directives of the variable, in source order,
+	// e.g. ["static/*", "index.html"] for `//go:embed static/* "index.html"`.
+	Patterns   []string
+	Directives []*Directive
+}
+
+// EmbedOf returns the embed of the variable with the given name, or nil if it is not embedding files.
+func (p *PackageInfo) EmbedOf(name string) *EmbedInfo {
+	for _, e := range p.Embeds {
+		if e.Variable.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// linkEmbeds collects the `//go:embed` directives of the package into info.Embeds, setting the
+// EmbedPatterns of their variables. The directives not attached to a variable are ignored,
+// as the compiler rejects them.
+func linkEmbeds(info *PackageInfo) {
+	variables := make(map[string]*VariableInfo, len(info.Variables))
+	for _, v := range info.Variables {
+		variables[v.FilePath+"\x00"+v.Name] = v
+	}
+	embeds := make(map[*VariableInfo]*EmbedInfo)
+	for _, d := range info.DirectivesNamed("go:embed") {
+		v, ok := variables[d.FilePath+"\x00"+d.Decl]
+		if !ok || d.Decl == "" {
+			continue
+		}
+		patterns, err := parseEmbedPatterns(d.Args)
+		if err != nil {
+			continue
+		}
+		e, ok := embeds[v]
+		if !ok {
+			e = &EmbedInfo{Variable: v}
+			embeds[v] = e
+			info.Embeds = append(info.Embeds, e)
+		}
+		e.Patterns = append(e.Patterns, patterns...)
+		e.Directives = append(e.Directives, d)
+		v.EmbedPatterns = e.Patterns
+	}
+}
+
+// parseEmbedPatterns splits the arguments of a `//go:embed` directive into patterns, which
+// are separated by spaces and may be double-quoted or back-quoted Go strings, as go/build does.
+func parseEmbedPatterns(args string) ([]string, error) {
+	var patterns []string
+	for args = strings.TrimLeftFunc(args, unicode.IsSpace); args != ""; args = strings.TrimLeftFunc(args, unicode.IsSpace) {
+		var pattern string
+		switch args[0] {
+		default:
+			i := strings.IndexFunc(args, unicode.IsSpace)
+			if i < 0 {
+				i = len(args)
+			}
+			pattern, args = args[:i], args[i:]
+		case '`':
+			i := strings.Index(args[1:], "`")
+			if i < 0 {
+				return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args)
+			}
+			pattern, args = args[1:1+i], args[2+i:]
+		case '"':
+			i := 1
+			for ; i < len(args); i++ {
+				if args[i] == '\\' {
+					i++
+					continue
+				}
+				if args[i] == '"' {
+					break
+				}
+			}
+			if i >= len(args) {
+				return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args)
+			}
+			q, err := strconv.Unquote(args[:i+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args[:i+1])
+			}
+			pattern, args = q, args[i+1:]
+		}
+		if r, _ := utf8.DecodeRuneInString(args); args != "" && !unicode.IsSpace(r) {
+			return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", args)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,81 @@
+package scanner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestScanner_Embeds(t *testing.T) {
+	source := `package app
+
+import (
+	"embed"
+	_ "embed"
+)
+
+//go:embed version.txt
+var version string
+
+// Assets are the static files.
+//
+//go:embed static/* "my file.txt"
+//go:embed ` + "`templates`" + `
+var Assets embed.FS
+
+var (
+	//go:embed logo.png
+	logo []byte
+
+	plain = "not embedded"
+)
+
+//go:embed misplaced.txt
+func Run() {}
+`
+	workdir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod": "module example.com/app",
+		"app.go": source,
+	})
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithWorkDir(workdir), goscan.WithGoModuleResolver())
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	pkg, err := s.ScanPackageFromImportPath(context.Background(), "example.com/app")
+	if err != nil {
+		t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, e := range pkg.Embeds {
+		got[e.Variable.Name] = e.Patterns
+		if len(e.Directives) == 0 {
+			t.Errorf("%s: want the directives of the embed", e.Variable.Name)
+		}
+	}
+	want := map[string][]string{
+		"version": {"version.txt"},
+		"Assets":  {"static/*", "my file.txt", "templates"},
+		"logo":    {"logo.png"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Embeds mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, v := range pkg.Variables {
+		if diff := cmp.Diff(want[v.Name], v.EmbedPatterns); diff != "" {
+			t.Errorf("%s: EmbedPatterns mismatch (-want +got):\n%s", v.Name, diff)
+		}
+	}
+	if e := pkg.EmbedOf("Assets"); e == nil || len(e.Directives) != 2 {
+		t.Errorf("EmbedOf(%q) = %v, want an embed with 2 directives", "Assets", e)
+	}
+	if e := pkg.EmbedOf("plain"); e != nil {
+		t.Errorf("EmbedOf(%q) = %v, want nil", "plain", e)
+	}
+}
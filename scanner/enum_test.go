@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"go/token"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -149,3 +150,156 @@ func TestEnumScanning_LazyLoaded(t *testing.T) {
 		t.Errorf("Expected 3 enum members for Status, but got %d", len(resolvedType.EnumMembers))
 	}
 }
+
+func TestEnumScanning_Members(t *testing.T) {
+	source := `package main
+
+const (
+	Active  Status = "active"
+	Blocked        = Status("blocked")
+	Other          = "untyped" // not a member, an untyped constant
+)
+
+type Status string
+
+type Color int
+
+const (
+	_ Color = iota
+	Red
+	Green
+	Blue = Green + 10
+)
+
+const Yellow Color = 7
+`
+	testDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(testDir, "main.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	s := newTestScanner(t, "mymodule", testDir)
+	pkgInfo, err := s.ScanFiles(context.Background(), []string{filepath.Join(testDir, "main.go")}, testDir)
+	if err != nil {
+		t.Fatalf("ScanFiles failed: %v", err)
+	}
+
+	cases := []struct {
+		typeName string
+		want     []string // name=value
+	}{
+		{typeName: "Status", want: []string{`Active="active"`, `Blocked="blocked"`}},
+		{typeName: "Color", want: []string{"Red=1", "Green=2", "Blue=12", "Yellow=7"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.typeName, func(t *testing.T) {
+			typeInfo := pkgInfo.Lookup(tc.typeName)
+			if typeInfo == nil {
+				t.Fatalf("Type %q not found", tc.typeName)
+			}
+			var got []string
+			for _, member := range typeInfo.EnumMembers {
+				got = append(got, member.Name+"="+member.Value)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("EnumMembers = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if c := pkgInfo.Constants[2]; c.Name != "Other" || c.Type != nil {
+		t.Errorf("expected Other to be untyped, got %s with type %v", c.Name, c.Type)
+	}
+}
+
+func TestEnumScanning_Kind(t *testing.T) {
+	source := `package main
+
+type Status int
+
+const (
+	ToDo Status = iota
+	Done
+)
+
+type Priority string
+
+const (
+	Low  Priority = "low"
+	High Priority = "high"
+)
+
+type Perm uint8
+
+const (
+	Read Perm = 1 << iota
+	Write
+	Exec
+	NoPerm    Perm = 0
+	ReadWrite      = Read | Write
+)
+
+// Level counts from one, whose first values are powers of two but not flags.
+type Level int
+
+const (
+	_ Level = iota
+	Debug
+	Info
+)
+
+type Mode int
+
+const (
+	ModeA Mode = 1 << iota
+	ModeB
+	ModeC Mode = 5 // not a combination of the flags, 4 is not declared
+)
+`
+	testDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(testDir, "main.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	s := newTestScanner(t, "mymodule", testDir)
+	pkgInfo, err := s.ScanFiles(context.Background(), []string{filepath.Join(testDir, "main.go")}, testDir)
+	if err != nil {
+		t.Fatalf("ScanFiles failed: %v", err)
+	}
+
+	cases := []struct {
+		typeName string
+		want     EnumKind
+	}{
+		{typeName: "Status", want: PlainEnumKind},
+		{typeName: "Priority", want: StringEnumKind},
+		{typeName: "Perm", want: BitFlagsEnumKind},
+		{typeName: "Level", want: PlainEnumKind},
+		{typeName: "Mode", want: PlainEnumKind},
+	}
+	for _, tc := range cases {
+		t.Run(tc.typeName, func(t *testing.T) {
+			typeInfo := pkgInfo.Lookup(tc.typeName)
+			if typeInfo == nil {
+				t.Fatalf("Type %q not found", tc.typeName)
+			}
+			if typeInfo.EnumKind != tc.want {
+				t.Errorf("EnumKind = %s, want %s", typeInfo.EnumKind, tc.want)
+			}
+		})
+	}
+
+	t.Run("FlagMembers", func(t *testing.T) {
+		perm := pkgInfo.Lookup("Perm")
+		var names []string
+		for _, c := range perm.FlagMembers(0b101) {
+			names = append(names, c.Name)
+		}
+		if got, want := fmt.Sprint(names), "[Read Exec]"; got != want {
+			t.Errorf("FlagMembers(0b101) = %s, want %s", got, want)
+		}
+		if got := pkgInfo.Lookup("Status").FlagMembers(1); got != nil {
+			t.Errorf("expected no flag members for a plain enum, got %v", got)
+		}
+	})
+}
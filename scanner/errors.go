@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	goscanner "go/scanner"
+	"go/token"
+	"sort"
+)
+
+// FileError is an error of a file of a package, e.g. a syntax error.
+type FileError struct {
+	Pos token.Position // The position of the error; only the file name is set if it is unknown.
+	Msg string
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+// PackageError lists the errors of the files of a package which could not be parsed, sorted by
+// file and position. With partial results (see Scanner.PartialResults), the other files of the
+// package are scanned, and the errors are in PackageInfo.Errors instead.
+type PackageError struct {
+	Path   string // The directory of the package.
+	Errors []*FileError
+}
+
+func (e *PackageError) Error() string {
+	switch len(e.Errors) {
+	case 0:
+		return fmt.Sprintf("package %s: no errors", e.Path)
+	case 1:
+		return fmt.Sprintf("package %s: %s", e.Path, e.Errors[0])
+	}
+	return fmt.Sprintf("package %s: %s (and %d more errors)", e.Path, e.Errors[0], len(e.Errors)-1)
+}
+
+// Unwrap returns the errors of the files.
+func (e *PackageError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// newFileErrors returns the errors of a file from the error of its parsing, one for each
+// syntax error.
+func newFileErrors(filePath string, err error) []*FileError {
+	var list goscanner.ErrorList
+	if errors.As(err, &list) && len(list) > 0 {
+		fileErrs := make([]*FileError, len(list))
+		for i, e := range list {
+			fileErrs[i] = &FileError{Pos: e.Pos, Msg: e.Msg}
+		}
+		return fileErrs
+	}
+	return []*FileError{{Pos: token.Position{Filename: filePath}, Msg: err.Error()}}
+}
+
+// sortFileErrors sorts the errors by file and position.
+func sortFileErrors(errs []*FileError) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, b := errs[i].Pos, errs[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
@@ -0,0 +1,142 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanner_Fingerprints(t *testing.T) {
+	v1 := `package mymodule
+
+// Config is the configuration.
+type Config struct {
+	Name string
+}
+
+type Options struct {
+	Verbose bool
+}
+
+func Helper() int { return 1 }
+
+func Changed() int { return 1 }
+
+func Stub() int
+`
+	// v2 shifts every declaration, edits the doc of Config, adds a field to Options and
+	// changes the body of Changed.
+	v2 := `package mymodule
+
+import "strings"
+
+var _ = strings.ToUpper
+
+// Config is the configuration
+// of the module.
+type Config struct {
+	Name string
+}
+
+type Options struct {
+	Verbose bool
+	Debug   bool
+}
+
+func Helper() int { return 1 }
+
+func Changed() int { return 2 }
+
+func Stub() int
+`
+	scan := func(t *testing.T, source string, mode LoadMode) *PackageInfo {
+		t.Helper()
+		dir := t.TempDir()
+		path := filepath.Join(dir, "main.go")
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+		s := newTestScanner(t, "mymodule", dir)
+		s.DefaultLoadMode = mode
+		pkg, err := s.ScanFiles(context.Background(), []string{path}, dir)
+		if err != nil {
+			t.Fatalf("ScanFiles failed: %v", err)
+		}
+		return pkg
+	}
+	function := func(t *testing.T, pkg *PackageInfo, name string) *FunctionInfo {
+		t.Helper()
+		for _, f := range pkg.Functions {
+			if f.Name == name {
+				return f
+			}
+		}
+		t.Fatalf("function %s not found", name)
+		return nil
+	}
+
+	old, cur := scan(t, v1, LoadFull), scan(t, v2, LoadFull)
+
+	t.Run("files", func(t *testing.T) {
+		if len(old.FileInfos) != 1 || old.FileInfos[0].Path != old.Files[0] {
+			t.Fatalf("FileInfos does not match Files: %+v", old.FileInfos)
+		}
+		if got := old.FileInfos[0].Hash; got != contentHash([]byte(v1)) {
+			t.Errorf("file hash: got %s", got)
+		}
+		if old.FileInfos[0].Hash == cur.FileInfos[0].Hash {
+			t.Errorf("the file hash did not change")
+		}
+	})
+
+	t.Run("types", func(t *testing.T) {
+		for _, tt := range []struct {
+			name    string
+			changed bool
+		}{
+			{"Config", false}, // only the doc comment changed
+			{"Options", true},
+		} {
+			o, c := old.Lookup(tt.name), cur.Lookup(tt.name)
+			if o.DeclHash == "" {
+				t.Errorf("%s: DeclHash is empty", tt.name)
+			}
+			if changed := o.DeclHash != c.DeclHash; changed != tt.changed {
+				t.Errorf("%s: want changed=%v, got %v", tt.name, tt.changed, changed)
+			}
+		}
+	})
+
+	t.Run("functions", func(t *testing.T) {
+		for _, tt := range []struct {
+			name    string
+			changed bool
+		}{
+			{"Helper", false}, // moved only
+			{"Changed", true},
+		} {
+			o, c := function(t, old, tt.name), function(t, cur, tt.name)
+			if o.BodyHash == "" {
+				t.Errorf("%s: BodyHash is empty", tt.name)
+			}
+			if changed := o.BodyHash != c.BodyHash; changed != tt.changed {
+				t.Errorf("%s: want changed=%v, got %v", tt.name, tt.changed, changed)
+			}
+		}
+		if got := function(t, old, "Stub").BodyHash; got != "" {
+			t.Errorf("Stub: want no BodyHash, got %s", got)
+		}
+	})
+
+	t.Run("decls mode", func(t *testing.T) {
+		decls := scan(t, v1, LoadDecls)
+		f := function(t, decls, "Changed")
+		if f.AstDecl.Body != nil {
+			t.Errorf("the body is kept with LoadDecls")
+		}
+		if want := function(t, old, "Changed").BodyHash; f.BodyHash != want {
+			t.Errorf("BodyHash with LoadDecls: want %s, got %s", want, f.BodyHash)
+		}
+	})
+}
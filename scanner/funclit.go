@@ -0,0 +1,162 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+// FuncLitInfo holds information about a function literal (an anonymous function),
+// e.g. a handler closure or a goroutine body.
+type FuncLitInfo struct {
+	// Name is the name the Go toolchain gives to the literal, unique within the package:
+	// "Run.func1" for the first literal of Run, "Run.func1.1" for a literal nested in it,
+	// "(*T).Start.func1" for a literal of a method, "init.0.func1" for a literal of the first
+	// init function and "init.func1" for a literal of a package-level variable initializer.
+	Name     string `json:"name"`
+	PkgPath  string `json:"pkgPath"`
+	FilePath string `json:"filePath"`
+	// Enclosing is the function declaring the literal, or nil for a package-level variable initializer.
+	Enclosing *FunctionInfo `json:"-"`
+	// Parent is the literal declaring the literal, or nil if it is not nested in another literal.
+	Parent     *FuncLitInfo `json:"-"`
+	Parameters []*FieldInfo `json:"parameters,omitempty"`
+	Results    []*FieldInfo `json:"results,omitempty"`
+	IsVariadic bool         `json:"isVariadic,omitempty"`
+	// Captured holds the names of the variables of the enclosing functions that the literal
+	// refers to, in order of first use. Package-level variables are not included.
+	Captured []string     `json:"captured,omitempty"`
+	AstLit   *ast.FuncLit `json:"-"`
+	Pkg      *PackageInfo `json:"-"` // Back-reference to the containing package.
+}
+
+// Position returns the position of the literal in the source.
+func (l *FuncLitInfo) Position() token.Position {
+	if l.AstLit == nil || l.Pkg == nil || l.Pkg.Fset == nil {
+		return token.Position{Filename: l.FilePath}
+	}
+	return l.Pkg.Fset.Position(l.AstLit.Pos())
+}
+
+// funcLitCollector collects the function literals of a package, in source order.
+// filePath and importLookup are set for each file.
+type funcLitCollector struct {
+	s            *Scanner
+	info         *PackageInfo
+	filePath     string
+	importLookup map[string]string
+	initIndex    int // The number of init functions seen so far in the package.
+	globalIndex  int // The number of package-level literals seen so far in the package.
+}
+
+// collectFuncDecl collects the literals in the body of a function declaration.
+func (c *funcLitCollector) collectFuncDecl(ctx context.Context, fn *FunctionInfo) {
+	decl := fn.AstDecl
+	prefix := funcLitPrefix(fn)
+	if decl.Recv == nil && decl.Name.Name == "init" {
+		prefix = fmt.Sprintf("init.%d", c.initIndex)
+		c.initIndex++
+	}
+	if decl.Body == nil {
+		return
+	}
+	c.collect(ctx, decl.Body, prefix, fn, nil, fn.TypeParams, decl)
+}
+
+// collectValueSpec collects the literals in the initializer of a package-level variable.
+func (c *funcLitCollector) collectValueSpec(ctx context.Context, spec *ast.ValueSpec) {
+	for _, value := range spec.Values {
+		ast.Inspect(value, func(n ast.Node) bool {
+			lit, ok := n.(*ast.FuncLit)
+			if !ok {
+				return true
+			}
+			c.globalIndex++
+			l := c.newFuncLit(ctx, lit, fmt.Sprintf("init.func%d", c.globalIndex), nil, nil, nil, nil)
+			c.collect(ctx, lit.Body, l.Name, nil, l, nil, lit)
+			return false
+		})
+	}
+}
+
+// collect collects the literals directly nested in root (the literals nested in them are
+// collected recursively). The literals of a function are numbered from 1, and the literals
+// nested in a literal are numbered from 1 too, e.g. "Run.func1.1".
+func (c *funcLitCollector) collect(ctx context.Context, root ast.Node, prefix string, enclosing *FunctionInfo, parent *FuncLitInfo, typeParams []*TypeParamInfo, scope ast.Node) {
+	n := 0
+	ast.Inspect(root, func(node ast.Node) bool {
+		lit, ok := node.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		n++
+		name := fmt.Sprintf("%s.func%d", prefix, n)
+		if parent != nil {
+			name = fmt.Sprintf("%s.%d", prefix, n)
+		}
+		l := c.newFuncLit(ctx, lit, name, enclosing, parent, typeParams, scope)
+		c.collect(ctx, lit.Body, l.Name, enclosing, l, typeParams, scope)
+		return false
+	})
+}
+
+func (c *funcLitCollector) newFuncLit(ctx context.Context, lit *ast.FuncLit, name string, enclosing *FunctionInfo, parent *FuncLitInfo, typeParams []*TypeParamInfo, scope ast.Node) *FuncLitInfo {
+	sig := c.s.parseFuncType(ctx, lit.Type, typeParams, c.info, c.importLookup)
+	l := &FuncLitInfo{
+		Name:       name,
+		PkgPath:    c.info.ImportPath,
+		FilePath:   c.filePath,
+		Enclosing:  enclosing,
+		Parent:     parent,
+		Parameters: sig.Parameters,
+		Results:    sig.Results,
+		IsVariadic: sig.IsVariadic,
+		Captured:   capturedVars(lit, scope),
+		AstLit:     lit,
+		Pkg:        c.info,
+	}
+	c.info.FuncLits = append(c.info.FuncLits, l)
+	return l
+}
+
+// funcLitPrefix returns the name of the function as used in the names of its literals,
+// e.g. "Run" or "(*T).Start".
+func funcLitPrefix(fn *FunctionInfo) string {
+	name := fn.CanonicalName()
+	if !name.IsMethod() {
+		return name.Name
+	}
+	if name.IsPointer {
+		return fmt.Sprintf("(*%s).%s", name.TypeName, name.Name)
+	}
+	return fmt.Sprintf("%s.%s", name.TypeName, name.Name)
+}
+
+// capturedVars returns the names of the variables that the literal refers to and that are
+// declared outside of it, but inside scope (the enclosing function declaration).
+// It relies on the identifier resolution of go/parser, so it only sees the variables
+// declared in the same file, which is always the case for local variables.
+func capturedVars(lit *ast.FuncLit, scope ast.Node) []string {
+	if scope == nil {
+		return nil
+	}
+	var captured []string
+	seen := make(map[string]bool)
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || ident.Obj == nil || ident.Obj.Kind != ast.Var || ident.Name == "_" {
+			return true
+		}
+		pos := ident.Obj.Pos()
+		if !pos.IsValid() || (lit.Pos() <= pos && pos < lit.End()) || pos < scope.Pos() || scope.End() <= pos {
+			return true
+		}
+		if !seen[ident.Name] {
+			seen[ident.Name] = true
+			captured = append(captured, ident.Name)
+		}
+		return true
+	})
+	return captured
+}
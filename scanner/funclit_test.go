@@ -0,0 +1,101 @@
+package scanner_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestScanner_FuncLits(t *testing.T) {
+	source := `package app
+
+import "net/http"
+
+var handler = func(w http.ResponseWriter, r *http.Request) {}
+
+type Server struct{ name string }
+
+func (s *Server) Start(addr string) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = s.name + addr
+	}()
+	<-done
+}
+
+func Run[T any](items []T, f func(T) bool) (n int) {
+	count := func(item T) {
+		if f(item) {
+			n++
+		}
+	}
+	for _, item := range items {
+		count(item)
+	}
+	func(xs ...int) {
+		local := 0
+		_ = func() int { return local + len(items) }
+	}(1, 2)
+	return n
+}
+
+func init() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) { handler(w, r) })
+}
+`
+	workdir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod": "module example.com/app",
+		"app.go": source,
+	})
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithWorkDir(workdir), goscan.WithGoModuleResolver())
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	pkg, err := s.ScanPackageFromImportPath(context.Background(), "example.com/app")
+	if err != nil {
+		t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+	}
+
+	type funcLit struct {
+		Name      string
+		Enclosing string
+		Parent    string
+		Params    []string
+		Variadic  bool
+		Captured  []string
+		Line      int
+	}
+	var got []funcLit
+	for _, l := range pkg.FuncLits {
+		lit := funcLit{Name: l.Name, Variadic: l.IsVariadic, Captured: l.Captured, Line: l.Position().Line}
+		if l.Enclosing != nil {
+			lit.Enclosing = l.Enclosing.Name
+		}
+		if l.Parent != nil {
+			lit.Parent = l.Parent.Name
+		}
+		for _, p := range l.Parameters {
+			lit.Params = append(lit.Params, fmt.Sprintf("%s %s", p.Name, p.Type.String()))
+		}
+		got = append(got, lit)
+	}
+
+	want := []funcLit{
+		{Name: "init.func1", Params: []string{"w http.ResponseWriter", "r *http.Request"}, Line: 5},
+		{Name: "(*Server).Start.func1", Enclosing: "Start", Captured: []string{"done", "s", "addr"}, Line: 11},
+		{Name: "Run.func1", Enclosing: "Run", Params: []string{"item T"}, Captured: []string{"f", "n"}, Line: 19},
+		{Name: "Run.func2", Enclosing: "Run", Params: []string{"xs []int"}, Variadic: true, Captured: []string{"items"}, Line: 27},
+		{Name: "Run.func2.1", Enclosing: "Run", Parent: "Run.func2", Captured: []string{"local", "items"}, Line: 29},
+		{Name: "init.0.func1", Enclosing: "init", Params: []string{"w http.ResponseWriter", "r *http.Request"}, Line: 35},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FuncLits mismatch (-want +got):\n%s", diff)
+	}
+}
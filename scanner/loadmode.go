@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LoadMode controls how much of a package's source is loaded when it is scanned.
+type LoadMode int
+
+const (
+	// LoadFull loads all declarations, including function bodies. This is the default.
+	LoadFull LoadMode = iota
+	// LoadDecls loads all declarations, but drops function bodies after parsing.
+	LoadDecls
+	// LoadImports loads only the package clause and the import declarations of each file.
+	// No types, functions, constants, or variables are collected.
+	LoadImports
+)
+
+// String returns the name of the mode, as accepted by ParseLoadMode.
+func (m LoadMode) String() string {
+	switch m {
+	case LoadFull:
+		return "full"
+	case LoadDecls:
+		return "decls"
+	case LoadImports:
+		return "imports"
+	default:
+		return fmt.Sprintf("LoadMode(%d)", int(m))
+	}
+}
+
+// ParseLoadMode parses a mode name ("full", "decls" or "imports").
+func ParseLoadMode(s string) (LoadMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "full":
+		return LoadFull, nil
+	case "decls":
+		return LoadDecls, nil
+	case "imports":
+		return LoadImports, nil
+	default:
+		return LoadFull, fmt.Errorf("unknown load mode %q, expected one of full, decls, imports", s)
+	}
+}
+
+// LoadModeRule applies a LoadMode to the packages whose import path matches Pattern.
+// A pattern ending with "/..." matches the package and all of its sub-packages (see CompilePattern).
+type LoadModeRule struct {
+	Pattern string
+	Mode    LoadMode
+}
+
+// LoadModeFor returns the load mode for the package with the given import path.
+// The last matching rule in LoadModeRules wins. Packages listed in DeclarationsOnlyPackages
+// use LoadDecls, and all other packages use DefaultLoadMode.
+func (s *Scanner) LoadModeFor(importPath string) LoadMode {
+	for i := len(s.LoadModeRules) - 1; i >= 0; i-- {
+		if MatchPattern(s.LoadModeRules[i].Pattern, importPath) {
+			return s.LoadModeRules[i].Mode
+		}
+	}
+	for _, pattern := range s.DeclarationsOnlyPackages {
+		if MatchPattern(pattern, importPath) {
+			return LoadDecls
+		}
+	}
+	return s.DefaultLoadMode
+}
+
+// MatchPattern reports whether the import path matches the pattern (see CompilePattern).
+func MatchPattern(pattern, importPath string) bool {
+	return CompilePattern(pattern)(importPath)
+}
+
+// CompilePattern returns the matcher of an import path pattern, where "..." matches any string,
+// e.g. "example.com/app/..." or "example.com/.../internal". As with the go command, a pattern
+// ending with "/..." also matches the path before it, e.g. "example.com/app", and "..." alone
+// matches every path.
+func CompilePattern(pattern string) func(importPath string) bool {
+	if !strings.Contains(pattern, "...") {
+		return func(path string) bool { return path == pattern }
+	}
+	if base, ok := strings.CutSuffix(pattern, "/..."); ok && !strings.Contains(base, "...") {
+		return func(path string) bool { return path == base || strings.HasPrefix(path, base+"/") }
+	}
+	expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\.\.\.`, `.*`)
+	if trimmed, ok := strings.CutSuffix(expr, `/.*`); ok {
+		expr = trimmed + `(/.*)?`
+	}
+	return regexp.MustCompile("^" + expr + "$").MatchString
+}
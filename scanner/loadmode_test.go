@@ -0,0 +1,68 @@
+package scanner
+
+import "testing"
+
+func TestLoadModeFor(t *testing.T) {
+	s := &Scanner{
+		DefaultLoadMode: LoadImports,
+		LoadModeRules: []LoadModeRule{
+			{Pattern: "example.com/me/...", Mode: LoadFull},
+			{Pattern: "example.com/me/third_party/...", Mode: LoadDecls},
+		},
+		DeclarationsOnlyPackages: []string{"net/http"},
+	}
+	cases := []struct {
+		importPath string
+		want       LoadMode
+	}{
+		{importPath: "example.com/me", want: LoadFull},
+		{importPath: "example.com/me/api", want: LoadFull},
+		{importPath: "example.com/me/third_party/lib", want: LoadDecls},
+		{importPath: "example.com/meow", want: LoadImports},
+		{importPath: "net/http", want: LoadDecls},
+		{importPath: "strings", want: LoadImports},
+	}
+	for _, tc := range cases {
+		if got := s.LoadModeFor(tc.importPath); got != tc.want {
+			t.Errorf("LoadModeFor(%q) = %s, want %s", tc.importPath, got, tc.want)
+		}
+	}
+}
+
+func TestParseLoadMode(t *testing.T) {
+	for _, mode := range []LoadMode{LoadFull, LoadDecls, LoadImports} {
+		got, err := ParseLoadMode(mode.String())
+		if err != nil {
+			t.Fatalf("ParseLoadMode(%q) failed: %v", mode, err)
+		}
+		if got != mode {
+			t.Errorf("ParseLoadMode(%q) = %s, want %s", mode, got, mode)
+		}
+	}
+	if _, err := ParseLoadMode("bodies"); err == nil {
+		t.Error("ParseLoadMode(\"bodies\") should fail")
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	cases := []struct {
+		pattern, importPath string
+		want                bool
+	}{
+		{pattern: "example.com/me", importPath: "example.com/me", want: true},
+		{pattern: "example.com/me", importPath: "example.com/me/api", want: false},
+		{pattern: "example.com/me/...", importPath: "example.com/me", want: true},
+		{pattern: "example.com/me/...", importPath: "example.com/me/api/v1", want: true},
+		{pattern: "example.com/me/...", importPath: "example.com/meow", want: false},
+		{pattern: "example.com/.../internal", importPath: "example.com/me/internal", want: true},
+		{pattern: "example.com/.../internal", importPath: "example.com/me/internal/x", want: false},
+		{pattern: "example.com/.../internal/...", importPath: "example.com/me/internal", want: true},
+		{pattern: "k8s.io/client-go...", importPath: "k8s.io/client-go/kubernetes", want: true},
+		{pattern: "...", importPath: "strings", want: true},
+	}
+	for _, tc := range cases {
+		if got := MatchPattern(tc.pattern, tc.importPath); got != tc.want {
+			t.Errorf("MatchPattern(%q, %q) = %v, want %v", tc.pattern, tc.importPath, got, tc.want)
+		}
+	}
+}
@@ -43,6 +43,42 @@ const (
 	UnknownKind
 )
 
+// EnumKind classifies the constants of an enum type, so that the generators can choose
+// how to (un)marshal the values.
+type EnumKind int
+
+const (
+	// UnknownEnumKind is the kind of the types which are not enums, or whose members
+	// are not all integers or all strings.
+	UnknownEnumKind EnumKind = iota
+	// PlainEnumKind is the kind of the enums of integer constants, e.g. `A Status = iota`.
+	PlainEnumKind
+	// BitFlagsEnumKind is the kind of the enums of single-bit integer constants declared with
+	// shifts, e.g. `Read Perm = 1 << iota`. A value is a combination of the flags, and the members
+	// may include the combinations (`ReadWrite = Read | Write`) and the zero value.
+	BitFlagsEnumKind
+	// StringEnumKind is the kind of the enums of string constants, e.g. `Low Priority = "low"`.
+	StringEnumKind
+)
+
+func (k EnumKind) String() string {
+	switch k {
+	case PlainEnumKind:
+		return "plain"
+	case BitFlagsEnumKind:
+		return "bitflags"
+	case StringEnumKind:
+		return "string"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the kind by its name, e.g. "bitflags".
+func (k EnumKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
 // PackageResolver is an interface that can resolve an import path to a package definition.
 // It is implemented by the top-level typescanner.Scanner to enable lazy, cached lookups.
 type PackageResolver interface {
@@ -62,17 +98,45 @@ type PackageInfo struct {
 	ModulePath string // The go module path this package belongs to.
 	ModuleDir  string // The absolute path to the module's root directory
 	Files      []string
+	FileInfos  []*FileInfo // The fingerprints of the files, in the same order as Files.
 	Types      []*TypeInfo
 	Constants  []*ConstantInfo
 	Variables  []*VariableInfo
 	Functions  []*FunctionInfo
+	FuncLits   []*FuncLitInfo       // Function literals, in source order.
+	Directives []*Directive         // Comment directives (e.g. `//go:generate ...`), in source order.
+	Embeds     []*EmbedInfo         // The variables initialized by `//go:embed` directives, in source order.
 	Fset       *token.FileSet       // Added: Fileset for position information
 	AstFiles   map[string]*ast.File // Added: Parsed AST for each file
+	// LoadMode is the mode the package was loaded with. With LoadImports, AstFiles
+	// hold only the package clause and imports, and no declarations are collected.
+	LoadMode LoadMode
+	// UsesCgo is true if some files of the package import "C". Their cgo preamble
+	// and C.xxx references are ignored; see CgoImportPath.
+	UsesCgo  bool
+	CgoFiles []string // The files that import "C".
+	// XTest is the external test package (`package foo_test`) declared by the _test.go files
+	// in the package directory, scanned as a separate package when tests are included.
+	// Its ImportPath is the ImportPath of the package with a "_test" suffix.
+	XTest *PackageInfo
+	// TestBase is the package tested by an external test package, and nil for other packages.
+	TestBase *PackageInfo
+	// Errors are the errors of the files which could not be parsed, left out of the package,
+	// when the package is scanned with partial results (see Scanner.PartialResults).
+	Errors []*FileError
 
 	lookupOnce sync.Once
 	lookup     map[string]*TypeInfo
 }
 
+// FileInfo is the fingerprint of a file of a package.
+type FileInfo struct {
+	Path string `json:"path"`
+	// Hash is the hex-encoded SHA-256 hash of the content of the file, as scanned (the overlay
+	// content for an overlaid file).
+	Hash string `json:"hash"`
+}
+
 // Lookup finds a type by name in the package.
 func (p *PackageInfo) Lookup(name string) *TypeInfo {
 	p.lookupOnce.Do(func() {
@@ -115,9 +179,24 @@ type TypeInfo struct {
 	Interface  *InterfaceInfo   `json:"interface,omitempty"`
 	Underlying *FieldType       `json:"underlying,omitempty"` // For alias types
 
+	// DeclHash is the hash of the source text of the type spec (e.g. `Name[T any] struct{...}`),
+	// without the doc comment and the methods. It changes only when the declaration changes.
+	DeclHash string `json:"declHash,omitempty"`
+
+	// EnclosingFunc is the function whose body declares the type, for a local type (see
+	// FunctionInfo.LocalTypes), and nil for a package-level type.
+	EnclosingFunc *FunctionInfo `json:"-"`
+
+	// --- Fields for alias declarations (`type A = B`) ---
+	IsAlias     bool       `json:"isAlias,omitempty"`     // True if declared with `=`, as opposed to a defined type (`type A B`)
+	AliasTarget *FieldType `json:"aliasTarget,omitempty"` // The aliased type (B), set for every alias regardless of its kind
+
 	// --- Fields for Enum-like patterns ---
 	IsEnum      bool            `json:"isEnum,omitempty"`      // True if this type is identified as an enum
 	EnumMembers []*ConstantInfo `json:"enumMembers,omitempty"` // List of constants belonging to this enum type
+	EnumKind    EnumKind        `json:"enumKind,omitempty"`    // The kind of the enum, set with IsEnum
+
+	IsTest bool `json:"isTest,omitempty"` // True if declared in a _test.go file
 
 	// --- Fields for inspect mode ---
 	Inspect           bool            `json:"-"`                    // Flag to enable inspection logging
@@ -139,12 +218,57 @@ func NewUnresolvedTypeInfo(pkgPath, name string) *TypeInfo {
 	}
 }
 
+// ResolveAlias follows alias declarations (`type A = B`) and returns the type they finally denote.
+// A type that is not an alias is returned as is. If the chain ends in a type without a declaration
+// of its own (a built-in, a pointer, a type literal, ...), the last alias in the chain is returned;
+// its Kind and Underlying describe the aliased type.
+func (ti *TypeInfo) ResolveAlias(ctx context.Context) (*TypeInfo, error) {
+	resolved, _, err := ti.ResolveAliasFunc(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// ResolveAliasFunc is ResolveAlias, also stopping at an alias whose target is rejected by follow,
+// if not nil, e.g. a type of a package outside of a scan policy. Along with the type reached, it
+// returns the alias target naming it (nil if no alias was followed). On an error, the last type
+// reached is returned with it.
+func (ti *TypeInfo) ResolveAliasFunc(ctx context.Context, follow func(target *FieldType) bool) (*TypeInfo, *FieldType, error) {
+	seen := make(map[*TypeInfo]bool)
+	cur := ti
+	var via *FieldType
+	for cur != nil && cur.IsAlias && cur.AliasTarget != nil {
+		if seen[cur] {
+			return cur, via, fmt.Errorf("alias cycle detected at %s.%s", cur.PkgPath, cur.Name)
+		}
+		seen[cur] = true
+
+		target := cur.AliasTarget
+		if target.IsPointer || target.IsSlice || target.IsMap || target.IsChan || target.IsBuiltin || target.IsTypeParam || target.TypeName == "" {
+			return cur, via, nil
+		}
+		if follow != nil && !follow(target) {
+			return cur, via, nil
+		}
+		def, err := target.Resolve(ctx)
+		if err != nil {
+			return cur, via, fmt.Errorf("resolving alias target of %s.%s: %w", cur.PkgPath, cur.Name, err)
+		}
+		if def == nil {
+			return cur, via, nil
+		}
+		cur, via = def, target
+	}
+	return cur, via, nil
+}
+
 // Annotation extracts the value of a specific annotation from the TypeInfo's Doc string.
 // Annotations are expected to be in the format "@<name>[:<value>]" or "@<name> <value>".
 // If inspect mode is enabled, it logs the checking process.
 func (ti *TypeInfo) Annotation(ctx context.Context, name string) (value string, ok bool) {
 	// The core annotation searching logic.
-	searchValue, found := ti.searchAnnotation(name)
+	searchValue, found := searchAnnotation(ti.Doc, name)
 
 	// If inspect mode is off, just return the result.
 	if !ti.Inspect || ti.Logger == nil {
@@ -175,12 +299,12 @@ func (ti *TypeInfo) Annotation(ctx context.Context, name string) (value string,
 	return searchValue, found
 }
 
-// searchAnnotation is the core logic for finding an annotation, separated to keep the main Annotation method clean.
-func (ti *TypeInfo) searchAnnotation(name string) (value string, ok bool) {
-	if ti.Doc == "" {
+// searchAnnotation is the core logic for finding an annotation in a doc comment, separated to keep the main Annotation methods clean.
+func searchAnnotation(doc string, name string) (value string, ok bool) {
+	if doc == "" {
 		return "", false
 	}
-	lines := strings.Split(ti.Doc, "\n")
+	lines := strings.Split(doc, "\n")
 	prefix := "@" + name
 	for _, line := range lines {
 		trimmedLine := strings.TrimSpace(line)
@@ -221,6 +345,41 @@ type InterfaceInfo struct {
 	// within the same package.
 	Embedded []*FieldType `json:"embedded,omitempty"`
 	Union    []*FieldType `json:"union,omitempty"` // For union-type interfaces
+	// TypeTerms is the type list of a constraint interface, e.g. `~int` and `~string` for
+	// `interface{ ~int | ~string }`, in source order. The terms of several lines, which
+	// denote the intersection of their type sets, are all listed.
+	// A named type alone on a line (e.g. `interface{ MyInt }`) cannot be told from an
+	// embedded interface without resolving it, so it is listed in Embedded, unless the
+	// interface has a union.
+	TypeTerms []*TypeTerm `json:"typeTerms,omitempty"`
+}
+
+// TypeTerm is a term of the type list of a constraint interface.
+type TypeTerm struct {
+	Tilde bool       `json:"tilde,omitempty"` // Whether the term is `~T`, i.e. all the types whose underlying type is T.
+	Type  *FieldType `json:"type"`
+}
+
+// String returns the term as written, e.g. "~int".
+func (t *TypeTerm) String() string {
+	if t.Tilde {
+		return "~" + t.Type.String()
+	}
+	return t.Type.String()
+}
+
+// IsConstraint reports whether the interface can only be used as a type constraint, as it has
+// a type list or embeds `comparable`. Such an interface is not a method set to implement.
+func (i *InterfaceInfo) IsConstraint() bool {
+	if len(i.TypeTerms) > 0 {
+		return true
+	}
+	for _, e := range i.Embedded {
+		if e.IsBuiltin && e.Name == "comparable" {
+			return true
+		}
+	}
+	return false
 }
 
 // MethodInfo represents a single method in an interface.
@@ -454,6 +613,11 @@ func (ft *FieldType) Resolve(ctx context.Context) (*TypeInfo, error) {
 		)
 	}
 
+	// C.xxx types of cgo have no Go declaration to scan.
+	if ft.FullImportPath == CgoImportPath {
+		return NewUnresolvedTypeInfo(CgoImportPath, ft.TypeName), nil
+	}
+
 	// --- Resolve the package ---
 	pkgInfo, err := ft.Resolver.ScanPackageFromImportPath(ctx, ft.FullImportPath)
 	if err != nil {
@@ -503,17 +667,29 @@ type ConstantInfo struct {
 	ConstVal   constant.Value
 	IotaValue  int // The value of iota for the spec this constant was in.
 	ValExpr    ast.Expr
+	IsTest     bool // True if declared in a _test.go file
 }
 
 // VariableInfo represents a single top-level variable declaration.
 type VariableInfo struct {
-	Name       string
-	FilePath   string
-	Doc        string
-	Type       *FieldType
-	IsExported bool
-	Node       ast.Node
-	GenDecl    *ast.GenDecl // The *ast.GenDecl node for the var declaration
+	Name     string
+	FilePath string
+	Doc      string
+	// Type is the declared type, or the type inferred from the initializer if IsTypeInferred.
+	// It is nil if the type is not declared and cannot be inferred from the package alone.
+	Type           *FieldType
+	IsTypeInferred bool
+	IsExported     bool
+	Node           ast.Node
+	GenDecl        *ast.GenDecl // The *ast.GenDecl node for the var declaration
+	// ValExpr is the initializer expression, or nil. For `var a, b = f()`, the names share
+	// the call, and ValIndex is the index of the result assigned to the variable.
+	ValExpr  ast.Expr
+	ValIndex int
+	IsTest   bool // True if declared in a _test.go file
+	// EmbedPatterns are the patterns of the `//go:embed` directives of the variable, or nil.
+	// See PackageInfo.Embeds.
+	EmbedPatterns []string
 }
 
 // FunctionInfo represents a single top-level function or method declaration.
@@ -524,11 +700,33 @@ type FunctionInfo struct {
 	Doc        string           `json:"doc,omitempty"`
 	Receiver   *FieldInfo       `json:"receiver,omitempty"`
 	TypeParams []*TypeParamInfo `json:"typeParams,omitempty"` // For generic functions
-	Parameters []*FieldInfo     `json:"parameters,omitempty"`
-	Results    []*FieldInfo     `json:"results,omitempty"`
-	IsVariadic bool             `json:"isVariadic,omitempty"`
-	AstDecl    *ast.FuncDecl    `json:"-"` // Avoid cyclic JSON.
-	Pkg        *PackageInfo     `json:"-"` // Back-reference to the containing package.
+	// ReceiverTypeParams holds the names of the receiver's type parameters as written in the
+	// method declaration, e.g. ["U"] for `func (b *Box[U]) Get() U`.
+	ReceiverTypeParams []string      `json:"receiverTypeParams,omitempty"`
+	Parameters         []*FieldInfo  `json:"parameters,omitempty"`
+	Results            []*FieldInfo  `json:"results,omitempty"`
+	IsVariadic         bool          `json:"isVariadic,omitempty"`
+	IsTest             bool          `json:"isTest,omitempty"` // True if declared in a _test.go file
+	AstDecl            *ast.FuncDecl `json:"-"`                // Avoid cyclic JSON.
+	Pkg                *PackageInfo  `json:"-"`                // Back-reference to the containing package.
+
+	// LocalTypes are the types declared in the body, including in the function literals. They
+	// are not listed in PackageInfo.Types, as their names are scoped to the function.
+	LocalTypes []*TypeInfo `json:"localTypes,omitempty"`
+
+	// BodyHash is the hash of the source text of the body, and empty for a function without a
+	// body. It is set with LoadDecls too, although the body is not kept.
+	BodyHash string `json:"bodyHash,omitempty"`
+
+	// Calls are the calls in the body, found from the syntax only. They are collected when
+	// Scanner.CollectCalls is set (see goscan.WithStaticCalls), and nil otherwise.
+	Calls []CallInfo `json:"calls,omitempty"`
+}
+
+// Annotation extracts the value of a specific annotation from the function's Doc string,
+// in the same format as TypeInfo.Annotation, e.g. "@entrypoint" or "@route: GET /users".
+func (f *FunctionInfo) Annotation(name string) (value string, ok bool) {
+	return searchAnnotation(f.Doc, name)
 }
 
 // SetResolver is a test helper to overwrite the internal resolver.
@@ -543,7 +741,8 @@ type PackageImports struct {
 	Name        string
 	ImportPath  string
 	Imports     []string
-	FileImports map[string][]string // file path -> import paths
+	FileImports map[string][]string // file path -> import paths, without "C"
+	UsesCgo     bool                // True if some files import "C".
 }
 
 // Visitor defines the interface for operations to be performed at each node
@@ -561,3 +760,31 @@ type ModuleInfo struct {
 	Path string // The module path (e.g., "github.com/podhmo/go-scan").
 	Dir  string // The absolute path to the module's root directory.
 }
+
+// FlagMembers returns the single-bit members of a bitflags enum whose bits are set in v, in the
+// order of declaration, e.g. to build the comma-joined representation of a value. The bits not
+// covered by the members are ignored. It returns nil for the enums of the other kinds.
+func (ti *TypeInfo) FlagMembers(v uint64) []*ConstantInfo {
+	if ti.EnumKind != BitFlagsEnumKind {
+		return nil
+	}
+	var members []*ConstantInfo
+	for _, c := range ti.EnumMembers {
+		if bit, ok := singleBit(c.ConstVal); ok && v&bit != 0 {
+			members = append(members, c)
+		}
+	}
+	return members
+}
+
+// singleBit returns the value if it is a power of two.
+func singleBit(val constant.Value) (uint64, bool) {
+	if val == nil || val.Kind() != constant.Int {
+		return 0, false
+	}
+	v, exact := constant.Uint64Val(val)
+	if !exact || v == 0 || v&(v-1) != 0 {
+		return 0, false
+	}
+	return v, true
+}
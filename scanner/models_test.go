@@ -157,6 +157,11 @@ func TestTypeInfo_Annotation(t *testing.T) {
 			if gotValue != tt.wantValue {
 				t.Errorf("TypeInfo.Annotation() gotValue = %q, want %q", gotValue, tt.wantValue)
 			}
+
+			fi := &FunctionInfo{Doc: tt.doc}
+			if gotValue, gotOk := fi.Annotation(tt.annoName); gotValue != tt.wantValue || gotOk != tt.wantOk {
+				t.Errorf("FunctionInfo.Annotation() = %q, %v, want %q, %v", gotValue, gotOk, tt.wantValue, tt.wantOk)
+			}
 		})
 	}
 }
@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SortOrder is the order of the declarations returned by the Sorted accessors of PackageInfo,
+// e.g. SortedTypes. Unlike the iteration over the maps of a package, e.g. AstFiles, these
+// accessors return the same order on every run, for a stable output.
+type SortOrder int
+
+const (
+	// ByPosition sorts the declarations by file path, then by position in the file.
+	ByPosition SortOrder = iota
+	// ByName sorts the declarations by name, the methods by receiver type, then by name.
+	// The declarations with the same name keep their order by position.
+	ByName
+)
+
+// declKey is the sort key of a declaration.
+type declKey struct {
+	recv string // the receiver type of a method, without the pointer
+	name string
+	file string
+	pos  token.Pos
+}
+
+// sortDecls returns a sorted copy of the declarations of a package.
+func sortDecls[T any](decls []T, order SortOrder, key func(T) declKey) []T {
+	type keyed struct {
+		decl T
+		key  declKey
+	}
+	items := make([]keyed, len(decls))
+	for i, d := range decls {
+		items[i] = keyed{decl: d, key: key(d)}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].key, items[j].key
+		if order == ByName {
+			if a.recv != b.recv {
+				return a.recv < b.recv
+			}
+			if a.name != b.name {
+				return a.name < b.name
+			}
+		}
+		if a.file != b.file {
+			return a.file < b.file
+		}
+		return a.pos < b.pos
+	})
+	sorted := make([]T, len(items))
+	for i, item := range items {
+		sorted[i] = item.decl
+	}
+	return sorted
+}
+
+func nodePos(node ast.Node) token.Pos {
+	if node == nil {
+		return token.NoPos
+	}
+	return node.Pos()
+}
+
+// SortedTypes returns the types of the package in the given order.
+func (p *PackageInfo) SortedTypes(order SortOrder) []*TypeInfo {
+	return sortDecls(p.Types, order, func(t *TypeInfo) declKey {
+		return declKey{name: t.Name, file: t.FilePath, pos: nodePos(t.Node)}
+	})
+}
+
+// SortedFunctions returns the functions and methods of the package in the given order. By name,
+// the functions come first, then the methods grouped by receiver type.
+func (p *PackageInfo) SortedFunctions(order SortOrder) []*FunctionInfo {
+	return sortDecls(p.Functions, order, func(f *FunctionInfo) declKey {
+		var recv string
+		if f.Receiver != nil && f.Receiver.Type != nil {
+			recv = strings.TrimPrefix(f.Receiver.Type.String(), "*")
+		}
+		var pos token.Pos
+		if f.AstDecl != nil {
+			pos = f.AstDecl.Pos()
+		}
+		return declKey{recv: recv, name: f.Name, file: f.FilePath, pos: pos}
+	})
+}
+
+// SortedConstants returns the constants of the package in the given order.
+func (p *PackageInfo) SortedConstants(order SortOrder) []*ConstantInfo {
+	return sortDecls(p.Constants, order, func(c *ConstantInfo) declKey {
+		return declKey{name: c.Name, file: c.FilePath, pos: nodePos(c.Node)}
+	})
+}
+
+// SortedVariables returns the package-level variables of the package in the given order.
+func (p *PackageInfo) SortedVariables(order SortOrder) []*VariableInfo {
+	return sortDecls(p.Variables, order, func(v *VariableInfo) declKey {
+		return declKey{name: v.Name, file: v.FilePath, pos: nodePos(v.Node)}
+	})
+}
+
+// SortedAstFiles returns the parsed files of the package, sorted by file path.
+func (p *PackageInfo) SortedAstFiles() []*ast.File {
+	paths := make([]string, 0, len(p.AstFiles))
+	for path := range p.AstFiles {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	files := make([]*ast.File, len(paths))
+	for i, path := range paths {
+		files[i] = p.AstFiles[path]
+	}
+	return files
+}
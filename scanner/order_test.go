@@ -0,0 +1,136 @@
+package scanner_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestPackageInfo_Sorted(t *testing.T) {
+	workdir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod": "module example.com/app",
+		"b.go": `package app
+
+type Zeta struct{}
+
+func (z *Zeta) Run() {}
+
+func Open() {}
+
+const Max = 10
+
+var current string
+`,
+		"a.go": `package app
+
+type Alpha struct{}
+
+func (a Alpha) Close() {}
+
+func (z Zeta) Close() {}
+
+func Close() {}
+
+const Min = 1
+
+var Default string
+`,
+	})
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithWorkDir(workdir), goscan.WithGoModuleResolver())
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	pkg, err := s.ScanPackageFromImportPath(context.Background(), "example.com/app")
+	if err != nil {
+		t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+	}
+
+	names := func(n int, name func(int) string) []string {
+		var got []string
+		for i := 0; i < n; i++ {
+			got = append(got, name(i))
+		}
+		return got
+	}
+	funcName := func(fns []*scanner.FunctionInfo) func(int) string {
+		return func(i int) string {
+			if fns[i].Receiver != nil {
+				return fns[i].Receiver.Type.String() + "." + fns[i].Name
+			}
+			return fns[i].Name
+		}
+	}
+
+	tests := []struct {
+		name string
+		got  func(scanner.SortOrder) []string
+		want map[scanner.SortOrder][]string
+	}{
+		{
+			name: "types",
+			got: func(order scanner.SortOrder) []string {
+				types := pkg.SortedTypes(order)
+				return names(len(types), func(i int) string { return types[i].Name })
+			},
+			want: map[scanner.SortOrder][]string{
+				scanner.ByPosition: {"Alpha", "Zeta"},
+				scanner.ByName:     {"Alpha", "Zeta"},
+			},
+		},
+		{
+			name: "functions",
+			got: func(order scanner.SortOrder) []string {
+				fns := pkg.SortedFunctions(order)
+				return names(len(fns), funcName(fns))
+			},
+			want: map[scanner.SortOrder][]string{
+				scanner.ByPosition: {"Alpha.Close", "Zeta.Close", "Close", "*Zeta.Run", "Open"},
+				scanner.ByName:     {"Close", "Open", "Alpha.Close", "Zeta.Close", "*Zeta.Run"},
+			},
+		},
+		{
+			name: "constants",
+			got: func(order scanner.SortOrder) []string {
+				consts := pkg.SortedConstants(order)
+				return names(len(consts), func(i int) string { return consts[i].Name })
+			},
+			want: map[scanner.SortOrder][]string{
+				scanner.ByPosition: {"Min", "Max"},
+				scanner.ByName:     {"Max", "Min"},
+			},
+		},
+		{
+			name: "variables",
+			got: func(order scanner.SortOrder) []string {
+				vars := pkg.SortedVariables(order)
+				return names(len(vars), func(i int) string { return vars[i].Name })
+			},
+			want: map[scanner.SortOrder][]string{
+				scanner.ByPosition: {"Default", "current"},
+				scanner.ByName:     {"Default", "current"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		for order, want := range tt.want {
+			if diff := cmp.Diff(want, tt.got(order)); diff != "" {
+				t.Errorf("%s (order %d) mismatch (-want +got):\n%s", tt.name, order, diff)
+			}
+		}
+	}
+
+	var files []string
+	for _, f := range pkg.SortedAstFiles() {
+		files = append(files, filepath.Base(s.Fset().File(f.Pos()).Name()))
+	}
+	if diff := cmp.Diff([]string{"a.go", "b.go"}, files); diff != "" {
+		t.Errorf("SortedAstFiles() mismatch (-want +got):\n%s", diff)
+	}
+}
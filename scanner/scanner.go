@@ -2,6 +2,8 @@ package scanner
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"go/ast"
 	"go/constant"
@@ -10,6 +12,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -41,6 +44,7 @@ func getParallelismLimit(ctx context.Context) int {
 type fileParseResult struct {
 	filePath string
 	fileAst  *ast.File
+	content  []byte
 	err      error
 }
 
@@ -51,6 +55,10 @@ type Scanner struct {
 	ExternalTypeOverrides    ExternalTypeOverride
 	Overlay                  Overlay
 	DeclarationsOnlyPackages []string // Changed from map[string]bool
+	DefaultLoadMode          LoadMode
+	LoadModeRules            []LoadModeRule
+	CollectCalls             bool // Whether the calls of the functions are collected in FunctionInfo.Calls.
+	PartialResults           bool // Whether the files which cannot be parsed are skipped, and listed in PackageInfo.Errors, instead of failing with a *PackageError.
 	modulePath               string
 	moduleRootDir            string
 	inspect                  bool
@@ -137,6 +145,7 @@ func (s *Scanner) ScanFilesWithKnownImportPath(ctx context.Context, filePaths []
 }
 
 // ScanPackageFromFilePathImports parses only the import declarations from a set of Go files.
+// The imports of the package are sorted.
 func (s *Scanner) ScanPackageFromFilePathImports(ctx context.Context, filePaths []string, pkgDirPath string, canonicalImportPath string) (*PackageImports, error) {
 	info := &PackageImports{
 		ImportPath:  canonicalImportPath,
@@ -169,11 +178,17 @@ func (s *Scanner) ScanPackageFromFilePathImports(ctx context.Context, filePaths
 		}
 	}
 
-	// Determine the dominant package name, ignoring 'main' if another name exists
+	// Determine the dominant package name, ignoring 'main' if another name exists.
+	// The names are sorted, so that the same name is chosen on every run.
+	sortedNames := make([]string, 0, len(packageNames))
+	for name := range packageNames {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
 	var dominantPackageName string
 	if len(packageNames) > 1 {
 		if _, hasMain := packageNames["main"]; hasMain {
-			for name := range packageNames {
+			for _, name := range sortedNames {
 				if name != "main" {
 					dominantPackageName = name
 					break // Pick the first non-main package
@@ -185,7 +200,7 @@ func (s *Scanner) ScanPackageFromFilePathImports(ctx context.Context, filePaths
 			// Let's try to find a single non-test package name.
 			var basePackageNames []string
 			packageSet := make(map[string]bool)
-			for name := range packageNames {
+			for _, name := range sortedNames {
 				baseName := strings.TrimSuffix(name, "_test")
 				if !packageSet[baseName] {
 					packageSet[baseName] = true
@@ -197,11 +212,7 @@ func (s *Scanner) ScanPackageFromFilePathImports(ctx context.Context, filePaths
 			if len(basePackageNames) == 1 {
 				dominantPackageName = basePackageNames[0]
 			} else {
-				var names []string
-				for name := range packageNames {
-					names = append(names, name)
-				}
-				return nil, fmt.Errorf("mismatched package names: %v in directory %s", names, pkgDirPath)
+				return nil, fmt.Errorf("mismatched package names: %v in directory %s", sortedNames, pkgDirPath)
 			}
 		}
 	} else if len(packageNames) == 1 {
@@ -225,6 +236,10 @@ func (s *Scanner) ScanPackageFromFilePathImports(ctx context.Context, filePaths
 		for _, imp := range fileAst.Imports {
 			if imp.Path != nil {
 				importPath := strings.Trim(imp.Path.Value, `"`)
+				if importPath == CgoImportPath {
+					info.UsesCgo = true
+					continue // Not a real package; there is nothing to walk.
+				}
 				imports[importPath] = struct{}{}
 				fileImports = append(fileImports, importPath)
 			}
@@ -242,11 +257,13 @@ func (s *Scanner) ScanPackageFromFilePathImports(ctx context.Context, filePaths
 	for imp := range imports {
 		info.Imports = append(info.Imports, imp)
 	}
+	sort.Strings(info.Imports)
 
 	return info, nil
 }
 
 func (s *Scanner) scanGoFiles(ctx context.Context, filePaths []string, pkgDirPath string, canonicalImportPath string) (*PackageInfo, error) {
+	loadMode := s.LoadModeFor(canonicalImportPath)
 	info := &PackageInfo{
 		Path:       pkgDirPath,
 		ImportPath: canonicalImportPath,
@@ -254,6 +271,11 @@ func (s *Scanner) scanGoFiles(ctx context.Context, filePaths []string, pkgDirPat
 		ModuleDir:  s.moduleRootDir,
 		Fset:       s.fset,
 		AstFiles:   make(map[string]*ast.File),
+		LoadMode:   loadMode,
+	}
+	parseMode := parser.ParseComments
+	if loadMode == LoadImports {
+		parseMode |= parser.ImportsOnly
 	}
 
 	// Stage 1: Parallel Parsing
@@ -286,11 +308,11 @@ func (s *Scanner) scanGoFiles(ctx context.Context, filePaths []string, pkgDirPat
 			}
 
 			s.mu.Lock()
-			fileAst, err := parser.ParseFile(s.fset, fp, content, parser.ParseComments)
+			fileAst, err := parser.ParseFile(s.fset, fp, content, parseMode)
 			s.mu.Unlock()
 
 			select {
-			case results <- fileParseResult{filePath: fp, fileAst: fileAst, err: err}:
+			case results <- fileParseResult{filePath: fp, fileAst: fileAst, content: content, err: err}:
 				return nil
 			case <-gCtx.Done():
 				return gCtx.Err()
@@ -306,14 +328,27 @@ func (s *Scanner) scanGoFiles(ctx context.Context, filePaths []string, pkgDirPat
 
 	// Stage 2: Collect Results
 	parsedFileResults := make([]fileParseResult, 0, len(filePaths))
+	sources := make(map[string][]byte, len(filePaths))
+	var fileErrs []*FileError
 	for result := range results {
 		if result.err != nil {
-			return nil, fmt.Errorf("failed to parse file %s: %w", result.filePath, result.err)
+			fileErrs = append(fileErrs, newFileErrors(result.filePath, result.err)...)
+			continue
 		}
 		if result.fileAst.Name == nil {
 			continue // Skip files with no package name
 		}
 		parsedFileResults = append(parsedFileResults, result)
+		sources[result.filePath] = result.content
+	}
+	if len(fileErrs) > 0 {
+		sortFileErrors(fileErrs)
+		pkgErr := &PackageError{Path: pkgDirPath, Errors: fileErrs}
+		if !s.PartialResults || len(parsedFileResults) == 0 {
+			return nil, pkgErr
+		}
+		slog.WarnContext(ctx, "skipping the files which cannot be parsed", "package", pkgDirPath, "errors", len(fileErrs), "error", pkgErr)
+		info.Errors = fileErrs
 	}
 
 	// Stage 3: Filter files by dominant package name
@@ -363,8 +398,73 @@ func (s *Scanner) scanGoFiles(ctx context.Context, filePaths []string, pkgDirPat
 		}
 	}
 
+	// The files of the external test package (`package foo_test`) are scanned as a
+	// separate package variant, linked from the package as its XTest.
+	var xtestFiles []*ast.File
+	var xtestFilePaths []string
+	if dominantPackageName != "" && !strings.HasSuffix(dominantPackageName, "_test") {
+		var baseFiles []*ast.File
+		var baseFilePaths []string
+		for i, f := range parsedFiles {
+			if f.Name.Name == dominantPackageName+"_test" {
+				xtestFiles = append(xtestFiles, f)
+				xtestFilePaths = append(xtestFilePaths, filePathsForDominantPkg[i])
+			} else {
+				baseFiles = append(baseFiles, f)
+				baseFilePaths = append(baseFilePaths, filePathsForDominantPkg[i])
+			}
+		}
+		parsedFiles, filePathsForDominantPkg = baseFiles, baseFilePaths
+	}
+
 	info.Name = dominantPackageName
 	info.Files = filePathsForDominantPkg
+	if info.Name == "" && len(filePaths) > 0 {
+		return nil, fmt.Errorf("could not determine package name from scanned files in %s", pkgDirPath)
+	}
+	s.collectDecls(ctx, info, parsedFiles, sources)
+
+	if len(xtestFiles) > 0 {
+		xtest := &PackageInfo{
+			Name:       dominantPackageName + "_test",
+			Path:       pkgDirPath,
+			ImportPath: canonicalImportPath + "_test",
+			ModulePath: s.modulePath,
+			ModuleDir:  s.moduleRootDir,
+			Files:      xtestFilePaths,
+			Fset:       s.fset,
+			AstFiles:   make(map[string]*ast.File),
+			LoadMode:   loadMode,
+			TestBase:   info,
+		}
+		s.collectDecls(ctx, xtest, xtestFiles, sources)
+		info.XTest = xtest
+	}
+	return info, nil
+}
+
+// collectDecls collects the declarations of the parsed files of a package into info,
+// according to its load mode. The files are in the same order as info.Files, and sources
+// holds their contents, keyed by file path, for the fingerprints of the files and declarations.
+func (s *Scanner) collectDecls(ctx context.Context, info *PackageInfo, parsedFiles []*ast.File, sources map[string][]byte) {
+	loadMode := info.LoadMode
+	for _, filePath := range info.Files {
+		info.FileInfos = append(info.FileInfos, &FileInfo{Path: filePath, Hash: contentHash(sources[filePath])})
+	}
+	for i, fileAst := range parsedFiles {
+		if importsC(fileAst) {
+			info.UsesCgo = true
+			info.CgoFiles = append(info.CgoFiles, info.Files[i])
+		}
+		info.Directives = append(info.Directives, collectDirectives(info.Fset, fileAst, info.Files[i])...)
+	}
+
+	if loadMode == LoadImports {
+		for i, fileAst := range parsedFiles {
+			info.AstFiles[info.Files[i]] = fileAst
+		}
+		return
+	}
 
 	// Pass 1: Create placeholders for all type declarations from the filtered files.
 	for i, fileAst := range parsedFiles {
@@ -379,6 +479,7 @@ func (s *Scanner) scanGoFiles(ctx context.Context, filePaths []string, pkgDirPat
 							PkgPath:  info.ImportPath,
 							FilePath: filePath,
 							Doc:      commentText(ts.Doc),
+							DeclHash: sourceHash(info.Fset, sources[filePath], ts.Pos(), ts.End()),
 							Node:     ts,
 							Inspect:  s.inspect,
 							Logger:   s.logger,
@@ -403,50 +504,103 @@ func (s *Scanner) scanGoFiles(ctx context.Context, filePaths []string, pkgDirPat
 	}
 
 	// Pass 3: Process all other declarations (consts, vars, funcs).
-	isDeclarationsOnly := false
-	for _, pattern := range s.DeclarationsOnlyPackages {
-		if matches(pattern, canonicalImportPath) {
-			isDeclarationsOnly = true
-			break
-		}
-	}
-
+	funcLits := &funcLitCollector{s: s, info: info}
 	for i, fileAst := range parsedFiles {
 		filePath := info.Files[i]
-		if isDeclarationsOnly {
-			for _, decl := range fileAst.Decls {
-				if f, ok := decl.(*ast.FuncDecl); ok {
+		importLookup := s.BuildImportLookup(fileAst)
+		// The body hashes and the calls are computed before the bodies are dropped with LoadDecls.
+		bodyHashes := make(map[*ast.FuncDecl]string)
+		calls := make(map[*ast.FuncDecl][]CallInfo)
+		for _, decl := range fileAst.Decls {
+			if f, ok := decl.(*ast.FuncDecl); ok && f.Body != nil {
+				bodyHashes[f] = sourceHash(info.Fset, sources[filePath], f.Body.Pos(), f.Body.End())
+				if s.CollectCalls {
+					calls[f] = collectCalls(f.Body, importLookup)
+				}
+				if loadMode == LoadDecls {
 					f.Body = nil
 				}
 			}
 		}
-		importLookup := s.BuildImportLookup(fileAst)
+		funcLits.filePath, funcLits.importLookup = filePath, importLookup
 		for _, decl := range fileAst.Decls {
 			switch d := decl.(type) {
 			case *ast.GenDecl:
 				if d.Tok != token.TYPE { // Types are already detailed, just do const/var
 					s.parseGenDecl(ctx, d, info, filePath, importLookup)
 				}
+				if d.Tok == token.VAR {
+					for _, spec := range d.Specs {
+						funcLits.collectValueSpec(ctx, spec.(*ast.ValueSpec))
+					}
+				}
 			case *ast.FuncDecl:
-				info.Functions = append(info.Functions, s.parseFuncDecl(ctx, d, filePath, info, importLookup))
+				fn := s.parseFuncDecl(ctx, d, filePath, info, importLookup)
+				fn.BodyHash = bodyHashes[d]
+				fn.Calls = calls[d]
+				info.Functions = append(info.Functions, fn)
+				funcLits.collectFuncDecl(ctx, fn)
 			}
 		}
 	}
 
-	if info.Name == "" && len(filePaths) > 0 {
-		return nil, fmt.Errorf("could not determine package name from scanned files in %s", pkgDirPath)
+	if s.CollectCalls {
+		resolveLocalCalls(info)
 	}
-
 	s.evaluateAllConstants(ctx, info)
+	s.inferConstantTypes(ctx, info)
+	s.inferVariableTypes(ctx, info)
 	s.resolveEnums(info)
-	return info, nil
+	linkEmbeds(info)
+	markTestDecls(info)
+}
+
+// contentHash returns the hex-encoded SHA-256 hash of content.
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// sourceHash returns the hash of the source text between pos and end in src, the content of
+// the file containing them, or "" if they are out of its range.
+func sourceHash(fset *token.FileSet, src []byte, pos, end token.Pos) string {
+	file := fset.File(pos)
+	if file == nil || !pos.IsValid() || !end.IsValid() {
+		return ""
+	}
+	start, stop := file.Offset(pos), file.Offset(end)
+	if start > stop || stop > len(src) {
+		return ""
+	}
+	return contentHash(src[start:stop])
+}
+
+// markTestDecls sets IsTest on the declarations of the package made in _test.go files.
+func markTestDecls(info *PackageInfo) {
+	for _, t := range info.Types {
+		t.IsTest = isTestFile(t.FilePath)
+	}
+	for _, f := range info.Functions {
+		f.IsTest = isTestFile(f.FilePath)
+	}
+	for _, c := range info.Constants {
+		c.IsTest = isTestFile(c.FilePath)
+	}
+	for _, v := range info.Variables {
+		v.IsTest = isTestFile(v.FilePath)
+	}
+}
+
+func isTestFile(path string) bool {
+	return strings.HasSuffix(path, "_test.go")
 }
 
 // resolveEnums performs a linking pass to connect constants with their enum types.
 func (s *Scanner) resolveEnums(pkgInfo *PackageInfo) {
 	for _, c := range pkgInfo.Constants {
-		// A constant must have an explicit type to be considered an enum member.
-		if c.Type == nil || c.Type.TypeName == "" {
+		// A constant must be typed to be considered an enum member, and the blank identifier
+		// (e.g. `_ Status = iota`) is not a member.
+		if c.Name == "_" || c.Type == nil || c.Type.TypeName == "" {
 			continue
 		}
 
@@ -466,6 +620,148 @@ func (s *Scanner) resolveEnums(pkgInfo *PackageInfo) {
 		typeInfo.EnumMembers = append(typeInfo.EnumMembers, c)
 		typeInfo.IsEnum = true
 	}
+
+	for _, t := range pkgInfo.Types {
+		if t.IsEnum {
+			t.EnumKind = classifyEnum(t.EnumMembers)
+		}
+	}
+}
+
+// classifyEnum returns the kind of the enum from the values of its members. The members whose
+// values could not be evaluated are ignored.
+func classifyEnum(members []*ConstantInfo) EnumKind {
+	var ints, strs int
+	var bits, combined uint64
+	shifted, distinctBits := false, true
+	for _, c := range members {
+		if c.ConstVal == nil {
+			continue
+		}
+		switch c.ConstVal.Kind() {
+		case constant.String:
+			strs++
+			continue
+		case constant.Int:
+			ints++
+		case constant.Unknown:
+			continue
+		default:
+			return UnknownEnumKind
+		}
+
+		if isShift(c.ValExpr) {
+			shifted = true
+		}
+		if bit, ok := singleBit(c.ConstVal); ok {
+			if bits&bit != 0 {
+				distinctBits = false
+			}
+			bits |= bit
+			continue
+		}
+		if v, exact := constant.Uint64Val(c.ConstVal); exact {
+			combined |= v // the zero value or a combination of the flags
+		} else {
+			distinctBits = false // negative
+		}
+	}
+
+	switch {
+	case strs > 0 && ints > 0:
+		return UnknownEnumKind
+	case strs > 0:
+		return StringEnumKind
+	case ints == 0:
+		return UnknownEnumKind
+	case shifted && distinctBits && bits != 0 && combined&^bits == 0:
+		return BitFlagsEnumKind
+	default:
+		return PlainEnumKind
+	}
+}
+
+// isShift reports whether the expression is a left shift, e.g. `1 << iota`.
+func isShift(expr ast.Expr) bool {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = paren.X
+	}
+	bin, ok := expr.(*ast.BinaryExpr)
+	return ok && bin.Op == token.SHL
+}
+
+// inferConstantTypes sets the types of the constants declared without a type, whose values are
+// typed, e.g. `B = Status("blocked")` or `Blue = Green + 10` where Green is a Color. The constants
+// whose values are untyped, e.g. `X = "x"`, are left without a type.
+func (s *Scanner) inferConstantTypes(ctx context.Context, info *PackageInfo) {
+	consts := make(map[string]*ConstantInfo, len(info.Constants))
+	for _, c := range info.Constants {
+		consts[c.Name] = c
+	}
+	inferring := make(map[*ConstantInfo]bool)
+
+	var inferConst func(c *ConstantInfo) *FieldType
+	var inferExpr func(c *ConstantInfo, expr ast.Expr) *FieldType
+	inferConst = func(c *ConstantInfo) *FieldType {
+		if c.Type != nil || c.ValExpr == nil || inferring[c] {
+			return c.Type
+		}
+		inferring[c] = true
+		c.Type = inferExpr(c, c.ValExpr)
+		return c.Type
+	}
+	inferExpr = func(c *ConstantInfo, expr ast.Expr) *FieldType {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			return inferExpr(c, e.X)
+		case *ast.UnaryExpr:
+			return inferExpr(c, e.X)
+		case *ast.BinaryExpr:
+			switch e.Op {
+			case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ, token.LAND, token.LOR:
+				return nil // untyped bool
+			case token.SHL, token.SHR:
+				return inferExpr(c, e.X)
+			}
+			if typ := inferExpr(c, e.X); typ != nil {
+				return typ
+			}
+			return inferExpr(c, e.Y)
+		case *ast.Ident:
+			if ref, ok := consts[e.Name]; ok && ref != c {
+				return inferConst(ref)
+			}
+		case *ast.CallExpr:
+			fun, ok := e.Fun.(*ast.Ident)
+			if !ok || len(e.Args) != 1 {
+				return nil
+			}
+			if isBasicTypeName(fun.Name) || info.Lookup(fun.Name) != nil {
+				return s.TypeInfoFromExpr(ctx, fun, nil, info, s.BuildImportLookup(info.AstFiles[c.FilePath]))
+			}
+		}
+		return nil
+	}
+
+	for _, c := range info.Constants {
+		inferConst(c)
+	}
+}
+
+// isBasicTypeName reports whether name is a predeclared type which a constant can be converted to.
+func isBasicTypeName(name string) bool {
+	switch name {
+	case "string", "bool", "byte", "rune",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128":
+		return true
+	}
+	return false
 }
 
 // BuildImportLookup creates a map of local import names to their full package paths.
@@ -497,13 +793,17 @@ func (s *Scanner) parseGenDecl(ctx context.Context, decl *ast.GenDecl, info *Pac
 		var lastConstValues []ast.Expr
 		for iota, spec := range decl.Specs {
 			if vs, ok := spec.(*ast.ValueSpec); ok {
+				// A spec without a type and values repeats the previous one (implicit repetition),
+				// but a spec with values and without a type declares untyped constants, whose
+				// types are inferred later by inferConstantTypes.
 				var currentSpecType *FieldType
-				if vs.Type != nil {
+				switch {
+				case vs.Type != nil:
 					currentSpecType = s.TypeInfoFromExpr(ctx, vs.Type, nil, info, importLookup)
-					lastConstType = currentSpecType
-				} else {
+				case len(vs.Values) == 0:
 					currentSpecType = lastConstType
 				}
+				lastConstType = currentSpecType
 
 				if len(vs.Values) > 0 {
 					lastConstValues = vs.Values
@@ -547,16 +847,26 @@ func (s *Scanner) parseGenDecl(ctx context.Context, decl *ast.GenDecl, info *Pac
 					varType = s.TypeInfoFromExpr(ctx, vs.Type, nil, info, importLookup)
 				}
 
-				for _, name := range vs.Names {
+				doc := commentText(vs.Doc)
+				if doc == "" && decl.Doc != nil {
+					doc = commentText(decl.Doc)
+				}
+				for i, name := range vs.Names {
 					varInfo := &VariableInfo{
 						Name:       name.Name,
 						FilePath:   absFilePath,
-						Doc:        commentText(vs.Doc),
+						Doc:        doc,
 						Type:       varType,
 						IsExported: name.IsExported(),
 						Node:       name,
 						GenDecl:    decl,
 					}
+					switch {
+					case len(vs.Values) == len(vs.Names):
+						varInfo.ValExpr = vs.Values[i]
+					case len(vs.Values) == 1:
+						varInfo.ValExpr, varInfo.ValIndex = vs.Values[0], i
+					}
 					info.Variables = append(info.Variables, varInfo)
 				}
 			}
@@ -695,18 +1005,27 @@ func (s *Scanner) evalConstExpr(cctx *constContext, currentConst *ConstantInfo,
 		// TODO: Handle cross-package constant references.
 		return nil, fmt.Errorf("cross-package constant references not supported")
 	case *ast.CallExpr:
-		// Handle simple type conversions like `uint(0)`.
-		if typeIdent, ok := n.Fun.(*ast.Ident); ok {
-			if len(n.Args) == 1 {
-				if lit, ok := n.Args[0].(*ast.BasicLit); ok && lit.Kind == token.INT {
-					// This is a basic form of type conversion, e.g., uint(0).
-					// We can treat the literal as the value.
-					// This is a simplification and doesn't handle all conversions.
-					switch typeIdent.Name {
-					case "uint", "int", "uint64", "int64", "float64", "float32", "string":
-						return constant.MakeFromLiteral(lit.Value, lit.Kind, 0), nil
+		// Handle type conversions like `uint(0)` or `Status("active")`, to a predeclared type or
+		// to a type of the package. The value is kept as is, except for the conversions of an
+		// integer to a string or a float.
+		if typeIdent, ok := n.Fun.(*ast.Ident); ok && len(n.Args) == 1 {
+			if isBasicTypeName(typeIdent.Name) || cctx.pkg.Lookup(typeIdent.Name) != nil {
+				val, err := s.evalConstExpr(cctx, currentConst, n.Args[0])
+				if err != nil {
+					return nil, err
+				}
+				switch typeIdent.Name {
+				case "string":
+					if val.Kind() != constant.Int {
+						break
+					}
+					if r, ok := constant.Int64Val(val); ok {
+						return constant.MakeString(string(rune(r))), nil
 					}
+				case "float32", "float64":
+					return constant.ToFloat(val), nil
 				}
+				return val, nil
 			}
 		}
 		// TODO: Handle built-in functions like unsafe.Sizeof.
@@ -747,6 +1066,13 @@ func (s *Scanner) fillTypeInfoFromSpec(ctx context.Context, typeInfo *TypeInfo,
 		typeInfo.TypeParams = s.parseTypeParamList(childCtx, sp.TypeParams.List, info, importLookup)
 	}
 
+	if sp.Assign.IsValid() {
+		// An alias declaration (`type A = B`) denotes the same type as B. Record the target
+		// so that consumers can see through the alias, regardless of the target's kind.
+		typeInfo.IsAlias = true
+		typeInfo.AliasTarget = s.TypeInfoFromExpr(childCtx, sp.Type, typeInfo.TypeParams, info, importLookup)
+	}
+
 	switch t := sp.Type.(type) {
 	case *ast.StructType:
 		typeInfo.Kind = StructKind
@@ -764,7 +1090,11 @@ func (s *Scanner) fillTypeInfoFromSpec(ctx context.Context, typeInfo *TypeInfo,
 		typeInfo.Func = funcInfo
 	default:
 		typeInfo.Kind = AliasKind
-		typeInfo.Underlying = s.TypeInfoFromExpr(childCtx, sp.Type, typeInfo.TypeParams, info, importLookup)
+		if typeInfo.AliasTarget != nil {
+			typeInfo.Underlying = typeInfo.AliasTarget
+		} else {
+			typeInfo.Underlying = s.TypeInfoFromExpr(childCtx, sp.Type, typeInfo.TypeParams, info, importLookup)
+		}
 	}
 }
 
@@ -791,17 +1121,47 @@ func (s *Scanner) parseTypeParamList(ctx context.Context, typeParamFields []*ast
 	return params
 }
 
-// collectUnionTypes recursively traverses a binary expression representing a type union
-// (e.g., *Foo | *Bar) and collects all constituent types.
-func (s *Scanner) collectUnionTypes(ctx context.Context, expr ast.Expr, currentTypeParams []*TypeParamInfo, info *PackageInfo, importLookup map[string]string) []*FieldType {
-	if binExpr, ok := expr.(*ast.BinaryExpr); ok && binExpr.Op == token.OR {
-		// This is a union type, e.g., `A | B`. Recursively collect from both sides.
-		leftTypes := s.collectUnionTypes(ctx, binExpr.X, currentTypeParams, info, importLookup)
-		rightTypes := s.collectUnionTypes(ctx, binExpr.Y, currentTypeParams, info, importLookup)
-		return append(leftTypes, rightTypes...)
-	}
-	// This is a single type (a leaf in the union expression tree).
-	return []*FieldType{s.TypeInfoFromExpr(ctx, expr, currentTypeParams, info, importLookup)}
+// collectTypeTerms traverses the terms of a type list (e.g., ~int | ~string) and collects them.
+func (s *Scanner) collectTypeTerms(ctx context.Context, expr ast.Expr, currentTypeParams []*TypeParamInfo, info *PackageInfo, importLookup map[string]string) []*TypeTerm {
+	switch t := expr.(type) {
+	case *ast.BinaryExpr:
+		if t.Op == token.OR {
+			left := s.collectTypeTerms(ctx, t.X, currentTypeParams, info, importLookup)
+			return append(left, s.collectTypeTerms(ctx, t.Y, currentTypeParams, info, importLookup)...)
+		}
+	case *ast.UnaryExpr:
+		if t.Op == token.TILDE {
+			return []*TypeTerm{{Tilde: true, Type: s.TypeInfoFromExpr(ctx, t.X, currentTypeParams, info, importLookup)}}
+		}
+	case *ast.ParenExpr:
+		return s.collectTypeTerms(ctx, t.X, currentTypeParams, info, importLookup)
+	}
+	return []*TypeTerm{{Type: s.TypeInfoFromExpr(ctx, expr, currentTypeParams, info, importLookup)}}
+}
+
+// isTypeTerm reports whether an embedded element of an interface is a type term rather than
+// an embedded interface. Named types are assumed to be interfaces, as they cannot be told apart
+// without resolving them.
+func isTypeTerm(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.BinaryExpr:
+		return t.Op == token.OR
+	case *ast.UnaryExpr:
+		return t.Op == token.TILDE
+	case *ast.ParenExpr:
+		return isTypeTerm(t.X)
+	case *ast.Ident:
+		switch t.Name {
+		case "bool", "byte", "complex64", "complex128", "float32", "float64",
+			"int", "int8", "int16", "int32", "int64", "rune", "string",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr":
+			return true
+		}
+		return false
+	case *ast.StarExpr, *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.StructType:
+		return true
+	}
+	return false
 }
 
 func (s *Scanner) parseInterfaceType(ctx context.Context, it *ast.InterfaceType, currentTypeParams []*TypeParamInfo, info *PackageInfo, importLookup map[string]string) *InterfaceInfo {
@@ -839,10 +1199,15 @@ func (s *Scanner) parseInterfaceType(ctx context.Context, it *ast.InterfaceType,
 			methodInfo.Results = parsedFuncDetails.Results
 			interfaceInfo.Methods = append(interfaceInfo.Methods, methodInfo)
 		} else { // This is an embedded type or a union term
-			if isUnionInterface {
+			if isUnionInterface || isTypeTerm(field.Type) {
 				// If we determined this is a union interface, all non-method fields are terms.
-				terms := s.collectUnionTypes(ctx, field.Type, currentTypeParams, info, importLookup)
-				interfaceInfo.Union = append(interfaceInfo.Union, terms...)
+				terms := s.collectTypeTerms(ctx, field.Type, currentTypeParams, info, importLookup)
+				interfaceInfo.TypeTerms = append(interfaceInfo.TypeTerms, terms...)
+				if isUnionInterface {
+					for _, term := range terms {
+						interfaceInfo.Union = append(interfaceInfo.Union, term.Type)
+					}
+				}
 			} else {
 				// Otherwise, it's a regular embedded interface.
 				embeddedType := s.TypeInfoFromExpr(ctx, field.Type, currentTypeParams, info, importLookup)
@@ -903,7 +1268,7 @@ func (s *Scanner) parseFuncDecl(ctx context.Context, f *ast.FuncDecl, absFilePat
 	funcInfo.TypeParams = funcOwnTypeParams
 	funcInfo.Pkg = pkgInfo // Set the back-reference to the package
 
-	// After parsing the function signature, walk its body to find and resolve local type declarations.
+	// After parsing the function signature, walk its body to collect the local type declarations.
 	if f.Body != nil {
 		ast.Inspect(f.Body, func(n ast.Node) bool {
 			gd, ok := n.(*ast.GenDecl)
@@ -912,33 +1277,19 @@ func (s *Scanner) parseFuncDecl(ctx context.Context, f *ast.FuncDecl, absFilePat
 			}
 
 			// We found a local type declaration block (e.g., `type (...)`).
-			// Process it now and perform the special local-alias resolution.
 			for _, spec := range gd.Specs {
 				if ts, ok := spec.(*ast.TypeSpec); ok {
 					typeInfo := s.parseTypeSpec(ctx, ts, pkgInfo, absFilePath, importLookup)
 					if typeInfo.Doc == "" && gd.Doc != nil {
 						typeInfo.Doc = commentText(gd.Doc)
 					}
-
-					// This is the special logic that only runs for local types.
-					// Try to link the underlying type's definition immediately.
-					if typeInfo.Kind == AliasKind && typeInfo.Underlying != nil && typeInfo.Underlying.PkgName == "" {
-						underlyingName := typeInfo.Underlying.TypeName
-						if def := pkgInfo.Lookup(underlyingName); def != nil {
-							typeInfo.Underlying.Definition = def
-							// Also link the element's definition for pointers
-							if typeInfo.Underlying.IsPointer && typeInfo.Underlying.Elem != nil {
-								if elemDef := pkgInfo.Lookup(typeInfo.Underlying.Elem.TypeName); elemDef != nil {
-									typeInfo.Underlying.Elem.Definition = elemDef
-								}
-							}
-						}
-					}
-					pkgInfo.Types = append(pkgInfo.Types, typeInfo)
+					typeInfo.EnclosingFunc = funcInfo
+					funcInfo.LocalTypes = append(funcInfo.LocalTypes, typeInfo)
 				}
 			}
 			return false // Stop traversal within this GenDecl, as we've processed it.
 		})
+		linkLocalTypes(funcInfo.LocalTypes, pkgInfo)
 	}
 
 	if f.Recv != nil && len(f.Recv.List) > 0 {
@@ -948,6 +1299,8 @@ func (s *Scanner) parseFuncDecl(ctx context.Context, f *ast.FuncDecl, absFilePat
 			recvName = recvField.Names[0].Name
 		}
 
+		_, _, funcInfo.ReceiverTypeParams = receiverFromExpr(recvField.Type)
+
 		var receiverBaseTypeParams []*TypeParamInfo
 		parsedRecvFieldType := s.TypeInfoFromExpr(ctx, recvField.Type, funcOwnTypeParams, pkgInfo, importLookup)
 
@@ -963,7 +1316,7 @@ func (s *Scanner) parseFuncDecl(ctx context.Context, f *ast.FuncDecl, absFilePat
 			if pkgInfo != nil {
 				for _, ti := range pkgInfo.Types {
 					if ti.Name == baseRecvTypeName {
-						receiverBaseTypeParams = ti.TypeParams
+						receiverBaseTypeParams = renameReceiverTypeParams(ti.TypeParams, funcInfo.ReceiverTypeParams)
 						parsedRecvFieldType = s.TypeInfoFromExpr(ctx, recvField.Type, receiverBaseTypeParams, pkgInfo, importLookup)
 						break
 					}
@@ -987,6 +1340,55 @@ func (s *Scanner) parseFuncDecl(ctx context.Context, f *ast.FuncDecl, absFilePat
 	return funcInfo
 }
 
+// linkLocalTypes links the references to the local types of a function, among themselves, to
+// their definitions, as they cannot be resolved by name from the package. The underlying type
+// of a local type may also refer to a package-level type declared so far.
+func linkLocalTypes(locals []*TypeInfo, pkgInfo *PackageInfo) {
+	if len(locals) == 0 {
+		return
+	}
+	byName := make(map[string]*TypeInfo, len(locals))
+	for _, ti := range locals {
+		byName[ti.Name] = ti
+	}
+	lookup := func(name string) *TypeInfo {
+		if ti, ok := byName[name]; ok {
+			return ti
+		}
+		// pkgInfo.Lookup is not used, as it would index the package types before all are parsed.
+		for _, ti := range pkgInfo.Types {
+			if ti.Name == name {
+				return ti
+			}
+		}
+		return nil
+	}
+	var link func(ft *FieldType)
+	link = func(ft *FieldType) {
+		if ft == nil {
+			return
+		}
+		if ft.Definition == nil && ft.PkgName == "" && !ft.IsBuiltin && !ft.IsTypeParam && ft.TypeName != "" {
+			if def := lookup(ft.TypeName); def != nil {
+				ft.Definition = def
+			}
+		}
+		link(ft.Elem)
+		link(ft.MapKey)
+		for _, arg := range ft.TypeArgs {
+			link(arg)
+		}
+	}
+	for _, ti := range locals {
+		link(ti.Underlying)
+		if ti.Struct != nil {
+			for _, f := range ti.Struct.Fields {
+				link(f.Type)
+			}
+		}
+	}
+}
+
 func (s *Scanner) parseFuncType(ctx context.Context, ft *ast.FuncType, currentTypeParams []*TypeParamInfo, info *PackageInfo, importLookup map[string]string) *FunctionInfo {
 	funcInfo := &FunctionInfo{}
 	if ft.Params != nil {
@@ -1295,13 +1697,3 @@ func commentText(cg *ast.CommentGroup) string {
 	}
 	return strings.TrimSpace(cg.Text())
 }
-
-// matches checks if a given path matches a pattern.
-// The pattern can end with "..." to match any sub-path.
-func matches(pattern, path string) bool {
-	if strings.HasSuffix(pattern, "/...") {
-		base := strings.TrimSuffix(pattern, "/...")
-		return path == base || strings.HasPrefix(path, base+"/")
-	}
-	return path == pattern
-}
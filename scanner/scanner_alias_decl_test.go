@@ -0,0 +1,103 @@
+package scanner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestScanner_AliasDeclaration(t *testing.T) {
+	workdir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod": "module example.com/main\n\ngo 1.24\n",
+		"models/models.go": `package models
+
+type User struct {
+	Name string
+}
+`,
+		"main.go": `package main
+
+import "example.com/main/models"
+
+type Local struct {
+	ID int
+}
+
+type Defined Local
+
+type LocalAlias = Local
+
+type ChainedAlias = LocalAlias
+
+type ExternalAlias = models.User
+
+type StringAlias = string
+
+type PtrAlias = *Local
+
+type InlineAlias = struct {
+	X int
+}
+`,
+	})
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithWorkDir(workdir))
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	ctx := context.Background()
+	pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/main")
+	if err != nil {
+		t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+	}
+
+	type result struct {
+		IsAlias      bool
+		Target       string
+		ResolvedName string
+		ResolvedPkg  string
+		ResolvedKind scanner.Kind
+	}
+	cases := []struct {
+		name string
+		want result
+	}{
+		{name: "Local", want: result{ResolvedName: "Local", ResolvedPkg: "example.com/main", ResolvedKind: scanner.StructKind}},
+		{name: "Defined", want: result{ResolvedName: "Defined", ResolvedPkg: "example.com/main", ResolvedKind: scanner.AliasKind}},
+		{name: "LocalAlias", want: result{IsAlias: true, Target: "Local", ResolvedName: "Local", ResolvedPkg: "example.com/main", ResolvedKind: scanner.StructKind}},
+		{name: "ChainedAlias", want: result{IsAlias: true, Target: "LocalAlias", ResolvedName: "Local", ResolvedPkg: "example.com/main", ResolvedKind: scanner.StructKind}},
+		{name: "ExternalAlias", want: result{IsAlias: true, Target: "models.User", ResolvedName: "User", ResolvedPkg: "example.com/main/models", ResolvedKind: scanner.StructKind}},
+		{name: "StringAlias", want: result{IsAlias: true, Target: "string", ResolvedName: "StringAlias", ResolvedPkg: "example.com/main", ResolvedKind: scanner.AliasKind}},
+		{name: "PtrAlias", want: result{IsAlias: true, Target: "*Local", ResolvedName: "PtrAlias", ResolvedPkg: "example.com/main", ResolvedKind: scanner.AliasKind}},
+		{name: "InlineAlias", want: result{IsAlias: true, Target: "struct{...}", ResolvedName: "InlineAlias", ResolvedPkg: "example.com/main", ResolvedKind: scanner.StructKind}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ti := pkg.Lookup(tc.name)
+			if ti == nil {
+				t.Fatalf("type %s not found", tc.name)
+			}
+			resolved, err := ti.ResolveAlias(ctx)
+			if err != nil {
+				t.Fatalf("ResolveAlias() failed: %v", err)
+			}
+			got := result{
+				IsAlias:      ti.IsAlias,
+				ResolvedName: resolved.Name,
+				ResolvedPkg:  resolved.PkgPath,
+				ResolvedKind: resolved.Kind,
+			}
+			if ti.AliasTarget != nil {
+				got.Target = ti.AliasTarget.String()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
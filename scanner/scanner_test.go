@@ -693,8 +693,12 @@ func main() {
 	}
 
 	// 3. Assertions: Check if the scanner correctly parsed the local alias.
+	// The local type is attached to main, not listed in the package types.
+	if pkg.Lookup("Alias") != nil {
+		t.Error("Alias should not be a package-level type")
+	}
 	var aliasTypeInfo *TypeInfo
-	for _, ti := range pkg.Types {
+	for _, ti := range pkg.Functions[0].LocalTypes {
 		if ti.Name == "Alias" {
 			aliasTypeInfo = ti
 			break
@@ -755,8 +759,12 @@ func main() {
 	}
 
 	// 3. Assertions
+	// The local type is attached to main, not listed in the package types.
+	if pkg.Lookup("Alias") != nil {
+		t.Error("Alias should not be a package-level type")
+	}
 	var aliasTypeInfo *TypeInfo
-	for _, ti := range pkg.Types {
+	for _, ti := range pkg.Functions[0].LocalTypes {
 		if ti.Name == "Alias" {
 			aliasTypeInfo = ti
 			break
@@ -798,6 +806,71 @@ func main() {
 	}
 }
 
+func TestScanner_LocalTypes(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "main.go")
+	code := `
+package main
+type row struct {
+	Name string
+}
+func other() {
+	type row []string
+}
+func run() {
+	type (
+		row struct {
+			cells []cell
+		}
+		cell int
+	)
+}
+`
+	if err := os.WriteFile(filePath, []byte(code), 0644); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	s := newTestScanner(t, "example.com/me", dir)
+	pkg, err := s.ScanFiles(context.Background(), []string{filePath}, dir)
+	if err != nil {
+		t.Fatalf("scanning files: %v", err)
+	}
+
+	if len(pkg.Types) != 1 {
+		t.Fatalf("want only the package-level row in the package types, got %d types", len(pkg.Types))
+	}
+	if row := pkg.Lookup("row"); row == nil || row.Kind != StructKind || row.EnclosingFunc != nil {
+		t.Errorf("the package-level row is shadowed by a local type: %+v", row)
+	}
+
+	locals := make(map[string][]*TypeInfo)
+	for _, fn := range pkg.Functions {
+		for _, ti := range fn.LocalTypes {
+			if ti.EnclosingFunc != fn {
+				t.Errorf("%s.%s: EnclosingFunc is not set", fn.Name, ti.Name)
+			}
+		}
+		locals[fn.Name] = fn.LocalTypes
+	}
+	if got := locals["other"]; len(got) != 1 || got[0].Name != "row" || got[0].Underlying == nil || !got[0].Underlying.IsSlice {
+		t.Errorf("other: unexpected local types %+v", got)
+	}
+
+	got := locals["run"]
+	if len(got) != 2 {
+		t.Fatalf("run: want 2 local types, got %d", len(got))
+	}
+	row, cell := got[0], got[1]
+	if row.Struct == nil || len(row.Struct.Fields) != 1 {
+		t.Fatalf("run: row should be a struct with one field, got %+v", row)
+	}
+	// The field refers to the local cell declared after row, in the same function.
+	elem := row.Struct.Fields[0].Type.Elem
+	if elem == nil || elem.Definition != cell {
+		t.Errorf("run: the field type should be linked to the local cell, got %+v", elem)
+	}
+}
+
 func TestScanner_GenericMethodTypeParameters(t *testing.T) {
 	// 1. Setup: Create code with a method on a generic type.
 	dir := t.TempDir()
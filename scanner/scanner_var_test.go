@@ -0,0 +1,107 @@
+package scanner_test
+
+import (
+	"context"
+	"go/ast"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	scan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestVariables(t *testing.T) {
+	workdir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod": "module example.com/vars",
+		"vars.go": `package vars
+
+import "time"
+
+type Config struct{ Name string }
+
+type Level int
+
+const Timeout = 3
+const Prefix string = "x-"
+
+// Default is the default config.
+var Default = &Config{Name: "default"}
+
+var (
+	// Count is declared with a type.
+	Count int
+	Name  = "vars"
+	Ratio = 0.5
+	Ready = Count > 0
+	Level0 = Level(0)
+	Wait   = Timeout
+	Keys   = make(map[string]int)
+	Clone  = Default
+)
+
+var Started, Err = start()
+
+var Interval = time.Second
+
+var handler = func(name string) error { return nil }
+
+func start() (time.Time, error) { return time.Time{}, nil }
+`,
+	})
+	defer cleanup()
+
+	s, err := scan.New(scan.WithWorkDir(workdir))
+	if err != nil {
+		t.Fatalf("scan.New() failed: %v", err)
+	}
+	pkg, err := s.ScanPackageFromImportPath(context.Background(), "example.com/vars")
+	if err != nil {
+		t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+	}
+
+	type want struct {
+		Type     string
+		Inferred bool
+		Init     bool
+		ValIndex int
+		Doc      string
+	}
+	got := map[string]want{}
+	for _, v := range pkg.Variables {
+		w := want{Inferred: v.IsTypeInferred, Init: v.ValExpr != nil, ValIndex: v.ValIndex, Doc: v.Doc}
+		if v.Type != nil {
+			w.Type = v.Type.String()
+		}
+		got[v.Name] = w
+	}
+
+	expected := map[string]want{
+		"Default":  {Type: "*Config", Inferred: true, Init: true, Doc: "Default is the default config."},
+		"Count":    {Type: "int", Doc: "Count is declared with a type."},
+		"Name":     {Type: "string", Inferred: true, Init: true},
+		"Ratio":    {Type: "float64", Inferred: true, Init: true},
+		"Ready":    {Type: "bool", Inferred: true, Init: true},
+		"Level0":   {Type: "Level", Inferred: true, Init: true},
+		"Wait":     {Type: "int", Inferred: true, Init: true},
+		"Keys":     {Type: "map[string]int", Inferred: true, Init: true},
+		"Clone":    {Type: "*Config", Inferred: true, Init: true},
+		"Started":  {Type: "time.Time", Inferred: true, Init: true},
+		"Err":      {Type: "error", Inferred: true, Init: true, ValIndex: 1},
+		"Interval": {Init: true}, // a variable of another package is not inferred
+		"handler":  {Init: true}, // function types are not supported
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("variables mismatch (-want +got):\n%s", diff)
+	}
+
+	var started *scanner.VariableInfo
+	for _, v := range pkg.Variables {
+		if v.Name == "Started" {
+			started = v
+		}
+	}
+	if call, ok := started.ValExpr.(*ast.CallExpr); !ok || call.Fun.(*ast.Ident).Name != "start" {
+		t.Errorf("unexpected initializer of Started: %#v", started.ValExpr)
+	}
+}
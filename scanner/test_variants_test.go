@@ -0,0 +1,123 @@
+package scanner_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestScanner_TestVariants(t *testing.T) {
+	workdir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod": "module example.com/variants",
+		"lib/lib.go": `package lib
+
+func Hello() string { return "hello" }
+`,
+		"lib/lib_test.go": `package lib
+
+import "testing"
+
+const fixture = "hello"
+
+type helper struct{}
+
+func TestHello(t *testing.T) {}
+`,
+		"lib/example_test.go": `package lib_test
+
+import (
+	"testing"
+
+	"example.com/variants/lib"
+)
+
+func TestHelloExternal(t *testing.T) { lib.Hello() }
+`,
+	})
+	defer cleanup()
+
+	ctx := context.Background()
+	funcNames := func(pkg *scanner.PackageInfo) []string {
+		var names []string
+		for _, f := range pkg.Functions {
+			names = append(names, f.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	t.Run("with tests", func(t *testing.T) {
+		s, err := goscan.New(goscan.WithWorkDir(workdir), goscan.WithIncludeTests(true))
+		if err != nil {
+			t.Fatalf("goscan.New() failed: %v", err)
+		}
+		pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/variants/lib")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+		}
+
+		if diff := cmp.Diff([]string{"Hello", "TestHello"}, funcNames(pkg)); diff != "" {
+			t.Errorf("functions of the package mismatch (-want +got):\n%s", diff)
+		}
+		isTest := map[string]bool{}
+		for _, f := range pkg.Functions {
+			isTest[f.Name] = f.IsTest
+		}
+		if diff := cmp.Diff(map[string]bool{"Hello": false, "TestHello": true}, isTest); diff != "" {
+			t.Errorf("IsTest of functions mismatch (-want +got):\n%s", diff)
+		}
+		if typ := pkg.Lookup("helper"); typ == nil || !typ.IsTest {
+			t.Errorf("type helper should be marked as declared in tests")
+		}
+		if len(pkg.Constants) != 1 || !pkg.Constants[0].IsTest {
+			t.Errorf("constant fixture should be marked as declared in tests")
+		}
+
+		xtest := pkg.XTest
+		if xtest == nil {
+			t.Fatalf("XTest is nil")
+		}
+		if xtest.Name != "lib_test" || xtest.ImportPath != "example.com/variants/lib_test" {
+			t.Errorf("unexpected XTest: name=%q, import path=%q", xtest.Name, xtest.ImportPath)
+		}
+		if xtest.TestBase != pkg {
+			t.Errorf("TestBase of XTest is not the tested package")
+		}
+		if diff := cmp.Diff([]string{"TestHelloExternal"}, funcNames(xtest)); diff != "" {
+			t.Errorf("functions of XTest mismatch (-want +got):\n%s", diff)
+		}
+		if !xtest.Functions[0].IsTest || xtest.Functions[0].PkgPath != "example.com/variants/lib_test" {
+			t.Errorf("unexpected function of XTest: IsTest=%v, PkgPath=%q", xtest.Functions[0].IsTest, xtest.Functions[0].PkgPath)
+		}
+
+		got, err := s.ScanPackageFromImportPath(ctx, "example.com/variants/lib_test")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath() for the external test package failed: %v", err)
+		}
+		if got != xtest {
+			t.Errorf("the external test package is not the XTest of the tested package")
+		}
+	})
+
+	t.Run("without tests", func(t *testing.T) {
+		s, err := goscan.New(goscan.WithWorkDir(workdir))
+		if err != nil {
+			t.Fatalf("goscan.New() failed: %v", err)
+		}
+		pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/variants/lib")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+		}
+		if diff := cmp.Diff([]string{"Hello"}, funcNames(pkg)); diff != "" {
+			t.Errorf("functions of the package mismatch (-want +got):\n%s", diff)
+		}
+		if pkg.XTest != nil {
+			t.Errorf("XTest should be nil without tests")
+		}
+	})
+}
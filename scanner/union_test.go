@@ -105,3 +105,92 @@ type Loginable interface {
 		}
 	}
 }
+
+func TestScanner_TypeTerms(t *testing.T) {
+	source := `
+package mymodule
+
+type MyInt int
+
+type Stringer interface {
+	String() string
+}
+
+type Number interface {
+	~int | ~int64 | float64
+}
+
+type StringerInt interface {
+	~int
+	String() string
+}
+
+type Elems interface {
+	[]byte | ~map[string]MyInt
+}
+
+type Comparable interface {
+	comparable
+}
+
+type Plain interface {
+	Stringer
+}
+`
+	testDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(testDir, "go.mod"), []byte("module mymodule"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(testDir, "main.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestScanner(t, "mymodule", testDir)
+	pkgInfo, err := s.ScanFiles(context.Background(), []string{filepath.Join(testDir, "main.go")}, testDir)
+	if err != nil {
+		t.Fatalf("ScanFiles failed: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		wantTerms      []string
+		wantConstraint bool
+	}{
+		{name: "Number", wantTerms: []string{"~int", "~int64", "float64"}, wantConstraint: true},
+		{name: "StringerInt", wantTerms: []string{"~int"}, wantConstraint: true},
+		{name: "Elems", wantTerms: []string{"[]byte", "~map[string]MyInt"}, wantConstraint: true},
+		{name: "Comparable", wantConstraint: true},
+		{name: "Plain"},
+		{name: "Stringer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := pkgInfo.Lookup(tt.name)
+			if ti == nil || ti.Interface == nil {
+				t.Fatalf("interface %s not found", tt.name)
+			}
+			var got []string
+			for _, term := range ti.Interface.TypeTerms {
+				got = append(got, term.String())
+			}
+			if diff := cmp.Diff(tt.wantTerms, got); diff != "" {
+				t.Errorf("TypeTerms mismatch (-want +got):\n%s", diff)
+			}
+			if got := ti.Interface.IsConstraint(); got != tt.wantConstraint {
+				t.Errorf("IsConstraint() = %v, want %v", got, tt.wantConstraint)
+			}
+		})
+	}
+
+	// The type of a term with a tilde is resolved as the type itself.
+	number := pkgInfo.Lookup("Number").Interface
+	if term := number.TypeTerms[0]; !term.Tilde || term.Type.Name != "int" || !term.Type.IsBuiltin {
+		t.Errorf("unexpected first term of Number: %+v", term.Type)
+	}
+	if len(number.Union) != 3 {
+		t.Errorf("expected the union of Number to have 3 members, got %d", len(number.Union))
+	}
+	if len(pkgInfo.Lookup("StringerInt").Interface.Methods) != 1 {
+		t.Errorf("expected StringerInt to keep its method")
+	}
+}
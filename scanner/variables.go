@@ -0,0 +1,240 @@
+package scanner
+
+import (
+	"context"
+	"go/ast"
+	"go/constant"
+	"go/token"
+)
+
+// varContext holds the state needed for inferring the types of the variables of a package.
+type varContext struct {
+	pkg       *PackageInfo
+	vars      map[string]*VariableInfo
+	consts    map[string]*ConstantInfo
+	funcs     map[string]*FunctionInfo
+	types     map[string]*TypeInfo
+	inferring map[string]bool // For cycle detection
+}
+
+// inferVariableTypes sets the type of the variables declared without one, e.g. `var x = New()`,
+// from their initializer expressions. The type is left nil if it cannot be inferred from the
+// declarations of the package alone, e.g. for a call of a function of another package, or for
+// a function literal.
+func (s *Scanner) inferVariableTypes(ctx context.Context, info *PackageInfo) {
+	vctx := &varContext{
+		pkg:       info,
+		vars:      make(map[string]*VariableInfo, len(info.Variables)),
+		consts:    make(map[string]*ConstantInfo, len(info.Constants)),
+		funcs:     make(map[string]*FunctionInfo, len(info.Functions)),
+		types:     make(map[string]*TypeInfo, len(info.Types)),
+		inferring: make(map[string]bool),
+	}
+	for _, v := range info.Variables {
+		vctx.vars[v.Name] = v
+	}
+	for _, c := range info.Constants {
+		vctx.consts[c.Name] = c
+	}
+	for _, f := range info.Functions {
+		if f.Receiver == nil {
+			vctx.funcs[f.Name] = f
+		}
+	}
+	for _, t := range info.Types {
+		vctx.types[t.Name] = t
+	}
+
+	for _, v := range info.Variables {
+		s.inferVariableType(ctx, vctx, v)
+	}
+}
+
+func (s *Scanner) inferVariableType(ctx context.Context, vctx *varContext, v *VariableInfo) *FieldType {
+	if v.Type != nil || v.ValExpr == nil || vctx.inferring[v.Name] {
+		return v.Type
+	}
+	vctx.inferring[v.Name] = true
+	defer func() { vctx.inferring[v.Name] = false }()
+
+	importLookup := s.BuildImportLookup(vctx.pkg.AstFiles[v.FilePath])
+	if typ := s.inferExprType(ctx, vctx, v.ValExpr, v.ValIndex, importLookup); typ != nil {
+		v.Type = typ
+		v.IsTypeInferred = true
+	}
+	return v.Type
+}
+
+// inferExprType returns the type of the index-th value of expr, or nil if unknown.
+// The index is only non-zero for the multi-valued expressions, e.g. `var a, b = f()`.
+func (s *Scanner) inferExprType(ctx context.Context, vctx *varContext, expr ast.Expr, index int, importLookup map[string]string) *FieldType {
+	typeOf := func(typeExpr ast.Expr) *FieldType {
+		return s.TypeInfoFromExpr(ctx, typeExpr, nil, vctx.pkg, importLookup)
+	}
+
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return s.inferExprType(ctx, vctx, e.X, index, importLookup)
+	case *ast.BasicLit:
+		return typeOf(ast.NewIdent(defaultTypeOfLiteral(e.Kind)))
+	case *ast.CompositeLit:
+		if e.Type == nil {
+			return nil
+		}
+		return typeOf(e.Type)
+	case *ast.UnaryExpr:
+		switch e.Op {
+		case token.AND:
+			if lit, ok := e.X.(*ast.CompositeLit); ok && lit.Type != nil {
+				return typeOf(&ast.StarExpr{X: lit.Type})
+			}
+			return nil
+		case token.NOT:
+			return typeOf(ast.NewIdent("bool"))
+		case token.ARROW:
+			if index == 1 {
+				return typeOf(ast.NewIdent("bool")) // v, ok := <-ch
+			}
+			ch := s.inferExprType(ctx, vctx, e.X, 0, importLookup)
+			if ch == nil || !ch.IsChan {
+				return nil
+			}
+			return ch.Elem
+		default:
+			return s.inferExprType(ctx, vctx, e.X, 0, importLookup)
+		}
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ, token.LAND, token.LOR:
+			return typeOf(ast.NewIdent("bool"))
+		case token.SHL, token.SHR:
+			return s.inferExprType(ctx, vctx, e.X, 0, importLookup)
+		}
+		// The typed operand decides the type of the untyped one, e.g. `time.Second * 2`.
+		if _, ok := e.X.(*ast.BasicLit); ok {
+			return s.inferExprType(ctx, vctx, e.Y, 0, importLookup)
+		}
+		return s.inferExprType(ctx, vctx, e.X, 0, importLookup)
+	case *ast.TypeAssertExpr:
+		if index == 1 {
+			return typeOf(ast.NewIdent("bool")) // v, ok := x.(T)
+		}
+		if e.Type == nil {
+			return nil
+		}
+		return typeOf(e.Type)
+	case *ast.StarExpr:
+		ptr := s.inferExprType(ctx, vctx, e.X, 0, importLookup)
+		if ptr == nil || !ptr.IsPointer {
+			return nil
+		}
+		return ptr.Elem
+	case *ast.Ident:
+		switch e.Name {
+		case "true", "false":
+			return typeOf(ast.NewIdent("bool"))
+		case "nil":
+			return nil
+		}
+		if c, ok := vctx.consts[e.Name]; ok {
+			if c.Type != nil {
+				return c.Type
+			}
+			if name := defaultTypeOfConstant(c.ConstVal); name != "" {
+				return typeOf(ast.NewIdent(name))
+			}
+			return nil
+		}
+		if v, ok := vctx.vars[e.Name]; ok {
+			return s.inferVariableType(ctx, vctx, v)
+		}
+		return nil
+	case *ast.CallExpr:
+		return s.inferCallType(ctx, vctx, e, index, importLookup)
+	}
+	return nil
+}
+
+// inferCallType returns the type of the index-th result of a call, or of a conversion.
+func (s *Scanner) inferCallType(ctx context.Context, vctx *varContext, call *ast.CallExpr, index int, importLookup map[string]string) *FieldType {
+	typeOf := func(typeExpr ast.Expr) *FieldType {
+		return s.TypeInfoFromExpr(ctx, typeExpr, nil, vctx.pkg, importLookup)
+	}
+
+	switch fun := call.Fun.(type) {
+	case *ast.ParenExpr, *ast.StarExpr, *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.InterfaceType:
+		return typeOf(fun) // conversion, e.g. `[]byte("x")` or `(*T)(nil)`
+	case *ast.Ident:
+		switch fun.Name {
+		case "new":
+			if len(call.Args) == 1 {
+				return typeOf(&ast.StarExpr{X: call.Args[0]})
+			}
+			return nil
+		case "make":
+			if len(call.Args) > 0 {
+				return typeOf(call.Args[0])
+			}
+			return nil
+		case "len", "cap", "copy":
+			return typeOf(ast.NewIdent("int"))
+		case "string", "bool", "byte", "rune", "error", "any",
+			"int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+			"float32", "float64", "complex64", "complex128":
+			return typeOf(fun)
+		}
+		if _, ok := vctx.types[fun.Name]; ok {
+			return typeOf(fun) // conversion to a type of the package
+		}
+		if f, ok := vctx.funcs[fun.Name]; ok {
+			if len(f.TypeParams) > 0 || index >= len(f.Results) {
+				return nil // the type parameters are not inferred
+			}
+			return f.Results[index].Type
+		}
+	case *ast.IndexExpr:
+		if ident, ok := fun.X.(*ast.Ident); ok {
+			if _, ok := vctx.types[ident.Name]; ok {
+				return typeOf(fun) // conversion to an instantiated generic type
+			}
+		}
+	}
+	return nil
+}
+
+// defaultTypeOfLiteral returns the default type of an untyped literal.
+func defaultTypeOfLiteral(kind token.Token) string {
+	switch kind {
+	case token.INT:
+		return "int"
+	case token.FLOAT:
+		return "float64"
+	case token.IMAG:
+		return "complex128"
+	case token.CHAR:
+		return "rune"
+	default:
+		return "string"
+	}
+}
+
+// defaultTypeOfConstant returns the default type of an untyped constant, or "" if unknown.
+func defaultTypeOfConstant(val constant.Value) string {
+	if val == nil {
+		return ""
+	}
+	switch val.Kind() {
+	case constant.Bool:
+		return "bool"
+	case constant.String:
+		return "string"
+	case constant.Int:
+		return "int"
+	case constant.Float:
+		return "float64"
+	case constant.Complex:
+		return "complex128"
+	}
+	return ""
+}
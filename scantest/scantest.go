@@ -62,8 +62,9 @@ type Result struct {
 type RunOption func(*runConfig)
 
 type runConfig struct {
-	moduleRoot string
-	scanner    *scan.Scanner
+	moduleRoot     string
+	scanner        *scan.Scanner
+	scannerOptions []scan.ScannerOption
 }
 
 // WithModuleRoot explicitly sets the module root directory for the test run.
@@ -82,6 +83,14 @@ func WithScanner(s *scan.Scanner) RunOption {
 	}
 }
 
+// WithScannerOptions adds options to the scanner created by the Run function, e.g.
+// goscan.WithIncludeTests(true). It is ignored if a scanner is provided with WithScanner.
+func WithScannerOptions(options ...scan.ScannerOption) RunOption {
+	return func(c *runConfig) {
+		c.scannerOptions = append(c.scannerOptions, options...)
+	}
+}
+
 // Run sets up and executes a test scenario.
 // It returns a Result object if the action had side effects captured by the harness.
 //
@@ -145,6 +154,7 @@ func Run(t *testing.T, ctx context.Context, dir string, patterns []string, actio
 		if overlay != nil {
 			options = append(options, scan.WithOverlay(overlay))
 		}
+		options = append(options, cfg.scannerOptions...)
 
 		s, err = scan.New(options...)
 		if err != nil {
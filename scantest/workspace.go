@@ -0,0 +1,206 @@
+package scantest
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	scan "github.com/podhmo/go-scan"
+)
+
+// GoScanModulePath is the module path of go-scan, required by the modules of a Workspace with
+// Module.UseGoScan.
+const GoScanModulePath = "github.com/podhmo/go-scan"
+
+// Module is a module of a Workspace.
+type Module struct {
+	// Path is the module path, e.g. "example.com/app".
+	Path string
+	// Dir is the directory of the module relative to the root of the workspace, e.g. "app".
+	// If empty, the last element of Path is used.
+	Dir string
+	// Files are the files of the module, by slash-separated path relative to Dir.
+	// A go.mod file is generated unless one is given.
+	Files map[string]string
+	// Requires are the paths of the other modules of the workspace imported by this one. Each is
+	// required with a replace directive to its directory, so the module builds without go.work.
+	Requires []string
+	// UseGoScan requires go-scan with a replace directive to its local directory (see GoScanDir),
+	// for the modules importing the packages of go-scan, e.g. the code of a generator under test.
+	UseGoScan bool
+}
+
+// Workspace describes a temporary multi-module workspace, written by WriteWorkspace.
+type Workspace struct {
+	Modules []Module
+	// GoVersion is the version of the go directives of the generated go.mod and go.work files,
+	// "1.21" if empty.
+	GoVersion string
+	// SkipGoWork skips the generation of the go.work file using all the modules.
+	SkipGoWork bool
+}
+
+func (ws Workspace) goVersion() string {
+	if ws.GoVersion == "" {
+		return "1.21"
+	}
+	return ws.GoVersion
+}
+
+func (m Module) dir() string {
+	if m.Dir != "" {
+		return filepath.FromSlash(m.Dir)
+	}
+	return path.Base(m.Path)
+}
+
+// ModuleDirs returns the directories of the modules of the workspace written in root.
+func (ws Workspace) ModuleDirs(root string) []string {
+	dirs := make([]string, len(ws.Modules))
+	for i, m := range ws.Modules {
+		dirs[i] = filepath.Join(root, m.dir())
+	}
+	return dirs
+}
+
+// WriteWorkspace writes the modules of the workspace into a temporary directory, removed when the
+// test ends, and returns the directory. The go.mod files of the modules and the go.work file are
+// generated as described by Workspace and Module.
+func WriteWorkspace(t *testing.T, ws Workspace) string {
+	t.Helper()
+	if len(ws.Modules) == 0 {
+		t.Fatalf("scantest: the workspace has no modules")
+	}
+
+	dirs := make(map[string]string, len(ws.Modules)) // the directories of the modules, by module path
+	for _, m := range ws.Modules {
+		if m.Path == "" {
+			t.Fatalf("scantest: a module of the workspace has no path")
+		}
+		if _, ok := dirs[m.Path]; ok {
+			t.Fatalf("scantest: the module %s is declared twice", m.Path)
+		}
+		dirs[m.Path] = m.dir()
+	}
+
+	files := make(map[string]string)
+	for _, m := range ws.Modules {
+		for name, content := range m.Files {
+			files[filepath.Join(m.dir(), filepath.FromSlash(name))] = content
+		}
+		if _, ok := m.Files["go.mod"]; ok {
+			continue
+		}
+		goMod, err := generateGoMod(m, ws.goVersion(), dirs)
+		if err != nil {
+			t.Fatalf("scantest: %v", err)
+		}
+		files[filepath.Join(m.dir(), "go.mod")] = goMod
+	}
+	if !ws.SkipGoWork {
+		files["go.work"] = generateGoWork(ws)
+	}
+
+	dir, _ := WriteFiles(t, files)
+	return dir
+}
+
+// generateGoMod returns the go.mod file of the module, with the replace directives to the other
+// modules of the workspace, found in dirs, and to go-scan.
+func generateGoMod(m Module, goVersion string, dirs map[string]string) (string, error) {
+	type replace struct{ path, dir string }
+	var replaces []replace
+	for _, req := range m.Requires {
+		dir, ok := dirs[req]
+		if !ok {
+			return "", fmt.Errorf("the module %s requires %s, which is not a module of the workspace", m.Path, req)
+		}
+		rel, err := filepath.Rel(m.dir(), dir)
+		if err != nil {
+			return "", fmt.Errorf("relative path from %s to %s: %w", m.Path, req, err)
+		}
+		rel = filepath.ToSlash(rel)
+		if !strings.HasPrefix(rel, "../") {
+			rel = "./" + rel
+		}
+		replaces = append(replaces, replace{req, rel})
+	}
+	if m.UseGoScan {
+		replaces = append(replaces, replace{GoScanModulePath, filepath.ToSlash(GoScanDir())})
+	}
+	sort.Slice(replaces, func(i, j int) bool { return replaces[i].path < replaces[j].path })
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "module %s\n\ngo %s\n", m.Path, goVersion)
+	if len(replaces) > 0 {
+		b.WriteString("\nrequire (\n")
+		for _, r := range replaces {
+			fmt.Fprintf(&b, "\t%s v0.0.0\n", r.path)
+		}
+		b.WriteString(")\n\n")
+		for _, r := range replaces {
+			fmt.Fprintf(&b, "replace %s => %s\n", r.path, r.dir)
+		}
+	}
+	return b.String(), nil
+}
+
+// generateGoWork returns the go.work file using all the modules of the workspace.
+func generateGoWork(ws Workspace) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "go %s\n\nuse (\n", ws.goVersion())
+	for _, m := range ws.Modules {
+		fmt.Fprintf(&b, "\t./%s\n", filepath.ToSlash(m.dir()))
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
+
+// GoScanDir returns the root directory of the go-scan module this package is built from, the
+// target of the replace directives of Module.UseGoScan.
+func GoScanDir() string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return ""
+	}
+	return filepath.Dir(filepath.Dir(file))
+}
+
+// NewWorkspaceScanner returns a scanner over all the modules of the workspace written in root,
+// in workspace mode (see goscan.WithModuleDirs), resolving the packages of the other modules.
+// The options are applied after the default ones.
+func NewWorkspaceScanner(ws Workspace, root string, options ...scan.ScannerOption) (*scan.Scanner, error) {
+	defaults := []scan.ScannerOption{
+		scan.WithWorkDir(root),
+		scan.WithModuleDirs(ws.ModuleDirs(root)),
+		scan.WithGoModuleResolver(),
+	}
+	return scan.New(append(defaults, options...)...)
+}
+
+// RunWorkspace writes the workspace with WriteWorkspace, and runs the action as Run does, with a
+// scanner over all its modules created by NewWorkspaceScanner. The patterns are the import paths
+// of the packages, or their paths relative to the root of the workspace, e.g. "app/cmd".
+func RunWorkspace(t *testing.T, ctx context.Context, ws Workspace, patterns []string, action ActionFunc, opts ...RunOption) (*Result, error) {
+	t.Helper()
+
+	cfg := &runConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	root := WriteWorkspace(t, ws)
+	if cfg.scanner == nil {
+		s, err := NewWorkspaceScanner(ws, root, cfg.scannerOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("scantest: new workspace scanner: %w", err)
+		}
+		opts = append(opts, WithScanner(s))
+	}
+	return Run(t, ctx, root, patterns, action, append(opts, WithModuleRoot(root))...)
+}
@@ -0,0 +1,123 @@
+package scantest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	scan "github.com/podhmo/go-scan"
+)
+
+func TestWriteWorkspace(t *testing.T) {
+	ws := Workspace{
+		Modules: []Module{
+			{
+				Path:      "example.com/app",
+				Files:     map[string]string{"main.go": "package main\n"},
+				Requires:  []string{"example.com/lib"},
+				UseGoScan: true,
+			},
+			{
+				Path:  "example.com/lib",
+				Dir:   "libs/lib",
+				Files: map[string]string{"lib.go": "package lib\n"},
+			},
+		},
+	}
+	root := WriteWorkspace(t, ws)
+
+	want := map[string]string{
+		"go.work": "go 1.21\n\nuse (\n\t./app\n\t./libs/lib\n)\n",
+		"app/go.mod": fmt.Sprintf("module example.com/app\n\ngo 1.21\n\nrequire (\n\texample.com/lib v0.0.0\n\tgithub.com/podhmo/go-scan v0.0.0\n)\n\n"+
+			"replace example.com/lib => ../libs/lib\nreplace github.com/podhmo/go-scan => %s\n", filepath.ToSlash(GoScanDir())),
+		"app/main.go":     "package main\n",
+		"libs/lib/go.mod": "module example.com/lib\n\ngo 1.21\n",
+		"libs/lib/lib.go": "package lib\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("ReadFile(%q): %v", name, err)
+			continue
+		}
+		if diff := cmp.Diff(content, string(data)); diff != "" {
+			t.Errorf("%s mismatch (-want +got):\n%s", name, diff)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(GoScanDir(), "go.mod")); err != nil {
+		t.Errorf("GoScanDir() = %s, which is not the root of go-scan: %v", GoScanDir(), err)
+	}
+	if diff := cmp.Diff([]string{filepath.Join(root, "app"), filepath.Join(root, "libs", "lib")}, ws.ModuleDirs(root)); diff != "" {
+		t.Errorf("ModuleDirs() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunWorkspace(t *testing.T) {
+	ws := Workspace{
+		Modules: []Module{
+			{
+				Path: "example.com/app",
+				Files: map[string]string{
+					"model/model.go": `package model
+
+import (
+	"example.com/lib"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+type Model struct {
+	Thing lib.Thing
+	Kind  scanner.Kind
+}
+`,
+				},
+				Requires:  []string{"example.com/lib"},
+				UseGoScan: true,
+			},
+			{
+				Path:  "example.com/lib",
+				Files: map[string]string{"lib.go": "package lib\n\ntype Thing struct{ Name string }\n"},
+			},
+		},
+	}
+
+	action := func(ctx context.Context, s *scan.Scanner, pkgs []*scan.Package) error {
+		if len(pkgs) != 1 || pkgs[0].ImportPath != "example.com/app/model" {
+			return fmt.Errorf("expected the package example.com/app/model, got %d packages", len(pkgs))
+		}
+		model := pkgs[0].Lookup("Model")
+		if model == nil || model.Struct == nil {
+			return fmt.Errorf("struct Model not found")
+		}
+		want := map[string]string{
+			"Thing": "example.com/lib.Thing",
+			"Kind":  "github.com/podhmo/go-scan/scanner.Kind",
+		}
+		for _, f := range model.Struct.Fields {
+			def, err := f.Type.Resolve(ctx)
+			if err != nil {
+				return fmt.Errorf("resolve the type of %s: %w", f.Name, err)
+			}
+			if got := def.PkgPath + "." + def.Name; got != want[f.Name] {
+				t.Errorf("the type of %s: want %s, got %s", f.Name, want[f.Name], got)
+			}
+		}
+		return nil
+	}
+
+	for _, pattern := range []string{"example.com/app/model", "app/model"} {
+		t.Run(pattern, func(t *testing.T) {
+			result, err := RunWorkspace(t, context.Background(), ws, []string{pattern}, action)
+			if err != nil {
+				t.Fatalf("RunWorkspace() failed: %v", err)
+			}
+			if result != nil {
+				t.Errorf("expected a nil result for a pure check, but got %+v", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package goscan
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"sort"
+
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// SymbolID returns a stable identifier of a function, method or type, for tools that persist
+// results across runs (baselines, caches, diffs). It is computed from the package path, the kind
+// of the symbol, its name and, for methods, the receiver's type name, but not from file positions,
+// so it does not change when code moves around. The receiver's pointer-ness and type parameter
+// names are left out, as a type cannot have both a value and a pointer method of the same name.
+//
+//	func:example.com/pkg.Func
+//	method:example.com/pkg.Type.Method
+//	type:example.com/pkg.Type
+//
+// Several declarations may share an ID, e.g. the init functions of a package; use SymbolIDs
+// to disambiguate them.
+func SymbolID[T *scanner.FunctionInfo | *scanner.TypeInfo](sym T) string {
+	switch sym := any(sym).(type) {
+	case *scanner.FunctionInfo:
+		name := sym.CanonicalName()
+		if name.IsMethod() {
+			return fmt.Sprintf("method:%s.%s.%s", name.PkgPath, name.TypeName, name.Name)
+		}
+		return fmt.Sprintf("func:%s.%s", name.PkgPath, name.Name)
+	case *scanner.TypeInfo:
+		return fmt.Sprintf("type:%s.%s", sym.PkgPath, sym.Name)
+	}
+	return ""
+}
+
+// SymbolIDs maps the functions and types of a set of packages to stable IDs.
+// Each symbol gets its SymbolID, except for the declarations that share one: these are
+// suffixed with the base name of their file, e.g. "func:example.com/pkg.init@a.go",
+// and with their position in the file when that is not enough, e.g. "func:example.com/pkg.init@a.go#2".
+type SymbolIDs struct {
+	funcs map[*scanner.FunctionInfo]string
+	types map[*scanner.TypeInfo]string
+}
+
+// NewSymbolIDs computes the IDs of the functions and types declared in the packages.
+func NewSymbolIDs(pkgs ...*scanner.PackageInfo) *SymbolIDs {
+	ids := &SymbolIDs{
+		funcs: make(map[*scanner.FunctionInfo]string),
+		types: make(map[*scanner.TypeInfo]string),
+	}
+	for _, pkg := range pkgs {
+		if pkg == nil {
+			continue
+		}
+		var decls []symbolDecl
+		for _, fn := range pkg.Functions {
+			var pos token.Pos
+			if fn.AstDecl != nil {
+				pos = fn.AstDecl.Pos()
+			}
+			decls = append(decls, symbolDecl{id: SymbolID(fn), file: fn.FilePath, pos: pos, fn: fn})
+		}
+		for _, t := range pkg.Types {
+			var pos token.Pos
+			if t.Node != nil {
+				pos = t.Node.Pos()
+			}
+			decls = append(decls, symbolDecl{id: SymbolID(t), file: t.FilePath, pos: pos, typ: t})
+		}
+		ids.assign(decls)
+	}
+	return ids
+}
+
+type symbolDecl struct {
+	id   string
+	file string
+	pos  token.Pos
+	fn   *scanner.FunctionInfo
+	typ  *scanner.TypeInfo
+}
+
+func (ids *SymbolIDs) assign(decls []symbolDecl) {
+	groups := make(map[string][]symbolDecl)
+	for _, d := range decls {
+		groups[d.id] = append(groups[d.id], d)
+	}
+	for id, group := range groups {
+		if len(group) == 1 {
+			ids.set(group[0], id)
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			if bi, bj := filepath.Base(group[i].file), filepath.Base(group[j].file); bi != bj {
+				return bi < bj
+			}
+			return group[i].pos < group[j].pos
+		})
+		nth := make(map[string]int)
+		for _, d := range group {
+			base := filepath.Base(d.file)
+			nth[base]++
+			disambiguated := id + "@" + base
+			if n := nth[base]; n > 1 {
+				disambiguated = fmt.Sprintf("%s#%d", disambiguated, n)
+			}
+			ids.set(d, disambiguated)
+		}
+	}
+}
+
+func (ids *SymbolIDs) set(d symbolDecl, id string) {
+	if d.fn != nil {
+		ids.funcs[d.fn] = id
+	} else {
+		ids.types[d.typ] = id
+	}
+}
+
+// Func returns the ID of the function or method. For a function that is not declared in
+// the packages (e.g. an interface method), it returns its SymbolID.
+func (ids *SymbolIDs) Func(fn *scanner.FunctionInfo) string {
+	if id, ok := ids.funcs[fn]; ok {
+		return id
+	}
+	return SymbolID(fn)
+}
+
+// Type returns the ID of the type. For a type that is not declared in the packages,
+// it returns its SymbolID.
+func (ids *SymbolIDs) Type(t *scanner.TypeInfo) string {
+	if id, ok := ids.types[t]; ok {
+		return id
+	}
+	return SymbolID(t)
+}
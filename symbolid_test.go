@@ -0,0 +1,106 @@
+package goscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestSymbolIDs(t *testing.T) {
+	scan := func(t *testing.T, files map[string]string) map[string]bool {
+		t.Helper()
+		files["go.mod"] = "module example.com/app\n\ngo 1.22\n"
+		dir, cleanup := scantest.WriteFiles(t, files)
+		defer cleanup()
+
+		s, err := goscan.New(goscan.WithWorkDir(dir), goscan.WithGoModuleResolver())
+		if err != nil {
+			t.Fatalf("New() failed: %v", err)
+		}
+		pkg, err := s.ScanPackageFromImportPath(context.Background(), "example.com/app/lib")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+		}
+		ids := goscan.NewSymbolIDs(pkg)
+		got := make(map[string]bool)
+		for _, fn := range pkg.Functions {
+			got[ids.Func(fn)] = true
+		}
+		for _, typ := range pkg.Types {
+			got[ids.Type(typ)] = true
+		}
+		return got
+	}
+
+	want := map[string]bool{
+		"func:example.com/app/lib.Run":            true,
+		"method:example.com/app/lib.Box.Get":      true,
+		"method:example.com/app/lib.Server.Start": true,
+		"type:example.com/app/lib.Box":            true,
+		"type:example.com/app/lib.Server":         true,
+		"func:example.com/app/lib.init@a.go":      true,
+		"func:example.com/app/lib.init@a.go#2":    true,
+		"func:example.com/app/lib.init@b.go":      true,
+	}
+
+	t.Run("ids", func(t *testing.T) {
+		got := scan(t, map[string]string{
+			"lib/a.go": `package lib
+
+type Server struct{}
+
+func (s *Server) Start() {}
+
+func init() {}
+func init() {}
+`,
+			"lib/b.go": `package lib
+
+type Box[T any] struct{ v T }
+
+func (b Box[T]) Get() T { return b.v }
+
+func Run() {}
+
+func init() {}
+`,
+		})
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("symbol IDs mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("stable across code movement", func(t *testing.T) {
+		// The declarations are reordered and moved between files, and the receivers changed,
+		// but only the init functions keep their file.
+		got := scan(t, map[string]string{
+			"lib/a.go": `package lib
+
+// Run runs.
+func Run() {}
+
+func init() {}
+
+type Box[U any] struct{ v U }
+
+func (b *Box[U]) Get() U { return b.v }
+
+func init() {}
+`,
+			"lib/b.go": `package lib
+
+func init() {}
+
+type Server struct{ name string }
+
+func (s Server) Start() {}
+`,
+		})
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("symbol IDs mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -16,6 +16,11 @@ import (
 // that is out of the scan policy.
 var ErrUnresolvedEmbedded = fmt.Errorf("unresolved embedded type")
 
+// ErrAmbiguousEmbedded is a sentinel error returned when a method is promoted
+// from more than one embedded type at the same depth, or through two paths to the
+// same type, or collides with a field at that depth, making the selector ambiguous.
+var ErrAmbiguousEmbedded = fmt.Errorf("ambiguous selector via embedded types")
+
 // accessor provides methods for finding fields and methods on types,
 // handling embedded structs and method resolution.
 type accessor struct {
@@ -33,25 +38,36 @@ func (a *accessor) findFieldOnType(ctx context.Context, typeInfo *scanner.TypeIn
 		return nil, nil // Cannot find field without type info
 	}
 
+	field, _, err := a.findFieldOwner(ctx, typeInfo, fieldName)
+	return field, err
+}
+
+// findFieldOwner is like findFieldOnType, and also returns the struct type declaring the field,
+// which is an embedded type for a promoted field.
+func (a *accessor) findFieldOwner(ctx context.Context, typeInfo *scanner.TypeInfo, fieldName string) (*scanner.FieldInfo, *scanner.TypeInfo, error) {
+	if typeInfo == nil {
+		return nil, nil, nil
+	}
+
 	visited := make(map[string]bool)
 	return a.findFieldRecursive(ctx, typeInfo, fieldName, visited)
 }
 
-func (a *accessor) findFieldRecursive(ctx context.Context, typeInfo *scanner.TypeInfo, fieldName string, visited map[string]bool) (*scanner.FieldInfo, error) {
+func (a *accessor) findFieldRecursive(ctx context.Context, typeInfo *scanner.TypeInfo, fieldName string, visited map[string]bool) (*scanner.FieldInfo, *scanner.TypeInfo, error) {
 	if typeInfo == nil || typeInfo.Struct == nil {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	typeKey := fmt.Sprintf("%s.%s", typeInfo.PkgPath, typeInfo.Name)
 	if visited[typeKey] {
-		return nil, nil // Cycle detected
+		return nil, nil, nil // Cycle detected
 	}
 	visited[typeKey] = true
 
 	// 1. Search for a direct field on the current type.
 	for _, field := range typeInfo.Struct.Fields {
 		if !field.Embedded && field.Name == fieldName {
-			return field, nil
+			return field, typeInfo, nil
 		}
 	}
 
@@ -61,7 +77,7 @@ func (a *accessor) findFieldRecursive(ctx context.Context, typeInfo *scanner.Typ
 		if field.Embedded {
 			// If the embedded field itself has the name we're looking for (promoted field)
 			if field.Name == fieldName {
-				return field, nil
+				return field, typeInfo, nil
 			}
 
 			// An embedded field is considered "unresolved" if its import path is missing
@@ -75,16 +91,16 @@ func (a *accessor) findFieldRecursive(ctx context.Context, typeInfo *scanner.Typ
 
 			embeddedTypeInfo, _ := field.Type.Resolve(ctx)
 			if embeddedTypeInfo != nil {
-				foundField, err := a.findFieldRecursive(ctx, embeddedTypeInfo, fieldName, visited)
+				foundField, owner, err := a.findFieldRecursive(ctx, embeddedTypeInfo, fieldName, visited)
 				if err != nil {
 					if err == ErrUnresolvedEmbedded {
 						encounteredUnresolved = true // Propagate unresolved status from deeper calls.
 					} else {
-						return nil, err // Propagate other, unexpected errors.
+						return nil, nil, err // Propagate other, unexpected errors.
 					}
 				}
 				if foundField != nil {
-					return foundField, nil // Found it, we're done.
+					return foundField, owner, nil // Found it, we're done.
 				}
 			}
 		}
@@ -92,10 +108,10 @@ func (a *accessor) findFieldRecursive(ctx context.Context, typeInfo *scanner.Typ
 
 	// 3. If we finish the loop without finding the field, check if we hit an unresolved path.
 	if encounteredUnresolved {
-		return nil, ErrUnresolvedEmbedded
+		return nil, nil, ErrUnresolvedEmbedded
 	}
 
-	return nil, nil // Not found and no unresolved paths encountered.
+	return nil, nil, nil // Not found and no unresolved paths encountered.
 }
 
 // findMethodOnType recursively finds a method on a type or its embedded types.
@@ -115,23 +131,41 @@ func (a *accessor) findMethodRecursive(ctx context.Context, typeInfo *scanner.Ty
 		return nil, nil
 	}
 
-	// Create a unique key for the type to track visited nodes.
-	typeKey := fmt.Sprintf("%s.%s", typeInfo.PkgPath, typeInfo.Name)
-	if visited[typeKey] {
-		return nil, nil // Cycle detected
-	}
-	visited[typeKey] = true
-
-	// 1. Search for a direct method on the current type.
-	if method, err := a.findDirectMethodOnType(ctx, typeInfo, methodName, env, receiver, receiverPos); err != nil || method != nil {
-		return method, err
-	}
-
-	// 2. If not found, search in embedded structs.
+	// Follow Go's selector rules: embedded types are searched breadth-first, the
+	// method or field at the shallowest depth wins, and two of them at the same depth
+	// are ambiguous, including one type reached through two embedding paths.
 	var encounteredUnresolved bool
-	if typeInfo.Struct != nil {
-		for _, field := range typeInfo.Struct.Fields {
-			if field.Embedded {
+	level := newEmbeddedLevel(visited)
+	level.add(typeInfo, 1)
+	for len(level.types) > 0 {
+		var owner *scanner.TypeInfo
+		var matches int
+		var fieldMatched bool
+		next := newEmbeddedLevel(visited)
+		for _, ti := range level.types {
+			paths := level.paths[embeddedTypeKey(ti)]
+
+			// 1. Search for a direct method or field on the current type.
+			if methodInfo, err := a.findDirectMethodInfoOnType(ctx, ti, methodName); err == nil && methodInfo != nil {
+				owner = ti
+				matches += paths
+			}
+			if hasFieldNamed(ti, methodName) {
+				fieldMatched = true
+				matches += paths
+			}
+			if matches > 0 {
+				continue
+			}
+
+			// 2. If not found, queue the embedded types for the next depth.
+			if ti.Struct == nil {
+				continue
+			}
+			for _, field := range ti.Struct.Fields {
+				if !field.Embedded {
+					continue
+				}
 				// An embedded field is considered "unresolved" if its import path is missing
 				// (indicating incomplete type info from the scanner) or if it's explicitly
 				// outside the scan policy.
@@ -140,27 +174,24 @@ func (a *accessor) findMethodRecursive(ctx context.Context, typeInfo *scanner.Ty
 					encounteredUnresolved = true
 					continue // Don't stop; continue searching other embedded fields.
 				}
-
-				embeddedTypeInfo, _ := field.Type.Resolve(ctx)
-				if embeddedTypeInfo != nil {
-					// Recursive call, passing the original receiver.
-					foundFn, err := a.findMethodRecursive(ctx, embeddedTypeInfo, methodName, env, receiver, receiverPos, visited)
-					if err != nil {
-						if err == ErrUnresolvedEmbedded {
-							encounteredUnresolved = true // Propagate unresolved status from deeper calls.
-						} else {
-							return nil, err // Propagate other, unexpected errors.
-						}
-					}
-					if foundFn != nil {
-						return foundFn, nil // Found it.
-					}
+				if embeddedTypeInfo, _ := field.Type.Resolve(ctx); embeddedTypeInfo != nil {
+					next.add(embeddedTypeInfo, paths)
 				}
 			}
 		}
+
+		switch {
+		case matches > 1:
+			return nil, ErrAmbiguousEmbedded
+		case fieldMatched:
+			return nil, nil // The selector denotes a field, not a method.
+		case matches == 1:
+			return a.findDirectMethodOnType(ctx, owner, methodName, env, receiver, receiverPos)
+		}
+		level = next
 	}
 
-	// 3. If we finish the loop without finding the method, check if we hit an unresolved path.
+	// 3. If we finish the search without finding the method, check if we hit an unresolved path.
 	if encounteredUnresolved {
 		return nil, ErrUnresolvedEmbedded
 	}
@@ -168,6 +199,50 @@ func (a *accessor) findMethodRecursive(ctx context.Context, typeInfo *scanner.Ty
 	return nil, nil // Not found and no unresolved paths encountered.
 }
 
+// embeddedLevel is the set of types at one depth of a breadth-first selector search,
+// with the number of embedding paths reaching each of them.
+type embeddedLevel struct {
+	types   []*scanner.TypeInfo
+	paths   map[string]int
+	visited map[string]bool // the types of the shallower depths
+}
+
+func newEmbeddedLevel(visited map[string]bool) *embeddedLevel {
+	return &embeddedLevel{paths: make(map[string]int), visited: visited}
+}
+
+// add adds a type reached through the given number of paths. A type already found at
+// a shallower depth is skipped, which also stops the cycles.
+func (l *embeddedLevel) add(ti *scanner.TypeInfo, paths int) {
+	typeKey := embeddedTypeKey(ti)
+	if l.visited[typeKey] && l.paths[typeKey] == 0 {
+		return
+	}
+	if l.paths[typeKey] == 0 {
+		l.visited[typeKey] = true
+		l.types = append(l.types, ti)
+	}
+	l.paths[typeKey] += paths
+}
+
+func embeddedTypeKey(ti *scanner.TypeInfo) string {
+	return fmt.Sprintf("%s.%s", ti.PkgPath, ti.Name)
+}
+
+// hasFieldNamed reports whether the struct declares a field with the name, including
+// an embedded field named after its type.
+func hasFieldNamed(ti *scanner.TypeInfo, name string) bool {
+	if ti.Struct == nil {
+		return false
+	}
+	for _, field := range ti.Struct.Fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *accessor) findDirectMethodOnType(ctx context.Context, typeInfo *scanner.TypeInfo, methodName string, env *object.Environment, receiver object.Object, receiverPos token.Pos) (*object.Function, error) {
 	methodInfo, err := a.findDirectMethodInfoOnType(ctx, typeInfo, methodName)
 	if err != nil || methodInfo == nil {
@@ -218,29 +293,52 @@ func (a *accessor) findMethodInfoRecursive(ctx context.Context, typeInfo *scanne
 	if typeInfo == nil {
 		return nil
 	}
-	typeKey := fmt.Sprintf("%s.%s", typeInfo.PkgPath, typeInfo.Name)
-	if visited[typeKey] {
-		return nil // Cycle detected
-	}
-	visited[typeKey] = true
 
-	// 1. Search for a direct method on the current type.
-	if methodInfo, err := a.findDirectMethodInfoOnType(ctx, typeInfo, methodName); err == nil && methodInfo != nil {
-		return methodInfo
-	}
+	// The search is breadth-first, like findMethodRecursive. An ambiguous selector,
+	// or one denoting a field, yields nil.
+	level := newEmbeddedLevel(visited)
+	level.add(typeInfo, 1)
+	for len(level.types) > 0 {
+		var found *scanner.FunctionInfo
+		var matches int
+		var fieldMatched bool
+		next := newEmbeddedLevel(visited)
+		for _, ti := range level.types {
+			paths := level.paths[embeddedTypeKey(ti)]
+
+			// 1. Search for a direct method or field on the current type.
+			if methodInfo, err := a.findDirectMethodInfoOnType(ctx, ti, methodName); err == nil && methodInfo != nil {
+				found = methodInfo
+				matches += paths
+			}
+			if hasFieldNamed(ti, methodName) {
+				fieldMatched = true
+				matches += paths
+			}
+			if matches > 0 {
+				continue
+			}
 
-	// 2. If not found, search in embedded structs.
-	if typeInfo.Struct != nil {
-		for _, field := range typeInfo.Struct.Fields {
-			if field.Embedded {
-				embeddedTypeInfo, _ := field.Type.Resolve(ctx)
-				if embeddedTypeInfo != nil {
-					if foundMethod := a.findMethodInfoRecursive(ctx, embeddedTypeInfo, methodName, visited); foundMethod != nil {
-						return foundMethod
+			// 2. If not found, queue the embedded structs for the next depth.
+			if ti.Struct == nil {
+				continue
+			}
+			for _, field := range ti.Struct.Fields {
+				if field.Embedded {
+					if embeddedTypeInfo, _ := field.Type.Resolve(ctx); embeddedTypeInfo != nil {
+						next.add(embeddedTypeInfo, paths)
 					}
 				}
 			}
 		}
+
+		switch {
+		case matches > 1, fieldMatched:
+			return nil // Ambiguous selector, or a field
+		case matches == 1:
+			return found
+		}
+		level = next
 	}
 
 	return nil // Not found
@@ -81,3 +81,91 @@ func (s *S) GetName() string {
 		t.Fatalf("scantest.Run failed: %+v", err)
 	}
 }
+
+func TestAccessor_EmbeddedSelectors(t *testing.T) {
+	source := `
+package main
+
+type Shared struct{}
+
+func (Shared) Twice() {}
+
+type ViaLeft struct{ Shared }
+type ViaRight struct{ *Shared }
+
+type Diamond struct {
+	ViaLeft
+	ViaRight
+}
+
+type Named struct{ Name string }
+
+type Namer struct{}
+
+func (Namer) Name() string { return "" }
+
+type Clash struct {
+	Named
+	Namer
+}
+
+type Labeled struct {
+	Name func() string
+	Namer
+}
+
+type Direct struct {
+	Shared
+	ViaLeft
+}
+`
+	files := map[string]string{
+		"go.mod":  "module my-test",
+		"main.go": source,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	action := func(ctx context.Context, s *goscan.Scanner, pkgs []*goscan.Package) error {
+		pkg := pkgs[0]
+		eval := New(s, nil, nil, func(pkgpath string) bool { return true })
+		receiver := &object.SymbolicPlaceholder{Reason: "test receiver"}
+
+		cases := []struct {
+			typeName   string
+			methodName string
+			wantFound  bool
+			wantErr    error
+		}{
+			{typeName: "Diamond", methodName: "Twice", wantErr: ErrAmbiguousEmbedded}, // two paths to Shared
+			{typeName: "Clash", methodName: "Name", wantErr: ErrAmbiguousEmbedded},    // a field and a method at depth 1
+			{typeName: "Labeled", methodName: "Name"},                                 // the field shadows the method
+			{typeName: "Direct", methodName: "Twice", wantFound: true},                // depth 1 wins over depth 2
+		}
+		for _, c := range cases {
+			t.Run(c.typeName, func(t *testing.T) {
+				ti := pkg.Lookup(c.typeName)
+				if ti == nil {
+					t.Fatalf("type %s not found", c.typeName)
+				}
+
+				method, err := eval.accessor.findMethodOnType(ctx, ti, c.methodName, eval.UniverseEnv, receiver, 0)
+				if err != c.wantErr {
+					t.Errorf("findMethodOnType() error = %v, want %v", err, c.wantErr)
+				}
+				if got := method != nil; got != c.wantFound {
+					t.Errorf("findMethodOnType() found = %v, want %v", got, c.wantFound)
+				}
+				if got := eval.accessor.findMethodInfoOnType(ctx, ti, c.methodName) != nil; got != c.wantFound {
+					t.Errorf("findMethodInfoOnType() found = %v, want %v", got, c.wantFound)
+				}
+			})
+		}
+		return nil
+	}
+
+	_, err := scantest.Run(t, t.Context(), dir, []string{"."}, action)
+	if err != nil {
+		t.Fatalf("scantest.Run failed: %+v", err)
+	}
+}
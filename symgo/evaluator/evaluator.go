@@ -7,6 +7,7 @@ import (
 	"go/token"
 	"log/slog"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 
@@ -32,6 +33,7 @@ type Evaluator struct {
 	logger           *slog.Logger
 	tracer           object.Tracer // Tracer for debugging evaluation flow.
 	callStack        []*object.CallFrame
+	depth            int // the nesting of the calls of Eval and Apply, see evaluator_recover_panic.go
 	resolver         *Resolver
 	defaultIntrinsic intrinsics.IntrinsicFunc
 	initializedPkgs  map[string]bool // To track packages whose constants are loaded
@@ -75,6 +77,35 @@ type Evaluator struct {
 	// memoization
 	memoize          bool
 	memoizationCache map[token.Pos]object.Object
+
+	// memory budget and pruning, see evaluator_memory.go
+	memoryBudget  uint64
+	rootEnvs      []*object.Environment
+	applyCount    int
+	prunes        int
+	prunedEntries int
+
+	// returnSummaries holds the concrete types returned by the functions for their
+	// interface-typed results, keyed by declaration position, see evaluator_returned_types.go
+	returnSummaries map[token.Pos]returnSummary
+
+	// recursive cycles, see evaluator_recursion.go
+	recursionWidening  bool
+	widening           map[recursionKey]bool
+	recursionSummaries map[recursionKey]object.Object
+
+	// reflection, see evaluator_reflect.go
+	reflectAllMethods bool
+
+	// journal tracks the writes to the environments for snapshots, see evaluator_snapshot.go
+	journal *object.Journal
+
+	// the call sites whose results are untyped placeholders, see evaluator_unresolved_calls.go
+	unresolvedCalls      map[token.Pos]*UnresolvedCall
+	unresolvedCallReport []UnresolvedCall
+
+	// the statements and branches explored by function, see evaluator_coverage.go
+	coverage map[token.Pos]*functionCoverage
 }
 
 // contextKey is a private type to avoid collisions with other packages' context keys.
@@ -83,6 +114,10 @@ type contextKey string
 const (
 	// callFrameKey is the context key for the current call frame.
 	callFrameKey contextKey = "callFrame"
+	// callPosKey is the context key for the position of the call passed to the default intrinsic.
+	callPosKey contextKey = "callPos"
+	// labelKey is the context key for the labeled statement being evaluated.
+	labelKey contextKey = "label"
 )
 
 // FrameFromContext returns the call frame from the context, if one exists.
@@ -91,6 +126,13 @@ func FrameFromContext(ctx context.Context) (*object.CallFrame, bool) {
 	return frame, ok
 }
 
+// CallPosFromContext returns the position of the call expression, if the context is the one
+// passed to the default intrinsic for a call.
+func CallPosFromContext(ctx context.Context) (token.Pos, bool) {
+	pos, ok := ctx.Value(callPosKey).(token.Pos)
+	return pos, ok
+}
+
 // Option configures the evaluator.
 type Option func(*Evaluator)
 
@@ -141,13 +183,21 @@ func New(scanner *goscan.Scanner, logger *slog.Logger, tracer object.Tracer, sca
 		syntheticMethods:       make(map[string]map[string]*scan.MethodInfo),
 		memoize:                false,
 		memoizationCache:       nil,
+		recursionWidening:      true,
 	}
 	e.accessor = newAccessor(e)
+	e.registerReflectIntrinsics()
 
 	for _, opt := range opts {
 		opt(e)
 	}
 
+	e.journal = object.NewJournal()
+	e.UniverseEnv.SetJournal(e.journal)
+	for _, env := range e.rootEnvs {
+		env.SetJournal(e.journal)
+	}
+
 	return e
 }
 
@@ -166,6 +216,16 @@ func (e *Evaluator) RegisterDefaultIntrinsic(fn intrinsics.IntrinsicFunc) {
 	e.defaultIntrinsic = fn
 }
 
+// evalCallStmt evaluates the call of a `defer` or `go` statement in place. The result is
+// discarded, so that the return value of the called function is not mistaken for a `return`
+// statement of the current block.
+func (e *Evaluator) evalCallStmt(ctx context.Context, call *ast.CallExpr, env *object.Environment, pkg *scan.PackageInfo) object.Object {
+	if result := e.Eval(ctx, call, env, pkg); isError(result) {
+		return result
+	}
+	return nil
+}
+
 // PushIntrinsics creates a new temporary scope for intrinsics.
 func (e *Evaluator) PushIntrinsics() {
 	e.intrinsics.Push()
@@ -177,7 +237,10 @@ func (e *Evaluator) PopIntrinsics() {
 }
 
 // Eval is the main dispatch loop for the evaluator.
-func (e *Evaluator) Eval(ctx context.Context, node ast.Node, env *object.Environment, pkg *scan.PackageInfo) object.Object {
+func (e *Evaluator) Eval(ctx context.Context, node ast.Node, env *object.Environment, pkg *scan.PackageInfo) (result object.Object) {
+	e.depth++
+	defer e.recoverPanic(ctx, node, pkg, &result)
+
 	if e.maxSteps > 0 {
 		e.step++
 		if e.step > e.maxSteps {
@@ -195,8 +258,12 @@ func (e *Evaluator) Eval(ctx context.Context, node ast.Node, env *object.Environ
 		}
 	}
 
+	if e.coverage != nil {
+		e.recordStatement(node)
+	}
 	if e.tracer != nil {
 		e.tracer.Trace(object.TraceEvent{
+			Kind: object.TraceNode,
 			Step: e.step,
 			Node: node,
 			Pkg:  pkg,
@@ -275,9 +342,9 @@ func (e *Evaluator) Eval(ctx context.Context, node ast.Node, env *object.Environ
 		}
 		return result
 	case *ast.DeferStmt:
-		return e.Eval(ctx, n.Call, env, pkg)
+		return e.evalCallStmt(ctx, n.Call, env, pkg)
 	case *ast.GoStmt:
-		return e.Eval(ctx, n.Call, env, pkg)
+		return e.evalCallStmt(ctx, n.Call, env, pkg)
 	case *ast.DeclStmt:
 		return e.Eval(ctx, n.Decl, env, pkg)
 	case *ast.GenDecl:
@@ -327,6 +394,7 @@ func (e *Evaluator) Eval(ctx context.Context, node ast.Node, env *object.Environ
 		importLookup := e.scanner.BuildImportLookup(astFile)
 
 		fieldType := e.scanner.TypeInfoFromExpr(ctx, n, nil, pkg, importLookup)
+		e.bindLocalTypes(fieldType, env)
 		resolvedType := e.resolver.ResolveType(ctx, fieldType)
 
 		placeholder := &object.SymbolicPlaceholder{Reason: "array type expression"}
@@ -348,6 +416,7 @@ func (e *Evaluator) Eval(ctx context.Context, node ast.Node, env *object.Environ
 		importLookup := e.scanner.BuildImportLookup(astFile)
 
 		fieldType := e.scanner.TypeInfoFromExpr(ctx, n, nil, pkg, importLookup)
+		e.bindLocalTypes(fieldType, env)
 		resolvedType := e.resolver.ResolveType(ctx, fieldType)
 
 		placeholder := &object.SymbolicPlaceholder{Reason: "map type expression"}
@@ -369,6 +438,7 @@ func (e *Evaluator) Eval(ctx context.Context, node ast.Node, env *object.Environ
 		importLookup := e.scanner.BuildImportLookup(astFile)
 
 		fieldType := e.scanner.TypeInfoFromExpr(ctx, n, nil, pkg, importLookup)
+		e.bindLocalTypes(fieldType, env)
 		placeholder := &object.SymbolicPlaceholder{Reason: "channel type expression"}
 		placeholder.SetFieldType(fieldType)
 		return placeholder
@@ -418,7 +488,9 @@ func isCallable(obj object.Object) bool {
 	}
 }
 
-func (e *Evaluator) Apply(ctx context.Context, fn object.Object, args []object.Object, pkg *scan.PackageInfo) object.Object {
+func (e *Evaluator) Apply(ctx context.Context, fn object.Object, args []object.Object, pkg *scan.PackageInfo) (result object.Object) {
+	e.depth++
+	defer e.recoverPanic(ctx, nil, pkg, &result)
 	return e.applyFunction(ctx, fn, args, pkg, token.NoPos)
 }
 
@@ -438,9 +510,9 @@ func (e *Evaluator) Files() []*FileScope {
 	return e.files
 }
 
-// CallStack returns the current call stack.
+// CallStack returns a copy of the current call stack, which stays intact as the evaluation goes on.
 func (e *Evaluator) CallStack() []*object.CallFrame {
-	return e.callStack
+	return append([]*object.CallFrame(nil), e.callStack...)
 }
 
 // ApplyFunction is a public wrapper for the internal applyFunction, allowing it to be called from other packages.
@@ -453,17 +525,16 @@ func (e *Evaluator) ApplyFunction(ctx context.Context, call *ast.CallExpr, fn ob
 }
 
 // Finalize performs the final analysis step, connecting interface method calls
-// to their concrete implementations. This should be called after all initial
-// symbolic execution is complete.
+// to their concrete implementations, and computing the report of UnresolvedCalls.
+// This should be called after all initial symbolic execution is complete.
 func (e *Evaluator) Finalize(ctx context.Context) {
+	e.buildUnresolvedCallReport(ctx)
+
 	if e.defaultIntrinsic == nil {
 		e.logger.DebugContext(ctx, "skipping finalize: no default intrinsic registered")
 		return // Nothing to do if no intrinsic is registered to receive the results.
 	}
 
-	allStructs := make(map[string]*scan.TypeInfo)
-	allInterfaces := make(map[string]*scan.TypeInfo)
-
 	// 1. Collect all packages from the scanner's cache, respecting the scan policy.
 	// This replaces the old `e.seenPackages` mechanism.
 	allPackagesFromScanner := e.scanner.AllSeenPackages()
@@ -479,38 +550,9 @@ func (e *Evaluator) Finalize(ctx context.Context) {
 		return
 	}
 
-	// 2. Collect all struct and interface types from the policy-filtered packages.
-	e.logger.DebugContext(ctx, "finalize: starting type collection", "package_count", len(e.seenPackages))
-	for _, pkg := range e.seenPackages {
-		if pkg == nil {
-			continue
-		}
-		for _, t := range pkg.Types {
-			fullName := fmt.Sprintf("%s.%s", t.PkgPath, t.Name)
-			if t.Struct != nil {
-				allStructs[fullName] = t
-			} else if t.Interface != nil {
-				allInterfaces[fullName] = t
-			}
-		}
-	}
-	e.logger.DebugContext(ctx, "finalize: finished type collection", "struct_count", len(allStructs), "interface_count", len(allInterfaces))
-
-	// 3. Build the implementation map.
-	interfaceImplementers := make(map[string]map[string]struct{}) // key: interface name, value: set of implementer names
-	for ifaceName, ifaceType := range allInterfaces {
-		for structName, structType := range allStructs {
-			if e.scanner.Implements(ctx, structType, ifaceType) {
-				if _, ok := interfaceImplementers[ifaceName]; !ok {
-					interfaceImplementers[ifaceName] = make(map[string]struct{})
-				}
-				interfaceImplementers[ifaceName][structName] = struct{}{}
-			}
-		}
-	}
-	e.logger.DebugContext(ctx, "finalize: implementation map", "map", fmt.Sprintf("%#v", interfaceImplementers))
-
-	// 4. Process called interface methods.
+	// 2. Process called interface methods. The implementers of each interface are looked up
+	// on demand in the index of the scanner, see goscan.Scanner.ImplementersOf, and only those
+	// of the policy-filtered packages are marked.
 	e.logger.DebugContext(ctx, "finalize: processing called interface methods", "count", len(e.calledInterfaceMethods))
 	for calledMethodKey := range e.calledInterfaceMethods {
 		parts := strings.Split(calledMethodKey, ".")
@@ -521,17 +563,21 @@ func (e *Evaluator) Finalize(ctx context.Context) {
 		ifaceName := strings.Join(parts[:len(parts)-1], ".")
 		e.logger.DebugContext(ctx, "finalize: processing key", "key", calledMethodKey, "iface", ifaceName, "method", methodName)
 
-		implementers := interfaceImplementers[ifaceName]
+		iface := e.seenInterface(ifaceName)
+		if iface == nil {
+			continue
+		}
+		implementers := e.scanner.ImplementersOf(ctx, iface)
 		if len(implementers) == 0 {
 			e.logger.DebugContext(ctx, "finalize: no implementers found for interface", "interface", ifaceName)
 			continue
 		}
 
-		for structName := range implementers {
-			structType := allStructs[structName]
-			if structType == nil {
+		for _, structType := range implementers {
+			if _, ok := e.seenPackages[structType.PkgPath]; !ok {
 				continue
 			}
+			structName := structType.PkgPath + "." + structType.Name
 
 			// Find the concrete method on the struct.
 			concreteMethodInfo := e.accessor.findMethodInfoOnType(ctx, structType, methodName)
@@ -560,6 +606,35 @@ func (e *Evaluator) Finalize(ctx context.Context) {
 	}
 }
 
+// seenInterface returns the interface type of the policy-filtered packages with the qualified
+// name, e.g. "example.com/m/pkg.Reader", or nil.
+func (e *Evaluator) seenInterface(name string) *scan.TypeInfo {
+	i := strings.LastIndex(name, ".")
+	if i == -1 {
+		return nil
+	}
+	pkg, ok := e.seenPackages[name[:i]]
+	if !ok || pkg == nil {
+		return nil
+	}
+	t := pkg.Lookup(name[i+1:])
+	if t == nil || t.Interface == nil {
+		return nil
+	}
+	return t
+}
+
+// CalledInterfaceMethods returns the interface methods called during the evaluation,
+// as sorted "<pkg>.<Interface>.<Method>" keys.
+func (e *Evaluator) CalledInterfaceMethods() []string {
+	keys := make([]string, 0, len(e.calledInterfaceMethods))
+	for key := range e.calledInterfaceMethods {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // for test
 
 // CalledInterfaceMethodsForTest returns the map of called interface methods for testing.
@@ -51,6 +51,7 @@ func (e *Evaluator) applyFunction(ctx context.Context, fn object.Object, args []
 		}
 	}
 
+	e.checkMemoryBudget(ctx)
 	return result
 }
 
@@ -74,64 +75,30 @@ func (e *Evaluator) applyFunctionImpl(ctx context.Context, fn object.Object, arg
 		return &object.SymbolicPlaceholder{Reason: "max call stack depth exceeded"}
 	}
 
-	// New recursion check based on function definition (for named functions)
-	// or function literal position (for anonymous functions).
-	if f, ok := fn.(*object.Function); ok {
-		// Determine which call stack to use for recursion detection.
-		// If the function has a BoundCallStack, it means it was passed as an argument,
-		// and that stack represents the true logical path leading to this call.
-		stackToScan := e.callStack
-		if f.BoundCallStack != nil {
-			stackToScan = f.BoundCallStack
-		}
-
-		recursionCount := 0
-		for _, frame := range stackToScan {
-			if frame.Fn == nil {
-				continue
-			}
-
-			// Case 1: Named function with a definition. Compare declaration positions.
-			if f.Def != nil && f.Def.AstDecl != nil && frame.Fn.Def != nil && frame.Fn.Def.AstDecl != nil {
-				if f.Def.AstDecl.Pos() == frame.Fn.Def.AstDecl.Pos() {
-					recursionCount++
-				}
-				continue
-			}
-
-			// Case 2: Anonymous function (function literal). Compare literal positions.
-			if f.Lit != nil && frame.Fn.Lit != nil {
-				if f.Lit.Pos() == frame.Fn.Lit.Pos() {
-					recursionCount++
-				}
-			}
-		}
-
-		// Allow one level of recursion, but stop at the second call.
-		if recursionCount > 0 { // Changed from > 1 to > 0 to be more strict.
-			e.logc(ctx, slog.LevelDebug, "bounded recursion depth exceeded, halting analysis for this path", "function", name)
-			// Return a symbolic placeholder that matches the function's return signature.
-			if f.Def != nil && f.Def.AstDecl.Type.Results != nil {
-				numResults := len(f.Def.AstDecl.Type.Results.List)
-				if numResults > 1 {
-					results := make([]object.Object, numResults)
-					for i := 0; i < numResults; i++ {
-						results[i] = &object.SymbolicPlaceholder{Reason: "bounded recursion halt"}
-					}
-					return &object.MultiReturn{Values: results}
-				}
-			}
-			// Default to a single placeholder if signature is not available or has <= 1 return values.
-			return &object.SymbolicPlaceholder{Reason: "bounded recursion halt"}
+	// Recursion check based on function definition (for named functions)
+	// or function literal position (for anonymous functions), see evaluator_recursion.go.
+	if f, ok := fn.(*object.Function); ok && e.isRecursiveCall(f) {
+		result, widened, finish, done := e.applyRecursiveCall(ctx, f, name, args)
+		if done {
+			return result
 		}
+		result = e.applyFunctionBody(ctx, fn, name, widened, pkg, callPos)
+		finish(result)
+		return result
 	}
+	return e.applyFunctionBody(ctx, fn, name, args, pkg, callPos)
+}
 
+// applyFunctionBody pushes the call frame and evaluates the function.
+func (e *Evaluator) applyFunctionBody(ctx context.Context, fn object.Object, name string, args []object.Object, pkg *scan.PackageInfo, callPos token.Pos) object.Object {
 	frame := &object.CallFrame{Function: name, Pos: callPos, Args: args}
 	if f, ok := fn.(*object.Function); ok {
 		frame.Fn = f
 		if f.Receiver != nil {
 			frame.ReceiverPos = f.ReceiverPos
 		}
+	} else if inst, ok := fn.(*object.InstantiatedFunction); ok {
+		frame.Fn = inst.Function // The generic function, e.g. for bare returns of its named results.
 	}
 	e.callStack = append(e.callStack, frame)
 	defer func() {
@@ -201,7 +168,9 @@ func (e *Evaluator) applyFunctionImpl(ctx context.Context, fn object.Object, arg
 		}
 
 		// Evaluate the function body within the fully prepared environment.
+		e.traceEvent(object.TraceEnterFunction, funcPos(fn.Function), fn.Function.Package, fn, nil)
 		evaluated := e.Eval(ctx, fn.Function.Body, finalEnv, fn.Function.Package)
+		e.recordAborted(fn.Function, evaluated)
 
 		if ret, ok := evaluated.(*object.ReturnValue); ok {
 			return ret.Value
@@ -261,7 +230,9 @@ func (e *Evaluator) applyFunctionImpl(ctx context.Context, fn object.Object, arg
 			}
 		}
 
+		e.traceEvent(object.TraceEnterFunction, funcPos(fn), fn.Package, fn, nil)
 		evaluated := e.Eval(ctx, fn.Body, extendedEnv, fn.Package)
+		e.recordAborted(fn, evaluated)
 		if evaluated != nil {
 			if isError(evaluated) || evaluated.Type() == object.PANIC_OBJ {
 				return evaluated
@@ -278,7 +249,7 @@ func (e *Evaluator) applyFunctionImpl(ctx context.Context, fn object.Object, arg
 			return &object.ReturnValue{Value: object.NIL}
 		}
 
-		return &object.ReturnValue{Value: evaluatedValue}
+		return &object.ReturnValue{Value: e.bindReturnedTypes(ctx, fn, evaluatedValue)}
 
 	case *object.Intrinsic:
 		return fn.Fn(ctx, args...)
@@ -286,6 +257,11 @@ func (e *Evaluator) applyFunctionImpl(ctx context.Context, fn object.Object, arg
 	case *object.SymbolicPlaceholder:
 		// This now handles both external function calls and interface method calls.
 		if fn.UnderlyingFunc != nil {
+			// The receiver of an interface method call may be known to hold some concrete types.
+			if len(fn.PossibleConcreteTypes) > 0 {
+				e.applyToPossibleTypes(ctx, fn, args, pkg, callPos)
+			}
+
 			// If it has an AST declaration, it's a real function from source.
 			if fn.UnderlyingFunc.AstDecl != nil {
 				return e.createSymbolicResultForFuncInfo(ctx, fn.UnderlyingFunc, fn.Package, "result of external call to %s", fn.UnderlyingFunc.Name)
@@ -293,45 +269,7 @@ func (e *Evaluator) applyFunctionImpl(ctx context.Context, fn object.Object, arg
 
 			// Otherwise, it's a constructed FunctionInfo for an interface method.
 			// We create the result based on the Parameters/Results fields directly.
-			method := fn.UnderlyingFunc
-			var result object.Object
-			if len(method.Results) <= 1 {
-				var resultTypeInfo *scan.TypeInfo
-				var resultFieldType *scan.FieldType
-				if len(method.Results) == 1 {
-					resultFieldType = method.Results[0].Type
-					if resultFieldType != nil {
-						resultType := e.resolver.ResolveType(ctx, resultFieldType)
-						if resultType == nil && resultFieldType.IsBuiltin {
-							resultType = &scan.TypeInfo{Name: resultFieldType.Name}
-						}
-						resultTypeInfo = resultType
-					}
-				}
-				result = &object.SymbolicPlaceholder{
-					Reason:     fmt.Sprintf("result of interface method call %s", method.Name),
-					BaseObject: object.BaseObject{ResolvedTypeInfo: resultTypeInfo, ResolvedFieldType: resultFieldType},
-				}
-			} else {
-				// Multiple return values from interface method
-				results := make([]object.Object, len(method.Results))
-				for i, resFieldInfo := range method.Results {
-					resultFieldType := resFieldInfo.Type
-					var resultType *scan.TypeInfo
-					if resultFieldType != nil {
-						resultType = e.resolver.ResolveType(ctx, resultFieldType)
-						if resultType == nil && resultFieldType.IsBuiltin && resultFieldType.Name == "error" {
-							resultType = ErrorInterfaceTypeInfo
-						}
-					}
-					results[i] = &object.SymbolicPlaceholder{
-						Reason:     fmt.Sprintf("result %d of interface method call %s", i, method.Name),
-						BaseObject: object.BaseObject{ResolvedTypeInfo: resultType, ResolvedFieldType: resultFieldType},
-					}
-				}
-				result = &object.MultiReturn{Values: results}
-			}
-			return &object.ReturnValue{Value: result}
+			return e.createSymbolicResultForFields(ctx, fn.UnderlyingFunc.Results, fmt.Sprintf("result of interface method call %s", fn.UnderlyingFunc.Name))
 		}
 
 		// Case 3: A placeholder representing a callable variable (like flag.Usage)
@@ -431,12 +369,7 @@ func (e *Evaluator) applyFunctionImpl(ctx context.Context, fn object.Object, arg
 		if len(args) != 1 {
 			return e.newError(ctx, callPos, "wrong number of arguments for type conversion: got=%d, want=1", len(args))
 		}
-		// The result is a symbolic value of the target type.
-		placeholder := &object.SymbolicPlaceholder{
-			Reason: fmt.Sprintf("result of conversion to %s", fn.TypeName),
-		}
-		placeholder.SetTypeInfo(fn.ResolvedType)
-		return &object.ReturnValue{Value: placeholder}
+		return &object.ReturnValue{Value: e.convert(ctx, fn, args[0])}
 
 	default:
 		return e.newError(ctx, callPos, "not a function: %s", fn.Type())
@@ -446,10 +379,15 @@ func (e *Evaluator) applyFunctionImpl(ctx context.Context, fn object.Object, arg
 // createSymbolicResultForFuncInfo creates a symbolic result for a function call based on its FunctionInfo.
 // This is used for functions that are not deeply executed (e.g., due to scan policy or being unresolved).
 func (e *Evaluator) createSymbolicResultForFuncInfo(ctx context.Context, funcInfo *scan.FunctionInfo, pkgInfo *scan.PackageInfo, reasonFormat string, reasonArgs ...any) object.Object {
+	reason := fmt.Sprintf(reasonFormat, reasonArgs...)
 	if funcInfo.AstDecl == nil || funcInfo.AstDecl.Type == nil || pkgInfo == nil {
+		// Without the AST, fall back to the declared result types, if any.
+		// This keeps chained calls on the result (e.g. `f().Get(id).Name()`) typed.
+		if len(funcInfo.Results) > 0 {
+			return e.createSymbolicResultForFields(ctx, funcInfo.Results, reason)
+		}
 		return &object.SymbolicPlaceholder{Reason: "result of call with incomplete info"}
 	}
-	reason := fmt.Sprintf(reasonFormat, reasonArgs...)
 
 	results := funcInfo.AstDecl.Type.Results
 	if results == nil || len(results.List) == 0 {
@@ -504,6 +442,51 @@ func (e *Evaluator) createSymbolicResultForFuncInfo(ctx context.Context, funcInf
 	return &object.ReturnValue{Value: &object.MultiReturn{Values: returnValues}}
 }
 
+// createSymbolicResultForFields creates a symbolic result from the declared result fields of a signature.
+// It is used when there is no AST to re-resolve the result types from, e.g. for interface methods.
+func (e *Evaluator) createSymbolicResultForFields(ctx context.Context, fields []*scan.FieldInfo, reason string) object.Object {
+	if len(fields) <= 1 {
+		var resultTypeInfo *scan.TypeInfo
+		var resultFieldType *scan.FieldType
+		if len(fields) == 1 {
+			resultFieldType = fields[0].Type
+			if resultFieldType != nil {
+				resultType := e.resolver.ResolveType(ctx, resultFieldType)
+				if resultType == nil && resultFieldType.IsBuiltin {
+					if resultFieldType.Name == "error" {
+						resultType = ErrorInterfaceTypeInfo
+					} else {
+						resultType = &scan.TypeInfo{Name: resultFieldType.Name}
+					}
+				}
+				resultTypeInfo = resultType
+			}
+		}
+		return &object.ReturnValue{Value: &object.SymbolicPlaceholder{
+			Reason:     reason,
+			BaseObject: object.BaseObject{ResolvedTypeInfo: resultTypeInfo, ResolvedFieldType: resultFieldType},
+		}}
+	}
+
+	// Multiple return values
+	results := make([]object.Object, len(fields))
+	for i, field := range fields {
+		resultFieldType := field.Type
+		var resultType *scan.TypeInfo
+		if resultFieldType != nil {
+			resultType = e.resolver.ResolveType(ctx, resultFieldType)
+			if resultType == nil && resultFieldType.IsBuiltin && resultFieldType.Name == "error" {
+				resultType = ErrorInterfaceTypeInfo
+			}
+		}
+		results[i] = &object.SymbolicPlaceholder{
+			Reason:     fmt.Sprintf("%s (result %d)", reason, i),
+			BaseObject: object.BaseObject{ResolvedTypeInfo: resultType, ResolvedFieldType: resultFieldType},
+		}
+	}
+	return &object.ReturnValue{Value: &object.MultiReturn{Values: results}}
+}
+
 // createSymbolicResultForFunc creates a symbolic result for a function call
 // that is not being deeply executed due to scan policy.
 func (e *Evaluator) createSymbolicResultForFunc(ctx context.Context, fn *object.Function) object.Object {
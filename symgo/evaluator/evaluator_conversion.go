@@ -0,0 +1,310 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"math"
+	"unicode/utf8"
+
+	scan "github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// maxConvertedStringElements is the length of the strings above which `[]byte(s)` and
+// `[]rune(s)` result in slices of unknown elements.
+const maxConvertedStringElements = 1024
+
+// evalConversionType returns the type of a conversion whose type is a literal, e.g. `(*T)(p)`,
+// `[]byte(s)` or `func()(f)`, and nil if the called expression is not such a type. The types
+// denoted by names (`T(x)`) are evaluated to *object.Type by Eval.
+func (e *Evaluator) evalConversionType(ctx context.Context, fun ast.Expr, env *object.Environment, pkg *scan.PackageInfo) *object.Type {
+	for {
+		paren, ok := fun.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		fun = paren.X
+	}
+	switch t := fun.(type) {
+	case *ast.StarExpr:
+		// `(*T)(p)` is a conversion only if T is a type, `(*f)(x)` being a call of the function f points to.
+		switch e.Eval(ctx, t.X, env, pkg).(type) {
+		case *object.Type, *object.UnresolvedType:
+		default:
+			return nil
+		}
+	case *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.InterfaceType:
+	default:
+		return nil
+	}
+	if pkg == nil || pkg.Fset == nil {
+		return nil
+	}
+	file := pkg.Fset.File(fun.Pos())
+	if file == nil {
+		return nil
+	}
+	astFile, ok := pkg.AstFiles[file.Name()]
+	if !ok {
+		return nil
+	}
+	fieldType := e.scanner.TypeInfoFromExpr(ctx, fun, nil, pkg, e.scanner.BuildImportLookup(astFile))
+	e.bindLocalTypes(fieldType, env)
+	typ := &object.Type{TypeName: fieldType.String(), ResolvedType: e.resolver.ResolveType(ctx, fieldType)}
+	typ.SetFieldType(fieldType)
+	return typ
+}
+
+// convert evaluates the conversion of arg to the type. The value is kept where it is meaningful:
+// the constants are converted (e.g. `float64(2)`, `int8(x)` wrapping around, `string(r)`),
+// `[]byte(s)` and `string(b)` convert between strings and slices of known elements, and a value
+// converted to a named type, or a pointer converted to a pointer to a named type, keeps its
+// fields and elements, with the named type for its methods. Otherwise the result is a symbolic
+// value of the type.
+func (e *Evaluator) convert(ctx context.Context, typ *object.Type, arg object.Object) object.Object {
+	if ret, ok := arg.(*object.ReturnValue); ok {
+		arg = ret.Value
+	}
+	arg = unwrapVariable(arg)
+	ft := typ.FieldType()
+
+	if ft != nil && ft.IsPointer && ft.Elem != nil {
+		if ptr, ok := arg.(*object.Pointer); ok {
+			elem := &object.Type{TypeName: ft.Elem.String(), ResolvedType: e.resolver.ResolveType(ctx, ft.Elem)}
+			elem.SetFieldType(ft.Elem)
+			// The pointee keeps sharing its fields with the original one.
+			converted := &object.Pointer{Value: e.convert(ctx, elem, ptr.Pointee())}
+			converted.SetFieldType(ft)
+			converted.SetTypeInfo(typ.ResolvedType)
+			return converted
+		}
+	} else if converted := e.convertValue(ctx, typ, arg); converted != nil {
+		return converted
+	}
+
+	placeholder := &object.SymbolicPlaceholder{
+		Reason: fmt.Sprintf("result of conversion to %s", typ.TypeName),
+	}
+	placeholder.SetTypeInfo(typ.ResolvedType)
+	if ft != nil {
+		placeholder.SetFieldType(ft)
+	}
+	return placeholder
+}
+
+// convertValue converts a value which is not a pointer, returning nil if its value is not known.
+func (e *Evaluator) convertValue(ctx context.Context, typ *object.Type, arg object.Object) object.Object {
+	ti := typ.ResolvedType
+	named := ti != nil && ti.PkgPath != ""
+	withType := func(obj object.Object) object.Object {
+		if named {
+			obj.SetTypeInfo(ti)
+			if inst, ok := obj.(*object.Instance); ok {
+				inst.TypeName = ti.PkgPath + "." + ti.Name
+			}
+		}
+		if ft := typ.FieldType(); ft != nil {
+			obj.SetFieldType(ft)
+		}
+		return obj
+	}
+
+	if basic := e.basicTypeName(ctx, ti); basic != "" {
+		if converted := convertBasic(arg, basic); converted != nil {
+			return withType(converted)
+		}
+		return nil
+	}
+	if elem := e.sliceElemTypeName(ctx, typ); elem != "" {
+		if s, ok := arg.(*object.String); ok {
+			return withType(stringToSlice(s.Value, elem, typ))
+		}
+	}
+	if ti == nil {
+		return nil
+	}
+	switch arg.(type) {
+	case *object.Instance, *object.Function, *object.Slice, *object.Map:
+		// The instance shares its fields with the original one.
+		return withType(arg.Clone())
+	}
+	return nil
+}
+
+// basicTypeName returns the name of the predeclared type of the type, or of the underlying type
+// of a named one (e.g. "float64" for `type Celsius float64`), and "" for the other types.
+func (e *Evaluator) basicTypeName(ctx context.Context, ti *scan.TypeInfo) string {
+	for depth := 0; ti != nil && depth < 8; depth++ {
+		if ti.PkgPath == "" {
+			if basicTypeNames[ti.Name] {
+				return ti.Name
+			}
+			return ""
+		}
+		u := ti.Underlying
+		if ti.Kind != scan.AliasKind || u == nil || u.IsPointer || u.IsSlice || u.IsMap || u.IsChan {
+			return ""
+		}
+		if u.IsBuiltin {
+			if basicTypeNames[u.Name] {
+				return u.Name
+			}
+			return ""
+		}
+		ti = e.resolver.ResolveType(ctx, u)
+	}
+	return ""
+}
+
+// sliceElemTypeName returns the name of the predeclared type of the elements of the type, if it
+// is a slice, e.g. "byte" for `[]byte` and for `type Raw []byte`.
+func (e *Evaluator) sliceElemTypeName(ctx context.Context, typ *object.Type) string {
+	ft := typ.FieldType()
+	if ft == nil && typ.ResolvedType != nil && typ.ResolvedType.Kind == scan.AliasKind {
+		ft = typ.ResolvedType.Underlying
+	}
+	if ft == nil || !ft.IsSlice || ft.Elem == nil || !ft.Elem.IsBuiltin {
+		return ""
+	}
+	return ft.Elem.Name
+}
+
+var basicTypeNames = map[string]bool{
+	"bool": true, "string": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true, "rune": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true, "byte": true,
+	"float32": true, "float64": true,
+}
+
+// convertBasic converts a constant to a predeclared type, returning nil if arg is not a constant
+// of a type convertible to it.
+func convertBasic(arg object.Object, basic string) object.Object {
+	switch basic {
+	case "bool":
+		if b, ok := arg.(*object.Boolean); ok {
+			return b.Clone()
+		}
+	case "string":
+		switch v := arg.(type) {
+		case *object.String:
+			return &object.String{Value: v.Value}
+		case *object.Integer:
+			return &object.String{Value: string(rune(v.Value))}
+		case *object.UnsignedInteger:
+			return &object.String{Value: string(rune(v.Value))}
+		case *object.Slice:
+			return sliceToString(v)
+		}
+	case "float32", "float64":
+		var f float64
+		switch v := arg.(type) {
+		case *object.Integer:
+			f = float64(v.Value)
+		case *object.UnsignedInteger:
+			f = float64(v.Value)
+		case *object.Float:
+			f = v.Value
+		default:
+			return nil
+		}
+		if basic == "float32" {
+			f = float64(float32(f))
+		}
+		return &object.Float{Value: f}
+	default:
+		var i int64
+		switch v := arg.(type) {
+		case *object.Integer:
+			i = v.Value
+		case *object.UnsignedInteger:
+			i = int64(v.Value)
+		case *object.Float:
+			if math.IsNaN(v.Value) || math.IsInf(v.Value, 0) {
+				return nil
+			}
+			i = int64(v.Value)
+		default:
+			return nil
+		}
+		return convertInteger(i, basic)
+	}
+	return nil
+}
+
+// convertInteger converts the integer to the integer type, wrapping around as Go does.
+func convertInteger(i int64, basic string) object.Object {
+	switch basic {
+	case "int8":
+		return &object.Integer{Value: int64(int8(i))}
+	case "int16":
+		return &object.Integer{Value: int64(int16(i))}
+	case "int32", "rune":
+		return &object.Integer{Value: int64(int32(i))}
+	case "int", "int64":
+		return &object.Integer{Value: i}
+	case "uint8", "byte":
+		return &object.UnsignedInteger{Value: uint64(uint8(i))}
+	case "uint16":
+		return &object.UnsignedInteger{Value: uint64(uint16(i))}
+	case "uint32":
+		return &object.UnsignedInteger{Value: uint64(uint32(i))}
+	case "uint", "uint64", "uintptr":
+		return &object.UnsignedInteger{Value: uint64(i)}
+	}
+	return nil
+}
+
+// stringToSlice converts a string to a slice of bytes, or of runes for elem "rune" or "int32".
+func stringToSlice(s string, elem string, typ *object.Type) *object.Slice {
+	runes := elem == "rune" || elem == "int32"
+	slice := &object.Slice{SliceFieldType: typ.FieldType(), Len: int64(len(s)), Cap: int64(len(s))}
+	if runes {
+		n := int64(utf8.RuneCountInString(s))
+		slice.Len, slice.Cap = n, n
+	}
+	if len(s) > maxConvertedStringElements {
+		slice.Len, slice.Cap = -1, -1
+		return slice
+	}
+	if runes {
+		for _, r := range s {
+			slice.Elements = append(slice.Elements, &object.Integer{Value: int64(r)})
+		}
+		return slice
+	}
+	for i := 0; i < len(s); i++ {
+		slice.Elements = append(slice.Elements, &object.UnsignedInteger{Value: uint64(s[i])})
+	}
+	return slice
+}
+
+// sliceToString converts a slice of known bytes or runes to a string, returning nil if its
+// elements are not known.
+func sliceToString(s *object.Slice) object.Object {
+	if !s.HasKnownElements() {
+		return nil
+	}
+	runes := false
+	if ft := s.SliceFieldType; ft != nil && ft.Elem != nil {
+		runes = ft.Elem.Name == "rune" || ft.Elem.Name == "int32"
+	}
+	buf := make([]byte, 0, len(s.Elements))
+	for _, elem := range s.Elements {
+		var v int64
+		switch elem := unwrapVariable(elem).(type) {
+		case *object.Integer:
+			v = elem.Value
+		case *object.UnsignedInteger:
+			v = int64(elem.Value)
+		default:
+			return nil
+		}
+		if runes {
+			buf = utf8.AppendRune(buf, rune(v))
+		} else {
+			buf = append(buf, byte(v))
+		}
+	}
+	return &object.String{Value: string(buf)}
+}
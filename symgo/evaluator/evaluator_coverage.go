@@ -0,0 +1,232 @@
+package evaluator
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// FunctionCoverage tells which statements and branches of the body of a function were explored
+// by the symbolic execution, so that the results drawn from a partially explored function, e.g.
+// the calls found in its body, can be treated with caution.
+type FunctionCoverage struct {
+	Function string         // the qualified name, e.g. "example.com/m/pkg.(*Server).Run"
+	Pos      token.Position // the position of the declaration or the literal
+	Calls    int            // the number of evaluations of the body
+
+	Statements        int // the statements of the body, without those of the nested function literals
+	CoveredStatements int
+	Branches          int // the bodies of the if and else clauses, and the cases of the switch and select statements
+	CoveredBranches   int
+
+	UncoveredStatements []token.Position
+	UncoveredBranches   []token.Position
+
+	// Aborted is the error which ended an evaluation of the body, e.g. when the maximum number
+	// of steps is exceeded, and RecoveredErrors the number of errors recovered while evaluating
+	// it, after which the rest of a block is skipped.
+	Aborted         string
+	RecoveredErrors int
+}
+
+// Partial reports whether the body of the function was not fully explored.
+func (c FunctionCoverage) Partial() bool {
+	return c.Aborted != "" || c.CoveredStatements < c.Statements || c.CoveredBranches < c.Branches
+}
+
+// WithCoverage enables the recording of the statements and branches explored in the bodies of
+// the evaluated functions, see Coverage.
+func WithCoverage() Option {
+	return func(e *Evaluator) {
+		e.coverage = make(map[token.Pos]*functionCoverage)
+	}
+}
+
+// functionCoverage is the coverage recorded for a function, keyed by its position.
+type functionCoverage struct {
+	fn              *object.Function
+	calls           int
+	statements      map[token.Pos]bool
+	branches        map[token.Pos]bool
+	aborted         string
+	recoveredErrors int
+}
+
+// coverageOf returns the coverage recorded for the function of the current call frame.
+func (e *Evaluator) coverageOf(fn *object.Function) *functionCoverage {
+	if fn == nil || fn.Body == nil {
+		return nil
+	}
+	pos := funcPos(fn)
+	c, ok := e.coverage[pos]
+	if !ok {
+		c = &functionCoverage{fn: fn, statements: make(map[token.Pos]bool), branches: make(map[token.Pos]bool)}
+		e.coverage[pos] = c
+	}
+	return c
+}
+
+// currentCoverage returns the coverage of the function being evaluated, if enabled.
+func (e *Evaluator) currentCoverage() *functionCoverage {
+	if e.coverage == nil || len(e.callStack) == 0 {
+		return nil
+	}
+	return e.coverageOf(e.callStack[len(e.callStack)-1].Fn)
+}
+
+// recordStatement records the evaluation of a statement, called by Eval.
+func (e *Evaluator) recordStatement(node ast.Node) {
+	if _, ok := node.(ast.Stmt); !ok {
+		return
+	}
+	if c := e.currentCoverage(); c != nil {
+		c.statements[node.Pos()] = true
+	}
+}
+
+// recordCoverageEvent records the entered functions, the explored branches and the recovered
+// errors, called for the trace events.
+func (e *Evaluator) recordCoverageEvent(kind object.TraceEventKind, pos token.Pos, fn object.Object) {
+	if e.coverage == nil {
+		return
+	}
+	switch kind {
+	case object.TraceEnterFunction:
+		if inst, ok := fn.(*object.InstantiatedFunction); ok {
+			fn = inst.Function
+		}
+		if f, ok := fn.(*object.Function); ok {
+			if c := e.coverageOf(f); c != nil {
+				c.calls++
+			}
+		}
+	case object.TraceBranchExplored:
+		if c := e.currentCoverage(); c != nil {
+			c.branches[pos] = true
+		}
+	case object.TraceErrorRecovered:
+		if c := e.currentCoverage(); c != nil {
+			c.recoveredErrors++
+		}
+	}
+}
+
+// recordAborted records the error ending the evaluation of the body of a function.
+func (e *Evaluator) recordAborted(fn *object.Function, result object.Object) {
+	if e.coverage == nil {
+		return
+	}
+	err, ok := result.(*object.Error)
+	if !ok {
+		return
+	}
+	if c := e.coverageOf(fn); c != nil && c.aborted == "" {
+		c.aborted = err.Message
+	}
+}
+
+// Coverage returns the coverage of the functions whose bodies were evaluated, sorted by
+// position. It is empty unless enabled by WithCoverage.
+func (e *Evaluator) Coverage() []FunctionCoverage {
+	var fset *token.FileSet
+	if e.scanner != nil {
+		fset = e.scanner.Fset()
+	}
+	position := func(pos token.Pos) token.Position {
+		if fset == nil {
+			return token.Position{}
+		}
+		return fset.Position(pos)
+	}
+
+	report := make([]FunctionCoverage, 0, len(e.coverage))
+	for pos, c := range e.coverage {
+		statements, branches := coverageTargets(c.fn.Body)
+		fc := FunctionCoverage{
+			Function:        coverageName(c.fn),
+			Pos:             position(pos),
+			Calls:           c.calls,
+			Statements:      len(statements),
+			Branches:        len(branches),
+			Aborted:         c.aborted,
+			RecoveredErrors: c.recoveredErrors,
+		}
+		for _, p := range statements {
+			if c.statements[p] {
+				fc.CoveredStatements++
+			} else {
+				fc.UncoveredStatements = append(fc.UncoveredStatements, position(p))
+			}
+		}
+		for _, p := range branches {
+			if c.branches[p] {
+				fc.CoveredBranches++
+			} else {
+				fc.UncoveredBranches = append(fc.UncoveredBranches, position(p))
+			}
+		}
+		report = append(report, fc)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i].Pos, report[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return report
+}
+
+// coverageTargets returns the positions of the statements and the branches of a function body,
+// in order, without those of the nested function literals, which are functions of their own.
+// The post statements of the for loops are not counted, as they are never evaluated.
+func coverageTargets(body *ast.BlockStmt) (statements, branches []token.Pos) {
+	skip := make(map[ast.Node]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || skip[n] {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			return true
+		case *ast.ForStmt:
+			if n.Post != nil {
+				skip[n.Post] = true
+			}
+		case *ast.IfStmt:
+			branches = append(branches, n.Body.Pos())
+			if n.Else != nil {
+				branches = append(branches, n.Else.Pos())
+			}
+		case *ast.CaseClause, *ast.CommClause:
+			branches = append(branches, n.Pos())
+			return true
+		}
+		if _, ok := n.(ast.Stmt); ok {
+			statements = append(statements, n.Pos())
+		}
+		return true
+	})
+	return statements, branches
+}
+
+// coverageName returns the qualified name of a function, e.g. "example.com/m/pkg.(*T).Run",
+// or "<closure>" for a function literal.
+func coverageName(fn *object.Function) string {
+	name := "<closure>"
+	if fn.Name != nil {
+		name = fn.Name.Name
+	}
+	if fn.Decl != nil && fn.Decl.Recv != nil && len(fn.Decl.Recv.List) > 0 {
+		name = "(" + types.ExprString(fn.Decl.Recv.List[0].Type) + ")." + name
+	}
+	if fn.Package != nil {
+		name = fn.Package.ImportPath + "." + name
+	}
+	return name
+}
@@ -8,6 +8,7 @@ import (
 	"go/printer"
 	"go/token"
 	"log/slog"
+	"slices"
 
 	scan "github.com/podhmo/go-scan/scanner"
 	"github.com/podhmo/go-scan/symgo/object"
@@ -23,7 +24,12 @@ func (e *Evaluator) evalAssignStmt(ctx context.Context, n *ast.AssignStmt, env *
 			}
 
 			// Evaluate the source object `x`
-			originalObj := e.forceEval(ctx, e.Eval(ctx, typeAssert.X, env, pkg), pkg)
+			originalObj := e.Eval(ctx, typeAssert.X, env, pkg)
+			if isError(originalObj) {
+				return originalObj
+			}
+			types := assertedTypes(typeAssert.X, originalObj, env)
+			originalObj = e.forceEval(ctx, originalObj, pkg)
 			if isError(originalObj) {
 				return originalObj
 			}
@@ -43,6 +49,7 @@ func (e *Evaluator) evalAssignStmt(ctx context.Context, n *ast.AssignStmt, env *
 			importLookup := e.scanner.BuildImportLookup(astFile)
 
 			fieldType := e.scanner.TypeInfoFromExpr(ctx, typeAssert.Type, nil, pkg, importLookup)
+			e.bindLocalTypes(fieldType, env)
 			if fieldType == nil {
 				var typeNameBuf bytes.Buffer
 				printer.Fprint(&typeNameBuf, pkg.Fset, typeAssert.Type)
@@ -63,6 +70,15 @@ func (e *Evaluator) evalAssignStmt(ctx context.Context, n *ast.AssignStmt, env *
 			if ident, ok := n.Lhs[0].(*ast.Ident); ok {
 				if ident.Name != "_" {
 					e.assignIdentifier(ctx, ident, valueForV, n.Tok, env)
+					// Asserted to an interface, `v` may hold any of the types observed for `x`,
+					// not only the type of its current value.
+					if isInterfaceAssertion(fieldType, e.resolver.ResolveType(ctx, fieldType)) {
+						if v, ok := env.Get(ident.Name); ok {
+							if v, ok := v.(*object.Variable); ok {
+								v.PossibleConcreteTypes = addConcreteTypes(slices.Clone(v.PossibleConcreteTypes), types...)
+							}
+						}
+					}
 				}
 			}
 			if ident, ok := n.Lhs[1].(*ast.Ident); ok {
@@ -154,26 +170,39 @@ func (e *Evaluator) evalAssignStmt(ctx context.Context, n *ast.AssignStmt, env *
 		case *ast.SelectorExpr:
 			// This is an assignment to a field, like `foo.Bar = 1`.
 			// We need to evaluate the `foo` part (lhs.X) to trace any calls within it.
-			e.Eval(ctx, lhs.X, env, pkg)
+			target := e.Eval(ctx, lhs.X, env, pkg)
 			// Then evaluate the RHS.
-			e.Eval(ctx, n.Rhs[0], env, pkg)
+			val := e.Eval(ctx, n.Rhs[0], env, pkg)
+			if isError(target) || isError(val) {
+				return nil
+			}
+			e.traceFieldAccess(ctx, lhs, target, nil, pkg)
+			e.assignField(ctx, lhs, target, val, env)
 			return nil
 		case *ast.IndexExpr:
 			// This is an assignment to a map or slice index, like `m[k] = v`.
 			// We need to evaluate all parts to trace calls.
 			// 1. Evaluate the map/slice expression (e.g., `m`).
-			e.Eval(ctx, lhs.X, env, pkg)
+			target := e.Eval(ctx, lhs.X, env, pkg)
 			// 2. Evaluate the index expression (e.g., `k`).
-			e.Eval(ctx, lhs.Index, env, pkg)
+			index := e.Eval(ctx, lhs.Index, env, pkg)
 			// 3. Evaluate the RHS value (e.g., `v`).
-			e.Eval(ctx, n.Rhs[0], env, pkg)
+			val := e.Eval(ctx, n.Rhs[0], env, pkg)
+			if isError(target) || isError(index) || isError(val) {
+				return nil
+			}
+			assignIndex(target, index, val)
 			return nil
 		case *ast.StarExpr:
 			// This is an assignment to a pointer dereference, like `*p = v`.
 			// Evaluate the pointer expression (e.g., `p`).
-			e.Eval(ctx, lhs.X, env, pkg)
+			target := e.Eval(ctx, lhs.X, env, pkg)
 			// Evaluate the RHS value (e.g., `v`).
-			e.Eval(ctx, n.Rhs[0], env, pkg)
+			val := e.Eval(ctx, n.Rhs[0], env, pkg)
+			if isError(target) || isError(val) {
+				return nil
+			}
+			e.assignPointee(ctx, target, val)
 			return nil
 		default:
 			return e.newError(ctx, n.Pos(), "unsupported assignment target: expected an identifier, selector or index expression, but got %T", lhs)
@@ -195,12 +224,28 @@ func (e *Evaluator) evalAssignStmt(ctx context.Context, n *ast.AssignStmt, env *
 
 		// Now, perform the assignments.
 		for i, lhsExpr := range n.Lhs {
-			if ident, ok := lhsExpr.(*ast.Ident); ok {
-				if ident.Name == "_" {
+			switch lhs := lhsExpr.(type) {
+			case *ast.Ident:
+				if lhs.Name == "_" {
 					continue
 				}
-				e.assignIdentifier(ctx, ident, rhsValues[i], n.Tok, env)
-			} else {
+				e.assignIdentifier(ctx, lhs, rhsValues[i], n.Tok, env)
+			case *ast.SelectorExpr:
+				if target := e.Eval(ctx, lhs.X, env, pkg); !isError(target) {
+					e.traceFieldAccess(ctx, lhs, target, nil, pkg)
+					e.assignField(ctx, lhs, target, rhsValues[i], env)
+				}
+			case *ast.StarExpr:
+				if target := e.Eval(ctx, lhs.X, env, pkg); !isError(target) {
+					e.assignPointee(ctx, target, rhsValues[i])
+				}
+			case *ast.IndexExpr:
+				target := e.Eval(ctx, lhs.X, env, pkg)
+				index := e.Eval(ctx, lhs.Index, env, pkg)
+				if !isError(target) && !isError(index) {
+					assignIndex(target, index, rhsValues[i])
+				}
+			default:
 				// Handle other LHS types like selectors if needed in the future.
 				e.logc(ctx, slog.LevelWarn, "unsupported LHS in parallel assignment", "type", fmt.Sprintf("%T", lhsExpr))
 			}
@@ -212,6 +257,11 @@ func (e *Evaluator) evalAssignStmt(ctx context.Context, n *ast.AssignStmt, env *
 }
 
 func (e *Evaluator) evalIdentAssignment(ctx context.Context, ident *ast.Ident, rhs ast.Expr, tok token.Token, env *object.Environment, pkg *scan.PackageInfo) object.Object {
+	// `s += x` is evaluated as `s = s + x`, so that strings built piece by piece keep their parts.
+	if tok == token.ADD_ASSIGN {
+		rhs = &ast.BinaryExpr{X: ident, OpPos: rhs.Pos(), Op: token.ADD, Y: rhs}
+		tok = token.ASSIGN
+	}
 	val := e.Eval(ctx, rhs, env, pkg)
 	if isError(val) {
 		return val
@@ -234,6 +284,10 @@ func (e *Evaluator) evalIdentAssignment(ctx context.Context, ident *ast.Ident, r
 }
 
 func (e *Evaluator) assignIdentifier(ctx context.Context, ident *ast.Ident, val object.Object, tok token.Token, env *object.Environment) object.Object {
+	// The concrete types are taken before the evaluation, which drops those accumulated by
+	// an interface-typed variable on the RHS.
+	types := concreteTypesOf(val)
+
 	// Before assigning, the RHS must be fully evaluated.
 	val = e.forceEval(ctx, val, nil) // pkg is not strictly needed here as DeclPkg is used.
 	if isError(val) {
@@ -253,6 +307,7 @@ func (e *Evaluator) assignIdentifier(ctx context.Context, ident *ast.Ident, val
 				ResolvedTypeInfo:  val.TypeInfo(),
 				ResolvedFieldType: val.FieldType(),
 			},
+			PossibleConcreteTypes: types,
 		}
 		if val.FieldType() != nil {
 			if resolved := e.resolver.ResolveType(ctx, val.FieldType()); resolved != nil && resolved.Kind == scan.InterfaceKind {
@@ -291,12 +346,18 @@ func (e *Evaluator) assignIdentifier(ctx context.Context, ident *ast.Ident, val
 		}
 	}
 
+	e.journal.BeforeWriteVariable(v)
 	v.Value = val
 	if !isLHSInterface {
 		v.SetTypeInfo(val.TypeInfo())
 		v.SetFieldType(val.FieldType())
 	}
 	newFieldType := val.FieldType()
+	// The calls on a variable of a named interface are resolved with the interface map, so the
+	// concrete types are only accumulated for the others, e.g. `any`.
+	if !isLHSInterface {
+		v.PossibleConcreteTypes = addConcreteTypes(slices.Clone(v.PossibleConcreteTypes), types...)
+	}
 
 	// Always accumulate possible types. Resetting the map can lead to lost
 	// information, especially when dealing with interface assignments where the
@@ -328,3 +389,174 @@ func (e *Evaluator) assignIdentifier(ctx context.Context, ident *ast.Ident, val
 
 	return v
 }
+
+// assignField stores the value of a field assignment, `x.F = v` or `p.F = v`, in the struct
+// the selector refers to, so that the later reads of the field, through the variable or
+// any pointer to it, see the value. A zero value struct (a symbolic placeholder) is replaced
+// with a struct instance first. The assignments to other targets, e.g. the variables of
+// other packages, are not tracked.
+func (e *Evaluator) assignField(ctx context.Context, sel *ast.SelectorExpr, target, val object.Object, env *object.Environment) {
+	if ret, ok := val.(*object.ReturnValue); ok {
+		val = ret.Value
+	}
+	val = e.forceEval(ctx, val, nil)
+	if isError(val) {
+		return
+	}
+	if ret, ok := target.(*object.ReturnValue); ok {
+		target = ret.Value
+	}
+	target = e.forceEval(ctx, target, nil)
+
+	var inst *object.Instance
+	switch t := target.(type) {
+	case *object.Instance:
+		inst = t
+	case *object.Pointer:
+		switch pointee := t.Pointee().(type) {
+		case *object.Instance:
+			inst = pointee
+		case *object.SymbolicPlaceholder:
+			if ft := pointee.FieldType(); ft != nil && ft.IsPointer {
+				return
+			}
+			if inst = e.newZeroStruct(pointee); inst != nil {
+				e.writePointer(t, inst)
+			}
+		}
+	case *object.SymbolicPlaceholder:
+		// `x.F = v` on a variable declared without a value, e.g. `var x T`. The symbolic
+		// pointers, e.g. the results of the calls out of the scope, are not materialized.
+		if ft := t.FieldType(); ft != nil && ft.IsPointer {
+			return
+		}
+		ident, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return
+		}
+		obj, ok := env.Get(ident.Name)
+		if !ok {
+			return
+		}
+		v, ok := obj.(*object.Variable)
+		if !ok {
+			return
+		}
+		if inst = e.newZeroStruct(t); inst != nil {
+			e.journal.BeforeWriteVariable(v)
+			v.Value = inst
+			v.IsEvaluated = true
+		}
+	}
+	if inst == nil {
+		return
+	}
+	structVal, ok := inst.Underlying.(*object.Struct)
+	if !ok {
+		return
+	}
+	e.journal.BeforeWriteStruct(structVal)
+	structVal.Set(sel.Sel.Name, val)
+}
+
+// assignIndex stores the value of an assignment to an element of a slice, `xs[i] = v`, in the
+// slice. At an index which is not known, the value is recorded as a possible element, and the
+// elements of the slice are no longer known.
+func assignIndex(target, index, val object.Object) {
+	if v, ok := target.(*object.Variable); ok {
+		target = v.Value
+	}
+	if v, ok := index.(*object.Variable); ok {
+		index = v.Value
+	}
+	if ret, ok := val.(*object.ReturnValue); ok {
+		val = ret.Value
+	}
+	if v, ok := val.(*object.Variable); ok && v.Value != nil {
+		val = v.Value
+	}
+	slice, ok := target.(*object.Slice)
+	if !ok {
+		return
+	}
+	if i, ok := index.(*object.Integer); ok && slice.HasKnownElements() && i.Value >= 0 && i.Value < int64(len(slice.Elements)) {
+		slice.Elements[i.Value] = val
+		return
+	}
+	// Elements only grows beyond Len, so that the elements do not look known again.
+	if slice.Len < 0 || int64(len(slice.Elements)) >= slice.Len {
+		slice.Elements = append(slice.Elements, val)
+	}
+}
+
+// assignPointee stores the value of an assignment through a pointer, `*p = v`, in the pointer
+// and in the variable it was taken from, if any. A struct value is copied into the struct
+// the pointer points to, so that the other pointers to the struct see it too.
+func (e *Evaluator) assignPointee(ctx context.Context, target, val object.Object) {
+	if ret, ok := val.(*object.ReturnValue); ok {
+		val = ret.Value
+	}
+	val = e.forceEval(ctx, val, nil)
+	if isError(val) {
+		return
+	}
+	if ret, ok := target.(*object.ReturnValue); ok {
+		target = ret.Value
+	}
+	ptr, ok := e.forceEval(ctx, target, nil).(*object.Pointer)
+	if !ok {
+		return
+	}
+
+	if dst, ok := instanceStruct(ptr.Pointee()); ok {
+		if src, ok := instanceStruct(val); ok {
+			e.journal.BeforeWriteStruct(dst)
+			dst.Fields = make(map[string]object.Object, len(src.Fields))
+			for name, field := range src.Fields {
+				dst.Fields[name] = field
+			}
+			return
+		}
+	}
+	e.writePointer(ptr, val)
+}
+
+// writePointer replaces the object the pointer points to.
+func (e *Evaluator) writePointer(ptr *object.Pointer, val object.Object) {
+	e.journal.BeforeWritePointer(ptr)
+	ptr.Value = val
+	if ptr.Cell != nil {
+		e.journal.BeforeWriteVariable(ptr.Cell)
+		ptr.Cell.Value = val
+		ptr.Cell.IsEvaluated = true
+	}
+}
+
+// newZeroStruct returns a struct instance for a zero value placeholder of a struct type,
+// or nil if the type of the placeholder is not a resolved struct type.
+func (e *Evaluator) newZeroStruct(sp *object.SymbolicPlaceholder) *object.Instance {
+	typeInfo := sp.TypeInfo()
+	if typeInfo == nil || typeInfo.Kind != scan.StructKind || typeInfo.Unresolved {
+		return nil
+	}
+	structObj := &object.Struct{StructType: typeInfo, Fields: make(map[string]object.Object)}
+	structObj.SetTypeInfo(typeInfo)
+	structObj.SetFieldType(sp.FieldType())
+	instance := &object.Instance{
+		TypeName:   typeInfo.PkgPath + "." + typeInfo.Name,
+		Underlying: structObj,
+		BaseObject: object.BaseObject{ResolvedTypeInfo: typeInfo},
+	}
+	instance.SetFieldType(sp.FieldType())
+	return instance
+}
+
+// instanceStruct returns the struct of a struct instance.
+func instanceStruct(obj object.Object) (*object.Struct, bool) {
+	inst, ok := obj.(*object.Instance)
+	if !ok {
+		return nil, false
+	}
+	st, ok := inst.Underlying.(*object.Struct)
+	return st, ok
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"go/ast"
 	"go/token"
+	"go/types"
 
 	scan "github.com/podhmo/go-scan/scanner"
 	"github.com/podhmo/go-scan/symgo/object"
@@ -48,6 +49,8 @@ func (e *Evaluator) evalBinaryExpr(ctx context.Context, node *ast.BinaryExpr, en
 		return e.evalComplexInfixExpression(ctx, node.Pos(), node.Op, left, right)
 	case lType == object.FLOAT_OBJ || rType == object.FLOAT_OBJ:
 		return e.evalFloatInfixExpression(ctx, node.Pos(), node.Op, left, right)
+	case node.Op == token.ADD && isStringConcat(left, right):
+		return object.NewStringTemplate(templatePart(node.X, left), templatePart(node.Y, right))
 	default:
 		return &object.SymbolicPlaceholder{Reason: "binary expression"}
 	}
@@ -227,3 +230,28 @@ func (e *Evaluator) evalStringInfixExpression(ctx context.Context, pos token.Pos
 		return e.newError(ctx, pos, "unknown string operator: %s", op)
 	}
 }
+
+// isStringConcat reports whether the operands of `+` are a partially constant string:
+// a constant string or a string template, and a string template or a symbolic value.
+func isStringConcat(left, right object.Object) bool {
+	isString := func(o object.Object) bool {
+		t := o.Type()
+		return t == object.STRING_OBJ || t == object.STRING_TEMPLATE_OBJ
+	}
+	isOperand := func(o object.Object) bool {
+		return isString(o) || o.Type() == object.SYMBOLIC_OBJ
+	}
+	return (isString(left) && isOperand(right)) || (isOperand(left) && isString(right))
+}
+
+// templatePart returns the part of a string template for an operand of a concatenation.
+// The holes are named after their source expressions.
+func templatePart(expr ast.Expr, val object.Object) object.StringTemplatePart {
+	switch v := val.(type) {
+	case *object.String:
+		return object.StringTemplatePart{Const: true, Value: v.Value}
+	case *object.StringTemplate:
+		return object.StringTemplatePart{Object: v} // flattened by NewStringTemplate
+	}
+	return object.StringTemplatePart{Name: types.ExprString(expr), Object: val}
+}
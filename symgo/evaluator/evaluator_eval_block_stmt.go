@@ -3,6 +3,7 @@ package evaluator
 import (
 	"context"
 	"go/ast"
+	"log/slog"
 
 	scan "github.com/podhmo/go-scan/scanner"
 	"github.com/podhmo/go-scan/symgo/object"
@@ -15,14 +16,8 @@ func (e *Evaluator) evalBlockStmt(ctx context.Context, block *ast.BlockStmt, env
 	var result object.Object
 	// The caller is responsible for creating a new scope if one is needed.
 	// We evaluate the statements in the provided environment.
-	for _, stmt := range block.List {
-		// If a statement is itself a block, it introduces a new lexical scope.
-		if innerBlock, ok := stmt.(*ast.BlockStmt); ok {
-			blockEnv := object.NewEnclosedEnvironment(env)
-			result = e.evalBlockStmt(ctx, innerBlock, blockEnv, pkg)
-		} else {
-			result = e.Eval(ctx, stmt, env, pkg)
-		}
+	for i, stmt := range block.List {
+		result = e.evalBlockItem(ctx, stmt, env, pkg)
 
 		// It's possible for a statement (like a declaration) to evaluate to a nil object.
 		// We must check for this before calling .Type() to avoid a panic.
@@ -31,10 +26,53 @@ func (e *Evaluator) evalBlockStmt(ctx context.Context, block *ast.BlockStmt, env
 		}
 
 		switch result.(type) {
-		case *object.ReturnValue, *object.Error, *object.PanicError, *object.Break, *object.Continue:
+		case *object.Error:
+			return result
+		case *object.ReturnValue, *object.PanicError, *object.Break, *object.Continue:
+			if err := e.evalGotoTargets(ctx, block.List[i+1:], env, pkg); err != nil {
+				return err
+			}
 			return result
 		}
 	}
 
 	return result
 }
+
+// evalBlockItem evaluates a statement of a block.
+func (e *Evaluator) evalBlockItem(ctx context.Context, stmt ast.Stmt, env *object.Environment, pkg *scan.PackageInfo) object.Object {
+	// If a statement is itself a block, it introduces a new lexical scope.
+	if innerBlock, ok := stmt.(*ast.BlockStmt); ok {
+		return e.evalBlockStmt(ctx, innerBlock, object.NewEnclosedEnvironment(env), pkg)
+	}
+	return e.Eval(ctx, stmt, env, pkg)
+}
+
+// evalGotoTargets explores the statements following the labels in stmts, the rest of a block
+// after a return, a panic, a break or a continue. They are only reached by a goto (goto itself
+// is a no-op, see evalBranchStmt), e.g. the states of a state machine or the error handling at
+// the end of a function. Each label is explored once, as a branch, up to the next statement
+// leaving the block. It returns an error only for an infinite recursion.
+func (e *Evaluator) evalGotoTargets(ctx context.Context, stmts []ast.Stmt, env *object.Environment, pkg *scan.PackageInfo) object.Object {
+	for i := 0; i < len(stmts); i++ {
+		if _, ok := stmts[i].(*ast.LabeledStmt); !ok {
+			continue
+		}
+		e.traceEvent(object.TraceBranchExplored, stmts[i].Pos(), pkg, nil, nil)
+	explore:
+		for ; i < len(stmts); i++ {
+			switch result := e.evalBlockItem(ctx, stmts[i], env, pkg).(type) {
+			case *object.Error:
+				e.logc(ctx, slog.LevelWarn, "error evaluating statement after a label", "error", result)
+				if isInfiniteRecursionError(result) {
+					return result
+				}
+				e.traceEvent(object.TraceErrorRecovered, stmts[i].Pos(), pkg, nil, result)
+				break explore
+			case *object.ReturnValue, *object.PanicError, *object.Break, *object.Continue:
+				break explore
+			}
+		}
+	}
+	return nil
+}
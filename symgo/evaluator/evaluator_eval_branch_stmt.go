@@ -23,6 +23,8 @@ func (e *Evaluator) evalBranchStmt(ctx context.Context, n *ast.BranchStmt) objec
 		// Treat goto as a no-op for symbolic analysis.
 		// This avoids errors and complex control flow simulation,
 		// allowing the tracer to continue to the next statement sequentially.
+		// The labels that are not reached sequentially, after a return, are
+		// explored by evalBlockStmt.
 		return nil
 	case token.FALLTHROUGH:
 		return object.FALLTHROUGH
@@ -26,7 +26,12 @@ func (e *Evaluator) evalCallExpr(ctx context.Context, n *ast.CallExpr, env *obje
 		e.logger.Log(ctx, slog.LevelDebug, "call", slog.Group("stack", stackAttrs...))
 	}
 
-	function := e.Eval(ctx, n.Fun, env, pkg)
+	var function object.Object
+	if typ := e.evalConversionType(ctx, n.Fun, env, pkg); typ != nil {
+		function = typ
+	} else {
+		function = e.Eval(ctx, n.Fun, env, pkg)
+	}
 	if isError(function) {
 		return function
 	}
@@ -76,19 +81,23 @@ func (e *Evaluator) evalCallExpr(ctx context.Context, n *ast.CallExpr, env *obje
 		// dependency tracking, etc. It receives the function object itself as the first
 		// argument, followed by the regular arguments.
 
-		// Pass the current call frame in the context so the intrinsic can know the caller.
-		intrinsicCtx := ctx
+		// Pass the current call frame in the context so the intrinsic can know the caller,
+		// and the position of the call.
+		intrinsicCtx := context.WithValue(ctx, callPosKey, n.Pos())
 		if len(e.callStack) > 0 {
 			callerFrame := e.callStack[len(e.callStack)-1]
-			intrinsicCtx = context.WithValue(ctx, callFrameKey, callerFrame)
+			intrinsicCtx = context.WithValue(intrinsicCtx, callFrameKey, callerFrame)
 		}
 		e.defaultIntrinsic(intrinsicCtx, append([]object.Object{function}, args...)...)
 	}
 
+	e.traceEvent(object.TraceCallResolved, n.Pos(), pkg, function, nil)
 	result := e.applyFunction(ctx, function, args, pkg, n.Pos())
 	if isError(result) {
 		return result
 	}
+	e.traceCallResult(n.Pos(), pkg, function, result)
+	e.recordUnresolvedCall(n, function, result)
 	return result
 }
 
@@ -126,50 +135,64 @@ func (e *Evaluator) scanFunctionLiteral(ctx context.Context, fn *object.Function
 
 	// Populate the environment with symbolic placeholders for the parameters.
 	if fn.Parameters != nil {
-		var importLookup map[string]string
-		file := fn.Package.Fset.File(fn.Body.Pos())
-		if file != nil {
-			if astFile, ok := fn.Package.AstFiles[file.Name()]; ok {
-				importLookup = e.scanner.BuildImportLookup(astFile)
-			}
-		}
-		if importLookup == nil && len(fn.Package.AstFiles) > 0 {
-			for _, astFile := range fn.Package.AstFiles {
-				importLookup = e.scanner.BuildImportLookup(astFile)
-				break
-			}
-		}
-
+		importLookup := e.funcImportLookup(fn)
 		for _, field := range fn.Parameters.List {
-			fieldType := e.scanner.TypeInfoFromExpr(ctx, field.Type, nil, fn.Package, importLookup)
-			var resolvedType *scan.TypeInfo
-			if fieldType != nil {
-				resolvedType = e.resolver.ResolveType(ctx, fieldType)
-			}
-
-			placeholder := &object.SymbolicPlaceholder{
-				Reason: "symbolic parameter for function scan",
-				BaseObject: object.BaseObject{
-					ResolvedTypeInfo:  resolvedType,
-					ResolvedFieldType: fieldType,
-				},
-			}
-
+			placeholder := e.symbolicParam(ctx, fn, field, importLookup, "symbolic parameter for function scan")
 			for _, name := range field.Names {
 				if name.Name != "_" {
 					v := &object.Variable{Name: name.Name, Value: placeholder}
-					v.SetFieldType(fieldType)
-					v.SetTypeInfo(resolvedType)
+					v.SetFieldType(placeholder.FieldType())
+					v.SetTypeInfo(placeholder.TypeInfo())
 					fnEnv.Set(name.Name, v)
 				}
 			}
 		}
 	}
 
+	// The function may never be called, and if it is, the call is evaluated on its own.
+	// So the writes of the scan to the variables captured from the enclosing scopes are
+	// rolled back, e.g. `n` stays 0 after `register(func() { n++ })`.
+	snapshot := e.journal.Snapshot()
+	defer func() {
+		e.journal.Restore(snapshot)
+		e.journal.Release(snapshot)
+	}()
+
 	// Now evaluate the body. The result is ignored; we only care about the side effects.
 	e.Eval(ctx, fn.Body, fnEnv, fn.Package)
 }
 
+// funcImportLookup returns the imports of the file declaring a function literal or method value,
+// or of any file of its package if the file is not found.
+func (e *Evaluator) funcImportLookup(fn *object.Function) map[string]string {
+	if file := fn.Package.Fset.File(fn.Body.Pos()); file != nil {
+		if astFile, ok := fn.Package.AstFiles[file.Name()]; ok {
+			return e.scanner.BuildImportLookup(astFile)
+		}
+	}
+	if astFiles := fn.Package.SortedAstFiles(); len(astFiles) > 0 {
+		return e.scanner.BuildImportLookup(astFiles[0])
+	}
+	return nil
+}
+
+// symbolicParam returns a placeholder for a parameter of a function literal or method value,
+// typed with the type of the parameter.
+func (e *Evaluator) symbolicParam(ctx context.Context, fn *object.Function, field *ast.Field, importLookup map[string]string, reason string) *object.SymbolicPlaceholder {
+	fieldType := e.scanner.TypeInfoFromExpr(ctx, field.Type, nil, fn.Package, importLookup)
+	var resolvedType *scan.TypeInfo
+	if fieldType != nil {
+		resolvedType = e.resolver.ResolveType(ctx, fieldType)
+	}
+	return &object.SymbolicPlaceholder{
+		Reason: reason,
+		BaseObject: object.BaseObject{
+			ResolvedTypeInfo:  resolvedType,
+			ResolvedFieldType: fieldType,
+		},
+	}
+}
+
 func (e *Evaluator) extendFunctionEnv(ctx context.Context, fn *object.Function, args []object.Object, baseEnv *object.Environment) (*object.Environment, error) {
 	var env *object.Environment
 	if baseEnv != nil {
@@ -204,42 +227,7 @@ func (e *Evaluator) extendFunctionEnv(ctx context.Context, fn *object.Function,
 
 	// 2. Bind named return values (if any)
 	// This must be done before binding parameters, in case a parameter has the same name.
-	if fn.Decl != nil && fn.Decl.Type.Results != nil {
-		for _, field := range fn.Decl.Type.Results.List {
-			if len(field.Names) == 0 {
-				continue // Unnamed return value
-			}
-			var importLookup map[string]string
-			if file := fn.Package.Fset.File(field.Pos()); file != nil {
-				if astFile, ok := fn.Package.AstFiles[file.Name()]; ok {
-					importLookup = e.scanner.BuildImportLookup(astFile)
-				}
-			}
-
-			fieldType := e.scanner.TypeInfoFromExpr(ctx, field.Type, nil, fn.Package, importLookup)
-			resolvedType := e.resolver.ResolveType(ctx, fieldType)
-
-			for _, name := range field.Names {
-				if name.Name == "_" {
-					continue
-				}
-				// The zero value for any type in symbolic execution is a placeholder.
-				// This placeholder carries the type information of the variable.
-				zeroValue := &object.SymbolicPlaceholder{
-					Reason:     "zero value for named return",
-					BaseObject: object.BaseObject{ResolvedTypeInfo: resolvedType, ResolvedFieldType: fieldType},
-				}
-				v := &object.Variable{
-					Name:        name.Name,
-					Value:       zeroValue,
-					IsEvaluated: true, // It has its zero value.
-				}
-				v.SetFieldType(fieldType)
-				v.SetTypeInfo(resolvedType)
-				env.SetLocal(name.Name, v)
-			}
-		}
-	}
+	e.bindNamedResults(ctx, env, funcResults(fn), fn.Package)
 
 	// 3. Bind parameters
 	if fn.Def != nil {
@@ -251,7 +239,12 @@ func (e *Evaluator) extendFunctionEnv(ctx context.Context, fn *object.Function,
 				arg = args[argIndex]
 				argIndex++
 			} else {
-				arg = &object.SymbolicPlaceholder{Reason: "symbolic parameter for entry point"}
+				placeholder := &object.SymbolicPlaceholder{Reason: "symbolic parameter for entry point"}
+				if paramDef.Type != nil {
+					placeholder.SetFieldType(paramDef.Type)
+					placeholder.SetTypeInfo(e.resolver.ResolveType(ctx, paramDef.Type))
+				}
+				arg = placeholder
 			}
 
 			if paramDef.Name != "" && paramDef.Name != "_" {
@@ -305,6 +298,7 @@ func (e *Evaluator) extendFunctionEnv(ctx context.Context, fn *object.Function,
 		// Fallback for function literals which don't have a FunctionInfo
 		e.logc(ctx, slog.LevelDebug, "function definition not available in extendFunctionEnv, falling back to AST", "function", fn.Name)
 		argIndex := 0
+		var importLookup map[string]string
 		for _, field := range fn.Parameters.List {
 			// Handle variadic parameters indicated by Ellipsis in the AST
 			isVariadic := false
@@ -314,7 +308,20 @@ func (e *Evaluator) extendFunctionEnv(ctx context.Context, fn *object.Function,
 
 			for _, name := range field.Names {
 				if argIndex >= len(args) {
-					break
+					// As for the declared functions, the missing arguments are symbolic, e.g. for a
+					// callback applied by an intrinsic.
+					if fn.Package == nil || fn.Body == nil || name.Name == "_" {
+						continue
+					}
+					if importLookup == nil {
+						importLookup = e.funcImportLookup(fn)
+					}
+					placeholder := e.symbolicParam(ctx, fn, field, importLookup, "symbolic parameter for entry point")
+					v := &object.Variable{Name: name.Name, Value: placeholder, IsEvaluated: true}
+					v.SetTypeInfo(placeholder.TypeInfo())
+					v.SetFieldType(placeholder.FieldType())
+					env.SetLocal(name.Name, v)
+					continue
 				}
 				if name.Name != "_" {
 					var valToBind object.Object
@@ -348,3 +355,59 @@ func (e *Evaluator) extendFunctionEnv(ctx context.Context, fn *object.Function,
 
 	return env, nil
 }
+
+// funcResults returns the result list of a declared function or a function literal, or nil.
+func funcResults(fn *object.Function) *ast.FieldList {
+	switch {
+	case fn == nil:
+		return nil
+	case fn.Decl != nil:
+		return fn.Decl.Type.Results
+	case fn.Lit != nil:
+		return fn.Lit.Type.Results
+	}
+	return nil
+}
+
+// bindNamedResults declares the named results of a function in its environment,
+// initialized to typed zero values, so that the body can assign to them and bare
+// returns can collect them.
+func (e *Evaluator) bindNamedResults(ctx context.Context, env *object.Environment, results *ast.FieldList, pkg *scan.PackageInfo) {
+	if results == nil || pkg == nil {
+		return
+	}
+	for _, field := range results.List {
+		if len(field.Names) == 0 {
+			continue // Unnamed return value
+		}
+		var importLookup map[string]string
+		if file := pkg.Fset.File(field.Pos()); file != nil {
+			if astFile, ok := pkg.AstFiles[file.Name()]; ok {
+				importLookup = e.scanner.BuildImportLookup(astFile)
+			}
+		}
+
+		fieldType := e.scanner.TypeInfoFromExpr(ctx, field.Type, nil, pkg, importLookup)
+		resolvedType := e.resolver.ResolveType(ctx, fieldType)
+
+		for _, name := range field.Names {
+			if name.Name == "_" {
+				continue
+			}
+			// The zero value for any type in symbolic execution is a placeholder.
+			// This placeholder carries the type information of the variable.
+			zeroValue := &object.SymbolicPlaceholder{
+				Reason:     "zero value for named return",
+				BaseObject: object.BaseObject{ResolvedTypeInfo: resolvedType, ResolvedFieldType: fieldType},
+			}
+			v := &object.Variable{
+				Name:        name.Name,
+				Value:       zeroValue,
+				IsEvaluated: true, // It has its zero value.
+			}
+			v.SetFieldType(fieldType)
+			v.SetTypeInfo(resolvedType)
+			env.SetLocal(name.Name, v)
+		}
+	}
+}
@@ -29,7 +29,8 @@ func (e *Evaluator) evalCompositeLit(ctx context.Context, node *ast.CompositeLit
 		typeObj := e.Eval(ctx, node.Type, env, pkg)
 
 		if t, ok := typeObj.(*object.Type); ok && t.ResolvedType != nil {
-			originalTypeInfo := t.ResolvedType
+			// Aliases declared with '=' denote the aliased type itself.
+			originalTypeInfo := e.resolver.ResolveAlias(ctx, t.ResolvedType)
 			if originalTypeInfo.Kind == scan.AliasKind && originalTypeInfo.Underlying != nil {
 				aliasTypeInfo = originalTypeInfo // Capture the alias type info
 				// It's an alias. fieldType represents the alias itself.
@@ -74,6 +75,7 @@ func (e *Evaluator) evalCompositeLit(ctx context.Context, node *ast.CompositeLit
 		// This handles cases where node.Type is specified but wasn't in the env.
 		if node.Type != nil {
 			fieldType = e.scanner.TypeInfoFromExpr(ctx, node.Type, nil, pkg, importLookup)
+			e.bindLocalTypes(fieldType, env)
 			if fieldType == nil {
 				var typeNameBuf bytes.Buffer
 				printer.Fprint(&typeNameBuf, pkg.Fset, node.Type)
@@ -112,6 +114,7 @@ func (e *Evaluator) evalCompositeLit(ctx context.Context, node *ast.CompositeLit
 
 	// Now that we have the type, evaluate the elements of the literal.
 	elements := make([]object.Object, 0, len(node.Elts))
+	var keyed map[string]object.Object // the values of the keyed elements, when the type may be a struct
 	for _, elt := range node.Elts {
 		switch v := elt.(type) {
 		case *ast.KeyValueExpr:
@@ -119,6 +122,11 @@ func (e *Evaluator) evalCompositeLit(ctx context.Context, node *ast.CompositeLit
 			elements = append(elements, value)
 			if fieldType != nil && fieldType.IsMap {
 				e.Eval(ctx, v.Key, env, pkg)
+			} else if key, ok := v.Key.(*ast.Ident); ok {
+				if keyed == nil {
+					keyed = make(map[string]object.Object, len(node.Elts))
+				}
+				keyed[key.Name] = value
 			}
 		default:
 			element := e.Eval(ctx, v, env, pkg)
@@ -149,7 +157,12 @@ func (e *Evaluator) evalCompositeLit(ctx context.Context, node *ast.CompositeLit
 			Cap:            sliceLen, // For a slice literal, len and cap are the same.
 		}
 		sliceObj.SetFieldType(fieldType)
-		sliceObj.SetTypeInfo(resolvedType)
+		if aliasTypeInfo != nil {
+			// As for maps, keep the named slice type for method lookups.
+			sliceObj.SetTypeInfo(aliasTypeInfo)
+		} else {
+			sliceObj.SetTypeInfo(resolvedType)
+		}
 		return sliceObj
 	}
 
@@ -227,6 +240,11 @@ func (e *Evaluator) evalCompositeLit(ctx context.Context, node *ast.CompositeLit
 		placeholder := &object.SymbolicPlaceholder{
 			Reason: reason,
 		}
+		// Keep the field values of a struct literal, so that the selectors on it (e.g. a
+		// function stored in a field and called later) are not lost.
+		if resolvedType != nil && resolvedType.Kind == scan.StructKind {
+			placeholder.Fields = keyed
+		}
 		placeholder.SetFieldType(fieldType)
 		placeholder.SetTypeInfo(resolvedType)
 		return placeholder
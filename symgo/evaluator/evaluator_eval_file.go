@@ -35,6 +35,9 @@ func (e *Evaluator) evalFile(ctx context.Context, file *ast.File, env *object.En
 				e.evalTypeDecl(ctx, d, targetEnv, pkg)
 			}
 		case *ast.FuncDecl:
+			if d.Recv != nil {
+				continue // methods are looked up by FindMethod
+			}
 			var funcInfo *scan.FunctionInfo
 			for _, f := range pkg.Functions {
 				if f.AstDecl == d {
@@ -12,6 +12,7 @@ func (e *Evaluator) evalForStmt(ctx context.Context, n *ast.ForStmt, env *object
 	// For symbolic execution, we unroll the loop once.
 	// A more sophisticated engine might unroll N times or use summaries.
 	forEnv := object.NewEnclosedEnvironment(env)
+	label := labelOf(ctx, n)
 
 	if n.Init != nil {
 		if initResult := e.Eval(ctx, n.Init, forEnv, pkg); isError(initResult) {
@@ -33,15 +34,15 @@ func (e *Evaluator) evalForStmt(ctx context.Context, n *ast.ForStmt, env *object
 		if result != nil {
 			switch obj := result.(type) {
 			case *object.Break:
-				// If the break has a label, it's for an outer loop. Propagate it.
-				if obj.Label != "" {
+				// If the break has the label of an outer loop, propagate it.
+				if !isBranchTarget(obj.Label, label) {
 					return obj
 				}
 				// Otherwise, it's for this loop, so we absorb it.
 				return &object.SymbolicPlaceholder{Reason: "for loop"}
 			case *object.Continue:
-				// If the continue has a label, it's for an outer loop. Propagate it.
-				if obj.Label != "" {
+				// If the continue has the label of an outer loop, propagate it.
+				if !isBranchTarget(obj.Label, label) {
 					return obj
 				}
 				// Otherwise, it's for this loop, so we absorb it.
@@ -75,6 +75,7 @@ func (e *Evaluator) evalGenDecl(ctx context.Context, node *ast.GenDecl, env *obj
 			var staticFieldType *scan.FieldType
 			if valSpec.Type != nil {
 				staticFieldType = e.scanner.TypeInfoFromExpr(ctx, valSpec.Type, nil, pkg, importLookup)
+				e.bindLocalTypes(staticFieldType, env)
 			}
 
 			for i, name := range valSpec.Names {
@@ -93,12 +94,11 @@ func (e *Evaluator) evalGenDecl(ctx context.Context, node *ast.GenDecl, env *obj
 						val = ret.Value
 					}
 				} else {
-					placeholder := &object.SymbolicPlaceholder{Reason: "uninitialized variable"}
 					if staticFieldType != nil {
-						placeholder.SetFieldType(staticFieldType)
-						placeholder.SetTypeInfo(resolvedTypeInfo)
+						val = zeroValue(staticFieldType, resolvedTypeInfo, "uninitialized variable")
+					} else {
+						val = &object.SymbolicPlaceholder{Reason: "uninitialized variable"}
 					}
-					val = placeholder
 				}
 
 				v := &object.Variable{
@@ -106,6 +106,8 @@ func (e *Evaluator) evalGenDecl(ctx context.Context, node *ast.GenDecl, env *obj
 					Value:       val,
 					IsEvaluated: true,
 					DeclPkg:     pkg,
+
+					PossibleConcreteTypes: concreteTypesOf(val),
 				}
 				v.SetFieldType(val.FieldType())
 				v.SetTypeInfo(val.TypeInfo())
@@ -137,17 +139,12 @@ func (e *Evaluator) evalTypeDecl(ctx context.Context, d *ast.GenDecl, env *objec
 		// Find the TypeInfo that the scanner created for this TypeSpec.
 		var typeInfo *scan.TypeInfo
 		if pkg != nil { // pkg can be nil in some tests
-			for _, ti := range pkg.Types {
-				if ti.Node == ts {
-					typeInfo = ti
-					break
-				}
-			}
+			typeInfo = findTypeInfo(pkg, ts)
 		}
 
 		if typeInfo == nil {
-			// This could be a local type definition inside a function.
-			// The scanner does not create TypeInfo for these, so we create one on the fly.
+			// The scanner did not create a TypeInfo for this TypeSpec, e.g. for a package
+			// scanned from the files of a single function, so we create one on the fly.
 			if pkg == nil || pkg.Fset == nil {
 				e.logc(ctx, slog.LevelWarn, "cannot create local type info without package context", "type", ts.Name.Name)
 				continue
@@ -166,6 +163,7 @@ func (e *Evaluator) evalTypeDecl(ctx context.Context, d *ast.GenDecl, env *objec
 
 			// Determine the underlying type information.
 			underlyingFieldType := e.scanner.TypeInfoFromExpr(ctx, ts.Type, nil, pkg, importLookup)
+			e.bindLocalTypes(underlyingFieldType, env)
 			// Note: We don't resolve the underlying type here. The important part is to
 			// capture the AST (`ts`) and the textual representation of the underlying type (`underlyingFieldType`).
 			// The resolution will happen later when this type is actually used.
@@ -178,6 +176,10 @@ func (e *Evaluator) evalTypeDecl(ctx context.Context, d *ast.GenDecl, env *objec
 				Underlying: underlyingFieldType,
 				Kind:       scan.AliasKind, // Mark it as an alias.
 			}
+			if ts.Assign.IsValid() {
+				typeInfo.IsAlias = true
+				typeInfo.AliasTarget = underlyingFieldType
+			}
 		}
 
 		typeObj := &object.Type{
@@ -188,3 +190,42 @@ func (e *Evaluator) evalTypeDecl(ctx context.Context, d *ast.GenDecl, env *objec
 		env.Set(ts.Name.Name, typeObj)
 	}
 }
+
+// findTypeInfo returns the TypeInfo the scanner created for the TypeSpec, declared either at
+// the package level or in the body of a function.
+func findTypeInfo(pkg *scan.PackageInfo, ts *ast.TypeSpec) *scan.TypeInfo {
+	for _, ti := range pkg.Types {
+		if ti.Node == ts {
+			return ti
+		}
+	}
+	for _, fn := range pkg.Functions {
+		for _, ti := range fn.LocalTypes {
+			if ti.Node == ts {
+				return ti
+			}
+		}
+	}
+	return nil
+}
+
+// bindLocalTypes links the names in a type expression that refer to the types declared in the
+// enclosing functions to their declarations in the environment. The scanner resolves the
+// unqualified names from the package types, where the local types are not listed.
+func (e *Evaluator) bindLocalTypes(ft *scan.FieldType, env *object.Environment) {
+	if ft == nil {
+		return
+	}
+	if ft.Definition == nil && ft.PkgName == "" && !ft.IsBuiltin && !ft.IsTypeParam && ft.TypeName != "" {
+		if obj, ok := env.Get(ft.TypeName); ok {
+			if t, ok := obj.(*object.Type); ok && t.ResolvedType != nil && t.ResolvedType.EnclosingFunc != nil {
+				ft.Definition = t.ResolvedType
+			}
+		}
+	}
+	e.bindLocalTypes(ft.Elem, env)
+	e.bindLocalTypes(ft.MapKey, env)
+	for _, arg := range ft.TypeArgs {
+		e.bindLocalTypes(arg, env)
+	}
+}
@@ -27,11 +27,13 @@ func (e *Evaluator) evalIfStmt(ctx context.Context, n *ast.IfStmt, env *object.E
 
 	// Evaluate both branches. Each gets its own enclosed environment.
 	thenEnv := object.NewEnclosedEnvironment(ifStmtEnv)
+	e.traceEvent(object.TraceBranchExplored, n.Body.Pos(), pkg, nil, nil)
 	thenResult := e.Eval(ctx, n.Body, thenEnv, pkg)
 
 	var elseResult object.Object
 	if n.Else != nil {
 		elseEnv := object.NewEnclosedEnvironment(ifStmtEnv)
+		e.traceEvent(object.TraceBranchExplored, n.Else.Pos(), pkg, nil, nil)
 		elseResult = e.Eval(ctx, n.Else, elseEnv, pkg)
 	}
 
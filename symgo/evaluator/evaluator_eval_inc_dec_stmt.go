@@ -53,6 +53,7 @@ func (e *Evaluator) evalIncDecStmt(ctx context.Context, n *ast.IncDecStmt, env *
 	}
 
 	// Update the variable's value in place.
+	e.journal.BeforeWriteVariable(variable)
 	variable.Value = &object.Integer{Value: newInt}
 	// Also mark it as evaluated, since it now has a concrete value.
 	variable.IsEvaluated = true
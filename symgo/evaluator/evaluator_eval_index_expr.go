@@ -27,10 +27,17 @@ func (e *Evaluator) evalIndexExpr(ctx context.Context, node *ast.IndexExpr, env
 	}
 
 	// Fallback to original logic for slice/map indexing at runtime.
-	if index := e.Eval(ctx, node.Index, env, pkg); isError(index) {
+	index := e.Eval(ctx, node.Index, env, pkg)
+	if isError(index) {
 		return index
 	}
 
+	// An element of a slice with known elements is returned as is, e.g. a function
+	// appended to the slice, which can then be called.
+	if elem := knownSliceElement(left, index); elem != nil {
+		return elem
+	}
+
 	var elemFieldType *scan.FieldType
 	var resolvedElem *scan.TypeInfo
 
@@ -74,3 +81,23 @@ func (e *Evaluator) evalIndexExpr(ctx context.Context, node *ast.IndexExpr, env
 		},
 	}
 }
+
+// knownSliceElement returns the element of a slice with known elements at a constant index,
+// or nil.
+func knownSliceElement(left, index object.Object) object.Object {
+	if v, ok := left.(*object.Variable); ok {
+		left = v.Value
+	}
+	if v, ok := index.(*object.Variable); ok {
+		index = v.Value
+	}
+	slice, ok := left.(*object.Slice)
+	if !ok || !slice.HasKnownElements() {
+		return nil
+	}
+	i, ok := index.(*object.Integer)
+	if !ok || i.Value < 0 || i.Value >= int64(len(slice.Elements)) {
+		return nil
+	}
+	return slice.Elements[i.Value]
+}
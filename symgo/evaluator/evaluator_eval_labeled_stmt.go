@@ -9,7 +9,9 @@ import (
 )
 
 func (e *Evaluator) evalLabeledStmt(ctx context.Context, n *ast.LabeledStmt, env *object.Environment, pkg *scan.PackageInfo) object.Object {
-	result := e.Eval(ctx, n.Stmt, env, pkg)
+	// The labeled loop, switch or select finds its label in the context (see labelOf), to
+	// handle the break and continue statements targeting it.
+	result := e.Eval(context.WithValue(ctx, labelKey, n), n.Stmt, env, pkg)
 
 	switch obj := result.(type) {
 	case *object.Break:
@@ -28,3 +30,61 @@ func (e *Evaluator) evalLabeledStmt(ctx context.Context, n *ast.LabeledStmt, env
 	// just propagate it up.
 	return result
 }
+
+// labelOf returns the label of stmt, or "" if it is not labeled.
+func labelOf(ctx context.Context, stmt ast.Stmt) string {
+	if n, ok := ctx.Value(labelKey).(*ast.LabeledStmt); ok && n.Stmt == stmt {
+		return n.Label.Name
+	}
+	return ""
+}
+
+// isBranchTarget reports whether a break or continue with the given label targets the
+// statement with the label own; an unlabeled one targets the innermost statement.
+func isBranchTarget(label, own string) bool {
+	return label == "" || label == own
+}
+
+// caseBranches tracks the paths of a switch or select statement leaving it with a break or a
+// continue, e.g. for an enclosing loop, or with a return. Such a branch is propagated only if no path completes
+// the statement; otherwise, the evaluation goes on after it, so as not to lose its calls.
+type caseBranches struct {
+	label     string        // the label of the statement
+	leaving   object.Object // the first break, continue or return leaving the statement
+	completes bool          // whether a path completes the statement
+}
+
+// ends reports whether res, the result of a statement of a path, ends the path with a break
+// or a continue.
+func (b *caseBranches) ends(res object.Object) bool {
+	switch res := res.(type) {
+	case *object.Break:
+		if isBranchTarget(res.Label, b.label) {
+			b.completes = true
+			return true
+		}
+	case *object.Continue:
+	default:
+		return false
+	}
+	if b.leaving == nil {
+		b.leaving = res
+	}
+	return true
+}
+
+// returns records res, a return or a panic ending a path.
+func (b *caseBranches) returns(res object.Object) {
+	if b.leaving == nil {
+		b.leaving = res
+	}
+}
+
+// result returns the break, continue or return leaving the statement, or the placeholder if a path
+// completes it.
+func (b *caseBranches) result(placeholder object.Object) object.Object {
+	if b.leaving != nil && !b.completes {
+		return b.leaving
+	}
+	return placeholder
+}
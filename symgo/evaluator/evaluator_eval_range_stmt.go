@@ -8,31 +8,59 @@ import (
 	"github.com/podhmo/go-scan/symgo/object"
 )
 
+// maxRangeElements is the largest number of stored elements of a slice for which the body of
+// a range loop over it is evaluated once per element.
+const maxRangeElements = 16
+
+var intFieldType = &scan.FieldType{Name: "int", IsBuiltin: true}
+
 func (e *Evaluator) evalRangeStmt(ctx context.Context, n *ast.RangeStmt, env *object.Environment, pkg *scan.PackageInfo) object.Object {
 	// For symbolic execution, the most important part is to evaluate the expression
 	// being ranged over, as it might contain function calls we need to trace.
-	e.Eval(ctx, n.X, env, pkg)
+	x := e.Eval(ctx, n.X, env, pkg)
+	label := labelOf(ctx, n)
+
+	if v, ok := x.(*object.Variable); ok && v.Value != nil {
+		x = v.Value
+	}
+
+	// The body is evaluated once per element stored in a slice, e.g. for each function
+	// appended to it, so that the calls made through them are traced.
+	if slice, ok := x.(*object.Slice); ok && len(slice.Elements) > 0 && len(slice.Elements) <= maxRangeElements {
+		for i, elem := range slice.Elements {
+			var key object.Object = &object.Integer{Value: int64(i)}
+			if !slice.HasKnownElements() {
+				key = e.typedPlaceholder(ctx, "range loop key", intFieldType)
+			}
+			if result, done := e.evalRangeBody(ctx, n, env, pkg, label, key, elem); done {
+				return result
+			}
+		}
+		return &object.SymbolicPlaceholder{Reason: "for-range loop"}
+	}
+
+	// Otherwise, we symbolically execute the body once, with placeholders of the types of
+	// the keys and values, if known.
+	keyType, valueType := rangeTypes(x)
+	result, _ := e.evalRangeBody(ctx, n, env, pkg, label, e.typedPlaceholder(ctx, "range loop key", keyType), e.typedPlaceholder(ctx, "range loop value", valueType))
+	return result
+}
 
-	// We symbolically execute the body once.
+// evalRangeBody evaluates the body of a range loop, labeled with label, for a key and a value.
+// It returns true with the result of the loop if the loop ends, with a break or an error, or
+// with a continue of an outer loop.
+func (e *Evaluator) evalRangeBody(ctx context.Context, n *ast.RangeStmt, env *object.Environment, pkg *scan.PackageInfo, label string, key, value object.Object) (object.Object, bool) {
 	rangeEnv := object.NewEnclosedEnvironment(env)
 
-	// Create placeholder variables for the key and value in the loop's scope.
+	// Create the variables for the key and value in the loop's scope.
 	if n.Key != nil {
 		if ident, ok := n.Key.(*ast.Ident); ok && ident.Name != "_" {
-			keyVar := &object.Variable{
-				Name:  ident.Name,
-				Value: &object.SymbolicPlaceholder{Reason: "range loop key"},
-			}
-			rangeEnv.Set(ident.Name, keyVar)
+			rangeEnv.Set(ident.Name, &object.Variable{Name: ident.Name, Value: key, IsEvaluated: true})
 		}
 	}
 	if n.Value != nil {
 		if ident, ok := n.Value.(*ast.Ident); ok && ident.Name != "_" {
-			valueVar := &object.Variable{
-				Name:  ident.Name,
-				Value: &object.SymbolicPlaceholder{Reason: "range loop value"},
-			}
-			rangeEnv.Set(ident.Name, valueVar)
+			rangeEnv.Set(ident.Name, &object.Variable{Name: ident.Name, Value: value, IsEvaluated: true})
 		}
 	}
 
@@ -40,19 +68,56 @@ func (e *Evaluator) evalRangeStmt(ctx context.Context, n *ast.RangeStmt, env *ob
 	if result != nil {
 		switch obj := result.(type) {
 		case *object.Break:
-			if obj.Label != "" {
-				return obj
+			if !isBranchTarget(obj.Label, label) {
+				return obj, true
 			}
-			return &object.SymbolicPlaceholder{Reason: "for-range loop"}
+			return &object.SymbolicPlaceholder{Reason: "for-range loop"}, true
 		case *object.Continue:
-			if obj.Label != "" {
-				return obj
+			if !isBranchTarget(obj.Label, label) {
+				return obj, true
 			}
-			return &object.SymbolicPlaceholder{Reason: "for-range loop"}
 		case *object.Error:
-			return result // Propagate errors.
+			return result, true // Propagate errors.
 		}
 	}
+	return &object.SymbolicPlaceholder{Reason: "for-range loop"}, false
+}
 
-	return &object.SymbolicPlaceholder{Reason: "for-range loop"}
+// rangeTypes returns the types of the keys and values of a range loop over x, or nil if they
+// are not known.
+func rangeTypes(x object.Object) (key, value *scan.FieldType) {
+	var ft *scan.FieldType
+	switch x := x.(type) {
+	case *object.Slice:
+		ft = x.SliceFieldType
+	case *object.Map:
+		ft = x.MapFieldType
+	}
+	if ft == nil && x != nil {
+		ft = x.FieldType()
+		if ti := x.TypeInfo(); (ft == nil || !(ft.IsSlice || ft.IsMap)) && ti != nil && ti.Underlying != nil {
+			ft = ti.Underlying
+		}
+	}
+	switch {
+	case ft == nil:
+		return nil, nil
+	case ft.IsSlice:
+		return intFieldType, ft.Elem
+	case ft.IsMap:
+		return ft.MapKey, ft.Elem
+	}
+	return nil, nil
+}
+
+// typedPlaceholder returns a placeholder of the given type, which may be nil.
+func (e *Evaluator) typedPlaceholder(ctx context.Context, reason string, ft *scan.FieldType) *object.SymbolicPlaceholder {
+	p := &object.SymbolicPlaceholder{Reason: reason}
+	if ft != nil {
+		p.SetFieldType(ft)
+		if !ft.IsBuiltin {
+			p.SetTypeInfo(e.resolver.ResolveType(ctx, ft))
+		}
+	}
+	return p
 }
@@ -10,7 +10,7 @@ import (
 
 func (e *Evaluator) evalReturnStmt(ctx context.Context, n *ast.ReturnStmt, env *object.Environment, pkg *scan.PackageInfo) object.Object {
 	if len(n.Results) == 0 {
-		return &object.ReturnValue{Value: object.NIL} // naked return
+		return e.evalBareReturn(ctx, env, pkg)
 	}
 
 	if len(n.Results) == 1 {
@@ -50,6 +50,7 @@ func (e *Evaluator) evalReturnStmt(ctx context.Context, n *ast.ReturnStmt, env *
 		}
 		// --- End NEW ---
 
+		e.recordReturnedTypes(ctx, []object.Object{val})
 		return &object.ReturnValue{Value: val}
 	}
 
@@ -58,6 +59,40 @@ func (e *Evaluator) evalReturnStmt(ctx context.Context, n *ast.ReturnStmt, env *
 	if len(vals) == 1 && isError(vals[0]) {
 		return vals[0] // Error occurred during expression evaluation
 	}
+	e.recordReturnedTypes(ctx, vals)
 
 	return &object.ReturnValue{Value: &object.MultiReturn{Values: vals}}
 }
+
+// evalBareReturn evaluates a return statement without results. In a function with named
+// results, it returns their current values; otherwise, it returns nil.
+func (e *Evaluator) evalBareReturn(ctx context.Context, env *object.Environment, pkg *scan.PackageInfo) object.Object {
+	var results *ast.FieldList
+	if len(e.callStack) > 0 {
+		results = funcResults(e.callStack[len(e.callStack)-1].Fn)
+	}
+	if results == nil || len(results.List) == 0 || len(results.List[0].Names) == 0 {
+		return &object.ReturnValue{Value: object.NIL}
+	}
+
+	var vals []object.Object
+	for _, field := range results.List {
+		for _, name := range field.Names {
+			var val object.Object = &object.SymbolicPlaceholder{Reason: "zero value for named return"}
+			if name.Name != "_" {
+				if v, ok := env.Get(name.Name); ok {
+					val = e.forceEval(ctx, v, pkg)
+					if isError(val) {
+						return val
+					}
+				}
+			}
+			vals = append(vals, val)
+		}
+	}
+	e.recordReturnedTypes(ctx, vals)
+	if len(vals) == 1 {
+		return &object.ReturnValue{Value: vals[0]}
+	}
+	return &object.ReturnValue{Value: &object.MultiReturn{Values: vals}}
+}
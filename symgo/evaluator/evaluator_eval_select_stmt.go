@@ -13,29 +13,45 @@ func (e *Evaluator) evalSelectStmt(ctx context.Context, n *ast.SelectStmt, env *
 	if n.Body == nil {
 		return &object.SymbolicPlaceholder{Reason: "empty select statement"}
 	}
+	// A select statement blocks until one of its cases completes, so they are all its paths.
+	branches := &caseBranches{label: labelOf(ctx, n)}
+
 	// Symbolically execute all cases.
 	for _, c := range n.Body.List {
 		if caseClause, ok := c.(*ast.CommClause); ok {
 			caseEnv := object.NewEnclosedEnvironment(env)
+			e.traceEvent(object.TraceBranchExplored, caseClause.Pos(), pkg, nil, nil)
 
 			// Evaluate the communication expression (e.g., the channel operation).
 			if caseClause.Comm != nil {
 				if res := e.Eval(ctx, caseClause.Comm, caseEnv, pkg); isError(res) {
 					e.logc(ctx, slog.LevelWarn, "error evaluating select case communication", "error", res)
+					e.traceEvent(object.TraceErrorRecovered, caseClause.Comm.Pos(), pkg, nil, res)
 				}
 			}
 
 			// Evaluate the body of the case.
+			ended := false
 			for _, stmt := range caseClause.Body {
-				if res := e.Eval(ctx, stmt, caseEnv, pkg); isError(res) {
+				res := e.Eval(ctx, stmt, caseEnv, pkg)
+				if isError(res) {
 					e.logc(ctx, slog.LevelWarn, "error evaluating statement in select case", "error", res)
 					if isInfiniteRecursionError(res) {
 						return res // Stop processing on infinite recursion
 					}
+					e.traceEvent(object.TraceErrorRecovered, stmt.Pos(), pkg, nil, res)
+					continue
+				}
+				if branches.ends(res) {
+					ended = true
+					break
 				}
 			}
+			if !ended {
+				branches.completes = true
+			}
 		}
 	}
 
-	return &object.SymbolicPlaceholder{Reason: "select statement"}
+	return branches.result(&object.SymbolicPlaceholder{Reason: "select statement"})
 }
@@ -179,22 +179,27 @@ func (e *Evaluator) evalSelectorExpr(ctx context.Context, n *ast.SelectorExpr, e
 
 				// c. Return a callable SymbolicPlaceholder.
 				return &object.SymbolicPlaceholder{
-					Reason:         fmt.Sprintf("interface method %s.%s", staticType.Name, n.Sel.Name),
-					Receiver:       obj, // Pass the variable object itself as the receiver
-					UnderlyingFunc: methodFuncInfo,
-					Package:        pkg,
+					Reason:                fmt.Sprintf("interface method %s.%s", staticType.Name, n.Sel.Name),
+					Receiver:              obj, // Pass the variable object itself as the receiver
+					UnderlyingFunc:        methodFuncInfo,
+					Package:               pkg,
+					PossibleConcreteTypes: possibleTypesOf(obj),
 				}
 			}
 
 			// NEW: Handle struct field access on variables directly
 			if staticType != nil && staticType.Kind == scan.StructKind {
 				if field, err := e.accessor.findFieldOnType(ctx, staticType, n.Sel.Name); err == nil && field != nil {
+					e.traceFieldAccess(ctx, n, obj, staticType, pkg)
 					var fieldValue object.Object
 					if v, isVar := obj.(*object.Variable); isVar {
 						fieldValue = e.evalVariable(ctx, v, pkg)
 					} else {
 						fieldValue = obj // Should be a placeholder or instance
 					}
+					if stored, ok := storedField(fieldValue, n.Sel.Name); ok {
+						return stored
+					}
 					return e.resolver.ResolveSymbolicField(ctx, field, fieldValue)
 				}
 			}
@@ -218,6 +223,7 @@ func (e *Evaluator) evalSelectorExpr(ctx context.Context, n *ast.SelectorExpr, e
 	}
 
 	e.logger.Debug("evalSelectorExpr: evaluated left", "type", left.Type(), "value", inspectValuer{left})
+	e.traceFieldAccess(ctx, n, left, nil, pkg)
 
 	switch val := left.(type) {
 	case *object.SymbolicPlaceholder:
@@ -405,6 +411,10 @@ func (e *Evaluator) evalSelectorExpr(ctx context.Context, n *ast.SelectorExpr, e
 			if methodErr == nil && method != nil {
 				return method
 			}
+			if methodErr == ErrAmbiguousEmbedded {
+				e.logc(ctx, slog.LevelWarn, "ambiguous selector via embedded types, skipping", "method_name", n.Sel.Name, "type_name", val.TypeName)
+				return &object.SymbolicPlaceholder{Reason: fmt.Sprintf("ambiguous selector %s on type %s", n.Sel.Name, val.TypeName)}
+			}
 
 			var field *scan.FieldInfo
 			var fieldErr error
@@ -440,7 +450,7 @@ func (e *Evaluator) evalSelectorExpr(ctx context.Context, n *ast.SelectorExpr, e
 	case *object.Pointer:
 		// When we have a selector on a pointer, we look for the method on the
 		// type of the object the pointer points to.
-		pointee := val.Value
+		pointee := val.Pointee()
 
 		// NEW: Unwrap ReturnValue if present. This handles method calls on pointers
 		// returned directly from functions, e.g., `getPtr().Method()`.
@@ -462,6 +472,11 @@ func (e *Evaluator) evalSelectorExpr(ctx context.Context, n *ast.SelectorExpr, e
 		}
 		// --- End NEW ---
 
+		// A field written through a pointer, or set by a composite literal, has a value.
+		if field, ok := storedField(pointee, n.Sel.Name); ok {
+			return field
+		}
+
 		// Generalize pointer method lookup. The pointee can be an Instance, a Map, etc.
 		// As long as it has TypeInfo, we can find its methods.
 		if typeInfo := pointee.TypeInfo(); typeInfo != nil {
@@ -492,6 +507,10 @@ func (e *Evaluator) evalSelectorExpr(ctx context.Context, n *ast.SelectorExpr, e
 			if methodErr == nil && method != nil {
 				return method
 			}
+			if methodErr == ErrAmbiguousEmbedded {
+				e.logc(ctx, slog.LevelWarn, "ambiguous selector via embedded types, skipping", "method_name", n.Sel.Name, "type_name", typeName)
+				return &object.SymbolicPlaceholder{Reason: fmt.Sprintf("ambiguous selector %s on type %s", n.Sel.Name, typeName)}
+			}
 
 			// Also check for fields if the underlying type is a struct.
 			var field *scan.FieldInfo
@@ -571,7 +590,12 @@ func (e *Evaluator) evalSelectorExpr(ctx context.Context, n *ast.SelectorExpr, e
 		return &object.SymbolicPlaceholder{
 			Reason: fmt.Sprintf("selection from unresolved type %s.%s", val.PkgPath, val.TypeName),
 		}
-	case *object.Slice, *object.Integer, *object.String, *object.Float, *object.Complex, *object.Boolean:
+	case *object.Slice, *object.Map, *object.Function, *object.Integer, *object.String, *object.Float, *object.Complex, *object.Boolean:
+		// A value of a named type of these kinds (e.g. `type StringSet map[string]bool`)
+		// can have methods declared.
+		if method := e.findMethodOnNamedValue(ctx, left, n.Sel, env, n.X.Pos()); method != nil {
+			return method
+		}
 		// Attempting to select a field or method on a primitive type is invalid.
 		// Instead of returning a hard error, return a placeholder to allow analysis to continue.
 		e.logc(ctx, slog.LevelWarn, "invalid selector on primitive value", "type", left.Type(), "selector", n.Sel.Name)
@@ -583,9 +607,27 @@ func (e *Evaluator) evalSelectorExpr(ctx context.Context, n *ast.SelectorExpr, e
 	}
 }
 
+// findMethodOnNamedValue finds the method declared on the named type of a value which is not a
+// struct, e.g. `s.Keys()` for `type StringSet map[string]bool`. It returns nil if the value has
+// no named type or the type has no such method.
+func (e *Evaluator) findMethodOnNamedValue(ctx context.Context, val object.Object, sel *ast.Ident, env *object.Environment, receiverPos token.Pos) object.Object {
+	typeInfo := val.TypeInfo()
+	if typeInfo == nil || typeInfo.Name == "" || typeInfo.Unresolved {
+		return nil
+	}
+	method, err := e.accessor.findMethodOnType(ctx, typeInfo, sel.Name, env, val, receiverPos)
+	if err != nil || method == nil {
+		return nil
+	}
+	return method
+}
+
 // evalSymbolicSelection centralizes the logic for handling a selector expression (e.g., `x.Field` or `x.Method()`)
 // where `x` is a symbolic placeholder. This is a common case when dealing with values of unresolved types.
 func (e *Evaluator) evalSymbolicSelection(ctx context.Context, val *object.SymbolicPlaceholder, sel *ast.Ident, env *object.Environment, receiver object.Object, receiverPos token.Pos) object.Object {
+	if v, ok := val.Fields[sel.Name]; ok {
+		return v
+	}
 	typeInfo := val.TypeInfo()
 	if typeInfo == nil {
 		// If we are calling a method on a placeholder that has no type info (e.g., from an
@@ -630,6 +672,13 @@ func (e *Evaluator) evalSymbolicSelection(ctx context.Context, val *object.Symbo
 				Reason:   fmt.Sprintf("symbolic method call %s on unresolved symbolic type %s", sel.Name, typeInfo.Name),
 				Receiver: val,
 			}
+			// The package of the type may have been scanned by someone else (e.g. declarations
+			// only), so the call can still be matched with the declared method.
+			if method, pkgInfo := e.findDeclaredMethod(typeInfo, sel.Name); method != nil {
+				placeholder.UnderlyingFunc = method
+				placeholder.Package = pkgInfo
+				return placeholder
+			}
 			// Try to find method in interface definition if available
 			if typeInfo.Interface != nil {
 				for _, method := range typeInfo.Interface.Methods {
@@ -646,6 +695,30 @@ func (e *Evaluator) evalSymbolicSelection(ctx context.Context, val *object.Symbo
 			}
 			return placeholder
 		}
+
+		// A resolved interface type (e.g. the result of another interface method call).
+		// Carry the declared signature so that the result of the call keeps its type
+		// and chained calls like `c.Users().Get(id).Name()` can be followed.
+		if typeInfo.Interface != nil {
+			for _, method := range e.getAllInterfaceMethods(ctx, typeInfo, make(map[string]struct{})) {
+				if method.Name == sel.Name {
+					if typeInfo.Name != "" {
+						key := fmt.Sprintf("%s.%s.%s", typeInfo.PkgPath, typeInfo.Name, sel.Name)
+						e.calledInterfaceMethods[key] = append(e.calledInterfaceMethods[key], val)
+					}
+					return &object.SymbolicPlaceholder{
+						Reason:   fmt.Sprintf("interface method call %s.%s", typeInfo.Name, sel.Name),
+						Receiver: val,
+						UnderlyingFunc: &scan.FunctionInfo{
+							Name:       method.Name,
+							Parameters: method.Parameters,
+							Results:    method.Results,
+						},
+						PossibleConcreteTypes: val.PossibleConcreteTypes,
+					}
+				}
+			}
+		}
 	}
 
 	// For symbolic placeholders, don't error - return another placeholder
@@ -656,6 +729,31 @@ func (e *Evaluator) evalSymbolicSelection(ctx context.Context, val *object.Symbo
 	}
 }
 
+// findDeclaredMethod returns the method declared for a type of an unresolved type stub, if its package
+// is already in the scanner's cache. The package is never scanned here, to respect the scan policy.
+func (e *Evaluator) findDeclaredMethod(typeInfo *scan.TypeInfo, name string) (*scan.FunctionInfo, *scan.PackageInfo) {
+	if typeInfo.PkgPath == "" || typeInfo.Name == "" {
+		return nil, nil
+	}
+	pkgInfo, ok := e.scanner.SeenPackage(typeInfo.PkgPath)
+	if !ok {
+		return nil, nil
+	}
+	for _, fn := range pkgInfo.Functions {
+		if fn.Name != name || fn.Receiver == nil || fn.Receiver.Type == nil {
+			continue
+		}
+		recv := fn.Receiver.Type
+		if recv.IsPointer && recv.Elem != nil {
+			recv = recv.Elem
+		}
+		if recv.Name == typeInfo.Name {
+			return fn, pkgInfo
+		}
+	}
+	return nil, nil
+}
+
 // getAllInterfaceMethods recursively collects all methods from an interface and its embedded interfaces.
 // It handles cycles by keeping track of visited interface types.
 // A duplicate of this method exists in `goscan.Scanner` for historical reasons;
@@ -718,3 +816,21 @@ func (e *Evaluator) getOrResolveFunction(ctx context.Context, pkg *object.Packag
 	e.funcCache[key] = fn
 	return fn
 }
+
+// storedField returns the value of a field of a struct instance, or of the struct instance a
+// pointer points to, if it was set by a composite literal or an assignment. The fields of the
+// composite literals of unresolved types are kept by their placeholders.
+func storedField(obj object.Object, name string) (object.Object, bool) {
+	if ptr, ok := obj.(*object.Pointer); ok {
+		obj = ptr.Pointee()
+	}
+	if sp, ok := obj.(*object.SymbolicPlaceholder); ok {
+		v, ok := sp.Fields[name]
+		return v, ok
+	}
+	st, ok := instanceStruct(obj)
+	if !ok {
+		return nil, false
+	}
+	return st.Get(name)
+}
@@ -28,7 +28,7 @@ func (e *Evaluator) evalStarExpr(ctx context.Context, node *ast.StarExpr, env *o
 	if ptr, ok := val.(*object.Pointer); ok {
 		// If we are dereferencing a pointer to an unresolved type, the result is
 		// a symbolic placeholder representing an instance of that type.
-		if ut, ok := ptr.Value.(*object.UnresolvedType); ok {
+		if ut, ok := ptr.Pointee().(*object.UnresolvedType); ok {
 			placeholder := &object.SymbolicPlaceholder{
 				Reason: fmt.Sprintf("instance of unresolved type %s.%s", ut.PkgPath, ut.TypeName),
 			}
@@ -47,7 +47,7 @@ func (e *Evaluator) evalStarExpr(ctx context.Context, node *ast.StarExpr, env *o
 		// The value of a pointer is the object it points to.
 		// By returning the pointee directly, a selector expression like `(*p).MyMethod`
 		// will operate on the instance, which is the correct behavior.
-		return ptr.Value
+		return ptr.Pointee()
 	}
 
 	// If we have a symbolic placeholder that represents a pointer type,
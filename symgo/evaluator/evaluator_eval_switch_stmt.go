@@ -0,0 +1,135 @@
+package evaluator
+
+import (
+	"context"
+	"go/ast"
+	"log/slog"
+
+	scan "github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// evalSwitchStmt explores each case of an expression switch, as the if statements explore both
+// branches: a path starts at each case clause, and goes on through the following ones with
+// fallthrough. A path ending with a return, or an error, does not stop the exploration of the
+// other cases. If the tag is a variable, it is narrowed in a case with a single nil or constant
+// expression, e.g. `case nil:` or `case "debug":`, to the value of the expression.
+func (e *Evaluator) evalSwitchStmt(ctx context.Context, n *ast.SwitchStmt, env *object.Environment, pkg *scan.PackageInfo) object.Object {
+	switchEnv := env
+	if n.Init != nil {
+		switchEnv = object.NewEnclosedEnvironment(env)
+		if initResult := e.Eval(ctx, n.Init, switchEnv, pkg); isError(initResult) {
+			return initResult
+		}
+	}
+
+	// The tag is evaluated to trace the calls in it, e.g. `switch kind() {`.
+	if n.Tag != nil {
+		if tag := e.Eval(ctx, n.Tag, switchEnv, pkg); isError(tag) {
+			return tag
+		}
+	}
+	tagVar, tagName := switchTagVariable(n.Tag, switchEnv)
+
+	if n.Body == nil {
+		return &object.SymbolicPlaceholder{Reason: "switch statement"}
+	}
+
+	// Without a default case, no case may match and the statement completes.
+	branches := &caseBranches{label: labelOf(ctx, n), completes: !hasDefaultCase(n.Body)}
+	for i := 0; i < len(n.Body.List); i++ {
+		pathEnv := object.NewEnclosedEnvironment(switchEnv)
+		e.traceEvent(object.TraceBranchExplored, n.Body.List[i].Pos(), pkg, nil, nil)
+
+		for j := i; j < len(n.Body.List); j++ {
+			caseClause, ok := n.Body.List[j].(*ast.CaseClause)
+			if !ok {
+				continue
+			}
+
+			// The expressions of the clauses reached by a fallthrough are not evaluated.
+			if j == i {
+				for _, expr := range caseClause.List {
+					res := e.Eval(ctx, expr, pathEnv, pkg)
+					if isError(res) {
+						return res
+					}
+					if tagVar != nil && len(caseClause.List) == 1 {
+						e.narrowSwitchTag(ctx, pathEnv, tagName, tagVar, res, pkg)
+					}
+				}
+			}
+
+			hasFallthrough := false
+			for _, stmt := range caseClause.Body {
+				result := e.Eval(ctx, stmt, pathEnv, pkg)
+				if isError(result) {
+					e.logc(ctx, slog.LevelWarn, "error evaluating statement in switch case", "error", result)
+					if isInfiniteRecursionError(result) {
+						return result
+					}
+					e.traceEvent(object.TraceErrorRecovered, stmt.Pos(), pkg, nil, result)
+					continue
+				}
+				if branches.ends(result) {
+					goto endPath
+				}
+				switch result.(type) {
+				case *object.Fallthrough:
+					hasFallthrough = true
+				case *object.ReturnValue, *object.PanicError:
+					branches.returns(result)
+					goto endPath
+				}
+			}
+
+			if !hasFallthrough {
+				break
+			}
+		}
+		branches.completes = true
+	endPath:
+	}
+
+	return branches.result(&object.SymbolicPlaceholder{Reason: "switch statement"})
+}
+
+// switchTagVariable returns the variable of the tag of a switch statement, e.g. `switch err {`,
+// or nil if the tag is not a variable.
+func switchTagVariable(tag ast.Expr, env *object.Environment) (*object.Variable, string) {
+	ident, ok := tag.(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return nil, ""
+	}
+	obj, ok := env.Get(ident.Name)
+	if !ok {
+		return nil, ""
+	}
+	v, ok := obj.(*object.Variable)
+	if !ok {
+		return nil, ""
+	}
+	return v, ident.Name
+}
+
+// narrowSwitchTag binds the tag variable of a switch statement to the value of the expression
+// of a case, in the environment of the case, if the value is nil or a constant. A nil value has
+// no concrete types to dispatch the method calls to. As the variable is shadowed in the case,
+// an assignment to it there is not seen after the switch statement.
+func (e *Evaluator) narrowSwitchTag(ctx context.Context, env *object.Environment, name string, tagVar *object.Variable, value object.Object, pkg *scan.PackageInfo) {
+	value = e.forceEval(ctx, value, pkg)
+	switch value.(type) {
+	case *object.Nil, *object.String, *object.Integer, *object.UnsignedInteger, *object.Float, *object.Complex, *object.Boolean:
+	default:
+		return
+	}
+	narrowed := tagVar.Clone().(*object.Variable)
+	narrowed.Value = value
+	narrowed.Initializer = nil
+	narrowed.IsEvaluated = true
+	if _, ok := value.(*object.Nil); ok {
+		narrowed.PossibleTypes = nil
+		narrowed.PossibleConcreteTypes = nil
+	}
+	env.SetLocal(name, narrowed)
+}
@@ -22,6 +22,19 @@ func (e *Evaluator) evalTypeAssertExpr(ctx context.Context, n *ast.TypeAssertExp
 		return val
 	}
 
+	// A function asserted to a function type is kept, so that it is called through the result,
+	// e.g. `v.Load().(func())()`.
+	if _, ok := n.Type.(*ast.FuncType); ok {
+		fn := unwrapVariable(val)
+		if rv, ok := fn.(*object.ReturnValue); ok {
+			fn = unwrapVariable(rv.Value)
+		}
+		switch fn.(type) {
+		case *object.Function, *object.Intrinsic:
+			return fn
+		}
+	}
+
 	// Next, resolve the asserted type (T).
 	if pkg == nil || pkg.Fset == nil {
 		return e.newError(ctx, n.Pos(), "package info or fset is missing, cannot resolve types for type assertion")
@@ -37,6 +50,7 @@ func (e *Evaluator) evalTypeAssertExpr(ctx context.Context, n *ast.TypeAssertExp
 	importLookup := e.scanner.BuildImportLookup(astFile)
 
 	fieldType := e.scanner.TypeInfoFromExpr(ctx, n.Type, nil, pkg, importLookup)
+	e.bindLocalTypes(fieldType, env)
 	if fieldType == nil {
 		var typeNameBuf bytes.Buffer
 		printer.Fprint(&typeNameBuf, pkg.Fset, n.Type)
@@ -50,9 +64,42 @@ func (e *Evaluator) evalTypeAssertExpr(ctx context.Context, n *ast.TypeAssertExp
 	}
 
 	// In the single-value form, the result is just a value of the asserted type.
-	// We create a symbolic placeholder for it.
-	return &object.SymbolicPlaceholder{
+	// We create a symbolic placeholder for it. For an interface, it holds the concrete types
+	// observed for x, on which the methods called on the result are explored. If it is empty,
+	// the calls fall back to the implementations found in the interface map.
+	placeholder := &object.SymbolicPlaceholder{
 		Reason:     fmt.Sprintf("value from type assertion to %s", fieldType.String()),
 		BaseObject: object.BaseObject{ResolvedTypeInfo: resolvedType, ResolvedFieldType: fieldType},
 	}
+	if isInterfaceAssertion(fieldType, resolvedType) {
+		placeholder.PossibleConcreteTypes = assertedTypes(n.X, val, env)
+	}
+	return placeholder
+}
+
+// assertedTypes returns the concrete types the operand x of a type assertion may hold: those
+// assigned to it along the evaluated paths if it is a variable, or else those of its value.
+func assertedTypes(x ast.Expr, val object.Object, env *object.Environment) []*scan.FieldType {
+	if ident, ok := x.(*ast.Ident); ok {
+		if v, ok := env.Get(ident.Name); ok {
+			if v, ok := v.(*object.Variable); ok {
+				return concreteTypesOf(v)
+			}
+		}
+	}
+	return concreteTypesOf(val)
+}
+
+// isInterfaceAssertion reports whether a type assertion is to an interface, including `any`
+// and anonymous interfaces like `interface{ Hello() string }`.
+func isInterfaceAssertion(ft *scan.FieldType, ti *scan.TypeInfo) bool {
+	switch {
+	case ft.IsPointer || ft.IsSlice || ft.IsMap || ft.IsChan:
+		return false
+	case ft.IsBuiltin:
+		return ft.Name == "any" || ft.Name == "error"
+	case ft.Definition != nil && ft.Definition.Kind == scan.InterfaceKind:
+		return true
+	}
+	return ti != nil && ti.Kind == scan.InterfaceKind
 }
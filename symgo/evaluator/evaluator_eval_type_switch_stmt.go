@@ -21,6 +21,7 @@ func (e *Evaluator) evalTypeSwitchStmt(ctx context.Context, n *ast.TypeSwitchStm
 
 	var varName string
 	var originalObj object.Object
+	var types []*scan.FieldType // the concrete types observed for the switched value
 
 	switch assign := n.Assign.(type) {
 	case *ast.AssignStmt:
@@ -41,6 +42,7 @@ func (e *Evaluator) evalTypeSwitchStmt(ctx context.Context, n *ast.TypeSwitchStm
 		if isError(originalObj) {
 			return originalObj
 		}
+		types = assertedTypes(typeAssert.X, originalObj, switchEnv)
 
 	case *ast.ExprStmt:
 		typeAssert, ok := assign.X.(*ast.TypeAssertExpr)
@@ -56,6 +58,8 @@ func (e *Evaluator) evalTypeSwitchStmt(ctx context.Context, n *ast.TypeSwitchStm
 		return e.newError(ctx, n.Pos(), "expected AssignStmt or ExprStmt in TypeSwitchStmt, got %T", n.Assign)
 	}
 
+	// Without a default case, no case may match and the statement completes.
+	branches := &caseBranches{label: labelOf(ctx, n), completes: !hasDefaultCase(n.Body)}
 	if n.Body != nil {
 		file := pkg.Fset.File(n.Pos())
 		if file == nil {
@@ -73,6 +77,7 @@ func (e *Evaluator) evalTypeSwitchStmt(ctx context.Context, n *ast.TypeSwitchStm
 				continue
 			}
 			caseEnv := object.NewEnclosedEnvironment(switchEnv)
+			e.traceEvent(object.TraceBranchExplored, caseClause.Pos(), pkg, nil, nil)
 
 			if varName != "" {
 				if caseClause.List == nil { // default case
@@ -87,6 +92,7 @@ func (e *Evaluator) evalTypeSwitchStmt(ctx context.Context, n *ast.TypeSwitchStm
 							ResolvedTypeInfo:  val.TypeInfo(),
 							ResolvedFieldType: val.FieldType(),
 						},
+						PossibleConcreteTypes: types,
 					}
 					caseEnv.Set(varName, v)
 				} else {
@@ -94,6 +100,7 @@ func (e *Evaluator) evalTypeSwitchStmt(ctx context.Context, n *ast.TypeSwitchStm
 					// This allows the tracer to explore this path hypothetically.
 					typeExpr := caseClause.List[0]
 					fieldType := e.scanner.TypeInfoFromExpr(ctx, typeExpr, nil, pkg, importLookup)
+					e.bindLocalTypes(fieldType, env)
 					if fieldType == nil {
 						if id, ok := typeExpr.(*ast.Ident); ok {
 							fieldType = &scan.FieldType{Name: id.Name, IsBuiltin: true}
@@ -123,10 +130,14 @@ func (e *Evaluator) evalTypeSwitchStmt(ctx context.Context, n *ast.TypeSwitchStm
 							},
 						}
 					} else {
-						val = &object.SymbolicPlaceholder{
+						placeholder := &object.SymbolicPlaceholder{
 							Reason:     fmt.Sprintf("type switch case variable %s", fieldType.String()),
 							BaseObject: object.BaseObject{ResolvedTypeInfo: resolvedType, ResolvedFieldType: fieldType},
 						}
+						if isInterfaceAssertion(fieldType, resolvedType) {
+							placeholder.PossibleConcreteTypes = types
+						}
+						val = placeholder
 					}
 
 					v := &object.Variable{
@@ -142,70 +153,40 @@ func (e *Evaluator) evalTypeSwitchStmt(ctx context.Context, n *ast.TypeSwitchStm
 				}
 			}
 
+			ended := false
 			for _, stmt := range caseClause.Body {
-				if res := e.Eval(ctx, stmt, caseEnv, pkg); isError(res) {
+				res := e.Eval(ctx, stmt, caseEnv, pkg)
+				if isError(res) {
 					e.logc(ctx, slog.LevelWarn, "error evaluating statement in type switch case", "error", res)
 					if isInfiniteRecursionError(res) {
 						return res
 					}
+					e.traceEvent(object.TraceErrorRecovered, stmt.Pos(), pkg, nil, res)
+					continue
+				}
+				if branches.ends(res) {
+					ended = true
+					break
 				}
 			}
+			if !ended {
+				branches.completes = true
+			}
 		}
 	}
 
-	return &object.SymbolicPlaceholder{Reason: "type switch statement"}
+	return branches.result(&object.SymbolicPlaceholder{Reason: "type switch statement"})
 }
 
-func (e *Evaluator) evalSwitchStmt(ctx context.Context, n *ast.SwitchStmt, env *object.Environment, pkg *scan.PackageInfo) object.Object {
-	switchEnv := env
-	if n.Init != nil {
-		switchEnv = object.NewEnclosedEnvironment(env)
-		if initResult := e.Eval(ctx, n.Init, switchEnv, pkg); isError(initResult) {
-			return initResult
-		}
-	}
-
-	if n.Body == nil {
-		return &object.SymbolicPlaceholder{Reason: "switch statement"}
+// hasDefaultCase reports whether the body of a switch statement has a default case.
+func hasDefaultCase(body *ast.BlockStmt) bool {
+	if body == nil {
+		return false
 	}
-
-	for i := 0; i < len(n.Body.List); i++ {
-		pathEnv := object.NewEnclosedEnvironment(switchEnv)
-
-		for j := i; j < len(n.Body.List); j++ {
-			caseClause, ok := n.Body.List[j].(*ast.CaseClause)
-			if !ok {
-				continue
-			}
-
-			for _, expr := range caseClause.List {
-				if res := e.Eval(ctx, expr, pathEnv, pkg); isError(res) {
-					return res
-				}
-			}
-
-			hasFallthrough := false
-			for _, stmt := range caseClause.Body {
-				result := e.Eval(ctx, stmt, pathEnv, pkg)
-
-				if result != nil {
-					switch result.Type() {
-					case object.FALLTHROUGH_OBJ:
-						hasFallthrough = true
-					case object.BREAK_OBJ:
-						goto endPath
-					case object.RETURN_VALUE_OBJ, object.ERROR_OBJ, object.CONTINUE_OBJ:
-						return result
-					}
-				}
-			}
-
-			if !hasFallthrough {
-				break
-			}
+	for _, stmt := range body.List {
+		if clause, ok := stmt.(*ast.CaseClause); ok && clause.List == nil {
+			return true
 		}
-	endPath:
 	}
-
-	return &object.SymbolicPlaceholder{Reason: "switch statement"}
+	return false
 }
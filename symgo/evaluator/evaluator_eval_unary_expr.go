@@ -48,6 +48,15 @@ func (e *Evaluator) evalUnaryExpr(ctx context.Context, node *ast.UnaryExpr, env
 			return val
 		}
 		ptr := &object.Pointer{Value: val}
+		// A pointer to a variable shares the variable as its cell, so that the writes
+		// through the pointer and to the variable are seen by both.
+		if ident, ok := node.X.(*ast.Ident); ok {
+			if obj, ok := env.Get(ident.Name); ok {
+				if v, ok := obj.(*object.Variable); ok {
+					ptr.Cell = v
+				}
+			}
+		}
 		if originalFieldType := val.FieldType(); originalFieldType != nil {
 			pointerFieldType := &scan.FieldType{
 				IsPointer: true,
@@ -23,12 +23,14 @@ func (e *Evaluator) evalVariable(ctx context.Context, v *object.Variable, pkg *s
 		// This is a variable declared without a value, like `var x int`.
 		// Its value is the zero value for its type. For symbolic execution,
 		// we represent this with a specific placeholder that carries the type info.
-		placeholder := &object.SymbolicPlaceholder{Reason: "zero value for uninitialized variable"}
+		var zero object.Object
 		if ft := v.FieldType(); ft != nil {
-			placeholder.SetFieldType(ft)
-			placeholder.SetTypeInfo(e.resolver.ResolveType(ctx, ft))
+			zero = zeroValue(ft, e.resolver.ResolveType(ctx, ft), "zero value for uninitialized variable")
+		} else {
+			zero = &object.SymbolicPlaceholder{Reason: "zero value for uninitialized variable"}
 		}
-		v.Value = placeholder
+		e.journal.BeforeWriteVariable(v)
+		v.Value = zero
 		v.IsEvaluated = true
 		return v.Value
 	}
@@ -46,12 +48,33 @@ func (e *Evaluator) evalVariable(ctx context.Context, v *object.Variable, pkg *s
 	if isError(val) {
 		return val
 	}
+	e.journal.BeforeWriteVariable(v)
 	v.Value = val
 	v.IsEvaluated = true
 	e.logger.DebugContext(ctx, "evalVariable: finished evaluation", "var", v.Name, "value_type", val.Type(), "value", inspectValuer{val})
 	return val
 }
 
+// zeroValue returns the zero value of a variable of the given type declared without a value:
+// an empty slice for a slice type, so that the values appended to it are tracked, and a
+// placeholder carrying the type otherwise.
+func zeroValue(ft *scan.FieldType, ti *scan.TypeInfo, reason string) object.Object {
+	sliceType := ft
+	if !sliceType.IsSlice && ti != nil && ti.Underlying != nil && ti.Underlying.IsSlice {
+		sliceType = ti.Underlying
+	}
+	if sliceType.IsSlice {
+		slice := &object.Slice{SliceFieldType: sliceType}
+		slice.SetFieldType(ft)
+		slice.SetTypeInfo(ti)
+		return slice
+	}
+	placeholder := &object.SymbolicPlaceholder{Reason: reason}
+	placeholder.SetFieldType(ft)
+	placeholder.SetTypeInfo(ti)
+	return placeholder
+}
+
 // forceEval recursively evaluates an object until it is no longer a variable or ambiguous selector.
 // This is crucial for handling variables whose initializers are other variables and for resolving ambiguity.
 func (e *Evaluator) forceEval(ctx context.Context, obj object.Object, pkg *scan.PackageInfo) object.Object {
@@ -6,7 +6,9 @@ import (
 	"go/ast"
 	"go/token"
 	"log/slog"
+	"strings"
 
+	scan "github.com/podhmo/go-scan/scanner"
 	"github.com/podhmo/go-scan/symgo/object"
 )
 
@@ -65,6 +67,111 @@ func (e *Evaluator) getOrLoadPackage(ctx context.Context, path string) (*object.
 	return pkgObj, nil
 }
 
+// LoadPackage registers the package objects of an already scanned package at once: its
+// constants, types, variables and functions in its environment, and its methods in the
+// function cache, so that they can be looked up without evaluating its files. The variable
+// initializers are evaluated, as the package initialization does, including the ones of `_`.
+// Unlike getOrLoadPackage, the package is not resolved again, and a placeholder cached
+// for it (e.g. when it was denied by the scan policy) is replaced.
+func (e *Evaluator) LoadPackage(ctx context.Context, pkg *scan.PackageInfo) *object.Package {
+	pkgObj, ok := e.pkgCache[pkg.ImportPath]
+	if !ok || pkgObj.ScannedInfo == nil {
+		pkgObj = &object.Package{
+			Name:        pkg.Name,
+			Path:        pkg.ImportPath,
+			Env:         object.NewEnclosedEnvironment(e.UniverseEnv),
+			ScannedInfo: pkg,
+		}
+		e.pkgCache[pkg.ImportPath] = pkgObj
+		delete(e.initializedPkgs, pkg.ImportPath)
+	}
+	e.ensurePackageEnvPopulated(ctx, pkgObj)
+	for _, f := range pkgObj.ScannedInfo.Functions {
+		if f.Receiver != nil {
+			e.getOrResolveFunction(ctx, pkgObj, f)
+		}
+	}
+
+	for _, v := range pkgObj.ScannedInfo.Variables {
+		if v.ValExpr == nil {
+			continue
+		}
+		var obj object.Object
+		if v.Name == "_" {
+			// The blank variables are not bound, so each one is evaluated on its own.
+			blank := &object.Variable{Name: v.Name, Initializer: v.ValExpr, DeclEnv: pkgObj.Env, DeclPkg: pkgObj.ScannedInfo}
+			blank.SetFieldType(v.Type)
+			obj = blank
+		} else if obj, ok = pkgObj.Env.Get(v.Name); !ok {
+			continue
+		}
+		if result := e.forceEval(ctx, obj, pkgObj.ScannedInfo); isError(result) {
+			e.logc(ctx, slog.LevelWarn, "could not evaluate variable initializer", "package", pkg.ImportPath, "var", v.Name, "error", result)
+		}
+	}
+	return pkgObj
+}
+
+// FindMethod returns the method of the package with the canonical receiver name, in the
+// form of scanner.CanonicalName, e.g. "(*Server).Start" or "(*example.com/app.Server).Start"
+// ("Server.Start" for a value receiver). A method of a value receiver is also found with a
+// pointer receiver, as it is in the method set of the pointer.
+func (e *Evaluator) FindMethod(ctx context.Context, pkg *object.Package, name string) (object.Object, bool) {
+	if pkg.ScannedInfo == nil {
+		return nil, false
+	}
+	want, ok := parseMethodName(pkg.Path, name)
+	if !ok {
+		return nil, false
+	}
+	var found *scan.FunctionInfo
+	for _, f := range pkg.ScannedInfo.Functions {
+		if f.Receiver == nil {
+			continue
+		}
+		got := f.CanonicalName()
+		if got.TypeName != want.TypeName || got.Name != want.Name {
+			continue
+		}
+		if got.IsPointer == want.IsPointer {
+			found = f
+			break
+		}
+		if want.IsPointer {
+			found = f // a value method, unless the pointer one is found later
+		}
+	}
+	if found == nil {
+		return nil, false
+	}
+	return e.getOrResolveFunction(ctx, pkg, found), true
+}
+
+// parseMethodName parses the name of a method of the package at pkgPath, qualified or not.
+func parseMethodName(pkgPath, name string) (scan.CanonicalName, bool) {
+	if n, err := scan.ParseCanonicalName(name); err == nil && n.IsMethod() {
+		return n, n.PkgPath == pkgPath
+	}
+	var n scan.CanonicalName
+	if rest, ok := strings.CutPrefix(name, "("); ok {
+		recv, method, ok := strings.Cut(rest, ").")
+		if !ok {
+			return n, false
+		}
+		recv, n.IsPointer = strings.CutPrefix(recv, "*")
+		n.TypeName, n.Name = recv, method
+	} else {
+		recv, method, ok := strings.Cut(name, ".")
+		if !ok {
+			return n, false
+		}
+		n.TypeName, n.Name = recv, method
+	}
+	n.PkgPath = pkgPath
+	n.TypeName, _, _ = strings.Cut(n.TypeName, "[")
+	return n, n.TypeName != "" && n.Name != ""
+}
+
 func (e *Evaluator) ensurePackageEnvPopulated(ctx context.Context, pkgObj *object.Package) {
 	e.logc(ctx, slog.LevelDebug, "ensurePackageEnvPopulated: checking package", "path", pkgObj.Path, "scanned", pkgObj.ScannedInfo != nil)
 	if pkgObj.ScannedInfo == nil {
@@ -118,50 +225,22 @@ func (e *Evaluator) ensurePackageEnvPopulated(ctx context.Context, pkgObj *objec
 			continue
 		}
 
-		// A single var declaration can have multiple specs (e.g., var ( a=1; b=2 )).
-		// We need to find the right spec for the current variable `v`.
-		for _, spec := range v.GenDecl.Specs {
-			if vs, ok := spec.(*ast.ValueSpec); ok {
-				// Check if this spec contains our variable `v.Name`.
-				var valueIndex = -1
-				for i, nameIdent := range vs.Names {
-					if nameIdent.Name == v.Name {
-						valueIndex = i
-						break
-					}
-				}
-
-				// If we found our variable in this spec, determine its initializer.
-				if valueIndex != -1 {
-					var initializer ast.Expr
-					// Case 1: var a, b = 1, 2 (1-to-1 mapping)
-					if len(vs.Values) == len(vs.Names) {
-						initializer = vs.Values[valueIndex]
-					}
-					// Case 2: var a, b = f() (multi-value return from a single call)
-					if len(vs.Values) == 1 {
-						initializer = vs.Values[0]
-					}
-					// Case 3: var a, b string (no initializer) -> initializer remains nil.
-
-					lazyVar := &object.Variable{
-						Name:        v.Name,
-						IsEvaluated: false,
-						Initializer: initializer,
-						DeclEnv:     env,
-						DeclPkg:     pkgInfo,
-					}
-					lazyVar.SetFieldType(v.Type) // Set the static type from the declaration
-					env.SetLocal(v.Name, lazyVar)
-					break // Found the right spec, move to the next variable in pkgInfo.Variables
-				}
-			}
-		}
-	}
-
-	// Populate functions
+		// The initializer is nil for `var a, b string`, and shared by the names of `var a, b = f()`.
+		lazyVar := &object.Variable{
+			Name:        v.Name,
+			IsEvaluated: false,
+			Initializer: v.ValExpr,
+			DeclEnv:     env,
+			DeclPkg:     pkgInfo,
+		}
+		lazyVar.SetFieldType(v.Type) // Set the static type from the declaration
+		env.SetLocal(v.Name, lazyVar)
+	}
+
+	// Populate functions. The methods are not bound by name, as they would hide the
+	// functions of the same name; see FindMethod.
 	for _, f := range pkgInfo.Functions {
-		if !shouldScan && !ast.IsExported(f.Name) {
+		if f.Receiver != nil || (!shouldScan && !ast.IsExported(f.Name)) {
 			continue
 		}
 		fnObject := e.getOrResolveFunction(ctx, pkgObj, f)
@@ -0,0 +1,322 @@
+package evaluator
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"runtime"
+
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// memoryCheckInterval is the number of function applications between two checks of the memory budget.
+// Reading the memory statistics stops the world, so it is not done on every call.
+const memoryCheckInterval = 100
+
+// Stats is a snapshot of the evaluator's memory-related telemetry, for tuning the memory budget.
+type Stats struct {
+	// Objects and Environments are the numbers of distinct objects and environments
+	// retained by the evaluator: reachable from the loaded packages, the root environments,
+	// the live call frames, and the caches.
+	Objects      int
+	Environments int
+	// Packages is the number of loaded package objects.
+	Packages int
+
+	CachedFunctions        int // entries of the function cache
+	MemoizedResults        int // entries of the memoization cache
+	CalledInterfaceMethods int // distinct interface methods called
+	CallStackDepth         int // number of live call frames
+
+	// Prunes is the number of pruning passes run so far, and PrunedEntries the number
+	// of cache entries they dropped.
+	Prunes        int
+	PrunedEntries int
+
+	// HeapAlloc is the number of bytes of allocated heap objects, as reported by the runtime.
+	HeapAlloc uint64
+	// MemoryBudget is the configured memory budget in bytes, or 0 if unlimited.
+	MemoryBudget uint64
+}
+
+// WithMemoryBudget sets a soft memory budget in bytes. When the heap grows beyond it,
+// the evaluator periodically prunes its caches of the entries that retain environments
+// unreachable from the live frames.
+func WithMemoryBudget(bytes uint64) Option {
+	return func(e *Evaluator) {
+		e.memoryBudget = bytes
+	}
+}
+
+// WithRootEnvironment registers an environment owned by the caller (e.g. the interpreter's
+// global environment) as a root, so that pruning keeps everything reachable from it.
+func WithRootEnvironment(env *object.Environment) Option {
+	return func(e *Evaluator) {
+		e.rootEnvs = append(e.rootEnvs, env)
+	}
+}
+
+// checkMemoryBudget prunes the caches if the heap has grown beyond the memory budget.
+// It is called after each function application, and checks every memoryCheckInterval calls.
+func (e *Evaluator) checkMemoryBudget(ctx context.Context) {
+	if e.memoryBudget == 0 {
+		return
+	}
+	e.applyCount++
+	if e.applyCount%memoryCheckInterval != 0 {
+		return
+	}
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	if ms.HeapAlloc <= e.memoryBudget {
+		return
+	}
+	pruned := e.Prune(ctx)
+	e.logc(ctx, slog.LevelDebug, "memory budget exceeded, pruned caches", "heap_alloc", ms.HeapAlloc, "budget", e.memoryBudget, "pruned", pruned)
+}
+
+// Prune drops the cache entries that retain environments unreachable from the live frames,
+// the loaded packages and the root environments, so that the garbage collector can reclaim them.
+// The caches only avoid re-evaluation, so pruning never changes the analysis, but pruned
+// functions may be evaluated again. It returns the number of dropped entries.
+func (e *Evaluator) Prune(ctx context.Context) int {
+	live := newReachability()
+	e.markRoots(live)
+
+	pruned := 0
+	for key, result := range e.memoizationCache {
+		if live.retainsDeadEnv(result) {
+			delete(e.memoizationCache, key)
+			pruned++
+		}
+	}
+	for key, result := range e.recursionSummaries {
+		if live.retainsDeadEnv(result) {
+			delete(e.recursionSummaries, key)
+			pruned++
+		}
+	}
+	for key, fn := range e.funcCache {
+		if live.retainsDeadEnv(fn) {
+			delete(e.funcCache, key)
+			pruned++
+		}
+	}
+	for key, receivers := range e.calledInterfaceMethods {
+		// The key records that the method was called; only the receivers are dropped.
+		kept := receivers[:0]
+		for _, recv := range receivers {
+			if live.retainsDeadEnv(recv) {
+				pruned++
+				continue
+			}
+			kept = append(kept, recv)
+		}
+		e.calledInterfaceMethods[key] = kept
+	}
+
+	e.prunes++
+	e.prunedEntries += pruned
+	e.logc(ctx, slog.LevelDebug, "pruned unreachable cache entries", "pruned", pruned, "live_envs", len(live.envs))
+	return pruned
+}
+
+// Stats returns a snapshot of the evaluator's memory-related telemetry.
+// Counting the retained objects walks the whole object graph, so this is meant for
+// tuning and diagnostics rather than for frequent calls.
+func (e *Evaluator) Stats() Stats {
+	r := newReachability()
+	e.markRoots(r)
+	for _, result := range e.memoizationCache {
+		r.markObject(result)
+	}
+	for _, result := range e.recursionSummaries {
+		r.markObject(result)
+	}
+	for _, fn := range e.funcCache {
+		r.markObject(fn)
+	}
+	for _, receivers := range e.calledInterfaceMethods {
+		for _, recv := range receivers {
+			r.markObject(recv)
+		}
+	}
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return Stats{
+		Objects:                len(r.objects),
+		Environments:           len(r.envs),
+		Packages:               len(e.pkgCache),
+		CachedFunctions:        len(e.funcCache),
+		MemoizedResults:        len(e.memoizationCache),
+		CalledInterfaceMethods: len(e.calledInterfaceMethods),
+		CallStackDepth:         len(e.callStack),
+		Prunes:                 e.prunes,
+		PrunedEntries:          e.prunedEntries,
+		HeapAlloc:              ms.HeapAlloc,
+		MemoryBudget:           e.memoryBudget,
+	}
+}
+
+// markRoots marks everything reachable from the live state of the evaluator, excluding the caches.
+func (e *Evaluator) markRoots(r *reachability) {
+	r.markEnv(e.UniverseEnv)
+	for _, env := range e.rootEnvs {
+		r.markEnv(env)
+	}
+	for _, pkg := range e.pkgCache {
+		r.markObject(pkg)
+	}
+	for _, frame := range e.callStack {
+		r.markFrame(frame)
+	}
+}
+
+// reachability is the set of objects and environments reachable from a set of roots.
+type reachability struct {
+	objects map[object.Object]struct{}
+	envs    map[*object.Environment]struct{}
+}
+
+func newReachability() *reachability {
+	return &reachability{
+		objects: make(map[object.Object]struct{}),
+		envs:    make(map[*object.Environment]struct{}),
+	}
+}
+
+func (r *reachability) markEnv(env *object.Environment) {
+	r.walk(nil, env, nil)
+}
+
+func (r *reachability) markObject(obj object.Object) {
+	r.walk(obj, nil, nil)
+}
+
+func (r *reachability) markFrame(frame *object.CallFrame) {
+	if frame.Fn != nil {
+		r.markObject(frame.Fn)
+	}
+	for _, arg := range frame.Args {
+		r.markObject(arg)
+	}
+}
+
+// retainsDeadEnv reports whether the object retains an environment that is not in the set.
+// The set itself is not modified.
+func (r *reachability) retainsDeadEnv(obj object.Object) bool {
+	dead := false
+	seen := newReachability()
+	seen.walk(obj, nil, func(env *object.Environment) bool {
+		if _, ok := r.envs[env]; ok {
+			return false // live, and so is everything reachable from it
+		}
+		dead = true
+		return false
+	})
+	return dead
+}
+
+// walk marks the object graph starting at obj and env. If visitEnv is given, it is called
+// for each new environment, and the environment is only traversed if it returns true.
+// The walk is iterative, as environment chains and object graphs can be deep.
+func (r *reachability) walk(obj object.Object, env *object.Environment, visitEnv func(*object.Environment) bool) {
+	var objs []object.Object
+	var envs []*object.Environment
+	if obj != nil {
+		objs = append(objs, obj)
+	}
+	if env != nil {
+		envs = append(envs, env)
+	}
+
+	for len(objs) > 0 || len(envs) > 0 {
+		if n := len(envs); n > 0 {
+			env := envs[n-1]
+			envs = envs[:n-1]
+			if _, ok := r.envs[env]; ok {
+				continue
+			}
+			r.envs[env] = struct{}{}
+			if visitEnv != nil && !visitEnv(env) {
+				continue
+			}
+			env.WalkLocal(func(_ string, o object.Object) bool {
+				objs = append(objs, o)
+				return true
+			})
+			if outer := env.Outer(); outer != nil {
+				envs = append(envs, outer)
+			}
+			continue
+		}
+
+		n := len(objs)
+		o := objs[n-1]
+		objs = objs[:n-1]
+		if o == nil {
+			continue
+		}
+		if v := reflect.ValueOf(o); v.Kind() == reflect.Ptr && v.IsNil() {
+			continue // a typed nil, e.g. a (*object.Function)(nil) receiver
+		}
+		if _, ok := r.objects[o]; ok {
+			continue
+		}
+		r.objects[o] = struct{}{}
+
+		switch o := o.(type) {
+		case *object.Function:
+			if o.Env != nil {
+				envs = append(envs, o.Env)
+			}
+			objs = append(objs, o.Receiver)
+			for _, frame := range o.BoundCallStack {
+				if frame.Fn != nil {
+					objs = append(objs, frame.Fn)
+				}
+				objs = append(objs, frame.Args...)
+			}
+		case *object.InstantiatedFunction:
+			if o.Function != nil {
+				objs = append(objs, o.Function)
+			}
+		case *object.Package:
+			if o.Env != nil {
+				envs = append(envs, o.Env)
+			}
+		case *object.Instance:
+			for _, v := range o.State {
+				objs = append(objs, v)
+			}
+			objs = append(objs, o.Underlying)
+		case *object.SymbolicPlaceholder:
+			objs = append(objs, o.Receiver)
+		case *object.AmbiguousSelector:
+			objs = append(objs, o.Receiver)
+		case *object.ReturnValue:
+			objs = append(objs, o.Value)
+		case *object.Variable:
+			objs = append(objs, o.Value)
+		case *object.Pointer:
+			objs = append(objs, o.Value)
+		case *object.Variadic:
+			objs = append(objs, o.Value)
+		case *object.PanicError:
+			objs = append(objs, o.Value)
+		case *object.Struct:
+			for _, v := range o.Fields {
+				objs = append(objs, v)
+			}
+		case *object.Slice:
+			objs = append(objs, o.Elements...)
+		case *object.Map:
+			for k, v := range o.Pairs {
+				objs = append(objs, k, v)
+			}
+		case *object.MultiReturn:
+			objs = append(objs, o.Values...)
+		}
+	}
+}
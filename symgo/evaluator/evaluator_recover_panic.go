@@ -0,0 +1,63 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"log/slog"
+	"runtime/debug"
+
+	scan "github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// evalPanic is a panic of the evaluator on its way to the outermost Eval or Apply, carrying
+// what is known where it was raised: the Go stack, the innermost node being evaluated, and
+// the call stack, which is unwound by the time the panic is recovered.
+type evalPanic struct {
+	value  any
+	stack  []byte
+	pos    token.Pos
+	frames []*object.CallFrame
+}
+
+// recoverPanic is deferred by Eval and Apply. A panic of the evaluator (a bug of symgo or of
+// an intrinsic) is captured by the innermost call and unwinds the nested ones, running their
+// deferred cleanups, then the outermost call returns it as an *object.Error, so that a bad
+// function does not stop the analysis of the other ones.
+func (e *Evaluator) recoverPanic(ctx context.Context, node ast.Node, pkg *scan.PackageInfo, result *object.Object) {
+	e.depth--
+	r := recover()
+	if r == nil {
+		return
+	}
+	p, ok := r.(*evalPanic)
+	if !ok {
+		p = &evalPanic{value: r, stack: debug.Stack(), frames: e.CallStack()}
+		if node != nil {
+			p.pos = node.Pos()
+		}
+	}
+	if e.depth > 0 {
+		panic(p)
+	}
+
+	err := &object.Error{
+		Message:   fmt.Sprintf("internal error: %v", p.value),
+		Pos:       p.pos,
+		CallStack: p.frames,
+		Panic:     p.value,
+		GoStack:   p.stack,
+	}
+	if e.scanner != nil {
+		err.AttachFileSet(e.scanner.Fset())
+	}
+	posStr := fmt.Sprintf("%d", p.pos)
+	if e.scanner != nil && e.scanner.Fset() != nil && p.pos.IsValid() {
+		posStr = e.scanner.Fset().Position(p.pos).String()
+	}
+	e.logc(ctx, slog.LevelError, "recovered from a panic of the evaluator", "pos", posStr, "panic", fmt.Sprint(p.value), "stack", err.Inspect(), "go_stack", string(p.stack))
+	e.traceEvent(object.TraceErrorRecovered, p.pos, pkg, nil, err)
+	*result = err
+}
@@ -0,0 +1,191 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"log/slog"
+	"strings"
+
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// recursionKey identifies a recursive cycle: a function (by the position of its declaration
+// or literal) called on a receiver of a given type. The function values passed as arguments
+// are part of the key, as they decide which functions the cycle calls.
+type recursionKey struct {
+	pos      token.Pos
+	receiver string
+	funcArgs string
+}
+
+// WithRecursionWidening enables or disables the widening of recursive calls (enabled by default).
+//
+// When a function is called again while it is already on the call stack with a receiver of the
+// same type, its body is evaluated once more with the arguments widened to symbolic values, and
+// the result is reused as the summary of the cycle for all the later recursive calls. Without
+// widening, the recursive call is not evaluated at all and returns a placeholder, so the functions
+// called only from the deeper levels of a (mutually) recursive cycle are missed.
+func WithRecursionWidening(enabled bool) Option {
+	return func(e *Evaluator) {
+		e.recursionWidening = enabled
+	}
+}
+
+// isRecursiveCall reports whether f is already on the call stack with a receiver of the same type.
+func (e *Evaluator) isRecursiveCall(f *object.Function) bool {
+	// If the function has a BoundCallStack, it means it was passed as an argument,
+	// and that stack represents the true logical path leading to this call.
+	stackToScan := e.callStack
+	if f.BoundCallStack != nil {
+		stackToScan = f.BoundCallStack
+	}
+
+	for _, frame := range stackToScan {
+		if frame.Fn == nil {
+			continue
+		}
+
+		// Case 1: Named function with a definition. Compare declaration positions.
+		if f.Def != nil && f.Def.AstDecl != nil && frame.Fn.Def != nil && frame.Fn.Def.AstDecl != nil {
+			if f.Def.AstDecl.Pos() == frame.Fn.Def.AstDecl.Pos() && receiverTypeKey(f) == receiverTypeKey(frame.Fn) {
+				return true
+			}
+			continue
+		}
+
+		// Case 2: Anonymous function (function literal). Compare literal positions.
+		if f.Lit != nil && frame.Fn.Lit != nil {
+			if f.Lit.Pos() == frame.Fn.Lit.Pos() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyRecursiveCall handles a recursive call of f. It returns the result of the call and true if
+// the call must not be evaluated, or the widened arguments and false if the body is to be evaluated
+// as the summary of the cycle; finish must then be called with the result.
+func (e *Evaluator) applyRecursiveCall(ctx context.Context, f *object.Function, name string, args []object.Object) (result object.Object, widened []object.Object, finish func(object.Object), done bool) {
+	if !e.recursionWidening {
+		e.logc(ctx, slog.LevelDebug, "bounded recursion depth exceeded, halting analysis for this path", "function", name)
+		return recursionHalt(f), nil, nil, true
+	}
+
+	key := recursionKeyOf(f, args)
+	if summary, ok := e.recursionSummaries[key]; ok {
+		e.logc(ctx, slog.LevelDebug, "reusing summary of recursive cycle", "function", name)
+		return summary, nil, nil, true
+	}
+	if e.widening[key] {
+		// The widened call is being evaluated: this is the fixpoint of the cycle.
+		e.logc(ctx, slog.LevelDebug, "recursive cycle already widened, halting analysis for this path", "function", name)
+		return recursionHalt(f), nil, nil, true
+	}
+
+	e.logc(ctx, slog.LevelDebug, "widening arguments of recursive call", "function", name)
+	if e.widening == nil {
+		e.widening = make(map[recursionKey]bool)
+	}
+	e.widening[key] = true
+	finish = func(result object.Object) {
+		delete(e.widening, key)
+		if isError(result) {
+			return
+		}
+		if e.recursionSummaries == nil {
+			e.recursionSummaries = make(map[recursionKey]object.Object)
+		}
+		e.recursionSummaries[key] = result
+	}
+	return nil, widenArgs(args), finish, false
+}
+
+// recursionHalt returns a symbolic placeholder that matches the function's return signature.
+func recursionHalt(f *object.Function) object.Object {
+	if f.Def != nil && f.Def.AstDecl.Type.Results != nil {
+		numResults := len(f.Def.AstDecl.Type.Results.List)
+		if numResults > 1 {
+			results := make([]object.Object, numResults)
+			for i := 0; i < numResults; i++ {
+				results[i] = &object.SymbolicPlaceholder{Reason: "bounded recursion halt"}
+			}
+			return &object.MultiReturn{Values: results}
+		}
+	}
+	// Default to a single placeholder if signature is not available or has <= 1 return values.
+	return &object.SymbolicPlaceholder{Reason: "bounded recursion halt"}
+}
+
+// widenArgs replaces the arguments of a recursive call with symbolic values of the same types.
+// Function values are kept, as they are needed to follow the calls made through them.
+func widenArgs(args []object.Object) []object.Object {
+	widened := make([]object.Object, len(args))
+	for i, arg := range args {
+		switch v := unwrapVariable(arg).(type) {
+		case *object.Function, *object.InstantiatedFunction, *object.Intrinsic, *object.SymbolicPlaceholder:
+			widened[i] = v
+		default:
+			placeholder := &object.SymbolicPlaceholder{Reason: fmt.Sprintf("widened argument of recursive call: %s", arg.Inspect())}
+			placeholder.SetTypeInfo(arg.TypeInfo())
+			placeholder.SetFieldType(arg.FieldType())
+			widened[i] = placeholder
+		}
+	}
+	return widened
+}
+
+// recursionKeyOf returns the key of the recursive cycle entered by calling f with args.
+func recursionKeyOf(f *object.Function, args []object.Object) recursionKey {
+	key := recursionKey{receiver: receiverTypeKey(f)}
+	switch {
+	case f.Decl != nil:
+		key.pos = f.Decl.Pos()
+	case f.Lit != nil:
+		key.pos = f.Lit.Pos()
+	}
+
+	var funcArgs []string
+	for _, arg := range args {
+		switch v := unwrapVariable(arg).(type) {
+		case *object.Function:
+			switch {
+			case v.Decl != nil:
+				funcArgs = append(funcArgs, fmt.Sprintf("%d/%s", v.Decl.Pos(), receiverTypeKey(v)))
+			case v.Lit != nil:
+				funcArgs = append(funcArgs, fmt.Sprintf("%d", v.Lit.Pos()))
+			}
+		case *object.InstantiatedFunction:
+			funcArgs = append(funcArgs, v.Inspect())
+		}
+	}
+	key.funcArgs = strings.Join(funcArgs, ",")
+	return key
+}
+
+// receiverTypeKey returns the type of the receiver of a method, e.g. "*example.com/me.Node",
+// or "" for a function.
+func receiverTypeKey(f *object.Function) string {
+	if f.Receiver == nil {
+		return ""
+	}
+	recv := unwrapVariable(f.Receiver)
+	if ft := recv.FieldType(); ft != nil {
+		return ft.String()
+	}
+	if ti := recv.TypeInfo(); ti != nil {
+		return ti.PkgPath + "." + ti.Name
+	}
+	return ""
+}
+
+func unwrapVariable(obj object.Object) object.Object {
+	for {
+		v, ok := obj.(*object.Variable)
+		if !ok || v.Value == nil {
+			return obj
+		}
+		obj = v.Value
+	}
+}
@@ -0,0 +1,164 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"log/slog"
+	"sort"
+	"strings"
+
+	scan "github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// WithReflectAllMethods sets how the methods called through reflection with a name that is not
+// a constant (e.g. `v.MethodByName(name)`) are handled. When enabled, all the exported methods
+// of the reflected type are considered called, or only the ones matching the known parts of the
+// name (e.g. `"Handle" + name`). When disabled (the default), no method is considered called.
+// A method called with a constant name is always considered called.
+func WithReflectAllMethods(enabled bool) Option {
+	return func(e *Evaluator) {
+		e.reflectAllMethods = enabled
+	}
+}
+
+// registerReflectIntrinsics registers the intrinsics modeling the calls of the methods through
+// reflection, so that the methods looked up by name are not missing from the call graph.
+func (e *Evaluator) registerReflectIntrinsics() {
+	e.intrinsics.Register("reflect.ValueOf", func(ctx context.Context, args ...object.Object) object.Object {
+		v := &object.SymbolicPlaceholder{Reason: "result of reflect.ValueOf"}
+		v.SetTypeInfo(scan.NewUnresolvedTypeInfo("reflect", "Value"))
+		if len(args) > 0 {
+			v.Reflected = args[0] // a variable keeps its declared type
+		}
+		return v
+	})
+	e.intrinsics.Register("(reflect.Value).MethodByName", func(ctx context.Context, args ...object.Object) object.Object {
+		if len(args) == 2 {
+			if self, ok := args[0].(*object.SymbolicPlaceholder); ok && self.Reflected != nil {
+				e.markReflectedMethods(ctx, self.Reflected, unwrapVariable(args[1]))
+			}
+		}
+		v := &object.SymbolicPlaceholder{Reason: "result of reflect.Value.MethodByName"}
+		v.SetTypeInfo(scan.NewUnresolvedTypeInfo("reflect", "Value"))
+		return v
+	})
+}
+
+// markReflectedMethods marks the methods of the reflected value selected by name as used, and
+// scans their bodies, as `MethodByName(name).Call(...)` would call them.
+func (e *Evaluator) markReflectedMethods(ctx context.Context, reflected, name object.Object) {
+	typeInfo := e.reflectedType(ctx, reflected)
+	if typeInfo == nil || typeInfo.Unresolved {
+		return
+	}
+
+	var names []string
+	switch name := name.(type) {
+	case *object.String:
+		names = []string{name.Value}
+	case *object.StringTemplate:
+		if !e.reflectAllMethods {
+			return
+		}
+		for _, n := range e.exportedMethodNames(ctx, typeInfo) {
+			if matchesTemplate(n, name) {
+				names = append(names, n)
+			}
+		}
+	default:
+		if !e.reflectAllMethods {
+			return
+		}
+		names = e.exportedMethodNames(ctx, typeInfo)
+	}
+
+	for _, n := range names {
+		method, err := e.accessor.findMethodOnType(ctx, typeInfo, n, nil, reflected, token.NoPos)
+		if err != nil || method == nil {
+			e.logc(ctx, slog.LevelDebug, "method called through reflection not found", "type", typeInfo.Name, "method", n, "error", err)
+			continue
+		}
+		e.logc(ctx, slog.LevelDebug, "marking method called through reflection as used", "method", fmt.Sprintf("%s.%s", typeInfo.Name, n))
+		if e.defaultIntrinsic != nil {
+			e.defaultIntrinsic(ctx, method)
+		}
+		e.scanFunctionLiteral(ctx, method)
+	}
+}
+
+// reflectedType returns the type of the reflected value, looking through the variables and pointers.
+func (e *Evaluator) reflectedType(ctx context.Context, obj object.Object) *scan.TypeInfo {
+	for obj != nil {
+		if ti := obj.TypeInfo(); ti != nil {
+			return ti
+		}
+		if ft := obj.FieldType(); ft != nil {
+			if ft.IsPointer && ft.Elem != nil {
+				ft = ft.Elem
+			}
+			return e.resolver.ResolveType(ctx, ft)
+		}
+		switch o := obj.(type) {
+		case *object.Variable:
+			obj = o.Value
+		case *object.Pointer:
+			obj = o.Pointee()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// exportedMethodNames returns the sorted names of the exported methods declared for the type.
+// The methods promoted from the embedded fields are not included.
+func (e *Evaluator) exportedMethodNames(ctx context.Context, typeInfo *scan.TypeInfo) []string {
+	pkgObj, err := e.getOrLoadPackage(ctx, typeInfo.PkgPath)
+	if err != nil || pkgObj == nil || pkgObj.ScannedInfo == nil {
+		return nil
+	}
+	var names []string
+	for _, fn := range pkgObj.ScannedInfo.Functions {
+		if fn.Receiver == nil || !ast.IsExported(fn.Name) {
+			continue
+		}
+		recvTypeName := fn.Receiver.Type.TypeName
+		if recvTypeName == "" {
+			recvTypeName = fn.Receiver.Type.Name
+		}
+		if strings.TrimPrefix(recvTypeName, "*") == typeInfo.Name {
+			names = append(names, fn.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// matchesTemplate reports whether s can be a value of the string template, i.e. whether its
+// constant parts appear in s in order, the first one as a prefix and the last one as a suffix.
+func matchesTemplate(s string, t *object.StringTemplate) bool {
+	for i, part := range t.Parts {
+		if !part.Const {
+			continue
+		}
+		switch {
+		case i == 0:
+			if !strings.HasPrefix(s, part.Value) {
+				return false
+			}
+			s = s[len(part.Value):]
+		case i == len(t.Parts)-1:
+			return strings.HasSuffix(s, part.Value)
+		default:
+			j := strings.Index(s, part.Value)
+			if j < 0 {
+				return false
+			}
+			s = s[j+len(part.Value):]
+		}
+	}
+	return true
+}
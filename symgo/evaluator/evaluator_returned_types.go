@@ -0,0 +1,223 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"log/slog"
+	"slices"
+
+	scan "github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// returnSummary is the summary of a function: the concrete types it returns for each of
+// its interface-typed results, collected from every evaluated return statement.
+//
+// Both branches of an if statement are evaluated, but only the value of the last return
+// statement becomes the result of the call, so a constructor like
+//
+//	func New(debug bool) Reader {
+//		if debug {
+//			return &debugFile{}
+//		}
+//		return &file{}
+//	}
+//
+// would otherwise be seen to return a *file only. With the summary, the result of the call
+// is an interface value carrying all the types it may hold, and the interface method calls
+// on it are bound to the methods of these types (see applyToPossibleTypes).
+type returnSummary map[int][]*scan.FieldType // result index -> concrete types
+
+// recordReturnedTypes adds the concrete types of the returned values to the summary of
+// the function being evaluated.
+func (e *Evaluator) recordReturnedTypes(ctx context.Context, vals []object.Object) {
+	if len(e.callStack) == 0 {
+		return
+	}
+	fn := e.callStack[len(e.callStack)-1].Fn
+	if fn == nil || fn.Decl == nil || fn.Def == nil || len(fn.Def.Results) != len(vals) {
+		return // function literals and unknown signatures are not summarized
+	}
+
+	for i, val := range vals {
+		types := concreteTypesOf(val)
+		if len(types) == 0 {
+			continue
+		}
+		if !e.isInterfaceType(ctx, fn.Def.Results[i].Type) {
+			continue
+		}
+		if e.returnSummaries == nil {
+			e.returnSummaries = make(map[token.Pos]returnSummary)
+		}
+		summary := e.returnSummaries[fn.Decl.Pos()]
+		if summary == nil {
+			summary = make(returnSummary)
+			e.returnSummaries[fn.Decl.Pos()] = summary
+		}
+		summary[i] = addConcreteTypes(summary[i], types...)
+	}
+}
+
+// bindReturnedTypes replaces the interface-typed results of a call to fn with interface values
+// carrying all the concrete types fn may return. Nil results are kept as is.
+func (e *Evaluator) bindReturnedTypes(ctx context.Context, fn *object.Function, result object.Object) object.Object {
+	if fn.Decl == nil || fn.Def == nil {
+		return result
+	}
+	summary, ok := e.returnSummaries[fn.Decl.Pos()]
+	if !ok {
+		return result
+	}
+
+	bind := func(i int, val object.Object) object.Object {
+		types := summary[i]
+		if len(types) == 0 || val == object.NIL {
+			return val
+		}
+		declared := fn.Def.Results[i].Type
+		placeholder := &object.SymbolicPlaceholder{
+			Reason:                fmt.Sprintf("interface value returned by %s", fn.Def.Name),
+			PossibleConcreteTypes: types,
+		}
+		placeholder.SetFieldType(declared)
+		placeholder.SetTypeInfo(e.resolver.ResolveType(ctx, declared))
+		return placeholder
+	}
+
+	switch v := result.(type) {
+	case *object.MultiReturn:
+		if len(v.Values) != len(fn.Def.Results) {
+			return result
+		}
+		values := make([]object.Object, len(v.Values))
+		for i, val := range v.Values {
+			values[i] = bind(i, val)
+		}
+		return &object.MultiReturn{Values: values}
+	default:
+		if len(fn.Def.Results) != 1 {
+			return result
+		}
+		return bind(0, result)
+	}
+}
+
+// applyToPossibleTypes applies the methods of the possible concrete types of the receiver of
+// an interface method call, so that their bodies are evaluated as if they were called directly.
+func (e *Evaluator) applyToPossibleTypes(ctx context.Context, fn *object.SymbolicPlaceholder, args []object.Object, pkg *scan.PackageInfo, callPos token.Pos) {
+	for _, ft := range fn.PossibleConcreteTypes {
+		ti := ft.Definition
+		if ti == nil {
+			continue
+		}
+		receiver := &object.SymbolicPlaceholder{Reason: fmt.Sprintf("symbolic receiver of type %s", ft.String())}
+		receiver.SetTypeInfo(ti)
+		receiver.SetFieldType(ft)
+
+		method, err := e.accessor.findMethodOnType(ctx, ti, fn.UnderlyingFunc.Name, nil, receiver, callPos)
+		if err != nil || method == nil {
+			e.logc(ctx, slog.LevelDebug, "method not found on possible concrete type", "type", ft.String(), "method", fn.UnderlyingFunc.Name, "error", err)
+			continue
+		}
+		if e.defaultIntrinsic != nil {
+			e.defaultIntrinsic(ctx, append([]object.Object{method}, args...)...)
+		}
+		if result := e.applyFunction(ctx, method, args, pkg, callPos); isError(result) {
+			e.logc(ctx, slog.LevelWarn, "failed to apply method of possible concrete type", "type", ft.String(), "method", fn.UnderlyingFunc.Name, "error", result)
+			e.traceEvent(object.TraceErrorRecovered, callPos, pkg, method, result)
+		}
+	}
+}
+
+// possibleTypesOf returns the possible concrete types of an interface value: the types
+// returned by a function (see bindReturnedTypes), or assigned to an interface-typed variable
+// along the evaluated paths. It returns nil for any other value.
+func possibleTypesOf(val object.Object) []*scan.FieldType {
+	switch v := val.(type) {
+	case *object.Variable:
+		return addConcreteTypes(slices.Clone(v.PossibleConcreteTypes), possibleTypesOf(v.Value)...)
+	case *object.ReturnValue:
+		return possibleTypesOf(v.Value)
+	case *object.SymbolicPlaceholder:
+		return v.PossibleConcreteTypes
+	}
+	return nil
+}
+
+// isInterfaceType reports whether the type resolves to an interface.
+func (e *Evaluator) isInterfaceType(ctx context.Context, ft *scan.FieldType) bool {
+	if ft == nil || ft.IsPointer || ft.IsSlice || ft.IsMap || ft.IsChan {
+		return false
+	}
+	ti := e.resolver.ResolveType(ctx, ft)
+	return ti != nil && ti.Kind == scan.InterfaceKind
+}
+
+// concreteTypesOf returns the types a value may have at run time: the type of a struct
+// value or of a pointer to it, or the possible types of an interface value (see possibleTypesOf).
+// It returns nil if unknown.
+func concreteTypesOf(val object.Object) []*scan.FieldType {
+	switch v := val.(type) {
+	case *object.Variable:
+		return addConcreteTypes(slices.Clone(v.PossibleConcreteTypes), concreteTypesOf(v.Value)...)
+	case *object.ReturnValue:
+		return concreteTypesOf(v.Value)
+	case *object.SymbolicPlaceholder:
+		return v.PossibleConcreteTypes
+	case *object.Pointer:
+		switch v.Value.(type) {
+		case *object.Instance, *object.Struct:
+		default:
+			return nil
+		}
+		elem := concreteTypesOf(v.Value)
+		if len(elem) != 1 {
+			return nil
+		}
+		return []*scan.FieldType{{IsPointer: true, Elem: elem[0], Definition: elem[0].Definition}}
+	case *object.Instance, *object.Struct:
+		ti := v.TypeInfo()
+		if ti == nil || ti.Name == "" || ti.Kind == scan.InterfaceKind || ti.Unresolved {
+			return nil
+		}
+		return []*scan.FieldType{{
+			Name:           ti.Name,
+			TypeName:       ti.Name,
+			FullImportPath: ti.PkgPath,
+			Definition:     ti,
+		}}
+	}
+	return nil
+}
+
+// addConcreteTypes adds the types to the set, which is kept without duplicates.
+func addConcreteTypes(set []*scan.FieldType, types ...*scan.FieldType) []*scan.FieldType {
+	for _, ft := range types {
+		key := concreteTypeKey(ft)
+		found := false
+		for _, existing := range set {
+			if concreteTypeKey(existing) == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			set = append(set, ft)
+		}
+	}
+	return set
+}
+
+// concreteTypeKey returns the identity of a concrete type, e.g. "*example.com/me.file".
+func concreteTypeKey(ft *scan.FieldType) string {
+	if ft.Definition == nil {
+		return ft.String()
+	}
+	key := ft.Definition.PkgPath + "." + ft.Definition.Name
+	if ft.IsPointer {
+		return "*" + key
+	}
+	return key
+}
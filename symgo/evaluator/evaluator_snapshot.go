@@ -0,0 +1,56 @@
+package evaluator
+
+import (
+	"maps"
+
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// Snapshot is a saved state of the evaluator, for speculative evaluation: take a snapshot,
+// evaluate a path (e.g. one branch of a feature-flag check), then restore it to evaluate
+// another path from the same state.
+//
+// The environments and variables are saved copy-on-write by an object.Journal, so taking
+// a snapshot is cheap and the cost of evaluating under it grows with what is modified.
+// The caches (function cache, memoization, called interface methods) are not rolled back,
+// as they only avoid re-evaluation, but the packages loaded after the snapshot are dropped,
+// so that their package-level state is initialized again.
+type Snapshot struct {
+	heap        *object.Snapshot
+	pkgs        map[string]*object.Package
+	initialized map[string]bool
+	callStack   []*object.CallFrame
+}
+
+// Snapshot captures the current state of the evaluator.
+func (e *Evaluator) Snapshot() *Snapshot {
+	return &Snapshot{
+		heap:        e.journal.Snapshot(),
+		pkgs:        maps.Clone(e.pkgCache),
+		initialized: maps.Clone(e.initializedPkgs),
+		callStack:   append([]*object.CallFrame(nil), e.callStack...),
+	}
+}
+
+// Restore rolls the evaluator back to the state captured by s. The snapshot stays valid,
+// so it can be restored again, but the snapshots taken after it are released.
+func (e *Evaluator) Restore(s *Snapshot) {
+	e.journal.Restore(s.heap)
+	for path := range e.pkgCache {
+		if _, ok := s.pkgs[path]; !ok {
+			delete(e.pkgCache, path)
+		}
+	}
+	for path := range e.initializedPkgs {
+		if !s.initialized[path] {
+			delete(e.initializedPkgs, path)
+		}
+	}
+	e.callStack = append(e.callStack[:0], s.callStack...)
+}
+
+// Release stops tracking the writes for s, which makes the evaluation cheaper once the
+// snapshot is no longer needed. It is no longer possible to restore it.
+func (e *Evaluator) Release(s *Snapshot) {
+	e.journal.Release(s.heap)
+}
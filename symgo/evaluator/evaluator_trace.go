@@ -0,0 +1,104 @@
+package evaluator
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+
+	scan "github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// traceEvent sends an event of the given kind to the tracer, if any.
+// The events of the evaluated nodes are sent by Eval itself.
+// The events are also recorded for the coverage, if enabled.
+func (e *Evaluator) traceEvent(kind object.TraceEventKind, pos token.Pos, pkg *scan.PackageInfo, fn object.Object, obj object.Object) {
+	e.recordCoverageEvent(kind, pos, fn)
+	if e.tracer == nil {
+		return
+	}
+	e.tracer.Trace(object.TraceEvent{
+		Kind:     kind,
+		Step:     e.step,
+		Pkg:      pkg,
+		Pos:      pos,
+		Function: fn,
+		Object:   obj,
+	})
+}
+
+// traceFieldAccess sends a TraceFieldAccessed event if the selector selects a field of the struct
+// type of the receiver, with the struct declaring the field. The receiver is the evaluated
+// left-hand side of the selector, or the type of the variable when it is already known.
+func (e *Evaluator) traceFieldAccess(ctx context.Context, sel *ast.SelectorExpr, receiver object.Object, receiverType *scan.TypeInfo, pkg *scan.PackageInfo) {
+	if e.tracer == nil {
+		return
+	}
+	if receiverType == nil {
+		receiverType = selectedTypeInfo(receiver)
+	}
+	if receiverType == nil || receiverType.Struct == nil {
+		return
+	}
+	field, owner, err := e.accessor.findFieldOwner(ctx, receiverType, sel.Sel.Name)
+	if err != nil || field == nil {
+		return
+	}
+	e.tracer.Trace(object.TraceEvent{
+		Kind:  object.TraceFieldAccessed,
+		Step:  e.step,
+		Pkg:   pkg,
+		Pos:   sel.Sel.Pos(),
+		Type:  owner,
+		Field: field,
+	})
+}
+
+// selectedTypeInfo returns the type of the value a selector is applied to, the pointee for a pointer.
+func selectedTypeInfo(obj object.Object) *scan.TypeInfo {
+	for obj != nil {
+		switch o := obj.(type) {
+		case *object.ReturnValue:
+			obj = o.Value
+		case *object.Pointer:
+			if pointee := o.Pointee(); pointee != nil && pointee.TypeInfo() != nil {
+				return pointee.TypeInfo()
+			}
+			return o.TypeInfo()
+		case *object.Variable:
+			if ti := o.TypeInfo(); ti != nil {
+				return ti
+			}
+			obj = o.Value
+		default:
+			return obj.TypeInfo()
+		}
+	}
+	return nil
+}
+
+// traceCallResult sends a TracePlaceholderCreated event if the result of a call is symbolic.
+func (e *Evaluator) traceCallResult(pos token.Pos, pkg *scan.PackageInfo, fn object.Object, result object.Object) {
+	if e.tracer == nil {
+		return
+	}
+	if ret, ok := result.(*object.ReturnValue); ok {
+		result = ret.Value
+	}
+	if placeholder, ok := result.(*object.SymbolicPlaceholder); ok {
+		e.traceEvent(object.TracePlaceholderCreated, pos, pkg, fn, placeholder)
+	}
+}
+
+// funcPos returns the position of the declaration or the literal of a function.
+func funcPos(fn *object.Function) token.Pos {
+	switch {
+	case fn.Decl != nil:
+		return fn.Decl.Pos()
+	case fn.Lit != nil:
+		return fn.Lit.Pos()
+	case fn.Body != nil:
+		return fn.Body.Pos()
+	}
+	return token.NoPos
+}
@@ -0,0 +1,141 @@
+package evaluator
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// UnresolvedReason tells why the result of a call degraded to an untyped placeholder.
+type UnresolvedReason string
+
+const (
+	// UnresolvedOutOfPolicy is a call to a function of a package outside of the scan policy,
+	// whose result types could not be found without scanning it.
+	UnresolvedOutOfPolicy UnresolvedReason = "out-of-policy"
+	// UnresolvedUnsupported is a call of an expression which is not evaluated to a function,
+	// e.g. an element of a map or a field of a value of unknown type.
+	UnresolvedUnsupported UnresolvedReason = "unsupported"
+	// UnresolvedInferenceFailure is a call to a known function whose result types could not be
+	// inferred, e.g. because its signature was not found or the call stack was too deep.
+	UnresolvedInferenceFailure UnresolvedReason = "inference-failure"
+)
+
+// UnresolvedCall is a call site whose result degraded to an untyped placeholder, so that the
+// calls made on the result are not traced.
+type UnresolvedCall struct {
+	Pos    token.Position
+	Callee string // the called expression, e.g. "client.Do"
+	Reason UnresolvedReason
+	Detail string // the reason of the placeholder
+}
+
+// recordUnresolvedCall records the call site if its result is an untyped placeholder. Each call
+// site is recorded once, on its first degraded evaluation.
+func (e *Evaluator) recordUnresolvedCall(n *ast.CallExpr, fn object.Object, result object.Object) {
+	if ret, ok := result.(*object.ReturnValue); ok {
+		result = ret.Value
+	}
+	placeholder, ok := result.(*object.SymbolicPlaceholder)
+	if !ok || placeholder.TypeInfo() != nil || placeholder.FieldType() != nil || strings.HasSuffix(placeholder.Reason, "(no return value)") {
+		return
+	}
+	if _, ok := e.unresolvedCalls[n.Pos()]; ok {
+		return
+	}
+	reason, ok := e.unresolvedReason(unwrapVariable(fn), placeholder)
+	if !ok {
+		return
+	}
+	if e.unresolvedCalls == nil {
+		e.unresolvedCalls = make(map[token.Pos]*UnresolvedCall)
+	}
+	call := &UnresolvedCall{Callee: types.ExprString(n.Fun), Reason: reason, Detail: placeholder.Reason}
+	if e.scanner != nil && e.scanner.Fset() != nil {
+		call.Pos = e.scanner.Fset().Position(n.Pos())
+	}
+	e.unresolvedCalls[n.Pos()] = call
+}
+
+// unresolvedReason classifies the call of fn resulting in the untyped placeholder. It returns
+// false if the call is not a degradation: the call of an intrinsic, of a function without
+// results, or of a function whose body was evaluated.
+func (e *Evaluator) unresolvedReason(fn object.Object, placeholder *object.SymbolicPlaceholder) (UnresolvedReason, bool) {
+	if inst, ok := fn.(*object.InstantiatedFunction); ok {
+		fn = inst.Function
+	}
+	switch fn := fn.(type) {
+	case *object.Intrinsic:
+		return "", false
+	case *object.Function:
+		if fn.Def != nil && len(fn.Def.Results) == 0 {
+			return "", false
+		}
+		if fn.Package != nil && !e.resolver.ScanPolicy(fn.Package.ImportPath) {
+			return UnresolvedOutOfPolicy, true
+		}
+		if fn.Body != nil && placeholder.Reason != "max call stack depth exceeded" {
+			return "", false // the body returned the placeholder
+		}
+		return UnresolvedInferenceFailure, true
+	case *object.UnresolvedFunction:
+		return e.unresolvedPackageReason(fn.PkgPath), true
+	case *object.UnresolvedType:
+		return e.unresolvedPackageReason(fn.PkgPath), true
+	case *object.SymbolicPlaceholder:
+		if fn.UnderlyingFunc == nil {
+			if ti := fn.TypeInfo(); ti == nil || ti.Func == nil {
+				return UnresolvedUnsupported, true
+			}
+			return UnresolvedInferenceFailure, true
+		}
+		if len(fn.UnderlyingFunc.Results) == 0 {
+			return "", false
+		}
+		if fn.Package != nil && !e.resolver.ScanPolicy(fn.Package.ImportPath) {
+			return UnresolvedOutOfPolicy, true
+		}
+		return UnresolvedInferenceFailure, true
+	case *object.Type:
+		return UnresolvedInferenceFailure, true
+	}
+	return UnresolvedUnsupported, true
+}
+
+func (e *Evaluator) unresolvedPackageReason(pkgPath string) UnresolvedReason {
+	if !e.resolver.ScanPolicy(pkgPath) {
+		return UnresolvedOutOfPolicy
+	}
+	return UnresolvedInferenceFailure
+}
+
+// buildUnresolvedCallReport sorts the recorded call sites by position, for UnresolvedCalls.
+func (e *Evaluator) buildUnresolvedCallReport(ctx context.Context) {
+	calls := make([]UnresolvedCall, 0, len(e.unresolvedCalls))
+	for _, call := range e.unresolvedCalls {
+		calls = append(calls, *call)
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		a, b := calls[i].Pos, calls[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	e.unresolvedCallReport = calls
+	e.logger.DebugContext(ctx, "finalize: unresolved calls", "count", len(calls))
+}
+
+// UnresolvedCalls returns the call sites whose results degraded to untyped placeholders,
+// sorted by position, as computed by the last call of Finalize.
+func (e *Evaluator) UnresolvedCalls() []UnresolvedCall {
+	return e.unresolvedCallReport
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/podhmo/go-scan/scantest"
 	"github.com/podhmo/go-scan/symgo/object"
 
@@ -98,3 +99,133 @@ OuterLoop:
 		t.Fatalf("scantest.Run() failed: %v", err)
 	}
 }
+
+func TestEvaluator_LabeledBranches(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "continue of a labeled range loop goes on with the next element",
+			body: `
+	fns := []func(){a, b}
+Outer:
+	for _, fn := range fns {
+		for {
+			fn()
+			continue Outer
+		}
+	}
+	after()`,
+			want: []string{"a", "b", "after"},
+		},
+		{
+			name: "labeled branches from a switch without default case",
+			body: `
+Loop:
+	for {
+		switch {
+		case next() == 0:
+			continue Loop
+		case next() == 1:
+			break Loop
+		}
+		work()
+	}
+	after()`,
+			want: []string{"next", "next", "work", "after"},
+		},
+		{
+			name: "labeled branches from all the cases of a type switch",
+			body: `
+	var v any = next()
+Loop:
+	for {
+		switch v.(type) {
+		case int:
+			break Loop
+		default:
+			continue Loop
+		}
+		work()
+	}
+	after()`,
+			want: []string{"next", "after"},
+		},
+		{
+			name: "goto targets after a return are explored",
+			body: `
+start:
+	c := next()
+	if c == 0 {
+		goto done
+	}
+	if c == 1 {
+		goto stateA
+	}
+	return
+stateA:
+	work()
+	goto start
+done:
+	after()`,
+			want: []string{"next", "work", "after"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `
+package main
+
+func a()        {}
+func b()        {}
+func next() int { return 0 }
+func work()     {}
+func after()    {}
+
+func main() {` + tc.body + `
+}
+`
+			dir, cleanup := scantest.WriteFiles(t, map[string]string{
+				"go.mod":  "module example.com/me",
+				"main.go": source,
+			})
+			defer cleanup()
+
+			var calls []string
+			action := func(ctx context.Context, s *goscan.Scanner, pkgs []*goscan.Package) error {
+				pkg := pkgs[0]
+				eval := New(s, s.Logger, nil, nil)
+				eval.RegisterDefaultIntrinsic(func(ctx context.Context, args ...object.Object) object.Object {
+					if fn, ok := args[0].(*object.Function); ok && fn.Name != nil {
+						calls = append(calls, fn.Name.Name)
+					}
+					return nil
+				})
+				for _, f := range pkg.AstFiles {
+					eval.Eval(ctx, f, nil, pkg)
+				}
+				pkgEnv, ok := eval.PackageEnvForTest("example.com/me")
+				if !ok {
+					return fmt.Errorf("could not get package env for 'example.com/me'")
+				}
+				mainFuncObj, ok := pkgEnv.Get("main")
+				if !ok {
+					return fmt.Errorf("main function not found in package environment")
+				}
+				if err, ok := eval.Apply(ctx, mainFuncObj, nil, pkg).(*object.Error); ok {
+					return fmt.Errorf("eval failed: %s", err.Error())
+				}
+				return nil
+			}
+			if _, err := scantest.Run(t, t.Context(), dir, []string{"."}, action); err != nil {
+				t.Fatalf("scantest.Run() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, calls); diff != "" {
+				t.Errorf("calls mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
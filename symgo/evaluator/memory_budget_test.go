@@ -0,0 +1,115 @@
+package evaluator_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+	"github.com/podhmo/go-scan/symgo"
+)
+
+func TestMemoryBudget_PrunesUnreachableEnvironments(t *testing.T) {
+	// NewCounter returns a closure capturing its local environment. Once NewCounter has
+	// returned, that environment is only retained by the memoization cache, so pruning
+	// drops the entry. The F<n> functions only make the evaluator apply enough
+	// functions to reach the periodic memory check.
+	var src strings.Builder
+	src.WriteString(`package main
+
+func NewCounter() func() int {
+	n := 0
+	return func() int {
+		n++
+		return n
+	}
+}
+
+func Run() {
+	next := NewCounter()
+	next()
+`)
+	for i := 0; i < 150; i++ {
+		fmt.Fprintf(&src, "\tF%d()\n", i)
+	}
+	src.WriteString("}\n")
+	for i := 0; i < 150; i++ {
+		fmt.Fprintf(&src, "\nfunc F%d() {}\n", i)
+	}
+
+	sourceFiles := map[string]string{
+		"go.mod":  "module example.com/me",
+		"main.go": src.String(),
+	}
+
+	run := func(t *testing.T, options ...symgo.Option) symgo.Stats {
+		t.Helper()
+		dir, cleanup := scantest.WriteFiles(t, sourceFiles)
+		defer cleanup()
+
+		var stats symgo.Stats
+		action := func(ctx context.Context, s *goscan.Scanner, pkgs []*goscan.Package) error {
+			options = append([]symgo.Option{
+				symgo.WithPrimaryAnalysisScope("example.com/me"),
+				symgo.WithMemoization(true),
+			}, options...)
+			interpreter, err := symgo.NewInterpreter(s, options...)
+			if err != nil {
+				return err
+			}
+			fn, ok := interpreter.FindObjectInPackage(ctx, "example.com/me", "Run")
+			if !ok {
+				t.Fatal("could not find entry point Run")
+			}
+			if _, err := interpreter.Apply(ctx, fn, nil, nil); err != nil {
+				t.Errorf("error analyzing entry point Run: %v", err)
+			}
+			stats = interpreter.Stats()
+			return nil
+		}
+		if _, err := scantest.Run(t, context.Background(), dir, []string{"."}, action); err != nil {
+			t.Fatalf("scantest.Run failed: %v", err)
+		}
+		return stats
+	}
+
+	t.Run("no budget", func(t *testing.T) {
+		stats := run(t)
+		if stats.Prunes != 0 || stats.PrunedEntries != 0 {
+			t.Errorf("expected no pruning without a budget, got %d prunes of %d entries", stats.Prunes, stats.PrunedEntries)
+		}
+		if stats.MemoryBudget != 0 {
+			t.Errorf("MemoryBudget = %d, want 0", stats.MemoryBudget)
+		}
+		// Run, NewCounter, the closure, and the F<n> functions are all memoized.
+		if stats.MemoizedResults < 152 {
+			t.Errorf("MemoizedResults = %d, want at least 152", stats.MemoizedResults)
+		}
+		if stats.Packages == 0 || stats.Environments == 0 || stats.Objects == 0 {
+			t.Errorf("expected non-zero packages, environments and objects, got %+v", stats)
+		}
+	})
+
+	t.Run("budget exceeded", func(t *testing.T) {
+		// A one-byte budget is always exceeded, so every periodic check prunes.
+		withoutBudget := run(t)
+		stats := run(t, symgo.WithMemoryBudget(1))
+		if stats.Prunes == 0 {
+			t.Fatalf("expected at least one pruning pass, got %+v", stats)
+		}
+		if stats.PrunedEntries == 0 {
+			t.Errorf("expected the memoized closure of NewCounter to be pruned, got %+v", stats)
+		}
+		if stats.MemoryBudget != 1 {
+			t.Errorf("MemoryBudget = %d, want 1", stats.MemoryBudget)
+		}
+		if stats.MemoizedResults >= withoutBudget.MemoizedResults {
+			t.Errorf("MemoizedResults = %d, want fewer than %d", stats.MemoizedResults, withoutBudget.MemoizedResults)
+		}
+		if stats.Environments >= withoutBudget.Environments {
+			t.Errorf("Environments = %d, want fewer than %d", stats.Environments, withoutBudget.Environments)
+		}
+	})
+}
@@ -63,3 +63,124 @@ func main() {
 		t.Fatalf("scantest.Run() failed: %+v", err)
 	}
 }
+
+func TestEval_BareReturnOfNamedResults(t *testing.T) {
+	source := `
+package main
+
+type User struct{ Name string }
+
+func find(name string) (u *User, err error) {
+	u = &User{Name: name}
+	return
+}
+
+func zero() (u *User) { return }
+
+func first[T any](xs []T) (v T, ok bool) {
+	v = xs[0]
+	ok = true
+	return
+}
+
+func useLit() (int, error) {
+	lit := func() (n int, err error) {
+		n = 1
+		return
+	}
+	return lit()
+}
+
+func useFirst() (string, bool) {
+	return first([]string{"x"})
+}
+`
+	action := func(ctx context.Context, s *goscan.Scanner, pkgs []*goscan.Package) error {
+		eval := evaluator.New(s, nil, nil, func(path string) bool { return true })
+		mainPkg := pkgs[0]
+		pkgObj, err := eval.GetOrLoadPackageForTest(ctx, mainPkg.ImportPath)
+		if err != nil {
+			return err
+		}
+		apply := func(name string) []object.Object {
+			t.Helper()
+			fn, ok := pkgObj.Env.Get(name)
+			if !ok {
+				t.Fatalf("function %s not found", name)
+			}
+			result := eval.Apply(ctx, fn, nil, mainPkg)
+			if ret, ok := result.(*object.ReturnValue); ok {
+				result = ret.Value
+			}
+			if err, ok := result.(*object.Error); ok {
+				t.Fatalf("%s: evaluation failed: %v", name, err)
+			}
+			if multi, ok := result.(*object.MultiReturn); ok {
+				return multi.Values
+			}
+			return []object.Object{result}
+		}
+
+		t.Run("assigned results", func(t *testing.T) {
+			vals := apply("find")
+			if len(vals) != 2 {
+				t.Fatalf("want 2 results, got %d: %v", len(vals), vals)
+			}
+			ptr, ok := vals[0].(*object.Pointer)
+			if !ok {
+				t.Fatalf("want a pointer for u, got %T: %s", vals[0], vals[0].Inspect())
+			}
+			if ti := ptr.Value.TypeInfo(); ti == nil || ti.Name != "User" {
+				t.Errorf("want u to point to a User, got %v", ti)
+			}
+		})
+
+		t.Run("unassigned result is a typed zero value", func(t *testing.T) {
+			vals := apply("zero")
+			if len(vals) != 1 {
+				t.Fatalf("want 1 result, got %d", len(vals))
+			}
+			ft := vals[0].FieldType()
+			if ft == nil || !ft.IsPointer {
+				t.Fatalf("want the zero value to be typed as *User, got %v", ft)
+			}
+			if ti := vals[0].TypeInfo(); ti == nil || ti.Name != "User" {
+				t.Errorf("want the zero value to be typed as *User, got %v", ti)
+			}
+		})
+
+		t.Run("function literal", func(t *testing.T) {
+			vals := apply("useLit")
+			if len(vals) != 2 {
+				t.Fatalf("want 2 results, got %d: %v", len(vals), vals)
+			}
+			if n, ok := vals[0].(*object.Integer); !ok || n.Value != 1 {
+				t.Errorf("want n to be 1, got %s", vals[0].Inspect())
+			}
+		})
+
+		t.Run("generic function", func(t *testing.T) {
+			vals := apply("useFirst")
+			if len(vals) != 2 {
+				t.Fatalf("want 2 results, got %d: %v", len(vals), vals)
+			}
+			if _, ok := vals[0].(*object.Nil); ok {
+				t.Errorf("want v to be the first element, got nil")
+			}
+			if b, ok := vals[1].(*object.Boolean); !ok || !b.Value {
+				t.Errorf("want ok to be true, got %s", vals[1].Inspect())
+			}
+		})
+		return nil
+	}
+
+	dir, cleanup := scantest.WriteFiles(t, map[string]string{
+		"go.mod":  "module example.com/main",
+		"main.go": source,
+	})
+	defer cleanup()
+
+	if _, err := scantest.Run(t, t.Context(), dir, []string{"."}, action, scantest.WithModuleRoot(dir)); err != nil {
+		t.Fatalf("scantest.Run() failed: %+v", err)
+	}
+}
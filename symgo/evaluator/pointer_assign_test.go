@@ -0,0 +1,128 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+func TestEval_AssignThroughPointer(t *testing.T) {
+	cases := []struct {
+		name string
+		main string
+	}{
+		{
+			name: "field set by a pointer receiver",
+			main: `
+	o := &Options{}
+	o.SetHandler(handler)
+	o.Handler()`,
+		},
+		{
+			name: "field set on a zero value through a pointer",
+			main: `
+	var o Options
+	p := &o
+	p.Handler = handler
+	o.Handler()`,
+		},
+		{
+			name: "field set on an alias",
+			main: `
+	o := &Options{}
+	alias := o
+	alias.Handler = handler
+	o.Handler()`,
+		},
+		{
+			name: "function variable set through a pointer",
+			main: `
+	var f func()
+	p := &f
+	*p = handler
+	f()`,
+		},
+		{
+			name: "variable read through a pointer after a write",
+			main: `
+	var f func()
+	p := &f
+	f = handler
+	(*p)()`,
+		},
+		{
+			name: "struct set through a pointer",
+			main: `
+	o := &Options{}
+	*o = Options{Handler: handler}
+	o.Handler()`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := `
+package main
+
+type Options struct {
+	Handler func()
+}
+
+func (o *Options) SetHandler(h func()) {
+	o.Handler = h
+}
+
+func handler() {}
+
+func main() {` + tc.main + `
+}
+`
+			dir, cleanup := scantest.WriteFiles(t, map[string]string{
+				"go.mod":  "module example.com/me",
+				"main.go": source,
+			})
+			defer cleanup()
+
+			action := func(ctx context.Context, s *goscan.Scanner, pkgs []*goscan.Package) error {
+				pkg := pkgs[0]
+				eval := New(s, s.Logger, nil, nil)
+
+				var called bool
+				eval.RegisterDefaultIntrinsic(func(ctx context.Context, args ...object.Object) object.Object {
+					if fn, ok := args[0].(*object.Function); ok && fn.Def != nil && fn.Def.Name == "handler" {
+						called = true
+					}
+					return nil
+				})
+
+				env := object.NewEnclosedEnvironment(eval.UniverseEnv)
+				for _, file := range pkg.AstFiles {
+					eval.Eval(ctx, file, env, pkg)
+				}
+				pkgEnv, ok := eval.PackageEnvForTest(pkg.ImportPath)
+				if !ok {
+					return fmt.Errorf("package env not found for %q", pkg.ImportPath)
+				}
+				mainFunc, ok := pkgEnv.Get("main")
+				if !ok {
+					return fmt.Errorf("function 'main' not found")
+				}
+				if err, ok := eval.Apply(ctx, mainFunc, nil, pkg).(*object.Error); ok {
+					return fmt.Errorf("evaluation failed unexpectedly: %s", err.Message)
+				}
+				if !called {
+					return fmt.Errorf("the call of handler, stored through a pointer, was not tracked")
+				}
+				return nil
+			}
+
+			if _, err := scantest.Run(t, context.Background(), dir, []string{"."}, action); err != nil {
+				t.Fatalf("scantest.Run() failed: %v", err)
+			}
+		})
+	}
+}
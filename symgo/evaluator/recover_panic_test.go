@@ -0,0 +1,92 @@
+package evaluator_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestRecoverPanic(t *testing.T) {
+	source := `
+package main
+
+func broken(s string) {}
+
+func helper() {
+	broken("boom")
+}
+
+func Other() string {
+	return "ok"
+}
+
+func main() {
+	helper()
+}
+`
+	tc := symgotest.TestCase{
+		Source: map[string]string{
+			"go.mod":  "module example.com/main",
+			"main.go": source,
+		},
+		EntryPoint: "example.com/main.main",
+		Options: []symgotest.Option{
+			symgotest.WithIntrinsic("example.com/main.broken", func(ctx context.Context, interp *symgo.Interpreter, args []object.Object) object.Object {
+				var m map[string]int
+				m["bug"]++ // an intrinsic with a bug, panicking
+				return nil
+			}),
+		},
+		ExpectError: true,
+	}
+
+	action := func(t *testing.T, r *symgotest.Result) {
+		var err *object.Error
+		if !errors.As(r.Error, &err) {
+			t.Fatalf("want an *object.Error, got %T: %v", r.Error, r.Error)
+		}
+		if err.Panic == nil || !strings.Contains(err.Message, "assignment to entry in nil map") {
+			t.Errorf("want the panic as the error, got %q (panic: %v)", err.Message, err.Panic)
+		}
+		if !strings.Contains(string(err.GoStack), "recover_panic_test.go") {
+			t.Errorf("want the Go stack of the panic, got:\n%s", err.GoStack)
+		}
+		if pos := r.Interpreter.Scanner().Fset().Position(err.Pos); pos.Line != 7 {
+			t.Errorf("want the position of the call of broken, got %v", pos)
+		}
+		var funcs []string
+		for _, frame := range err.CallStack {
+			funcs = append(funcs, frame.Function)
+		}
+		if got := strings.Join(funcs, " > "); got != "main > helper" {
+			t.Errorf("want the call stack at the panic, got %q", got)
+		}
+
+		// The interpreter is still usable.
+		if stack := r.Interpreter.CallStack(); len(stack) != 0 {
+			t.Errorf("want the call stack unwound, got %d frames", len(stack))
+		}
+		ctx := context.Background()
+		fn, ok := r.Interpreter.FindObjectInPackage(ctx, "example.com/main", "Other")
+		if !ok {
+			t.Fatalf("Other not found")
+		}
+		res, applyErr := r.Interpreter.Apply(ctx, fn, nil, nil)
+		if applyErr != nil {
+			t.Fatalf("Apply(Other) failed after the recovered panic: %v", applyErr)
+		}
+		if ret, ok := res.(*object.ReturnValue); ok {
+			res = ret.Value
+		}
+		if s, ok := res.(*object.String); !ok || s.Value != "ok" {
+			t.Errorf("want \"ok\", got %v", res.Inspect())
+		}
+	}
+
+	symgotest.Run(t, tc, action)
+}
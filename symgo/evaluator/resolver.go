@@ -54,7 +54,24 @@ func (r *Resolver) ResolveType(ctx context.Context, fieldType *scanner.FieldType
 		r.logger.DebugContext(ctx, "type resolution failed, returning placeholder", "type", fieldType.String(), "error", err)
 		return scanner.NewUnresolvedTypeInfo(fieldType.FullImportPath, fieldType.TypeName)
 	}
-	return resolvedType
+	return r.ResolveAlias(ctx, resolvedType)
+}
+
+// ResolveAlias follows alias declarations (`type A = B`) so that aliases are transparent,
+// returning the type the alias denotes. Following stops at a type that is not an alias,
+// at an alias of a type without a declaration of its own (e.g. `type A = []int`), or at
+// an alias whose target package is disallowed by the scan policy.
+func (r *Resolver) ResolveAlias(ctx context.Context, typeInfo *scanner.TypeInfo) *scanner.TypeInfo {
+	if typeInfo == nil {
+		return nil
+	}
+	resolved, _, err := typeInfo.ResolveAliasFunc(ctx, func(target *scanner.FieldType) bool {
+		return target.FullImportPath == "" || r.ScanPolicy(target.FullImportPath)
+	})
+	if err != nil {
+		r.logger.DebugContext(ctx, "alias resolution failed, keeping the last type reached", "alias", typeInfo.Name, "reached", resolved.Name, "error", err)
+	}
+	return resolved
 }
 
 // resolveTypeWithoutPolicyCheck resolves a FieldType to a TypeInfo without enforcing the scan policy.
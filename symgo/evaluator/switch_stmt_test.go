@@ -0,0 +1,127 @@
+package evaluator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+func TestEvaluator_SwitchStmt(t *testing.T) {
+	source := `
+package main
+
+type MyError struct{}
+
+func (e *MyError) Error() string { return "my error" }
+
+func kind() string { return "x" }
+func onA(s string)      {}
+func onB(s string)      {}
+func onOther(s string)  {}
+func onNil(err error)   {}
+func onError(err error) {}
+
+func handle(m string) string {
+	switch m {
+	case "a":
+		onA(m)
+		return "a"
+	case "b":
+		onB(m)
+		fallthrough
+	default:
+		onOther(m)
+	}
+	return ""
+}
+
+func check(err error) {
+	switch err {
+	case nil:
+		onNil(err)
+	default:
+		onError(err)
+	}
+}
+
+func main() {
+	handle("x")
+	check(&MyError{})
+	switch kind() {
+	}
+}
+`
+	// setup
+	ctx := t.Context()
+	files := map[string]string{
+		"go.mod":  "module a.b/c",
+		"main.go": source,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	// run
+	var calls []string
+	action := func(ctx context.Context, s *goscan.Scanner, pkgs []*goscan.Package) error {
+		eval := New(s, s.Logger, nil, nil)
+
+		eval.RegisterDefaultIntrinsic(func(ctx context.Context, args ...object.Object) object.Object {
+			fn, ok := args[0].(*object.Function)
+			if !ok || fn.Name == nil {
+				return nil
+			}
+			call := fn.Name.Name
+			if len(args) > 1 {
+				call += "(" + args[1].Inspect() + ")"
+			}
+			calls = append(calls, call)
+			return nil
+		})
+
+		mainPkg := pkgs[0]
+		for _, f := range mainPkg.AstFiles {
+			eval.Eval(ctx, f, nil, mainPkg)
+		}
+
+		pkgEnv, ok := eval.PackageEnvForTest("a.b/c")
+		if !ok {
+			t.Fatal("could not get package env for 'a.b/c'")
+		}
+		mainFunc, ok := pkgEnv.Get("main")
+		if !ok {
+			t.Fatal("main function not found")
+		}
+		result := eval.Apply(ctx, mainFunc, nil, mainPkg)
+		if err, ok := result.(*object.Error); ok {
+			return err
+		}
+		return nil
+	}
+
+	_, err := scantest.Run(t, ctx, dir, []string{"."}, action)
+	if err != nil {
+		t.Fatalf("run failed: %+v", err)
+	}
+
+	// assert
+	// Each case is explored, after the return of the first one, with the tag narrowed to the
+	// value of the case; the default case and the fallthrough keep the value of the tag.
+	want := []string{
+		`handle("x")`,
+		`onA("a")`,
+		`onB("b")`,
+		`onOther("b")`,
+		`onOther("x")`,
+		"check(&instance<a.b/c.MyError, underlying={}>)",
+		"onNil(nil)",
+		"onError(&instance<a.b/c.MyError, underlying={}>)",
+		"kind",
+	}
+	if diff := cmp.Diff(want, calls); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
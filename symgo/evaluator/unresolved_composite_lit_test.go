@@ -0,0 +1,151 @@
+package evaluator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+func TestEval_CompositeLitOfOutOfPolicyType(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/me",
+		"main.go": `
+package main
+
+import "example.com/me/foreign"
+
+func handle() {}
+
+func handlePtr() {}
+
+func main() {
+	foreign.Config{Name: "direct"}.Describe()
+	c := foreign.Config{Name: "app", Handler: handle}
+	c.Handler()
+	p := &foreign.Config{Name: "ptr", Handler: handlePtr}
+	p.Handler()
+	c.Run()
+}
+`,
+		"foreign/foreign.go": `
+package foreign
+
+type Config struct {
+	Name    string
+	Handler func()
+}
+
+func (c *Config) Run() error { return nil }
+
+func (c Config) Describe() string { return c.Name }
+`,
+	}
+
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	cases := []struct {
+		name string
+		// declsScanned scans the foreign package before the evaluation, as a decls-only load would.
+		declsScanned bool
+	}{
+		{name: "unscanned"},
+		{name: "scanned by someone else", declsScanned: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			action := func(ctx context.Context, s *goscan.Scanner, pkgs []*goscan.Package) error {
+				if tc.declsScanned {
+					if _, err := s.ScanPackageFromImportPath(ctx, "example.com/me/foreign"); err != nil {
+						t.Fatalf("scanning the foreign package failed: %v", err)
+					}
+				}
+				policy := func(path string) bool {
+					return !strings.Contains(path, "foreign")
+				}
+				eval := New(s, s.Logger, nil, policy)
+
+				var mainPkg *goscan.Package
+				for _, pkg := range pkgs {
+					if pkg.ImportPath == "example.com/me" {
+						mainPkg = pkg
+					}
+				}
+				for _, file := range mainPkg.AstFiles {
+					eval.Eval(ctx, file, eval.UniverseEnv, mainPkg)
+				}
+
+				var called []object.Object
+				eval.RegisterDefaultIntrinsic(func(ctx context.Context, args ...object.Object) object.Object {
+					called = append(called, args[0])
+					return nil
+				})
+
+				pkgEnv, _ := eval.PackageEnvForTest("example.com/me")
+				mainFunc, _ := pkgEnv.Get("main")
+				if result := eval.Apply(ctx, mainFunc, nil, mainPkg); isError(result) {
+					t.Fatalf("Apply() failed: %s", result.Inspect())
+				}
+
+				methodCall := func(name string) *object.SymbolicPlaceholder {
+					for _, fn := range called {
+						if sp, ok := fn.(*object.SymbolicPlaceholder); ok && sp.Receiver != nil && strings.Contains(sp.Reason, "call "+name+" ") {
+							return sp
+						}
+					}
+					t.Fatalf("the call of %s is not found, got %v", name, called)
+					return nil
+				}
+
+				// The functions stored in the fields of the literals are called through the fields.
+				for _, name := range []string{"handle", "handlePtr"} {
+					var found bool
+					for _, fn := range called {
+						if f, ok := fn.(*object.Function); ok && f.Name != nil && f.Name.Name == name {
+							found = true
+						}
+					}
+					if !found {
+						t.Errorf("the function %s in the field of the literal is not called", name)
+					}
+				}
+
+				// The receiver of a method call is a typed placeholder with the field values.
+				run := methodCall("Run")
+				recv, ok := run.Receiver.(*object.SymbolicPlaceholder)
+				if !ok {
+					t.Fatalf("expected the receiver to be a placeholder, got %T", run.Receiver)
+				}
+				if ti := recv.TypeInfo(); ti == nil || ti.Name != "Config" || ti.PkgPath != "example.com/me/foreign" {
+					t.Errorf("expected the receiver to be typed as foreign.Config, got %+v", ti)
+				}
+				if name, ok := recv.Fields["Name"].(*object.String); !ok || name.Value != "app" {
+					t.Errorf("expected the receiver to hold the Name field, got %v", recv.Fields["Name"])
+				}
+
+				// The call is matched with the declared method only if the package was scanned.
+				describe := methodCall("Describe")
+				if tc.declsScanned {
+					if describe.UnderlyingFunc == nil || describe.UnderlyingFunc.Name != "Describe" || describe.UnderlyingFunc.Receiver == nil {
+						t.Errorf("expected the call to be matched with the declared method, got %+v", describe.UnderlyingFunc)
+					}
+					if describe.Package == nil || describe.Package.ImportPath != "example.com/me/foreign" {
+						t.Errorf("expected the package of the declared method, got %+v", describe.Package)
+					}
+				} else if describe.UnderlyingFunc != nil {
+					t.Errorf("expected no declared method for an unscanned package, got %+v", describe.UnderlyingFunc)
+				}
+				return nil
+			}
+
+			if _, err := scantest.Run(t, t.Context(), dir, []string{"."}, action); err != nil {
+				t.Fatalf("scantest.Run() failed: %v", err)
+			}
+		})
+	}
+}
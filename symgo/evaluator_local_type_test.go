@@ -87,3 +87,39 @@ func TestDo() {
 		t.Log("Test ran without unexpected errors.")
 	})
 }
+
+func TestEval_LocalTypesOfDifferentFunctions(t *testing.T) {
+	// Each function declares its own row; the one of run must be used for `var r row`.
+	tc := symgotest.TestCase{
+		Source: map[string]string{
+			"go.mod": "module example.com/m",
+			"main.go": `
+package main
+
+func other() {
+	type row []string
+}
+
+func run() int {
+	type row struct {
+		f func() int
+	}
+	var r row
+	r.f = func() int { return 9 }
+	return r.f()
+}
+`,
+		},
+		EntryPoint: "example.com/m.run",
+	}
+
+	symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+		if r.Error != nil {
+			t.Fatalf("expected no error, but got: %+v", r.Error)
+		}
+		got := symgotest.AssertAs[*object.Integer](r, t, 0)
+		if got.Value != 9 {
+			t.Errorf("expected 9, but got %d", got.Value)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/podhmo/go-scan/symgo"
 	"github.com/podhmo/go-scan/symgo/object"
 	"github.com/podhmo/go-scan/symgo/symgotest"
@@ -377,3 +378,86 @@ func run() string {
 
 	symgotest.Run(t, tc, action)
 }
+
+func TestFeature_StringTemplate(t *testing.T) {
+	source := map[string]string{
+		"go.mod": "module example.com/me",
+		"main.go": `package main
+import "fmt"
+
+const version = "v1"
+
+func route() string {
+	return "/api/" + version + "/users"
+}
+
+func routeOf(id string) string {
+	return "/api/" + version + "/users/" + id + "/items"
+}
+
+func query(table string) string {
+	return fmt.Sprintf("SELECT * FROM %s WHERE id = %d", table, 1)
+}
+
+func build(id string) string {
+	s := "/users"
+	s += "/" + id
+	s += "/items"
+	return s
+}
+`,
+	}
+	param := func(name string) object.Object {
+		return &object.Variable{Name: name, Value: &object.SymbolicPlaceholder{Reason: "function parameter"}}
+	}
+
+	cases := []struct {
+		entryPoint   string
+		args         []object.Object
+		wantString   string // for a folded constant
+		wantSkeleton string // for a template, with "*" for the holes
+		wantHoles    []string
+	}{
+		{entryPoint: "route", wantString: "/api/v1/users"},
+		{entryPoint: "routeOf", args: []object.Object{param("id")}, wantSkeleton: "/api/v1/users/*/items", wantHoles: []string{"id"}},
+		{entryPoint: "query", args: []object.Object{param("table")}, wantSkeleton: "SELECT * FROM * WHERE id = 1", wantHoles: []string{""}},
+		{entryPoint: "build", args: []object.Object{param("id")}, wantSkeleton: "/users/*/items", wantHoles: []string{"id"}},
+	}
+	for _, c := range cases {
+		t.Run(c.entryPoint, func(t *testing.T) {
+			tc := symgotest.TestCase{
+				Source:     source,
+				EntryPoint: "example.com/me." + c.entryPoint,
+				Args:       c.args,
+			}
+			symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+				if r.Error != nil {
+					t.Fatalf("Execution failed unexpectedly: %v", r.Error)
+				}
+				if c.wantString != "" {
+					str, ok := r.ReturnValue.(*object.String)
+					if !ok || str.Value != c.wantString {
+						t.Fatalf("expected the constant string %q, got %s", c.wantString, r.ReturnValue.Inspect())
+					}
+					return
+				}
+				tmpl, ok := r.ReturnValue.(*object.StringTemplate)
+				if !ok {
+					t.Fatalf("expected a string template, got %T (%s)", r.ReturnValue, r.ReturnValue.Inspect())
+				}
+				if got := tmpl.Skeleton("*"); got != c.wantSkeleton {
+					t.Errorf("Skeleton() = %q, want %q", got, c.wantSkeleton)
+				}
+				var holes []string
+				for _, p := range tmpl.Parts {
+					if !p.Const {
+						holes = append(holes, p.Name)
+					}
+				}
+				if diff := cmp.Diff(c.wantHoles, holes); diff != "" {
+					t.Errorf("holes mismatch (-want +got):\n%s", diff)
+				}
+			})
+		})
+	}
+}
@@ -4,9 +4,52 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/podhmo/go-scan/scanner"
 	"github.com/podhmo/go-scan/symgo/object"
 )
 
+// maxKnownElements is the largest length of a slice made with `make` whose elements are
+// tracked, as zero values.
+const maxKnownElements = 64
+
+// intFieldType is the type of the results of len, cap and copy.
+var intFieldType = &scanner.FieldType{Name: "int", IsBuiltin: true}
+
+// symbolicInt returns a placeholder for an int whose value is not known.
+func symbolicInt(reason string) *object.SymbolicPlaceholder {
+	p := &object.SymbolicPlaceholder{Reason: reason, Len: -1, Cap: -1}
+	p.SetFieldType(intFieldType)
+	return p
+}
+
+// unwrap returns the value held by a variable or a return value.
+func unwrap(obj object.Object) object.Object {
+	for {
+		switch o := obj.(type) {
+		case *object.Variable:
+			if o.Value == nil {
+				return o
+			}
+			obj = o.Value
+		case *object.ReturnValue:
+			obj = o.Value
+		default:
+			return obj
+		}
+	}
+}
+
+// sliceFieldType returns the slice type of obj, which may be a named slice type.
+func sliceFieldType(obj object.Object) *scanner.FieldType {
+	if ft := obj.FieldType(); ft != nil && ft.IsSlice {
+		return ft
+	}
+	if ti := obj.TypeInfo(); ti != nil && ti.Underlying != nil && ti.Underlying.IsSlice {
+		return ti.Underlying
+	}
+	return nil
+}
+
 // BuiltinPanic is the intrinsic function for the built-in `panic`.
 func BuiltinPanic(ctx context.Context, args ...object.Object) object.Object {
 	if len(args) != 1 {
@@ -32,6 +75,13 @@ func BuiltinMake(ctx context.Context, args ...object.Object) object.Object {
 	}
 
 	fieldType := typeArg.FieldType()
+	if fieldType == nil {
+		// A named type, e.g. `make(StringSet)` for `type StringSet map[string]bool`, is made
+		// from its underlying type, keeping the named type for its methods.
+		if ti := typeArg.TypeInfo(); ti != nil && ti.Underlying != nil {
+			fieldType = ti.Underlying
+		}
+	}
 	if fieldType == nil {
 		// Fallback if type information is not available.
 		return &object.SymbolicPlaceholder{Reason: "make(...) call with untyped arg"}
@@ -75,6 +125,16 @@ func BuiltinMake(ctx context.Context, args ...object.Object) object.Object {
 			Len:            length,
 			Cap:            capacity,
 		}
+		// The elements of a short slice are tracked as zero values, so that the values stored
+		// later with append or copy are found by index.
+		if length >= 0 && length <= maxKnownElements {
+			slice.Elements = make([]object.Object, length)
+			for i := range slice.Elements {
+				zero := &object.SymbolicPlaceholder{Reason: "zero value of slice element", Len: -1, Cap: -1}
+				zero.SetFieldType(fieldType.Elem)
+				slice.Elements[i] = zero
+			}
+		}
 		slice.SetFieldType(fieldType)
 		slice.SetTypeInfo(typeArg.TypeInfo())
 		return slice
@@ -90,7 +150,10 @@ func BuiltinMake(ctx context.Context, args ...object.Object) object.Object {
 	}
 
 	// Fallback for other types or when type info is not available
-	return &object.SymbolicPlaceholder{Reason: fmt.Sprintf("make(%s) call", fieldType.String())}
+	result := &object.SymbolicPlaceholder{Reason: fmt.Sprintf("make(%s) call", fieldType.String())}
+	result.SetFieldType(fieldType)
+	result.SetTypeInfo(typeArg.TypeInfo())
+	return result
 }
 
 // BuiltinAppend is the intrinsic function for the built-in `append`.
@@ -98,8 +161,50 @@ func BuiltinAppend(ctx context.Context, args ...object.Object) object.Object {
 	if len(args) < 1 {
 		return &object.Error{Message: "wrong number of arguments: append needs at least 1"}
 	}
-	// In symbolic execution, we just acknowledge the call and return a placeholder.
-	return &object.SymbolicPlaceholder{Reason: "append(...) call"}
+	slice := unwrap(args[0])
+	if slice == nil {
+		return &object.SymbolicPlaceholder{Reason: "append(...) call"}
+	}
+
+	// The appended values are kept, so that a function appended to a slice can be called
+	// later. The elements spread with `...` are known if the spread slice has known elements.
+	var appended []object.Object
+	knownAppended := true
+	for _, arg := range args[1:] {
+		v, ok := arg.(*object.Variadic)
+		if !ok {
+			appended = append(appended, unwrap(arg))
+			continue
+		}
+		src, ok := unwrap(v.Value).(*object.Slice)
+		if !ok {
+			knownAppended = false
+			continue
+		}
+		appended = append(appended, src.Elements...)
+		knownAppended = knownAppended && src.HasKnownElements()
+	}
+
+	// The result has the type of the slice, which may be a named type with methods.
+	result := &object.Slice{SliceFieldType: sliceFieldType(slice), Len: -1, Cap: -1}
+	result.SetFieldType(slice.FieldType())
+	result.SetTypeInfo(slice.TypeInfo())
+	switch s := slice.(type) {
+	case *object.Slice:
+		result.Elements = append(append([]object.Object{}, s.Elements...), appended...)
+		if s.HasKnownElements() && knownAppended {
+			result.Len = int64(len(result.Elements))
+		}
+	case *object.Nil:
+		result.Elements = appended
+		if knownAppended {
+			result.Len = int64(len(appended))
+		}
+	default:
+		// A slice of unknown contents.
+		result.Elements = appended
+	}
+	return result
 }
 
 // BuiltinLen is the intrinsic function for the built-in `len`.
@@ -108,29 +213,14 @@ func BuiltinLen(ctx context.Context, args ...object.Object) object.Object {
 		return &object.Error{Message: "wrong number of arguments: len expects 1"}
 	}
 
-	// If the argument is a return value, unwrap it to get the actual object.
-	if ret, ok := args[0].(*object.ReturnValue); ok {
-		args[0] = ret.Value
-	}
-
-	arg := args[0]
-	// Recursively unwrap variables to get to the underlying object.
-	for {
-		v, ok := arg.(*object.Variable)
-		if !ok {
-			break
-		}
-		arg = v.Value
-	}
-
-	switch arg := arg.(type) {
+	switch arg := unwrap(args[0]).(type) {
 	case *object.Slice:
 		// If the slice itself has a concrete length, use it.
 		if arg.Len >= 0 {
 			return &object.Integer{Value: arg.Len}
 		}
 		// Otherwise, it's symbolic.
-		return &object.SymbolicPlaceholder{Reason: "len on symbolic slice"}
+		return symbolicInt("len on symbolic slice")
 	case *object.String:
 		return &object.Integer{Value: int64(len(arg.Value))}
 	case *object.Map:
@@ -140,12 +230,12 @@ func BuiltinLen(ctx context.Context, args ...object.Object) object.Object {
 		if arg.Len != -1 {
 			return &object.Integer{Value: arg.Len}
 		}
-		return &object.SymbolicPlaceholder{Reason: "len on symbolic value"}
+		return symbolicInt("len on symbolic value")
 	case *object.UnresolvedFunction:
 		// This can happen if `len` is called on a variable from an unscanned
 		// package that is mis-identified as a function. Instead of crashing,
 		// return a symbolic placeholder for the length.
-		return &object.SymbolicPlaceholder{Reason: "len on unresolved function"}
+		return symbolicInt("len on unresolved function")
 	case *object.Nil:
 		// len(nil) is a valid operation for slices, maps, and channels, and it returns 0.
 		return &object.Integer{Value: 0}
@@ -159,27 +249,17 @@ func BuiltinCap(ctx context.Context, args ...object.Object) object.Object {
 	if len(args) != 1 {
 		return &object.Error{Message: "wrong number of arguments: cap expects 1"}
 	}
-	arg := args[0]
-	// Recursively unwrap variables to get to the underlying object.
-	for {
-		v, ok := arg.(*object.Variable)
-		if !ok {
-			break
-		}
-		arg = v.Value
-	}
-
-	switch arg := arg.(type) {
+	switch arg := unwrap(args[0]).(type) {
 	case *object.Slice:
 		if arg.Cap >= 0 {
 			return &object.Integer{Value: arg.Cap}
 		}
-		return &object.SymbolicPlaceholder{Reason: "cap on symbolic slice"}
+		return symbolicInt("cap on symbolic slice")
 	case *object.SymbolicPlaceholder:
 		if arg.Cap != -1 {
 			return &object.Integer{Value: arg.Cap}
 		}
-		return &object.SymbolicPlaceholder{Reason: "cap on symbolic value"}
+		return symbolicInt("cap on symbolic value")
 	default:
 		return &object.Error{Message: fmt.Sprintf("argument to `cap` not supported, got %s", arg.Type())}
 	}
@@ -255,7 +335,20 @@ func BuiltinCopy(ctx context.Context, args ...object.Object) object.Object {
 	if len(args) != 2 {
 		return &object.Error{Message: "wrong number of arguments: copy expects 2"}
 	}
-	return &object.SymbolicPlaceholder{Reason: "copy(...) call"} // Returns int
+	dst, dstOK := unwrap(args[0]).(*object.Slice)
+	src, srcOK := unwrap(args[1]).(*object.Slice)
+	if !dstOK || !srcOK {
+		return symbolicInt("copy(...) call")
+	}
+	if dst.HasKnownElements() && src.HasKnownElements() {
+		n := copy(dst.Elements, src.Elements)
+		return &object.Integer{Value: int64(n)}
+	}
+	if !dst.HasKnownElements() {
+		// The copied values are stored at unknown positions.
+		dst.Elements = append(dst.Elements, src.Elements...)
+	}
+	return symbolicInt("copy(...) call")
 }
 
 // BuiltinDelete is the intrinsic function for the built-in `delete`.
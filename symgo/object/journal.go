@@ -0,0 +1,181 @@
+package object
+
+import "maps"
+
+// Journal tracks the writes to a family of environments (the environments it is set on,
+// and the environments they enclose), to the variables they hold, and to the struct fields
+// and pointers written through, so that their state can be captured and rolled back with
+// Snapshot and Restore.
+//
+// Snapshots are copy-on-write: taking one copies nothing. The first write to an environment
+// after a snapshot saves its bindings and gives the environment a copy of them, and the first
+// write to a variable, a struct or a pointer saves it, so the cost is proportional to what is written
+// while the snapshot is active.
+type Journal struct {
+	snapshots []*Snapshot // The active snapshots, oldest first.
+	seq       int         // The number of snapshots taken so far.
+}
+
+// NewJournal creates a journal without active snapshots.
+func NewJournal() *Journal {
+	return &Journal{}
+}
+
+// Snapshot is the state of the environments and variables tracked by a Journal at some point.
+// It stays valid after it is restored, so the same state can be restored several times,
+// until it is released.
+type Snapshot struct {
+	journal *Journal
+	seq     int
+	envs    map[*Environment]map[string]Object
+	vars    map[*Variable]Variable
+	structs map[*Struct]map[string]Object
+	ptrs    map[*Pointer]Pointer
+}
+
+// Snapshot captures the current state.
+func (j *Journal) Snapshot() *Snapshot {
+	j.seq++
+	s := &Snapshot{
+		journal: j,
+		seq:     j.seq,
+		envs:    make(map[*Environment]map[string]Object),
+		vars:    make(map[*Variable]Variable),
+		structs: make(map[*Struct]map[string]Object),
+		ptrs:    make(map[*Pointer]Pointer),
+	}
+	j.snapshots = append(j.snapshots, s)
+	return s
+}
+
+// Restore rolls the environments and variables back to the state captured by s.
+// The snapshots taken after s are released, as the state they captured is gone.
+// Restoring a released snapshot does nothing.
+func (j *Journal) Restore(s *Snapshot) {
+	i := j.index(s)
+	if i < 0 {
+		return
+	}
+	for _, later := range j.snapshots[i+1:] {
+		later.journal = nil
+	}
+	j.snapshots = j.snapshots[:i+1]
+
+	for env, store := range s.envs {
+		env.store = store
+	}
+	for v, saved := range s.vars {
+		*v = saved
+	}
+	for st, fields := range s.structs {
+		st.Fields = fields
+	}
+	for p, saved := range s.ptrs {
+		*p = saved
+	}
+	// The restored bindings are now shared with the environments,
+	// so they are saved again (and copied) on the next write.
+	clear(s.envs)
+	clear(s.vars)
+	clear(s.structs)
+	clear(s.ptrs)
+}
+
+// Release stops tracking the writes for s. It is no longer possible to restore it.
+func (j *Journal) Release(s *Snapshot) {
+	i := j.index(s)
+	if i < 0 {
+		return
+	}
+	s.journal = nil
+	j.snapshots = append(j.snapshots[:i], j.snapshots[i+1:]...)
+}
+
+func (j *Journal) index(s *Snapshot) int {
+	if s == nil || s.journal != j {
+		return -1
+	}
+	for i, active := range j.snapshots {
+		if active == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// beforeWrite is called before the bindings of env are modified.
+// The environments created after a snapshot are not part of its state, so
+// their bindings are not saved for it; most environments are never saved at all.
+func (j *Journal) beforeWrite(env *Environment) {
+	if len(j.snapshots) == 0 {
+		return
+	}
+	// If the latest snapshot has saved the bindings, they have been copied since
+	// the older ones were taken too.
+	latest := j.snapshots[len(j.snapshots)-1]
+	if latest.seq <= env.created {
+		return
+	}
+	if _, ok := latest.envs[env]; ok {
+		return
+	}
+	for _, s := range j.snapshots {
+		if s.seq <= env.created {
+			continue
+		}
+		if _, ok := s.envs[env]; !ok {
+			s.envs[env] = env.store
+		}
+	}
+	env.store = maps.Clone(env.store)
+}
+
+// BeforeWriteVariable saves v for the active snapshots, if it has not been saved since they
+// were taken. It must be called before v is modified.
+func (j *Journal) BeforeWriteVariable(v *Variable) {
+	if j == nil || len(j.snapshots) == 0 {
+		return
+	}
+	if _, ok := j.snapshots[len(j.snapshots)-1].vars[v]; ok {
+		return
+	}
+	for _, s := range j.snapshots {
+		if _, ok := s.vars[v]; !ok {
+			saved := *v
+			saved.PossibleTypes = maps.Clone(v.PossibleTypes)
+			s.vars[v] = saved
+		}
+	}
+}
+
+// BeforeWriteStruct saves the fields of st for the active snapshots, if they have not been saved
+// since the snapshots were taken. It must be called before a field of st is set.
+func (j *Journal) BeforeWriteStruct(st *Struct) {
+	if j == nil || len(j.snapshots) == 0 {
+		return
+	}
+	if _, ok := j.snapshots[len(j.snapshots)-1].structs[st]; ok {
+		return
+	}
+	for _, s := range j.snapshots {
+		if _, ok := s.structs[st]; !ok {
+			s.structs[st] = maps.Clone(st.Fields)
+		}
+	}
+}
+
+// BeforeWritePointer saves p for the active snapshots, if it has not been saved since they
+// were taken. It must be called before p is modified.
+func (j *Journal) BeforeWritePointer(p *Pointer) {
+	if j == nil || len(j.snapshots) == 0 {
+		return
+	}
+	if _, ok := j.snapshots[len(j.snapshots)-1].ptrs[p]; ok {
+		return
+	}
+	for _, s := range j.snapshots {
+		if _, ok := s.ptrs[p]; !ok {
+			s.ptrs[p] = *p
+		}
+	}
+}
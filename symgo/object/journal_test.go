@@ -0,0 +1,109 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJournal_SnapshotRestore(t *testing.T) {
+	j := NewJournal()
+	global := NewEnvironment()
+	global.SetJournal(j)
+	global.SetLocal("x", &Integer{Value: 1})
+	v := &Variable{Name: "v", Value: &String{Value: "initial"}, IsEvaluated: true}
+	global.SetLocal("v", v)
+
+	bindings := func(env *Environment) map[string]string {
+		got := make(map[string]string)
+		env.Walk(func(name string, obj Object) bool {
+			got[name] = obj.Inspect()
+			return true
+		})
+		return got
+	}
+	assign := func(val string) {
+		j.BeforeWriteVariable(v)
+		v.Value = &String{Value: val}
+	}
+
+	s1 := j.Snapshot()
+	if len(s1.envs) != 0 || len(s1.vars) != 0 {
+		t.Fatalf("taking a snapshot must not copy anything, got %d environments and %d variables", len(s1.envs), len(s1.vars))
+	}
+
+	global.Set("x", &Integer{Value: 2})
+	assign("first")
+	local := NewEnclosedEnvironment(global)
+	local.SetLocal("y", &Integer{Value: 3})
+	if _, ok := s1.envs[local]; ok {
+		t.Errorf("an environment created after the snapshot must not be saved")
+	}
+
+	s2 := j.Snapshot()
+	global.SetLocal("z", &Integer{Value: 4})
+	assign("second")
+	local.Set("y", &Integer{Value: 5})
+
+	j.Restore(s2)
+	want := map[string]string{"x": "2", "v": `"first"`}
+	if diff := cmp.Diff(want, bindings(global)); diff != "" {
+		t.Errorf("bindings after restoring s2 mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"y": "3", "x": "2", "v": `"first"`}, bindings(local)); diff != "" {
+		t.Errorf("local bindings after restoring s2 mismatch (-want +got):\n%s", diff)
+	}
+
+	j.Restore(s1)
+	want = map[string]string{"x": "1", "v": `"initial"`}
+	if diff := cmp.Diff(want, bindings(global)); diff != "" {
+		t.Errorf("bindings after restoring s1 mismatch (-want +got):\n%s", diff)
+	}
+	if s2.journal != nil {
+		t.Errorf("restoring s1 must release s2")
+	}
+
+	// s1 can be restored again after more writes.
+	global.Set("x", &Integer{Value: 6})
+	assign("third")
+	j.Restore(s1)
+	if diff := cmp.Diff(want, bindings(global)); diff != "" {
+		t.Errorf("bindings after restoring s1 again mismatch (-want +got):\n%s", diff)
+	}
+
+	// After Release, the writes are no longer tracked.
+	j.Release(s1)
+	global.Set("x", &Integer{Value: 7})
+	j.Restore(s1)
+	want = map[string]string{"x": "7", "v": `"initial"`}
+	if diff := cmp.Diff(want, bindings(global)); diff != "" {
+		t.Errorf("bindings after restoring a released snapshot mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestJournal_StructAndPointer(t *testing.T) {
+	j := NewJournal()
+	st := &Struct{Fields: map[string]Object{"Name": &String{Value: "initial"}}}
+	cell := &Variable{Name: "x", Value: &Integer{Value: 1}, IsEvaluated: true}
+	ptr := &Pointer{Value: cell.Value, Cell: cell}
+
+	s := j.Snapshot()
+	j.BeforeWriteStruct(st)
+	st.Set("Name", &String{Value: "changed"})
+	st.Set("Handler", &Integer{Value: 2})
+	j.BeforeWritePointer(ptr)
+	j.BeforeWriteVariable(cell)
+	ptr.Value = &Integer{Value: 3}
+	cell.Value = ptr.Value
+
+	j.Restore(s)
+	if diff := cmp.Diff(`{Name: "initial"}`, st.Inspect()); diff != "" {
+		t.Errorf("struct after restoring mismatch (-want +got):\n%s", diff)
+	}
+	if got := ptr.Pointee().Inspect(); got != "1" {
+		t.Errorf("pointee after restoring = %s, want 1", got)
+	}
+	if ptr.Value.Inspect() != "1" {
+		t.Errorf("pointer value after restoring = %s, want 1", ptr.Value.Inspect())
+	}
+}
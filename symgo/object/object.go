@@ -52,6 +52,7 @@ const (
 	UNRESOLVED_TYPE_OBJ       ObjectType = "UNRESOLVED_TYPE"
 	PANIC_OBJ                 ObjectType = "PANIC"
 	AMBIGUOUS_SELECTOR_OBJ    ObjectType = "AMBIGUOUS_SELECTOR"
+	STRING_TEMPLATE_OBJ       ObjectType = "STRING_TEMPLATE"
 )
 
 // Object is the interface that all value types in our symbolic engine will implement.
@@ -124,6 +125,109 @@ func (s *String) Release() {
 	stringPool.Put(s)
 }
 
+// --- StringTemplate Object ---
+
+// StringTemplatePart is a part of a StringTemplate: either a constant string,
+// or a hole for a value unknown at analysis time.
+type StringTemplatePart struct {
+	Const bool
+	// Value is the constant string, for a constant part.
+	Value string
+	// Name is the source expression of a hole, e.g. "version", if it is known.
+	Name string
+	// Object is the value of a hole, typically a *SymbolicPlaceholder.
+	Object Object
+}
+
+// StringTemplate represents a string built from constant and non-constant parts,
+// e.g. `"/api/" + version + "/users"` or `fmt.Sprintf("SELECT * FROM %s", table)`.
+// Its parts keep the constant skeleton of the string; adjacent constants are merged.
+type StringTemplate struct {
+	BaseObject
+	Parts []StringTemplatePart
+}
+
+// NewStringTemplate joins the parts, merging the adjacent constant parts and flattening
+// the nested templates. It returns a *String if all the parts are constant.
+func NewStringTemplate(parts ...StringTemplatePart) Object {
+	t := &StringTemplate{}
+	for _, p := range parts {
+		if !p.Const {
+			if nested, ok := p.Object.(*StringTemplate); ok {
+				for _, np := range nested.Parts {
+					t.append(np)
+				}
+				continue
+			}
+			if str, ok := p.Object.(*String); ok {
+				p = StringTemplatePart{Const: true, Value: str.Value}
+			}
+		}
+		t.append(p)
+	}
+	if len(t.Parts) == 0 {
+		return &String{Value: ""}
+	}
+	if len(t.Parts) == 1 && t.Parts[0].Const {
+		return &String{Value: t.Parts[0].Value}
+	}
+	return t
+}
+
+func (t *StringTemplate) append(p StringTemplatePart) {
+	if p.Const {
+		if p.Value == "" {
+			return
+		}
+		if n := len(t.Parts); n > 0 && t.Parts[n-1].Const {
+			t.Parts[n-1].Value += p.Value
+			return
+		}
+	}
+	t.Parts = append(t.Parts, p)
+}
+
+// Type returns the type of the StringTemplate object.
+func (t *StringTemplate) Type() ObjectType { return STRING_TEMPLATE_OBJ }
+
+// Inspect returns the skeleton of the string, with the holes shown as `{name}`.
+func (t *StringTemplate) Inspect() string {
+	var b strings.Builder
+	b.WriteString("<Template: ")
+	for _, p := range t.Parts {
+		if p.Const {
+			b.WriteString(p.Value)
+			continue
+		}
+		b.WriteString("{")
+		b.WriteString(p.Name)
+		b.WriteString("}")
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// Skeleton returns the string with each hole replaced by hole, e.g. "/api/*/users" for "*"
+// or "SELECT * FROM ?" for "?".
+func (t *StringTemplate) Skeleton(hole string) string {
+	var b strings.Builder
+	for _, p := range t.Parts {
+		if p.Const {
+			b.WriteString(p.Value)
+		} else {
+			b.WriteString(hole)
+		}
+	}
+	return b.String()
+}
+
+// Clone creates a shallow copy.
+func (t *StringTemplate) Clone() Object {
+	c := *t
+	c.Parts = append([]StringTemplatePart(nil), t.Parts...)
+	return &c
+}
+
 // --- Integer Object ---
 
 // Integer represents an integer value.
@@ -420,7 +524,11 @@ type Error struct {
 	Message   string
 	Pos       token.Pos
 	CallStack []*CallFrame
-	fset      *token.FileSet // FileSet to resolve positions
+	// Panic is the value of a panic of the evaluator itself, for an internal error (a bug of
+	// symgo or of an intrinsic) recovered as an error, and GoStack is the Go stack of the panic.
+	Panic   any
+	GoStack []byte
+	fset    *token.FileSet // FileSet to resolve positions
 }
 
 // Type returns the type of the Error object.
@@ -485,6 +593,11 @@ type SymbolicPlaceholder struct {
 	// For interface method calls, this holds the set of possible concrete field types
 	// that the receiver variable could hold.
 	PossibleConcreteTypes []*scanner.FieldType
+	// For a composite literal of an unresolved type (e.g. out of the scan policy), this holds
+	// the values of its keyed fields, which are returned by the selectors on the placeholder.
+	Fields map[string]Object
+	// For a reflect.Value (the result of reflect.ValueOf), this holds the reflected value.
+	Reflected Object
 	// Cache for the Inspect() result to avoid repeated string building
 	inspectCache string
 	cacheValid   bool
@@ -554,6 +667,11 @@ type Variable struct {
 	DeclEnv       *Environment         // Environment where the variable was declared
 	DeclPkg       *scanner.PackageInfo // Package where the variable was declared
 	PossibleTypes map[string]struct{}  // Used for tracking possible types for interface variables
+
+	// PossibleConcreteTypes are the concrete types of the values assigned to the variable along
+	// the evaluated paths, if it is interface-typed (e.g. `any`). The method calls and the type
+	// assertions on the variable are explored against each of them.
+	PossibleConcreteTypes []*scanner.FieldType
 }
 
 // Type returns the type of the Variable object.
@@ -579,6 +697,17 @@ func (v *Variable) Clone() Object {
 type Pointer struct {
 	BaseObject
 	Value Object
+	// Cell is the variable the pointer was taken from (`&x`), or nil. It is shared with the
+	// variable and its other pointers, so a write through one of them is seen by all of them.
+	Cell *Variable
+}
+
+// Pointee returns the object the pointer points to: the current value of its Cell, if any.
+func (p *Pointer) Pointee() Object {
+	if p.Cell != nil && p.Cell.IsEvaluated && p.Cell.Value != nil {
+		return p.Cell.Value
+	}
+	return p.Value
 }
 
 // Type returns the type of the Pointer object.
@@ -676,12 +805,21 @@ func (n *Nil) Clone() Object {
 
 // Slice represents a slice literal. Its type is represented by a FieldType,
 // which captures the slice structure (e.g., []User).
+//
+// Elements holds the elements of the slice when they are known (see HasKnownElements).
+// Otherwise, it holds the elements known to be stored in the slice, e.g. the ones appended
+// to a slice of unknown contents, whose positions are unknown.
 type Slice struct {
 	BaseObject
 	Elements       []Object
 	SliceFieldType *scanner.FieldType
-	Len            int64
-	Cap            int64
+	Len            int64 // -1 if unknown
+	Cap            int64 // -1 if unknown
+}
+
+// HasKnownElements reports whether Elements holds all the elements of the slice, in order.
+func (s *Slice) HasKnownElements() bool {
+	return s.Len >= 0 && int64(len(s.Elements)) == s.Len
 }
 
 // Type returns the type of the Slice object.
@@ -777,8 +915,10 @@ func (c *Channel) Clone() Object {
 
 // Environment holds the bindings for variables and functions.
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	store   map[string]Object
+	outer   *Environment
+	journal *Journal // Tracks the writes for snapshots; inherited by the enclosed environments.
+	created int      // The number of snapshots taken by the journal when the environment was created.
 }
 
 // Object pools for reusing common objects
@@ -816,6 +956,8 @@ func NewEnvironment() *Environment {
 		delete(env.store, k)
 	}
 	env.outer = nil
+	env.journal = nil
+	env.created = 0
 	return env
 }
 
@@ -823,9 +965,22 @@ func NewEnvironment() *Environment {
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
+	if outer != nil && outer.journal != nil {
+		env.SetJournal(outer.journal)
+	}
 	return env
 }
 
+// SetJournal makes j track the writes to the environment, and to the environments
+// enclosed by it from now on.
+func (e *Environment) SetJournal(j *Journal) {
+	e.journal = j
+	e.created = 0
+	if j != nil {
+		e.created = j.seq
+	}
+}
+
 // Get retrieves an object by name from the environment, checking outer scopes if necessary.
 func (e *Environment) Get(name string) (Object, bool) {
 	obj, ok := e.store[name]
@@ -839,6 +994,7 @@ func (e *Environment) Get(name string) (Object, bool) {
 // to find where the variable is defined.
 func (e *Environment) Set(name string, val Object) Object {
 	if _, ok := e.store[name]; ok {
+		e.beforeWrite()
 		e.store[name] = val
 		return val
 	}
@@ -846,6 +1002,7 @@ func (e *Environment) Set(name string, val Object) Object {
 		return e.outer.Set(name, val)
 	}
 	// If not found anywhere, define it in the current (innermost) scope.
+	e.beforeWrite()
 	e.store[name] = val
 	return val
 }
@@ -853,10 +1010,17 @@ func (e *Environment) Set(name string, val Object) Object {
 // SetLocal stores an object by name in the local (current) environment only.
 // This is used for `:=` declarations.
 func (e *Environment) SetLocal(name string, val Object) Object {
+	e.beforeWrite()
 	e.store[name] = val
 	return val
 }
 
+func (e *Environment) beforeWrite() {
+	if e.journal != nil {
+		e.journal.beforeWrite(e)
+	}
+}
+
 // IsEmpty checks if the environment has any local bindings.
 func (e *Environment) IsEmpty() bool {
 	return len(e.store) == 0
@@ -885,6 +1049,11 @@ func (e *Environment) WalkLocal(fn func(name string, obj Object) bool) {
 	}
 }
 
+// Outer returns the enclosing environment, or nil for a top-level environment.
+func (e *Environment) Outer() *Environment {
+	return e.outer
+}
+
 // Release returns the environment to the pool for reuse.
 // Only call this on environments that are no longer needed.
 func (e *Environment) Release() {
@@ -1090,21 +1259,113 @@ func (t *Type) Clone() Object {
 
 // --- Tracer Interface ---
 
+// TraceEventKind is the kind of a TraceEvent.
+type TraceEventKind int
+
+const (
+	// TraceNode is the evaluation of an AST node (Node).
+	TraceNode TraceEventKind = iota
+	// TraceEnterFunction is the start of the evaluation of the body of a function (Function).
+	TraceEnterFunction
+	// TraceCallResolved is a call whose callee (Function) and arguments have been evaluated.
+	TraceCallResolved
+	// TraceBranchExplored is the evaluation of a branch of an if, switch, type switch, or select statement.
+	TraceBranchExplored
+	// TracePlaceholderCreated is a call of Function that results in a symbolic placeholder (Object),
+	// e.g. a call of a function outside of the analysis scope or a recursive call.
+	TracePlaceholderCreated
+	// TraceErrorRecovered is an error (Object) that is logged and skipped, the evaluation going on.
+	TraceErrorRecovered
+	// TraceFieldAccessed is the selection of a struct field (Field) declared by Type, read or
+	// written, e.g. `x.Name`. For a promoted field, Type is the embedded struct declaring it.
+	TraceFieldAccessed
+)
+
+// String returns the name of the kind, e.g. "enter-function".
+func (k TraceEventKind) String() string {
+	switch k {
+	case TraceNode:
+		return "node"
+	case TraceEnterFunction:
+		return "enter-function"
+	case TraceCallResolved:
+		return "call-resolved"
+	case TraceBranchExplored:
+		return "branch-explored"
+	case TracePlaceholderCreated:
+		return "placeholder-created"
+	case TraceErrorRecovered:
+		return "error-recovered"
+	case TraceFieldAccessed:
+		return "field-accessed"
+	}
+	return fmt.Sprintf("TraceEventKind(%d)", int(k))
+}
+
 // TraceEvent represents a single event in the evaluation trace.
+//
+// Node and Env are only set for the TraceNode events, so a tracer interested in the
+// evaluated nodes only can skip the events without a Node.
 type TraceEvent struct {
+	Kind TraceEventKind
 	Step int
 	Node ast.Node
 	Pkg  *scanner.PackageInfo
 	Env  *Environment
+
+	// Pos is the position of the event: the call, the function, the branch, or the error.
+	Pos token.Pos
+	// Function is the called or entered function, for the function and call events.
+	Function Object
+	// Object is the created placeholder, or the recovered error.
+	Object Object
+	// Type and Field are the struct type and its accessed field, for the field events.
+	Type  *scanner.TypeInfo
+	Field *scanner.FieldInfo
 }
 
 // Tracer is an interface for instrumenting the symbolic execution process.
 // An implementation can be passed to the interpreter to track which AST nodes
-// are being evaluated.
+// are being evaluated, and which functions, calls, and branches are explored.
 type Tracer interface {
 	Trace(event TraceEvent)
 }
 
+// TraceHooks is a Tracer calling the hook of the kind of each event. The nil hooks are skipped.
+type TraceHooks struct {
+	OnNode               func(event TraceEvent)
+	OnEnterFunction      func(event TraceEvent)
+	OnCallResolved       func(event TraceEvent)
+	OnBranchExplored     func(event TraceEvent)
+	OnPlaceholderCreated func(event TraceEvent)
+	OnErrorRecovered     func(event TraceEvent)
+	OnFieldAccessed      func(event TraceEvent)
+}
+
+// Trace calls the hook of the kind of the event.
+func (h *TraceHooks) Trace(event TraceEvent) {
+	var hook func(TraceEvent)
+	switch event.Kind {
+	case TraceNode:
+		hook = h.OnNode
+	case TraceEnterFunction:
+		hook = h.OnEnterFunction
+	case TraceCallResolved:
+		hook = h.OnCallResolved
+	case TraceBranchExplored:
+		hook = h.OnBranchExplored
+	case TracePlaceholderCreated:
+		hook = h.OnPlaceholderCreated
+	case TraceErrorRecovered:
+		hook = h.OnErrorRecovered
+	case TraceFieldAccessed:
+		hook = h.OnFieldAccessed
+	}
+	if hook != nil {
+		hook(event)
+	}
+}
+
 // ScanPolicyFunc is a function that determines whether a package should be scanned from source.
 type ScanPolicyFunc func(importPath string) bool
 
@@ -0,0 +1,186 @@
+package symgo
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// Decision is the decision of a ScanPolicy for a package.
+type Decision int
+
+const (
+	// DecisionSkip does not scan the package: its functions and types are symbolic placeholders.
+	DecisionSkip Decision = iota
+	// DecisionDecls scans the declarations of the package (goscan.LoadDecls), so its types are
+	// resolved, but its function bodies are not evaluated.
+	DecisionDecls
+	// DecisionFull scans the package and evaluates its function bodies (goscan.LoadFull).
+	DecisionFull
+)
+
+// String returns the name of the decision: "skip", "decls" or "full".
+func (d Decision) String() string {
+	switch d {
+	case DecisionSkip:
+		return "skip"
+	case DecisionDecls:
+		return "decls"
+	case DecisionFull:
+		return "full"
+	default:
+		return fmt.Sprintf("Decision(%d)", int(d))
+	}
+}
+
+// PolicyMatch explains the decision of a ScanPolicy for a package.
+type PolicyMatch struct {
+	Decision Decision
+	// Rule is the rule which decided, e.g. `Include("example.com/app/...")`, or "default".
+	Rule string
+}
+
+// ScanPolicy decides which packages are scanned and evaluated, from a list of rules built with
+// its methods, e.g.
+//
+//	symgo.NewScanPolicy().Include("example.com/app/...").ExcludeStdlib().DeclsOnly("k8s.io/...")
+//
+// The last matching rule wins, and packages matching no rule get the default decision
+// (DecisionSkip unless set with Default). A pattern is an import path, where "..." matches any
+// string, e.g. "example.com/app/..." (the package and its sub-packages) or
+// "example.com/.../internal". The decisions are memoized per package.
+//
+// A ScanPolicy is given to the interpreter with WithPolicy. It must not be modified after that.
+type ScanPolicy struct {
+	rules []policyRule
+	def   Decision
+
+	mu    sync.Mutex
+	cache map[string]PolicyMatch
+}
+
+type policyRule struct {
+	name     string
+	pattern  string // empty for the rules which are not a pattern, e.g. ExcludeStdlib
+	match    func(importPath string) bool
+	decision Decision
+}
+
+// NewScanPolicy returns a policy without rules, which skips every package.
+func NewScanPolicy() *ScanPolicy {
+	return &ScanPolicy{def: DecisionSkip}
+}
+
+// Include scans and evaluates the packages matching the patterns.
+func (p *ScanPolicy) Include(patterns ...string) *ScanPolicy {
+	return p.addPatterns("Include", DecisionFull, patterns)
+}
+
+// DeclsOnly scans the declarations of the packages matching the patterns, without evaluating
+// their function bodies.
+func (p *ScanPolicy) DeclsOnly(patterns ...string) *ScanPolicy {
+	return p.addPatterns("DeclsOnly", DecisionDecls, patterns)
+}
+
+// Exclude skips the packages matching the patterns.
+func (p *ScanPolicy) Exclude(patterns ...string) *ScanPolicy {
+	return p.addPatterns("Exclude", DecisionSkip, patterns)
+}
+
+// ExcludeStdlib skips the packages of the standard library, i.e. the packages whose first path
+// element has no dot.
+func (p *ScanPolicy) ExcludeStdlib() *ScanPolicy {
+	return p.add(policyRule{name: "ExcludeStdlib()", match: isStdlib, decision: DecisionSkip})
+}
+
+// Default sets the decision for the packages matching no rule.
+func (p *ScanPolicy) Default(d Decision) *ScanPolicy {
+	p.def = d
+	p.reset()
+	return p
+}
+
+func (p *ScanPolicy) addPatterns(method string, d Decision, patterns []string) *ScanPolicy {
+	for _, pattern := range patterns {
+		p.add(policyRule{
+			name:     fmt.Sprintf("%s(%q)", method, pattern),
+			pattern:  pattern,
+			match:    scanner.CompilePattern(pattern),
+			decision: d,
+		})
+	}
+	return p
+}
+
+func (p *ScanPolicy) add(rule policyRule) *ScanPolicy {
+	p.rules = append(p.rules, rule)
+	p.reset()
+	return p
+}
+
+func (p *ScanPolicy) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache = nil
+}
+
+// Decide returns the decision for the package with the given import path.
+func (p *ScanPolicy) Decide(importPath string) Decision {
+	return p.Explain(importPath).Decision
+}
+
+// Explain returns the decision for the package with the given import path, with the rule
+// which decided it. It is meant for debugging the policy.
+func (p *ScanPolicy) Explain(importPath string) PolicyMatch {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if m, ok := p.cache[importPath]; ok {
+		return m
+	}
+
+	m := PolicyMatch{Decision: p.def, Rule: "default"}
+	for i := len(p.rules) - 1; i >= 0; i-- {
+		if p.rules[i].match(importPath) {
+			m = PolicyMatch{Decision: p.rules[i].decision, Rule: p.rules[i].name}
+			break
+		}
+	}
+	if p.cache == nil {
+		p.cache = make(map[string]PolicyMatch)
+	}
+	p.cache[importPath] = m
+	return m
+}
+
+// ShouldScan reports whether the package is scanned from source, i.e. its decision is not
+// DecisionSkip. It can be used as a ScanPolicyFunc.
+func (p *ScanPolicy) ShouldScan(importPath string) bool {
+	return p.Decide(importPath) != DecisionSkip
+}
+
+// applyLoadModes sets the load modes of the scanner for the default decision and the pattern
+// rules, in the same order, so that the last matching rule wins there too.
+func (p *ScanPolicy) applyLoadModes(s *goscan.Scanner) {
+	if p.def == DecisionDecls {
+		s.SetPackageLoadMode("...", goscan.LoadDecls)
+	}
+	for _, rule := range p.rules {
+		switch {
+		case rule.pattern == "":
+			continue
+		case rule.decision == DecisionFull:
+			s.SetPackageLoadMode(rule.pattern, goscan.LoadFull)
+		case rule.decision == DecisionDecls:
+			s.SetPackageLoadMode(rule.pattern, goscan.LoadDecls)
+		}
+	}
+}
+
+// isStdlib reports whether the import path looks like a package of the standard library.
+func isStdlib(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
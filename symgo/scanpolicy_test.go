@@ -0,0 +1,119 @@
+package symgo_test
+
+import (
+	"context"
+	"testing"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+func TestScanPolicy_Explain(t *testing.T) {
+	policy := symgo.NewScanPolicy().
+		Include("example.com/app/...").
+		ExcludeStdlib().
+		DeclsOnly("k8s.io/...").
+		Exclude("example.com/app/.../gen")
+
+	cases := []struct {
+		importPath string
+		want       symgo.PolicyMatch
+	}{
+		{importPath: "example.com/app", want: symgo.PolicyMatch{Decision: symgo.DecisionFull, Rule: `Include("example.com/app/...")`}},
+		{importPath: "example.com/app/api", want: symgo.PolicyMatch{Decision: symgo.DecisionFull, Rule: `Include("example.com/app/...")`}},
+		{importPath: "example.com/app/api/gen", want: symgo.PolicyMatch{Decision: symgo.DecisionSkip, Rule: `Exclude("example.com/app/.../gen")`}},
+		{importPath: "k8s.io/client-go/kubernetes", want: symgo.PolicyMatch{Decision: symgo.DecisionDecls, Rule: `DeclsOnly("k8s.io/...")`}},
+		{importPath: "net/http", want: symgo.PolicyMatch{Decision: symgo.DecisionSkip, Rule: "ExcludeStdlib()"}},
+		{importPath: "github.com/other/lib", want: symgo.PolicyMatch{Decision: symgo.DecisionSkip, Rule: "default"}},
+	}
+	for _, tc := range cases {
+		if got := policy.Explain(tc.importPath); got != tc.want {
+			t.Errorf("Explain(%q) = %+v, want %+v", tc.importPath, got, tc.want)
+		}
+	}
+
+	// Changing the policy drops the memoized decisions.
+	policy.Default(symgo.DecisionDecls)
+	if got := policy.Decide("github.com/other/lib"); got != symgo.DecisionDecls {
+		t.Errorf("Decide() after Default() = %s, want %s", got, symgo.DecisionDecls)
+	}
+}
+
+func TestWithPolicy(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/me",
+		"main.go": `
+package main
+import "example.com/me/foreign/lib"
+func main() {
+	helper()
+	lib.DoSomething()
+}
+func helper() { reached() }
+func reached() {}
+`,
+		"foreign/lib/lib.go": `
+package lib
+type ForeignType struct{ ID string }
+func DoSomething() { ShouldNotBeCalled() }
+func ShouldNotBeCalled() {}
+`,
+	}
+	dir, cleanup := writeTestFiles(t, files)
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithWorkDir(dir))
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	policy := symgo.NewScanPolicy().Include("example.com/me/...").DeclsOnly("example.com/me/foreign/...")
+	interp, err := symgo.NewInterpreter(s, symgo.WithPolicy(policy))
+	if err != nil {
+		t.Fatalf("could not create symgo interpreter: %v", err)
+	}
+
+	for path, want := range map[string]goscan.LoadMode{
+		"example.com/me":             goscan.LoadFull,
+		"example.com/me/foreign/lib": goscan.LoadDecls,
+	} {
+		if got := s.LoadModeFor(path); got != want {
+			t.Errorf("LoadModeFor(%q) = %s, want %s", path, got, want)
+		}
+	}
+
+	var reached, shouldNotBeCalledReached bool
+	interp.RegisterIntrinsic("example.com/me.reached", func(ctx context.Context, i *symgo.Interpreter, args []object.Object) object.Object {
+		reached = true
+		return nil
+	})
+	interp.RegisterIntrinsic("example.com/me/foreign/lib.ShouldNotBeCalled", func(ctx context.Context, i *symgo.Interpreter, args []object.Object) object.Object {
+		shouldNotBeCalledReached = true
+		return nil
+	})
+
+	ctx := context.Background()
+	pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/me")
+	if err != nil {
+		t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+	}
+	for _, file := range pkg.AstFiles {
+		if _, err := interp.Eval(ctx, file, pkg); err != nil {
+			t.Fatalf("Eval(file) returned an error: %v", err)
+		}
+	}
+	mainFunc, ok := interp.FindObjectInPackage(ctx, "example.com/me", "main")
+	if !ok {
+		t.Fatal("main function not found")
+	}
+	if _, err := interp.Apply(ctx, mainFunc, nil, pkg); err != nil {
+		t.Fatalf("Apply(main) returned an error: %v", err)
+	}
+
+	if !reached {
+		t.Error("the body of helper was not evaluated")
+	}
+	if shouldNotBeCalledReached {
+		t.Error("the body of a declarations-only package was evaluated")
+	}
+}
@@ -0,0 +1,255 @@
+package symgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// containerValues holds the values stored in the value containers of the standard library,
+// atomic.Value, sync.Map and the values of context.WithValue, so that the values loaded from
+// them keep the types, or the functions, which were stored.
+type containerValues struct {
+	mu     sync.Mutex
+	values map[any][]Object // by container (see containerKey)
+}
+
+// add stores a value in a container.
+func (c *containerValues) add(key any, val Object) {
+	val = unwrapVariable(val)
+	if val == nil || val == object.NIL {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		c.values = make(map[any][]Object)
+	}
+	for _, stored := range c.values[key] {
+		if stored == val {
+			return
+		}
+	}
+	c.values[key] = append(c.values[key], val)
+}
+
+// get returns the values stored in a container.
+func (c *containerValues) get(key any) []Object {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key]
+}
+
+// containerKey returns the identity of a container given as the receiver of a method: the
+// value a pointer points to, or a variable holds. A field of a symbolic value is a new
+// placeholder at each access, so it is identified by the declaration of the field instead,
+// which is shared by the values of the struct type.
+func containerKey(obj Object) any {
+	for {
+		switch o := obj.(type) {
+		case *Pointer:
+			if o.Value != nil {
+				obj = o.Value
+				continue
+			}
+		case *Variable:
+			if o.Value != nil {
+				obj = o.Value
+				continue
+			}
+		case *SymbolicPlaceholder:
+			if ft := o.FieldType(); ft != nil && strings.HasPrefix(o.Reason, "field access") {
+				return ft
+			}
+		}
+		return obj
+	}
+}
+
+// contextKey returns the identity of a key of context.WithValue: a constant by its value, a
+// value of a named type by its type, as the keys are usually values of an unexported type,
+// or else the object itself.
+func contextKey(obj Object) any {
+	switch o := unwrapVariable(obj).(type) {
+	case *String:
+		return "string:" + o.Value
+	case *Integer:
+		return fmt.Sprintf("int:%d", o.Value)
+	case *Instance:
+		return "type:" + o.TypeName
+	case *Pointer:
+		if inst, ok := o.Value.(*Instance); ok {
+			return "type:*" + inst.TypeName
+		}
+		return o
+	default:
+		if ti := o.TypeInfo(); ti != nil && ti.Name != "" {
+			return "type:" + ti.PkgPath + "." + ti.Name
+		}
+		return o
+	}
+}
+
+// loadedValue returns the value loaded from a container holding the stored values: the value
+// itself if only one was stored, a function applying all the functions if they are functions,
+// or else a value of type any carrying the concrete types of the values.
+func (i *Interpreter) loadedValue(key string, stored []Object) Object {
+	switch len(stored) {
+	case 0:
+		return typedPlaceholder("value loaded by "+key, &scanner.FieldType{Name: "any", IsBuiltin: true})
+	case 1:
+		return stored[0]
+	}
+
+	fns := make([]*Function, 0, len(stored))
+	var types []*scanner.FieldType
+	for _, val := range stored {
+		if fn, ok := val.(*Function); ok {
+			fns = append(fns, fn)
+		}
+		types = append(types, possibleConcreteTypes(val)...)
+	}
+	if len(fns) == len(stored) {
+		return &object.Intrinsic{Fn: func(ctx context.Context, args ...Object) Object {
+			var result Object = object.NIL
+			for _, fn := range fns {
+				r, err := i.Apply(ctx, fn, args, fn.Package)
+				if err != nil {
+					i.logger.DebugContext(ctx, "failed to apply a loaded function", "container", key, "error", err)
+					continue
+				}
+				result = r
+			}
+			return result
+		}}
+	}
+	p := typedPlaceholder("value loaded by "+key, &scanner.FieldType{Name: "any", IsBuiltin: true})
+	p.PossibleConcreteTypes = types
+	return p
+}
+
+// possibleConcreteTypes returns the concrete types of a stored value: the type of a struct
+// value or of a pointer to it, or the possible types of an interface value.
+func possibleConcreteTypes(val Object) []*scanner.FieldType {
+	switch v := val.(type) {
+	case *SymbolicPlaceholder:
+		return v.PossibleConcreteTypes
+	case *Pointer:
+		if inst, ok := v.Value.(*Instance); ok && inst.TypeInfo() != nil {
+			elem := possibleConcreteTypes(inst)
+			if len(elem) == 1 {
+				return []*scanner.FieldType{{IsPointer: true, Elem: elem[0], Definition: elem[0].Definition}}
+			}
+		}
+	case *Instance:
+		if ti := v.TypeInfo(); ti != nil && ti.Name != "" && ti.Kind != scanner.InterfaceKind && !ti.Unresolved {
+			return []*scanner.FieldType{{Name: ti.Name, TypeName: ti.Name, FullImportPath: ti.PkgPath, Definition: ti}}
+		}
+	}
+	return nil
+}
+
+// typedPlaceholder returns a symbolic value of a type.
+func typedPlaceholder(reason string, ft *scanner.FieldType) *SymbolicPlaceholder {
+	p := &SymbolicPlaceholder{Reason: reason}
+	p.SetFieldType(ft)
+	return p
+}
+
+// symbolicBool is the symbolic result of the functions reporting whether a value was found.
+func symbolicBool(reason string) Object {
+	return typedPlaceholder(reason, &scanner.FieldType{Name: "bool", IsBuiltin: true})
+}
+
+// contextType is the type of the contexts returned by context.WithValue.
+var contextType = &scanner.TypeInfo{Name: "Context", PkgPath: "context", Kind: scanner.InterfaceKind}
+
+// containerIntrinsics returns the intrinsics of the value containers of the standard library,
+// whose stored values are kept in the interpreter, by container for atomic.Value and sync.Map,
+// and by key for context.WithValue.
+func containerIntrinsics() map[string]IntrinsicFunc {
+	store := func(name string, valueIndex int, result func(i *Interpreter, key string, stored []Object) Object) IntrinsicFunc {
+		return func(ctx context.Context, i *Interpreter, args []Object) Object {
+			if valueIndex >= len(args) {
+				return &Error{Message: "too few arguments in call to " + name}
+			}
+			key := containerKey(args[0])
+			var before []Object
+			if result != nil {
+				before = i.containers.get(key)
+			}
+			i.containers.add(key, args[valueIndex])
+			if result == nil {
+				return object.NIL
+			}
+			return result(i, name, before)
+		}
+	}
+	load := func(name string, result func(value Object) Object) IntrinsicFunc {
+		return func(ctx context.Context, i *Interpreter, args []Object) Object {
+			if len(args) == 0 {
+				return &Error{Message: "missing receiver in call to " + name}
+			}
+			return result(i.loadedValue(name, i.containers.get(containerKey(args[0]))))
+		}
+	}
+	single := func(value Object) Object { return value }
+	withFound := func(name string) func(value Object) Object {
+		return func(value Object) Object {
+			return &MultiReturn{Values: []Object{value, symbolicBool("result of " + name)}}
+		}
+	}
+	loaded := func(i *Interpreter, name string, before []Object) Object {
+		return &MultiReturn{Values: []Object{i.loadedValue(name, before), symbolicBool("result of " + name)}}
+	}
+
+	return map[string]IntrinsicFunc{
+		"(*sync/atomic.Value).Store": store("(*sync/atomic.Value).Store", 1, nil),
+		"(*sync/atomic.Value).Load":  load("(*sync/atomic.Value).Load", single),
+		"(*sync/atomic.Value).Swap": store("(*sync/atomic.Value).Swap", 1, func(i *Interpreter, name string, before []Object) Object {
+			return i.loadedValue(name, before)
+		}),
+		"(*sync/atomic.Value).CompareAndSwap": store("(*sync/atomic.Value).CompareAndSwap", 2, func(i *Interpreter, name string, before []Object) Object {
+			return symbolicBool("result of " + name)
+		}),
+
+		"(*sync.Map).Store":         store("(*sync.Map).Store", 2, nil),
+		"(*sync.Map).LoadOrStore":   store("(*sync.Map).LoadOrStore", 2, loaded),
+		"(*sync.Map).Swap":          store("(*sync.Map).Swap", 2, loaded),
+		"(*sync.Map).Load":          load("(*sync.Map).Load", withFound("(*sync.Map).Load")),
+		"(*sync.Map).LoadAndDelete": load("(*sync.Map).LoadAndDelete", withFound("(*sync.Map).LoadAndDelete")),
+		"(*sync.Map).Range": func(ctx context.Context, i *Interpreter, args []Object) Object {
+			if len(args) < 2 {
+				return &Error{Message: "too few arguments in call to (*sync.Map).Range"}
+			}
+			if fn, ok := unwrapVariable(args[1]).(*Function); ok && fn.Body != nil {
+				key := typedPlaceholder("key of (*sync.Map).Range", &scanner.FieldType{Name: "any", IsBuiltin: true})
+				value := i.loadedValue("(*sync.Map).Range", i.containers.get(containerKey(args[0])))
+				if _, err := i.Apply(ctx, fn, []Object{key, value}, fn.Package); err != nil {
+					i.logger.DebugContext(ctx, "failed to apply the callback", "function", "(*sync.Map).Range", "error", err)
+				}
+			}
+			return object.NIL
+		},
+
+		"context.WithValue": func(ctx context.Context, i *Interpreter, args []Object) Object {
+			if len(args) < 3 {
+				return &Error{Message: "too few arguments in call to context.WithValue"}
+			}
+			i.containers.add(contextKey(args[1]), args[2])
+			p := typedPlaceholder("result of context.WithValue", &scanner.FieldType{Name: "Context", PkgName: "context", FullImportPath: "context", TypeName: "Context"})
+			p.SetTypeInfo(contextType)
+			return p
+		},
+		"(context.Context).Value": func(ctx context.Context, i *Interpreter, args []Object) Object {
+			if len(args) < 2 {
+				return &Error{Message: "too few arguments in call to (context.Context).Value"}
+			}
+			return i.loadedValue("(context.Context).Value", i.containers.get(contextKey(args[1])))
+		},
+	}
+}
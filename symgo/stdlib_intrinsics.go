@@ -0,0 +1,96 @@
+package symgo
+
+import (
+	"context"
+
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// higherOrderFunc describes a higher-order function: which argument is the callback it calls,
+// and what it returns.
+type higherOrderFunc struct {
+	callback int                        // The index of the callback in the arguments, the receiver of a method being the first one.
+	result   func(args []Object) Object // The result of the call, or nil for a symbolic one.
+}
+
+// noResult is the result of the functions returning nothing.
+func noResult(args []Object) Object { return object.NIL }
+
+// symbolicIntResult is the result of the functions returning an index.
+func symbolicIntResult(args []Object) Object {
+	p := &SymbolicPlaceholder{Reason: "symbolic index"}
+	p.SetFieldType(&scanner.FieldType{Name: "int", IsBuiltin: true})
+	return p
+}
+
+// convertedFunc is the result of the conversion of a function to a function type.
+func convertedFunc(args []Object) Object { return args[0] }
+
+// stdlibHigherOrderFuncs are the higher-order functions of the standard library (and of
+// golang.org/x/sync) whose callbacks are applied by StdlibIntrinsics, keyed as the intrinsics.
+var stdlibHigherOrderFuncs = map[string]higherOrderFunc{
+	"sort.Slice":       {callback: 1, result: noResult},
+	"sort.SliceStable": {callback: 1, result: noResult},
+	"sort.Search":      {callback: 1, result: symbolicIntResult},
+
+	"(*sync.Once).Do": {callback: 1, result: noResult},
+
+	"(*golang.org/x/sync/errgroup.Group).Go":    {callback: 1, result: noResult},
+	"(*golang.org/x/sync/errgroup.Group).TryGo": {callback: 1},
+
+	"net/http.HandlerFunc":            {callback: 0, result: convertedFunc},
+	"net/http.HandleFunc":             {callback: 1, result: noResult},
+	"(*net/http.ServeMux).HandleFunc": {callback: 2, result: noResult},
+
+	"path/filepath.Walk":    {callback: 1},
+	"path/filepath.WalkDir": {callback: 1},
+	"io/fs.WalkDir":         {callback: 2},
+}
+
+// StdlibIntrinsics returns the intrinsics of the common higher-order functions of the standard
+// library, e.g. sort.Slice, (*sync.Once).Do, (*errgroup.Group).Go, http.HandlerFunc or
+// filepath.WalkDir. Each one applies its callback with symbolic arguments, as the function would
+// call it, so that the calls in the callback are traced and its effects are kept.
+//
+// They also model the value containers, atomic.Value, sync.Map and context.WithValue: the values
+// loaded from a container are the ones stored in it anywhere during the analysis, so that the
+// functions and the implementations of interfaces stored in them are called through them.
+// They are registered by WithStdlibIntrinsics, or can be pushed with PushIntrinsics.
+func StdlibIntrinsics() map[string]IntrinsicFunc {
+	intrinsics := containerIntrinsics()
+	for key, f := range stdlibHigherOrderFuncs {
+		intrinsics[key] = func(ctx context.Context, i *Interpreter, args []Object) Object {
+			return i.callHigherOrderFunc(ctx, key, f, args)
+		}
+	}
+	return intrinsics
+}
+
+// callHigherOrderFunc applies the callback of a call of a higher-order function, and returns the
+// result of the call.
+func (i *Interpreter) callHigherOrderFunc(ctx context.Context, key string, f higherOrderFunc, args []Object) Object {
+	if f.callback >= len(args) {
+		return &Error{Message: "too few arguments in call to " + key}
+	}
+	if fn, ok := unwrapVariable(args[f.callback]).(*Function); ok && fn.Body != nil {
+		if _, err := i.Apply(ctx, fn, nil, fn.Package); err != nil {
+			i.logger.DebugContext(ctx, "failed to apply the callback", "function", key, "error", err)
+		}
+	}
+	if f.result != nil {
+		return f.result(args)
+	}
+	return &SymbolicPlaceholder{Reason: "result of " + key}
+}
+
+// unwrapVariable returns the value held by a variable.
+func unwrapVariable(obj Object) Object {
+	for {
+		v, ok := obj.(*Variable)
+		if !ok || v.Value == nil {
+			return obj
+		}
+		obj = v.Value
+	}
+}
@@ -26,14 +26,29 @@ type Integer = object.Integer
 type Pointer = object.Pointer
 type Variable = object.Variable
 type SymbolicPlaceholder = object.SymbolicPlaceholder
+type StringTemplate = object.StringTemplate
+type StringTemplatePart = object.StringTemplatePart
 type Slice = object.Slice
 type MultiReturn = object.MultiReturn
 type Nil = object.Nil
+type UnresolvedFunction = object.UnresolvedFunction
 type BaseObject = object.BaseObject
 type Environment = object.Environment
 type Tracer = object.Tracer
 type TraceEvent = object.TraceEvent
 type TracerFunc = object.TracerFunc
+type TraceHooks = object.TraceHooks
+type TraceEventKind = object.TraceEventKind
+
+// The kinds of the trace events, see object.TraceEventKind.
+const (
+	TraceNode               = object.TraceNode
+	TraceEnterFunction      = object.TraceEnterFunction
+	TraceCallResolved       = object.TraceCallResolved
+	TraceBranchExplored     = object.TraceBranchExplored
+	TracePlaceholderCreated = object.TracePlaceholderCreated
+	TraceErrorRecovered     = object.TraceErrorRecovered
+)
 
 // NewEnclosedEnvironment creates a new environment that is enclosed by an outer one.
 var NewEnclosedEnvironment = object.NewEnclosedEnvironment
@@ -52,10 +67,18 @@ type Interpreter struct {
 	logger                     *slog.Logger
 	tracer                     object.Tracer
 	scanPolicy                 object.ScanPolicyFunc // This will be built from primary scope
+	policy                     *ScanPolicy
+	modulePolicies             map[string]object.ScanPolicyFunc // keyed by module path
 	primaryAnalysisPatterns    []string
 	symbolicDependencyPatterns []string
 	maxSteps                   int
-	memoize                    bool // Flag to enable/disable memoization
+	memoize                    bool            // Flag to enable/disable memoization
+	memoryBudget               uint64          // Soft memory budget in bytes, 0 means unlimited
+	recursionWidening          bool            // Flag to enable/disable the widening of recursive calls
+	reflectAllMethods          bool            // Flag to consider all the methods called by a non-constant reflected name
+	stdlibIntrinsics           bool            // Flag to register the intrinsics of StdlibIntrinsics
+	coverage                   bool            // Flag to record the statements and branches explored by function
+	containers                 containerValues // The values stored in the containers, by the intrinsics of StdlibIntrinsics
 }
 
 // Option is a functional option for configuring the Interpreter.
@@ -69,6 +92,9 @@ func WithLogger(logger *slog.Logger) Option {
 }
 
 // WithTracer sets the tracer for the interpreter.
+// The tracer receives an event for each evaluated node, entered function, resolved call,
+// explored branch, symbolic call result, and recovered error (see TraceEventKind).
+// Use a TraceHooks to handle only some kinds of events.
 func WithTracer(tracer object.Tracer) Option {
 	return func(i *Interpreter) {
 		i.tracer = tracer
@@ -77,6 +103,8 @@ func WithTracer(tracer object.Tracer) Option {
 
 // WithPrimaryAnalysisScope sets the package patterns for deep, symbolic execution.
 // Patterns can include wildcards (e.g., "example.com/mymodule/...").
+// These packages are loaded with goscan.LoadFull, overriding the scanner's load mode for them
+// (but not WithSymbolicDependencyScope).
 func WithPrimaryAnalysisScope(patterns ...string) Option {
 	return func(i *Interpreter) {
 		i.primaryAnalysisPatterns = append(i.primaryAnalysisPatterns, patterns...)
@@ -84,7 +112,8 @@ func WithPrimaryAnalysisScope(patterns ...string) Option {
 }
 
 // WithSymbolicDependencyScope sets the package patterns for declarations-only parsing.
-// These packages are needed for type resolution but their function bodies will not be executed.
+// These packages are needed for type resolution but their function bodies will not be executed,
+// so they are loaded with goscan.LoadDecls.
 // Patterns can include wildcards (e.g., "net/http", "github.com/some/lib/...").
 func WithSymbolicDependencyScope(patterns ...string) Option {
 	return func(i *Interpreter) {
@@ -94,13 +123,35 @@ func WithSymbolicDependencyScope(patterns ...string) Option {
 
 // WithScanPolicy sets a custom policy function to determine which packages to scan from source.
 //
-// DEPRECATED: Use WithPrimaryAnalysisScope instead. This option may be removed in the future.
+// DEPRECATED: Use WithPrimaryAnalysisScope or WithPolicy instead. This option may be removed in the future.
 func WithScanPolicy(policy object.ScanPolicyFunc) Option {
 	return func(i *Interpreter) {
 		i.scanPolicy = policy
 	}
 }
 
+// WithPolicy sets the policy deciding which packages are evaluated, scanned for declarations
+// only, or skipped (see ScanPolicy). It takes precedence over WithPrimaryAnalysisScope and
+// WithScanPolicy for these decisions, and sets the load modes of the scanner for its rules.
+func WithPolicy(policy *ScanPolicy) Option {
+	return func(i *Interpreter) {
+		i.policy = policy
+	}
+}
+
+// WithModuleScanPolicy sets the scan policy for the packages of a module of the workspace,
+// overriding the interpreter's policy (WithPolicy, WithPrimaryAnalysisScope or the default) for
+// them. The module of a package is the workspace module with the longest module path matching
+// its import path (see goscan.Scanner.ModuleOf).
+func WithModuleScanPolicy(modulePath string, policy object.ScanPolicyFunc) Option {
+	return func(i *Interpreter) {
+		if i.modulePolicies == nil {
+			i.modulePolicies = make(map[string]object.ScanPolicyFunc)
+		}
+		i.modulePolicies[modulePath] = policy
+	}
+}
+
 // WithMaxSteps sets the maximum number of evaluation steps for the underlying evaluator.
 func WithMaxSteps(n int) Option {
 	return func(i *Interpreter) {
@@ -119,6 +170,58 @@ func WithMemoization(enabled bool) Option {
 	}
 }
 
+// WithMemoryBudget sets a soft memory budget in bytes for the analysis.
+// When the heap grows beyond it, the interpreter periodically prunes its caches of the
+// entries that retain environments unreachable from the live call frames, so that they
+// can be garbage collected. Use Stats to see what is retained when tuning the budget.
+func WithMemoryBudget(bytes uint64) Option {
+	return func(i *Interpreter) {
+		i.memoryBudget = bytes
+	}
+}
+
+// WithRecursionWidening enables or disables the widening of recursive calls (enabled by default).
+// When enabled, a function called again while it is already on the call stack (with a receiver
+// of the same type) is evaluated once more with its arguments widened to symbolic values, and the
+// result is reused for the rest of the cycle, so that mutually recursive code yields a complete
+// call graph. When disabled, such a call is not evaluated and returns a placeholder.
+func WithRecursionWidening(enabled bool) Option {
+	return func(i *Interpreter) {
+		i.recursionWidening = enabled
+	}
+}
+
+// WithReflectAllMethods sets how the methods called through reflection are handled when their
+// names are not constant (disabled by default). The method looked up with a constant name,
+// e.g. `reflect.ValueOf(x).MethodByName("Run")`, is always considered called, and its body is
+// evaluated. With a name that is not constant, all the exported methods of the type of x are
+// considered called when enabled (or those matching the constant parts of the name, e.g.
+// `"Handle" + name`), and none when disabled.
+func WithReflectAllMethods(enabled bool) Option {
+	return func(i *Interpreter) {
+		i.reflectAllMethods = enabled
+	}
+}
+
+// WithStdlibIntrinsics enables or disables the intrinsics of the common higher-order functions
+// of the standard library (disabled by default), see StdlibIntrinsics. When enabled, the
+// callbacks passed to them, e.g. `sort.Slice(xs, less)` or `once.Do(f)`, are applied where the
+// functions are called, instead of being only scanned for the calls in their bodies.
+// The intrinsics registered with RegisterIntrinsic take precedence over them.
+func WithStdlibIntrinsics(enabled bool) Option {
+	return func(i *Interpreter) {
+		i.stdlibIntrinsics = enabled
+	}
+}
+
+// WithCoverage enables or disables the recording of the statements and branches explored in the
+// bodies of the evaluated functions (disabled by default), see Interpreter.Coverage.
+func WithCoverage(enabled bool) Option {
+	return func(i *Interpreter) {
+		i.coverage = enabled
+	}
+}
+
 // Scanner returns the underlying go-scan Scanner instance.
 func (i *Interpreter) Scanner() *goscan.Scanner {
 	return i.scanner
@@ -132,8 +235,9 @@ func NewInterpreter(scanner *goscan.Scanner, options ...Option) (*Interpreter, e
 	}
 
 	i := &Interpreter{
-		scanner:   scanner,
-		globalEnv: object.NewEnvironment(),
+		scanner:           scanner,
+		globalEnv:         object.NewEnvironment(),
+		recursionWidening: true,
 	}
 
 	for _, opt := range options {
@@ -145,13 +249,23 @@ func NewInterpreter(scanner *goscan.Scanner, options ...Option) (*Interpreter, e
 		i.logger = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError}))
 	}
 
-	// Configure the underlying scanner with the symbolic dependency scope.
-	if len(i.symbolicDependencyPatterns) > 0 {
-		i.scanner.AddDeclarationsOnlyPackages(i.symbolicDependencyPatterns)
+	// Align the scanner's load modes with the analysis scopes: the primary scope needs
+	// function bodies, while symbolic dependencies only need declarations.
+	// The symbolic dependency scope is set last so that it wins when patterns overlap.
+	for _, pattern := range i.primaryAnalysisPatterns {
+		i.scanner.SetPackageLoadMode(pattern, goscan.LoadFull)
+	}
+	for _, pattern := range i.symbolicDependencyPatterns {
+		i.scanner.SetPackageLoadMode(pattern, goscan.LoadDecls)
+	}
+	if i.policy != nil {
+		i.policy.applyLoadModes(i.scanner)
 	}
 
-	// Configure the scan policy based on the primary analysis scope.
-	if len(i.primaryAnalysisPatterns) > 0 {
+	// Configure the scan policy based on the policy, or on the primary analysis scope.
+	if i.policy != nil {
+		i.scanPolicy = i.policy.ShouldScan
+	} else if len(i.primaryAnalysisPatterns) > 0 {
 		i.scanPolicy = func(importPath string) bool {
 			for _, pattern := range i.primaryAnalysisPatterns {
 				if matches(pattern, importPath) {
@@ -171,7 +285,7 @@ func NewInterpreter(scanner *goscan.Scanner, options ...Option) (*Interpreter, e
 			}
 			i.scanPolicy = func(importPath string) bool {
 				for _, modulePath := range modulePaths {
-					if strings.HasPrefix(importPath, modulePath) {
+					if importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/") {
 						return true
 					}
 				}
@@ -185,6 +299,25 @@ func NewInterpreter(scanner *goscan.Scanner, options ...Option) (*Interpreter, e
 		}
 	}
 
+	if len(i.modulePolicies) > 0 {
+		base := i.scanPolicy
+		i.scanPolicy = func(importPath string) bool {
+			if mod := i.scanner.ModuleOf(importPath); mod != nil {
+				if policy, ok := i.modulePolicies[mod.Path]; ok {
+					return policy(importPath)
+				}
+			}
+			return base(importPath)
+		}
+	}
+
+	// Packages loaded with goscan.LoadImports have no declarations to analyze,
+	// so they are always treated as being outside of the scan policy.
+	policy := i.scanPolicy
+	i.scanPolicy = func(importPath string) bool {
+		return policy(importPath) && i.scanner.LoadModeFor(importPath) != goscan.LoadImports
+	}
+
 	evalOpts := []evaluator.Option{}
 	if i.maxSteps > 0 {
 		evalOpts = append(evalOpts, evaluator.WithMaxSteps(i.maxSteps))
@@ -192,12 +325,26 @@ func NewInterpreter(scanner *goscan.Scanner, options ...Option) (*Interpreter, e
 	if i.memoize {
 		evalOpts = append(evalOpts, evaluator.WithMemoization())
 	}
+	if i.memoryBudget > 0 {
+		evalOpts = append(evalOpts, evaluator.WithMemoryBudget(i.memoryBudget))
+	}
+	if i.coverage {
+		evalOpts = append(evalOpts, evaluator.WithCoverage())
+	}
+	evalOpts = append(evalOpts, evaluator.WithRecursionWidening(i.recursionWidening))
+	evalOpts = append(evalOpts, evaluator.WithReflectAllMethods(i.reflectAllMethods))
+	evalOpts = append(evalOpts, evaluator.WithRootEnvironment(i.globalEnv))
 	i.eval = evaluator.New(scanner, i.logger, i.tracer, i.scanPolicy, evalOpts...)
 
 	// Register default intrinsics
 	i.RegisterIntrinsic("fmt.Sprintf", func(ctx context.Context, eval *Interpreter, args []Object) Object {
 		return i.intrinsicSprintf(ctx, args)
 	})
+	if i.stdlibIntrinsics {
+		for key, handler := range StdlibIntrinsics() {
+			i.RegisterIntrinsic(key, handler)
+		}
+	}
 
 	return i, nil
 }
@@ -287,6 +434,8 @@ func (i *Interpreter) intrinsicSprintf(ctx context.Context, args []Object) Objec
 	result := format.Value
 	argIndex := 1
 
+	// The formatted string is a template if some arguments are not constant.
+	var parts []StringTemplatePart
 	var newStr strings.Builder
 	for i := 0; i < len(result); i++ {
 		if result[i] == '%' && i+1 < len(result) {
@@ -312,14 +461,12 @@ func (i *Interpreter) intrinsicSprintf(ctx context.Context, args []Object) Objec
 					replacement = v.Value
 				case *Integer:
 					replacement = fmt.Sprintf("%d", v.Value)
-				case *SymbolicPlaceholder:
-					// When a symbolic placeholder for a zero-value variable is formatted,
-					// provide a sensible zero value representation based on the verb.
-					if verb == 'd' {
-						replacement = "0"
-					} else {
-						replacement = "" // for %s, %v
-					}
+				case *SymbolicPlaceholder, *StringTemplate:
+					parts = append(parts, StringTemplatePart{Const: true, Value: newStr.String()}, StringTemplatePart{Object: arg})
+					newStr.Reset()
+					argIndex++
+					i++ // skip the verb
+					continue
 				default:
 					replacement = arg.Inspect()
 				}
@@ -335,7 +482,11 @@ func (i *Interpreter) intrinsicSprintf(ctx context.Context, args []Object) Objec
 		}
 	}
 
-	return &String{Value: newStr.String()}
+	if parts == nil {
+		return &String{Value: newStr.String()}
+	}
+	parts = append(parts, StringTemplatePart{Const: true, Value: newStr.String()})
+	return object.NewStringTemplate(parts...)
 }
 
 // Eval evaluates a given AST node in the interpreter's persistent environment.
@@ -345,7 +496,7 @@ func (i *Interpreter) Eval(ctx context.Context, node ast.Node, pkg *scanner.Pack
 	// We no longer need to pre-populate the environment here.
 	result := i.eval.Eval(ctx, node, i.globalEnv, pkg)
 	if err, ok := result.(*Error); ok {
-		return nil, errors.New(err.Inspect())
+		return nil, err
 	}
 	return result, nil
 }
@@ -379,7 +530,7 @@ func (i *Interpreter) EvaluatorForTest() *evaluator.Evaluator {
 func (i *Interpreter) EvalWithEnv(ctx context.Context, node ast.Node, env *Environment, pkg *scanner.PackageInfo) (Object, error) {
 	result := i.eval.Eval(ctx, node, env, pkg)
 	if err, ok := result.(*Error); ok {
-		return nil, errors.New(err.Inspect())
+		return nil, err
 	}
 	return result, nil
 }
@@ -422,17 +573,46 @@ func (i *Interpreter) FindObject(name string) (Object, bool) {
 	return i.globalEnv.Get(name)
 }
 
+// LoadPackage registers the objects of a scanned package in one call: its constants, types,
+// variables, functions and methods, so that FindObjectInPackage finds them without
+// evaluating the files of the package.
+func (i *Interpreter) LoadPackage(ctx context.Context, pkg *scanner.PackageInfo) error {
+	if pkg == nil || pkg.ImportPath == "" {
+		return errors.New("symgo: LoadPackage requires a scanned package with an import path")
+	}
+	i.eval.LoadPackage(ctx, pkg)
+	return nil
+}
+
 // FindObjectInPackage looks up an object in a specific package's environment.
-// This is primarily a test helper to bypass the global environment and check
-// the state of a single package.
+// A method is looked up by its canonical receiver name, e.g. "(*Server).Start",
+// "Server.Start", or "(*example.com/app.Server).Start", as methods are not bound
+// in the package's environment.
 func (i *Interpreter) FindObjectInPackage(ctx context.Context, pkgPath string, name string) (Object, bool) {
 	pkgObj, err := i.eval.GetOrLoadPackageForTest(ctx, pkgPath)
 	if err != nil {
 		return nil, false
 	}
+	if strings.Contains(name, ".") {
+		return i.eval.FindMethod(ctx, pkgObj, name)
+	}
 	return pkgObj.Env.Get(name)
 }
 
+// FindFunction returns the function object for a function or method declared in the package.
+// Unlike FindObjectInPackage, methods can be looked up, as they are not bound in the package's environment.
+func (i *Interpreter) FindFunction(ctx context.Context, pkgPath string, fn *scanner.FunctionInfo) (Object, bool) {
+	pkgObj, err := i.eval.GetOrLoadPackageForTest(ctx, pkgPath)
+	if err != nil {
+		return nil, false
+	}
+	obj := i.eval.GetOrResolveFunctionForTest(ctx, pkgObj, fn)
+	if obj == nil {
+		return nil, false
+	}
+	return obj, true
+}
+
 // Apply is a wrapper around the internal evaluator's applyFunction.
 // It is intended for advanced use cases like docgen where direct function invocation is needed.
 func (i *Interpreter) Apply(ctx context.Context, fn Object, args []Object, pkg *scanner.PackageInfo) (Object, error) {
@@ -444,11 +624,87 @@ func (i *Interpreter) Apply(ctx context.Context, fn Object, args []Object, pkg *
 	return result, nil
 }
 
-// Finalize performs the final analysis step after evaluation, resolving interface method calls.
+// Finalize performs the final analysis step after evaluation, resolving interface method calls
+// and computing the report of UnresolvedCalls.
 func (i *Interpreter) Finalize(ctx context.Context) {
 	i.eval.Finalize(ctx)
 }
 
+// UnresolvedCall is a call site whose result degraded to an untyped placeholder.
+type UnresolvedCall = evaluator.UnresolvedCall
+
+// UnresolvedReason tells why the result of a call degraded to an untyped placeholder.
+type UnresolvedReason = evaluator.UnresolvedReason
+
+const (
+	UnresolvedOutOfPolicy      = evaluator.UnresolvedOutOfPolicy
+	UnresolvedUnsupported      = evaluator.UnresolvedUnsupported
+	UnresolvedInferenceFailure = evaluator.UnresolvedInferenceFailure
+)
+
+// UnresolvedCalls returns the call sites whose results degraded to untyped placeholders, with
+// their positions and reasons, as computed by Finalize. The calls made on these results are not
+// traced, so the report tells how complete the analysis is.
+func (i *Interpreter) UnresolvedCalls() []UnresolvedCall {
+	return i.eval.UnresolvedCalls()
+}
+
+// CalledInterfaceMethods returns the interface methods called during the analysis,
+// as sorted "<pkg>.<Interface>.<Method>" keys.
+func (i *Interpreter) CalledInterfaceMethods() []string {
+	return i.eval.CalledInterfaceMethods()
+}
+
+// ImplementersOf returns the struct types of the packages seen by the scanner which implement the
+// interface, sorted by import path and name. The implementers are looked up in an index of the
+// struct types by method name, built on demand and shared with Finalize, instead of checking
+// every struct type of every package, see goscan.Scanner.ImplementersOf.
+func (i *Interpreter) ImplementersOf(ctx context.Context, iface *scanner.TypeInfo) []*scanner.TypeInfo {
+	return i.scanner.ImplementersOf(ctx, iface)
+}
+
+// FunctionCoverage tells which statements and branches of a function were explored.
+type FunctionCoverage = evaluator.FunctionCoverage
+
+// Coverage returns, for each function whose body was evaluated, the numbers and the positions of
+// the statements and branches explored, and the error which aborted its evaluation, if any, e.g.
+// when the maximum number of steps is exceeded (see WithMaxSteps). The calls found in a function
+// whose coverage is Partial may be incomplete. It is empty unless enabled by WithCoverage.
+func (i *Interpreter) Coverage() []FunctionCoverage {
+	return i.eval.Coverage()
+}
+
+// Stats is a snapshot of the interpreter's memory-related telemetry.
+type Stats = evaluator.Stats
+
+// Stats returns the counts of the objects, environments and packages retained by the
+// interpreter, along with the cache sizes and pruning activity, for tuning WithMemoryBudget.
+func (i *Interpreter) Stats() Stats {
+	return i.eval.Stats()
+}
+
+// Snapshot is a saved state of the interpreter, see Interpreter.Snapshot.
+type Snapshot = evaluator.Snapshot
+
+// Snapshot captures the current state of the environments and variables, for building
+// "what-if" tools: evaluate one path, Restore the snapshot, then evaluate another path
+// from the same state. Nothing is copied when the snapshot is taken; the state is saved
+// copy-on-write as it is modified, so Release the snapshot when it is no longer needed.
+func (i *Interpreter) Snapshot() *Snapshot {
+	return i.eval.Snapshot()
+}
+
+// Restore rolls the interpreter back to the state captured by s. The snapshot can be
+// restored several times, but the snapshots taken after it are released.
+func (i *Interpreter) Restore(s *Snapshot) {
+	i.eval.Restore(s)
+}
+
+// ReleaseSnapshot stops tracking the modifications for s. It can no longer be restored.
+func (i *Interpreter) ReleaseSnapshot(s *Snapshot) {
+	i.eval.Release(s)
+}
+
 // CalledInterfaceMethodsForTest returns the map of called interface methods for testing.
 func (i *Interpreter) CalledInterfaceMethodsForTest() map[string][]object.Object {
 	return i.eval.CalledInterfaceMethodsForTest()
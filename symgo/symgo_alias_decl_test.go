@@ -0,0 +1,99 @@
+package symgo_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestAliasDeclarationIsTransparent(t *testing.T) {
+	source := `
+package main
+
+import "example.com/me/models"
+
+type Impl struct{}
+
+func (i Impl) Value() {}
+func (i *Impl) Pointer() {}
+
+type ImplAlias = Impl
+
+type ChainedAlias = ImplAlias
+
+type UserAlias = models.User
+
+func fromParam(a ImplAlias, c *ChainedAlias) {
+	a.Value()
+	c.Pointer()
+}
+
+func main() {
+	a := ImplAlias{}
+	a.Value()
+
+	p := &ChainedAlias{}
+	p.Pointer()
+
+	u := UserAlias{}
+	u.Greet()
+
+	fromParam(a, p)
+}
+`
+	models := `
+package models
+
+type User struct{}
+
+func (u User) Greet() {}
+`
+	var called []string
+	tc := symgotest.TestCase{
+		Source: map[string]string{
+			"go.mod":           "module example.com/me\n\ngo 1.21\n",
+			"main.go":          source,
+			"models/models.go": models,
+		},
+		EntryPoint: "example.com/me.main",
+		Options: []symgotest.Option{
+			symgotest.WithDefaultIntrinsic(func(ctx context.Context, i *symgo.Interpreter, args []symgo.Object) symgo.Object {
+				if len(args) == 0 {
+					return object.NIL
+				}
+				if fn, ok := args[0].(*symgo.Function); ok && fn.Def != nil && fn.Def.Receiver != nil {
+					recv := fn.Def.Receiver.Type
+					name := recv.TypeName
+					if name == "" {
+						name = recv.Name
+					}
+					called = append(called, fmt.Sprintf("%s.%s", name, fn.Def.Name))
+				}
+				return object.NIL
+			}),
+		},
+	}
+
+	symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+		if r.Error != nil {
+			t.Fatalf("Execution failed unexpectedly: %v", r.Error)
+		}
+		sort.Strings(called)
+		want := []string{
+			"Impl.Pointer", // via &ChainedAlias{}
+			"Impl.Pointer", // via *ChainedAlias parameter
+			"Impl.Value",   // via ImplAlias{}
+			"Impl.Value",   // via ImplAlias parameter
+			"User.Greet",   // via alias of a type in another package
+		}
+		if diff := cmp.Diff(want, called); diff != "" {
+			t.Errorf("called methods mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
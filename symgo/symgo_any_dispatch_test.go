@@ -0,0 +1,152 @@
+package symgo_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestAnyValue_DispatchOverAssignedTypes(t *testing.T) {
+	const decls = `
+type Helloer interface{ Hello() int }
+
+type Greeter struct{}
+
+func (g *Greeter) Hello() int { return 1 }
+
+type Other struct{}
+
+func (o Other) Hello() int { return 2 }
+`
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name: "assertion to a named interface",
+			source: `
+func run() int {
+	var x any
+	x = &Greeter{}
+	return x.(Helloer).Hello()
+}`,
+			want: []string{"Greeter.Hello"},
+		},
+		{
+			name: "assertion to an anonymous interface",
+			source: `
+func run() int {
+	var x any = Other{}
+	return x.(interface{ Hello() int }).Hello()
+}`,
+			want: []string{"Other.Hello"},
+		},
+		{
+			name: "assigned on several paths",
+			source: `
+func run(flag bool) int {
+	var x any
+	if flag {
+		x = &Greeter{}
+	} else {
+		x = Other{}
+	}
+	return x.(Helloer).Hello()
+}`,
+			want: []string{"Greeter.Hello", "Other.Hello"},
+		},
+		{
+			name: "comma-ok assertion",
+			source: `
+func run(flag bool) int {
+	var x any = Other{}
+	if flag {
+		x = &Greeter{}
+	}
+	if h, ok := x.(Helloer); ok {
+		return h.Hello()
+	}
+	return 0
+}`,
+			want: []string{"Greeter.Hello", "Other.Hello"},
+		},
+		{
+			name: "type switch case of an interface",
+			source: `
+func run(flag bool) int {
+	var x any = Other{}
+	if flag {
+		x = &Greeter{}
+	}
+	switch v := x.(type) {
+	case Helloer:
+		return v.Hello()
+	}
+	return 0
+}`,
+			want: []string{"Greeter.Hello", "Other.Hello"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called []string
+			tc := symgotest.TestCase{
+				Source: map[string]string{
+					"go.mod":  "module example.com/me\ngo 1.22",
+					"main.go": "package main\n" + decls + tt.source,
+				},
+				EntryPoint: "example.com/me.run",
+				Options: []symgotest.Option{
+					symgotest.WithDefaultIntrinsic(func(ctx context.Context, i *symgo.Interpreter, args []symgo.Object) symgo.Object {
+						if fn, ok := args[0].(*symgo.Function); ok && fn.Def != nil && fn.Def.Receiver != nil {
+							recv := fn.Def.Receiver.Type
+							name := recv.TypeName
+							if name == "" {
+								name = recv.Name
+							}
+							called = append(called, fmt.Sprintf("%s.%s", name, fn.Def.Name))
+						}
+						return nil
+					}),
+				},
+			}
+			symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+				if r.Error != nil {
+					t.Fatalf("Execution failed unexpectedly: %v", r.Error)
+				}
+				sort.Strings(called)
+				if diff := cmp.Diff(tt.want, called); diff != "" {
+					t.Errorf("called methods mismatch (-want +got):\n%s", diff)
+				}
+			})
+		})
+	}
+
+	t.Run("assertion to the concrete type", func(t *testing.T) {
+		tc := symgotest.TestCase{
+			Source: map[string]string{
+				"go.mod": "module example.com/me\ngo 1.22",
+				"main.go": "package main\n" + decls + `
+func run() int {
+	var x any
+	x = &Greeter{}
+	return x.(*Greeter).Hello()
+}`,
+			},
+			EntryPoint: "example.com/me.run",
+		}
+		symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+			got := symgotest.AssertAs[*object.Integer](r, t, 0)
+			if got.Value != 1 {
+				t.Errorf("want 1, got %d", got.Value)
+			}
+		})
+	})
+}
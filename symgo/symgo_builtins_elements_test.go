@@ -0,0 +1,116 @@
+package symgo_test
+
+import (
+	"testing"
+
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestBuiltins_SliceElements(t *testing.T) {
+	const funcs = `
+func one() int { return 1 }
+func two() int { return 2 }
+`
+	tests := []struct {
+		name   string
+		source string
+		want   int64
+	}{
+		{
+			name: "append to a nil slice",
+			source: `
+func run() int {
+	var fs []func() int
+	fs = append(fs, one)
+	return fs[0]()
+}`,
+			want: 1,
+		},
+		{
+			name: "append several times",
+			source: `
+func run() int {
+	fs := []func() int{}
+	fs = append(fs, one)
+	fs = append(fs, two)
+	return fs[1]()
+}`,
+			want: 2,
+		},
+		{
+			name: "append a spread slice",
+			source: `
+func run() int {
+	more := []func() int{one, two}
+	var fs []func() int
+	fs = append(fs, more...)
+	return fs[1]()
+}`,
+			want: 2,
+		},
+		{
+			name: "copy",
+			source: `
+func run() int {
+	src := []func() int{two}
+	dst := make([]func() int, 1)
+	copy(dst, src)
+	return dst[0]()
+}`,
+			want: 2,
+		},
+		{
+			name: "index assignment",
+			source: `
+func run() int {
+	fs := make([]func() int, 2)
+	fs[1] = two
+	return fs[1]()
+}`,
+			want: 2,
+		},
+		{
+			name: "len of a known slice",
+			source: `
+func run() int {
+	var fs []func() int
+	fs = append(fs, one, two)
+	return len(fs)
+}`,
+			want: 2,
+		},
+		{
+			name: "range over appended functions",
+			source: `
+func run() int {
+	var fs []func() int
+	fs = append(fs, two)
+	result := 0
+	for _, f := range fs {
+		result = f()
+	}
+	return result
+}`,
+			want: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := symgotest.TestCase{
+				Source: map[string]string{
+					"go.mod":  "module example.com/me\ngo 1.22",
+					"main.go": "package main\n" + funcs + tt.source,
+				},
+				EntryPoint: "example.com/me.run",
+			}
+			symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+				got := symgotest.AssertAs[*object.Integer](r, t, 0)
+				if got.Value != tt.want {
+					t.Errorf("want %d, got %d", tt.want, got.Value)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package symgo_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestChainedCallsKeepDeclaredResultTypes(t *testing.T) {
+	source := `
+package main
+
+import "example.com/me/client"
+
+type Getter interface {
+	Get(id string) *client.User
+}
+
+type UserService interface {
+	Getter
+	Find(id string) (*client.User, error)
+}
+
+type Client interface {
+	Users() UserService
+}
+
+func run(c Client) {
+	c.Users().Get("1").Name()     // interface -> interface -> concrete
+	u, _ := c.Users().Find("2")   // multiple results
+	u.Name()
+	client.New().Users().Get("3").Name() // concrete chain
+}
+`
+	clientSrc := `
+package client
+
+type Client struct{}
+
+func New() *Client { return &Client{} }
+
+func (c *Client) Users() *UserService { return &UserService{} }
+
+type UserService struct{}
+
+func (s *UserService) Get(id string) *User { return &User{} }
+
+type User struct{}
+
+func (u *User) Name() string { return "" }
+`
+	var called []string
+	tc := symgotest.TestCase{
+		Source: map[string]string{
+			"go.mod":           "module example.com/me\n\ngo 1.21\n",
+			"main.go":          source,
+			"client/client.go": clientSrc,
+		},
+		EntryPoint: "example.com/me.run",
+		Options: []symgotest.Option{
+			symgotest.WithDefaultIntrinsic(func(ctx context.Context, i *symgo.Interpreter, args []symgo.Object) symgo.Object {
+				if len(args) == 0 {
+					return object.NIL
+				}
+				if fn, ok := args[0].(*symgo.Function); ok && fn.Def != nil && fn.Def.Receiver != nil {
+					recv := fn.Def.Receiver.Type
+					name := recv.TypeName
+					if name == "" {
+						name = recv.Name
+					}
+					called = append(called, fmt.Sprintf("%s.%s", name, fn.Def.Name))
+				}
+				return object.NIL
+			}),
+		},
+	}
+
+	symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+		if r.Error != nil {
+			t.Fatalf("Execution failed unexpectedly: %v", r.Error)
+		}
+		sort.Strings(called)
+		want := []string{
+			"Client.Users",
+			"User.Name", // via c.Users().Get("1")
+			"User.Name", // via c.Users().Find("2")
+			"User.Name", // via client.New().Users().Get("3")
+			"UserService.Get",
+		}
+		if diff := cmp.Diff(want, called); diff != "" {
+			t.Errorf("called methods mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package symgo_test
+
+import (
+	"testing"
+
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestClosure_CapturedVariables(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   int64
+	}{
+		{
+			name: "increment",
+			source: `
+func run() int {
+	counter := 0
+	inc := func() { counter++ }
+	inc()
+	inc()
+	return counter
+}`,
+			want: 2,
+		},
+		{
+			name: "assignment",
+			source: `
+func run() int {
+	x := 1
+	set := func(v int) { x = v }
+	set(5)
+	return x
+}`,
+			want: 5,
+		},
+		{
+			name: "closures sharing a variable after the function returned",
+			source: `
+func makeCounter() (func(), func() int) {
+	n := 0
+	return func() { n++ }, func() int { return n }
+}
+
+func run() int {
+	inc, get := makeCounter()
+	inc()
+	inc()
+	inc()
+	return get()
+}`,
+			want: 3,
+		},
+		{
+			name: "function value assigned after the closure is created",
+			source: `
+func answer() int { return 42 }
+
+func run() int {
+	var f func() int
+	call := func() int { return f() }
+	f = answer
+	return call()
+}`,
+			want: 42,
+		},
+		{
+			name: "closure passed to a function calling it",
+			source: `
+func apply(f func()) { f() }
+
+func run() int {
+	n := 0
+	apply(func() { n += 2 })
+	return n
+}`,
+			want: 2,
+		},
+		{
+			name: "closure passed to a function not calling it",
+			source: `
+func register(f func()) {}
+
+func run() int {
+	n := 0
+	register(func() { n += 2 })
+	return n
+}`,
+			want: 0,
+		},
+		{
+			name: "closure started as a goroutine",
+			source: `
+func run() int {
+	n := 0
+	go func() { n++ }()
+	return n + 10
+}`,
+			want: 11,
+		},
+		{
+			name: "statements after a defer",
+			source: `
+func cleanup() int { return 1 }
+
+func run() int {
+	n := 0
+	defer cleanup()
+	n = 3
+	return n
+}`,
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := symgotest.TestCase{
+				Source: map[string]string{
+					"go.mod":  "module example.com/me\ngo 1.22",
+					"main.go": "package main\n" + tt.source,
+				},
+				EntryPoint: "example.com/me.run",
+			}
+			symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+				if r.Error != nil {
+					t.Fatalf("execution failed: %+v", r.Error)
+				}
+				got := symgotest.AssertAs[*object.Integer](r, t, 0)
+				if got.Value != tt.want {
+					t.Errorf("want %d, got %d", tt.want, got.Value)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package symgo_test
+
+import (
+	"testing"
+
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestConversion(t *testing.T) {
+	const types = `
+type User struct{ Name string }
+
+type Admin User
+
+func (a Admin) Greet() string { return "hello " + a.Name }
+
+type ID int
+
+func (i ID) Valid() bool { return i > 0 }
+
+type Celsius float64
+
+func (c Celsius) Add(d Celsius) Celsius { return c + d }
+`
+	tests := []struct {
+		name   string
+		source string
+		check  func(t *testing.T, r *symgotest.Result)
+	}{
+		{
+			name: "named struct type",
+			source: `
+func run() string {
+	u := User{Name: "alice"}
+	return Admin(u).Greet()
+}`,
+			check: func(t *testing.T, r *symgotest.Result) {
+				if got := symgotest.AssertAs[*object.String](r, t, 0); got.Value != "hello alice" {
+					t.Errorf("want %q, got %q", "hello alice", got.Value)
+				}
+			},
+		},
+		{
+			name: "pointer to named struct type",
+			source: `
+func run() string {
+	u := &User{Name: "bob"}
+	return (*Admin)(u).Greet()
+}`,
+			check: func(t *testing.T, r *symgotest.Result) {
+				if got := symgotest.AssertAs[*object.String](r, t, 0); got.Value != "hello bob" {
+					t.Errorf("want %q, got %q", "hello bob", got.Value)
+				}
+			},
+		},
+		{
+			name: "named integer type",
+			source: `
+func run() bool {
+	return ID(5).Valid()
+}`,
+			check: func(t *testing.T, r *symgotest.Result) {
+				if got := symgotest.AssertAs[*object.Boolean](r, t, 0); !got.Value {
+					t.Errorf("want true, got false")
+				}
+			},
+		},
+		{
+			name: "named float type",
+			source: `
+func run() Celsius {
+	return Celsius(36.5).Add(1)
+}`,
+			check: func(t *testing.T, r *symgotest.Result) {
+				got := symgotest.AssertAs[*object.Float](r, t, 0)
+				if got.Value != 37.5 {
+					t.Errorf("want 37.5, got %v", got.Value)
+				}
+			},
+		},
+		{
+			name: "numeric widening",
+			source: `
+func run() int64 {
+	var x int32 = 3
+	return int64(x)
+}`,
+			check: func(t *testing.T, r *symgotest.Result) {
+				if got := symgotest.AssertAs[*object.Integer](r, t, 0); got.Value != 3 {
+					t.Errorf("want 3, got %d", got.Value)
+				}
+			},
+		},
+		{
+			name: "integer to float",
+			source: `
+func run() float64 {
+	return float64(2)
+}`,
+			check: func(t *testing.T, r *symgotest.Result) {
+				if got := symgotest.AssertAs[*object.Float](r, t, 0); got.Value != 2 {
+					t.Errorf("want 2, got %v", got.Value)
+				}
+			},
+		},
+		{
+			name: "integer narrowing wraps around",
+			source: `
+func run() int8 {
+	x := 200
+	return int8(x)
+}`,
+			check: func(t *testing.T, r *symgotest.Result) {
+				if got := symgotest.AssertAs[*object.Integer](r, t, 0); got.Value != -56 {
+					t.Errorf("want -56, got %d", got.Value)
+				}
+			},
+		},
+		{
+			name: "string to bytes",
+			source: `
+func run() []byte {
+	return []byte("hi")
+}`,
+			check: func(t *testing.T, r *symgotest.Result) {
+				got := symgotest.AssertAs[*object.Slice](r, t, 0)
+				if len(got.Elements) != 2 || got.Len != 2 {
+					t.Errorf("want 2 elements, got %s", got.Inspect())
+				}
+			},
+		},
+		{
+			name: "bytes to string",
+			source: `
+func run() string {
+	b := []byte("hi")
+	return string(b)
+}`,
+			check: func(t *testing.T, r *symgotest.Result) {
+				if got := symgotest.AssertAs[*object.String](r, t, 0); got.Value != "hi" {
+					t.Errorf("want %q, got %q", "hi", got.Value)
+				}
+			},
+		},
+		{
+			name: "unknown bytes to string",
+			source: `
+func conv(b []byte) string {
+	return string(b)
+}
+
+func run() string {
+	return conv(nil)
+}`,
+			check: func(t *testing.T, r *symgotest.Result) {
+				got := symgotest.AssertAs[*object.SymbolicPlaceholder](r, t, 0)
+				if ti := got.TypeInfo(); ti == nil || ti.Name != "string" {
+					t.Errorf("want a placeholder of string, got %v", ti)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := symgotest.TestCase{
+				Source: map[string]string{
+					"go.mod":  "module example.com/me\ngo 1.22",
+					"main.go": "package main\n" + types + tt.source,
+				},
+				EntryPoint: "example.com/me.run",
+			}
+			symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+				if r.Error != nil {
+					t.Fatalf("execution failed: %+v", r.Error)
+				}
+				tt.check(t, r)
+			})
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package symgo
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+func TestCoverage(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/me\ngo 1.21\n",
+		"main.go": `
+package main
+
+type T struct{}
+
+func (t *T) Run(n int) string {
+	if n > 0 {
+		return "positive"
+	} else if n < 0 {
+		return "negative"
+	}
+	switch n {
+	case 0:
+		n++
+	default:
+		n--
+	}
+	f := func() int { return n }
+	return label(f())
+}
+
+func label(n int) string {
+	return "label"
+	println("unreachable")
+}
+
+func main() {
+	t := &T{}
+	t.Run(1)
+}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	run := func(t *testing.T, options ...Option) *Interpreter {
+		t.Helper()
+		s, err := goscan.New(goscan.WithWorkDir(dir), goscan.WithGoModuleResolver())
+		if err != nil {
+			t.Fatalf("failed to create scanner: %v", err)
+		}
+		interp, err := NewInterpreter(s, append([]Option{WithLogger(s.Logger)}, options...)...)
+		if err != nil {
+			t.Fatalf("failed to create interpreter: %v", err)
+		}
+		ctx := t.Context()
+		pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/me")
+		if err != nil {
+			t.Fatalf("could not scan package: %v", err)
+		}
+		if _, err := interp.Eval(ctx, pkg.AstFiles[pkg.Files[0]], pkg); err != nil {
+			t.Fatalf("evaluation of main pkg failed: %v", err)
+		}
+		mainFunc, ok := interp.FindObjectInPackage(ctx, "example.com/me", "main")
+		if !ok {
+			t.Fatalf("could not find main function")
+		}
+		interp.Apply(ctx, mainFunc, []object.Object{}, pkg) // the error of an aborted evaluation is in the coverage
+		return interp
+	}
+
+	type coverage struct {
+		Function   string
+		Statements string
+		Branches   string
+		Uncovered  []int // the lines of the uncovered statements
+		Aborted    bool
+		Partial    bool
+	}
+	summarize := func(report []FunctionCoverage) []coverage {
+		var got []coverage
+		for _, c := range report {
+			cov := coverage{
+				Function:   c.Function,
+				Statements: fmt.Sprintf("%d/%d", c.CoveredStatements, c.Statements),
+				Branches:   fmt.Sprintf("%d/%d", c.CoveredBranches, c.Branches),
+				Aborted:    c.Aborted != "",
+				Partial:    c.Partial(),
+			}
+			for _, pos := range c.UncoveredStatements {
+				cov.Uncovered = append(cov.Uncovered, pos.Line)
+			}
+			got = append(got, cov)
+		}
+		return got
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		if got := run(t).Coverage(); len(got) != 0 {
+			t.Errorf("Coverage() = %v, want none", got)
+		}
+	})
+
+	t.Run("explored", func(t *testing.T) {
+		want := []coverage{
+			{Function: "example.com/me.(*T).Run", Statements: "9/9", Branches: "5/5"},
+			{Function: "example.com/me.<closure>", Statements: "1/1", Branches: "0/0"},
+			{Function: "example.com/me.label", Statements: "1/2", Branches: "0/0", Uncovered: []int{24}, Partial: true},
+			{Function: "example.com/me.main", Statements: "2/2", Branches: "0/0"},
+		}
+		if diff := cmp.Diff(want, summarize(run(t, WithCoverage(true)).Coverage())); diff != "" {
+			t.Errorf("Coverage() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		report := run(t, WithCoverage(true), WithMaxSteps(30)).Coverage()
+		var aborted []string
+		for _, c := range report {
+			if c.Aborted != "" {
+				aborted = append(aborted, c.Function)
+				if !strings.Contains(c.Aborted, "max execution steps") || !c.Partial() {
+					t.Errorf("%s: unexpected coverage %+v", c.Function, c)
+				}
+			}
+		}
+		if diff := cmp.Diff([]string{"example.com/me.(*T).Run", "example.com/me.main"}, aborted); diff != "" {
+			t.Errorf("aborted functions mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
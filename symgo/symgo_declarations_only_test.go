@@ -121,6 +121,95 @@ func ShouldNotBeCalled() {}
 	}
 }
 
+func TestLoadModeFollowsAnalysisScopes(t *testing.T) {
+	mainCode := `
+package main
+import "example.com/me/foreign/lib"
+func main() {
+	helper()
+	lib.DoSomething()
+}
+func helper() {
+	// Reached only if the body of helper is loaded.
+	reached()
+}
+func reached() {}
+`
+	foreignCode := `
+package lib
+func DoSomething() {
+	ShouldNotBeCalled()
+}
+func ShouldNotBeCalled() {}
+`
+	files := map[string]string{
+		"go.mod":             "module example.com/me",
+		"main.go":            mainCode,
+		"foreign/lib/lib.go": foreignCode,
+	}
+
+	dir, cleanup := writeTestFiles(t, files)
+	defer cleanup()
+
+	// Only declarations are loaded by default; symgo must request bodies for its primary scope.
+	s, err := goscan.New(goscan.WithWorkDir(dir), goscan.WithLoadMode(goscan.LoadDecls))
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	interp, err := symgo.NewInterpreter(s,
+		symgo.WithPrimaryAnalysisScope("example.com/me/..."),
+		symgo.WithSymbolicDependencyScope("example.com/me/foreign/lib"),
+	)
+	if err != nil {
+		t.Fatalf("could not create symgo interpreter: %v", err)
+	}
+
+	for path, want := range map[string]goscan.LoadMode{
+		"example.com/me":             goscan.LoadFull,
+		"example.com/me/foreign/lib": goscan.LoadDecls,
+		"strings":                    goscan.LoadDecls,
+	} {
+		if got := s.LoadModeFor(path); got != want {
+			t.Errorf("LoadModeFor(%q) = %s, want %s", path, got, want)
+		}
+	}
+
+	var reached, shouldNotBeCalledReached bool
+	interp.RegisterIntrinsic("example.com/me.reached", func(ctx context.Context, i *symgo.Interpreter, args []object.Object) object.Object {
+		reached = true
+		return nil
+	})
+	interp.RegisterIntrinsic("example.com/me/foreign/lib.ShouldNotBeCalled", func(ctx context.Context, i *symgo.Interpreter, args []object.Object) object.Object {
+		shouldNotBeCalledReached = true
+		return nil
+	})
+
+	ctx := context.Background()
+	pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/me")
+	if err != nil {
+		t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+	}
+	for _, file := range pkg.AstFiles {
+		if _, err := interp.Eval(ctx, file, pkg); err != nil {
+			t.Fatalf("Eval(file) returned an error: %v", err)
+		}
+	}
+	mainFunc, ok := interp.FindObjectInPackage(ctx, "example.com/me", "main")
+	if !ok {
+		t.Fatal("main function not found")
+	}
+	if _, err := interp.Apply(ctx, mainFunc, nil, pkg); err != nil {
+		t.Fatalf("Apply(main) returned an error: %v", err)
+	}
+
+	if !reached {
+		t.Error("the body of helper was not evaluated, the primary scope should be loaded with LoadFull")
+	}
+	if shouldNotBeCalledReached {
+		t.Error("intrinsic for ShouldNotBeCalled was reached, but it should have been ignored")
+	}
+}
+
 // findPackage is a test helper to find a package by its import path.
 func findPackage(t *testing.T, pkgs []*goscan.Package, importPath string) *goscan.Package {
 	t.Helper()
@@ -0,0 +1,164 @@
+package symgo_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestEmbeddedMethodPromotion(t *testing.T) {
+	source := `
+package main
+
+type Base struct{}
+
+func (b *Base) DoThing() {}
+func (b *Base) Shadowed() {}
+
+type Mid struct {
+	*Base
+}
+
+func (m Mid) MidOnly() {}
+
+type Top struct {
+	Mid
+}
+
+func (t *Top) Shadowed() {}
+
+type Left struct{}
+
+func (Left) Ambiguous() {}
+
+type Right struct{}
+
+func (Right) Ambiguous() {}
+
+type Both struct {
+	Left
+	Right
+}
+
+func (b Both) Use() {
+	b.Ambiguous()
+}
+
+type S struct {
+	*Base
+}
+
+type Other struct{}
+
+func (o *Other) DoThing() {}
+
+type Shallow struct {
+	Mid
+	*Other
+}
+
+type Shared struct{}
+
+func (Shared) Twice() {}
+
+type ViaLeft struct{ Shared }
+
+type ViaRight struct{ *Shared }
+
+type Diamond struct {
+	ViaLeft
+	ViaRight
+}
+
+func (d Diamond) UseTwice() {
+	d.Twice()
+}
+
+type Named struct{ Name string }
+
+type Namer struct{}
+
+func (Namer) Name() string { return "" }
+
+type Clash struct {
+	Named
+	Namer
+}
+
+func (c Clash) UseName() {
+	c.Name()
+}
+
+func main() {
+	s := S{Base: &Base{}}
+	s.DoThing()
+
+	top := &Top{}
+	top.DoThing()
+	top.MidOnly()
+	top.Shadowed()
+
+	var both Both
+	both.Use()
+
+	shallow := &Shallow{}
+	shallow.DoThing()
+
+	var diamond Diamond
+	diamond.UseTwice()
+
+	var clash Clash
+	clash.UseName()
+}
+`
+	var called []string
+	tc := symgotest.TestCase{
+		Source: map[string]string{
+			"go.mod":  "module example.com/me\n\ngo 1.21\n",
+			"main.go": source,
+		},
+		EntryPoint: "example.com/me.main",
+		Options: []symgotest.Option{
+			symgotest.WithDefaultIntrinsic(func(ctx context.Context, i *symgo.Interpreter, args []symgo.Object) symgo.Object {
+				if len(args) == 0 {
+					return object.NIL
+				}
+				if fn, ok := args[0].(*symgo.Function); ok && fn.Def != nil && fn.Def.Receiver != nil {
+					recv := fn.Def.Receiver.Type
+					name := recv.TypeName
+					if name == "" {
+						name = recv.Name
+					}
+					called = append(called, fmt.Sprintf("%s.%s", name, fn.Def.Name))
+				}
+				return object.NIL
+			}),
+		},
+	}
+
+	symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+		if r.Error != nil {
+			t.Fatalf("Execution failed unexpectedly: %v", r.Error)
+		}
+		sort.Strings(called)
+		want := []string{
+			"Base.DoThing", // via S{*Base}
+			"Base.DoThing", // via Top -> Mid -> *Base
+			"Both.Use",
+			"Clash.UseName",    // Named.Name and Namer.Name are at the same depth
+			"Diamond.UseTwice", // Shared.Twice is reached through ViaLeft and ViaRight
+			"Mid.MidOnly",
+			"Other.DoThing", // depth 1 via *Other wins over depth 2 via Mid -> *Base
+			"Top.Shadowed",  // the shallower method wins over Base.Shadowed
+		}
+		if diff := cmp.Diff(want, called); diff != "" {
+			t.Errorf("called methods mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package symgo_test
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestInterfaceBinding_ConstructorResults(t *testing.T) {
+	storeSource := `
+package store
+
+type Reader interface {
+	Read() string
+}
+
+type file struct{}
+
+func (f *file) Read() string { return readFile() }
+
+type debugFile struct{}
+
+func (f *debugFile) Read() string { return readDebugFile() }
+
+// mem is never returned by the constructors.
+type mem struct{}
+
+func (m *mem) Read() string { return readMem() }
+
+func readFile() string      { return "" }
+func readDebugFile() string { return "" }
+func readMem() string       { return "" }
+
+func New(debug bool) Reader {
+	if debug {
+		return &debugFile{}
+	}
+	return &file{}
+}
+
+func Open() (Reader, error) {
+	return &file{}, nil
+}
+`
+	tests := []struct {
+		name string
+		main string
+		want []string
+	}{
+		{
+			name: "all branches of the constructor",
+			main: `
+package main
+
+import "myapp/store"
+
+func consume(r store.Reader) string {
+	return r.Read()
+}
+
+func main() {
+	consume(store.New(false))
+}
+`,
+			want: []string{"readDebugFile", "readFile"},
+		},
+		{
+			name: "multiple results",
+			main: `
+package main
+
+import "myapp/store"
+
+func main() {
+	if r, err := store.Open(); err == nil {
+		r.Read()
+	}
+}
+`,
+			want: []string{"readFile"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := map[string]bool{}
+			tc := symgotest.TestCase{
+				Source: map[string]string{
+					"go.mod":         "module myapp",
+					"main.go":        tt.main,
+					"store/store.go": storeSource,
+				},
+				EntryPoint: "myapp.main",
+				Options: []symgotest.Option{
+					symgotest.WithDefaultIntrinsic(func(ctx context.Context, i *symgo.Interpreter, args []object.Object) object.Object {
+						if fn, ok := args[0].(*object.Function); ok && fn.Name != nil && strings.HasPrefix(fn.Name.Name, "read") {
+							called[fn.Name.Name] = true
+						}
+						return nil
+					}),
+				},
+			}
+
+			symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+				// Finalize is not called, as it marks the Read method of every implementation of Reader.
+				got := slices.Sorted(maps.Keys(called))
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("called functions mismatch (-want +got):\n%s", diff)
+				}
+			})
+		})
+	}
+}
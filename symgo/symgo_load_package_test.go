@@ -0,0 +1,108 @@
+package symgo_test
+
+import (
+	"context"
+	"testing"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+func TestLoadPackage(t *testing.T) {
+	code := `
+package app
+
+type Server struct{ name string }
+
+func (s *Server) Start() string { return "start " + s.name }
+func (s Server) Name() string   { return s.name }
+func (s *Server) Run() string   { return "method" }
+
+func Run() string { return "func" }
+
+func NewServer() *Server { return &Server{name: "app"} }
+
+var Default = NewServer()
+
+var _ = Init()
+
+func Init() int { return 1 }
+`
+	dir, cleanup := writeTestFiles(t, map[string]string{
+		"go.mod": "module example.com/app",
+		"app.go": code,
+	})
+	defer cleanup()
+
+	ctx := context.Background()
+	s, err := goscan.New(goscan.WithWorkDir(dir))
+	if err != nil {
+		t.Fatalf("goscan.New() failed: %v", err)
+	}
+	pkgs, err := s.Scan(ctx, "./...")
+	if err != nil {
+		t.Fatalf("s.Scan() failed: %v", err)
+	}
+	interp, err := symgo.NewInterpreter(s)
+	if err != nil {
+		t.Fatalf("could not create symgo interpreter: %v", err)
+	}
+
+	var initialized []string
+	for _, name := range []string{"NewServer", "Init"} {
+		name := name
+		interp.RegisterIntrinsic("example.com/app."+name, func(ctx context.Context, i *symgo.Interpreter, args []object.Object) object.Object {
+			initialized = append(initialized, name)
+			return &object.SymbolicPlaceholder{Reason: name}
+		})
+	}
+
+	if err := interp.LoadPackage(ctx, findPackage(t, pkgs, "example.com/app")); err != nil {
+		t.Fatalf("LoadPackage() failed: %v", err)
+	}
+	if len(initialized) != 2 {
+		t.Errorf("want the variable initializers to be evaluated, called %v", initialized)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		wantDecl string // the name of the declaration of the function found
+		wantRecv bool
+	}{
+		{name: "Run", wantDecl: "Run"},
+		{name: "NewServer", wantDecl: "NewServer"},
+		{name: "(*Server).Start", wantDecl: "Start", wantRecv: true},
+		{name: "(*Server).Run", wantDecl: "Run", wantRecv: true},
+		{name: "Server.Name", wantDecl: "Name", wantRecv: true},
+		{name: "(*Server).Name", wantDecl: "Name", wantRecv: true}, // in the method set of *Server
+		{name: "(*example.com/app.Server).Start", wantDecl: "Start", wantRecv: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, ok := interp.FindObjectInPackage(ctx, "example.com/app", tt.name)
+			if !ok {
+				t.Fatalf("FindObjectInPackage(%q) found nothing", tt.name)
+			}
+			fn, ok := obj.(*object.Function)
+			if !ok {
+				t.Fatalf("want a function, got %T", obj)
+			}
+			if fn.Decl == nil || fn.Decl.Name.Name != tt.wantDecl || (fn.Decl.Recv != nil) != tt.wantRecv {
+				t.Errorf("FindObjectInPackage(%q) found the wrong function %v", tt.name, fn.Inspect())
+			}
+		})
+	}
+
+	for _, name := range []string{"Server.Start", "(*Server).Stop", "(*other.Server).Start", "Default"} {
+		obj, ok := interp.FindObjectInPackage(ctx, "example.com/app", name)
+		if name == "Default" {
+			if _, isVar := obj.(*object.Variable); !ok || !isVar {
+				t.Errorf("FindObjectInPackage(%q) = %T, want the variable", name, obj)
+			}
+			continue
+		}
+		if ok {
+			t.Errorf("FindObjectInPackage(%q) = %v, want nothing", name, obj.Inspect())
+		}
+	}
+}
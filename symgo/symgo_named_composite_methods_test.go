@@ -0,0 +1,151 @@
+package symgo_test
+
+import (
+	"testing"
+
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestNamedCompositeType_MethodCalls(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   int64
+	}{
+		{
+			name: "map literal",
+			source: `
+type StringSet map[string]bool
+func (s StringSet) Keys() int { return 1 }
+
+func run() int {
+	m := StringSet{"a": true}
+	return m.Keys()
+}`,
+			want: 1,
+		},
+		{
+			name: "map made with make",
+			source: `
+type StringSet map[string]bool
+func (s StringSet) Keys() int { return 1 }
+
+func run() int {
+	m := make(StringSet)
+	return m.Keys()
+}`,
+			want: 1,
+		},
+		{
+			name: "slice literal",
+			source: `
+type Ints []int
+func (s Ints) Len() int { return 2 }
+
+func run() int {
+	xs := Ints{1, 2}
+	return xs.Len()
+}`,
+			want: 2,
+		},
+		{
+			name: "slice after append",
+			source: `
+type Ints []int
+func (s Ints) Len() int { return 2 }
+
+func run() int {
+	var xs Ints
+	xs = append(xs, 1)
+	return xs.Len()
+}`,
+			want: 2,
+		},
+		{
+			name: "array literal",
+			source: `
+type Pair [2]int
+func (p Pair) Sum() int { return 3 }
+
+func run() int {
+	p := Pair{1, 2}
+	return p.Sum()
+}`,
+			want: 3,
+		},
+		{
+			name: "converted function",
+			source: `
+type HandlerFunc func() int
+func (f HandlerFunc) Serve() int { return f() }
+
+func run() int {
+	h := HandlerFunc(func() int { return 4 })
+	return h.Serve()
+}`,
+			want: 4,
+		},
+		{
+			name: "parameter",
+			source: `
+type Ints []int
+func (s Ints) Len() int { return 2 }
+
+func use(xs Ints) int { return xs.Len() }
+
+func run() int {
+	return use(Ints{1})
+}`,
+			want: 2,
+		},
+		{
+			name: "struct field",
+			source: `
+type Ints []int
+func (s Ints) Len() int { return 2 }
+
+type S struct{ xs Ints }
+
+func run() int {
+	s := S{xs: Ints{1}}
+	return s.xs.Len()
+}`,
+			want: 2,
+		},
+		{
+			name: "function result",
+			source: `
+type Ints []int
+func (s Ints) Len() int { return 2 }
+
+func mk() Ints { return Ints{1} }
+
+func run() int {
+	return mk().Len()
+}`,
+			want: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := symgotest.TestCase{
+				Source: map[string]string{
+					"go.mod":  "module example.com/me\ngo 1.22",
+					"main.go": "package main\n" + tt.source,
+				},
+				EntryPoint: "example.com/me.run",
+			}
+			symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+				if r.Error != nil {
+					t.Fatalf("execution failed: %+v", r.Error)
+				}
+				got := symgotest.AssertAs[*object.Integer](r, t, 0)
+				if got.Value != tt.want {
+					t.Errorf("want %d, got %d", tt.want, got.Value)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package symgo_test
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestRecursionWidening(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		widening bool
+		want     []string
+	}{
+		{
+			name: "function value passed to the recursive call",
+			source: `
+package main
+
+func root(n int) {}
+func leaf(n int) {}
+
+func walk(n int, visit func(int)) {
+	visit(n)
+	if n > 0 {
+		walk(n-1, leaf)
+	}
+}
+
+func main() {
+	walk(3, root)
+}
+`,
+			widening: true,
+			want:     []string{"leaf", "root", "walk"},
+		},
+		{
+			name: "without widening, the recursive call is not evaluated",
+			source: `
+package main
+
+func root(n int) {}
+func leaf(n int) {}
+
+func walk(n int, visit func(int)) {
+	visit(n)
+	if n > 0 {
+		walk(n-1, leaf)
+	}
+}
+
+func main() {
+	walk(3, root)
+}
+`,
+			widening: false,
+			want:     []string{"root", "walk"},
+		},
+		{
+			name: "mutual recursion through a shared helper",
+			source: `
+package main
+
+func cont(f func(int), n int) {
+	f(n)
+}
+
+func Ping(n int) {
+	pinged()
+	cont(Pong, n+1)
+}
+
+func Pong(n int) {
+	ponged()
+	cont(Ping, n+1)
+}
+
+func pinged() {}
+func ponged() {}
+
+func main() {
+	Ping(0)
+}
+`,
+			widening: true,
+			want:     []string{"Ping", "Pong", "cont", "pinged", "ponged"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := map[string]int{}
+			tc := symgotest.TestCase{
+				Source: map[string]string{
+					"go.mod":  "module myapp",
+					"main.go": tt.source,
+				},
+				EntryPoint: "myapp.main",
+				Options: []symgotest.Option{
+					symgotest.WithInterpreterOptions(symgo.WithRecursionWidening(tt.widening)),
+					symgotest.WithDefaultIntrinsic(func(ctx context.Context, i *symgo.Interpreter, args []object.Object) object.Object {
+						if fn, ok := args[0].(*object.Function); ok && fn.Name != nil {
+							called[fn.Name.Name]++
+						}
+						return nil
+					}),
+				},
+			}
+
+			symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+				if r.Error != nil {
+					t.Fatalf("expected no error, but got: %+v", r.Error)
+				}
+				got := slices.Sorted(maps.Keys(called))
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("called functions mismatch (-want +got):\n%s", diff)
+				}
+			})
+		})
+	}
+}
+
+func TestRecursionWidening_NoBlowup(t *testing.T) {
+	// Each call makes three recursive calls, so unrolling the recursion would evaluate
+	// exponentially many calls. The summary of the cycle is reused instead.
+	source := `
+package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2) + fib(n-3)
+}
+
+func main() {
+	fib(30)
+}
+`
+	calls := 0
+	tc := symgotest.TestCase{
+		Source: map[string]string{
+			"go.mod":  "module myapp",
+			"main.go": source,
+		},
+		EntryPoint: "myapp.main",
+		Options: []symgotest.Option{
+			symgotest.WithDefaultIntrinsic(func(ctx context.Context, i *symgo.Interpreter, args []object.Object) object.Object {
+				if fn, ok := args[0].(*object.Function); ok && fn.Name != nil && fn.Name.Name == "fib" {
+					calls++
+				}
+				return nil
+			}),
+		},
+	}
+
+	symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+		if r.Error != nil {
+			t.Fatalf("expected no error, but got: %+v", r.Error)
+		}
+		// main -> fib (1), the widened fib (3), and the calls to the summary (3 + 3).
+		if calls > 10 {
+			t.Errorf("fib is called %d times, want at most 10", calls)
+		}
+	})
+}
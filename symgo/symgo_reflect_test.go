@@ -0,0 +1,110 @@
+package symgo_test
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestReflectMethodByName(t *testing.T) {
+	const server = `
+package main
+
+import "reflect"
+
+type Server struct{}
+
+func (s *Server) Start() { started() }
+func (s *Server) Stop()  {}
+func (s Server) HandleUsers() {}
+func (s *Server) HandleItems() {}
+func (s *Server) helper() {}
+
+func started() {}
+`
+	tests := []struct {
+		name       string
+		body       string
+		reflectAll bool
+		want       []string
+	}{
+		{
+			name: "constant name",
+			body: `
+func dispatch(s *Server, name string) {
+	reflect.ValueOf(s).MethodByName("Start").Call(nil)
+}
+`,
+			want: []string{"Start", "started"},
+		},
+		{
+			name: "non-constant name",
+			body: `
+func dispatch(s *Server, name string) {
+	reflect.ValueOf(s).MethodByName(name).Call(nil)
+}
+`,
+			want: nil,
+		},
+		{
+			name: "non-constant name, all methods",
+			body: `
+func dispatch(s *Server, name string) {
+	v := reflect.ValueOf(s)
+	m := v.MethodByName(name)
+	m.Call(nil)
+}
+`,
+			reflectAll: true,
+			want:       []string{"HandleItems", "HandleUsers", "Start", "Stop", "started"},
+		},
+		{
+			name: "partially constant name, all methods",
+			body: `
+func dispatch(s *Server, name string) {
+	reflect.ValueOf(s).MethodByName("Handle" + name).Call(nil)
+}
+`,
+			reflectAll: true,
+			want:       []string{"HandleItems", "HandleUsers"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := map[string]int{}
+			tc := symgotest.TestCase{
+				Source: map[string]string{
+					"go.mod":  "module myapp",
+					"main.go": server + tt.body,
+				},
+				EntryPoint: "myapp.dispatch",
+				Options: []symgotest.Option{
+					symgotest.WithInterpreterOptions(symgo.WithReflectAllMethods(tt.reflectAll)),
+					symgotest.WithDefaultIntrinsic(func(ctx context.Context, i *symgo.Interpreter, args []object.Object) object.Object {
+						if fn, ok := args[0].(*object.Function); ok && fn.Name != nil {
+							called[fn.Name.Name]++
+						}
+						return nil
+					}),
+				},
+			}
+
+			symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+				if r.Error != nil {
+					t.Fatalf("expected no error, but got: %+v", r.Error)
+				}
+				got := slices.Sorted(maps.Keys(called))
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("called functions mismatch (-want +got):\n%s", diff)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package symgo_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestInterpreter_SnapshotRestore(t *testing.T) {
+	source := `
+package main
+
+var count = 0
+var mode = "default"
+
+func main() {
+	count++
+}
+
+func enable() {
+	count++
+	mode = "enabled"
+}
+
+func disable() {
+	mode = "disabled"
+}
+`
+	tc := symgotest.TestCase{
+		Source: map[string]string{
+			"go.mod":  "module myapp",
+			"main.go": source,
+		},
+		EntryPoint: "myapp.main",
+	}
+
+	action := func(t *testing.T, r *symgotest.Result) {
+		if r.Error != nil {
+			t.Fatalf("Execution failed: %+v", r.Error)
+		}
+		ctx := t.Context()
+		interp := r.Interpreter
+
+		apply := func(name string) {
+			t.Helper()
+			fn, ok := interp.FindObjectInPackage(ctx, "myapp", name)
+			if !ok {
+				t.Fatalf("function %q not found", name)
+			}
+			if _, err := interp.Apply(ctx, fn, nil, fn.(*symgo.Function).Package); err != nil {
+				t.Fatalf("Apply(%s) failed: %v", name, err)
+			}
+		}
+		state := func() []string {
+			t.Helper()
+			var values []string
+			for _, name := range []string{"count", "mode"} {
+				obj, ok := interp.FindObjectInPackage(ctx, "myapp", name)
+				if !ok {
+					t.Fatalf("variable %q not found", name)
+				}
+				v := obj.(*object.Variable)
+				values = append(values, v.Inspect())
+			}
+			return values
+		}
+
+		before := state()
+		snapshot := interp.Snapshot()
+
+		apply("enable")
+		want := []string{"2", `"enabled"`}
+		if diff := cmp.Diff(want, state()); diff != "" {
+			t.Errorf("state after enable() mismatch (-want +got):\n%s", diff)
+		}
+
+		// The other branch starts from the state of the snapshot.
+		interp.Restore(snapshot)
+		if diff := cmp.Diff(before, state()); diff != "" {
+			t.Errorf("state after Restore() mismatch (-want +got):\n%s", diff)
+		}
+		apply("disable")
+		want = []string{"1", `"disabled"`}
+		if diff := cmp.Diff(want, state()); diff != "" {
+			t.Errorf("state after disable() mismatch (-want +got):\n%s", diff)
+		}
+
+		// A snapshot can be restored several times.
+		interp.Restore(snapshot)
+		if diff := cmp.Diff(before, state()); diff != "" {
+			t.Errorf("state after the second Restore() mismatch (-want +got):\n%s", diff)
+		}
+
+		// Once released, the modifications are kept.
+		interp.ReleaseSnapshot(snapshot)
+		apply("enable")
+		interp.Restore(snapshot)
+		want = []string{"2", `"enabled"`}
+		if diff := cmp.Diff(want, state()); diff != "" {
+			t.Errorf("state after restoring a released snapshot mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	symgotest.Run(t, tc, action)
+}
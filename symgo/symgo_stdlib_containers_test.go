@@ -0,0 +1,202 @@
+package symgo_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestStdlibIntrinsics_Containers(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name: "global atomic.Value storing implementations",
+			source: `
+import "sync/atomic"
+
+type Greeter interface{ Greet() }
+type en struct{}
+func (en) Greet() { hello() }
+type ja struct{}
+func (*ja) Greet() { konnichiwa() }
+
+var current atomic.Value
+
+func setup(lang string) {
+	if lang == "ja" {
+		current.Store(&ja{})
+	} else {
+		current.Store(en{})
+	}
+}
+
+func run() {
+	setup("ja")
+	current.Load().(Greeter).Greet()
+}
+func hello() {}
+func konnichiwa() {}`,
+			want: []string{"(*ja).Greet", "(en).Greet", "hello", "konnichiwa", "setup"},
+		},
+		{
+			name: "struct field atomic.Value",
+			source: `
+import "sync/atomic"
+
+type Hook interface{ Fire() }
+type auditHook struct{}
+func (*auditHook) Fire() { audited() }
+
+type Server struct{ hook atomic.Value }
+
+func (s *Server) SetHook(h Hook) { s.hook.Store(h) }
+func (s *Server) Run() { s.hook.Load().(Hook).Fire() }
+
+func run() {
+	s := &Server{}
+	s.SetHook(&auditHook{})
+	s.Run()
+}
+func audited() {}`,
+			want: []string{"(*Server).Run", "(*Server).SetHook", "(*auditHook).Fire", "audited"},
+		},
+		{
+			name: "sync.Map",
+			source: `
+import "sync"
+
+type Handler interface{ Handle() }
+type ping struct{}
+func (*ping) Handle() { pong() }
+
+func run() {
+	var m sync.Map
+	m.Store("ping", &ping{})
+	if h, ok := m.Load("ping"); ok {
+		h.(Handler).Handle()
+	}
+	m.Range(func(k, v any) bool {
+		v.(Handler).Handle()
+		return true
+	})
+}
+func pong() {}`,
+			want: []string{"(*ping).Handle", "pong"},
+		},
+		{
+			name: "context.WithValue",
+			source: `
+import "context"
+
+type loggerKey struct{}
+type Logger interface{ Log() }
+type stdLogger struct{}
+func (*stdLogger) Log() { logged() }
+
+func run() {
+	ctx := context.WithValue(context.Background(), loggerKey{}, &stdLogger{})
+	handle(ctx)
+}
+func handle(ctx context.Context) {
+	ctx.Value(loggerKey{}).(Logger).Log()
+}
+func logged() {}`,
+			want: []string{"(*stdLogger).Log", "handle", "logged"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called []string
+			tc := symgotest.TestCase{
+				Source: map[string]string{
+					"go.mod":  "module example.com/me\ngo 1.22",
+					"main.go": "package main\n" + tt.source,
+				},
+				EntryPoint: "example.com/me.run",
+				Options: []symgotest.Option{
+					symgotest.WithInterpreterOptions(symgo.WithStdlibIntrinsics(true)),
+					symgotest.WithDefaultIntrinsic(func(ctx context.Context, i *symgo.Interpreter, args []symgo.Object) symgo.Object {
+						if fn, ok := args[0].(*symgo.Function); ok && fn.Def != nil {
+							name := fn.Def.Name
+							if fn.Def.Receiver != nil {
+								name = "(" + fn.Def.Receiver.Type.String() + ")." + name
+							}
+							called = append(called, name)
+						}
+						return nil
+					}),
+				},
+			}
+			symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+				if r.Error != nil {
+					t.Fatalf("execution failed: %+v", r.Error)
+				}
+				got := uniqueSorted(called)
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("called functions mismatch (-want +got):\n%s", diff)
+				}
+			})
+		})
+	}
+}
+
+func TestStdlibIntrinsics_ContainerFunctions(t *testing.T) {
+	source := `
+import "sync/atomic"
+
+func run() int {
+	n := 0
+	var v atomic.Value
+	v.Store(func() { n = 1 })
+	f := v.Load().(func())
+	f()
+	return n
+}`
+	for _, enabled := range []bool{true, false} {
+		want := int64(1)
+		if !enabled {
+			want = 0 // The stored function is only scanned, and its writes are rolled back.
+		}
+		tc := symgotest.TestCase{
+			Source: map[string]string{
+				"go.mod":  "module example.com/me\ngo 1.22",
+				"main.go": "package main\n" + source,
+			},
+			EntryPoint: "example.com/me.run",
+			Options: []symgotest.Option{
+				symgotest.WithInterpreterOptions(symgo.WithStdlibIntrinsics(enabled)),
+			},
+		}
+		symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+			if r.Error != nil {
+				t.Fatalf("execution failed: %+v", r.Error)
+			}
+			got := symgotest.AssertAs[*object.Integer](r, t, 0)
+			if got.Value != want {
+				t.Errorf("enabled=%v: want %d, got %d", enabled, want, got.Value)
+			}
+		})
+	}
+}
+
+func uniqueSorted(xs []string) []string {
+	seen := map[string]bool{}
+	var r []string
+	for _, x := range xs {
+		if !seen[x] {
+			seen[x] = true
+			r = append(r, x)
+		}
+	}
+	sort.Strings(r)
+	return r
+}
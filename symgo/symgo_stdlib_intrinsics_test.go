@@ -0,0 +1,135 @@
+package symgo_test
+
+import (
+	"testing"
+
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+	"github.com/podhmo/go-scan/symgo/symgotest"
+)
+
+func TestStdlibIntrinsics(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   int64
+	}{
+		{
+			name: "sort.Slice",
+			source: `
+import "sort"
+
+func run() int {
+	xs := []int{2, 1}
+	n := 0
+	sort.Slice(xs, func(i, j int) bool {
+		n++
+		return xs[i] < xs[j]
+	})
+	return n
+}`,
+			want: 1,
+		},
+		{
+			name: "sort.Search",
+			source: `
+import "sort"
+
+func run() int {
+	n := 0
+	sort.Search(10, func(i int) bool {
+		n = 3
+		return i > 5
+	})
+	return n
+}`,
+			want: 3,
+		},
+		{
+			name: "sync.Once.Do",
+			source: `
+import "sync"
+
+func run() int {
+	n := 0
+	var once sync.Once
+	once.Do(func() { n = 1 })
+	return n
+}`,
+			want: 1,
+		},
+		{
+			name: "http.HandlerFunc",
+			source: `
+import "net/http"
+
+func run() int {
+	n := 0
+	http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		n = 4
+	})
+	return n
+}`,
+			want: 4,
+		},
+		{
+			name: "filepath.WalkDir",
+			source: `
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+func run() int {
+	n := 0
+	filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		n = 5
+		return nil
+	})
+	return n
+}`,
+			want: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, enabled := range []bool{true, false} {
+			want := tt.want
+			if !enabled {
+				want = 0 // The callback is only scanned, and its writes are rolled back.
+			}
+			name := tt.name
+			if !enabled {
+				name += " (disabled)"
+			}
+			t.Run(name, func(t *testing.T) {
+				tc := symgotest.TestCase{
+					Source: map[string]string{
+						"go.mod":  "module example.com/me\ngo 1.22",
+						"main.go": "package main\n" + tt.source,
+					},
+					EntryPoint: "example.com/me.run",
+					Options: []symgotest.Option{
+						symgotest.WithInterpreterOptions(symgo.WithStdlibIntrinsics(enabled)),
+					},
+				}
+				symgotest.Run(t, tc, func(t *testing.T, r *symgotest.Result) {
+					if r.Error != nil {
+						t.Fatalf("execution failed: %+v", r.Error)
+					}
+					got := symgotest.AssertAs[*object.Integer](r, t, 0)
+					if got.Value != want {
+						t.Errorf("want %d, got %d", want, got.Value)
+					}
+				})
+			})
+		}
+	}
+}
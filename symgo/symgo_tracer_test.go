@@ -63,3 +63,132 @@ func main() {
 
 	symgotest.Run(t, tc, action)
 }
+
+func TestInterpreter_WithTracer_Events(t *testing.T) {
+	var events []string
+	var placeholders []string
+	hooks := &symgo.TraceHooks{
+		OnEnterFunction: func(ev symgo.TraceEvent) {
+			fn := ev.Function.(*symgo.Function)
+			events = append(events, fmt.Sprintf("%s %s", ev.Kind, fn.Name.Name))
+		},
+		OnCallResolved: func(ev symgo.TraceEvent) {
+			if !ev.Pos.IsValid() {
+				t.Errorf("call event without position")
+			}
+			events = append(events, fmt.Sprintf("%s %s", ev.Kind, ev.Function.Inspect()))
+		},
+		OnBranchExplored: func(ev symgo.TraceEvent) {
+			events = append(events, ev.Kind.String())
+		},
+		OnPlaceholderCreated: func(ev symgo.TraceEvent) {
+			placeholders = append(placeholders, ev.Function.Inspect())
+		},
+	}
+	tc := symgotest.TestCase{
+		Source: map[string]string{
+			"go.mod": "module example.com/me",
+			"main.go": `package main
+
+import "strings"
+
+func check(s string) bool {
+	return strings.HasPrefix(s, "x")
+}
+
+func main() {
+	if check("xy") {
+		return
+	} else {
+		return
+	}
+}`,
+		},
+		EntryPoint: "example.com/me.main",
+		Options: []symgotest.Option{
+			symgotest.WithTracer(hooks),
+		},
+	}
+
+	action := func(t *testing.T, r *symgotest.Result) {
+		if r.Error != nil {
+			t.Fatalf("Execution failed unexpectedly: %v", r.Error)
+		}
+		expected := []string{
+			"enter-function main",
+			"call-resolved func check() { ... }",
+			"enter-function check",
+			"call-resolved <Unresolved Function: strings.HasPrefix>",
+			"branch-explored",
+			"branch-explored",
+		}
+		if !reflect.DeepEqual(events, expected) {
+			t.Errorf("Tracer did not record the expected events.\nGot:  %q\nWant: %q", events, expected)
+		}
+		// The result of check is symbolic, as the result of strings.HasPrefix is.
+		expectedPlaceholders := []string{"<Unresolved Function: strings.HasPrefix>", "func check() { ... }"}
+		if !reflect.DeepEqual(placeholders, expectedPlaceholders) {
+			t.Errorf("Tracer did not record the expected placeholders.\nGot:  %q\nWant: %q", placeholders, expectedPlaceholders)
+		}
+	}
+
+	symgotest.Run(t, tc, action)
+}
+
+func TestInterpreter_WithTracer_FieldAccessed(t *testing.T) {
+	var fields []string
+	hooks := &symgo.TraceHooks{
+		OnFieldAccessed: func(ev symgo.TraceEvent) {
+			fields = append(fields, fmt.Sprintf("%s.%s", ev.Type.Name, ev.Field.Name))
+		},
+	}
+	tc := symgotest.TestCase{
+		Source: map[string]string{
+			"go.mod": "module example.com/me",
+			"main.go": `package main
+
+type Base struct {
+	ID int
+}
+
+type User struct {
+	Base
+	Name string
+}
+
+type Group struct {
+	Name  string
+	Owner *User
+}
+
+func (u *User) Rename(name string) {
+	u.Name = name
+}
+
+func main() {
+	u := &User{}
+	u.Rename("x")
+	_ = u.ID
+	g := Group{Owner: u}
+	_ = g.Owner.Name
+}`,
+		},
+		EntryPoint: "example.com/me.main",
+		Options: []symgotest.Option{
+			symgotest.WithTracer(hooks),
+		},
+	}
+
+	action := func(t *testing.T, r *symgotest.Result) {
+		if r.Error != nil {
+			t.Fatalf("Execution failed unexpectedly: %v", r.Error)
+		}
+		// A promoted field is reported with the struct declaring it, and Group.Name is never accessed.
+		expected := []string{"User.Name", "Base.ID", "Group.Owner", "User.Name"}
+		if !reflect.DeepEqual(fields, expected) {
+			t.Errorf("Tracer did not record the expected fields.\nGot:  %q\nWant: %q", fields, expected)
+		}
+	}
+
+	symgotest.Run(t, tc, action)
+}
@@ -0,0 +1,85 @@
+package symgo
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+func TestUnresolvedCalls(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/me\ngo 1.21\n",
+		"main.go": `
+package main
+
+import "example.com/missing/lib"
+
+func count() int { return 1 }
+
+func log(s string) {}
+
+func main() {
+	n := count()
+	log("start")
+	conn := lib.Open(n)
+	conn.Close()
+}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	s, err := goscan.New(goscan.WithWorkDir(dir), goscan.WithGoModuleResolver())
+	if err != nil {
+		t.Fatalf("failed to create scanner: %v", err)
+	}
+	interp, err := NewInterpreter(s, WithLogger(s.Logger))
+	if err != nil {
+		t.Fatalf("failed to create interpreter: %v", err)
+	}
+
+	ctx := t.Context()
+	pkg, err := s.ScanPackageFromImportPath(ctx, "example.com/me")
+	if err != nil {
+		t.Fatalf("could not scan package: %v", err)
+	}
+	if _, err := interp.Eval(ctx, pkg.AstFiles[pkg.Files[0]], pkg); err != nil {
+		t.Fatalf("evaluation of main pkg failed: %v", err)
+	}
+	mainFunc, ok := interp.FindObjectInPackage(ctx, "example.com/me", "main")
+	if !ok {
+		t.Fatalf("could not find main function")
+	}
+	if _, err := interp.Apply(ctx, mainFunc, []object.Object{}, pkg); err != nil {
+		t.Fatalf("error applying main function: %v", err)
+	}
+
+	if got := interp.UnresolvedCalls(); len(got) != 0 {
+		t.Errorf("UnresolvedCalls() before Finalize = %v, want none", got)
+	}
+	interp.Finalize(ctx)
+
+	// count() is typed and log() has no result: neither is reported.
+	type call struct {
+		Line   int
+		Callee string
+		Reason UnresolvedReason
+	}
+	want := []call{
+		{Line: 13, Callee: "lib.Open", Reason: UnresolvedOutOfPolicy},
+		{Line: 14, Callee: "conn.Close", Reason: UnresolvedUnsupported},
+	}
+	var got []call
+	for _, c := range interp.UnresolvedCalls() {
+		got = append(got, call{Line: c.Pos.Line, Callee: c.Callee, Reason: c.Reason})
+		if c.Detail == "" {
+			t.Errorf("%s: empty detail", c.Callee)
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("UnresolvedCalls() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -110,7 +110,7 @@ func runLogic(t *testing.T, tc TestCase) *Result {
 			}
 			defaultPolicy := func(pkgPath string) bool {
 				for _, modPath := range modulePaths {
-					if strings.HasPrefix(pkgPath, modPath) {
+					if pkgPath == modPath || strings.HasPrefix(pkgPath, modPath+"/") {
 						return true
 					}
 				}
@@ -122,6 +122,8 @@ func runLogic(t *testing.T, tc TestCase) *Result {
 		}
 	}
 
+	interpreterOpts = append(interpreterOpts, cfg.InterpreterOpts...)
+
 	interpreter, err := symgo.NewInterpreter(scanner, interpreterOpts...)
 	if err != nil {
 		res.Error = &object.Error{Message: fmt.Sprintf("failed to create interpreter: %v", err)}
@@ -325,7 +327,11 @@ func NewExecutionTracer(fset *token.FileSet) *ExecutionTracer {
 }
 
 // Trace implements the symgo.Tracer interface.
+// Only the evaluated nodes are recorded.
 func (t *ExecutionTracer) Trace(event object.TraceEvent) {
+	if event.Kind != object.TraceNode {
+		return
+	}
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -368,6 +374,7 @@ type config struct {
 	DefaultIntrinsic symgo.IntrinsicFunc
 	SetupFunc        func(interp *symgo.Interpreter) error
 	Tracer           object.Tracer
+	InterpreterOpts  []symgo.Option
 }
 
 // Option configures a test run.
@@ -407,6 +414,14 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithInterpreterOptions passes additional options to symgo.NewInterpreter,
+// e.g. symgo.WithRecursionWidening(false).
+func WithInterpreterOptions(opts ...symgo.Option) Option {
+	return func(c *config) {
+		c.InterpreterOpts = append(c.InterpreterOpts, opts...)
+	}
+}
+
 // WithScanPolicy defines which packages are "in-policy" (evaluated recursively)
 // versus "out-of-policy" (treated as symbolic placeholders).
 func WithScanPolicy(policy symgo.ScanPolicyFunc) Option {
@@ -15,3 +15,24 @@ type EmbeddedReader interface {
 type AnotherInterface interface {
 	AnotherMethod() string
 }
+
+// ReaderAlias is an alias declaration for SimpleReader.
+type ReaderAlias = SimpleReader
+
+// Record is a type used in the signature of Store.
+type Record struct {
+	ID string
+}
+
+// Store has a method with a parameter of a type of this package.
+type Store interface {
+	Save(r *Record) error
+	All() []*Record
+}
+
+// ReaderConstraint has the method of SimpleReader but also a type list,
+// so it can only be used as a type constraint.
+type ReaderConstraint interface {
+	~struct{} | ~int
+	Read(p []byte) (n int, err error)
+}
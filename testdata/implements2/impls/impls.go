@@ -1,5 +1,7 @@
 package impls
 
+import "example.com/implements2/ifaces"
+
 // MyReader implements ifaces.SimpleReader.
 type MyReader struct{}
 
@@ -44,3 +46,20 @@ func (r *PartialImplementer) Read(p []byte) (n int, err error) {
 type StructWithEmbeddedConcrete struct {
 	EmbeddedStruct
 }
+
+// MyReaderAlias is an alias declaration for MyReader; it shares MyReader's methods.
+type MyReaderAlias = MyReader
+
+// MemoryStore implements ifaces.Store, referring to the types of the signature as ifaces.Record.
+type MemoryStore struct {
+	records []*ifaces.Record
+}
+
+func (s *MemoryStore) Save(r *ifaces.Record) error {
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *MemoryStore) All() []*ifaces.Record {
+	return s.records
+}
@@ -73,7 +73,14 @@ func main() {
 		return path != "example.com/test/foreign"
 	}
 
-	err := run(context.Background(), debugOff, true, false, dir, false, false, "auto", startPatterns, []string{"vendor"}, scanPolicy, nil, nil)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"vendor"},
+		ScanPolicy:    scanPolicy,
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// loadBaseline reads the IDs of the known orphans from the JSON output of a previous run.
+// As the IDs do not depend on file positions, the baseline stays valid when code moves around.
+func loadBaseline(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var orphans []Orphan
+	if err := json.Unmarshal(data, &orphans); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	known := make(map[string]bool, len(orphans))
+	for _, o := range orphans {
+		if o.ID != "" {
+			known[o.ID] = true
+		}
+	}
+	return known, nil
+}
+
+// filterBaseline drops the orphans listed in the baseline.
+func (a *analyzer) filterBaseline(ctx context.Context, orphans []Orphan) []Orphan {
+	var kept []Orphan
+	for _, o := range orphans {
+		if a.baseline[o.ID] {
+			continue
+		}
+		kept = append(kept, o)
+	}
+	if n := len(orphans) - len(kept); n > 0 {
+		slog.InfoContext(ctx, "orphans in the baseline are not reported", "count", n)
+	}
+	return kept
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestFindOrphans_baseline(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/baseline-test\ngo 1.21\n",
+		"main.go": `
+package main
+import "example.com/baseline-test/lib"
+func main() { lib.Used() }
+`,
+		"lib/lib.go": `
+package lib
+type T struct{}
+func Used() {}
+func (t *T) UnusedMethod() {}
+func UnusedFunc() {}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	runJSON := func(baseline string) []byte {
+		t.Helper()
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := run(context.Background(), runConfig{
+			All:           true,
+			Workspace:     dir,
+			JSON:          true,
+			Mode:          "auto",
+			StartPatterns: []string{"example.com/baseline-test/..."},
+			ExcludeDirs:   []string{"testdata", "vendor"},
+			Baseline:      baseline,
+		})
+		w.Close()
+		os.Stdout = oldStdout
+		if err != nil {
+			t.Fatalf("run() failed: %v", err)
+		}
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		return buf.Bytes()
+	}
+
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(baseline, runJSON(""), 0644); err != nil {
+		t.Fatalf("failed to write baseline: %v", err)
+	}
+
+	// Move the known orphans around and add a new one: only the new one is reported.
+	if err := os.WriteFile(filepath.Join(dir, "lib", "lib.go"), []byte(`
+package lib
+
+func UnusedFunc() {}
+
+func NewUnused() {}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "lib", "types.go"), []byte(`
+package lib
+
+type T struct{ name string }
+
+func Used() {}
+
+func (t T) UnusedMethod() {}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []Orphan
+	if err := json.Unmarshal(runJSON(baseline), &got); err != nil {
+		t.Fatalf("failed to unmarshal JSON output: %v", err)
+	}
+	var ids []string
+	for _, o := range got {
+		ids = append(ids, o.ID)
+	}
+	want := []string{"func:example.com/baseline-test/lib.NewUnused"}
+	if diff := cmp.Diff(want, ids); diff != "" {
+		t.Errorf("orphans mismatch (-want +got):\n%s", diff)
+	}
+}
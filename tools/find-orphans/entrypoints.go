@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// EntrypointConfig is the content of the file given by -entrypoints.
+// It declares the functions that are invoked by a framework (e.g. cobra commands, grpc services,
+// or wire providers), which are treated as additional roots of the analysis.
+type EntrypointConfig struct {
+	Entrypoints []EntrypointRule `json:"entrypoints"`
+}
+
+// EntrypointRule selects functions and methods as entry points.
+// The selectors which are set must all match.
+type EntrypointRule struct {
+	// Name is a regular expression matched against the full name of the function,
+	// e.g. "example.com/app/cmd.run.*" or "\(\*example.com/app/svc.Server\)\..*".
+	Name string `json:"name,omitempty"`
+	// Implements is the interface ("<pkg>.<Iface>") whose methods are entry points,
+	// for each type implementing it.
+	Implements string `json:"implements,omitempty"`
+	// Annotation is a text that must appear in the doc comment of the function, e.g. "+entrypoint".
+	Annotation string `json:"annotation,omitempty"`
+
+	name *regexp.Regexp
+}
+
+// loadEntrypointConfig reads the entry point configuration file.
+func loadEntrypointConfig(path string) (*EntrypointConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entrypoints config: %w", err)
+	}
+	var config EntrypointConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse entrypoints config %s: %w", path, err)
+	}
+	for i := range config.Entrypoints {
+		rule := &config.Entrypoints[i]
+		if rule.Name == "" && rule.Implements == "" && rule.Annotation == "" {
+			return nil, fmt.Errorf("entrypoints config %s: rule #%d has no selector", path, i)
+		}
+		if rule.Name != "" {
+			re, err := regexp.Compile(rule.Name)
+			if err != nil {
+				return nil, fmt.Errorf("entrypoints config %s: rule #%d has an invalid name pattern: %w", path, i, err)
+			}
+			rule.name = re
+		}
+	}
+	return &config, nil
+}
+
+// match reports whether the function is selected by the rule.
+// methodSets maps the interfaces of the rules to their method names, and implementers to the
+// struct types implementing them.
+func (r *EntrypointRule) match(pkg *scanner.PackageInfo, fn *scanner.FunctionInfo, methodSets map[string]map[string]bool, implementers map[string][]*scanner.TypeInfo) bool {
+	if r.name != nil && !r.name.MatchString(getFullName(pkg, fn)) {
+		return false
+	}
+	if r.Annotation != "" && !hasAnnotation(fn, r.Annotation) {
+		return false
+	}
+	if r.Implements != "" {
+		if fn.Receiver == nil || !methodSets[r.Implements][fn.Name] {
+			return false
+		}
+		recvName := fn.CanonicalName().TypeName
+		implemented := false
+		for _, impl := range implementers[r.Implements] {
+			if impl.PkgPath == pkg.ImportPath && impl.Name == recvName {
+				implemented = true
+				break
+			}
+		}
+		if !implemented {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAnnotation(fn *scanner.FunctionInfo, annotation string) bool {
+	if fn.AstDecl == nil || fn.AstDecl.Doc == nil {
+		return false
+	}
+	for _, comment := range fn.AstDecl.Doc.List {
+		if strings.Contains(comment.Text, annotation) {
+			return true
+		}
+	}
+	return false
+}
+
+// findConfiguredEntrypoints returns the functions and methods of the scanned packages
+// selected by the entry point configuration.
+func (a *analyzer) findConfiguredEntrypoints(ctx context.Context, implementersOf func(*scanner.TypeInfo) []*scanner.TypeInfo) []*configuredEntrypoint {
+	methodSets := make(map[string]map[string]bool)
+	implementers := make(map[string][]*scanner.TypeInfo)
+	for _, rule := range a.entrypoints.Entrypoints {
+		if rule.Implements == "" {
+			continue
+		}
+		if _, ok := methodSets[rule.Implements]; ok {
+			continue
+		}
+		methodSets[rule.Implements] = make(map[string]bool)
+		if iface := a.lookupInterface(ctx, rule.Implements); iface != nil {
+			methodSets[rule.Implements] = a.typeMethodSet(ctx, iface, make(map[string]bool))
+			implementers[rule.Implements] = implementersOf(iface)
+		}
+	}
+
+	var found []*configuredEntrypoint
+	for _, pkg := range a.packages {
+		for _, fn := range pkg.Functions {
+			for i := range a.entrypoints.Entrypoints {
+				if a.entrypoints.Entrypoints[i].match(pkg, fn, methodSets, implementers) {
+					found = append(found, &configuredEntrypoint{pkg: pkg, fn: fn})
+					break
+				}
+			}
+		}
+	}
+	slog.InfoContext(ctx, "found configured entry points", "count", len(found))
+	return found
+}
+
+type configuredEntrypoint struct {
+	pkg *scanner.PackageInfo
+	fn  *scanner.FunctionInfo
+}
+
+// lookupInterface returns the interface of the entrypoints config ("<pkg>.<Iface>") in the
+// walked packages, or nil.
+func (a *analyzer) lookupInterface(ctx context.Context, ifaceName string) *scanner.TypeInfo {
+	idx := strings.LastIndex(ifaceName, ".")
+	if idx < 0 {
+		return nil
+	}
+	pkg, ok := a.packages[ifaceName[:idx]]
+	if !ok {
+		slog.WarnContext(ctx, "the package of the interface in the entrypoints config is not scanned", "interface", ifaceName)
+		return nil
+	}
+	typeName := ifaceName[idx+1:]
+	for _, t := range pkg.Types {
+		if t.Name == typeName && t.Interface != nil {
+			return t
+		}
+	}
+	slog.WarnContext(ctx, "the interface in the entrypoints config is not found", "interface", ifaceName)
+	return nil
+}
+
+// typeMethodSet returns the names of the methods of the interface type, including the ones of
+// the embedded interfaces.
+func (a *analyzer) typeMethodSet(ctx context.Context, t *scanner.TypeInfo, visited map[string]bool) map[string]bool {
+	methods := make(map[string]bool)
+	ifaceName := t.PkgPath + "." + t.Name
+	if visited[ifaceName] || t.Interface == nil {
+		return methods
+	}
+	visited[ifaceName] = true
+	for _, m := range t.Interface.Methods {
+		methods[m.Name] = true
+	}
+	for _, embedded := range t.Interface.Embedded {
+		embeddedInfo, err := embedded.Resolve(ctx)
+		if err != nil || embeddedInfo == nil {
+			slog.DebugContext(ctx, "could not resolve embedded interface", "interface", ifaceName, "embedded", embedded.String(), "error", err)
+			continue
+		}
+		for name := range a.typeMethodSet(ctx, embeddedInfo, visited) {
+			methods[name] = true
+		}
+	}
+	return methods
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestFindOrphans_entrypoints(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/entrypoints-test\ngo 1.21\n",
+		"main.go": `
+package main
+import "example.com/entrypoints-test/svc"
+func main() { svc.Register() }
+`,
+		"svc/svc.go": `
+package svc
+
+type Greeter interface {
+	Named
+	Greet() string
+}
+
+type Named interface {
+	Name() string
+}
+
+type server struct{}
+
+func (s *server) Greet() string { return greeting() }
+func (s *server) Name() string { return "server" }
+func (s *server) unused() {}
+
+func greeting() string { return "hello" }
+
+func Register() {}
+
+func runServe() error { return serve() }
+func serve() error { return nil }
+
+// NewStore is called by the generated code.
+// +entrypoint
+func NewStore() {}
+
+func Unused() {}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	config := filepath.Join(t.TempDir(), "entrypoints.json")
+	if err := os.WriteFile(config, []byte(`{
+  "entrypoints": [
+    {"implements": "example.com/entrypoints-test/svc.Greeter"},
+    {"name": "^example\\.com/entrypoints-test/svc\\.run"},
+    {"annotation": "+entrypoint"}
+  ]
+}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		JSON:          true,
+		Mode:          "auto",
+		StartPatterns: []string{"example.com/entrypoints-test/..."},
+		ExcludeDirs:   []string{"testdata", "vendor"},
+		Entrypoints:   config,
+	})
+	w.Close()
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	var got []Orphan
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal JSON output: %v", err)
+	}
+	var names []string
+	for _, o := range got {
+		names = append(names, o.Name)
+	}
+	sort.Strings(names)
+	want := []string{
+		"(*example.com/entrypoints-test/svc.server).unused",
+		"example.com/entrypoints-test/svc.Unused",
+	}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("orphans mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadEntrypointConfig_invalid(t *testing.T) {
+	cases := map[string]string{
+		"no selector":  `{"entrypoints": [{}]}`,
+		"invalid name": `{"entrypoints": [{"name": "("}]}`,
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "entrypoints.json")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadEntrypointConfig(path); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/rewrite"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// fixConfig holds the options of -fix and -fix-dry-run.
+type fixConfig struct {
+	DryRun  bool // print the diffs instead of writing the files
+	Comment bool // comment out the orphans instead of deleting them
+}
+
+// fixMarker is the comment added above a commented out orphan, to find it later.
+const fixMarker = "find-orphans: orphan"
+
+// runFix removes the orphan functions and methods. Removing a function may orphan the functions
+// it calls, so the analysis is repeated on the rewritten sources until no new orphans are found.
+// The rewritten sources are given to the scanner of the next round as an overlay, and are only
+// written at the end, or printed as diffs with DryRun.
+func runFix(ctx context.Context, cfg *fixConfig, newScanner func(scanner.Overlay) (*goscan.Scanner, error), newAnalyzer func(*goscan.Scanner) *analyzer) error {
+	files := make(map[string]*rewrite.File) // by the absolute path
+	var paths []string
+	overlay := make(scanner.Overlay)
+	failed := make(map[string]bool) // IDs of the orphans which could not be removed
+
+	var removed []Orphan
+	lines, rounds := 0, 0
+	for {
+		s, err := newScanner(overlay)
+		if err != nil {
+			return err
+		}
+		orphans, _, _, _, _, err := newAnalyzer(s).findOrphans(ctx)
+		if err != nil {
+			return err
+		}
+
+		var targets []Orphan
+		for _, o := range orphans {
+			if o.decl != nil && !failed[o.ID] {
+				targets = append(targets, o)
+			}
+		}
+		if len(targets) == 0 {
+			break
+		}
+		rounds++
+		slog.InfoContext(ctx, "* fix orphans", "round", rounds, "count", len(targets))
+
+		for _, o := range targets {
+			decl := o.decl.AstDecl
+			path := s.Fset().Position(decl.Pos()).Filename
+			f, ok := files[path]
+			if !ok {
+				f, err = rewrite.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %w", path, err)
+				}
+				files[path] = f
+				paths = append(paths, path)
+			}
+
+			if err := removeOrphan(f, decl, cfg.Comment); err != nil {
+				slog.WarnContext(ctx, "failed to remove orphan", "name", o.Name, "error", err)
+				failed[o.ID] = true
+				continue
+			}
+			start := decl.Pos()
+			if decl.Doc != nil {
+				start = decl.Doc.Pos()
+			}
+			lines += s.Fset().Position(decl.End()).Line - s.Fset().Position(start).Line + 1
+			removed = append(removed, o)
+		}
+
+		for _, path := range paths {
+			rel, err := filepath.Rel(s.RootDir(), path)
+			if err != nil {
+				return fmt.Errorf("failed to make %s relative to the module root: %w", path, err)
+			}
+			overlay[rel] = files[path].Source()
+		}
+	}
+
+	changed := 0
+	for _, path := range paths {
+		f := files[path]
+		if !f.Changed() {
+			continue
+		}
+		changed++
+		if cfg.DryRun {
+			fmt.Print(f.Diff())
+			continue
+		}
+		if err := f.WriteFile(ctx); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	if len(removed) == 0 {
+		fmt.Println("No orphans found.")
+		return nil
+	}
+	verb := "deleted"
+	if cfg.Comment {
+		verb = "commented out"
+	}
+	if cfg.DryRun {
+		verb = "Would have " + verb
+	} else {
+		verb = strings.ToUpper(verb[:1]) + verb[1:]
+	}
+	fmt.Println("\n-- Fixed Orphans --")
+	for _, o := range removed {
+		fmt.Printf("%s\n  %s\n", o.Name, o.Position)
+	}
+	fmt.Printf("\n%s %d orphans (%d lines) in %d files, after %d rounds of analysis.\n", verb, len(removed), lines, changed, rounds)
+	return nil
+}
+
+// removeOrphan deletes or comments out the declaration of an orphan function or method, and then
+// deletes the imports which were only used by it.
+func removeOrphan(f *rewrite.File, decl *ast.FuncDecl, comment bool) error {
+	before := usedImports(f)
+
+	name := decl.Name.Name
+	if decl.Recv != nil && len(decl.Recv.List) > 0 {
+		name = receiverTypeName(decl.Recv.List[0].Type) + "." + name
+	}
+	var err error
+	if comment {
+		err = f.CommentOutDecl(name, fixMarker)
+	} else {
+		err = f.DeleteDecl(name)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, imp := range before {
+		if !f.UsesImport(imp.path) {
+			if err := f.DeleteImport(imp.name, imp.path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type importSpec struct {
+	name, path string
+}
+
+// usedImports returns the imports used in the file. The blank, dot and cgo imports are ignored,
+// as their usage cannot be seen.
+func usedImports(f *rewrite.File) []importSpec {
+	var used []importSpec
+	for _, spec := range f.AST.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path == "C" {
+			continue
+		}
+		var name string
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		if f.UsesImport(path) {
+			used = append(used, importSpec{name: name, path: path})
+		}
+	}
+	return used
+}
+
+// receiverTypeName returns the name of the type of a receiver, e.g. "T" for `*T` or `T[K]`.
+func receiverTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(e.X)
+	case *ast.ParenExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
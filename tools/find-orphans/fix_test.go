@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestFindOrphans_fix(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/fix\ngo 1.21\n",
+		"main.go": `package main
+
+import "example.com/fix/lib"
+
+func main() { lib.Run() }
+`,
+		"lib/lib.go": `package lib
+
+import (
+	"fmt"
+	"strings"
+)
+
+type T struct{}
+
+func Run() { fmt.Println("run") }
+
+// unused calls helper, which is not used from anywhere else.
+func unused() { helper() }
+
+func helper() { fmt.Println(strings.ToUpper("helper")) }
+
+func (t *T) unusedMethod() {}
+`,
+	}
+
+	runFixMode := func(t *testing.T, cfg *fixConfig) (string, string) {
+		t.Helper()
+		dir, cleanup := scantest.WriteFiles(t, files)
+		t.Cleanup(cleanup)
+
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := run(context.Background(), runConfig{
+			All:           true,
+			Workspace:     dir,
+			Mode:          "app",
+			StartPatterns: []string{"example.com/fix/..."},
+			ExcludeDirs:   []string{"testdata", "vendor"},
+			Fix:           cfg,
+		})
+		w.Close()
+		os.Stdout = oldStdout
+		if err != nil {
+			t.Fatalf("run() failed: %v", err)
+		}
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+
+		content, err := os.ReadFile(filepath.Join(dir, "lib", "lib.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf.String(), string(content)
+	}
+
+	t.Run("delete", func(t *testing.T) {
+		out, got := runFixMode(t, &fixConfig{})
+		want := `package lib
+
+import (
+	"fmt"
+)
+
+type T struct{}
+
+func Run() { fmt.Println("run") }
+`
+		if got != want {
+			t.Errorf("unexpected rewritten file:\n%s", got)
+		}
+		if !strings.Contains(out, "Deleted 3 orphans (4 lines) in 1 files, after 1 rounds of analysis.") {
+			t.Errorf("unexpected summary:\n%s", out)
+		}
+	})
+
+	t.Run("comment out", func(t *testing.T) {
+		_, got := runFixMode(t, &fixConfig{Comment: true})
+		for _, line := range []string{"// " + fixMarker, "// func unused() { helper() }", "// func (t *T) unusedMethod() {}"} {
+			if !strings.Contains(got, line) {
+				t.Errorf("%q is not found in the rewritten file:\n%s", line, got)
+			}
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		out, got := runFixMode(t, &fixConfig{DryRun: true})
+		if got != files["lib/lib.go"] {
+			t.Errorf("the file is rewritten with dry run:\n%s", got)
+		}
+		for _, line := range []string{"-func unused() { helper() }", "-\t\"strings\"", "Would have deleted 3 orphans"} {
+			if !strings.Contains(out, line) {
+				t.Errorf("%q is not found in the output:\n%s", line, out)
+			}
+		}
+	})
+}
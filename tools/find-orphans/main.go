@@ -1,15 +1,15 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"go/ast"
-	"go/printer"
+	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
@@ -31,25 +31,57 @@ func main() {
 		verbose              = flag.Bool("v", false, "enable verbose output")
 		asJSON               = flag.Bool("json", false, "output orphans in JSON format")
 		debug                = flag.Bool("debug", false, "enable debug output")
-		mode                 = flag.String("mode", "auto", "analysis mode: auto, app, or lib")
+		mode                 = flag.String("mode", "auto", "analysis mode: auto, app, lib, or public-api")
+		members              = flag.Bool("members", false, "also report unused struct fields and interface methods")
+		baseline             = flag.String("baseline", "", "JSON output of a previous run; the orphans listed in it are not reported")
+		entrypoints          = flag.String("entrypoints", "", "JSON file declaring additional entry points, e.g. the functions invoked by frameworks")
+		testOnly             = flag.Bool("test-only", false, "also report the functions used only from tests (requires -include-tests)")
+		reflectAllMethods    = flag.Bool("reflect-all-methods", false, "treat all exported methods of a type as used when a method is looked up by a non-constant name through reflection")
+		fix                  = flag.Bool("fix", false, "delete the orphan functions and methods, repeating the analysis until no new orphans are found")
+		fixDryRun            = flag.Bool("fix-dry-run", false, "like -fix, but print the diffs instead of writing the files")
+		fixComment           = flag.Bool("fix-comment", false, "with -fix or -fix-dry-run, comment out the orphans instead of deleting them")
+		heuristicStrings     = flag.Bool("heuristic-strings", false, "report the orphans whose exported name appears in a string literal (e.g. a template FuncMap or an RPC name) separately, as referenced by string")
+		ifaceSatisfaction    = flag.Bool("interface-satisfaction", false, "treat the methods of a type as used when its value is passed as an interface whose methods are called, or which is declared out of the scan scope (e.g. an http.Handler passed to a framework), and report them separately")
 		excludeDirs          stringSliceFlag
 		primaryAnalysisScope stringSliceFlag
 		entrypointPkgs       stringSliceFlag
+		publicAPIPkgs        stringSliceFlag
 	)
-	flag.Var(&excludeDirs, "exclude-dirs", "comma-separated list of directories to exclude (e.g. testdata,vendor)")
+	flag.Var(&excludeDirs, "exclude-dirs", "comma-separated list of directories to exclude (default: exclude-dirs of .goscan.yaml, or testdata,vendor)")
 	flag.Var(&primaryAnalysisScope, "primary-analysis-scope", "comma-separated list of package patterns to define the primary analysis scope (for debugging purposes)")
 	flag.Var(&entrypointPkgs, "entrypoint-pkg", "comma-separated list of main packages to use as entry points in app mode")
+	flag.Var(&publicAPIPkgs, "public-api-pkg", "comma-separated list of package patterns (e.g. example.com/lib/...) forming the public API in public-api mode, in addition to the packages marked with //go:scan:public-api")
+	var heuristicStringsFiles stringSliceFlag
+	flag.Var(&heuristicStringsFiles, "heuristic-strings-files", "comma-separated list of glob patterns of the non-Go files to search with -heuristic-strings (e.g. *.tmpl,*.html); implies -heuristic-strings")
 	flag.Parse()
 
 	// Validate mode
 	switch *mode {
-	case "auto", "app", "lib":
+	case "auto", "app", "lib", "public-api":
 		// valid
 	default:
 		slog.Error("invalid mode specified", "mode", *mode)
 		os.Exit(1)
 	}
 
+	// The settings of .goscan.yaml are the defaults of the flags.
+	root := *workspace
+	if root == "" {
+		root = "."
+	}
+	cfg, err := goscan.FindConfigFile(root)
+	if err != nil {
+		slog.Error("invalid config file", "error", err)
+		os.Exit(1)
+	}
+	if len(excludeDirs) == 0 && cfg != nil {
+		excludeDirs = cfg.ExcludeDirs
+	}
+	var scanPolicy symgo.ScanPolicyFunc
+	if cfg != nil && len(cfg.ScanPolicy) > 0 {
+		scanPolicy = cfg.InScanPolicy
+	}
+
 	// Set default exclude directories
 	if len(excludeDirs) == 0 {
 		excludeDirs = []string{"testdata", "vendor"}
@@ -60,8 +92,40 @@ func main() {
 		startPatterns = []string{"./..."}
 	}
 
+	var fixCfg *fixConfig
+	if *fix || *fixDryRun {
+		fixCfg = &fixConfig{DryRun: *fixDryRun, Comment: *fixComment}
+	}
+
+	var stringRefsCfg *stringRefsConfig
+	if *heuristicStrings || len(heuristicStringsFiles) > 0 {
+		stringRefsCfg = &stringRefsConfig{Files: heuristicStringsFiles}
+	}
+
 	ctx := context.Background()
-	if err := run(ctx, *debug, *all, *includeTests, *workspace, *verbose, *asJSON, *mode, startPatterns, excludeDirs, nil, primaryAnalysisScope, entrypointPkgs); err != nil {
+	if err := run(ctx, runConfig{
+		Debug:                 *debug,
+		All:                   *all,
+		IncludeTests:          *includeTests,
+		Workspace:             *workspace,
+		Verbose:               *verbose,
+		JSON:                  *asJSON,
+		Mode:                  *mode,
+		StartPatterns:         startPatterns,
+		ExcludeDirs:           excludeDirs,
+		ScanPolicy:            scanPolicy,
+		PrimaryAnalysisScope:  primaryAnalysisScope,
+		EntrypointPkgs:        entrypointPkgs,
+		PublicAPIPkgs:         publicAPIPkgs,
+		Members:               *members,
+		Baseline:              *baseline,
+		Entrypoints:           *entrypoints,
+		TestOnly:              *testOnly,
+		ReflectAllMethods:     *reflectAllMethods,
+		Fix:                   fixCfg,
+		StringRefs:            stringRefsCfg,
+		InterfaceSatisfaction: *ifaceSatisfaction,
+	}); err != nil {
 		slog.ErrorContext(ctx, "toplevel", "error", err)
 		os.Exit(1)
 	}
@@ -140,17 +204,44 @@ func discoverModules(ctx context.Context, root string, excludeDirs []string) ([]
 	return modules, nil
 }
 
-func run(ctx context.Context, debug bool, all bool, includeTests bool, workspace string, verbose bool, asJSON bool, mode string, startPatterns []string, excludeDirs []string, scanPolicy symgo.ScanPolicyFunc, primaryAnalysisScope []string, entrypointPkgs []string) error {
+// runConfig holds the settings of a run, mostly from the command-line flags of the same names.
+type runConfig struct {
+	Debug        bool
+	All          bool
+	IncludeTests bool
+	Workspace    string // -workspace-root
+	Verbose      bool
+	JSON         bool
+	Mode         string
+
+	StartPatterns        []string // the packages to report the orphans of
+	ExcludeDirs          []string
+	ScanPolicy           symgo.ScanPolicyFunc // nil for the packages of the workspace modules
+	PrimaryAnalysisScope []string
+	EntrypointPkgs       []string
+	PublicAPIPkgs        []string
+
+	Members               bool
+	Baseline              string
+	Entrypoints           string
+	TestOnly              bool
+	ReflectAllMethods     bool
+	Fix                   *fixConfig        // nil without -fix or -fix-dry-run
+	StringRefs            *stringRefsConfig // nil without -heuristic-strings
+	InterfaceSatisfaction bool
+}
+
+func run(ctx context.Context, cfg runConfig) error {
 	logLevel := new(slog.LevelVar)
-	if debug {
+	if cfg.Debug {
 		logLevel.Set(slog.LevelDebug)
-	} else if verbose {
+	} else if cfg.Verbose {
 		logLevel.Set(slog.LevelInfo)
 	} else {
 		logLevel.Set(slog.LevelWarn)
 	}
 	opts := &slog.HandlerOptions{
-		AddSource: verbose && false, // for debug
+		AddSource: cfg.Verbose && false, // for debug
 		Level:     logLevel,
 	}
 	logger := slog.New(slog.NewTextHandler(os.Stderr, opts))
@@ -162,21 +253,21 @@ func run(ctx context.Context, debug bool, all bool, includeTests bool, workspace
 	var resolutionDir string
 
 	locatorOpts := []locator.Option{locator.WithGoModuleResolver()}
-	if workspace != "" {
+	if cfg.Workspace != "" {
 		var err error
-		absWorkspace, err := filepath.Abs(workspace)
+		absWorkspace, err := filepath.Abs(cfg.Workspace)
 		if err != nil {
-			return fmt.Errorf("could not get absolute path for workspace root %q: %w", workspace, err)
+			return fmt.Errorf("could not get absolute path for workspace root %q: %w", cfg.Workspace, err)
 		}
-		workspace = absWorkspace
-		resolutionDir = workspace
+		cfg.Workspace = absWorkspace
+		resolutionDir = cfg.Workspace
 
-		moduleDirs, err = discoverModules(ctx, workspace, excludeDirs)
+		moduleDirs, err = discoverModules(ctx, cfg.Workspace, cfg.ExcludeDirs)
 		if err != nil {
 			return err
 		}
 		if len(moduleDirs) == 0 {
-			return fmt.Errorf("no go.mod files found in workspace root %s", workspace)
+			return fmt.Errorf("no go.mod files found in workspace root %s", cfg.Workspace)
 		}
 		logger.DebugContext(ctx, "creating locators for workspace", "count", len(moduleDirs), "modules", moduleDirs)
 		for _, dir := range moduleDirs {
@@ -204,7 +295,7 @@ func run(ctx context.Context, debug bool, all bool, includeTests bool, workspace
 	}
 
 	// Resolve the target packages for reporting. This is always from the positional args.
-	targetPackages, err := resolveTargetPackages(ctx, locators, startPatterns, excludeDirs, resolutionDir)
+	targetPackages, err := resolveTargetPackages(ctx, locators, cfg.StartPatterns, cfg.ExcludeDirs, resolutionDir)
 	if err != nil {
 		return fmt.Errorf("could not resolve target packages: %w", err)
 	}
@@ -213,42 +304,47 @@ func run(ctx context.Context, debug bool, all bool, includeTests bool, workspace
 	// Resolve all packages for scanning (the analysis scope).
 	// This is defined by --primary-analysis-scope if provided, otherwise it's the whole workspace.
 	scanPatterns := []string{"./..."}
-	if len(primaryAnalysisScope) > 0 {
-		scanPatterns = primaryAnalysisScope
+	if len(cfg.PrimaryAnalysisScope) > 0 {
+		scanPatterns = cfg.PrimaryAnalysisScope
 	}
-	scanPackages, err := resolveTargetPackages(ctx, locators, scanPatterns, excludeDirs, resolutionDir)
+	scanPackages, err := resolveTargetPackages(ctx, locators, scanPatterns, cfg.ExcludeDirs, resolutionDir)
 	if err != nil {
 		return fmt.Errorf("could not resolve scan packages: %w", err)
 	}
 	logger.InfoContext(ctx, "* resolved scan packages for analysis", "count", len(scanPackages))
 
-	// Now create the main scanner
-	var scannerOpts []goscan.ScannerOption
-	scannerOpts = append(scannerOpts, goscan.WithIncludeTests(includeTests))
-	scannerOpts = append(scannerOpts, goscan.WithGoModuleResolver())
-	scannerOpts = append(scannerOpts, goscan.WithLogger(logger))
-
-	if workspace != "" {
-		scannerOpts = append(scannerOpts, goscan.WithModuleDirs(moduleDirs))
-	} else {
-		// In single-module mode, the resolutionDir is the workDir.
-		scannerOpts = append(scannerOpts, goscan.WithWorkDir(resolutionDir))
-	}
+	// Now create the main scanner. With -fix, a new one is created for each round of the analysis,
+	// reading the rewritten files from the overlay.
+	newScanner := func(overlay scanner.Overlay) (*goscan.Scanner, error) {
+		var scannerOpts []goscan.ScannerOption
+		scannerOpts = append(scannerOpts, goscan.WithIncludeTests(cfg.IncludeTests))
+		scannerOpts = append(scannerOpts, goscan.WithGoModuleResolver())
+		scannerOpts = append(scannerOpts, goscan.WithLogger(logger))
+		scannerOpts = append(scannerOpts, goscan.WithOverlay(overlay))
+
+		if cfg.Workspace != "" {
+			scannerOpts = append(scannerOpts, goscan.WithModuleDirs(moduleDirs))
+		} else {
+			// In single-module mode, the resolutionDir is the workDir.
+			scannerOpts = append(scannerOpts, goscan.WithWorkDir(resolutionDir))
+		}
 
-	s, err := goscan.New(scannerOpts...)
-	if err != nil {
-		return fmt.Errorf("failed to create scanner: %w", err)
+		s, err := goscan.New(scannerOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create scanner: %w", err)
+		}
+		return s, nil
 	}
 
 	// Define the scan policy if one is not provided.
 	// The policy is to scan packages within the workspace modules, but not the standard library or other external dependencies.
-	if scanPolicy == nil {
+	if cfg.ScanPolicy == nil {
 		modulePaths := make([]string, len(locators))
 		for i, loc := range locators {
 			modulePaths[i] = loc.ModulePath()
 		}
 
-		scanPolicy = func(pkgPath string) bool {
+		cfg.ScanPolicy = func(pkgPath string) bool {
 			// Heuristic: stdlib packages don't have a dot in their first component.
 			if !strings.Contains(pkgPath, ".") {
 				slog.DebugContext(ctx, "scan policy: skipping stdlib package", "package", pkgPath)
@@ -256,7 +352,7 @@ func run(ctx context.Context, debug bool, all bool, includeTests bool, workspace
 			}
 			// Check if the package belongs to any of the workspace modules.
 			for _, modPath := range modulePaths {
-				if strings.HasPrefix(pkgPath, modPath) {
+				if pkgPath == modPath || strings.HasPrefix(pkgPath, modPath+"/") {
 					slog.DebugContext(ctx, "scan policy: scanning workspace package", "package", pkgPath)
 					return true
 				}
@@ -266,18 +362,57 @@ func run(ctx context.Context, debug bool, all bool, includeTests bool, workspace
 		}
 	}
 
-	a := &analyzer{
-		s:                    s,
-		packages:             make(map[string]*scanner.PackageInfo),
-		targetPackages:       targetPackages,
-		mode:                 mode,
-		scanPackages:         scanPackages,
-		includeTests:         includeTests,
-		scanPolicy:           scanPolicy,
-		primaryAnalysisScope: primaryAnalysisScope,
-		entrypointPkgs:       entrypointPkgs,
+	var known map[string]bool
+	if cfg.Baseline != "" {
+		known, err = loadBaseline(cfg.Baseline)
+		if err != nil {
+			return err
+		}
+	}
+
+	var entrypointConfig *EntrypointConfig
+	if cfg.TestOnly && !cfg.IncludeTests {
+		return fmt.Errorf("-test-only requires -include-tests")
+	}
+	if cfg.Entrypoints != "" {
+		entrypointConfig, err = loadEntrypointConfig(cfg.Entrypoints)
+		if err != nil {
+			return err
+		}
+	}
+
+	newAnalyzer := func(s *goscan.Scanner) *analyzer {
+		return &analyzer{
+			s:                    s,
+			packages:             make(map[string]*scanner.PackageInfo),
+			targetPackages:       targetPackages,
+			mode:                 cfg.Mode,
+			scanPackages:         scanPackages,
+			includeTests:         cfg.IncludeTests,
+			scanPolicy:           cfg.ScanPolicy,
+			primaryAnalysisScope: cfg.PrimaryAnalysisScope,
+			entrypointPkgs:       cfg.EntrypointPkgs,
+			publicAPIPkgs:        cfg.PublicAPIPkgs,
+			members:              cfg.Members,
+			baseline:             known,
+			entrypoints:          entrypointConfig,
+			testOnly:             cfg.TestOnly,
+			reflectAllMethods:    cfg.ReflectAllMethods,
+			stringRefs:           cfg.StringRefs,
+			ifaceSatisfaction:    cfg.InterfaceSatisfaction,
+			excludeDirs:          cfg.ExcludeDirs,
+			verbose:              cfg.Verbose,
+		}
+	}
+
+	if cfg.Fix != nil {
+		return runFix(ctx, cfg.Fix, newScanner, newAnalyzer)
+	}
+	s, err := newScanner(nil)
+	if err != nil {
+		return err
 	}
-	return a.analyze(ctx, asJSON)
+	return newAnalyzer(s).analyze(ctx, cfg.JSON)
 }
 
 // resolveTargetPackages converts user-provided patterns (including file paths and import paths)
@@ -368,7 +503,7 @@ func resolveTargetPackages(ctx context.Context, locators []*locator.Locator, pat
 			var rootDir string
 			var found bool
 			for _, loc := range locators {
-				dir, err := loc.FindPackageDir(cleanPattern)
+				dir, err := loc.FindPackageDirContext(ctx, cleanPattern)
 				if err == nil {
 					rootDir = dir
 					found = true
@@ -436,11 +571,178 @@ type analyzer struct {
 	scanPolicy           symgo.ScanPolicyFunc
 	primaryAnalysisScope []string
 	entrypointPkgs       []string
+	publicAPIPkgs        []string // the package patterns of the public API, in public-api mode
+	members              bool
+	baseline             map[string]bool // IDs of the known orphans, which are not reported
+	entrypoints          *EntrypointConfig
+	testOnly             bool              // report the functions used only from tests
+	reflectAllMethods    bool              // treat all exported methods as used for the reflective lookups by non-constant names
+	stringRefs           *stringRefsConfig // nil unless -heuristic-strings
+	ifaceSatisfaction    bool              // treat the methods of the types passed as interfaces as used
+	excludeDirs          []string
+	symbolIDs            *goscan.SymbolIDs
 	mu                   sync.Mutex
 	ctx                  context.Context
+
+	// calledInterfaceMethods records the method calls on interface values, as "<pkg>.<Iface>.<Method>".
+	calledInterfaceMethods map[string]bool
+	// accessedFields records the struct fields read or written in the evaluated code, as
+	// "<pkg>.<Type>.<Field>" with the struct declaring the field (with -members).
+	accessedFields map[string]bool
+	// unresolvedCalls are the call sites whose results are unknown to the analysis, so that the
+	// functions called through these results may be reported as orphans.
+	unresolvedCalls []symgo.UnresolvedCall
+	// abortedFunctions are the functions whose evaluation was aborted by an error, so that the
+	// functions called only from the rest of their bodies may be reported as orphans.
+	abortedFunctions []symgo.FunctionCoverage
+	verbose          bool // list the unresolved calls and the aborted functions
+	// interfaceUses are the values of the types of the scan packages passed as interfaces, keyed
+	// by interfaceUse.key, with -interface-satisfaction.
+	interfaceUses     map[string]interfaceUse
+	interfaceUsesDone map[string]bool // the pairs of a use and a method analyzed, see satisfyingMethods
+}
+
+// Orphan is an unused function or method, or with -members, an unused struct field or interface method.
+type Orphan struct {
+	// ID is the stable identity of the orphan (see goscan.SymbolID), used to match baselines.
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Position string `json:"position"`
+	Package  string `json:"package"`
+	// Kind is empty for functions and methods, and "field" or "interface-method" for members.
+	Kind string `json:"kind,omitempty"`
+	// Exported is true for an exported function or method, which is dead API unless it is used
+	// from outside of the scan scope.
+	Exported bool `json:"exported,omitempty"`
+	// UsedOnlyInTests is true for a function which is used, but only from tests (with -test-only).
+	UsedOnlyInTests bool `json:"usedOnlyInTests,omitempty"`
+	// ReferencedBy is the position of the first string mentioning the name of an orphan (with
+	// -heuristic-strings), which is likely used through a string-based registration.
+	ReferencedBy string `json:"referencedBy,omitempty"`
+	// UsedVia is the interface through which a method is used (with -interface-satisfaction),
+	// and AssignedAt the position of the call where a value of its type is passed as the interface.
+	UsedVia    string `json:"usedVia,omitempty"`
+	AssignedAt string `json:"assignedAt,omitempty"`
+
+	decl *scanner.FunctionInfo // the declaration of an orphan function or method, used by -fix
 }
 
 func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
+	orphans, unusedMembers, usedOnlyInTests, referencedByString, usedViaInterface, err := a.findOrphans(ctx)
+	if err != nil {
+		return err
+	}
+	a.reportUnresolvedCalls(os.Stderr)
+	a.reportAbortedFunctions(os.Stderr)
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		all := append(append(append(append(orphans, unusedMembers...), usedOnlyInTests...), referencedByString...), usedViaInterface...)
+		if err := encoder.Encode(all); err != nil {
+			return fmt.Errorf("failed to encode orphans to JSON: %w", err)
+		}
+	} else {
+		if len(orphans) == 0 && len(unusedMembers) == 0 && len(usedOnlyInTests) == 0 && len(referencedByString) == 0 && len(usedViaInterface) == 0 {
+			fmt.Println("No orphans found.")
+			return nil
+		}
+		var internalOrphans, exportedOrphans []Orphan
+		for _, o := range orphans {
+			if o.Exported {
+				exportedOrphans = append(exportedOrphans, o)
+			} else {
+				internalOrphans = append(internalOrphans, o)
+			}
+		}
+		if len(internalOrphans) > 0 {
+			fmt.Println("\n-- Orphans --")
+			for _, o := range internalOrphans {
+				fmt.Printf("%s\n  %s\n", o.Name, o.Position)
+			}
+		}
+		if len(exportedOrphans) > 0 {
+			fmt.Println("\n-- Exported Orphans --")
+			for _, o := range exportedOrphans {
+				fmt.Printf("%s\n  %s\n", o.Name, o.Position)
+			}
+		}
+		if len(unusedMembers) > 0 {
+			fmt.Println("\n-- Unused Members --")
+			for _, m := range unusedMembers {
+				fmt.Printf("%s [%s]\n  %s\n", m.Name, m.Kind, m.Position)
+			}
+		}
+		if len(usedOnlyInTests) > 0 {
+			fmt.Println("\n-- Used Only From Tests --")
+			for _, o := range usedOnlyInTests {
+				fmt.Printf("%s\n  %s\n", o.Name, o.Position)
+			}
+		}
+		if len(referencedByString) > 0 {
+			fmt.Println("\n-- Referenced By String --")
+			for _, o := range referencedByString {
+				fmt.Printf("%s\n  %s\n  referenced at %s\n", o.Name, o.Position, o.ReferencedBy)
+			}
+		}
+		if len(usedViaInterface) > 0 {
+			fmt.Println("\n-- Used Via Interface --")
+			for _, o := range usedViaInterface {
+				fmt.Printf("%s\n  %s\n  passed as %s at %s\n", o.Name, o.Position, o.UsedVia, o.AssignedAt)
+			}
+		}
+	}
+
+	return nil
+}
+
+// reportUnresolvedCalls writes the number of unresolved call sites by reason, and with -v
+// the call sites, so that users know how much the orphans can be trusted.
+func (a *analyzer) reportUnresolvedCalls(w io.Writer) {
+	if len(a.unresolvedCalls) == 0 {
+		return
+	}
+	counts := make(map[symgo.UnresolvedReason]int)
+	for _, c := range a.unresolvedCalls {
+		counts[c.Reason]++
+	}
+	var parts []string
+	for _, reason := range []symgo.UnresolvedReason{symgo.UnresolvedOutOfPolicy, symgo.UnresolvedUnsupported, symgo.UnresolvedInferenceFailure} {
+		if counts[reason] > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", reason, counts[reason]))
+		}
+	}
+	fmt.Fprintf(w, "%d call sites could not be resolved (%s); the functions called only through their results may be reported as orphans.\n", len(a.unresolvedCalls), strings.Join(parts, ", "))
+	if !a.verbose {
+		fmt.Fprintln(w, "Run with -v to list them.")
+		return
+	}
+	for _, c := range a.unresolvedCalls {
+		fmt.Fprintf(w, "  %s: %s [%s] %s\n", c.Pos, c.Callee, c.Reason, c.Detail)
+	}
+}
+
+// reportAbortedFunctions writes the number of functions whose evaluation was aborted by an error,
+// and with -v the functions with the explored parts of their bodies.
+func (a *analyzer) reportAbortedFunctions(w io.Writer) {
+	if len(a.abortedFunctions) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%d functions were only partially explored, their evaluation being aborted by an error; the functions called only from their unexplored statements may be reported as orphans.\n", len(a.abortedFunctions))
+	if !a.verbose {
+		fmt.Fprintln(w, "Run with -v to list them.")
+		return
+	}
+	for _, c := range a.abortedFunctions {
+		fmt.Fprintf(w, "  %s: %s (statements: %d/%d, branches: %d/%d) %s\n", c.Pos, c.Function, c.CoveredStatements, c.Statements, c.CoveredBranches, c.Branches, c.Aborted)
+	}
+}
+
+// findOrphans runs the analysis, and returns the orphan functions and methods, the unused members
+// (with -members), the functions used only from tests (with -test-only), the orphans and
+// unused members whose name appears in a string (with -heuristic-strings), and the methods used
+// only through an interface their type is passed as (with -interface-satisfaction).
+func (a *analyzer) findOrphans(ctx context.Context) (orphans, unusedMembers, usedOnlyInTests, referencedByString, usedViaInterface []Orphan, err error) {
 	a.ctx = ctx
 
 	// Walk all dependencies, starting from the scan packages to find all potential usages.
@@ -448,13 +750,10 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 
 	slog.DebugContext(ctx, "walking with patterns", "patterns", patternsToWalk)
 	if err := a.s.Walker.Walk(ctx, a, patternsToWalk...); err != nil {
-		return fmt.Errorf("failed to walk packages: %w", err)
+		return nil, nil, nil, nil, nil, fmt.Errorf("failed to walk packages: %w", err)
 	}
 	slog.InfoContext(ctx, "analysis phase", "packages", len(a.packages))
 
-	interfaceMap := buildInterfaceMap(a.ctx, a.s, a.packages)
-	slog.DebugContext(ctx, "built interface map", "interfaces", len(interfaceMap))
-
 	// Use the user-provided primary analysis scope if available, otherwise default to all scanned packages.
 	analysisScopePatterns := a.primaryAnalysisScope
 	if len(analysisScopePatterns) == 0 {
@@ -465,17 +764,39 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 		symgo.WithLogger(slog.Default()),
 		symgo.WithPrimaryAnalysisScope(analysisScopePatterns...),
 		symgo.WithMemoization(true), // Enable memoization for performance
+		symgo.WithReflectAllMethods(a.reflectAllMethods),
+		symgo.WithCoverage(true), // To report the functions whose evaluation was aborted
 	}
 	if a.scanPolicy != nil {
 		interpreterOptions = append(interpreterOptions, symgo.WithScanPolicy(a.scanPolicy))
 	}
+	if a.members {
+		a.accessedFields = make(map[string]bool)
+		interpreterOptions = append(interpreterOptions, symgo.WithTracer(&symgo.TraceHooks{
+			OnFieldAccessed: func(ev symgo.TraceEvent) {
+				a.accessedFields[ev.Type.PkgPath+"."+ev.Type.Name+"."+ev.Field.Name] = true
+			},
+		}))
+	}
 
 	interp, err := symgo.NewInterpreter(
 		a.s,
 		interpreterOptions...,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create interpreter: %w", err)
+		return nil, nil, nil, nil, nil, fmt.Errorf("failed to create interpreter: %w", err)
+	}
+
+	// implementersOf returns the struct types of the walked packages implementing the interface,
+	// looked up on demand in the index shared with the interpreter.
+	implementersOf := func(iface *scanner.TypeInfo) []*scanner.TypeInfo {
+		var found []*scanner.TypeInfo
+		for _, t := range interp.ImplementersOf(ctx, iface) {
+			if _, ok := a.packages[t.PkgPath]; ok {
+				found = append(found, t)
+			}
+		}
+		return found
 	}
 
 	usageMap := make(map[string]bool)
@@ -483,7 +804,6 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 	// markUsage is a helper function to mark a function/method as used.
 	// It's designed to be called on any object, and it will figure out if it's a function.
 	markUsage := func(obj object.Object) {
-		var fullName string
 		switch fn := obj.(type) {
 		case *object.Function:
 			if fn.Package != nil && fn.Name != nil {
@@ -491,20 +811,7 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 					return // Don't track usage for functions outside the scan scope.
 				}
 
-				if fn.Decl.Recv != nil && len(fn.Decl.Recv.List) > 0 {
-					var buf bytes.Buffer
-					printer.Fprint(&buf, a.s.Fset(), fn.Decl.Recv.List[0].Type)
-					fullName = fmt.Sprintf("(%s.%s).%s", fn.Package.ImportPath, buf.String(), fn.Name.Name)
-					usageMap[fullName] = true
-
-					if recvTypeStr := buf.String(); len(recvTypeStr) > 0 && recvTypeStr[0] == '*' {
-						valueRecvName := fmt.Sprintf("(%s.%s).%s", fn.Package.ImportPath, recvTypeStr[1:], fn.Name.Name)
-						usageMap[valueRecvName] = true
-					}
-				} else {
-					fullName = fmt.Sprintf("%s.%s", fn.Package.ImportPath, fn.Name.Name)
-					usageMap[fullName] = true
-				}
+				markMethodUsage(usageMap, getCanonicalName(fn.Package, &scanner.FunctionInfo{Name: fn.Name.Name, AstDecl: fn.Decl}))
 			}
 		case *object.SymbolicPlaceholder:
 			// For symbolic placeholders, we also check if they belong to a scanned package.
@@ -515,25 +822,28 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 			}
 
 			if fn.UnderlyingFunc != nil {
-				// Case 1: It's an interface method call placeholder (it has a receiver).
+				// Case 1: It's a method call on a value of an unresolved type (e.g. out of the scan
+				// policy), matched with the method declared in its package.
+				if fn.Receiver != nil && fn.UnderlyingFunc.Receiver != nil && fn.Package != nil {
+					markMethodUsage(usageMap, getCanonicalName(fn.Package, fn.UnderlyingFunc))
+					return
+				}
+				// Case 2: It's an interface method call placeholder (it has a receiver).
 				if fn.Receiver != nil {
 					methodName := fn.UnderlyingFunc.Name
 					var implementerTypes []*scanner.FieldType
 					if fn.Receiver != nil {
 						receiverTypeInfo := fn.Receiver.TypeInfo()
 						if receiverTypeInfo != nil && receiverTypeInfo.Kind == scanner.InterfaceKind {
-							ifaceName := fmt.Sprintf("%s.%s", receiverTypeInfo.PkgPath, receiverTypeInfo.Name)
-							if allImplementers, ok := interfaceMap[ifaceName]; ok {
-								for _, ti := range allImplementers {
-									implementerTypes = append(implementerTypes, &scanner.FieldType{Definition: ti})
-								}
+							for _, ti := range implementersOf(receiverTypeInfo) {
+								implementerTypes = append(implementerTypes, &scanner.FieldType{Definition: ti})
 							}
 						}
 					}
 					for _, implFt := range implementerTypes {
 						a.markMethodAsUsed(ctx, usageMap, implFt, methodName)
 					}
-				} else { // Case 2: It's a regular function placeholder (no receiver).
+				} else { // Case 3: It's a regular function placeholder (no receiver).
 					if fn.Package != nil {
 						fullName := fmt.Sprintf("%s.%s", fn.Package.ImportPath, fn.UnderlyingFunc.Name)
 						usageMap[fullName] = true
@@ -551,6 +861,9 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 		for _, arg := range args {
 			markUsage(arg)
 		}
+		if a.ifaceSatisfaction {
+			a.recordInterfaceUses(ctx, args)
+		}
 		return nil
 	})
 
@@ -558,20 +871,24 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 	// First, find all potential entry points.
 	var mainEntryPoints []*object.Function
 	var libraryEntryPoints []*object.Function
+	var testEntryPoints []*object.Function
+	var publicAPIEntryPoints []*object.Function // with the init functions, in public-api mode
 
 	for _, pkg := range a.packages {
 		slog.InfoContext(ctx, "** scan package", "package", pkg.ImportPath)
 
-		// Load all files in the package to define all symbols in the interpreter's env
-		for _, fileAst := range pkg.AstFiles {
-			if _, err := interp.Eval(ctx, fileAst, pkg); err != nil {
-				slog.WarnContext(ctx, "could not load package", "package", pkg.ImportPath, "error", err)
-				break // if one file fails, probably best to skip the whole pkg
-			}
+		// Define all symbols of the package in the interpreter's env.
+		if err := interp.LoadPackage(ctx, pkg); err != nil {
+			slog.WarnContext(ctx, "could not load package", "package", pkg.ImportPath, "error", err)
+			continue
 		}
 
 		for _, fnInfo := range pkg.Functions {
-			funcObj, ok := interp.FindObjectInPackage(ctx, pkg.ImportPath, fnInfo.Name)
+			lookupName := fnInfo.Name
+			if fnInfo.Receiver != nil {
+				lookupName = getCanonicalName(pkg, fnInfo).String()
+			}
+			funcObj, ok := interp.FindObjectInPackage(ctx, pkg.ImportPath, lookupName)
 			if !ok {
 				slog.DebugContext(ctx, "could not find function object in interpreter", "function", fnInfo.Name, "package", pkg.ImportPath)
 				continue
@@ -593,6 +910,12 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 			if isExported || isInit {
 				libraryEntryPoints = append(libraryEntryPoints, fn)
 			}
+			if isInit || (isExported && a.mode == "public-api" && a.isPublicAPIPackage(pkg)) {
+				publicAPIEntryPoints = append(publicAPIEntryPoints, fn)
+			}
+			if fnInfo.IsTest && fnInfo.Receiver == nil && isTestEntryName(fnInfo.Name) {
+				testEntryPoints = append(testEntryPoints, fn)
+			}
 		}
 	}
 
@@ -617,14 +940,15 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 	// initial usage marks.
 	var analysisFns []*object.Function
 	isAppMode := false
+	isPublicAPIMode := false
 
 	switch a.mode {
 	case "app":
 		if len(mainEntryPoints) == 0 {
 			if len(a.entrypointPkgs) > 0 {
-				return fmt.Errorf("application mode specified with --entrypoint-pkg, but no main entry point was found in the specified packages: %v", a.entrypointPkgs)
+				return nil, nil, nil, nil, nil, fmt.Errorf("application mode specified with --entrypoint-pkg, but no main entry point was found in the specified packages: %v", a.entrypointPkgs)
 			}
-			return fmt.Errorf("application mode specified, but no main entry point was found")
+			return nil, nil, nil, nil, nil, fmt.Errorf("application mode specified, but no main entry point was found")
 		}
 		analysisFns = mainEntryPoints
 		isAppMode = true
@@ -637,6 +961,15 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 		}
 		isAppMode = false // Explicitly false for library mode
 		slog.InfoContext(ctx, "running in forced library mode", "analysis_functions", len(analysisFns))
+	case "public-api":
+		// Only the exported functions of the public packages are entry points, so that the
+		// unused exported functions of the other packages are reported.
+		if !a.hasPublicAPIPackage() {
+			return nil, nil, nil, nil, nil, fmt.Errorf("public-api mode specified, but no public API package was found; use -public-api-pkg or mark the packages with //go:scan:public-api")
+		}
+		analysisFns = append(publicAPIEntryPoints, mainEntryPoints...)
+		isPublicAPIMode = true
+		slog.InfoContext(ctx, "running in public API mode", "analysis_functions", len(analysisFns))
 	case "auto":
 		fallthrough
 	default: // auto
@@ -651,27 +984,65 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 		}
 	}
 
-	// In application mode, the entry point is always considered used.
-	// In library mode, we don't mark anything initially. A function is only "used"
-	// if it's actually called by another function in the analysis set.
-	if isAppMode {
+	// In application mode, the entry point is always considered used, and so is the public API
+	// in public API mode. In library mode, we don't mark anything initially. A function is only
+	// "used" if it's actually called by another function in the analysis set.
+	if isAppMode || isPublicAPIMode {
 		for _, ep := range analysisFns {
-			epName := getFullName(a.s, ep.Package, &scanner.FunctionInfo{Name: ep.Name.Name, AstDecl: ep.Decl})
+			epName := getFullName(ep.Package, &scanner.FunctionInfo{Name: ep.Name.Name, AstDecl: ep.Decl})
 			usageMap[epName] = true
 		}
 	}
 
+	// The entry points declared in the config are always used, and are analyzed in every mode.
+	if a.entrypoints != nil {
+		seen := make(map[*object.Function]bool, len(analysisFns))
+		for _, fn := range analysisFns {
+			seen[fn] = true
+		}
+		for _, ep := range a.findConfiguredEntrypoints(ctx, implementersOf) {
+			markMethodUsage(usageMap, getCanonicalName(ep.pkg, ep.fn))
+			obj, ok := interp.FindFunction(ctx, ep.pkg.ImportPath, ep.fn)
+			if !ok {
+				continue
+			}
+			if fn, ok := obj.(*object.Function); ok && !seen[fn] {
+				seen[fn] = true
+				analysisFns = append(analysisFns, fn)
+			}
+		}
+	}
+
+	// With -test-only, the functions declared in tests are analyzed after the others, so that
+	// the functions used only from tests can be told apart. In application mode, the tests
+	// are analyzed in addition to the main entry points.
+	var testFns []*object.Function
+	if a.testOnly {
+		var nonTestFns []*object.Function
+		for _, fn := range analysisFns {
+			if fn.Def != nil && fn.Def.IsTest {
+				testFns = append(testFns, fn)
+			} else {
+				nonTestFns = append(nonTestFns, fn)
+			}
+		}
+		analysisFns = nonTestFns
+		if isAppMode || isPublicAPIMode {
+			testFns = append(testFns, testEntryPoints...)
+		}
+	}
+
 	// Run symbolic execution from each analysis function to find what they use.
 	var testingT_FieldType *scanner.FieldType // Cache for performance
 
-	for _, ep := range analysisFns {
-		epName := getFullName(a.s, ep.Package, &scanner.FunctionInfo{Name: ep.Name.Name, AstDecl: ep.Decl})
+	analyzeEntryPoint := func(ep *object.Function) {
+		epName := getFullName(ep.Package, &scanner.FunctionInfo{Name: ep.Name.Name, AstDecl: ep.Decl})
 		slog.InfoContext(ctx, "** analyzing entry point", "function", epName)
 
 		args := []object.Object{}
 		// If the entry point is a test function, provide a symbolic *testing.T.
-		// We only do this for library mode entry points, as main.main is not a test.
-		if !isAppMode && a.includeTests && isTestFunction(ep.Def) {
+		// main.main is not a test, so this only applies to the tests.
+		if a.includeTests && isTestFunction(ep.Def) {
 			// Lazily load the type info for *testing.T once.
 			if testingT_FieldType == nil {
 				testingPkg, err := a.s.ScanPackageFromImportPath(ctx, "testing")
@@ -725,18 +1096,42 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 			}
 		}
 	}
+	for _, ep := range analysisFns {
+		analyzeEntryPoint(ep)
+	}
+	usedVia := a.analyzeInterfaceUses(ctx, interp, analyzeEntryPoint)
+	var usedBeforeTests, usedAfterTests map[string]bool
+	if a.testOnly {
+		usedBeforeTests = maps.Clone(usageMap)
+		for _, ep := range testFns {
+			analyzeEntryPoint(ep)
+		}
+		maps.Copy(usedVia, a.analyzeInterfaceUses(ctx, interp, analyzeEntryPoint))
+		usedAfterTests = maps.Clone(usageMap)
+	}
 	slog.InfoContext(ctx, "symbolic execution complete")
 
 	// Finalize the analysis to resolve any collected interface method calls.
 	slog.InfoContext(ctx, "finalizing analysis for interface resolution")
 	interp.Finalize(ctx)
+	a.unresolvedCalls = interp.UnresolvedCalls()
+	a.abortedFunctions = nil
+	for _, c := range interp.Coverage() {
+		if c.Aborted != "" {
+			a.abortedFunctions = append(a.abortedFunctions, c)
+		}
+	}
+
+	a.calledInterfaceMethods = make(map[string]bool)
+	for _, key := range interp.CalledInterfaceMethods() {
+		a.calledInterfaceMethods[key] = true
+	}
 
-	type Orphan struct {
-		Name     string `json:"name"`
-		Position string `json:"position"`
-		Package  string `json:"package"`
+	packages := make([]*scanner.PackageInfo, 0, len(a.packages))
+	for _, pkg := range a.packages {
+		packages = append(packages, pkg)
 	}
-	var orphans []Orphan
+	a.symbolIDs = goscan.NewSymbolIDs(packages...)
 
 	for _, pkg := range a.packages {
 		// Only report orphans from the packages the user explicitly asked to scan.
@@ -745,18 +1140,24 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 		}
 
 		for _, decl := range pkg.Functions {
-			name := getFullName(a.s, pkg, decl)
+			name := getFullName(pkg, decl)
+			if a.testOnly && !decl.IsTest && isUsedOnlyInTests(usedBeforeTests, usedAfterTests, getCanonicalName(pkg, decl)) {
+				usedOnlyInTests = append(usedOnlyInTests, Orphan{
+					ID:              a.symbolIDs.Func(decl),
+					Name:            name,
+					Position:        a.s.Fset().Position(decl.AstDecl.Pos()).String(),
+					Package:         pkg.ImportPath,
+					UsedOnlyInTests: true,
+				})
+				continue
+			}
 			if _, used := usageMap[name]; !used {
 				// If a method is on a pointer receiver, a call to it might have been marked
 				// against the value receiver type. Let's check for that possibility.
-				if decl.Receiver != nil {
-					var buf bytes.Buffer
-					printer.Fprint(&buf, a.s.Fset(), decl.AstDecl.Recv.List[0].Type)
-					if recvTypeStr := buf.String(); len(recvTypeStr) > 0 && recvTypeStr[0] == '*' {
-						valueRecvName := fmt.Sprintf("(%s.%s).%s", pkg.ImportPath, recvTypeStr[1:], decl.Name)
-						if _, usedValue := usageMap[valueRecvName]; usedValue {
-							continue // It was used, just via the value type.
-						}
+				if canonical := getCanonicalName(pkg, decl); canonical.IsPointer {
+					canonical.IsPointer = false
+					if _, usedValue := usageMap[canonical.String()]; usedValue {
+						continue // It was used, just via the value type.
 					}
 				}
 
@@ -777,10 +1178,7 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 				// regular .go file is just a regular function.
 				pos := a.s.Fset().Position(decl.AstDecl.Pos())
 				isTestFile := strings.HasSuffix(pos.Filename, "_test.go")
-				isTestFunc := strings.HasPrefix(decl.Name, "Test") ||
-					strings.HasPrefix(decl.Name, "Benchmark") ||
-					strings.HasPrefix(decl.Name, "Example") ||
-					strings.HasPrefix(decl.Name, "Fuzz")
+				isTestFunc := isTestEntryName(decl.Name)
 
 				// If `a.s.Config.IncludeTests` is false, `isTestFile` will always be false
 				// because no _test.go files are scanned, so this check works correctly
@@ -796,34 +1194,84 @@ func (a *analyzer) analyze(ctx context.Context, asJSON bool) error {
 						}
 					}
 				}
+				if use, ok := usedVia[name]; ok {
+					usedViaInterface = append(usedViaInterface, Orphan{
+						ID:         a.symbolIDs.Func(decl),
+						Name:       name,
+						Position:   pos.String(),
+						Package:    pkg.ImportPath,
+						UsedVia:    use.iface.PkgPath + "." + use.iface.Name,
+						AssignedAt: a.s.Fset().Position(use.pos).String(),
+					})
+					continue
+				}
 				orphans = append(orphans, Orphan{
+					ID:       a.symbolIDs.Func(decl),
 					Name:     name,
 					Position: pos.String(), // pos is already defined above
 					Package:  pkg.ImportPath,
+					Exported: decl.AstDecl.Name.IsExported(),
+					decl:     decl,
 				})
 			}
 		nextDecl:
 		}
 	}
 
-	if asJSON {
-		encoder := json.NewEncoder(os.Stdout)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(orphans); err != nil {
-			return fmt.Errorf("failed to encode orphans to JSON: %w", err)
+	if a.members {
+		unusedMembers = a.findUnusedMembers(ctx, implementersOf, usageMap)
+	}
+	if a.stringRefs != nil {
+		refs := a.collectStringRefs(ctx, a.stringRefs)
+		orphans = demoteStringRefs(refs, orphans, &referencedByString)
+		unusedMembers = demoteStringRefs(refs, unusedMembers, &referencedByString)
+		slog.InfoContext(ctx, "orphans referenced by string", "count", len(referencedByString))
+	}
+	if a.baseline != nil {
+		orphans = a.filterBaseline(ctx, orphans)
+		unusedMembers = a.filterBaseline(ctx, unusedMembers)
+		usedOnlyInTests = a.filterBaseline(ctx, usedOnlyInTests)
+		referencedByString = a.filterBaseline(ctx, referencedByString)
+		usedViaInterface = a.filterBaseline(ctx, usedViaInterface)
+	}
+
+	return orphans, unusedMembers, usedOnlyInTests, referencedByString, usedViaInterface, nil
+}
+
+// isPublicAPIPackage reports whether the package is a part of the public API in public-api mode:
+// it matches a pattern of -public-api-pkg, or the doc comment of its package clause has the
+// //go:scan:public-api marker.
+func (a *analyzer) isPublicAPIPackage(pkg *scanner.PackageInfo) bool {
+	for _, pattern := range a.publicAPIPkgs {
+		if base, ok := strings.CutSuffix(pattern, "/..."); ok {
+			if pkg.ImportPath == base || strings.HasPrefix(pkg.ImportPath, base+"/") {
+				return true
+			}
+		} else if pkg.ImportPath == pattern {
+			return true
 		}
-	} else {
-		if len(orphans) == 0 {
-			fmt.Println("No orphans found.")
-			return nil
+	}
+	for _, file := range pkg.AstFiles {
+		if file.Doc == nil {
+			continue
 		}
-		fmt.Println("\n-- Orphans --")
-		for _, o := range orphans {
-			fmt.Printf("%s\n  %s\n", o.Name, o.Position)
+		for _, comment := range file.Doc.List {
+			if strings.TrimSpace(comment.Text) == "//go:scan:public-api" {
+				return true
+			}
 		}
 	}
+	return false
+}
 
-	return nil
+// hasPublicAPIPackage reports whether one of the target packages is a part of the public API.
+func (a *analyzer) hasPublicAPIPackage() bool {
+	for importPath := range a.targetPackages {
+		if pkg, ok := a.packages[importPath]; ok && a.isPublicAPIPackage(pkg) {
+			return true
+		}
+	}
+	return false
 }
 
 func (a *analyzer) markMethodAsUsed(ctx context.Context, usageMap map[string]bool, implFt *scanner.FieldType, methodName string) {
@@ -843,16 +1291,7 @@ func (a *analyzer) markMethodAsUsed(ctx context.Context, usageMap map[string]boo
 			// Check if the receiver of the method `m` matches the type `typeInfo`.
 			if m.Receiver.Type.Name == typeInfo.Name || (m.Receiver.Type.IsPointer && m.Receiver.Type.Elem.Name == typeInfo.Name) {
 				// Found the method. Mark it as used.
-				var buf bytes.Buffer
-				printer.Fprint(&buf, a.s.Fset(), m.AstDecl.Recv.List[0].Type)
-				methodFullName := fmt.Sprintf("(%s.%s).%s", implPkg.ImportPath, buf.String(), m.Name)
-				usageMap[methodFullName] = true
-
-				// Also mark the value-receiver form if the concrete method has a pointer receiver
-				if recvTypeStr := buf.String(); len(recvTypeStr) > 0 && recvTypeStr[0] == '*' {
-					valueRecvName := fmt.Sprintf("(%s.%s).%s", implPkg.ImportPath, recvTypeStr[1:], m.Name)
-					usageMap[valueRecvName] = true
-				}
+				markMethodUsage(usageMap, getCanonicalName(implPkg, m))
 				break
 			}
 		}
@@ -875,6 +1314,12 @@ func (a *analyzer) Visit(pkg *goscan.PackageImports) ([]string, error) {
 	}
 	a.packages[pkg.ImportPath] = fullPkg
 
+	// The external test package of a scanned package is analyzed with it.
+	if xtest := fullPkg.XTest; xtest != nil && a.scanPackages[pkg.ImportPath] {
+		a.packages[xtest.ImportPath] = xtest
+		a.scanPackages[xtest.ImportPath] = true
+	}
+
 	// Only follow imports that are part of the original scan scope.
 	// This prevents the walker from traversing into third-party dependencies.
 	var importsToFollow []string
@@ -886,56 +1331,55 @@ func (a *analyzer) Visit(pkg *goscan.PackageImports) ([]string, error) {
 	return importsToFollow, nil
 }
 
-func getFullName(s *goscan.Scanner, pkg *scanner.PackageInfo, fn *scanner.FunctionInfo) string {
-	if fn.Receiver != nil {
-		var buf bytes.Buffer
-		// fn.AstDecl can be nil for functions without bodies (like in interfaces)
-		// or for entry points where we create a synthetic FunctionInfo.
-		if fn.AstDecl == nil || fn.AstDecl.Recv == nil || len(fn.AstDecl.Recv.List) == 0 {
-			// Fallback for safety, using the less precise type string.
-			// This is important for analyzing entry points that are just names.
-			return fmt.Sprintf("(%s.%s).%s", pkg.ImportPath, fn.Receiver.Type.String(), fn.Name)
-		}
-		printer.Fprint(&buf, s.Fset(), fn.AstDecl.Recv.List[0].Type)
-		return fmt.Sprintf("(%s.%s).%s", pkg.ImportPath, buf.String(), fn.Name)
-	}
-	// Handle special case for main entry point where fn is synthetic
-	if fn.Name == "main" && fn.AstDecl == nil {
-		return fmt.Sprintf("%s.main", pkg.ImportPath)
-	}
-	return fmt.Sprintf("%s.%s", pkg.ImportPath, fn.Name)
+// getFullName returns the canonical name of the function, e.g. "(*example.com/pkg.Foo).Method".
+func getFullName(pkg *scanner.PackageInfo, fn *scanner.FunctionInfo) string {
+	return getCanonicalName(pkg, fn).String()
 }
 
-func buildInterfaceMap(ctx context.Context, s *goscan.Scanner, packages map[string]*scanner.PackageInfo) map[string][]*scanner.TypeInfo {
-	interfaceMap := make(map[string][]*scanner.TypeInfo)
-	var allInterfaces []*scanner.TypeInfo
-	var allStructs []*scanner.TypeInfo
-
-	for _, pkg := range packages {
-		for _, t := range pkg.Types {
-			if t.Kind == scanner.InterfaceKind {
-				allInterfaces = append(allInterfaces, t)
-			} else if t.Kind == scanner.StructKind {
-				allStructs = append(allStructs, t)
-			}
-		}
+// getCanonicalName returns the canonical name of a function of the package.
+// fn may be a synthetic FunctionInfo (e.g. for an entry point) without a package path.
+func getCanonicalName(pkg *scanner.PackageInfo, fn *scanner.FunctionInfo) scanner.CanonicalName {
+	name := fn.CanonicalName()
+	if name.PkgPath == "" {
+		name.PkgPath = pkg.ImportPath
 	}
+	return name
+}
 
-	for _, iface := range allInterfaces {
-		ifaceName := fmt.Sprintf("%s.%s", iface.PkgPath, iface.Name)
-		var implementers []*scanner.TypeInfo
+// markMethodUsage marks the function as used. A method with a pointer receiver is also
+// marked under its value receiver, as calls may have been recorded against either form.
+func markMethodUsage(usageMap map[string]bool, name scanner.CanonicalName) {
+	usageMap[name.String()] = true
+	if name.IsPointer {
+		name.IsPointer = false
+		usageMap[name.String()] = true
+	}
+}
 
-		for _, strct := range allStructs {
-			if s.Implements(ctx, strct, iface) {
-				implementers = append(implementers, strct)
-			}
+// isUsedOnlyInTests reports whether the function was marked as used while analyzing the tests,
+// but not before. As in the orphan check, a method with a pointer receiver may have been
+// marked under its value receiver.
+func isUsedOnlyInTests(usedBeforeTests, usedAfterTests map[string]bool, name scanner.CanonicalName) bool {
+	isUsed := func(usageMap map[string]bool) bool {
+		if usageMap[name.String()] {
+			return true
 		}
-		if len(implementers) > 0 {
-			interfaceMap[ifaceName] = implementers
+		if name.IsPointer {
+			valueName := name
+			valueName.IsPointer = false
+			return usageMap[valueName.String()]
 		}
+		return false
 	}
+	return isUsed(usedAfterTests) && !isUsed(usedBeforeTests)
+}
 
-	return interfaceMap
+// isTestEntryName reports whether the name is the one of a function run by `go test`.
+func isTestEntryName(name string) bool {
+	return strings.HasPrefix(name, "Test") ||
+		strings.HasPrefix(name, "Benchmark") ||
+		strings.HasPrefix(name, "Example") ||
+		strings.HasPrefix(name, "Fuzz")
 }
 
 // isTestFunction checks if a function is a standard Go test function.
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -17,11 +18,6 @@ import (
 	"github.com/podhmo/go-scan/scantest"
 )
 
-const (
-	debugOff = false
-	debugOn  = true
-)
-
 func TestFindOrphans(t *testing.T) {
 	files := map[string]string{
 		"go.mod": "module example.com/find-orphans-test\ngo 1.21\n",
@@ -57,7 +53,13 @@ func IgnoredFunc() {}
 	startPatterns := []string{"example.com/find-orphans-test/..."}
 	// Set verbose to false, and asJSON to false
 	log.SetOutput(w)
-	err := run(context.Background(), debugOff, true, false, dir, false, false, "auto", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -73,7 +75,7 @@ func IgnoredFunc() {}
 
 	expectedOrphans := []string{
 		"example.com/find-orphans-test.unused_main_func",
-		"(example.com/find-orphans-test/greeter.*Greeter).UnusedMethod",
+		"(*example.com/find-orphans-test/greeter.Greeter).UnusedMethod",
 		"example.com/find-orphans-test/greeter.UnusedFunc",
 	}
 	sort.Strings(expectedOrphans)
@@ -81,7 +83,7 @@ func IgnoredFunc() {}
 	var foundOrphans []string
 	lines := strings.Split(strings.TrimSpace(output), "\n")
 	for _, line := range lines {
-		if strings.HasPrefix(line, "example.com") || strings.HasPrefix(line, "(example.com") {
+		if strings.HasPrefix(line, "example.com") || strings.HasPrefix(line, "(example.com") || strings.HasPrefix(line, "(*example.com") {
 			foundOrphans = append(foundOrphans, line)
 		}
 	}
@@ -157,7 +159,13 @@ func EntryPoint() {
 		return pkgPath == "example.com/scope-test/pkgc"
 	}
 
-	err := run(context.Background(), debugOff, false, false, dir, false, false, "lib", reportPatterns, nil, scanPolicy, primaryScope, nil)
+	err := run(context.Background(), runConfig{
+		Workspace:            dir,
+		Mode:                 "lib",
+		StartPatterns:        reportPatterns,
+		ScanPolicy:           scanPolicy,
+		PrimaryAnalysisScope: primaryScope,
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -230,7 +238,13 @@ func UnusedExportedFunc() {}
 
 	startPatterns := []string{"./..."}
 	// Run in "auto" mode. Since there is no main.main, it will fall back to library mode.
-	err := run(context.Background(), debugOff, true, false, dir, false, false, "auto", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -292,7 +306,13 @@ func UnusedFunc() {}
 	log.SetOutput(io.Discard)
 
 	startPatterns := []string{"./..."}
-	err := run(context.Background(), debugOff, true, false, dir, false, false, "auto", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -379,7 +399,13 @@ func unusedInB() {}
 	defer os.Chdir(oldWd)
 
 	// Run in auto mode. It should detect both main packages.
-	err = run(context.Background(), debugOff, true, false, ".", false, false, "auto", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     ".",
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -457,7 +483,14 @@ func TestSomething(t *testing.T) {
 	startPatterns := []string{"example.com/subtest-usage/lib"}
 	// We need --include-tests=true for this to work at all.
 	// We use "lib" mode to ensure that TestSomething is treated as an entry point.
-	err := run(context.Background(), debugOff, true, true, dir, false, false, "lib", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		IncludeTests:  true,
+		Workspace:     dir,
+		Mode:          "lib",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -566,7 +599,13 @@ func (c *Client) TriggerCallback(callback func()) {
 
 	// Note: We no longer need a 'replace' directive in go.mod because the
 	// go.work file handles module resolution within the workspace.
-	err = run(context.Background(), debugOff, true, false, ".", false, false, "auto", startPatterns, []string{"testdata"}, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     ".",
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -641,7 +680,13 @@ func trulyUnusedFunc() {}
 	log.SetOutput(io.Discard)
 
 	startPatterns := []string{"example.com/intra-pkg-methods/lib"}
-	err := run(context.Background(), debugOff, true, false, dir, false, false, "lib", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		Mode:          "lib",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -654,7 +699,7 @@ func trulyUnusedFunc() {}
 	output := buf.String()
 
 	expectedOrphans := []string{
-		"(example.com/intra-pkg-methods/lib.*MyType).ExportedMethod",
+		"(*example.com/intra-pkg-methods/lib.MyType).ExportedMethod",
 		"example.com/intra-pkg-methods/lib.trulyUnusedFunc",
 	}
 	sort.Strings(expectedOrphans)
@@ -711,7 +756,13 @@ func UnusedFuncInExcludedModule() {}
 	defer os.Chdir(oldWd)
 
 	// We explicitly exclude the "testdata" directory where moduleb resides.
-	err = run(context.Background(), debugOff, true, false, ".", false, false, "auto", startPatterns, []string{"testdata"}, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     ".",
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -733,6 +784,89 @@ func UnusedFuncInExcludedModule() {}
 	}
 }
 
+// TestFindOrphans_workspaceSiblingModules verifies that the packages of modules in sibling
+// directories sharing a prefix (api and api-client), with the same relative paths, keep their
+// own identities: the used util.Helper of each module is not reported.
+func TestFindOrphans_workspaceSiblingModules(t *testing.T) {
+	files := map[string]string{
+		"workspace/api/go.mod": "module example.com/api\ngo 1.21\nrequire example.com/client v0.0.0\nreplace example.com/client => ../api-client\n",
+		"workspace/api/main.go": `
+package main
+import (
+	"example.com/api/internal/util"
+	"example.com/client/sdk"
+)
+func main() {
+	util.Helper()
+	sdk.Call()
+}
+`,
+		"workspace/api/internal/util/util.go": `
+package util
+func Helper() {}
+func UnusedInAPI() {}
+`,
+		"workspace/api-client/go.mod": "module example.com/client\ngo 1.21\n",
+		"workspace/api-client/sdk/sdk.go": `
+package sdk
+import "example.com/client/internal/util"
+func Call() { util.Helper() }
+`,
+		"workspace/api-client/internal/util/util.go": `
+package util
+func Helper() {}
+func UnusedInClient() {}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	log.SetOutput(io.Discard)
+
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get wd: %v", err)
+	}
+	if err := os.Chdir(filepath.Join(dir, "workspace")); err != nil {
+		t.Fatalf("failed to change wd: %v", err)
+	}
+	defer os.Chdir(oldWd)
+
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     ".",
+		Mode:          "auto",
+		StartPatterns: []string{"./..."},
+	})
+	w.Close()
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	var foundOrphans []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if strings.HasPrefix(line, "example.com/") {
+			foundOrphans = append(foundOrphans, strings.TrimSpace(line))
+		}
+	}
+	sort.Strings(foundOrphans)
+	expectedOrphans := []string{
+		"example.com/api/internal/util.UnusedInAPI",
+		"example.com/client/internal/util.UnusedInClient",
+	}
+	if diff := cmp.Diff(expectedOrphans, foundOrphans); diff != "" {
+		t.Errorf("find-orphans mismatch (-want +got):\n%s\nFull output:\n%s", diff, output)
+	}
+}
+
 // TestFindOrphans_CrossModuleUsage verifies that a function in a target package
 // is correctly identified as "used" even if its only usage is in another
 // package within the same workspace (which is scanned but not targeted for reporting).
@@ -781,7 +915,13 @@ func UnusedFunc() {}
 	defer os.Chdir(oldWd)
 
 	// workspaceRoot is ".", startPatterns is the specific import path.
-	err = run(context.Background(), debugOff, true, false, ".", false, false, "auto", startPatterns, []string{"testdata"}, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     ".",
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -847,7 +987,13 @@ func MyUnusedFunc() {}
 	defer os.Chdir(oldWd)
 
 	// The key is that this should not error out.
-	err = run(context.Background(), debugOff, true, false, ".", false, false, "auto", startPatterns, []string{"testdata"}, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     ".",
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed with an unexpected error: %v", err)
 	}
@@ -909,7 +1055,13 @@ func UnusedFunc() {
 	defer os.Chdir(oldWd)
 
 	// Use a relative path for the workspace root
-	err = run(context.Background(), debugOff, true, false, "..", false, false, "auto", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     "..",
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -968,7 +1120,13 @@ func Unused() {}
 
 	// We only target the main package, NOT the dependency.
 	startPatterns := []string{"example.com/filter-test"}
-	err := run(context.Background(), debugOff, true, false, dir, false, false, "auto", startPatterns, []string{"vendor"}, nil, nil, nil)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1021,7 +1179,13 @@ func UnusedInTestdata() {}
 
 	startPatterns := []string{"./..."}
 	// We explicitly EXCLUDE "testdata"
-	err = run(context.Background(), debugOff, true, false, ".", false, false, "auto", startPatterns, []string{"testdata"}, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     ".",
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1089,7 +1253,13 @@ func UnusedFunc() {
 	defer os.Chdir(oldWd)
 
 	// Set verbose to false, and asJSON to false
-	err = run(context.Background(), debugOff, true, false, workspaceRoot, false, false, "auto", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     workspaceRoot,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1152,7 +1322,14 @@ func unusedInTest() {}
 		os.Stdout = w
 		log.SetOutput(io.Discard)
 
-		err := run(context.Background(), debugOff, true, true, dir, true, false, "auto", []string{"./..."}, nil, nil, nil, nil)
+		err := run(context.Background(), runConfig{
+			All:           true,
+			IncludeTests:  true,
+			Workspace:     dir,
+			Verbose:       true,
+			Mode:          "auto",
+			StartPatterns: []string{"./..."},
+		})
 		if err != nil {
 			t.Fatalf("run() failed: %v", err)
 		}
@@ -1183,7 +1360,13 @@ func unusedInTest() {}
 		os.Stdout = w
 		log.SetOutput(io.Discard)
 
-		err := run(context.Background(), debugOff, true, false, dir, true, false, "auto", []string{"./..."}, nil, nil, nil, nil)
+		err := run(context.Background(), runConfig{
+			All:           true,
+			Workspace:     dir,
+			Verbose:       true,
+			Mode:          "auto",
+			StartPatterns: []string{"./..."},
+		})
 		if err != nil {
 			t.Fatalf("run() failed: %v", err)
 		}
@@ -1249,7 +1432,13 @@ func UnusedFunc() {
 	}
 	defer os.Chdir(oldWd)
 
-	err = run(context.Background(), debugOff, true, false, workspaceRoot, false, false, "auto", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     workspaceRoot,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1302,7 +1491,13 @@ func unusedInternalFunc() {}
 	log.SetOutput(io.Discard)
 
 	startPatterns := []string{"example.com/find-orphans-test/lib"}
-	err := run(context.Background(), debugOff, true, false, dir, false, false, "auto", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1375,7 +1570,14 @@ func UnusedFunc() {}
 
 	startPatterns := []string{"example.com/find-orphans-test/..."}
 	// Run with asJSON=true
-	err := run(context.Background(), debugOff, true, false, dir, false, true, "auto", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		JSON:          true,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1400,7 +1602,7 @@ func UnusedFunc() {}
 
 	expectedOrphanNames := []string{
 		"example.com/find-orphans-test.unused_main_func",
-		"(example.com/find-orphans-test/greeter.*Greeter).UnusedMethod",
+		"(*example.com/find-orphans-test/greeter.Greeter).UnusedMethod",
 		"example.com/find-orphans-test/greeter.UnusedFunc",
 	}
 
@@ -1452,7 +1654,13 @@ func (c *Cat) UnusedMethod() {}
 	log.SetOutput(io.Discard)
 
 	startPatterns := []string{"example.com/find-orphans-test/..."}
-	err := run(context.Background(), debugOff, true, false, dir, false, false, "auto", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1465,8 +1673,8 @@ func (c *Cat) UnusedMethod() {}
 	output := buf.String()
 
 	expectedOrphans := []string{
-		"(example.com/find-orphans-test/speaker.*Dog).UnusedMethod",
-		"(example.com/find-orphans-test/speaker.*Cat).UnusedMethod",
+		"(*example.com/find-orphans-test/speaker.Dog).UnusedMethod",
+		"(*example.com/find-orphans-test/speaker.Cat).UnusedMethod",
 	}
 	sort.Strings(expectedOrphans)
 
@@ -1518,7 +1726,11 @@ func ExportedAndUnused() {}
 	defer os.Chdir(oldWd)
 
 	// Force library mode
-	err = run(context.Background(), debugOff, true, false, "", false, false, "lib", startPatterns, nil, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Mode:          "lib",
+		StartPatterns: startPatterns,
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1561,7 +1773,11 @@ func SomeFunc() {}
 	defer os.Chdir(oldWd)
 
 	// Force application mode, expecting an error
-	err = run(context.Background(), debugOff, true, false, "", false, false, "app", startPatterns, nil, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Mode:          "app",
+		StartPatterns: startPatterns,
+	})
 	if err == nil {
 		t.Fatalf("run() should have failed in app mode with no main function, but it did not")
 	}
@@ -1613,7 +1829,11 @@ func unusedInternal() {}
 	// Force library mode.
 	// The test is to ensure that even in lib mode, main() and init() are
 	// used as entry points for analysis.
-	err = run(context.Background(), debugOff, true, false, "", false, false, "lib", startPatterns, nil, nil, nil, nil)
+	err = run(context.Background(), runConfig{
+		All:           true,
+		Mode:          "lib",
+		StartPatterns: startPatterns,
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1675,7 +1895,13 @@ func UnusedInB() {}
 	startPatterns := []string{"./..."}
 	primaryScope := []string{"example.com/test/pkga"} // Only analyze pkga
 
-	err := run(context.Background(), debugOff, true, false, dir, false, false, "lib", startPatterns, nil, nil, primaryScope, nil)
+	err := run(context.Background(), runConfig{
+		All:                  true,
+		Workspace:            dir,
+		Mode:                 "lib",
+		StartPatterns:        startPatterns,
+		PrimaryAnalysisScope: primaryScope,
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1766,7 +1992,13 @@ func unusedInB() {}
 	defer os.Chdir(oldWd)
 
 	// Run in app mode, specifying only cmda as the entry point.
-	err = run(context.Background(), debugOff, true, false, ".", false, false, "app", startPatterns, nil, nil, nil, entrypointPkgs)
+	err = run(context.Background(), runConfig{
+		All:            true,
+		Workspace:      ".",
+		Mode:           "app",
+		StartPatterns:  startPatterns,
+		EntrypointPkgs: entrypointPkgs,
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1842,7 +2074,12 @@ func main() {}
 	defer os.Chdir(oldWd)
 
 	// Force application mode, expecting an error
-	err = run(context.Background(), debugOff, true, false, "", false, false, "app", startPatterns, nil, nil, nil, entrypointPkgs)
+	err = run(context.Background(), runConfig{
+		All:            true,
+		Mode:           "app",
+		StartPatterns:  startPatterns,
+		EntrypointPkgs: entrypointPkgs,
+	})
 	if err == nil {
 		t.Fatalf("run() should have failed with an invalid entrypoint package, but it did not")
 	}
@@ -1874,7 +2111,13 @@ func unused_in_main() {}
 	log.SetOutput(io.Discard)
 
 	startPatterns := []string{"./..."}
-	err := run(context.Background(), debugOff, true, false, dir, false, false, "auto", startPatterns, []string{"testdata", "vendor"}, nil, nil, nil)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
 	if err != nil {
 		t.Fatalf("run() failed: %v", err)
 	}
@@ -1916,3 +2159,313 @@ func unused_in_main() {}
 		t.Errorf("find-orphans mismatch (-want +got):\n%s\nFull output:\n%s", diff, output)
 	}
 }
+
+func TestFindOrphans_members(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/members-test\ngo 1.21\n",
+		"main.go": `
+package main
+import "example.com/members-test/store"
+func main() {
+	var s store.Store = store.New()
+	s.Get("id")
+	s.Close()
+
+	cfg := store.Config{Name: "x"}
+	_ = cfg
+	p := store.Point{1, 2}
+	_ = p
+	items := []store.Item{{Label: "a"}}
+	_ = items
+
+	notify(nil)
+}
+func notify(n store.Notifier) {
+	n.Notify()
+}
+`,
+		"store/store.go": `
+package store
+
+type Config struct {
+	Name   string
+	Unused int
+	Tagged string ` + "`json:\"tagged\"`" + `
+	//go:scan:ignore
+	Ignored bool
+}
+
+type Point struct{ X, Y int }
+
+type Item struct {
+	Label string
+	Price int
+}
+
+type Base interface {
+	Close() error
+	Reset()
+}
+
+type Store interface {
+	Base
+	Get(id string) string
+	Put(id string)
+}
+
+// Notifier has no implementations, so its methods are only used through calls on the interface.
+type Notifier interface {
+	Notify()
+	Silence()
+}
+
+type memStore struct {
+	data map[string]string
+	hits int
+}
+
+func New() Store { return &memStore{data: map[string]string{}} }
+
+func (m *memStore) Get(id string) string { return m.data[id] }
+func (m *memStore) Put(id string)        {}
+func (m *memStore) Close() error         { return nil }
+func (m *memStore) Reset()               {}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	startPatterns := []string{"example.com/members-test/..."}
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		JSON:          true,
+		Mode:          "auto",
+		StartPatterns: startPatterns,
+		ExcludeDirs:   []string{"testdata", "vendor"},
+		Members:       true,
+	})
+	if err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	var found []Orphan
+	if err := json.Unmarshal(buf.Bytes(), &found); err != nil {
+		t.Fatalf("failed to unmarshal JSON output: %v\nOutput was:\n%s", err, buf.String())
+	}
+
+	var members []string
+	for _, o := range found {
+		if o.Kind != "" {
+			members = append(members, o.Name+" ["+o.Kind+"]")
+		}
+	}
+	want := []string{
+		"(example.com/members-test/store.Base).Reset [interface-method]",
+		"(example.com/members-test/store.Config).Unused [field]",
+		"(example.com/members-test/store.Item).Price [field]",
+		"(example.com/members-test/store.Notifier).Silence [interface-method]",
+		"(example.com/members-test/store.Store).Put [interface-method]",
+		"(example.com/members-test/store.memStore).hits [field]",
+	}
+	if diff := cmp.Diff(want, members); diff != "" {
+		t.Errorf("unused members mismatch (-want +got):\n%s\nFull output:\n%s", diff, buf.String())
+	}
+}
+
+func TestFindOrphans_membersByType(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/members-bytype\ngo 1.21\n",
+		"main.go": `
+package main
+import "example.com/members-bytype/lib"
+func main() {
+	var u lib.User
+	println(u.Name)
+	var o lib.Outer
+	o.ID = 1
+	var req lib.Request
+	println(&req)
+}
+`,
+		"lib/lib.go": `
+package lib
+
+type User struct {
+	Name string
+}
+
+// Group.Name shares its name with User.Name, which is the only one used.
+type Group struct {
+	Name string
+}
+
+type Inner struct {
+	ID int
+}
+
+type Outer struct {
+	Inner
+}
+
+// Request is named in main, so its tagged fields may be read through reflection.
+type Request struct {
+	Body string ` + "`json:\"body\"`" + `
+}
+
+// Response is never named, so its tagged fields are unused.
+type Response struct {
+	Body string ` + "`json:\"body\"`" + `
+}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		JSON:          true,
+		Mode:          "auto",
+		StartPatterns: []string{"example.com/members-bytype/..."},
+		ExcludeDirs:   []string{"testdata", "vendor"},
+		Members:       true,
+	})
+	if err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	var found []Orphan
+	if err := json.Unmarshal(buf.Bytes(), &found); err != nil {
+		t.Fatalf("failed to unmarshal JSON output: %v\nOutput was:\n%s", err, buf.String())
+	}
+
+	var members []string
+	for _, o := range found {
+		if o.Kind != "" {
+			members = append(members, o.Name+" ["+o.Kind+"]")
+		}
+	}
+	want := []string{
+		"(example.com/members-bytype/lib.Group).Name [field]",
+		"(example.com/members-bytype/lib.Response).Body [field]",
+	}
+	if diff := cmp.Diff(want, members); diff != "" {
+		t.Errorf("unused members mismatch (-want +got):\n%s\nFull output:\n%s", diff, buf.String())
+	}
+}
+
+func TestFindOrphans_reflection(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/reflect-test\n\ngo 1.22\n",
+		"main.go": `
+package main
+
+import (
+	"os"
+	"reflect"
+)
+
+type Server struct{}
+
+func (s *Server) Start()       { warmUp() }
+func (s *Server) Stop()        {}
+func (s *Server) HandleUsers() {}
+func (s *Server) HandleItems() {}
+
+func warmUp() {}
+
+func dispatch(s *Server, name string) {
+	reflect.ValueOf(s).MethodByName("Handle" + name).Call(nil)
+}
+
+func main() {
+	s := &Server{}
+	reflect.ValueOf(s).MethodByName("Start").Call(nil)
+	dispatch(s, os.Args[1])
+}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	cases := []struct {
+		reflectAllMethods bool
+		want              []string
+	}{
+		{
+			reflectAllMethods: false,
+			want: []string{
+				"(*example.com/reflect-test.Server).HandleItems",
+				"(*example.com/reflect-test.Server).HandleUsers",
+				"(*example.com/reflect-test.Server).Stop",
+			},
+		},
+		{
+			reflectAllMethods: true,
+			want: []string{
+				"(*example.com/reflect-test.Server).Stop",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run("reflect-all-methods="+strconv.FormatBool(tc.reflectAllMethods), func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			startPatterns := []string{"example.com/reflect-test/..."}
+			err := run(context.Background(), runConfig{
+				All:               true,
+				Workspace:         dir,
+				JSON:              true,
+				Mode:              "app",
+				StartPatterns:     startPatterns,
+				ExcludeDirs:       []string{"testdata", "vendor"},
+				ReflectAllMethods: tc.reflectAllMethods,
+			})
+
+			w.Close()
+			os.Stdout = oldStdout
+			if err != nil {
+				t.Fatalf("run() failed: %v", err)
+			}
+
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+
+			var found []Orphan
+			if err := json.Unmarshal(buf.Bytes(), &found); err != nil {
+				t.Fatalf("failed to unmarshal JSON output: %v\nOutput was:\n%s", err, buf.String())
+			}
+			var names []string
+			for _, o := range found {
+				names = append(names, o.Name)
+			}
+			sort.Strings(names)
+			if diff := cmp.Diff(tc.want, names); diff != "" {
+				t.Errorf("orphans mismatch (-want +got):\n%s\nFull output:\n%s", diff, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"log/slog"
+	"sort"
+	"strings"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+const (
+	kindField           = "field"
+	kindInterfaceMethod = "interface-method"
+)
+
+// fieldReferenceIndex is the index of the struct field references found in the scan scope, by
+// struct type and field. The selectors like `x.Name` are the field accesses reported by symgo,
+// with the struct declaring the field, so a promoted field is used through the embedding type
+// too. The keys of the composite literals like `T{Name: ...}` are found in the syntax, with the
+// type of their literal, and the unkeyed composite literals like `T{1, 2}` reference every
+// field of their type.
+type fieldReferenceIndex struct {
+	fields     map[string]bool // "<pkg>.<Type>.<Field>" of the fields accessed or used as literal keys
+	positional map[string]bool // "<pkg>.<Type>" of types used in unkeyed composite literals
+	referenced map[string]bool // "<pkg>.<Type>" of types named outside of their declaration
+}
+
+func buildFieldReferenceIndex(s *goscan.Scanner, packages map[string]*scanner.PackageInfo, accessedFields map[string]bool) *fieldReferenceIndex {
+	idx := &fieldReferenceIndex{
+		fields:     make(map[string]bool, len(accessedFields)),
+		positional: make(map[string]bool),
+		referenced: make(map[string]bool),
+	}
+	for key := range accessedFields {
+		idx.fields[key] = true
+	}
+	for _, pkg := range packages {
+		for _, file := range pkg.AstFiles {
+			importLookup := s.BuildImportLookup(file)
+			// The type of an elided composite literal (e.g. the elements of `[]T{{...}}`)
+			// is implied by its parent literal.
+			implied := make(map[*ast.CompositeLit]ast.Expr)
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.TypeSpec:
+					ast.Inspect(n.Type, func(n ast.Node) bool {
+						idx.addTypeName(n, pkg, importLookup)
+						return true
+					})
+					return false // the name of the declaration is not a reference
+				case *ast.CompositeLit:
+					typeExpr := n.Type
+					if typeExpr == nil {
+						typeExpr = implied[n]
+					}
+					idx.addCompositeLit(n, typeExpr, pkg.ImportPath, importLookup, implied)
+				}
+				idx.addTypeName(n, pkg, importLookup)
+				return true
+			})
+		}
+	}
+	return idx
+}
+
+// addTypeName records the type named by an identifier or a qualified identifier, e.g. in
+// `var req Request`. Identifiers shadowing a type name make the index conservative.
+func (idx *fieldReferenceIndex) addTypeName(n ast.Node, pkg *scanner.PackageInfo, importLookup map[string]string) {
+	switch n := n.(type) {
+	case *ast.Ident:
+		if pkg.Lookup(n.Name) != nil {
+			idx.referenced[pkg.ImportPath+"."+n.Name] = true
+		}
+	case *ast.SelectorExpr:
+		if x, ok := n.X.(*ast.Ident); ok {
+			if path, ok := importLookup[x.Name]; ok {
+				idx.referenced[path+"."+n.Sel.Name] = true
+			}
+		}
+	}
+}
+
+func (idx *fieldReferenceIndex) addCompositeLit(lit *ast.CompositeLit, typeExpr ast.Expr, pkgPath string, importLookup map[string]string, implied map[*ast.CompositeLit]ast.Expr) {
+	var keyType, elemType ast.Expr
+	switch t := typeExpr.(type) {
+	case *ast.ArrayType:
+		elemType = t.Elt
+	case *ast.MapType:
+		keyType, elemType = t.Key, t.Value
+	}
+
+	for _, elt := range lit.Elts {
+		value := elt
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			value = kv.Value
+			if id, ok := kv.Key.(*ast.Ident); ok && elemType == nil && keyType == nil {
+				if name := literalTypeName(typeExpr, pkgPath, importLookup); name != "" {
+					idx.fields[name+"."+id.Name] = true // a struct literal key
+				}
+			} else if child, ok := unparen(kv.Key).(*ast.CompositeLit); ok && child.Type == nil && keyType != nil {
+				implied[child] = derefExpr(keyType)
+			}
+		} else if elemType == nil && keyType == nil {
+			if name := literalTypeName(typeExpr, pkgPath, importLookup); name != "" {
+				idx.positional[name] = true
+			}
+		}
+		if child, ok := unparen(value).(*ast.CompositeLit); ok && child.Type == nil && elemType != nil {
+			implied[child] = derefExpr(elemType)
+		}
+	}
+}
+
+// isUsed reports whether the field of the struct type named "<pkg>.<Type>" is referenced.
+// A field with a struct tag is also used if its type is named anywhere, as such a field is
+// typically accessed through reflection (e.g. by encoding/json) on the values of the type.
+func (idx *fieldReferenceIndex) isUsed(typeName, fieldName string, tagged bool) bool {
+	if tagged && idx.referenced[typeName] {
+		return true
+	}
+	return idx.fields[typeName+"."+fieldName] || idx.positional[typeName]
+}
+
+// literalTypeName returns the "<pkg>.<Type>" name of a composite literal type expression, if it is a named type.
+func literalTypeName(expr ast.Expr, pkgPath string, importLookup map[string]string) string {
+	switch t := derefExpr(expr).(type) {
+	case *ast.Ident:
+		return pkgPath + "." + t.Name
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			if path, ok := importLookup[x.Name]; ok {
+				return path + "." + t.Sel.Name
+			}
+		}
+	case *ast.IndexExpr: // generic type instantiation, e.g. Box[int]
+		return literalTypeName(t.X, pkgPath, importLookup)
+	case *ast.IndexListExpr:
+		return literalTypeName(t.X, pkgPath, importLookup)
+	}
+	return ""
+}
+
+func derefExpr(expr ast.Expr) ast.Expr {
+	expr = unparen(expr)
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return unparen(star.X)
+	}
+	return expr
+}
+
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		p, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = p.X
+	}
+}
+
+// findUnusedMembers reports the struct fields that are never referenced in the scan scope, and the
+// interface methods that are never called, for the types in the target packages.
+// The fields with a struct tag are used as soon as their type is, see fieldReferenceIndex.isUsed,
+// and the embedded fields are skipped.
+//
+// Like the orphan analysis, this is conservative: an interface method is used if it is called
+// through the interface (or an interface embedding it), or if any implementation of it is used,
+// as symgo calls the concrete method directly when it knows the dynamic type of the receiver.
+func (a *analyzer) findUnusedMembers(ctx context.Context, implementersOf func(*scanner.TypeInfo) []*scanner.TypeInfo, usageMap map[string]bool) []Orphan {
+	index := buildFieldReferenceIndex(a.s, a.packages, a.accessedFields)
+	embedders := a.buildInterfaceEmbedders(ctx)
+
+	var members []Orphan
+	for _, pkg := range a.packages {
+		if _, isTarget := a.targetPackages[pkg.ImportPath]; !isTarget {
+			continue
+		}
+		for _, t := range pkg.Types {
+			ts, ok := t.Node.(*ast.TypeSpec)
+			if !ok || t.IsAlias || isIgnored(ts.Doc) {
+				continue
+			}
+			typeName := pkg.ImportPath + "." + t.Name
+			switch typ := ts.Type.(type) {
+			case *ast.StructType:
+				for _, field := range typ.Fields.List {
+					if len(field.Names) == 0 || isIgnored(field.Doc) {
+						continue
+					}
+					for _, name := range field.Names {
+						if name.Name == "_" || index.isUsed(typeName, name.Name, field.Tag != nil) {
+							continue
+						}
+						members = append(members, Orphan{
+							ID:       memberID(kindField, a.symbolIDs.Type(t), name.Name),
+							Name:     fmt.Sprintf("(%s).%s", typeName, name.Name),
+							Position: a.s.Fset().Position(name.Pos()).String(),
+							Package:  pkg.ImportPath,
+							Kind:     kindField,
+						})
+					}
+				}
+			case *ast.InterfaceType:
+				for _, method := range typ.Methods.List {
+					if _, ok := method.Type.(*ast.FuncType); !ok {
+						continue // an embedded interface or a type constraint
+					}
+					if isIgnored(method.Doc) {
+						continue
+					}
+					for _, name := range method.Names {
+						if a.isInterfaceMethodCalled(typeName, name.Name, embedders, make(map[string]bool)) ||
+							isImplementationUsed(implementersOf(t), name.Name, usageMap) {
+							continue
+						}
+						members = append(members, Orphan{
+							ID:       memberID(kindInterfaceMethod, a.symbolIDs.Type(t), name.Name),
+							Name:     fmt.Sprintf("(%s).%s", typeName, name.Name),
+							Position: a.s.Fset().Position(name.Pos()).String(),
+							Package:  pkg.ImportPath,
+							Kind:     kindInterfaceMethod,
+						})
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Name < members[j].Name
+	})
+	return members
+}
+
+// memberID returns the stable ID of a member of a type, e.g. "field:example.com/pkg.Type.Name"
+// for the type "type:example.com/pkg.Type".
+func memberID(kind, typeID, name string) string {
+	return kind + ":" + strings.TrimPrefix(typeID, "type:") + "." + name
+}
+
+// isInterfaceMethodCalled reports whether the method is called through the interface,
+// or through any interface that embeds it.
+func (a *analyzer) isInterfaceMethodCalled(ifaceName, methodName string, embedders map[string][]string, visited map[string]bool) bool {
+	if visited[ifaceName] {
+		return false
+	}
+	visited[ifaceName] = true
+	if a.calledInterfaceMethods[ifaceName+"."+methodName] {
+		return true
+	}
+	for _, embedder := range embedders[ifaceName] {
+		if a.isInterfaceMethodCalled(embedder, methodName, embedders, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// isImplementationUsed reports whether the method of any of the implementers is used.
+func isImplementationUsed(implementers []*scanner.TypeInfo, methodName string, usageMap map[string]bool) bool {
+	for _, impl := range implementers {
+		name := scanner.CanonicalName{PkgPath: impl.PkgPath, TypeName: impl.Name, Name: methodName}
+		for _, tp := range impl.TypeParams {
+			name.TypeParams = append(name.TypeParams, tp.Name)
+		}
+		if usageMap[name.String()] { // the value receiver form is also marked for pointer receivers
+			return true
+		}
+	}
+	return false
+}
+
+// buildInterfaceEmbedders maps each interface to the interfaces that embed it.
+func (a *analyzer) buildInterfaceEmbedders(ctx context.Context) map[string][]string {
+	embedders := make(map[string][]string)
+	for _, pkg := range a.packages {
+		for _, t := range pkg.Types {
+			if t.Kind != scanner.InterfaceKind || t.Interface == nil {
+				continue
+			}
+			for _, embedded := range t.Interface.Embedded {
+				embeddedInfo, err := embedded.Resolve(ctx)
+				if err != nil || embeddedInfo == nil {
+					slog.DebugContext(ctx, "could not resolve embedded interface", "interface", t.Name, "embedded", embedded.String(), "error", err)
+					continue
+				}
+				key := embeddedInfo.PkgPath + "." + embeddedInfo.Name
+				embedders[key] = append(embedders[key], pkg.ImportPath+"."+t.Name)
+			}
+		}
+	}
+	return embedders
+}
+
+func isIgnored(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.Contains(comment.Text, "//go:scan:ignore") {
+			return true
+		}
+	}
+	return false
+}
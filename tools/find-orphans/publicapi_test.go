@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestFindOrphans_publicAPI(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/publicapi\ngo 1.21\n",
+		"api/api.go": `
+package api
+import "example.com/publicapi/internal/impl"
+func Do() { impl.Used() }
+func unused() {}
+`,
+		"marked/marked.go": `
+//go:scan:public-api
+package marked
+import "example.com/publicapi/internal/impl"
+func Run() { impl.UsedByMarked() }
+`,
+		"internal/impl/impl.go": `
+package impl
+func Used() { helper() }
+func UsedByMarked() {}
+func Forgotten() {}
+func helper() {}
+func dead() {}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	runPublicAPI := func(t *testing.T, publicAPIPkgs []string) ([]Orphan, error) {
+		t.Helper()
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := run(context.Background(), runConfig{
+			All:           true,
+			Workspace:     dir,
+			JSON:          true,
+			Mode:          "public-api",
+			StartPatterns: []string{"example.com/publicapi/..."},
+			PublicAPIPkgs: publicAPIPkgs,
+		})
+		w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		if err != nil {
+			return nil, err
+		}
+		var got []Orphan
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("failed to unmarshal JSON output: %v\n%s", err, buf.String())
+		}
+		return got, nil
+	}
+
+	t.Run("by flag and marker", func(t *testing.T) {
+		got, err := runPublicAPI(t, []string{"example.com/publicapi/api/..."})
+		if err != nil {
+			t.Fatalf("run() failed: %v", err)
+		}
+		var orphans, exported []string
+		for _, o := range got {
+			if o.Exported {
+				exported = append(exported, o.Name)
+			} else {
+				orphans = append(orphans, o.Name)
+			}
+		}
+		sort.Strings(orphans)
+		sort.Strings(exported)
+		if diff := cmp.Diff([]string{"example.com/publicapi/api.unused", "example.com/publicapi/internal/impl.dead"}, orphans); diff != "" {
+			t.Errorf("orphans mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"example.com/publicapi/internal/impl.Forgotten"}, exported); diff != "" {
+			t.Errorf("exported orphans mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("by marker only", func(t *testing.T) {
+		got, err := runPublicAPI(t, nil)
+		if err != nil {
+			t.Fatalf("run() failed: %v", err)
+		}
+		var names []string
+		for _, o := range got {
+			names = append(names, o.Name)
+		}
+		sort.Strings(names)
+		// The functions of api are not a part of the public API anymore.
+		want := []string{
+			"example.com/publicapi/api.Do",
+			"example.com/publicapi/api.unused",
+			"example.com/publicapi/internal/impl.Forgotten",
+			"example.com/publicapi/internal/impl.Used",
+			"example.com/publicapi/internal/impl.dead",
+			"example.com/publicapi/internal/impl.helper",
+		}
+		if diff := cmp.Diff(want, names); diff != "" {
+			t.Errorf("orphans mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+}
+
+func TestFindOrphans_publicAPINotFound(t *testing.T) {
+	files := map[string]string{
+		"go.mod":     "module example.com/nopublic\ngo 1.21\n",
+		"lib/lib.go": "package lib\nfunc Do() {}\n",
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		JSON:          true,
+		Mode:          "public-api",
+		StartPatterns: []string{"example.com/nopublic/..."},
+	})
+	if err == nil || !strings.Contains(err.Error(), "no public API package was found") {
+		t.Errorf("expected an error about the missing public API, got %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"go/token"
+	"log/slog"
+	"sort"
+
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/evaluator"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// interfaceUse is a value of a type of the scan packages passed as an argument of an interface
+// type, recorded with -interface-satisfaction.
+type interfaceUse struct {
+	impl  *scanner.TypeInfo
+	iface *scanner.TypeInfo
+	pos   token.Pos // the position of the call
+}
+
+func (u interfaceUse) key() string {
+	return u.impl.PkgPath + "." + u.impl.Name + " " + u.iface.PkgPath + "." + u.iface.Name
+}
+
+// recordInterfaceUses records the arguments of the call (args[0] being the callee) whose types
+// are declared in the scan packages, and which are passed as parameters of interface types.
+func (a *analyzer) recordInterfaceUses(ctx context.Context, args []object.Object) {
+	if len(args) < 2 {
+		return
+	}
+	def := a.calleeDef(ctx, args[0])
+	if def == nil || len(def.Parameters) == 0 {
+		return
+	}
+	pos, _ := evaluator.CallPosFromContext(ctx)
+	for i, arg := range args[1:] {
+		var paramType *scanner.FieldType
+		last := len(def.Parameters) - 1
+		switch {
+		case def.IsVariadic && i >= last:
+			if _, ok := arg.(*object.Variadic); ok {
+				continue // the elements of a slice are not traced
+			}
+			paramType = def.Parameters[last].Type
+			if paramType != nil && paramType.IsSlice && paramType.Elem != nil {
+				paramType = paramType.Elem
+			}
+		case i <= last:
+			paramType = def.Parameters[i].Type
+		}
+		if paramType == nil || paramType.IsPointer || paramType.IsSlice || paramType.IsMap {
+			continue
+		}
+		iface, err := paramType.Resolve(ctx)
+		if err != nil || iface == nil || iface.Kind != scanner.InterfaceKind {
+			continue
+		}
+		impl := concreteType(arg)
+		if impl == nil || impl.Kind == scanner.InterfaceKind || !a.scanPackages[impl.PkgPath] {
+			continue
+		}
+		use := interfaceUse{impl: impl, iface: iface, pos: pos}
+		if a.interfaceUses == nil {
+			a.interfaceUses = make(map[string]interfaceUse)
+		}
+		if _, seen := a.interfaceUses[use.key()]; !seen {
+			slog.DebugContext(ctx, "value passed as an interface", "type", impl.PkgPath+"."+impl.Name, "interface", iface.PkgPath+"."+iface.Name)
+			a.interfaceUses[use.key()] = use
+		}
+	}
+}
+
+// calleeDef returns the declaration of the called function, or nil if it is unknown. The
+// functions of the packages out of the scan policy are looked up in their packages.
+func (a *analyzer) calleeDef(ctx context.Context, fn object.Object) *scanner.FunctionInfo {
+	switch fn := fn.(type) {
+	case *object.Function:
+		return fn.Def
+	case *object.InstantiatedFunction:
+		return a.calleeDef(ctx, fn.Function)
+	case *object.SymbolicPlaceholder:
+		return fn.UnderlyingFunc
+	case *object.UnresolvedFunction:
+		pkg, ok := a.packages[fn.PkgPath]
+		if !ok {
+			var err error
+			if pkg, err = a.s.ScanPackageFromImportPath(ctx, fn.PkgPath); err != nil {
+				slog.DebugContext(ctx, "could not scan the package of the called function", "package", fn.PkgPath, "error", err)
+				return nil
+			}
+		}
+		for _, f := range pkg.Functions {
+			if f.Name == fn.FuncName && f.Receiver == nil {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// concreteType returns the type of the value of the argument, dereferencing a pointer.
+func concreteType(arg object.Object) *scanner.TypeInfo {
+	for {
+		switch v := arg.(type) {
+		case *object.Variable:
+			if v.Value == nil {
+				return v.TypeInfo()
+			}
+			arg = v.Value
+			continue
+		case *object.Pointer:
+			if ti := v.Value.TypeInfo(); ti != nil {
+				return ti
+			}
+			return v.TypeInfo()
+		case nil:
+			return nil
+		}
+		return arg.TypeInfo()
+	}
+}
+
+// analyzeInterfaceUses analyzes the methods of the types used through interfaces as entry
+// points, until no new ones are found, as they may pass other values as interfaces. It returns
+// the methods keyed by their full names, with the use found first, and nothing without
+// -interface-satisfaction.
+func (a *analyzer) analyzeInterfaceUses(ctx context.Context, interp *symgo.Interpreter, analyze func(*object.Function)) map[string]interfaceUse {
+	usedVia := make(map[string]interfaceUse)
+	if !a.ifaceSatisfaction {
+		return usedVia
+	}
+	if a.interfaceUsesDone == nil {
+		a.interfaceUsesDone = make(map[string]bool)
+	}
+	for {
+		a.calledInterfaceMethods = make(map[string]bool)
+		for _, key := range interp.CalledInterfaceMethods() {
+			a.calledInterfaceMethods[key] = true
+		}
+		methods := a.satisfyingMethods(ctx, a.interfaceUsesDone)
+		if len(methods) == 0 {
+			return usedVia
+		}
+		names := make([]string, 0, len(methods))
+		for name := range methods {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			m := methods[name]
+			if _, ok := usedVia[name]; !ok {
+				usedVia[name] = m.use
+			}
+			obj, ok := interp.FindFunction(ctx, m.pkg.ImportPath, m.fn)
+			if !ok {
+				continue
+			}
+			if fn, ok := obj.(*object.Function); ok {
+				analyze(fn)
+			}
+		}
+	}
+}
+
+// methodUse is a method of a type used through an interface.
+type methodUse struct {
+	use interfaceUse
+	pkg *scanner.PackageInfo
+	fn  *scanner.FunctionInfo
+}
+
+// satisfyingMethods returns the methods of the types used through interfaces whose methods are
+// called, i.e. are called in the analyzed code, or belong to an interface declared out of the
+// scan packages (whose calls are not visible). They are keyed by their full names, with the first
+// use found. The pairs of a use and a method already in done are skipped, and the others are
+// added to it.
+func (a *analyzer) satisfyingMethods(ctx context.Context, done map[string]bool) map[string]methodUse {
+	embedders := a.buildInterfaceEmbedders(ctx)
+	keys := make([]string, 0, len(a.interfaceUses))
+	for key := range a.interfaceUses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	methods := make(map[string]methodUse)
+	for _, key := range keys {
+		use := a.interfaceUses[key]
+		pkg, ok := a.packages[use.impl.PkgPath]
+		if !ok {
+			continue
+		}
+		ifaceName := use.iface.PkgPath + "." + use.iface.Name
+		outOfScope := !a.scanPackages[use.iface.PkgPath]
+		for methodName := range a.typeMethodSet(ctx, use.iface, make(map[string]bool)) {
+			if done[key+" "+methodName] {
+				continue
+			}
+			if !outOfScope && !a.isInterfaceMethodCalled(ifaceName, methodName, embedders, make(map[string]bool)) {
+				continue
+			}
+			done[key+" "+methodName] = true
+			for _, fn := range pkg.Functions {
+				if fn.Name != methodName || fn.Receiver == nil {
+					continue
+				}
+				recv := fn.Receiver.Type
+				if recv.Name == use.impl.Name || (recv.IsPointer && recv.Elem != nil && recv.Elem.Name == use.impl.Name) {
+					if name := getFullName(pkg, fn); methods[name].fn == nil {
+						methods[name] = methodUse{use: use, pkg: pkg, fn: fn}
+					}
+					break
+				}
+			}
+		}
+	}
+	return methods
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestFindOrphans_interfaceSatisfaction(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/ifaceuse\ngo 1.21\n",
+		"main.go": `
+package main
+
+import (
+	"net/http"
+
+	"example.com/ifaceuse/lib"
+)
+
+type handler struct{}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	render(w)
+}
+
+func (h *handler) unused() {}
+
+func render(w http.ResponseWriter) {}
+
+type greeter struct{}
+
+func (g greeter) Greet() string { return "hello" }
+
+func (g greeter) Bye() string { return "bye" }
+
+func main() {
+	http.Handle("/", &handler{})
+	lib.Run(greeter{})
+}
+`,
+		"lib/lib.go": `
+package lib
+
+type Greeter interface {
+	Greet() string
+	Bye() string
+}
+
+func Run(g Greeter) {
+	println(g.Greet())
+}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	runJSON := func(t *testing.T, interfaceSatisfaction bool) []Orphan {
+		t.Helper()
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := run(context.Background(), runConfig{
+			All:                   true,
+			Workspace:             dir,
+			JSON:                  true,
+			Mode:                  "auto",
+			StartPatterns:         []string{"example.com/ifaceuse/..."},
+			ExcludeDirs:           []string{"testdata", "vendor"},
+			InterfaceSatisfaction: interfaceSatisfaction,
+		})
+		w.Close()
+		os.Stdout = oldStdout
+		if err != nil {
+			t.Fatalf("run() failed: %v", err)
+		}
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		var orphans []Orphan
+		if err := json.Unmarshal(buf.Bytes(), &orphans); err != nil {
+			t.Fatalf("failed to unmarshal JSON output: %v\n%s", err, buf.String())
+		}
+		return orphans
+	}
+	names := func(orphans []Orphan, usedVia bool) []string {
+		var names []string
+		for _, o := range orphans {
+			if (o.UsedVia != "") == usedVia {
+				names = append(names, o.Name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		got := runJSON(t, false)
+		want := []string{
+			"(*example.com/ifaceuse.handler).ServeHTTP",
+			"(*example.com/ifaceuse.handler).unused",
+			"(example.com/ifaceuse.greeter).Bye",
+			"example.com/ifaceuse.render",
+		}
+		if diff := cmp.Diff(want, names(got, false)); diff != "" {
+			t.Errorf("orphans mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		got := runJSON(t, true)
+		wantOrphans := []string{
+			"(*example.com/ifaceuse.handler).unused",
+			"(example.com/ifaceuse.greeter).Bye", // lib.Greeter.Bye is never called
+		}
+		if diff := cmp.Diff(wantOrphans, names(got, false)); diff != "" {
+			t.Errorf("orphans mismatch (-want +got):\n%s", diff)
+		}
+		wantUsedVia := []string{"(*example.com/ifaceuse.handler).ServeHTTP"}
+		if diff := cmp.Diff(wantUsedVia, names(got, true)); diff != "" {
+			t.Errorf("used via interface mismatch (-want +got):\n%s", diff)
+		}
+		for _, o := range got {
+			if o.UsedVia == "" {
+				continue
+			}
+			if want := "net/http.Handler"; o.UsedVia != want {
+				t.Errorf("%s: want used via %s, got %s", o.Name, want, o.UsedVia)
+			}
+			if want := filepath.Join(dir, "main.go") + ":27:2"; o.AssignedAt != want {
+				t.Errorf("%s: want assigned at %s, got %s", o.Name, want, o.AssignedAt)
+			}
+		}
+	})
+}
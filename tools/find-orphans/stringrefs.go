@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/token"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// stringRefsConfig holds the options of -heuristic-strings.
+type stringRefsConfig struct {
+	// Files are the glob patterns of the non-Go files to search too, matched against the base
+	// names of the files in the modules, e.g. "*.tmpl" for the templates calling methods by name.
+	Files []string
+}
+
+// identChainPattern matches identifiers and dotted chains of identifiers, e.g. "Arith.Multiply".
+var identChainPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*`)
+
+// stringRefs indexes the words of the string literals (struct tags included) and of the extra
+// files, with the position of their first occurrence. A word is an identifier, or two
+// identifiers joined with a dot, for the "Type.Method" names of RPC registrations.
+type stringRefs map[string]string
+
+func (r stringRefs) add(text string, position func(offset int) string) {
+	for _, loc := range identChainPattern.FindAllStringIndex(text, -1) {
+		parts := strings.Split(text[loc[0]:loc[1]], ".")
+		for i, part := range parts {
+			if _, ok := r[part]; !ok {
+				r[part] = position(loc[0])
+			}
+			if i > 0 {
+				pair := parts[i-1] + "." + part
+				if _, ok := r[pair]; !ok {
+					r[pair] = position(loc[0])
+				}
+			}
+		}
+	}
+}
+
+// collectStringRefs collects the words of the string literals of the scanned packages, and of
+// the files matching cfg.Files under the module roots.
+func (a *analyzer) collectStringRefs(ctx context.Context, cfg *stringRefsConfig) stringRefs {
+	refs := make(stringRefs)
+	fset := a.s.Fset()
+	// The packages and files are searched in order, so that the first mention is the same on every run.
+	for _, path := range slices.Sorted(maps.Keys(a.packages)) {
+		for _, file := range a.packages[path].SortedAstFiles() {
+			ast.Inspect(file, func(n ast.Node) bool {
+				lit, ok := n.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				text, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return true
+				}
+				refs.add(text, func(int) string { return fset.Position(lit.Pos()).String() })
+				return true
+			})
+		}
+	}
+
+	if len(cfg.Files) == 0 {
+		return refs
+	}
+	excluded := make(map[string]bool, len(a.excludeDirs))
+	for _, dir := range a.excludeDirs {
+		excluded[dir] = true
+	}
+	for _, root := range a.s.ModuleRoots() {
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (excluded[d.Name()] || d.Name() == "vendor" || strings.HasPrefix(d.Name(), ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !matchesAnyGlob(cfg.Files, d.Name()) {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				slog.WarnContext(ctx, "could not read file for string references", "path", path, "error", err)
+				return nil
+			}
+			slog.DebugContext(ctx, "searching file for string references", "path", path)
+			refs.add(string(content), func(offset int) string {
+				return path + ":" + strconv.Itoa(bytes.Count(content[:offset], []byte("\n"))+1)
+			})
+			return nil
+		})
+		if err != nil {
+			slog.WarnContext(ctx, "could not walk module for string references", "root", root, "error", err)
+		}
+	}
+	return refs
+}
+
+func matchesAnyGlob(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the position of the first string mentioning the orphan, if any. Only exported
+// names are searched, as the string-based registrations (template FuncMap, RPC name maps, ...)
+// look them up by name. A method or a member also matches as "Type.Name".
+func (r stringRefs) find(o Orphan) (string, bool) {
+	typeName, name := orphanNames(o)
+	if !ast.IsExported(name) {
+		return "", false
+	}
+	if typeName != "" {
+		if pos, ok := r[typeName+"."+name]; ok {
+			return pos, true
+		}
+	}
+	pos, ok := r[name]
+	return pos, ok
+}
+
+// orphanNames returns the name of the orphan, and the name of its type for a method or a member.
+func orphanNames(o Orphan) (typeName, name string) {
+	if o.decl != nil {
+		cn := o.decl.CanonicalName()
+		return cn.TypeName, cn.Name
+	}
+	// A member is named "(pkg.Type).Name".
+	i := strings.LastIndex(o.Name, ").")
+	if !strings.HasPrefix(o.Name, "(") || i < 0 {
+		return "", o.Name[strings.LastIndex(o.Name, ".")+1:]
+	}
+	typeName = strings.TrimPrefix(o.Name[1:i], "*")
+	return typeName[strings.LastIndex(typeName, ".")+1:], o.Name[i+2:]
+}
+
+// demoteStringRefs moves the orphans mentioned in the strings to the referenced list.
+func demoteStringRefs(refs stringRefs, orphans []Orphan, referenced *[]Orphan) []Orphan {
+	var kept []Orphan
+	for _, o := range orphans {
+		if pos, ok := refs.find(o); ok {
+			o.ReferencedBy = pos
+			*referenced = append(*referenced, o)
+			continue
+		}
+		kept = append(kept, o)
+	}
+	return kept
+}
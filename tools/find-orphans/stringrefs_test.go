@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestFindOrphans_heuristicStrings(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/strrefs\ngo 1.21\n",
+		"main.go": `
+package main
+
+import "example.com/strrefs/lib"
+
+var method = "Arith.Multiply"
+
+func main() {
+	lib.Used()
+	println(method, "helper")
+	_ = lib.Form{}
+}
+`,
+		"lib/lib.go": `
+package lib
+
+type Arith struct{}
+
+func (a *Arith) Multiply() {}
+
+func FormatDate() string { return "" }
+
+func Used() {}
+
+func Unused() {}
+
+func helper() {}
+
+// Confirm is used as a tagged field of a used type, and its tag mentions Password.
+type Form struct {
+	Password string
+	Confirm  string ` + "`validate:\"eqfield=Password\"`" + `
+}
+`,
+		"views/index.tmpl": "<p>\n{{ FormatDate }}\n</p>\n",
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	runJSON := func(cfg *stringRefsConfig) []Orphan {
+		t.Helper()
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := run(context.Background(), runConfig{
+			All:           true,
+			Workspace:     dir,
+			JSON:          true,
+			Mode:          "auto",
+			StartPatterns: []string{"example.com/strrefs/..."},
+			ExcludeDirs:   []string{"testdata", "vendor"},
+			Members:       true,
+			StringRefs:    cfg,
+		})
+		w.Close()
+		os.Stdout = oldStdout
+		if err != nil {
+			t.Fatalf("run() failed: %v", err)
+		}
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		var orphans []Orphan
+		if err := json.Unmarshal(buf.Bytes(), &orphans); err != nil {
+			t.Fatalf("failed to unmarshal JSON output: %v\n%s", err, buf.String())
+		}
+		return orphans
+	}
+	names := func(orphans []Orphan, referenced bool) []string {
+		var names []string
+		for _, o := range orphans {
+			if (o.ReferencedBy != "") == referenced {
+				names = append(names, o.Name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		got := runJSON(nil)
+		want := []string{
+			"(*example.com/strrefs/lib.Arith).Multiply",
+			"(example.com/strrefs/lib.Form).Password",
+			"example.com/strrefs/lib.FormatDate",
+			"example.com/strrefs/lib.Unused",
+			"example.com/strrefs/lib.helper",
+		}
+		if diff := cmp.Diff(want, names(got, false)); diff != "" {
+			t.Errorf("orphans mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		got := runJSON(&stringRefsConfig{Files: []string{"*.tmpl"}})
+		wantOrphans := []string{
+			"example.com/strrefs/lib.Unused",
+			"example.com/strrefs/lib.helper", // unexported names are not looked up by string
+		}
+		if diff := cmp.Diff(wantOrphans, names(got, false)); diff != "" {
+			t.Errorf("orphans mismatch (-want +got):\n%s", diff)
+		}
+		wantReferenced := []string{
+			"(*example.com/strrefs/lib.Arith).Multiply",
+			"(example.com/strrefs/lib.Form).Password",
+			"example.com/strrefs/lib.FormatDate",
+		}
+		if diff := cmp.Diff(wantReferenced, names(got, true)); diff != "" {
+			t.Errorf("referenced by string mismatch (-want +got):\n%s", diff)
+		}
+
+		for _, o := range got {
+			if o.Name == "example.com/strrefs/lib.FormatDate" {
+				if want := filepath.Join(dir, "views", "index.tmpl") + ":2"; o.ReferencedBy != want {
+					t.Errorf("FormatDate: want referenced at %s, got %s", want, o.ReferencedBy)
+				}
+			}
+			if o.Name == "(*example.com/strrefs/lib.Arith).Multiply" && !strings.HasPrefix(o.ReferencedBy, filepath.Join(dir, "main.go")+":") {
+				t.Errorf("Multiply: want referenced in main.go, got %s", o.ReferencedBy)
+			}
+		}
+	})
+}
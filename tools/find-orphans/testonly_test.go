@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestFindOrphans_testOnly(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/testonly\ngo 1.21\n",
+		"main.go": `
+package main
+import "example.com/testonly/lib"
+func main() { lib.Run() }
+`,
+		"lib/lib.go": `
+package lib
+
+func Run() { used() }
+func used() {}
+
+func forInternalTest() {}
+func ForExternalTest() {}
+
+func unused() {}
+`,
+		"lib/lib_test.go": `
+package lib
+import "testing"
+func TestInternal(t *testing.T) { forInternalTest() }
+func helper() {}
+`,
+		"lib/external_test.go": `
+package lib_test
+import (
+	"testing"
+	"example.com/testonly/lib"
+)
+func TestExternal(t *testing.T) { lib.ForExternalTest() }
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := run(context.Background(), runConfig{
+		All:           true,
+		IncludeTests:  true,
+		Workspace:     dir,
+		JSON:          true,
+		Mode:          "auto",
+		StartPatterns: []string{"example.com/testonly/..."},
+		ExcludeDirs:   []string{"testdata", "vendor"},
+		TestOnly:      true,
+	})
+	w.Close()
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	var got []Orphan
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal JSON output: %v\n%s", err, buf.String())
+	}
+	var orphans, testOnly []string
+	for _, o := range got {
+		if o.UsedOnlyInTests {
+			testOnly = append(testOnly, o.Name)
+		} else {
+			orphans = append(orphans, o.Name)
+		}
+	}
+	sort.Strings(orphans)
+	sort.Strings(testOnly)
+
+	if diff := cmp.Diff([]string{"example.com/testonly/lib.helper", "example.com/testonly/lib.unused"}, orphans); diff != "" {
+		t.Errorf("orphans mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"example.com/testonly/lib.ForExternalTest", "example.com/testonly/lib.forInternalTest"}, testOnly); diff != "" {
+		t.Errorf("functions used only from tests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFindOrphans_testOnlyRequiresIncludeTests(t *testing.T) {
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     ".",
+		JSON:          true,
+		Mode:          "auto",
+		StartPatterns: []string{"./..."},
+		TestOnly:      true,
+	})
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
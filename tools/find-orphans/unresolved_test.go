@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestFindOrphans_unresolvedCalls(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/unresolved\ngo 1.21\n",
+		"main.go": `
+package main
+
+import "example.com/missing/lib"
+
+func main() {
+	conn := lib.Open()
+	conn.Close()
+}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	runStderr := func(t *testing.T, verbose bool) string {
+		t.Helper()
+		oldStdout, oldStderr := os.Stdout, os.Stderr
+		r, w, _ := os.Pipe()
+		os.Stderr = w
+		os.Stdout, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		err := run(context.Background(), runConfig{
+			All:           true,
+			Workspace:     dir,
+			Verbose:       verbose,
+			JSON:          true,
+			Mode:          "auto",
+			StartPatterns: []string{"example.com/unresolved/..."},
+			ExcludeDirs:   []string{"testdata", "vendor"},
+		})
+		w.Close()
+		os.Stdout.Close()
+		os.Stdout, os.Stderr = oldStdout, oldStderr
+		if err != nil {
+			t.Fatalf("run() failed: %v", err)
+		}
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		return buf.String()
+	}
+
+	t.Run("summary", func(t *testing.T) {
+		got := runStderr(t, false)
+		if want := "2 call sites could not be resolved (out-of-policy: 1, unsupported: 1)"; !strings.Contains(got, want) {
+			t.Errorf("stderr does not contain %q:\n%s", want, got)
+		}
+		if strings.Contains(got, "lib.Open") {
+			t.Errorf("the call sites are listed without -v:\n%s", got)
+		}
+	})
+	t.Run("verbose", func(t *testing.T) {
+		got := runStderr(t, true)
+		for _, want := range []string{"main.go:7:10: lib.Open [out-of-policy]", "main.go:8:2: conn.Close [unsupported]"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("stderr does not contain %q:\n%s", want, got)
+			}
+		}
+	})
+}
+
+func TestFindOrphans_abortedFunctions(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/aborted\ngo 1.21\n",
+		"main.go": `
+package main
+
+func main() {
+	run()
+}
+
+func run() {
+	x := undefined
+	helper(x)
+}
+
+func helper(x int) {}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	os.Stdout, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	err := run(context.Background(), runConfig{
+		All:           true,
+		Workspace:     dir,
+		Verbose:       true,
+		JSON:          true,
+		Mode:          "auto",
+		StartPatterns: []string{"example.com/aborted/..."},
+		ExcludeDirs:   []string{"testdata", "vendor"},
+	})
+	w.Close()
+	os.Stdout.Close()
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+	if err != nil {
+		t.Fatalf("run() failed: %v", err)
+	}
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	got := buf.String()
+	for _, want := range []string{"2 functions were only partially explored", "main.go:8:1: example.com/aborted.run (statements: 1/2, branches: 0/0)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("stderr does not contain %q:\n%s", want, got)
+		}
+	}
+}
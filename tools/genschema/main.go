@@ -201,7 +201,8 @@ func (g *Generator) Generate(ctx context.Context, ob *scanner.TypeInfo) (*ordere
 
 func (g *Generator) generateSchemaForTypeInfo(ctx context.Context, ob *scanner.TypeInfo) (*orderedmap.OrderedMap, error) {
 	// Use the pre-computed enum information from go-scan.
-	if ob.IsEnum {
+	// The values of bitflags are combinations of the members, which cannot be listed.
+	if ob.IsEnum && ob.EnumKind != scanner.BitFlagsEnumKind {
 		var enumValues []any
 		for _, member := range ob.EnumMembers {
 			if member.ConstVal == nil {
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/podhmo/go-scan/scanner"
+)
+
+const browserHelp = `commands:
+  <n>        expand or collapse node n
+  e <n>      expand node n
+  c <n>      collapse node n (without n, collapse all)
+  g <n>      show the definition position of node n
+  / <text>   search functions by name, and expand the tree to show the matches
+  a          show or hide accessors
+  r          show or hide recursive calls
+  ?          show this help
+  q          quit`
+
+// browseNode is a node of the call tree explored by the Browser.
+// The same function may appear in several nodes, once per call path.
+type browseNode struct {
+	fn        *scanner.FunctionInfo
+	parent    *browseNode
+	depth     int
+	recursive bool // The function is already on the path from the root, so it has no children.
+	expanded  bool
+	built     bool // Whether children has been built; this is done on first use.
+	children  []*browseNode
+}
+
+// Browser explores the call graph interactively: it renders the visible part of the
+// call tree, then reads a command from In and applies it, until `q` or the end of In.
+// It works on any terminal without a dependency on a TUI library, as commands are
+// read line by line.
+type Browser struct {
+	Graph   callGraph
+	Printer *Printer // Formats the functions, as in the non-interactive output.
+	Fset    *token.FileSet
+	In      io.Reader
+	Out     io.Writer
+	Clear   bool // Clear the screen before each render, for terminals.
+
+	HideAccessors bool
+	HideRecursive bool
+
+	roots   []*browseNode
+	query   string
+	matches map[*browseNode]bool
+	message string
+}
+
+// Run starts the interactive session for the given entry points.
+func (b *Browser) Run(entryPoints []*scanner.FunctionInfo) error {
+	sort.Slice(entryPoints, func(i, j int) bool {
+		return getFuncID(entryPoints[i]) < getFuncID(entryPoints[j])
+	})
+	b.roots = nil
+	for _, f := range entryPoints {
+		b.roots = append(b.roots, &browseNode{fn: f})
+	}
+
+	in := bufio.NewScanner(b.In)
+	for {
+		b.render()
+		fmt.Fprint(b.Out, "> ")
+		if !in.Scan() {
+			fmt.Fprintln(b.Out)
+			return in.Err()
+		}
+		if quit := b.exec(strings.TrimSpace(in.Text())); quit {
+			return nil
+		}
+	}
+}
+
+// exec applies a single command. It returns true if the session should end.
+func (b *Browser) exec(line string) bool {
+	b.message = ""
+	cmd, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	if strings.HasPrefix(cmd, "/") && len(cmd) > 1 { // "/text" without a space
+		cmd, arg = "/", strings.TrimSpace(line[1:])
+	}
+
+	switch cmd {
+	case "":
+	case "q":
+		return true
+	case "?", "h":
+		b.message = browserHelp
+	case "a":
+		b.HideAccessors = !b.HideAccessors
+	case "r":
+		b.HideRecursive = !b.HideRecursive
+	case "c":
+		if arg == "" {
+			b.walkBuilt(func(n *browseNode) { n.expanded = false })
+			return false
+		}
+		if n := b.node(arg); n != nil {
+			n.expanded = false
+		}
+	case "e":
+		if n := b.node(arg); n != nil {
+			b.expand(n)
+		}
+	case "g":
+		if n := b.node(arg); n != nil {
+			b.message = b.position(n.fn)
+		}
+	case "/":
+		b.search(arg)
+	default:
+		if _, err := strconv.Atoi(cmd); err != nil {
+			b.message = fmt.Sprintf("unknown command %q (type ? for help)", line)
+			return false
+		}
+		if n := b.node(cmd); n != nil {
+			if n.expanded {
+				n.expanded = false
+			} else {
+				b.expand(n)
+			}
+		}
+	}
+	return false
+}
+
+// node returns the visible node numbered by arg, or nil with a message.
+func (b *Browser) node(arg string) *browseNode {
+	visible := b.visible()
+	i, err := strconv.Atoi(arg)
+	if err != nil || i < 1 || i > len(visible) {
+		b.message = fmt.Sprintf("no such node %q", arg)
+		return nil
+	}
+	return visible[i-1]
+}
+
+func (b *Browser) expand(n *browseNode) {
+	if len(b.children(n)) == 0 {
+		b.message = fmt.Sprintf("%s has no callees", b.Printer.formatFunc(n.fn))
+		return
+	}
+	n.expanded = true
+}
+
+// children builds the callees of the node, deduplicated and sorted as in the Printer.
+func (b *Browser) children(n *browseNode) []*browseNode {
+	if n.built || n.recursive {
+		return n.children
+	}
+	n.built = true
+
+	onPath := make(map[string]bool)
+	for p := n; p != nil; p = p.parent {
+		onPath[getFuncID(p.fn)] = true
+	}
+	seen := make(map[string]bool)
+	var callees []*scanner.FunctionInfo
+	for _, callee := range b.Graph[n.fn] {
+		if id := getFuncID(callee); !seen[id] {
+			seen[id] = true
+			callees = append(callees, callee)
+		}
+	}
+	sort.Slice(callees, func(i, j int) bool {
+		return getFuncID(callees[i]) < getFuncID(callees[j])
+	})
+	for _, callee := range callees {
+		n.children = append(n.children, &browseNode{
+			fn:        callee,
+			parent:    n,
+			depth:     n.depth + 1,
+			recursive: onPath[getFuncID(callee)],
+		})
+	}
+	return n.children
+}
+
+func (b *Browser) hidden(n *browseNode) bool {
+	return (b.HideAccessors && isAccessor(n.fn)) || (b.HideRecursive && n.recursive)
+}
+
+// visible returns the nodes to render, in order.
+func (b *Browser) visible() []*browseNode {
+	var nodes []*browseNode
+	var walk func(n *browseNode)
+	walk = func(n *browseNode) {
+		if b.hidden(n) {
+			return
+		}
+		nodes = append(nodes, n)
+		if n.expanded {
+			for _, child := range b.children(n) {
+				walk(child)
+			}
+		}
+	}
+	for _, root := range b.roots {
+		walk(root)
+	}
+	return nodes
+}
+
+// walkBuilt calls fn for every node built so far.
+func (b *Browser) walkBuilt(fn func(n *browseNode)) {
+	var walk func(n *browseNode)
+	walk = func(n *browseNode) {
+		fn(n)
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	for _, root := range b.roots {
+		walk(root)
+	}
+}
+
+// search marks the nodes whose function matches the query (case-insensitively) and expands
+// their ancestors. Each function is only searched below its first occurrence in the tree,
+// which keeps the search linear in the size of the graph.
+func (b *Browser) search(query string) {
+	b.query = query
+	b.matches = nil
+	if query == "" {
+		return
+	}
+	b.matches = make(map[*browseNode]bool)
+	lower := strings.ToLower(query)
+	seen := make(map[string]bool)
+	var walk func(n *browseNode)
+	walk = func(n *browseNode) {
+		id := getFuncID(n.fn)
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		if strings.Contains(strings.ToLower(b.Printer.formatFunc(n.fn)), lower) {
+			b.matches[n] = true
+			for p := n.parent; p != nil; p = p.parent {
+				p.expanded = true
+			}
+		}
+		for _, child := range b.children(n) {
+			walk(child)
+		}
+	}
+	for _, root := range b.roots {
+		walk(root)
+	}
+	if len(b.matches) == 0 {
+		b.message = fmt.Sprintf("no functions match %q", query)
+	}
+}
+
+// position returns the definition position of the function, with its doc comment.
+func (b *Browser) position(f *scanner.FunctionInfo) string {
+	name := b.Printer.formatFunc(f)
+	if f.AstDecl == nil || b.Fset == nil {
+		return fmt.Sprintf("%s: no source position", name)
+	}
+	msg := fmt.Sprintf("%s\n  %s", name, b.Fset.Position(f.AstDecl.Pos()))
+	if doc := strings.TrimSpace(f.Doc); doc != "" {
+		msg += "\n  " + strings.ReplaceAll(doc, "\n", "\n  ")
+	}
+	return msg
+}
+
+func (b *Browser) render() {
+	if b.Clear {
+		fmt.Fprint(b.Out, "\x1b[H\x1b[2J")
+	}
+	var status []string
+	if b.HideAccessors {
+		status = append(status, "accessors hidden")
+	}
+	if b.HideRecursive {
+		status = append(status, "recursive calls hidden")
+	}
+	if b.query != "" {
+		status = append(status, fmt.Sprintf("search %q: %d matches", b.query, len(b.matches)))
+	}
+	header := "goinspect (type ? for help)"
+	if len(status) > 0 {
+		header += " [" + strings.Join(status, ", ") + "]"
+	}
+	fmt.Fprintln(b.Out, header)
+
+	for i, n := range b.visible() {
+		marker := " "
+		if !n.recursive && len(b.Graph[n.fn]) > 0 {
+			marker = "+"
+			if n.expanded {
+				marker = "-"
+			}
+		}
+		match := " "
+		if b.matches[n] {
+			match = "*"
+		}
+		var prefix string
+		if n.recursive {
+			prefix += "[recursive] "
+		}
+		if isAccessor(n.fn) {
+			prefix += "[accessor] "
+		}
+		fmt.Fprintf(b.Out, "%s%3d %s%s %s%s\n", match, i+1, strings.Repeat("  ", n.depth), marker, prefix, b.Printer.formatFunc(n.fn))
+	}
+	if b.message != "" {
+		fmt.Fprintln(b.Out, b.message)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/podhmo/go-scan/scanner"
+)
+
+func TestBrowser(t *testing.T) {
+	source := `package app
+
+// Run starts the app.
+func Run() { load(); save(); (&T{}).Name() }
+
+func load() { helper() }
+
+func save() { helper(); save() }
+
+func helper() {}
+
+type T struct{ name string }
+
+func (t *T) Name() string { return t.name }
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "app.go", source, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile() failed: %v", err)
+	}
+	funcs := make(map[string]*scanner.FunctionInfo)
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		f := &scanner.FunctionInfo{Name: fd.Name.Name, PkgPath: "example.com/app", AstDecl: fd, Doc: fd.Doc.Text()}
+		if fd.Recv != nil {
+			f.Receiver = &scanner.FieldInfo{Name: "t", Type: &scanner.FieldType{Name: "T", IsPointer: true}}
+			f.Results = []*scanner.FieldInfo{{Type: &scanner.FieldType{Name: "string"}}}
+		}
+		funcs[f.Name] = f
+	}
+	graph := callGraph{
+		funcs["Run"]:  {funcs["load"], funcs["save"], funcs["Name"]},
+		funcs["load"]: {funcs["helper"]},
+		funcs["save"]: {funcs["helper"], funcs["save"]},
+	}
+
+	commands := []string{
+		"1",       // expand Run
+		"a",       // hide the accessor
+		"/helper", // expand the tree to show helper
+		"r",       // hide the recursive call of save
+		"g 1",     // show the position of Run
+		"c",       // collapse all
+		"9",       // no such node
+		"x",       // unknown command
+		"q",
+	}
+	var out bytes.Buffer
+	b := &Browser{
+		Graph:   graph,
+		Printer: &Printer{Graph: graph, Short: true},
+		Fset:    fset,
+		In:      strings.NewReader(strings.Join(commands, "\n") + "\n"),
+		Out:     &out,
+	}
+	if err := b.Run([]*scanner.FunctionInfo{funcs["Run"]}); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	want := `goinspect (type ? for help)
+   1 + func example.com/app.Run(...)
+> goinspect (type ? for help)
+   1 - func example.com/app.Run(...)
+   2   + func example.com/app.save(...)
+   3     [accessor] func (*T).Name(...)
+   4   + func example.com/app.load(...)
+> goinspect (type ? for help) [accessors hidden]
+   1 - func example.com/app.Run(...)
+   2   + func example.com/app.save(...)
+   3   + func example.com/app.load(...)
+> goinspect (type ? for help) [accessors hidden, search "helper": 1 matches]
+   1 - func example.com/app.Run(...)
+   2   - func example.com/app.save(...)
+   3       [recursive] func example.com/app.save(...)
+*  4       func example.com/app.helper(...)
+   5   + func example.com/app.load(...)
+> goinspect (type ? for help) [accessors hidden, recursive calls hidden, search "helper": 1 matches]
+   1 - func example.com/app.Run(...)
+   2   - func example.com/app.save(...)
+*  3       func example.com/app.helper(...)
+   4   + func example.com/app.load(...)
+> goinspect (type ? for help) [accessors hidden, recursive calls hidden, search "helper": 1 matches]
+   1 - func example.com/app.Run(...)
+   2   - func example.com/app.save(...)
+*  3       func example.com/app.helper(...)
+   4   + func example.com/app.load(...)
+func example.com/app.Run(...)
+  app.go:4:1
+  Run starts the app.
+> goinspect (type ? for help) [accessors hidden, recursive calls hidden, search "helper": 1 matches]
+   1 + func example.com/app.Run(...)
+> goinspect (type ? for help) [accessors hidden, recursive calls hidden, search "helper": 1 matches]
+   1 + func example.com/app.Run(...)
+no such node "9"
+> goinspect (type ? for help) [accessors hidden, recursive calls hidden, search "helper": 1 matches]
+   1 + func example.com/app.Run(...)
+unknown command "x" (type ? for help)
+> `
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("browser output mismatch (-want +got):\n%s", diff)
+	}
+}
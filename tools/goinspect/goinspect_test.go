@@ -22,10 +22,16 @@ func TestGoInspect(t *testing.T) {
 		pkgPatterns       []string
 		withPatterns      []string
 		targets           []string
+		targetRegexps     []string
+		targetAnnotations []string
 		trimPrefix        bool
 		includeUnexported bool
 		shortFormat       bool
 		expandFormat      bool
+		showExternal      bool
+		aggregateExternal bool
+		showUnresolved    bool
+		showPartial       bool
 	}{
 		{
 			name:        "default",
@@ -69,6 +75,18 @@ func TestGoInspect(t *testing.T) {
 			pkgPatterns: []string{"./testdata/src/target"},
 			targets:     []string{"github.com/podhmo/go-scan/tools/goinspect/testdata/src/target.FuncA"},
 		},
+		{
+			name:          "target_regex",
+			pkgPatterns:   []string{"./testdata/src/selection"},
+			targetRegexps: []string{"(*Service).*"},
+			trimPrefix:    true,
+		},
+		{
+			name:              "target_annotation",
+			pkgPatterns:       []string{"./testdata/src/selection"},
+			targetAnnotations: []string{"@entrypoint"},
+			trimPrefix:        true,
+		},
 		{
 			name:        "trim_prefix",
 			pkgPatterns: []string{"./testdata/src/myapp"},
@@ -99,6 +117,30 @@ func TestGoInspect(t *testing.T) {
 			name:        "special_funcs",
 			pkgPatterns: []string{"./testdata/src/special/..."},
 		},
+		{
+			name:         "show_external",
+			pkgPatterns:  []string{"./testdata/src/myapp"},
+			showExternal: true,
+		},
+		{
+			name:              "aggregate_external",
+			pkgPatterns:       []string{"./testdata/src/external"},
+			trimPrefix:        true,
+			showExternal:      true,
+			aggregateExternal: true,
+		},
+		{
+			name:           "show_unresolved",
+			pkgPatterns:    []string{"./testdata/src/unresolved"},
+			trimPrefix:     true,
+			showUnresolved: true,
+		},
+		{
+			name:        "show_partial",
+			pkgPatterns: []string{"./testdata/src/partial"},
+			trimPrefix:  true,
+			showPartial: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -112,7 +154,20 @@ func TestGoInspect(t *testing.T) {
 			ctx := context.Background()
 			ctx = scanner.WithParallelismLimit(ctx, 1)
 
-			err := run(ctx, &buf, logger, tc.pkgPatterns, tc.withPatterns, tc.targets, tc.trimPrefix, tc.includeUnexported, tc.shortFormat, tc.expandFormat)
+			cfg := runConfig{
+				PkgPatterns:       tc.pkgPatterns,
+				WithPatterns:      tc.withPatterns,
+				Targets:           targetSelection{Names: tc.targets, Regexps: tc.targetRegexps, Annotations: tc.targetAnnotations},
+				TrimPrefix:        tc.trimPrefix,
+				IncludeUnexported: tc.includeUnexported,
+				Short:             tc.shortFormat,
+				Expand:            tc.expandFormat,
+				ShowExternal:      tc.showExternal,
+				AggregateExternal: tc.aggregateExternal,
+				ShowUnresolved:    tc.showUnresolved,
+				ShowPartial:       tc.showPartial,
+			}
+			err := run(ctx, &buf, nil, logger, cfg)
 			if err != nil {
 				t.Fatalf("run() failed: %v", err)
 			}
@@ -193,7 +248,16 @@ func TestGoInspect_NoModuleContext(t *testing.T) {
 			ctx := context.Background()
 			ctx = scanner.WithParallelismLimit(ctx, 1)
 
-			err := run(ctx, &buf, logger, tc.pkgPatterns, tc.withPatterns, tc.targets, tc.trimPrefix, tc.includeUnexported, tc.shortFormat, tc.expandFormat)
+			cfg := runConfig{
+				PkgPatterns:       tc.pkgPatterns,
+				WithPatterns:      tc.withPatterns,
+				Targets:           targetSelection{Names: tc.targets},
+				TrimPrefix:        tc.trimPrefix,
+				IncludeUnexported: tc.includeUnexported,
+				Short:             tc.shortFormat,
+				Expand:            tc.expandFormat,
+			}
+			err := run(ctx, &buf, nil, logger, cfg)
 			if err != nil {
 				t.Fatalf("run() failed: %v", err)
 			}
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"io"
 	"log"
 	"log/slog"
@@ -26,6 +27,16 @@ import (
 // The key is the caller function, and the value is a list of callee functions.
 type callGraph map[*scanner.FunctionInfo][]*scanner.FunctionInfo
 
+// externalCalls stores the calls of each function into the packages out of the analysis scope,
+// e.g. the standard library, which are not in the callGraph.
+type externalCalls map[*scanner.FunctionInfo][]externalCall
+
+// externalCall is a call into a package out of the analysis scope.
+type externalCall struct {
+	Name string    // The qualified name of the callee, e.g. "fmt.Printf".
+	Pos  token.Pos // The position of the call.
+}
+
 // stringSlice is a custom type to handle multiple string flags.
 type stringSlice []string
 
@@ -42,6 +53,10 @@ func main() {
 	// 1. Define and parse command-line flags.
 	var targets stringSlice
 	flag.Var(&targets, "target", "Target function or method to inspect (e.g., mypkg.MyFunc, (*mypkg.MyType).MyMethod). Can be specified multiple times.")
+	var targetRegexps stringSlice
+	flag.Var(&targetRegexps, "target-regex", "Regexp selecting the target functions and methods of the --pkg packages by name (e.g., '(*Service).*'). Can be specified multiple times.")
+	var targetAnnotations stringSlice
+	flag.Var(&targetAnnotations, "target-annotation", "Annotation in the doc comments selecting the target functions and methods of the --pkg packages (e.g., @entrypoint). Can be specified multiple times.")
 	var pkgPatterns stringSlice
 	flag.Var(&pkgPatterns, "pkg", "Go package pattern to inspect (e.g., ./...). This is the primary analysis scope and where entry points are found. Can be specified multiple times.")
 	var withPatterns stringSlice
@@ -50,6 +65,11 @@ func main() {
 	includeUnexported := flag.Bool("include-unexported", false, "Include unexported functions as entry points")
 	shortFormat := flag.Bool("short", false, "Use short format for output")
 	expandFormat := flag.Bool("expand", false, "Use expand format for output with UIDs")
+	tui := flag.Bool("tui", false, "Explore the call graph interactively")
+	showExternal := flag.Bool("show-external", false, "Show the calls into the packages out of the analysis scope as leaves")
+	aggregateExternal := flag.Bool("aggregate-external", false, "With -show-external, show the repeated external calls of a function once, with their count")
+	showUnresolved := flag.Bool("show-unresolved", false, "Show the calls whose results are unknown to the analysis, below which the call graph may be incomplete")
+	showPartial := flag.Bool("show-partial", false, "Show the functions whose bodies were only partially explored by the analysis, whose calls may be incomplete")
 	var logLevel = slog.LevelWarn
 	flag.TextVar(&logLevel, "log-level", &logLevel, "Log level (debug, info, warn, error)")
 
@@ -62,7 +82,24 @@ func main() {
 
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: &logLevel}))
 	slog.SetDefault(logger)
-	if err := run(context.Background(), os.Stdout, logger, pkgPatterns, withPatterns, targets, *trimPrefix, *includeUnexported, *shortFormat, *expandFormat); err != nil {
+	var in io.Reader
+	if *tui {
+		in = os.Stdin
+	}
+	cfg := runConfig{
+		PkgPatterns:       pkgPatterns,
+		WithPatterns:      withPatterns,
+		Targets:           targetSelection{Names: targets, Regexps: targetRegexps, Annotations: targetAnnotations},
+		TrimPrefix:        *trimPrefix,
+		IncludeUnexported: *includeUnexported,
+		Short:             *shortFormat,
+		Expand:            *expandFormat,
+		ShowExternal:      *showExternal,
+		AggregateExternal: *aggregateExternal,
+		ShowUnresolved:    *showUnresolved,
+		ShowPartial:       *showPartial,
+	}
+	if err := run(context.Background(), os.Stdout, in, logger, cfg); err != nil {
 		log.Fatalf("Error: %+v", err)
 	}
 }
@@ -143,7 +180,29 @@ func setupTempModule(ctx context.Context, logger *slog.Logger, pkgPatterns, with
 	return tmpDir, cleanup, nil
 }
 
-func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, withPatterns []string, targets []string, trimPrefix, includeUnexported, shortFormat, expandFormat bool) error {
+// runConfig holds the settings of a run, from the command-line flags of the same names.
+type runConfig struct {
+	PkgPatterns       []string // -pkg
+	WithPatterns      []string // -with
+	Targets           targetSelection
+	TrimPrefix        bool
+	IncludeUnexported bool
+	Short             bool
+	Expand            bool
+
+	// ShowExternal prints the calls into the packages out of the analysis scope as leaves.
+	ShowExternal      bool
+	AggregateExternal bool
+	// ShowUnresolved prints the calls whose results are unknown after the call graph.
+	ShowUnresolved bool
+	// ShowPartial prints the functions whose bodies were only partially explored after the call graph.
+	ShowPartial bool
+}
+
+// run analyzes the packages and prints the call graph to out.
+// If in is not nil, the call graph is explored interactively instead, reading commands from in.
+// If cfg.Targets selects functions with patterns, the selected entry points are printed before the call graph.
+func run(ctx context.Context, out io.Writer, in io.Reader, logger *slog.Logger, cfg runConfig) error {
 	inModuleMode := isModuleMode()
 	logger.Info("running context", "module_mode", inModuleMode)
 
@@ -154,7 +213,7 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, w
 
 	var cleanup func()
 	if !inModuleMode {
-		tmpDir, c, err := setupTempModule(ctx, logger, pkgPatterns, withPatterns)
+		tmpDir, c, err := setupTempModule(ctx, logger, cfg.PkgPatterns, cfg.WithPatterns)
 		if err != nil {
 			return fmt.Errorf("failed to setup temporary module: %w", err)
 		}
@@ -171,8 +230,8 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, w
 	}
 
 	var allPatterns []string
-	allPatterns = append(allPatterns, pkgPatterns...)
-	allPatterns = append(allPatterns, withPatterns...)
+	allPatterns = append(allPatterns, cfg.PkgPatterns...)
+	allPatterns = append(allPatterns, cfg.WithPatterns...)
 
 	var allScannedPkgs []*scanner.PackageInfo
 	for _, pkgPattern := range allPatterns {
@@ -205,7 +264,7 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, w
 	// 2c. To determine entry points, we need the set of packages specified with --pkg.
 	// We can get this by scanning just those patterns again; it will be fast due to caching.
 	entrypointPkgPaths := make(map[string]bool)
-	for _, pkgPattern := range pkgPatterns {
+	for _, pkgPattern := range cfg.PkgPatterns {
 		entryPkgs, err := s.Scan(ctx, pkgPattern) // This is fast due to the scanner's cache
 		if err != nil {
 			if strings.Contains(err.Error(), "no packages found") {
@@ -229,11 +288,43 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, w
 
 	// 3. Initialize symgo.Evaluator with a custom intrinsic.
 	graph := make(callGraph)
-	interp, err := symgo.NewInterpreter(s,
+	externals := make(externalCalls)
+
+	// The calls out of the scope are recorded from the trace, which has the positions of the calls,
+	// so that a call evaluated more than once (e.g. in a recursive function) is counted once.
+	// The default intrinsic, called just before the trace event of the same call, sets the caller.
+	var externalCaller *scanner.FunctionInfo
+	seenExternal := make(map[*scanner.FunctionInfo]map[token.Pos]bool)
+	tracer := &object.TraceHooks{
+		OnCallResolved: func(event object.TraceEvent) {
+			name, ok := externalName(event.Function, scanPolicy)
+			if !ok || externalCaller == nil {
+				return
+			}
+			if seenExternal[externalCaller] == nil {
+				seenExternal[externalCaller] = make(map[token.Pos]bool)
+			}
+			if seenExternal[externalCaller][event.Pos] {
+				return
+			}
+			seenExternal[externalCaller][event.Pos] = true
+			externals[externalCaller] = append(externals[externalCaller], externalCall{
+				Name: name,
+				Pos:  event.Pos,
+			})
+		},
+	}
+
+	interpOptions := []symgo.Option{
 		symgo.WithLogger(logger.WithGroup("symgo")),
 		symgo.WithScanPolicy(scanPolicy),
 		symgo.WithMemoization(true),
-	)
+		symgo.WithCoverage(true),
+	}
+	if cfg.ShowExternal {
+		interpOptions = append(interpOptions, symgo.WithTracer(tracer))
+	}
+	interp, err := symgo.NewInterpreter(s, interpOptions...)
 	if err != nil {
 		return fmt.Errorf("failed to create interpreter: %w", err)
 	}
@@ -245,13 +336,20 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, w
 		}
 
 		calleeObj := args[0]
+		externalCaller = nil
+		_, isExternal := externalName(calleeObj, scanPolicy)
+		if isExternal && callerFrame.Fn != nil {
+			externalCaller = callerFrame.Fn.Def
+		}
 		var calleeFunc *scanner.FunctionInfo
 
 		switch f := calleeObj.(type) {
 		case *object.Function:
 			calleeFunc = f.Def
 		case *object.SymbolicPlaceholder:
-			calleeFunc = f.UnderlyingFunc
+			if !isExternal {
+				calleeFunc = f.UnderlyingFunc
+			}
 		}
 
 		if callerFrame.Fn != nil && callerFrame.Fn.Def != nil && calleeFunc != nil {
@@ -277,22 +375,21 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, w
 
 	// 4. Determine entry point functions for analysis.
 	var entryPoints []*scanner.FunctionInfo
-	if len(targets) > 0 {
+	if !cfg.Targets.isEmpty() {
 		// If specific targets are provided, find them from the sorted list.
-		targetSet := make(map[string]bool)
-		for _, target := range targets {
-			targetSet[target] = true
+		entryPoints, err = cfg.Targets.match(allFunctions, entrypointPkgPaths)
+		if err != nil {
+			return err
 		}
 
-		for _, f := range allFunctions {
-			targetName := getFuncTargetName(f)
-			if targetSet[targetName] {
-				entryPoints = append(entryPoints, f)
-			}
+		if !cfg.Targets.isPattern() && len(entryPoints) != len(cfg.Targets.Names) {
+			logger.Warn("could not find all specified targets", "found", len(entryPoints), "wanted", len(cfg.Targets.Names))
 		}
-
-		if len(entryPoints) != len(targets) {
-			logger.Warn("could not find all specified targets", "found", len(entryPoints), "wanted", len(targets))
+		if cfg.Targets.isPattern() {
+			if len(entryPoints) == 0 {
+				return fmt.Errorf("no functions match the targets")
+			}
+			printSelectedTargets(out, entryPoints)
 		}
 
 	} else {
@@ -303,7 +400,7 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, w
 			if _, isEntryPointPkg := entrypointPkgPaths[f.PkgPath]; isEntryPointPkg {
 				// Special cases: `init` is always an entry point, and so is `main` in a `main` package.
 				isSpecial := (f.Name == "init") || (f.Name == "main" && f.Pkg.Name == "main")
-				if includeUnexported || ast.IsExported(f.Name) || isSpecial {
+				if cfg.IncludeUnexported || ast.IsExported(f.Name) || isSpecial {
 					entryPoints = append(entryPoints, f)
 				}
 			}
@@ -314,7 +411,7 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, w
 
 	// 5. Analyze all functions in a deterministic order.
 	for _, f := range allFunctions {
-		if !includeUnexported && !ast.IsExported(f.Name) {
+		if !cfg.IncludeUnexported && !ast.IsExported(f.Name) {
 			continue
 		}
 
@@ -333,6 +430,23 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, w
 		interp.Apply(ctx, fnObj, nil, f.Pkg)
 	}
 
+	// The methods called through interfaces are already in the graph, as the calls of the
+	// placeholders; Finalize is only for the report of the unresolved calls.
+	interp.Finalize(ctx)
+	unresolved := interp.UnresolvedCalls()
+	if len(unresolved) > 0 && !cfg.ShowUnresolved {
+		logger.Warn("the results of some calls are unknown, the call graph may be incomplete below them (see -show-unresolved)", "count", len(unresolved))
+	}
+	var partial []symgo.FunctionCoverage
+	for _, c := range interp.Coverage() {
+		if c.Partial() {
+			partial = append(partial, c)
+		}
+	}
+	if len(partial) > 0 && !cfg.ShowPartial {
+		logger.Warn("some functions were only partially explored, their calls may be incomplete (see -show-partial)", "count", len(partial))
+	}
+
 	// 5. Filter for true top-level functions (not called by any other entry point).
 	// A function is a "callee" if it's called by another function. A self-recursive
 	// call does not disqualify a function from being a top-level entry point.
@@ -358,23 +472,27 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, w
 		}
 	}
 
-	var topLevelFunctions []*scanner.FunctionInfo
+	var roots []*scanner.FunctionInfo
 	for _, f := range entryPoints {
 		if !callees[getFuncID(f)] {
-			topLevelFunctions = append(topLevelFunctions, f)
+			roots = append(roots, f)
 		}
 	}
 
-	// If filtering results in an empty list, it's likely a library composed
-	// entirely of a call cycle (e.g., mutual recursion). In this case,
-	// fall back to showing all original entry points.
-	if len(topLevelFunctions) == 0 && len(entryPoints) > 0 {
-		topLevelFunctions = entryPoints
+	// The entry points not reachable from the others are in a call cycle (e.g., mutual
+	// recursion) without an entry of its own, e.g. a library composed entirely of the cycle.
+	// All of them are shown as top-level functions too.
+	reachable := reachableFuncs(graph, roots)
+	var topLevelFunctions []*scanner.FunctionInfo
+	for _, f := range entryPoints {
+		if id := getFuncID(f); !callees[id] || !reachable[id] {
+			topLevelFunctions = append(topLevelFunctions, f)
+		}
 	}
 
 	// 6. Print the call graph starting from the true top-level functions.
 	var modulePrefix string
-	if trimPrefix {
+	if cfg.TrimPrefix {
 		l, err := locator.New(".")
 		if err != nil {
 			logger.Warn("could not find module root, --trim-prefix will be ignored", "error", err)
@@ -384,20 +502,144 @@ func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns, w
 	}
 
 	p := &Printer{
-		Graph:      graph,
-		Short:      shortFormat,
-		Expand:     expandFormat,
-		Out:        out,
-		TrimPrefix: modulePrefix,
+		Graph:             graph,
+		IDs:               goscan.NewSymbolIDs(pkgs...),
+		Short:             cfg.Short,
+		Expand:            cfg.Expand,
+		Out:               out,
+		TrimPrefix:        modulePrefix,
+		AggregateExternal: cfg.AggregateExternal,
 		// visited and assigned are initialized in Print()
 	}
+	if cfg.ShowExternal {
+		p.External = externals
+	}
+	if in != nil {
+		b := &Browser{
+			Graph:   graph,
+			Printer: p,
+			Fset:    s.Fset(),
+			In:      in,
+			Out:     out,
+			Clear:   isTerminal(out),
+		}
+		return b.Run(topLevelFunctions)
+	}
 	p.Print(topLevelFunctions)
+	if cfg.ShowUnresolved {
+		printUnresolvedCalls(out, unresolved)
+	}
+	if cfg.ShowPartial {
+		printPartialFunctions(out, partial, p.trim)
+	}
 
 	return nil
 }
 
+// printUnresolvedCalls prints the calls whose results are unknown to the analysis, with the
+// positions relative to the current directory.
+func printUnresolvedCalls(out io.Writer, calls []symgo.UnresolvedCall) {
+	if len(calls) == 0 {
+		return
+	}
+	cwd, _ := os.Getwd()
+	fmt.Fprintf(out, "\n-- unresolved calls (%d) --\n", len(calls))
+	for _, c := range calls {
+		pos := c.Pos
+		if rel, err := filepath.Rel(cwd, pos.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			pos.Filename = rel
+		}
+		fmt.Fprintf(out, "%s: %s [%s]\n", pos, c.Callee, c.Reason)
+	}
+}
+
+// printPartialFunctions prints the functions whose bodies were only partially explored, with the
+// numbers of the explored statements and branches, the error which aborted their evaluation, and
+// the positions of the unexplored statements, relative to the current directory. The names are
+// printed with trim.
+func printPartialFunctions(out io.Writer, functions []symgo.FunctionCoverage, trim func(string) string) {
+	if len(functions) == 0 {
+		return
+	}
+	cwd, _ := os.Getwd()
+	rel := func(pos token.Position) token.Position {
+		if rel, err := filepath.Rel(cwd, pos.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			pos.Filename = rel
+		}
+		return pos
+	}
+	fmt.Fprintf(out, "\n-- partially explored functions (%d) --\n", len(functions))
+	for _, c := range functions {
+		fmt.Fprintf(out, "%s: %s statements %d/%d, branches %d/%d", rel(c.Pos), trim(c.Function), c.CoveredStatements, c.Statements, c.CoveredBranches, c.Branches)
+		if c.Aborted != "" {
+			fmt.Fprintf(out, " [aborted: %s]", c.Aborted)
+		}
+		fmt.Fprintln(out)
+		for _, pos := range c.UncoveredStatements {
+			fmt.Fprintf(out, "  %s: not explored\n", rel(pos))
+		}
+		for _, pos := range c.UncoveredBranches {
+			fmt.Fprintf(out, "  %s: branch not explored\n", rel(pos))
+		}
+	}
+}
+
+// externalName returns the name of a called function which is out of the analysis scope:
+// an unresolved function, or a method declared in a package out of the scope.
+func externalName(callee object.Object, scanPolicy func(string) bool) (string, bool) {
+	switch f := callee.(type) {
+	case *object.UnresolvedFunction:
+		return f.PkgPath + "." + f.FuncName, true
+	case *object.SymbolicPlaceholder:
+		if f.UnderlyingFunc == nil || f.UnderlyingFunc.Receiver == nil || f.Package == nil || scanPolicy(f.Package.ImportPath) {
+			return "", false
+		}
+		recv := f.UnderlyingFunc.Receiver.Type
+		if recv.IsPointer && recv.Elem != nil {
+			recv = recv.Elem
+		}
+		return f.Package.ImportPath + "." + recv.Name + "." + f.UnderlyingFunc.Name, true
+	}
+	return "", false
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 // getFuncID generates a unique and stable identifier for a function.
 // It uses the package's unique ID and the function's syntax position.
+// reachableFuncs returns the IDs of the functions reachable from the roots in the call graph.
+func reachableFuncs(graph map[*scanner.FunctionInfo][]*scanner.FunctionInfo, roots []*scanner.FunctionInfo) map[string]bool {
+	calls := make(map[string][]*scanner.FunctionInfo, len(graph))
+	for caller, callees := range graph {
+		id := getFuncID(caller)
+		calls[id] = append(calls[id], callees...)
+	}
+	reachable := make(map[string]bool)
+	var visit func(f *scanner.FunctionInfo)
+	visit = func(f *scanner.FunctionInfo) {
+		id := getFuncID(f)
+		if reachable[id] {
+			return
+		}
+		reachable[id] = true
+		for _, callee := range calls[id] {
+			visit(callee)
+		}
+	}
+	for _, f := range roots {
+		visit(f)
+	}
+	return reachable
+}
+
 func getFuncID(f *scanner.FunctionInfo) string {
 	if f == nil {
 		return ""
@@ -416,14 +658,20 @@ func getFuncID(f *scanner.FunctionInfo) string {
 // Printer handles the output of the call graph.
 type Printer struct {
 	Graph      callGraph
+	IDs        *goscan.SymbolIDs // Identifies the functions for the "#N" references; nil uses goscan.SymbolID.
 	Short      bool
 	Expand     bool
 	Out        io.Writer
 	TrimPrefix string
 
+	// External is printed as the leaves of the callers, if not nil. With AggregateExternal,
+	// the repeated calls of a caller to the same function are printed once, with their count.
+	External          externalCalls
+	AggregateExternal bool
+
 	// State for printing
-	visited  map[string]bool // Key: func ID. For preventing infinite recursion in printing.
-	assigned map[string]int  // Key: func ID. For assigning numeric UIDs in expand mode.
+	visited  map[string]bool // Key: symbol ID. For preventing infinite recursion in printing.
+	assigned map[string]int  // Key: symbol ID. For assigning the numeric "#N" references.
 	nextID   int
 }
 
@@ -444,7 +692,7 @@ func (p *Printer) Print(entryPoints []*scanner.FunctionInfo) {
 }
 
 func (p *Printer) printRecursive(f *scanner.FunctionInfo, indent int) {
-	id := getFuncID(f)
+	id := p.symbolID(f)
 
 	accessorPrefix := ""
 	if isAccessor(f) {
@@ -512,6 +760,46 @@ func (p *Printer) printRecursive(f *scanner.FunctionInfo, indent int) {
 			p.printRecursive(callee, indent+1)
 		}
 	}
+	p.printExternal(f, indent+1)
+}
+
+// printExternal prints the calls of the function out of the analysis scope, in the order of the calls.
+func (p *Printer) printExternal(f *scanner.FunctionInfo, indent int) {
+	calls := append([]externalCall(nil), p.External[f]...)
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].Pos < calls[j].Pos
+	})
+
+	if !p.AggregateExternal {
+		for _, call := range calls {
+			fmt.Fprintf(p.Out, "%s%s (external)\n", strings.Repeat("  ", indent), p.trim(call.Name))
+		}
+		return
+	}
+
+	var names []string
+	counts := make(map[string]int)
+	for _, call := range calls {
+		if counts[call.Name] == 0 {
+			names = append(names, call.Name)
+		}
+		counts[call.Name]++
+	}
+	for _, name := range names {
+		count := ""
+		if counts[name] > 1 {
+			count = fmt.Sprintf(" x%d", counts[name])
+		}
+		fmt.Fprintf(p.Out, "%s%s (external)%s\n", strings.Repeat("  ", indent), p.trim(name), count)
+	}
+}
+
+// symbolID returns the stable identity of the function, which does not depend on its position.
+func (p *Printer) symbolID(f *scanner.FunctionInfo) string {
+	if p.IDs != nil {
+		return p.IDs.Func(f)
+	}
+	return goscan.SymbolID(f)
 }
 
 // isAccessor checks if a function is a simple getter or setter.
@@ -560,38 +848,18 @@ func (p *Printer) formatFunc(f *scanner.FunctionInfo) string {
 		return "<nil>"
 	}
 
-	// trim is a helper function that removes the module prefix from a given string.
-	// It correctly handles package paths and fully qualified type names for
-	// both root packages and sub-packages.
-	trim := func(s string) string {
-		if p.TrimPrefix == "" {
-			return s
-		}
-		// First, replace the prefix for sub-packages (e.g., "my/module/pkg" -> "pkg").
-		// This also handles nested types like "(*my/module/pkg.Type)".
-		res := strings.ReplaceAll(s, p.TrimPrefix+"/", "")
-		// Second, replace the prefix for root package types (e.g., "my/module.Type" -> "Type").
-		res = strings.ReplaceAll(res, p.TrimPrefix+".", "")
-		// Finally, if the string was the module path itself, it will not have been
-		// modified by the replacements. In this case, return an empty string.
-		if res == p.TrimPrefix {
-			return ""
-		}
-		return res
-	}
-
 	var b strings.Builder
 	b.WriteString("func ")
 
 	if f.Receiver != nil {
 		// Method: func (receiver) MethodName(...)
 		b.WriteString("(")
-		b.WriteString(trim(f.Receiver.Type.String()))
+		b.WriteString(p.trim(f.Receiver.Type.String()))
 		b.WriteString(")")
 		b.WriteString(".")
 	} else {
 		// Function: func pkg.FuncName(...)
-		trimmedPkgPath := trim(f.PkgPath)
+		trimmedPkgPath := p.trim(f.PkgPath)
 		if trimmedPkgPath != "" {
 			b.WriteString(trimmedPkgPath)
 			b.WriteString(".")
@@ -610,7 +878,7 @@ func (p *Printer) formatFunc(f *scanner.FunctionInfo) string {
 		b.WriteString("(")
 		params := make([]string, len(f.Parameters))
 		for i, param := range f.Parameters {
-			params[i] = trim(param.Type.String())
+			params[i] = p.trim(param.Type.String())
 		}
 		b.WriteString(strings.Join(params, ", "))
 		b.WriteString(")")
@@ -618,3 +886,23 @@ func (p *Printer) formatFunc(f *scanner.FunctionInfo) string {
 	// TODO: Add results
 	return b.String()
 }
+
+// trim removes the module prefix from a given string.
+// It correctly handles package paths and fully qualified type names for
+// both root packages and sub-packages.
+func (p *Printer) trim(s string) string {
+	if p.TrimPrefix == "" {
+		return s
+	}
+	// First, replace the prefix for sub-packages (e.g., "my/module/pkg" -> "pkg").
+	// This also handles nested types like "(*my/module/pkg.Type)".
+	res := strings.ReplaceAll(s, p.TrimPrefix+"/", "")
+	// Second, replace the prefix for root package types (e.g., "my/module.Type" -> "Type").
+	res = strings.ReplaceAll(res, p.TrimPrefix+".", "")
+	// Finally, if the string was the module path itself, it will not have been
+	// modified by the replacements. In this case, return an empty string.
+	if res == p.TrimPrefix {
+		return ""
+	}
+	return res
+}
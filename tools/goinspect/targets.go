@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// targetSelection selects the entry points of the analysis, instead of all the functions of
+// the --pkg packages.
+type targetSelection struct {
+	Names       []string // The canonical names of the functions, from -target.
+	Regexps     []string // The patterns matching the names of the functions, from -target-regex.
+	Annotations []string // The annotations of the doc comments of the functions, from -target-annotation.
+}
+
+// isEmpty reports whether no targets are given.
+func (sel targetSelection) isEmpty() bool {
+	return len(sel.Names) == 0 && len(sel.Regexps) == 0 && len(sel.Annotations) == 0
+}
+
+// isPattern reports whether some targets are given with patterns or annotations, which select
+// an unknown number of functions.
+func (sel targetSelection) isPattern() bool {
+	return len(sel.Regexps) > 0 || len(sel.Annotations) > 0
+}
+
+// match returns the functions selected by the names, and the functions of the entry point
+// packages selected by the patterns or the annotations, in the order of the functions.
+func (sel targetSelection) match(functions []*scanner.FunctionInfo, entrypointPkgPaths map[string]bool) ([]*scanner.FunctionInfo, error) {
+	names := make(map[string]bool, len(sel.Names))
+	for _, name := range sel.Names {
+		names[name] = true
+	}
+	regexps := make([]*regexp.Regexp, 0, len(sel.Regexps))
+	for _, pattern := range sel.Regexps {
+		re, err := compileTargetRegexp(pattern)
+		if err != nil {
+			return nil, err
+		}
+		regexps = append(regexps, re)
+	}
+
+	var selected []*scanner.FunctionInfo
+	for _, f := range functions {
+		if names[getFuncTargetName(f)] || (entrypointPkgPaths[f.PkgPath] && sel.matchPattern(f, regexps)) {
+			selected = append(selected, f)
+		}
+	}
+	return selected, nil
+}
+
+// matchPattern reports whether the function matches one of the regexps, or has one of the annotations.
+func (sel targetSelection) matchPattern(f *scanner.FunctionInfo, regexps []*regexp.Regexp) bool {
+	for _, re := range regexps {
+		if re.MatchString(getFuncTargetName(f)) || re.MatchString(getFuncLocalName(f)) {
+			return true
+		}
+	}
+	for _, annotation := range sel.Annotations {
+		if _, ok := f.Annotation(strings.TrimPrefix(annotation, "@")); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// compileTargetRegexp compiles a -target-regex pattern. As "(*" is not valid in a regexp, a
+// pointer receiver written as in the names of the methods, e.g. "(*Service).*", is matched literally.
+func compileTargetRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern):
+			b.WriteString(pattern[i : i+2])
+			i++
+			continue
+		case strings.HasPrefix(pattern[i:], "(*"):
+			if end := strings.IndexByte(pattern[i:], ')'); end != -1 {
+				b.WriteString(regexp.QuoteMeta(pattern[i : i+end+1]))
+				i += end
+				continue
+			}
+		}
+		b.WriteByte(pattern[i])
+	}
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid -target-regex %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// getFuncLocalName returns the name of a function or method in its package, e.g. "FuncName"
+// or "(*TypeName).MethodName", for matching against the -target-regex patterns.
+func getFuncLocalName(f *scanner.FunctionInfo) string {
+	n := f.CanonicalName()
+	if !n.IsMethod() {
+		return n.Name
+	}
+	if n.IsPointer {
+		return fmt.Sprintf("(*%s).%s", n.TypeName, n.Name)
+	}
+	return fmt.Sprintf("(%s).%s", n.TypeName, n.Name)
+}
+
+// printSelectedTargets prints the entry points selected by the patterns, before the analysis.
+func printSelectedTargets(out io.Writer, entryPoints []*scanner.FunctionInfo) {
+	fmt.Fprintf(out, "-- selected entry points (%d) --\n", len(entryPoints))
+	for _, f := range entryPoints {
+		fmt.Fprintln(out, getFuncTargetName(f))
+	}
+	fmt.Fprintln(out)
+}
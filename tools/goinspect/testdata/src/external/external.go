@@ -0,0 +1,23 @@
+package external
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/podhmo/go-scan/tools/goinspect/testdata/src/another"
+)
+
+// Report prints the lines, calling the same external functions repeatedly.
+func Report(lines []string) {
+	fmt.Println("-- report --")
+	for _, line := range lines {
+		fmt.Println(strings.TrimSpace(line))
+	}
+	fmt.Println("-- end --")
+	another.Helper()
+	footer()
+}
+
+func footer() {
+	fmt.Printf("%d\n", 0)
+}
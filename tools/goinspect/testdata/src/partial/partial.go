@@ -0,0 +1,20 @@
+package partial
+
+// Load reads the configuration, but the analysis cannot evaluate the undefined variable, so the
+// rest of its body is not explored.
+func Load() {
+	path := undefinedPath
+	read(path)
+}
+
+// Validate has a statement which cannot be reached.
+func Validate() error {
+	return check()
+	cleanup()
+}
+
+func read(path string) {}
+
+func check() error { return nil }
+
+func cleanup() {}
@@ -0,0 +1,30 @@
+package selection
+
+// Service is a service whose methods are selected by -target-regex.
+type Service struct{}
+
+func (s *Service) Start() {
+	s.setup()
+}
+
+func (s *Service) Stop() {
+	cleanup()
+}
+
+func (s *Service) setup() {}
+
+// Run is selected by -target-annotation.
+//
+// @entrypoint
+func Run() {
+	helper()
+}
+
+// Serve is not an entry point.
+func Serve() {
+	helper()
+}
+
+func helper() {}
+
+func cleanup() {}
@@ -0,0 +1,13 @@
+package unresolved
+
+import "example.com/missing/store"
+
+// Save stores the value with a client of a package which cannot be found, so the calls on the
+// client are unknown.
+func Save(v string) {
+	client := store.Connect()
+	client.Put(v)
+	flush()
+}
+
+func flush() {}
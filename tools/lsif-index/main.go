@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scanner"
+	"github.com/podhmo/go-scan/symgo"
+	"github.com/podhmo/go-scan/symgo/object"
+)
+
+// stringSlice is a custom type to handle multiple string flags.
+type stringSlice []string
+
+func (s *stringSlice) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringSlice) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func main() {
+	var pkgPatterns stringSlice
+	flag.Var(&pkgPatterns, "pkg", "Go package pattern to index (e.g., ./...). Can be specified multiple times.")
+	output := flag.String("o", "dump.lsif", "Output file of the LSIF dump, or - for stdout")
+	projectRoot := flag.String("project-root", ".", "Root directory of the project; the documents are relative to it")
+	noCalls := flag.Bool("no-calls", false, "Skip the symbolic execution resolving the method calls; only the references found in the syntax are indexed")
+	var logLevel = slog.LevelWarn
+	flag.TextVar(&logLevel, "log-level", &logLevel, "Log level (debug, info, warn, error)")
+	flag.Parse()
+
+	// The log level of .goscan.yaml is the default of the flag.
+	cfg, err := goscan.FindConfigFile(*projectRoot)
+	if err != nil {
+		log.Fatalf("Error: %+v", err)
+	}
+	if level, ok := cfg.Level(); ok && !isFlagSet("log-level") {
+		logLevel = level
+	}
+
+	if len(pkgPatterns) == 0 {
+		pkgPatterns = append(pkgPatterns, "./...")
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: &logLevel}))
+
+	out := io.Writer(os.Stdout)
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Error: %+v", err)
+		}
+		defer f.Close()
+		w := bufio.NewWriter(f)
+		defer w.Flush()
+		out = w
+	}
+	if err := run(context.Background(), out, logger, pkgPatterns, *projectRoot, !*noCalls); err != nil {
+		log.Fatalf("Error: %+v", err)
+	}
+}
+
+// run writes the LSIF dump of the packages matching the patterns. With withCalls, every
+// function of the packages is evaluated by symgo, and the calls it resolves (e.g. method calls,
+// which need the types of the receivers) are indexed as references to their callees.
+func run(ctx context.Context, out io.Writer, logger *slog.Logger, pkgPatterns []string, projectRoot string, withCalls bool) error {
+	s, err := goscan.New(goscan.WithLogger(logger), goscan.WithGoModuleResolver(), goscan.WithWorkDir(projectRoot))
+	if err != nil {
+		return fmt.Errorf("failed to create scanner: %w", err)
+	}
+
+	pkgsByID := make(map[string]*scanner.PackageInfo)
+	for _, pattern := range pkgPatterns {
+		scanned, err := s.Scan(ctx, pattern)
+		if err != nil {
+			return fmt.Errorf("failed to scan package pattern %q: %w", pattern, err)
+		}
+		for _, pkg := range scanned {
+			pkgsByID[pkg.ID] = pkg
+		}
+	}
+	pkgs := make([]*scanner.PackageInfo, 0, len(pkgsByID))
+	for _, pkg := range pkgsByID {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].ID < pkgs[j].ID })
+
+	index := goscan.NewLSIFIndex(s.Fset(), pkgs...)
+	if withCalls {
+		if err := addCalls(ctx, logger, s, pkgs, index); err != nil {
+			return err
+		}
+	}
+	return index.WriteLSIF(out, projectRoot)
+}
+
+// addCalls evaluates the functions of the packages, adding the calls resolved by symgo to the index.
+func addCalls(ctx context.Context, logger *slog.Logger, s *goscan.Scanner, pkgs []*scanner.PackageInfo, index *goscan.LSIFIndex) error {
+	scope := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		scope[pkg.ImportPath] = true
+	}
+	var resolved, added int
+	tracer := &object.TraceHooks{
+		OnCallResolved: func(event object.TraceEvent) {
+			fn, ok := event.Function.(*object.Function)
+			if !ok || fn.Def == nil {
+				return
+			}
+			resolved++
+			if index.AddCall(event.Pos, fn.Def) {
+				added++
+			}
+		},
+	}
+	interp, err := symgo.NewInterpreter(s,
+		symgo.WithLogger(logger.WithGroup("symgo")),
+		symgo.WithScanPolicy(func(importPath string) bool { return scope[importPath] }),
+		symgo.WithMemoization(true),
+		symgo.WithTracer(tracer),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create interpreter: %w", err)
+	}
+
+	for _, pkg := range pkgs {
+		if err := interp.LoadPackage(ctx, pkg); err != nil {
+			logger.WarnContext(ctx, "could not load package", "package", pkg.ImportPath, "error", err)
+			continue
+		}
+		for _, fn := range pkg.Functions {
+			name := fn.Name
+			if fn.Receiver != nil {
+				canonical := fn.CanonicalName()
+				if canonical.PkgPath == "" {
+					canonical.PkgPath = pkg.ImportPath
+				}
+				name = canonical.String()
+			}
+			obj, ok := interp.FindObjectInPackage(ctx, pkg.ImportPath, name)
+			if !ok {
+				logger.DebugContext(ctx, "could not find function object", "function", name, "package", pkg.ImportPath)
+				continue
+			}
+			if _, err := interp.Apply(ctx, obj, nil, pkg); err != nil {
+				logger.DebugContext(ctx, "evaluation failed", "function", name, "package", pkg.ImportPath, "error", err)
+			}
+		}
+	}
+	logger.InfoContext(ctx, "indexed the resolved calls", "resolved", resolved, "added", added)
+	return nil
+}
+
+// isFlagSet reports whether the flag is given on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
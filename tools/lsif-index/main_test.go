@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestRun(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"server/server.go": `package server
+
+type Server struct{ name string }
+
+func New(name string) *Server { return &Server{name: name} }
+
+func (s *Server) Start() string { return "start " + s.name }
+`,
+		"main.go": `package main
+
+import "example.com/app/server"
+
+func main() {
+	s := server.New("app")
+	println(s.Start())
+}
+`,
+	}
+	dir, cleanup := scantest.WriteFiles(t, files)
+	defer cleanup()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tests := []struct {
+		name      string
+		withCalls bool
+		want      []string // the references of the dump
+	}{
+		{
+			name: "syntax only",
+			want: []string{"server/server.go:5:24", "server/server.go:5:41", "server/server.go:7:10", "main.go:6:14"},
+		},
+		{
+			name:      "with calls",
+			withCalls: true,
+			want:      []string{"server/server.go:5:24", "server/server.go:5:41", "server/server.go:7:10", "main.go:6:14", "main.go:7:12"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := run(context.Background(), &buf, logger, []string{"./..."}, dir, tt.withCalls); err != nil {
+				t.Fatalf("run() failed: %v", err)
+			}
+			got := references(t, buf.Bytes(), dir)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("want the references %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// references returns the ranges of the references of an LSIF dump, in order, as
+// "file:line:column" with 1-based lines and columns.
+func references(t *testing.T, dump []byte, root string) []string {
+	t.Helper()
+	type element struct {
+		ID       int    `json:"id"`
+		Label    string `json:"label"`
+		URI      string `json:"uri"`
+		Property string `json:"property"`
+		InVs     []int  `json:"inVs"`
+		Document int    `json:"document"`
+		Start    struct {
+			Line      int `json:"line"`
+			Character int `json:"character"`
+		} `json:"start"`
+	}
+	elements := make(map[int]element)
+	var refs []string
+	sc := bufio.NewScanner(bytes.NewReader(dump))
+	for sc.Scan() {
+		var e element
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("invalid LSIF line %q: %v", sc.Text(), err)
+		}
+		elements[e.ID] = e
+		if e.Label != "item" || e.Property != "references" {
+			continue
+		}
+		rel, err := filepath.Rel(root, strings.TrimPrefix(elements[e.Document].URI, "file://"))
+		if err != nil {
+			t.Fatalf("document out of the project root: %v", err)
+		}
+		for _, id := range e.InVs {
+			r := elements[id]
+			refs = append(refs, fmt.Sprintf("%s:%d:%d", filepath.ToSlash(rel), r.Start.Line+1, r.Start.Character+1))
+		}
+	}
+	return refs
+}
@@ -3,6 +3,7 @@ package goscan
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/podhmo/go-scan/scanner"
@@ -11,6 +12,8 @@ import (
 // Implements checks if a struct type implements an interface type.
 // It uses a robust, scanner-based analysis to resolve methods and types,
 // correctly handling pointer/value receivers and embedded types.
+// A constraint interface (one with a type list, like `interface{ ~int | ~string }`)
+// is not a method set, so nothing is reported to implement it.
 func (s *Scanner) Implements(ctx context.Context, structCandidate *scanner.TypeInfo, interfaceDef *scanner.TypeInfo) bool {
 	return s.isImplementer(ctx, structCandidate, interfaceDef)
 }
@@ -18,6 +21,9 @@ func (s *Scanner) Implements(ctx context.Context, structCandidate *scanner.TypeI
 // isImplementer checks if a given concrete type implements an interface.
 // This is ported from the more robust symgo/evaluator.
 func (s *Scanner) isImplementer(ctx context.Context, concreteType *scanner.TypeInfo, interfaceType *scanner.TypeInfo) bool {
+	// Aliases (`type A = B`) are transparent: check the types they denote.
+	concreteType = resolveAliasOrSelf(ctx, concreteType)
+	interfaceType = resolveAliasOrSelf(ctx, interfaceType)
 	if concreteType == nil || interfaceType == nil || interfaceType.Interface == nil {
 		return false
 	}
@@ -39,6 +45,10 @@ func (s *Scanner) isImplementer(ctx context.Context, concreteType *scanner.TypeI
 	if interfaceType.Kind != scanner.InterfaceKind {
 		return false
 	}
+	if interfaceType.Interface.IsConstraint() {
+		slog.DebugContext(ctx, "constraint interface is not a method set", "interface", interfaceType.Name, "type", concreteType.Name)
+		return false
+	}
 
 	// Get all methods from the interface, including from embedded interfaces.
 	allInterfaceMethods := s.getAllInterfaceMethods(ctx, interfaceType, make(map[string]struct{}))
@@ -123,7 +133,8 @@ func (s *Scanner) getAllInterfaceMethods(ctx context.Context, ifaceType *scanner
 }
 
 // fieldTypeEquals compares two FieldType objects for equality.
-// It uses the string representation for a robust comparison of the type structure.
+// It uses the string representation qualified by the import paths, so that a type is equal to
+// itself when written in its own package (`*User`) and in another one (`*domain.User`).
 func (s *Scanner) fieldTypeEquals(a, b *scanner.FieldType) bool {
 	if a == b {
 		return true
@@ -131,7 +142,34 @@ func (s *Scanner) fieldTypeEquals(a, b *scanner.FieldType) bool {
 	if a == nil || b == nil {
 		return false
 	}
-	return a.String() == b.String()
+	return a.String() == b.String() || qualifiedTypeString(a) == qualifiedTypeString(b)
+}
+
+// qualifiedTypeString returns the string representation of the type, with the named types
+// qualified by their import paths, e.g. "*example.com/domain.User".
+func qualifiedTypeString(ft *scanner.FieldType) string {
+	switch {
+	case ft == nil:
+		return ""
+	case ft.IsPointer && ft.Elem != nil && !ft.IsSlice && !ft.IsMap:
+		return "*" + qualifiedTypeString(ft.Elem)
+	case ft.IsSlice:
+		return "[]" + qualifiedTypeString(ft.Elem)
+	case ft.IsMap:
+		return "map[" + qualifiedTypeString(ft.MapKey) + "]" + qualifiedTypeString(ft.Elem)
+	case ft.FullImportPath == "" || ft.TypeName == "" || ft.IsTypeParam || ft.IsBuiltin:
+		return ft.String()
+	}
+	var sb strings.Builder
+	sb.WriteString(ft.FullImportPath + "." + ft.TypeName)
+	if len(ft.TypeArgs) > 0 {
+		args := make([]string, len(ft.TypeArgs))
+		for i, arg := range ft.TypeArgs {
+			args[i] = qualifiedTypeString(arg)
+		}
+		sb.WriteString("[" + strings.Join(args, ", ") + "]")
+	}
+	return sb.String()
 }
 
 // findMethodInfoOnType finds the scanner.FunctionInfo for a method on a type, handling embedding.
@@ -218,3 +256,16 @@ func (s *Scanner) findDirectMethodInfoOnType(ctx context.Context, typeInfo *scan
 	}
 	return nil, nil
 }
+
+// resolveAliasOrSelf returns the type denoted by an alias declaration, or t itself
+// if t is not an alias or the alias cannot be resolved.
+func resolveAliasOrSelf(ctx context.Context, t *scanner.TypeInfo) *scanner.TypeInfo {
+	if t == nil || !t.IsAlias {
+		return t
+	}
+	resolved, err := t.ResolveAlias(ctx)
+	if err != nil || resolved == nil {
+		return t
+	}
+	return resolved
+}
@@ -73,11 +73,18 @@ func TestImplements_New(t *testing.T) {
 		{"MyEmbeddedReader implements EmbeddedReader", "example.com/implements2/impls.MyEmbeddedReader", "example.com/implements2/ifaces.EmbeddedReader", true},
 		{"MyEmbeddedReader implements SimpleReader (via EmbeddedReader)", "example.com/implements2/impls.MyEmbeddedReader", "example.com/implements2/ifaces.SimpleReader", true},
 		{"StructWithEmbeddedConcrete implements AnotherInterface", "example.com/implements2/impls.StructWithEmbeddedConcrete", "example.com/implements2/ifaces.AnotherInterface", true},
+		{"MyReaderAlias implements SimpleReader", "example.com/implements2/impls.MyReaderAlias", "example.com/implements2/ifaces.SimpleReader", true},
+		{"MyReader implements ReaderAlias", "example.com/implements2/impls.MyReader", "example.com/implements2/ifaces.ReaderAlias", true},
+		{"MyReaderAlias implements ReaderAlias", "example.com/implements2/impls.MyReaderAlias", "example.com/implements2/ifaces.ReaderAlias", true},
+		{"MemoryStore implements Store (with the types of the interface's package)", "example.com/implements2/impls.MemoryStore", "example.com/implements2/ifaces.Store", true},
 
 		// Negative cases
 		{"NonImplementer does not implement SimpleReader", "example.com/implements2/impls.NonImplementer", "example.com/implements2/ifaces.SimpleReader", false},
 		{"PartialImplementer does not implement EmbeddedReader", "example.com/implements2/impls.PartialImplementer", "example.com/implements2/ifaces.EmbeddedReader", false},
 		{"MyReader does not implement EmbeddedReader", "example.com/implements2/impls.MyReader", "example.com/implements2/ifaces.EmbeddedReader", false},
+		{"MyReaderAlias does not implement EmbeddedReader", "example.com/implements2/impls.MyReaderAlias", "example.com/implements2/ifaces.EmbeddedReader", false},
+		{"MyReader does not implement Store", "example.com/implements2/impls.MyReader", "example.com/implements2/ifaces.Store", false},
+		{"MyReader does not implement ReaderConstraint (a constraint interface)", "example.com/implements2/impls.MyReader", "example.com/implements2/ifaces.ReaderConstraint", false},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,341 @@
+package goscan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+
+	"github.com/podhmo/go-scan/scanner"
+)
+
+// TypeGraph is the exported type graph of a package in a canonical form: its exported types, and
+// the unexported types they reference, with their exported fields, methods and enum values. It
+// has no file positions, names of parameters or comments, so it can be compared across repositories
+// and versions of a library, e.g. to check in CI that the wire format of a package did not change.
+// Encoded as JSON, it is stable: the same declarations give the same bytes.
+type TypeGraph struct {
+	PkgPath string     `json:"pkgPath"`
+	Types   []TypeNode `json:"types"` // sorted by name
+	// Hash is the hash of the whole graph, to tell at once whether anything changed.
+	Hash string `json:"hash"`
+}
+
+// TypeNode is a type of a TypeGraph. The types of its fields and signatures are qualified by
+// their import paths, e.g. "*example.com/pkg.User".
+type TypeNode struct {
+	Name string `json:"name"`
+	// Kind is "struct", "interface", "func", "alias" (`type A = B`) or "defined" (`type A B`).
+	Kind       string   `json:"kind"`
+	TypeParams []string `json:"typeParams,omitempty"` // e.g. "T any"
+	// Underlying is the type denoted by an alias or a defined type, and the signature of a func type.
+	Underlying string `json:"underlying,omitempty"`
+	// Fields are the exported and the embedded fields of a struct, and the embedded types of an
+	// interface, in the order of declaration.
+	Fields []FieldNode `json:"fields,omitempty"`
+	// Methods are the exported methods of the type, or of the interface, sorted by name.
+	Methods []MethodNode `json:"methods,omitempty"`
+	// Values are the members of an enum, as "Name = value", in the order of declaration.
+	Values []string `json:"values,omitempty"`
+	// Refs are the names of the types of the package used by the type, sorted.
+	Refs []string `json:"refs,omitempty"`
+	// Hash is the content-addressable hash of the type and of the types it reaches through Refs,
+	// so that it changes when the fields of a nested type change.
+	Hash string `json:"hash"`
+}
+
+// FieldNode is a field of a TypeNode.
+type FieldNode struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Tag      string `json:"tag,omitempty"`
+	Embedded bool   `json:"embedded,omitempty"`
+}
+
+// MethodNode is a method of a TypeNode, with its signature without the names of the parameters,
+// e.g. "func(context.Context, string) (*example.com/pkg.User, error)".
+type MethodNode struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+}
+
+// TypeGraphChange is a difference between two graphs of the same package, see CompareTypeGraphs.
+type TypeGraphChange struct {
+	Name string `json:"name"`
+	// Change is "added", "removed" or "changed".
+	Change string `json:"change"`
+}
+
+// ExportTypeGraph computes the exported type graph of the package. The types declared in test
+// files are left out.
+func ExportTypeGraph(pkg *scanner.PackageInfo) *TypeGraph {
+	declared := make(map[string]*scanner.TypeInfo)
+	for _, t := range pkg.Types {
+		if !t.IsTest {
+			declared[t.Name] = t
+		}
+	}
+	methods := make(map[string][]*scanner.FunctionInfo) // by receiver type name
+	for _, fn := range pkg.Functions {
+		if fn.Receiver == nil || fn.IsTest || !ast.IsExported(fn.Name) {
+			continue
+		}
+		name := fn.CanonicalName().TypeName
+		methods[name] = append(methods[name], fn)
+	}
+
+	nodes := make(map[string]*TypeNode)
+	var queue []string
+	for name := range declared {
+		if ast.IsExported(name) {
+			queue = append(queue, name)
+		}
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if _, done := nodes[name]; done {
+			continue
+		}
+		r := &typeGraphRenderer{pkgPath: pkg.ImportPath, refs: make(map[string]bool)}
+		node := r.node(declared[name], methods[name])
+		nodes[name] = node
+		for ref := range r.refs {
+			if _, ok := declared[ref]; ok && ref != name {
+				node.Refs = append(node.Refs, ref)
+				queue = append(queue, ref)
+			}
+		}
+		sort.Strings(node.Refs)
+	}
+
+	g := &TypeGraph{PkgPath: pkg.ImportPath}
+	texts := make(map[string]string, len(nodes))
+	for name, node := range nodes {
+		texts[name] = node.canonicalText()
+	}
+	for name, node := range nodes {
+		reached := map[string]bool{name: true}
+		stack := []string{name}
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, ref := range nodes[cur].Refs {
+				if !reached[ref] {
+					reached[ref] = true
+					stack = append(stack, ref)
+				}
+			}
+		}
+		node.Hash = hashTexts(texts, sortedKeys(reached))
+		g.Types = append(g.Types, *node)
+	}
+	sort.Slice(g.Types, func(i, j int) bool { return g.Types[i].Name < g.Types[j].Name })
+	g.Hash = hashTexts(texts, sortedKeys(texts))
+	return g
+}
+
+// CompareTypeGraphs returns the exported types added, removed or changed from old to new, sorted
+// by name. A type is changed if it, or a type it reaches, is changed.
+func CompareTypeGraphs(old, new *TypeGraph) []TypeGraphChange {
+	hashes := func(g *TypeGraph) map[string]string {
+		m := make(map[string]string)
+		for _, t := range g.Types {
+			if ast.IsExported(t.Name) {
+				m[t.Name] = t.Hash
+			}
+		}
+		return m
+	}
+	before, after := hashes(old), hashes(new)
+	var changes []TypeGraphChange
+	for name, hash := range before {
+		switch newHash, ok := after[name]; {
+		case !ok:
+			changes = append(changes, TypeGraphChange{Name: name, Change: "removed"})
+		case newHash != hash:
+			changes = append(changes, TypeGraphChange{Name: name, Change: "changed"})
+		}
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			changes = append(changes, TypeGraphChange{Name: name, Change: "added"})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// canonicalText is the text hashed for the node, one line per element.
+func (n *TypeNode) canonicalText() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "type %s[%s] %s %s\n", n.Name, strings.Join(n.TypeParams, ", "), n.Kind, n.Underlying)
+	for _, f := range n.Fields {
+		fmt.Fprintf(&sb, "field %s %s %q %t\n", f.Name, f.Type, f.Tag, f.Embedded)
+	}
+	for _, m := range n.Methods {
+		fmt.Fprintf(&sb, "method %s %s\n", m.Name, m.Signature)
+	}
+	for _, v := range n.Values {
+		fmt.Fprintf(&sb, "value %s\n", v)
+	}
+	return sb.String()
+}
+
+func hashTexts(texts map[string]string, names []string) string {
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(texts[name]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// typeGraphRenderer renders the types of a node, recording the types of the package it uses.
+type typeGraphRenderer struct {
+	pkgPath string
+	refs    map[string]bool
+}
+
+func (r *typeGraphRenderer) node(t *scanner.TypeInfo, methods []*scanner.FunctionInfo) *TypeNode {
+	node := &TypeNode{Name: t.Name}
+	for _, tp := range t.TypeParams {
+		node.TypeParams = append(node.TypeParams, strings.TrimSpace(tp.Name+" "+r.typeString(tp.Constraint)))
+	}
+	switch {
+	case t.IsAlias:
+		node.Kind = "alias"
+		node.Underlying = r.typeString(t.AliasTarget)
+	case t.Kind == scanner.StructKind:
+		node.Kind = "struct"
+		if t.Struct != nil {
+			node.Fields = r.fields(t.Struct.Fields)
+		}
+	case t.Kind == scanner.InterfaceKind:
+		node.Kind = "interface"
+		if t.Interface != nil {
+			for _, embedded := range t.Interface.Embedded {
+				node.Fields = append(node.Fields, FieldNode{Name: embedded.Name, Type: r.typeString(embedded), Embedded: true})
+			}
+			for _, m := range t.Interface.Methods {
+				node.Methods = append(node.Methods, MethodNode{Name: m.Name, Signature: r.signature(m.Parameters, m.Results, false)})
+			}
+		}
+	case t.Kind == scanner.FuncKind:
+		node.Kind = "func"
+		if t.Func != nil {
+			node.Underlying = r.signature(t.Func.Parameters, t.Func.Results, t.Func.IsVariadic)
+		}
+	default:
+		node.Kind = "defined"
+		node.Underlying = r.typeString(t.Underlying)
+	}
+	for _, fn := range methods {
+		node.Methods = append(node.Methods, MethodNode{Name: fn.Name, Signature: r.signature(fn.Parameters, fn.Results, fn.IsVariadic)})
+	}
+	sort.Slice(node.Methods, func(i, j int) bool { return node.Methods[i].Name < node.Methods[j].Name })
+	if t.IsEnum {
+		for _, c := range t.EnumMembers {
+			value := c.Value
+			if c.ConstVal != nil {
+				value = c.ConstVal.ExactString()
+			}
+			node.Values = append(node.Values, c.Name+" = "+value)
+		}
+	}
+	return node
+}
+
+func (r *typeGraphRenderer) fields(fields []*scanner.FieldInfo) []FieldNode {
+	var nodes []FieldNode
+	for _, f := range fields {
+		if !f.Embedded && !ast.IsExported(f.Name) {
+			continue
+		}
+		nodes = append(nodes, FieldNode{Name: f.Name, Type: r.typeString(f.Type), Tag: f.Tag, Embedded: f.Embedded})
+	}
+	return nodes
+}
+
+func (r *typeGraphRenderer) signature(params, results []*scanner.FieldInfo, variadic bool) string {
+	ps := make([]string, len(params))
+	for i, p := range params {
+		ps[i] = r.typeString(p.Type)
+		if variadic && i == len(params)-1 {
+			ps[i] = "..." + strings.TrimPrefix(ps[i], "[]")
+		}
+	}
+	sig := "func(" + strings.Join(ps, ", ") + ")"
+	rs := make([]string, len(results))
+	for i, res := range results {
+		rs[i] = r.typeString(res.Type)
+	}
+	switch len(rs) {
+	case 0:
+		return sig
+	case 1:
+		return sig + " " + rs[0]
+	}
+	return sig + " (" + strings.Join(rs, ", ") + ")"
+}
+
+// typeString is like qualifiedTypeString, also rendering the channels and the anonymous structs
+// and interfaces, and recording the named types of the package.
+func (r *typeGraphRenderer) typeString(ft *scanner.FieldType) string {
+	switch {
+	case ft == nil:
+		return ""
+	case ft.IsPointer && ft.Elem != nil && !ft.IsSlice && !ft.IsMap:
+		return "*" + r.typeString(ft.Elem)
+	case ft.IsSlice:
+		return "[]" + r.typeString(ft.Elem)
+	case ft.IsMap:
+		return "map[" + r.typeString(ft.MapKey) + "]" + r.typeString(ft.Elem)
+	case ft.IsChan:
+		return "chan " + r.typeString(ft.Elem)
+	case ft.Definition != nil && ft.Definition.Name == "" && ft.Definition.Struct != nil:
+		var fields []string
+		for _, f := range r.fields(ft.Definition.Struct.Fields) {
+			field := f.Name + " " + f.Type
+			if f.Tag != "" {
+				field += fmt.Sprintf(" %q", f.Tag)
+			}
+			fields = append(fields, field)
+		}
+		return "struct{" + strings.Join(fields, "; ") + "}"
+	case ft.Definition != nil && ft.Definition.Name == "" && ft.Definition.Interface != nil:
+		var methods []string
+		for _, m := range ft.Definition.Interface.Methods {
+			methods = append(methods, m.Name+strings.TrimPrefix(r.signature(m.Parameters, m.Results, false), "func"))
+		}
+		sort.Strings(methods)
+		return "interface{" + strings.Join(methods, "; ") + "}"
+	case ft.IsTypeParam || ft.IsBuiltin:
+		return ft.String()
+	case ft.FullImportPath == "" || ft.TypeName == "":
+		return ft.String()
+	}
+	if ft.FullImportPath == r.pkgPath {
+		r.refs[ft.TypeName] = true
+	}
+	var sb strings.Builder
+	sb.WriteString(ft.FullImportPath + "." + ft.TypeName)
+	if len(ft.TypeArgs) > 0 {
+		args := make([]string, len(ft.TypeArgs))
+		for i, arg := range ft.TypeArgs {
+			args[i] = r.typeString(arg)
+		}
+		sb.WriteString("[" + strings.Join(args, ", ") + "]")
+	}
+	return sb.String()
+}
@@ -0,0 +1,178 @@
+package goscan_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	goscan "github.com/podhmo/go-scan"
+	"github.com/podhmo/go-scan/scantest"
+)
+
+func TestExportTypeGraph(t *testing.T) {
+	export := func(t *testing.T, src string) *goscan.TypeGraph {
+		t.Helper()
+		dir, cleanup := scantest.WriteFiles(t, map[string]string{
+			"go.mod":     "module example.com/app\n\ngo 1.22\n",
+			"lib/lib.go": src,
+		})
+		defer cleanup()
+
+		s, err := goscan.New(goscan.WithWorkDir(dir), goscan.WithGoModuleResolver())
+		if err != nil {
+			t.Fatalf("New() failed: %v", err)
+		}
+		pkg, err := s.ScanPackageFromImportPath(context.Background(), "example.com/app/lib")
+		if err != nil {
+			t.Fatalf("ScanPackageFromImportPath() failed: %v", err)
+		}
+		return goscan.ExportTypeGraph(pkg)
+	}
+
+	const base = `package lib
+
+import "context"
+
+type User struct {
+	ID      int ` + "`json:\"id\"`" + `
+	Profile profile
+	secret  string
+}
+
+func (u *User) Validate() error { return nil }
+
+func (u *User) normalize() {}
+
+type profile struct {
+	Name string
+}
+
+type Status string
+
+const (
+	Active   Status = "active"
+	Inactive Status = "inactive"
+)
+
+type Store interface {
+	Get(ctx context.Context, id string) (*User, error)
+}
+
+type unused struct{}
+`
+
+	g := export(t, base)
+
+	t.Run("nodes", func(t *testing.T) {
+		got := make(map[string]goscan.TypeNode)
+		for _, node := range g.Types {
+			node.Hash = ""
+			got[node.Name] = node
+		}
+		want := map[string]goscan.TypeNode{
+			"User": {
+				Name: "User", Kind: "struct",
+				Fields: []goscan.FieldNode{
+					{Name: "ID", Type: "int", Tag: `json:"id"`},
+					{Name: "Profile", Type: "example.com/app/lib.profile"},
+				},
+				Methods: []goscan.MethodNode{{Name: "Validate", Signature: "func() error"}},
+				Refs:    []string{"profile"},
+			},
+			"profile": {Name: "profile", Kind: "struct", Fields: []goscan.FieldNode{{Name: "Name", Type: "string"}}},
+			"Status":  {Name: "Status", Kind: "defined", Underlying: "string", Values: []string{`Active = "active"`, `Inactive = "inactive"`}},
+			"Store": {
+				Name: "Store", Kind: "interface",
+				Methods: []goscan.MethodNode{{Name: "Get", Signature: "func(context.Context, string) (*example.com/app/lib.User, error)"}},
+				Refs:    []string{"User"},
+			},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ExportTypeGraph() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("stable", func(t *testing.T) {
+		moved := `package lib
+
+import "context"
+
+type unused struct{}
+
+// Store stores the users.
+type Store interface {
+	Get(ctx context.Context, key string) (u *User, err error)
+}
+
+type Status string
+
+const (
+	Active   Status = "active"
+	Inactive Status = "inactive"
+)
+
+type profile struct {
+	Name string
+}
+
+func (u *User) normalize() {}
+
+func (u *User) Validate() error { return nil }
+
+type User struct {
+	ID      int ` + "`json:\"id\"`" + `
+	Profile profile
+	token   []byte
+}
+`
+		a, err := json.Marshal(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(export(t, moved))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(string(a), string(b)); diff != "" {
+			t.Errorf("the graph changed with the order of declarations, the names of parameters and the unexported fields (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		changed := export(t, `package lib
+
+import "context"
+
+type User struct {
+	ID      int `+"`json:\"id\"`"+`
+	Profile profile
+}
+
+func (u *User) Validate() error { return nil }
+
+type profile struct {
+	Name string `+"`json:\"name\"`"+`
+}
+
+type Store interface {
+	Get(ctx context.Context, id string) (*User, error)
+}
+
+type Role int
+`)
+		got := goscan.CompareTypeGraphs(g, changed)
+		want := []goscan.TypeGraphChange{
+			{Name: "Role", Change: "added"},
+			{Name: "Status", Change: "removed"},
+			{Name: "Store", Change: "changed"}, // through User and profile
+			{Name: "User", Change: "changed"},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("CompareTypeGraphs() mismatch (-want +got):\n%s", diff)
+		}
+		if g.Hash == changed.Hash {
+			t.Errorf("the hash of the graph did not change")
+		}
+	})
+}